@@ -0,0 +1,72 @@
+/* Copyright (c) 2023, VRAI Labs and/or its affiliates. All rights reserved.
+ *
+ * This software is licensed under the Apache License, Version 2.0 (the
+ * "License") as published by the Apache Software Foundation.
+ *
+ * You may not use this file except in compliance with the License. You may
+ * obtain a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+ * License for the specific language governing permissions and limitations
+ * under the License.
+ */
+
+// Package echoadapter wires SuperTokens into echo applications.
+package echoadapter
+
+import (
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"github.com/supertokens/supertokens-golang/recipe/session"
+	"github.com/supertokens/supertokens-golang/recipe/session/sessmodels"
+	"github.com/supertokens/supertokens-golang/supertokens"
+)
+
+// Middleware serves the SuperTokens APIs (sign in, refresh, sign out, ...)
+// and passes every other request on to the next handler. Errors returned by
+// the next handler are returned to echo, so they reach its HTTPErrorHandler.
+func Middleware() echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			var err error
+			supertokens.Middleware(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+				c.SetRequest(r)
+				err = next(c)
+			})).ServeHTTP(c.Response(), c.Request())
+			return err
+		}
+	}
+}
+
+// VerifySession checks the session of the request before calling the next
+// handler. If the session is invalid, the response is sent by SuperTokens
+// and the next handler is not called. Use GetSessionFromEchoContext to read
+// the session in that handler.
+func VerifySession(options *sessmodels.VerifySessionOptions) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			var err error
+			session.VerifySession(options, func(rw http.ResponseWriter, r *http.Request) {
+				c.SetRequest(c.Request().WithContext(r.Context()))
+				err = next(c)
+			})(c.Response(), c.Request())
+			return err
+		}
+	}
+}
+
+// VerifySessionWithRoles is like VerifySession, but also requires the user to
+// have all the given roles. Other users get a 403 response with the failed
+// role claim validation in its body.
+func VerifySessionWithRoles(options *sessmodels.VerifySessionOptions, roles ...string) echo.MiddlewareFunc {
+	return VerifySession(session.WithRequiredRoles(options, roles))
+}
+
+// GetSessionFromEchoContext returns the session added by VerifySession, or
+// nil if there is none (for example when SessionRequired is false).
+func GetSessionFromEchoContext(c echo.Context) sessmodels.SessionContainer {
+	return session.GetSessionFromRequestContext(c.Request().Context())
+}
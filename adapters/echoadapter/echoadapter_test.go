@@ -0,0 +1,73 @@
+/* Copyright (c) 2023, VRAI Labs and/or its affiliates. All rights reserved.
+ *
+ * This software is licensed under the Apache License, Version 2.0 (the
+ * "License") as published by the Apache Software Foundation.
+ *
+ * You may not use this file except in compliance with the License. You may
+ * obtain a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+ * License for the specific language governing permissions and limitations
+ * under the License.
+ */
+
+package echoadapter
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/supertokens/supertokens-golang/recipe/session"
+	"github.com/supertokens/supertokens-golang/recipe/session/sessmodels"
+	"github.com/supertokens/supertokens-golang/supertokens"
+)
+
+func TestEchoAdapter(t *testing.T) {
+	supertokens.ResetForTest()
+	session.ResetForTest()
+	defer supertokens.ResetForTest()
+	defer session.ResetForTest()
+	err := supertokens.Init(supertokens.TypeInput{
+		Supertokens: &supertokens.ConnectionInfo{
+			ConnectionURI: "http://localhost:8080",
+		},
+		AppInfo: supertokens.AppInfo{
+			AppName:       "SuperTokens",
+			WebsiteDomain: "supertokens.io",
+			APIDomain:     "api.supertokens.io",
+		},
+		RecipeList: []supertokens.Recipe{session.Init(nil)},
+	})
+	assert.NoError(t, err)
+
+	e := echo.New()
+	e.Use(Middleware())
+	sessionRequired := false
+	e.GET("/optional", func(c echo.Context) error {
+		return c.JSON(http.StatusOK, map[string]interface{}{"hasSession": GetSessionFromEchoContext(c) != nil})
+	}, VerifySession(&sessmodels.VerifySessionOptions{SessionRequired: &sessionRequired}))
+	e.GET("/required", func(c echo.Context) error {
+		return c.JSON(http.StatusOK, map[string]interface{}{"hasSession": GetSessionFromEchoContext(c) != nil})
+	}, VerifySession(nil))
+	e.GET("/teapot", func(c echo.Context) error {
+		return echo.NewHTTPError(http.StatusTeapot)
+	}, VerifySession(&sessmodels.VerifySessionOptions{SessionRequired: &sessionRequired}))
+
+	res := httptest.NewRecorder()
+	e.ServeHTTP(res, httptest.NewRequest(http.MethodGet, "/optional", nil))
+	assert.Equal(t, http.StatusOK, res.Code)
+	assert.JSONEq(t, `{"hasSession":false}`, res.Body.String())
+
+	res = httptest.NewRecorder()
+	e.ServeHTTP(res, httptest.NewRequest(http.MethodGet, "/required", nil))
+	assert.Equal(t, http.StatusUnauthorized, res.Code)
+
+	res = httptest.NewRecorder()
+	e.ServeHTTP(res, httptest.NewRequest(http.MethodGet, "/teapot", nil))
+	assert.Equal(t, http.StatusTeapot, res.Code)
+}
@@ -0,0 +1,66 @@
+/* Copyright (c) 2023, VRAI Labs and/or its affiliates. All rights reserved.
+ *
+ * This software is licensed under the Apache License, Version 2.0 (the
+ * "License") as published by the Apache Software Foundation.
+ *
+ * You may not use this file except in compliance with the License. You may
+ * obtain a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+ * License for the specific language governing permissions and limitations
+ * under the License.
+ */
+
+// Package ginadapter wires SuperTokens into gin applications.
+package ginadapter
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/supertokens/supertokens-golang/recipe/session"
+	"github.com/supertokens/supertokens-golang/recipe/session/sessmodels"
+	"github.com/supertokens/supertokens-golang/supertokens"
+)
+
+// Middleware serves the SuperTokens APIs (sign in, refresh, sign out, ...)
+// and passes every other request on to the next handler in the chain.
+func Middleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		supertokens.Middleware(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+			c.Request = r
+			c.Next()
+		})).ServeHTTP(c.Writer, c.Request)
+		// we call Abort so that the next handler in the chain is not called, unless we call Next explicitly
+		c.Abort()
+	}
+}
+
+// VerifySession checks the session of the request before calling the next
+// handler in the chain. Use GetSessionFromGinContext to read the session in
+// that handler.
+func VerifySession(options *sessmodels.VerifySessionOptions) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		session.VerifySession(options, func(rw http.ResponseWriter, r *http.Request) {
+			c.Request = c.Request.WithContext(r.Context())
+			c.Next()
+		})(c.Writer, c.Request)
+		// we call Abort so that the next handler in the chain is not called, unless we call Next explicitly
+		c.Abort()
+	}
+}
+
+// VerifySessionWithRoles is like VerifySession, but also requires the user to
+// have all the given roles. Other users get a 403 response with the failed
+// role claim validation in its body.
+func VerifySessionWithRoles(options *sessmodels.VerifySessionOptions, roles ...string) gin.HandlerFunc {
+	return VerifySession(session.WithRequiredRoles(options, roles))
+}
+
+// GetSessionFromGinContext returns the session added by VerifySession, or nil
+// if there is none (for example when SessionRequired is false).
+func GetSessionFromGinContext(c *gin.Context) sessmodels.SessionContainer {
+	return session.GetSessionFromRequestContext(c.Request.Context())
+}
@@ -0,0 +1,25 @@
+module github.com/supertokens/supertokens-golang/adapters/gqlgenadapter
+
+go 1.18
+
+require (
+	github.com/99designs/gqlgen v0.17.20
+	github.com/stretchr/testify v1.7.1
+	github.com/supertokens/supertokens-golang v0.0.0-20210909070424-b13c10ce5994
+	github.com/vektah/gqlparser/v2 v2.5.1
+)
+
+require (
+	github.com/MicahParks/keyfunc/v2 v2.1.0 // indirect
+	github.com/davecgh/go-spew v1.1.1 // indirect
+	github.com/golang-jwt/jwt/v5 v5.0.0 // indirect
+	github.com/h2non/parth v0.0.0-20190131123155-b4df798d6542 // indirect
+	github.com/pmezard/go-difflib v1.0.0 // indirect
+	golang.org/x/net v0.2.0 // indirect
+	gopkg.in/alexcesaro/quotedprintable.v3 v3.0.0-20150716171945-2caba252f4dc // indirect
+	gopkg.in/gomail.v2 v2.0.0-20160411212932-81ebce5c23df // indirect
+	gopkg.in/h2non/gock.v1 v1.1.2 // indirect
+	gopkg.in/yaml.v3 v3.0.1 // indirect
+)
+
+replace github.com/supertokens/supertokens-golang => ../../
@@ -0,0 +1,109 @@
+/* Copyright (c) 2023, VRAI Labs and/or its affiliates. All rights reserved.
+ *
+ * This software is licensed under the Apache License, Version 2.0 (the
+ * "License") as published by the Apache Software Foundation.
+ *
+ * You may not use this file except in compliance with the License. You may
+ * obtain a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+ * License for the specific language governing permissions and limitations
+ * under the License.
+ */
+
+// Package gqlgenadapter wires SuperTokens sessions into gqlgen servers.
+// Middleware verifies the session of every GraphQL request (without
+// requiring one), and the Auth directive requires it per field:
+//
+//	directive @auth on FIELD_DEFINITION
+//
+//	type Query {
+//		me: User! @auth
+//	}
+//
+//	c := generated.Config{Resolvers: &resolver{}}
+//	c.Directives.Auth = gqlgenadapter.Auth()
+//	srv := handler.NewDefaultServer(generated.NewExecutableSchema(c))
+//	http.Handle("/query", gqlgenadapter.Middleware(nil)(srv))
+package gqlgenadapter
+
+import (
+	"context"
+	defaultErrors "errors"
+	"net/http"
+
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/supertokens/supertokens-golang/recipe/session"
+	"github.com/supertokens/supertokens-golang/recipe/session/claims"
+	"github.com/supertokens/supertokens-golang/recipe/session/errors"
+	"github.com/supertokens/supertokens-golang/recipe/session/sessmodels"
+	"github.com/vektah/gqlparser/v2/gqlerror"
+)
+
+const (
+	// UnauthenticatedCode is the extensions code of errors returned for
+	// fields that require a session when there is none
+	UnauthenticatedCode = "UNAUTHENTICATED"
+	// ForbiddenCode is the extensions code of errors returned when the
+	// session fails the claim validators of a field
+	ForbiddenCode = "FORBIDDEN"
+)
+
+// Middleware verifies the session of requests to the GraphQL handler and
+// adds it to their context. Requests without a session are let through so
+// that public fields can be resolved; use Auth on the fields that need one.
+// Expired access tokens and sessions that fail the global claim validators
+// are still answered with the usual SuperTokens responses.
+func Middleware(options *sessmodels.VerifySessionOptions) func(http.Handler) http.Handler {
+	middlewareOptions := sessmodels.VerifySessionOptions{}
+	if options != nil {
+		middlewareOptions = *options
+	}
+	False := false
+	middlewareOptions.SessionRequired = &False
+	return func(next http.Handler) http.Handler {
+		return session.VerifySession(&middlewareOptions, next.ServeHTTP)
+	}
+}
+
+// GetSession returns the session added to ctx by Middleware, or nil if the
+// request has none.
+func GetSession(ctx context.Context) sessmodels.SessionContainer {
+	return session.GetSessionFromRequestContext(ctx)
+}
+
+// Auth returns the implementation of an @auth directive. Fields with it are
+// only resolved if the request has a session that passes claimValidators,
+// which are checked in addition to the global ones checked by Middleware.
+func Auth(claimValidators ...claims.SessionClaimValidator) func(ctx context.Context, obj interface{}, next graphql.Resolver) (interface{}, error) {
+	return func(ctx context.Context, obj interface{}, next graphql.Resolver) (interface{}, error) {
+		sessionContainer := GetSession(ctx)
+		if sessionContainer == nil {
+			return nil, &gqlerror.Error{
+				Path:       graphql.GetPath(ctx),
+				Message:    "unauthorised",
+				Extensions: map[string]interface{}{"code": UnauthenticatedCode},
+			}
+		}
+		if len(claimValidators) > 0 {
+			err := sessionContainer.AssertClaimsWithContext(claimValidators, &map[string]interface{}{})
+			if err != nil {
+				var invalidClaimError errors.InvalidClaimError
+				if defaultErrors.As(err, &invalidClaimError) {
+					return nil, &gqlerror.Error{
+						Path:    graphql.GetPath(ctx),
+						Message: "invalid claims",
+						Extensions: map[string]interface{}{
+							"code":          ForbiddenCode,
+							"invalidClaims": invalidClaimError.InvalidClaims,
+						},
+					}
+				}
+				return nil, err
+			}
+		}
+		return next(ctx)
+	}
+}
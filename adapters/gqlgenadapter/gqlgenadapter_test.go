@@ -0,0 +1,88 @@
+/* Copyright (c) 2023, VRAI Labs and/or its affiliates. All rights reserved.
+ *
+ * This software is licensed under the Apache License, Version 2.0 (the
+ * "License") as published by the Apache Software Foundation.
+ *
+ * You may not use this file except in compliance with the License. You may
+ * obtain a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+ * License for the specific language governing permissions and limitations
+ * under the License.
+ */
+
+package gqlgenadapter
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/supertokens/supertokens-golang/recipe/session"
+	"github.com/supertokens/supertokens-golang/recipe/session/claims"
+	"github.com/supertokens/supertokens-golang/recipe/session/errors"
+	"github.com/supertokens/supertokens-golang/recipe/session/sessmodels"
+	"github.com/supertokens/supertokens-golang/supertokens"
+	"github.com/vektah/gqlparser/v2/gqlerror"
+)
+
+func TestMiddlewareLetsRequestsWithoutSessionThrough(t *testing.T) {
+	supertokens.ResetForTest()
+	session.ResetForTest()
+	defer supertokens.ResetForTest()
+	defer session.ResetForTest()
+	err := supertokens.Init(supertokens.TypeInput{
+		Supertokens: &supertokens.ConnectionInfo{
+			ConnectionURI: "http://localhost:8080",
+		},
+		AppInfo: supertokens.AppInfo{
+			AppName:       "SuperTokens",
+			WebsiteDomain: "supertokens.io",
+			APIDomain:     "api.supertokens.io",
+		},
+		RecipeList: []supertokens.Recipe{session.Init(nil)},
+	})
+	assert.NoError(t, err)
+
+	called := false
+	handler := Middleware(nil)(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		called = true
+		assert.Nil(t, GetSession(r.Context()))
+	}))
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, "/query", nil))
+	assert.True(t, called)
+}
+
+func TestAuthDirective(t *testing.T) {
+	next := func(ctx context.Context) (interface{}, error) {
+		return "resolved", nil
+	}
+
+	res, err := Auth()(context.Background(), nil, next)
+	assert.Nil(t, res)
+	assert.Equal(t, UnauthenticatedCode, err.(*gqlerror.Error).Extensions["code"])
+
+	invalidClaims := []claims.ClaimValidationError{{ID: "st-role"}}
+	sessionContainer := &sessmodels.TypeSessionContainer{
+		AssertClaimsWithContext: func(claimValidators []claims.SessionClaimValidator, userContext supertokens.UserContext) error {
+			if len(claimValidators) > 0 {
+				return errors.InvalidClaimError{Msg: "invalid claims", InvalidClaims: invalidClaims}
+			}
+			return nil
+		},
+	}
+	ctx := context.WithValue(context.Background(), sessmodels.SessionContext, sessmodels.SessionContainer(sessionContainer))
+
+	res, err = Auth()(ctx, nil, next)
+	assert.NoError(t, err)
+	assert.Equal(t, "resolved", res)
+
+	res, err = Auth(claims.SessionClaimValidator{ID: "st-role"})(ctx, nil, next)
+	assert.Nil(t, res)
+	assert.Equal(t, ForbiddenCode, err.(*gqlerror.Error).Extensions["code"])
+	assert.Equal(t, invalidClaims, err.(*gqlerror.Error).Extensions["invalidClaims"])
+}
@@ -0,0 +1,67 @@
+/* Copyright (c) 2023, VRAI Labs and/or its affiliates. All rights reserved.
+ *
+ * This software is licensed under the Apache License, Version 2.0 (the
+ * "License") as published by the Apache Software Foundation.
+ *
+ * You may not use this file except in compliance with the License. You may
+ * obtain a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+ * License for the specific language governing permissions and limitations
+ * under the License.
+ */
+
+// Package muxadapter wires SuperTokens into gorilla/mux routers.
+package muxadapter
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/gorilla/mux"
+	"github.com/supertokens/supertokens-golang/recipe/session"
+	"github.com/supertokens/supertokens-golang/recipe/session/sessmodels"
+	"github.com/supertokens/supertokens-golang/supertokens"
+)
+
+// tenantIdPattern matches the tenant IDs that the middleware accepts
+// between the API base path and the path of an API
+const tenantIdPattern = "{tenantId:[a-zA-Z0-9-]+}"
+
+// RegisterRoutes adds a route to router for every API served by the recipes
+// in the RecipeList, with and without a tenant ID, instead of wrapping the
+// whole router with supertokens.Middleware. It must be called after
+// supertokens.Init.
+//
+// APIs whose path is matched by the recipe itself rather than from its table
+// of APIs (such as the assets of the dashboard UI) are not registered.
+func RegisterRoutes(router *mux.Router) error {
+	routes, err := supertokens.GetAPIRoutes()
+	if err != nil {
+		return err
+	}
+	handler := supertokens.Middleware(http.NotFoundHandler())
+	for _, route := range routes {
+		basePath := strings.TrimSuffix(route.Path, route.PathWithoutAPIBasePath)
+		router.Handle(route.Path, handler).Methods(route.Method)
+		router.Handle(basePath+"/"+tenantIdPattern+route.PathWithoutAPIBasePath, handler).Methods(route.Method)
+	}
+	return nil
+}
+
+// VerifySession checks the session of the request before calling next. It
+// can be passed to router.Handle, or used as a mux.MiddlewareFunc on a
+// subrouter with VerifySessionMiddleware.
+func VerifySession(options *sessmodels.VerifySessionOptions, next http.Handler) http.Handler {
+	return session.VerifySession(options, next.ServeHTTP)
+}
+
+// VerifySessionMiddleware returns a mux.MiddlewareFunc that checks the
+// session of every request matched by the router it is added to.
+func VerifySessionMiddleware(options *sessmodels.VerifySessionOptions) mux.MiddlewareFunc {
+	return func(next http.Handler) http.Handler {
+		return VerifySession(options, next)
+	}
+}
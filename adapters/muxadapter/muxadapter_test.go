@@ -0,0 +1,82 @@
+/* Copyright (c) 2023, VRAI Labs and/or its affiliates. All rights reserved.
+ *
+ * This software is licensed under the Apache License, Version 2.0 (the
+ * "License") as published by the Apache Software Foundation.
+ *
+ * You may not use this file except in compliance with the License. You may
+ * obtain a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+ * License for the specific language governing permissions and limitations
+ * under the License.
+ */
+
+package muxadapter
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/supertokens/supertokens-golang/recipe/session"
+	"github.com/supertokens/supertokens-golang/supertokens"
+)
+
+func TestMuxAdapter(t *testing.T) {
+	supertokens.ResetForTest()
+	session.ResetForTest()
+	defer supertokens.ResetForTest()
+	defer session.ResetForTest()
+	err := supertokens.Init(supertokens.TypeInput{
+		Supertokens: &supertokens.ConnectionInfo{
+			ConnectionURI: "http://localhost:8080",
+		},
+		AppInfo: supertokens.AppInfo{
+			AppName:       "SuperTokens",
+			WebsiteDomain: "supertokens.io",
+			APIDomain:     "api.supertokens.io",
+		},
+		RecipeList: []supertokens.Recipe{
+			session.Init(nil),
+			supertokens.MakeCustomRecipe(supertokens.CustomRecipeConfig{
+				RecipeID: "ping",
+				APIs: []supertokens.CustomRecipeAPI{{
+					Method: http.MethodGet,
+					Path:   "/ping",
+					Handler: func(tenantId string, req *http.Request, res http.ResponseWriter, userContext supertokens.UserContext) error {
+						return supertokens.Send200Response(res, map[string]interface{}{"tenantId": tenantId})
+					},
+				}},
+			}),
+		},
+	})
+	assert.NoError(t, err)
+
+	router := mux.NewRouter().StrictSlash(true)
+	assert.NoError(t, RegisterRoutes(router))
+	router.Handle("/sessioninfo", VerifySession(nil, http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		rw.WriteHeader(http.StatusOK)
+	}))).Methods(http.MethodGet)
+
+	res := httptest.NewRecorder()
+	router.ServeHTTP(res, httptest.NewRequest(http.MethodGet, "/auth/ping", nil))
+	assert.Equal(t, http.StatusOK, res.Code)
+	assert.JSONEq(t, `{"tenantId":"public"}`, res.Body.String())
+
+	res = httptest.NewRecorder()
+	router.ServeHTTP(res, httptest.NewRequest(http.MethodGet, "/auth/tenant1/ping", nil))
+	assert.Equal(t, http.StatusOK, res.Code)
+	assert.JSONEq(t, `{"tenantId":"tenant1"}`, res.Body.String())
+
+	res = httptest.NewRecorder()
+	router.ServeHTTP(res, httptest.NewRequest(http.MethodPost, "/auth/session/refresh", nil))
+	assert.Equal(t, http.StatusUnauthorized, res.Code)
+
+	res = httptest.NewRecorder()
+	router.ServeHTTP(res, httptest.NewRequest(http.MethodGet, "/sessioninfo", nil))
+	assert.Equal(t, http.StatusUnauthorized, res.Code)
+}
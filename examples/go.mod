@@ -14,9 +14,15 @@ require (
 	github.com/osohq/go-oso v0.21.0
 	github.com/spf13/viper v1.8.1
 	github.com/supertokens/supertokens-golang v0.0.0-20210909070424-b13c10ce5994
+	github.com/supertokens/supertokens-golang/adapters/echoadapter v0.0.0-00010101000000-000000000000
+	github.com/supertokens/supertokens-golang/adapters/ginadapter v0.0.0-00010101000000-000000000000
 	github.com/twitchtv/twirp v8.1.0+incompatible
 	github.com/zeromicro/go-zero v1.3.5
 	google.golang.org/protobuf v1.28.0
 )
 
 replace github.com/supertokens/supertokens-golang => ../
+
+replace github.com/supertokens/supertokens-golang/adapters/echoadapter => ../adapters/echoadapter
+
+replace github.com/supertokens/supertokens-golang/adapters/ginadapter => ../adapters/ginadapter
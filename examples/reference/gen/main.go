@@ -0,0 +1,124 @@
+// Command gen generates the reference apps in examples/reference. Each app
+// has the same auth setup and end to end test, and differs only in the web
+// framework used to serve it.
+//
+// Run it from the examples directory with:
+//
+//	go run ./reference/gen
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"go/format"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/template"
+)
+
+type framework struct {
+	// Name is used in the directory of the app, e.g. with-chi
+	Name        string
+	DisplayName string
+	URL         string
+	Imports     []string
+	// MakeHandler is the body of makeHandler in main.go
+	MakeHandler string
+}
+
+var frameworks = []framework{
+	{
+		Name:        "http",
+		DisplayName: "net/http",
+		URL:         "https://pkg.go.dev/net/http",
+		MakeHandler: `	mux := http.NewServeMux()
+	mux.Handle("/sessioninfo", session.VerifySession(nil, sessioninfo))
+	mux.Handle("/admin", session.VerifySession(adminOnly(), admin))
+	return corsMiddleware(supertokens.Middleware(mux))`,
+	},
+	{
+		Name:        "chi",
+		DisplayName: "chi",
+		URL:         "https://github.com/go-chi/chi",
+		Imports:     []string{"github.com/go-chi/chi/v5"},
+		MakeHandler: `	r := chi.NewRouter()
+	r.Use(corsMiddleware)
+	r.Use(supertokens.Middleware)
+	r.Get("/sessioninfo", session.VerifySession(nil, sessioninfo))
+	r.Get("/admin", session.VerifySession(adminOnly(), admin))
+	return r`,
+	},
+	{
+		Name:        "gin",
+		DisplayName: "gin",
+		URL:         "https://github.com/gin-gonic/gin",
+		Imports:     []string{"github.com/gin-gonic/gin"},
+		MakeHandler: `	router := gin.New()
+	router.GET("/sessioninfo", gin.WrapF(session.VerifySession(nil, sessioninfo)))
+	router.GET("/admin", gin.WrapF(session.VerifySession(adminOnly(), admin)))
+	return corsMiddleware(supertokens.Middleware(router))`,
+	},
+	{
+		Name:        "mux",
+		DisplayName: "gorilla/mux",
+		URL:         "https://github.com/gorilla/mux",
+		Imports:     []string{"github.com/gorilla/mux"},
+		MakeHandler: `	router := mux.NewRouter()
+	router.HandleFunc("/sessioninfo", session.VerifySession(nil, sessioninfo)).Methods(http.MethodGet)
+	router.HandleFunc("/admin", session.VerifySession(adminOnly(), admin)).Methods(http.MethodGet)
+	return corsMiddleware(supertokens.Middleware(router))`,
+	},
+}
+
+func main() {
+	outDir := flag.String("out", "reference", "directory to write the apps to")
+	flag.Parse()
+
+	for _, fw := range frameworks {
+		appDir := filepath.Join(*outDir, "with-"+fw.Name)
+		err := os.MkdirAll(appDir, 0755)
+		if err != nil {
+			panic(err.Error())
+		}
+		files, err := generateApp(fw)
+		if err != nil {
+			panic(err.Error())
+		}
+		for fileName, content := range files {
+			err = ioutil.WriteFile(filepath.Join(appDir, fileName), content, 0644)
+			if err != nil {
+				panic(err.Error())
+			}
+		}
+	}
+}
+
+// generateApp returns the content of the files of the app for fw, by file name
+func generateApp(fw framework) (map[string][]byte, error) {
+	templates := map[string]*template.Template{
+		"main.go":      mainTemplate,
+		"auth.go":      authTemplate,
+		"main_test.go": testTemplate,
+		"README.md":    readmeTemplate,
+	}
+	files := map[string][]byte{}
+	for fileName, tmpl := range templates {
+		var buf bytes.Buffer
+		err := tmpl.Execute(&buf, fw)
+		if err != nil {
+			return nil, err
+		}
+		content := buf.Bytes()
+		if strings.HasSuffix(fileName, ".go") {
+			content, err = format.Source(content)
+			if err != nil {
+				return nil, fmt.Errorf("%s of %s: %s", fileName, fw.Name, err.Error())
+			}
+		}
+		files[fileName] = content
+	}
+	return files, nil
+}
@@ -0,0 +1,28 @@
+package main
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+)
+
+// TestThatCommittedAppsAreUpToDate fails if the generator was changed without
+// running go generate ./reference (or if the generated files were edited)
+func TestThatCommittedAppsAreUpToDate(t *testing.T) {
+	for _, fw := range frameworks {
+		files, err := generateApp(fw)
+		if err != nil {
+			t.Fatal(err.Error())
+		}
+		for fileName, content := range files {
+			path := filepath.Join("..", "with-"+fw.Name, fileName)
+			committed, err := ioutil.ReadFile(path)
+			if err != nil {
+				t.Fatal(err.Error())
+			}
+			if string(committed) != string(content) {
+				t.Errorf("%s is out of date, please run go generate ./reference in the examples directory", path)
+			}
+		}
+	}
+}
@@ -0,0 +1,254 @@
+package main
+
+import "text/template"
+
+const generatedHeader = "// Code generated by reference/gen. DO NOT EDIT.\n\n"
+
+var mainTemplate = template.Must(template.New("main.go").Parse(generatedHeader + `package main
+
+import (
+	"net/http"
+	"strings"
+
+{{range .Imports}}	"{{.}}"
+{{end}}
+	"github.com/supertokens/supertokens-golang/recipe/session"
+	"github.com/supertokens/supertokens-golang/supertokens"
+)
+
+func main() {
+	err := supertokens.Init(makeConfig())
+	if err != nil {
+		panic(err.Error())
+	}
+
+	err = http.ListenAndServe(":3001", makeHandler())
+	if err != nil {
+		panic(err.Error())
+	}
+}
+
+// makeHandler serves the app's APIs with {{.DisplayName}}. It must be called
+// after supertokens.Init.
+func makeHandler() http.Handler {
+{{.MakeHandler}}
+}
+
+func corsMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(response http.ResponseWriter, r *http.Request) {
+		response.Header().Set("Access-Control-Allow-Origin", websiteDomain)
+		response.Header().Set("Access-Control-Allow-Credentials", "true")
+		if r.Method == "OPTIONS" {
+			response.Header().Set("Access-Control-Allow-Headers", strings.Join(append([]string{"Content-Type"}, supertokens.GetAllCORSHeaders()...), ","))
+			response.Header().Set("Access-Control-Allow-Methods", "*")
+			response.Write([]byte(""))
+		} else {
+			next.ServeHTTP(response, r)
+		}
+	})
+}
+`))
+
+var authTemplate = template.Must(template.New("auth.go").Parse(generatedHeader + `package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"os"
+
+	"github.com/supertokens/supertokens-golang/recipe/dashboard"
+	"github.com/supertokens/supertokens-golang/recipe/emailverification"
+	"github.com/supertokens/supertokens-golang/recipe/emailverification/evmodels"
+	"github.com/supertokens/supertokens-golang/recipe/session"
+	"github.com/supertokens/supertokens-golang/recipe/session/claims"
+	"github.com/supertokens/supertokens-golang/recipe/session/sessmodels"
+	"github.com/supertokens/supertokens-golang/recipe/thirdparty/tpmodels"
+	"github.com/supertokens/supertokens-golang/recipe/thirdpartyemailpassword"
+	"github.com/supertokens/supertokens-golang/recipe/thirdpartyemailpassword/tpepmodels"
+	"github.com/supertokens/supertokens-golang/recipe/userroles"
+	"github.com/supertokens/supertokens-golang/recipe/userroles/userrolesclaims"
+	"github.com/supertokens/supertokens-golang/supertokens"
+)
+
+const apiDomain = "http://localhost:3001"
+const websiteDomain = "http://localhost:3000"
+
+func makeConfig() supertokens.TypeInput {
+	connectionURI := os.Getenv("SUPERTOKENS_CONNECTION_URI")
+	if connectionURI == "" {
+		connectionURI = "https://try.supertokens.io"
+	}
+	return supertokens.TypeInput{
+		Supertokens: &supertokens.ConnectionInfo{
+			ConnectionURI: connectionURI,
+			APIKey:        os.Getenv("SUPERTOKENS_API_KEY"),
+		},
+		AppInfo: supertokens.AppInfo{
+			AppName:       "SuperTokens Reference App",
+			APIDomain:     apiDomain,
+			WebsiteDomain: websiteDomain,
+		},
+		RecipeList: recipeList(),
+	}
+}
+
+func recipeList() []supertokens.Recipe {
+	return []supertokens.Recipe{
+		emailverification.Init(evmodels.TypeInput{
+			Mode: evmodels.ModeRequired,
+		}),
+		thirdpartyemailpassword.Init(&tpepmodels.TypeInput{
+			Providers: []tpmodels.ProviderInput{
+				// These are development keys. Please replace them with your
+				// own OAuth keys for production use.
+				{
+					Config: tpmodels.ProviderConfig{
+						ThirdPartyId: "google",
+						Clients: []tpmodels.ProviderClientConfig{
+							{
+								ClientID:     "1060725074195-kmeum4crr01uirfl2op9kd5acmi9jutn.apps.googleusercontent.com",
+								ClientSecret: "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",
+							},
+						},
+					},
+				},
+				{
+					Config: tpmodels.ProviderConfig{
+						ThirdPartyId: "github",
+						Clients: []tpmodels.ProviderClientConfig{
+							{
+								ClientID:     "467101b197249757c71f",
+								ClientSecret: "e97051221f4b6426e8fe8d51486396703012f5bd",
+							},
+						},
+					},
+				},
+			},
+		}),
+		userroles.Init(nil),
+		session.Init(nil),
+		dashboard.Init(nil),
+	}
+}
+
+// adminOnly requires the "admin" role, in addition to a verified email
+func adminOnly() *sessmodels.VerifySessionOptions {
+	return &sessmodels.VerifySessionOptions{
+		OverrideGlobalClaimValidators: func(globalClaimValidators []claims.SessionClaimValidator, sessionContainer sessmodels.SessionContainer, userContext supertokens.UserContext) ([]claims.SessionClaimValidator, error) {
+			return append(globalClaimValidators, userrolesclaims.UserRoleClaimValidators.Includes("admin", nil, nil)), nil
+		},
+	}
+}
+
+func sessioninfo(w http.ResponseWriter, r *http.Request) {
+	sessionContainer := session.GetSessionFromRequestContext(r.Context())
+	writeJSON(w, map[string]interface{}{
+		"sessionHandle":      sessionContainer.GetHandle(),
+		"userId":             sessionContainer.GetUserID(),
+		"accessTokenPayload": sessionContainer.GetAccessTokenPayload(),
+	})
+}
+
+func admin(w http.ResponseWriter, r *http.Request) {
+	sessionContainer := session.GetSessionFromRequestContext(r.Context())
+	writeJSON(w, map[string]interface{}{
+		"message": "hello admin " + sessionContainer.GetUserID(),
+	})
+}
+
+func writeJSON(w http.ResponseWriter, body map[string]interface{}) {
+	bytes, err := json.Marshal(body)
+	if err != nil {
+		w.WriteHeader(500)
+		w.Write([]byte("error in converting to json"))
+		return
+	}
+	w.Header().Set("content-type", "application/json")
+	w.WriteHeader(200)
+	w.Write(bytes)
+}
+`))
+
+var testTemplate = template.Must(template.New("main_test.go").Parse(generatedHeader + `package main
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/supertokens/supertokens-golang/recipe/userroles"
+	"github.com/supertokens/supertokens-golang/test/stinttest"
+)
+
+// TestAuthFlows runs against a core started in docker, or the one set in
+// STINTTEST_CONNECTION_URI
+func TestAuthFlows(t *testing.T) {
+	core, err := stinttest.StartCore(context.Background(), stinttest.CoreOptions{})
+	if err != nil {
+		t.Skip("could not start a SuperTokens core: " + err.Error())
+	}
+	defer core.Stop()
+
+	h := stinttest.Setup(t, core, stinttest.Options{
+		RecipeList:  recipeList(),
+		MakeHandler: makeHandler,
+	})
+
+	email := fmt.Sprintf("user-%d@example.com", time.Now().UnixNano())
+	s := h.SignUp(email, "password123")
+	assertStatus(t, h, "/sessioninfo", s, http.StatusForbidden)
+
+	h.VerifyEmail(s)
+	s = h.SignIn(email, "password123")
+	assertStatus(t, h, "/sessioninfo", s, http.StatusOK)
+	assertStatus(t, h, "/admin", s, http.StatusForbidden)
+
+	_, err = userroles.CreateNewRoleOrAddPermissions("admin", []string{})
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	_, err = userroles.AddRoleToUser("public", s.UserID, "admin")
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	s = h.SignIn(email, "password123")
+	assertStatus(t, h, "/admin", s, http.StatusOK)
+
+	h.Refresh(s)
+	assertStatus(t, h, "/sessioninfo", s, http.StatusOK)
+
+	h.Revoke(s)
+	assertStatus(t, h, "/sessioninfo", s, http.StatusUnauthorized)
+}
+
+func assertStatus(t *testing.T, h *stinttest.Harness, path string, s *stinttest.TestSession, status int) {
+	t.Helper()
+	res, body := h.Request(http.MethodGet, path, nil, s)
+	if res.StatusCode != status {
+		t.Fatalf("GET %s: expected status %d, got %d: %s", path, status, res.StatusCode, string(body))
+	}
+}
+`))
+
+var readmeTemplate = template.Must(template.New("README.md").Parse(`This is a reference app using [{{.DisplayName}}]({{.URL}}) with SuperTokens. It is generated by ` + "`reference/gen`" + `, so please edit the generator and run ` + "`go generate ./reference`" + ` instead of editing these files.
+
+It covers:
+- Email password and social (Google, GitHub) sign in
+- Required email verification
+- Role based access control, with an ` + "`/admin`" + ` API that needs the ` + "`admin`" + ` role
+- Session refresh and revocation
+
+To start this server, run:
+` + "```" + `
+go run ./reference/with-{{.Name}}
+` + "```" + `
+
+It uses the core at ` + "`SUPERTOKENS_CONNECTION_URI`" + `, or https://try.supertokens.io if that is not set.
+
+The test runs the flows above end to end, against a core started in docker (or the one set in ` + "`STINTTEST_CONNECTION_URI`" + `):
+` + "```" + `
+go test ./reference/with-{{.Name}}
+` + "```" + `
+`))
@@ -0,0 +1,5 @@
+// Package reference holds the reference apps generated by reference/gen, one
+// per web framework.
+package reference
+
+//go:generate go run ./gen -out .
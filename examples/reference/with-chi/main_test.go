@@ -0,0 +1,63 @@
+// Code generated by reference/gen. DO NOT EDIT.
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/supertokens/supertokens-golang/recipe/userroles"
+	"github.com/supertokens/supertokens-golang/test/stinttest"
+)
+
+// TestAuthFlows runs against a core started in docker, or the one set in
+// STINTTEST_CONNECTION_URI
+func TestAuthFlows(t *testing.T) {
+	core, err := stinttest.StartCore(context.Background(), stinttest.CoreOptions{})
+	if err != nil {
+		t.Skip("could not start a SuperTokens core: " + err.Error())
+	}
+	defer core.Stop()
+
+	h := stinttest.Setup(t, core, stinttest.Options{
+		RecipeList:  recipeList(),
+		MakeHandler: makeHandler,
+	})
+
+	email := fmt.Sprintf("user-%d@example.com", time.Now().UnixNano())
+	s := h.SignUp(email, "password123")
+	assertStatus(t, h, "/sessioninfo", s, http.StatusForbidden)
+
+	h.VerifyEmail(s)
+	s = h.SignIn(email, "password123")
+	assertStatus(t, h, "/sessioninfo", s, http.StatusOK)
+	assertStatus(t, h, "/admin", s, http.StatusForbidden)
+
+	_, err = userroles.CreateNewRoleOrAddPermissions("admin", []string{})
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	_, err = userroles.AddRoleToUser("public", s.UserID, "admin")
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	s = h.SignIn(email, "password123")
+	assertStatus(t, h, "/admin", s, http.StatusOK)
+
+	h.Refresh(s)
+	assertStatus(t, h, "/sessioninfo", s, http.StatusOK)
+
+	h.Revoke(s)
+	assertStatus(t, h, "/sessioninfo", s, http.StatusUnauthorized)
+}
+
+func assertStatus(t *testing.T, h *stinttest.Harness, path string, s *stinttest.TestSession, status int) {
+	t.Helper()
+	res, body := h.Request(http.MethodGet, path, nil, s)
+	if res.StatusCode != status {
+		t.Fatalf("GET %s: expected status %d, got %d: %s", path, status, res.StatusCode, string(body))
+	}
+}
@@ -4,14 +4,13 @@ import (
 	"encoding/json"
 	"errors"
 	"github.com/supertokens/supertokens-golang/recipe/dashboard"
-	"net/http"
 	"strings"
 
 	"github.com/labstack/echo/v4"
+	"github.com/supertokens/supertokens-golang/adapters/echoadapter"
 	"github.com/supertokens/supertokens-golang/recipe/emailverification"
 	"github.com/supertokens/supertokens-golang/recipe/emailverification/evmodels"
 	"github.com/supertokens/supertokens-golang/recipe/session"
-	"github.com/supertokens/supertokens-golang/recipe/session/sessmodels"
 	"github.com/supertokens/supertokens-golang/recipe/thirdparty/tpmodels"
 	"github.com/supertokens/supertokens-golang/recipe/thirdpartyemailpassword"
 	"github.com/supertokens/supertokens-golang/recipe/thirdpartyemailpassword/tpepmodels"
@@ -179,36 +178,15 @@ func main() {
 	})
 
 	// SuperTokens Middleware
-	e.Use(func(hf echo.HandlerFunc) echo.HandlerFunc {
-		return echo.HandlerFunc(func(c echo.Context) error {
-			supertokens.Middleware(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
-				if err := hf(c); err != nil {
-					c.Error(err)
-				}
-			})).ServeHTTP(c.Response(), c.Request())
-			return nil
-		})
-	})
+	e.Use(echoadapter.Middleware())
 
-	e.GET("/sessioninfo", sessioninfo, verifySession(nil))
+	e.GET("/sessioninfo", sessioninfo, echoadapter.VerifySession(nil))
 
 	e.Start(":3001")
 }
 
-func verifySession(options *sessmodels.VerifySessionOptions) echo.MiddlewareFunc {
-	return func(hf echo.HandlerFunc) echo.HandlerFunc {
-		return func(c echo.Context) error {
-			session.VerifySession(options, func(rw http.ResponseWriter, r *http.Request) {
-				c.Set("session", session.GetSessionFromRequestContext(r.Context()))
-				hf(c)
-			})(c.Response(), c.Request())
-			return nil
-		}
-	}
-}
-
 func sessioninfo(c echo.Context) error {
-	sessionContainer := c.Get("session").(sessmodels.SessionContainer)
+	sessionContainer := echoadapter.GetSessionFromEchoContext(c)
 
 	if sessionContainer == nil {
 		return errors.New("no session found")
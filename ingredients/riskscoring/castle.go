@@ -0,0 +1,105 @@
+/* Copyright (c) 2023, VRAI Labs and/or its affiliates. All rights reserved.
+ *
+ * This software is licensed under the Apache License, Version 2.0 (the
+ * "License") as published by the Apache Software Foundation.
+ *
+ * You may not use this file except in compliance with the License. You may
+ * obtain a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+ * License for the specific language governing permissions and limitations
+ * under the License.
+ */
+
+package riskscoring
+
+import (
+	"encoding/base64"
+	"errors"
+	"time"
+
+	"github.com/supertokens/supertokens-golang/supertokens"
+)
+
+const defaultCastleAPIURL = "https://api.castle.io/v1/risk"
+const defaultCastleRequestTokenHeader = "X-Castle-Request-Token"
+
+type CastleSettings struct {
+	APISecret string
+	// APIURL defaults to Castle's risk endpoint
+	APIURL string
+	// RequestTokenHeader is the request header in which the frontend sends the
+	// token generated by Castle's browser SDK. Defaults to X-Castle-Request-Token
+	RequestTokenHeader string
+	// Timeout of requests to Castle. Defaults to 5 seconds.
+	Timeout time.Duration
+}
+
+// MakeCastleService scores sign-ins using Castle's risk API. Castle returns a
+// risk between 0 and 1, which is scaled to 0 - 100.
+func MakeCastleService(settings CastleSettings) *RiskScoringInterface {
+	apiURL := settings.APIURL
+	if apiURL == "" {
+		apiURL = defaultCastleAPIURL
+	}
+	requestTokenHeader := settings.RequestTokenHeader
+	if requestTokenHeader == "" {
+		requestTokenHeader = defaultCastleRequestTokenHeader
+	}
+	authorization := "Basic " + base64.StdEncoding.EncodeToString([]byte(":"+settings.APISecret))
+
+	getRiskScore := func(input RiskInput, userContext supertokens.UserContext) (RiskScore, error) {
+		if settings.APISecret == "" {
+			return RiskScore{}, errors.New("please provide the Castle API secret")
+		}
+		headers := map[string]interface{}{}
+		requestToken := ""
+		if input.Request != nil {
+			requestToken = input.Request.Header.Get(requestTokenHeader)
+			for key := range input.Request.Header {
+				if key == "Cookie" || key == "Authorization" {
+					continue
+				}
+				headers[key] = input.Request.Header.Get(key)
+			}
+		}
+
+		eventType := "$login"
+		if input.Action == RiskActionSignUp {
+			eventType = "$registration"
+		}
+		response, err := doPostRequest(apiURL, map[string]interface{}{
+			"type":          eventType,
+			"status":        "$succeeded",
+			"request_token": requestToken,
+			"user": map[string]interface{}{
+				"id": input.UserID,
+			},
+			"context": map[string]interface{}{
+				"ip":      input.IPAddress,
+				"headers": headers,
+			},
+		}, map[string]string{
+			"Authorization": authorization,
+		}, settings.Timeout, userContext)
+		if err != nil {
+			return RiskScore{}, err
+		}
+
+		risk, ok := response["risk"].(float64)
+		if !ok {
+			return RiskScore{}, errors.New("Castle response did not contain a risk score")
+		}
+		signals, _ := response["signals"].(map[string]interface{})
+		return RiskScore{
+			Score:   risk * 100,
+			Signals: signals,
+		}, nil
+	}
+
+	return &RiskScoringInterface{
+		GetRiskScore: &getRiskScore,
+	}
+}
@@ -0,0 +1,97 @@
+/* Copyright (c) 2023, VRAI Labs and/or its affiliates. All rights reserved.
+ *
+ * This software is licensed under the Apache License, Version 2.0 (the
+ * "License") as published by the Apache Software Foundation.
+ *
+ * You may not use this file except in compliance with the License. You may
+ * obtain a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+ * License for the specific language governing permissions and limitations
+ * under the License.
+ */
+
+package riskscoring
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"time"
+
+	"github.com/supertokens/supertokens-golang/supertokens"
+)
+
+type Ingredient struct {
+	IngredientInterfaceImpl RiskScoringInterface
+}
+
+func MakeIngredient(config TypeInputWithService) Ingredient {
+	result := Ingredient{
+		IngredientInterfaceImpl: config.Service,
+	}
+
+	if config.Override != nil {
+		result.IngredientInterfaceImpl = config.Override(result.IngredientInterfaceImpl)
+	}
+
+	return result
+}
+
+const defaultRequestTimeout = 5 * time.Second
+
+func doPostRequest(url string, body map[string]interface{}, headers map[string]string, timeout time.Duration, userContext supertokens.UserContext) (map[string]interface{}, error) {
+	supertokens.LogDebugMessage(fmt.Sprintf("POST request to %s for risk scoring", url))
+
+	postBody, err := json.Marshal(body)
+	if err != nil {
+		return nil, err
+	}
+	if timeout == 0 {
+		timeout = defaultRequestTimeout
+	}
+	ctx := context.Background()
+	if request := supertokens.GetRequestFromUserContext(userContext); request != nil {
+		ctx = request.Context()
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewBuffer(postBody))
+	if err != nil {
+		return nil, err
+	}
+	for key, value := range headers {
+		req.Header.Set(key, value)
+	}
+	req.Header.Set("content-type", "application/json")
+
+	resp, err := supertokens.GetEgressHTTPClient().Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	respBody, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	supertokens.LogDebugMessage(fmt.Sprintf("Received response with status %d from risk scoring provider", resp.StatusCode))
+
+	if resp.StatusCode >= 300 {
+		return nil, fmt.Errorf("POST request to %s resulted in %d status with body %s", url, resp.StatusCode, string(respBody))
+	}
+
+	var result map[string]interface{}
+	err = json.Unmarshal(respBody, &result)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
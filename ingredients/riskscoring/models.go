@@ -0,0 +1,61 @@
+/* Copyright (c) 2023, VRAI Labs and/or its affiliates. All rights reserved.
+ *
+ * This software is licensed under the Apache License, Version 2.0 (the
+ * "License") as published by the Apache Software Foundation.
+ *
+ * You may not use this file except in compliance with the License. You may
+ * obtain a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+ * License for the specific language governing permissions and limitations
+ * under the License.
+ */
+
+package riskscoring
+
+import (
+	"net/http"
+
+	"github.com/supertokens/supertokens-golang/supertokens"
+)
+
+type RiskScoringInterface struct {
+	GetRiskScore *func(input RiskInput, userContext supertokens.UserContext) (RiskScore, error)
+}
+
+type TypeInput struct {
+	Service  *RiskScoringInterface
+	Override func(originalImplementation RiskScoringInterface) RiskScoringInterface
+}
+
+type TypeInputWithService struct {
+	Service  RiskScoringInterface
+	Override func(originalImplementation RiskScoringInterface) RiskScoringInterface
+}
+
+type RiskAction string
+
+const (
+	RiskActionSignIn RiskAction = "SIGN_IN"
+	RiskActionSignUp RiskAction = "SIGN_UP"
+)
+
+type RiskInput struct {
+	// Action defaults to RiskActionSignIn
+	Action    RiskAction
+	UserID    string
+	TenantID  string
+	IPAddress string
+	UserAgent string
+	// Request is nil if the session is created outside of an API call
+	Request *http.Request
+}
+
+type RiskScore struct {
+	// Score is normalised to be between 0 (no risk) and 100 (highest risk)
+	// regardless of the provider.
+	Score   float64
+	Signals map[string]interface{}
+}
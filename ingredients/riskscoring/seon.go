@@ -0,0 +1,86 @@
+/* Copyright (c) 2023, VRAI Labs and/or its affiliates. All rights reserved.
+ *
+ * This software is licensed under the Apache License, Version 2.0 (the
+ * "License") as published by the Apache Software Foundation.
+ *
+ * You may not use this file except in compliance with the License. You may
+ * obtain a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+ * License for the specific language governing permissions and limitations
+ * under the License.
+ */
+
+package riskscoring
+
+import (
+	"errors"
+	"time"
+
+	"github.com/supertokens/supertokens-golang/supertokens"
+)
+
+const defaultSEONAPIURL = "https://api.seon.io/SeonRestService/fraud-api/v2/"
+
+type SEONSettings struct {
+	LicenseKey string
+	// APIURL defaults to SEON's global fraud API endpoint
+	APIURL string
+	// Timeout of requests to SEON. Defaults to 5 seconds.
+	Timeout time.Duration
+}
+
+// MakeSEONService scores sign-ins using SEON's fraud API. SEON returns a
+// fraud_score between 0 and 100, which is used as is.
+func MakeSEONService(settings SEONSettings) *RiskScoringInterface {
+	apiURL := settings.APIURL
+	if apiURL == "" {
+		apiURL = defaultSEONAPIURL
+	}
+
+	getRiskScore := func(input RiskInput, userContext supertokens.UserContext) (RiskScore, error) {
+		if settings.LicenseKey == "" {
+			return RiskScore{}, errors.New("please provide the SEON license key")
+		}
+		actionType := "account_login"
+		if input.Action == RiskActionSignUp {
+			actionType = "account_register"
+		}
+		body := map[string]interface{}{
+			"action_type": actionType,
+			"user_id":     input.UserID,
+		}
+		if input.IPAddress != "" {
+			body["ip"] = input.IPAddress
+		}
+		if input.UserAgent != "" {
+			body["user_agent"] = input.UserAgent
+		}
+
+		response, err := doPostRequest(apiURL, body, map[string]string{
+			"X-API-KEY": settings.LicenseKey,
+		}, settings.Timeout, userContext)
+		if err != nil {
+			return RiskScore{}, err
+		}
+
+		data, ok := response["data"].(map[string]interface{})
+		if !ok {
+			return RiskScore{}, errors.New("SEON response did not contain any data")
+		}
+		score, ok := data["fraud_score"].(float64)
+		if !ok {
+			return RiskScore{}, errors.New("SEON response did not contain a fraud_score")
+		}
+		return RiskScore{
+			Score:   score,
+			Signals: data,
+		}, nil
+	}
+
+	return &RiskScoringInterface{
+		GetRiskScore: &getRiskScore,
+	}
+}
@@ -2,6 +2,7 @@ package api
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"net/http"
 
@@ -110,17 +111,22 @@ func AnalyticsPost(apiInterface dashboardmodels.APIInterface, tenantId string, o
 		return analyticsPostResponse{}, err
 	}
 
-	url := "https://api.supertokens.com/0/st/telemetry"
-	req, err := http.NewRequest("POST", url, bytes.NewBuffer(jsonData))
-	if err != nil {
-		return analyticsPostResponse{
-			Status: "OK",
-		}, nil
-	}
-	req.Header.Set("content-type", "application/json; charset=utf-8")
-	req.Header.Set("api-version", "3")
-	client := &http.Client{}
-	client.Do(req)
+	// telemetry is sent in the background so that the dashboard does not
+	// wait for it
+	supertokens.RunInBackground(func(ctx context.Context) {
+		url := "https://api.supertokens.com/0/st/telemetry"
+		req, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewBuffer(jsonData))
+		if err != nil {
+			return
+		}
+		req.Header.Set("content-type", "application/json; charset=utf-8")
+		req.Header.Set("api-version", "3")
+		client := supertokens.GetEgressHTTPClient()
+		resp, err := client.Do(req)
+		if err == nil {
+			resp.Body.Close()
+		}
+	})
 
 	return analyticsPostResponse{
 		Status: "OK",
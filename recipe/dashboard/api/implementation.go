@@ -55,19 +55,10 @@ func MakeAPIImplementation() dashboardmodels.APIInterface {
 
 		authMode := string(options.Config.AuthMode)
 
-		isSearchEnabled := false
-		querier, err := supertokens.GetNewQuerierInstanceOrThrowError(options.RecipeID)
+		isSearchEnabled, err := supertokens.CoreSupports(supertokens.CoreFeatureUserSearch)
 		if err != nil {
 			return "", err
 		}
-		cdiVersion, err := querier.GetQuerierAPIVersion()
-		if err != nil {
-			return "", err
-		}
-		if supertokens.MaxVersion(cdiVersion, "2.20") == cdiVersion {
-			// Only enable search for CDI version 2.20 and above
-			isSearchEnabled = true
-		}
 
 		return `
 		<html>
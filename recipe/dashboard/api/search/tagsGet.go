@@ -26,6 +26,11 @@ type searchTagsResponse struct {
 }
 
 func SearchTagsGet(apiImplementation dashboardmodels.APIInterface, tenantId string, options dashboardmodels.APIOptions, userContext supertokens.UserContext) (searchTagsResponse, error) {
+	err := supertokens.RequireCoreFeature(supertokens.CoreFeatureUserSearch)
+	if err != nil {
+		return searchTagsResponse{}, err
+	}
+
 	querier, querierErr := supertokens.GetNewQuerierInstanceOrThrowError("dashboard")
 
 	if querierErr != nil {
@@ -0,0 +1,141 @@
+/* Copyright (c) 2023, VRAI Labs and/or its affiliates. All rights reserved.
+ *
+ * This software is licensed under the Apache License, Version 2.0 (the
+ * "License") as published by the Apache Software Foundation.
+ *
+ * You may not use this file except in compliance with the License. You may
+ * obtain a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+ * License for the specific language governing permissions and limitations
+ * under the License.
+ */
+
+package emailpassword
+
+import (
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/supertokens/supertokens-golang/recipe/emailpassword/epmodels"
+	"github.com/supertokens/supertokens-golang/supertokens"
+)
+
+const (
+	defaultAccountLockoutMaxFailedAttempts = 5
+	defaultAccountLockoutFailureWindow     = 15 * time.Minute
+	defaultAccountLockoutDuration          = 15 * time.Minute
+)
+
+func normaliseAccountLockoutInput(config *epmodels.AccountLockoutInput) (*epmodels.NormalisedAccountLockoutConfig, error) {
+	if config == nil {
+		return nil, nil
+	}
+	result := &epmodels.NormalisedAccountLockoutConfig{
+		MaxFailedAttempts: defaultAccountLockoutMaxFailedAttempts,
+		FailureWindow:     defaultAccountLockoutFailureWindow,
+		LockoutDuration:   defaultAccountLockoutDuration,
+		OnAccountLocked:   config.OnAccountLocked,
+	}
+	if config.MaxFailedAttempts != nil {
+		if *config.MaxFailedAttempts == 0 {
+			return nil, errors.New("AccountLockout MaxFailedAttempts must be at least 1")
+		}
+		result.MaxFailedAttempts = *config.MaxFailedAttempts
+	}
+	if config.FailureWindow < 0 || config.LockoutDuration < 0 {
+		return nil, errors.New("AccountLockout FailureWindow and LockoutDuration cannot be negative")
+	}
+	if config.FailureWindow != 0 {
+		result.FailureWindow = config.FailureWindow
+	}
+	if config.LockoutDuration != 0 {
+		result.LockoutDuration = config.LockoutDuration
+	}
+	if config.Store != nil {
+		if config.Store.RecordFailedAttempt == nil || config.Store.Lock == nil || config.Store.GetLockedUntil == nil || config.Store.Reset == nil {
+			return nil, errors.New("account lockout store must implement RecordFailedAttempt, Lock, GetLockedUntil and Reset")
+		}
+		result.Store = *config.Store
+	} else {
+		result.Store = MakeInMemoryAccountLockoutStore()
+	}
+	return result, nil
+}
+
+// MakeInMemoryAccountLockoutStore returns an account lockout store that keeps
+// the failed attempts and locks in process memory.
+func MakeInMemoryAccountLockoutStore() epmodels.AccountLockoutStore {
+	type accountState struct {
+		failedAttempts uint64
+		windowEnd      time.Time
+		lockedUntil    time.Time
+	}
+	var mutex sync.Mutex
+	accounts := map[string]*accountState{}
+
+	removeExpired := func(now time.Time) {
+		for key, state := range accounts {
+			if !now.Before(state.windowEnd) && !now.Before(state.lockedUntil) {
+				delete(accounts, key)
+			}
+		}
+	}
+
+	recordFailedAttempt := func(key string, window time.Duration, userContext supertokens.UserContext) (uint64, error) {
+		mutex.Lock()
+		defer mutex.Unlock()
+
+		now := time.Now()
+		removeExpired(now)
+		state, ok := accounts[key]
+		if !ok {
+			state = &accountState{}
+			accounts[key] = state
+		}
+		if !now.Before(state.windowEnd) {
+			state.failedAttempts = 0
+			state.windowEnd = now.Add(window)
+		}
+		state.failedAttempts++
+		return state.failedAttempts, nil
+	}
+
+	lock := func(key string, lockedUntil time.Time, userContext supertokens.UserContext) error {
+		mutex.Lock()
+		defer mutex.Unlock()
+
+		accounts[key] = &accountState{lockedUntil: lockedUntil}
+		return nil
+	}
+
+	getLockedUntil := func(key string, userContext supertokens.UserContext) (*time.Time, error) {
+		mutex.Lock()
+		defer mutex.Unlock()
+
+		state, ok := accounts[key]
+		if !ok || !time.Now().Before(state.lockedUntil) {
+			return nil, nil
+		}
+		lockedUntil := state.lockedUntil
+		return &lockedUntil, nil
+	}
+
+	reset := func(key string, userContext supertokens.UserContext) error {
+		mutex.Lock()
+		defer mutex.Unlock()
+
+		delete(accounts, key)
+		return nil
+	}
+
+	return epmodels.AccountLockoutStore{
+		RecordFailedAttempt: &recordFailedAttempt,
+		Lock:                &lock,
+		GetLockedUntil:      &getLockedUntil,
+		Reset:               &reset,
+	}
+}
@@ -0,0 +1,131 @@
+/* Copyright (c) 2023, VRAI Labs and/or its affiliates. All rights reserved.
+ *
+ * This software is licensed under the Apache License, Version 2.0 (the
+ * "License") as published by the Apache Software Foundation.
+ *
+ * You may not use this file except in compliance with the License. You may
+ * obtain a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+ * License for the specific language governing permissions and limitations
+ * under the License.
+ */
+
+package emailpassword
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/supertokens/supertokens-golang/recipe/emailpassword/api"
+	"github.com/supertokens/supertokens-golang/recipe/emailpassword/epmodels"
+	"github.com/supertokens/supertokens-golang/supertokens"
+)
+
+func TestAccountLockoutConfigValidation(t *testing.T) {
+	config, err := normaliseAccountLockoutInput(nil)
+	assert.NoError(t, err)
+	assert.Nil(t, config)
+
+	zero := uint64(0)
+	_, err = normaliseAccountLockoutInput(&epmodels.AccountLockoutInput{MaxFailedAttempts: &zero})
+	assert.Error(t, err)
+
+	_, err = normaliseAccountLockoutInput(&epmodels.AccountLockoutInput{Store: &epmodels.AccountLockoutStore{}})
+	assert.Error(t, err)
+
+	config, err = normaliseAccountLockoutInput(&epmodels.AccountLockoutInput{})
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(5), config.MaxFailedAttempts)
+	assert.Equal(t, 15*time.Minute, config.FailureWindow)
+	assert.Equal(t, 15*time.Minute, config.LockoutDuration)
+}
+
+func TestAccountLockoutOfSignIn(t *testing.T) {
+	signInCalls := 0
+	signIn := func(email string, password string, tenantId string, userContext supertokens.UserContext) (epmodels.SignInResponse, error) {
+		signInCalls++
+		return epmodels.SignInResponse{WrongCredentialsError: &struct{}{}}, nil
+	}
+	maxFailedAttempts := uint64(3)
+	locked := []string{}
+	config, err := validateAndNormaliseUserInput(nil, supertokens.NormalisedAppinfo{}, &epmodels.TypeInput{
+		AccountLockout: &epmodels.AccountLockoutInput{
+			MaxFailedAttempts: &maxFailedAttempts,
+			OnAccountLocked: func(tenantId string, email string, lockedUntil time.Time, userContext supertokens.UserContext) {
+				locked = append(locked, email)
+			},
+		},
+	})
+	assert.NoError(t, err)
+	options := epmodels.APIOptions{
+		Config:               config,
+		RecipeImplementation: epmodels.RecipeInterface{SignIn: &signIn},
+	}
+	signInPOST := *api.MakeAPIImplementation().SignInPOST
+	formFields := func(email string) []epmodels.TypeFormField {
+		return []epmodels.TypeFormField{{ID: "email", Value: email}, {ID: "password", Value: "wrongPass123"}}
+	}
+	userContext := &map[string]interface{}{}
+
+	for i := 0; i < 2; i++ {
+		response, err := signInPOST(formFields("test@example.com"), "public", options, userContext)
+		assert.NoError(t, err)
+		assert.NotNil(t, response.WrongCredentialsError)
+	}
+	response, err := signInPOST(formFields("test@example.com"), "public", options, userContext)
+	assert.NoError(t, err)
+	assert.NotNil(t, response.AccountLockedError)
+	assert.Equal(t, []string{"test@example.com"}, locked)
+
+	// a locked account is not checked by the core, even with the right password
+	response, err = signInPOST(formFields("Test@Example.com"), "public", options, userContext)
+	assert.NoError(t, err)
+	assert.NotNil(t, response.AccountLockedError)
+	assert.Equal(t, 3, signInCalls)
+
+	// other tenants and emails are not locked
+	response, err = signInPOST(formFields("test@example.com"), "tenant1", options, userContext)
+	assert.NoError(t, err)
+	assert.NotNil(t, response.WrongCredentialsError)
+	response, err = signInPOST(formFields("other@example.com"), "public", options, userContext)
+	assert.NoError(t, err)
+	assert.NotNil(t, response.WrongCredentialsError)
+
+	err = (*config.AccountLockout.Store.Reset)(api.GetAccountLockoutKey("public", "test@example.com"), userContext)
+	assert.NoError(t, err)
+	response, err = signInPOST(formFields("test@example.com"), "public", options, userContext)
+	assert.NoError(t, err)
+	assert.NotNil(t, response.WrongCredentialsError)
+}
+
+func TestInMemoryAccountLockoutStore(t *testing.T) {
+	store := MakeInMemoryAccountLockoutStore()
+	userContext := &map[string]interface{}{}
+
+	count, err := (*store.RecordFailedAttempt)("key", 20*time.Millisecond, userContext)
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(1), count)
+	count, err = (*store.RecordFailedAttempt)("key", 20*time.Millisecond, userContext)
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(2), count)
+
+	// failed attempts outside of the window are not counted
+	time.Sleep(30 * time.Millisecond)
+	count, err = (*store.RecordFailedAttempt)("key", 20*time.Millisecond, userContext)
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(1), count)
+
+	err = (*store.Lock)("key", time.Now().Add(20*time.Millisecond), userContext)
+	assert.NoError(t, err)
+	lockedUntil, err := (*store.GetLockedUntil)("key", userContext)
+	assert.NoError(t, err)
+	assert.NotNil(t, lockedUntil)
+	time.Sleep(30 * time.Millisecond)
+	lockedUntil, err = (*store.GetLockedUntil)("key", userContext)
+	assert.NoError(t, err)
+	assert.Nil(t, lockedUntil)
+}
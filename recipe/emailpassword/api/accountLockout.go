@@ -0,0 +1,74 @@
+/* Copyright (c) 2023, VRAI Labs and/or its affiliates. All rights reserved.
+ *
+ * This software is licensed under the Apache License, Version 2.0 (the
+ * "License") as published by the Apache Software Foundation.
+ *
+ * You may not use this file except in compliance with the License. You may
+ * obtain a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+ * License for the specific language governing permissions and limitations
+ * under the License.
+ */
+
+package api
+
+import (
+	"strings"
+	"time"
+
+	"github.com/supertokens/supertokens-golang/recipe/emailpassword/epmodels"
+	"github.com/supertokens/supertokens-golang/supertokens"
+)
+
+// GetAccountLockoutKey returns the key of an account in the account lockout
+// store. Emails are compared case insensitively.
+func GetAccountLockoutKey(tenantId string, email string) string {
+	return "signin:" + tenantId + ":" + strings.ToLower(strings.TrimSpace(email))
+}
+
+// getAccountLockedUntil returns when the lock of the account expires, or nil
+// if it is not locked
+func getAccountLockedUntil(config *epmodels.NormalisedAccountLockoutConfig, tenantId string, email string, userContext supertokens.UserContext) (*time.Time, error) {
+	if config == nil {
+		return nil, nil
+	}
+	lockedUntil, err := (*config.Store.GetLockedUntil)(GetAccountLockoutKey(tenantId, email), userContext)
+	if err != nil || lockedUntil == nil || !time.Now().Before(*lockedUntil) {
+		return nil, err
+	}
+	return lockedUntil, nil
+}
+
+// recordFailedSignIn locks the account when it reaches MaxFailedAttempts, and
+// then returns when the lock expires
+func recordFailedSignIn(config *epmodels.NormalisedAccountLockoutConfig, tenantId string, email string, userContext supertokens.UserContext) (*time.Time, error) {
+	if config == nil {
+		return nil, nil
+	}
+	key := GetAccountLockoutKey(tenantId, email)
+	failedAttempts, err := (*config.Store.RecordFailedAttempt)(key, config.FailureWindow, userContext)
+	if err != nil || failedAttempts < config.MaxFailedAttempts {
+		return nil, err
+	}
+
+	lockedUntil := time.Now().Add(config.LockoutDuration)
+	err = (*config.Store.Lock)(key, lockedUntil, userContext)
+	if err != nil {
+		return nil, err
+	}
+	supertokens.LogDebugMessage("signInPOST: Locking account after too many failed sign ins")
+	if config.OnAccountLocked != nil {
+		config.OnAccountLocked(tenantId, email, lockedUntil, userContext)
+	}
+	return &lockedUntil, nil
+}
+
+func resetFailedSignIns(config *epmodels.NormalisedAccountLockoutConfig, tenantId string, email string, userContext supertokens.UserContext) error {
+	if config == nil {
+		return nil
+	}
+	return (*config.Store.Reset)(GetAccountLockoutKey(tenantId, email), userContext)
+}
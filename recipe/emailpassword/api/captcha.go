@@ -0,0 +1,62 @@
+/* Copyright (c) 2023, VRAI Labs and/or its affiliates. All rights reserved.
+ *
+ * This software is licensed under the Apache License, Version 2.0 (the
+ * "License") as published by the Apache Software Foundation.
+ *
+ * You may not use this file except in compliance with the License. You may
+ * obtain a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+ * License for the specific language governing permissions and limitations
+ * under the License.
+ */
+
+package api
+
+import (
+	"github.com/supertokens/supertokens-golang/recipe/emailpassword/epmodels"
+	"github.com/supertokens/supertokens-golang/supertokens"
+)
+
+// verifyCaptcha removes the captcha field from the raw form fields and
+// verifies its token. It returns the other form fields, and false if the
+// captcha is not valid. Form fields that are not an array are returned as
+// they are, so that their validation fails.
+func verifyCaptcha(options epmodels.APIOptions, apiID string, formFieldsRaw interface{}, tenantId string, userContext supertokens.UserContext) (interface{}, bool, error) {
+	config := options.Config.Captcha
+	if config == nil {
+		return formFieldsRaw, true, nil
+	}
+	rawFormFields, ok := formFieldsRaw.([]interface{})
+	if !ok {
+		return formFieldsRaw, true, nil
+	}
+
+	token := ""
+	otherFormFields := []interface{}{}
+	for _, rawFormField := range rawFormFields {
+		formField, ok := rawFormField.(map[string]interface{})
+		if ok && formField["id"] == config.FormFieldID {
+			token, _ = formField["value"].(string)
+			continue
+		}
+		otherFormFields = append(otherFormFields, rawFormField)
+	}
+
+	valid, err := config.Verify(token, apiID, tenantId, options.Req, userContext)
+	if err != nil {
+		return nil, false, err
+	}
+	if !valid {
+		supertokens.LogDebugMessage("verifyCaptcha: Returning CAPTCHA_FAILED_ERROR for API " + apiID)
+	}
+	return otherFormFields, valid, nil
+}
+
+func sendCaptchaFailedResponse(options epmodels.APIOptions) error {
+	return supertokens.Send200Response(options.Res, map[string]interface{}{
+		"status": "CAPTCHA_FAILED_ERROR",
+	})
+}
@@ -39,7 +39,11 @@ func GeneratePasswordResetToken(apiImplementation epmodels.APIInterface, tenantI
 		return err
 	}
 
-	formFields, err := validateFormFieldsOrThrowError(options.Config.ResetPasswordUsingTokenFeature.FormFieldsForGenerateTokenForm, formFieldsRaw["formFields"], tenantId)
+	formFields, err := validateFormFieldsOrThrowError(options.Config.ResetPasswordUsingTokenFeature.FormFieldsForGenerateTokenForm, nil, formFieldsRaw["formFields"], tenantId, userContext)
+	if err != nil {
+		return err
+	}
+	err = normaliseEmailFormField(options, formFields, tenantId, userContext)
 	if err != nil {
 		return err
 	}
@@ -17,6 +17,7 @@ package api
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/supertokens/supertokens-golang/ingredients/emaildelivery"
 	"github.com/supertokens/supertokens-golang/recipe/emailpassword/epmodels"
@@ -66,14 +67,7 @@ func MakeAPIImplementation() epmodels.APIInterface {
 			}, nil
 		}
 
-		passwordResetLink, err := GetPasswordResetLink(
-			options.AppInfo,
-			options.RecipeID,
-			response.OK.Token,
-			tenantId,
-			options.Req,
-			userContext,
-		)
+		passwordResetLink, err := options.Config.PasswordReset.GetPasswordResetLink(response.OK.Token, tenantId, options.Req, userContext)
 
 		if err != nil {
 			return epmodels.GeneratePasswordResetTokenPOSTResponse{}, err
@@ -113,10 +107,35 @@ func MakeAPIImplementation() epmodels.APIInterface {
 		}
 
 		if response.OK != nil {
+			auditEntry := supertokens.AuditEntry{
+				Action:   supertokens.AuditActionPasswordReset,
+				Result:   supertokens.AuditResultSuccess,
+				TenantId: tenantId,
+				RecipeID: options.RecipeID,
+			}
+			if response.OK.UserId != nil {
+				auditEntry.UserID = *response.OK.UserId
+				supertokens.EmitEvent(supertokens.Event{
+					Type:     supertokens.EventPasswordReset,
+					TenantId: tenantId,
+					UserID:   *response.OK.UserId,
+					Data: map[string]interface{}{
+						"recipeId": options.RecipeID,
+					},
+				}, userContext)
+			}
+			supertokens.WriteAuditLog(auditEntry, userContext)
 			return epmodels.ResetPasswordPOSTResponse{
 				OK: response.OK,
 			}, nil
 		} else {
+			supertokens.WriteAuditLog(supertokens.AuditEntry{
+				Action:   supertokens.AuditActionPasswordReset,
+				Result:   supertokens.AuditResultFailure,
+				Reason:   "RESET_PASSWORD_INVALID_TOKEN_ERROR",
+				TenantId: tenantId,
+				RecipeID: options.RecipeID,
+			}, userContext)
 			return epmodels.ResetPasswordPOSTResponse{
 				ResetPasswordInvalidTokenError: response.ResetPasswordInvalidTokenError,
 			}, nil
@@ -134,22 +153,91 @@ func MakeAPIImplementation() epmodels.APIInterface {
 			}
 		}
 
+		lockedUntil, err := getAccountLockedUntil(options.Config.AccountLockout, tenantId, email, userContext)
+		if err != nil {
+			return epmodels.SignInPOSTResponse{}, err
+		}
+		if lockedUntil != nil {
+			supertokens.WriteAuditLog(supertokens.AuditEntry{
+				Action:   supertokens.AuditActionSignIn,
+				Result:   supertokens.AuditResultFailure,
+				Reason:   "ACCOUNT_LOCKED_ERROR",
+				TenantId: tenantId,
+				RecipeID: options.RecipeID,
+				Data: map[string]interface{}{
+					"email": email,
+				},
+			}, userContext)
+			return epmodels.SignInPOSTResponse{
+				AccountLockedError: &struct{ LockedUntil time.Time }{LockedUntil: *lockedUntil},
+			}, nil
+		}
+
 		response, err := (*options.RecipeImplementation.SignIn)(email, password, tenantId, userContext)
 		if err != nil {
 			return epmodels.SignInPOSTResponse{}, err
 		}
 		if response.WrongCredentialsError != nil {
+			supertokens.WriteAuditLog(supertokens.AuditEntry{
+				Action:   supertokens.AuditActionSignIn,
+				Result:   supertokens.AuditResultFailure,
+				Reason:   "WRONG_CREDENTIALS_ERROR",
+				TenantId: tenantId,
+				RecipeID: options.RecipeID,
+				Data: map[string]interface{}{
+					"email": email,
+				},
+			}, userContext)
+			lockedUntil, err := recordFailedSignIn(options.Config.AccountLockout, tenantId, email, userContext)
+			if err != nil {
+				return epmodels.SignInPOSTResponse{}, err
+			}
+			if lockedUntil != nil {
+				return epmodels.SignInPOSTResponse{
+					AccountLockedError: &struct{ LockedUntil time.Time }{LockedUntil: *lockedUntil},
+				}, nil
+			}
 			return epmodels.SignInPOSTResponse{
 				WrongCredentialsError: &struct{}{},
 			}, nil
 		}
+		err = resetFailedSignIns(options.Config.AccountLockout, tenantId, email, userContext)
+		if err != nil {
+			return epmodels.SignInPOSTResponse{}, err
+		}
 
 		user := response.OK.User
+		if options.Config.OnSignIn != nil {
+			err = options.Config.OnSignIn(user, getFormFieldsWithoutPassword(formFields), tenantId, options.Req, userContext)
+			if err != nil {
+				return epmodels.SignInPOSTResponse{}, err
+			}
+		}
 		session, err := session.CreateNewSession(options.Req, options.Res, tenantId, user.ID, map[string]interface{}{}, map[string]interface{}{}, userContext)
 		if err != nil {
 			return epmodels.SignInPOSTResponse{}, err
 		}
 
+		supertokens.EmitEvent(supertokens.Event{
+			Type:     supertokens.EventSignIn,
+			TenantId: tenantId,
+			UserID:   user.ID,
+			Data: map[string]interface{}{
+				"recipeId":      options.RecipeID,
+				"sessionHandle": session.GetHandle(),
+			},
+		}, userContext)
+		supertokens.WriteAuditLog(supertokens.AuditEntry{
+			Action:   supertokens.AuditActionSignIn,
+			Result:   supertokens.AuditResultSuccess,
+			UserID:   user.ID,
+			TenantId: tenantId,
+			RecipeID: options.RecipeID,
+			Data: map[string]interface{}{
+				"sessionHandle": session.GetHandle(),
+			},
+		}, userContext)
+
 		return epmodels.SignInPOSTResponse{
 			OK: &struct {
 				User    epmodels.User
@@ -177,18 +265,55 @@ func MakeAPIImplementation() epmodels.APIInterface {
 			return epmodels.SignUpPOSTResponse{}, err
 		}
 		if response.EmailAlreadyExistsError != nil {
+			supertokens.WriteAuditLog(supertokens.AuditEntry{
+				Action:   supertokens.AuditActionSignUp,
+				Result:   supertokens.AuditResultFailure,
+				Reason:   "EMAIL_ALREADY_EXISTS_ERROR",
+				TenantId: tenantId,
+				RecipeID: options.RecipeID,
+				Data: map[string]interface{}{
+					"email": email,
+				},
+			}, userContext)
 			return epmodels.SignUpPOSTResponse{
 				EmailAlreadyExistsError: &struct{}{},
 			}, nil
 		}
 
 		user := response.OK.User
+		if options.Config.OnSignUp != nil {
+			err = options.Config.OnSignUp(user, getFormFieldsWithoutPassword(formFields), tenantId, options.Req, userContext)
+			if err != nil {
+				return epmodels.SignUpPOSTResponse{}, err
+			}
+		}
 
+		supertokens.SetIsSignUpInUserContext(userContext)
 		session, err := session.CreateNewSession(options.Req, options.Res, tenantId, user.ID, map[string]interface{}{}, map[string]interface{}{}, userContext)
 		if err != nil {
 			return epmodels.SignUpPOSTResponse{}, err
 		}
 
+		supertokens.EmitEvent(supertokens.Event{
+			Type:     supertokens.EventSignUp,
+			TenantId: tenantId,
+			UserID:   user.ID,
+			Data: map[string]interface{}{
+				"recipeId":      options.RecipeID,
+				"sessionHandle": session.GetHandle(),
+			},
+		}, userContext)
+		supertokens.WriteAuditLog(supertokens.AuditEntry{
+			Action:   supertokens.AuditActionSignUp,
+			Result:   supertokens.AuditResultSuccess,
+			UserID:   user.ID,
+			TenantId: tenantId,
+			RecipeID: options.RecipeID,
+			Data: map[string]interface{}{
+				"sessionHandle": session.GetHandle(),
+			},
+		}, userContext)
+
 		return epmodels.SignUpPOSTResponse{
 			OK: &struct {
 				User    epmodels.User
@@ -17,7 +17,10 @@ package api
 
 import (
 	"encoding/json"
+	"math"
+	"time"
 
+	"github.com/supertokens/supertokens-golang/recipe/emailpassword/constants"
 	"github.com/supertokens/supertokens-golang/recipe/emailpassword/epmodels"
 	"github.com/supertokens/supertokens-golang/supertokens"
 )
@@ -38,7 +41,19 @@ func SignInAPI(apiImplementation epmodels.APIInterface, tenantId string, options
 		return err
 	}
 
-	formFields, err := validateFormFieldsOrThrowError(options.Config.SignInFeature.FormFields, formFieldsRaw["formFields"], tenantId)
+	rawFormFields, captchaValid, err := verifyCaptcha(options, constants.SignInAPI, formFieldsRaw["formFields"], tenantId, userContext)
+	if err != nil {
+		return err
+	}
+	if !captchaValid {
+		return sendCaptchaFailedResponse(options)
+	}
+
+	formFields, err := validateFormFieldsOrThrowError(options.Config.SignInFeature.FormFields, nil, rawFormFields, tenantId, userContext)
+	if err != nil {
+		return err
+	}
+	err = normaliseEmailFormField(options, formFields, tenantId, userContext)
 	if err != nil {
 		return err
 	}
@@ -51,6 +66,15 @@ func SignInAPI(apiImplementation epmodels.APIInterface, tenantId string, options
 		return supertokens.Send200Response(options.Res, map[string]interface{}{
 			"status": "WRONG_CREDENTIALS_ERROR",
 		})
+	} else if result.AccountLockedError != nil {
+		retryAfter := int(math.Ceil(time.Until(result.AccountLockedError.LockedUntil).Seconds()))
+		if retryAfter < 1 {
+			retryAfter = 1
+		}
+		return supertokens.Send200Response(options.Res, map[string]interface{}{
+			"status":     "ACCOUNT_LOCKED_ERROR",
+			"retryAfter": retryAfter,
+		})
 	} else if result.OK != nil {
 		return supertokens.Send200Response(options.Res, map[string]interface{}{
 			"status": "OK",
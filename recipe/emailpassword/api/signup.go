@@ -18,6 +18,7 @@ package api
 import (
 	"encoding/json"
 
+	"github.com/supertokens/supertokens-golang/recipe/emailpassword/constants"
 	"github.com/supertokens/supertokens-golang/recipe/emailpassword/epmodels"
 	"github.com/supertokens/supertokens-golang/recipe/emailpassword/errors"
 	"github.com/supertokens/supertokens-golang/supertokens"
@@ -39,7 +40,19 @@ func SignUpAPI(apiImplementation epmodels.APIInterface, tenantId string, options
 		return err
 	}
 
-	formFields, err := validateFormFieldsOrThrowError(options.Config.SignUpFeature.FormFields, formFieldsRaw["formFields"], tenantId)
+	rawFormFields, captchaValid, err := verifyCaptcha(options, constants.SignUpAPI, formFieldsRaw["formFields"], tenantId, userContext)
+	if err != nil {
+		return err
+	}
+	if !captchaValid {
+		return sendCaptchaFailedResponse(options)
+	}
+
+	formFields, err := validateFormFieldsOrThrowError(options.Config.SignUpFeature.FormFields, options.Config.CheckPasswordPolicy, rawFormFields, tenantId, userContext)
+	if err != nil {
+		return err
+	}
+	err = normaliseEmailFormField(options, formFields, tenantId, userContext)
 	if err != nil {
 		return err
 	}
@@ -58,7 +71,7 @@ func SignUpAPI(apiImplementation epmodels.APIInterface, tenantId string, options
 			Msg: "Error in input formFields",
 			Payload: []errors.ErrorPayload{{
 				ID:       "email",
-				ErrorMsg: "This email already exists. Please sign in instead.",
+				ErrorMsg: supertokens.Translate(supertokens.MessageEmailAlreadyExists, userContext),
 			}},
 		}
 	} else if result.GeneralError != nil {
@@ -26,7 +26,7 @@ import (
 	"github.com/supertokens/supertokens-golang/supertokens"
 )
 
-func validateFormFieldsOrThrowError(configFormFields []epmodels.NormalisedFormField, formFieldsRaw interface{}, tenantId string) ([]epmodels.TypeFormField, error) {
+func validateFormFieldsOrThrowError(configFormFields []epmodels.NormalisedFormField, checkPasswordPolicy func(password string, tenantId string, userContext supertokens.UserContext) []epmodels.PasswordRuleViolation, formFieldsRaw interface{}, tenantId string, userContext supertokens.UserContext) ([]epmodels.TypeFormField, error) {
 	if formFieldsRaw == nil {
 		return nil, supertokens.BadInputError{
 			Msg: "Missing input param: formFields",
@@ -87,10 +87,39 @@ func validateFormFieldsOrThrowError(configFormFields []epmodels.NormalisedFormFi
 		}
 	}
 
-	return formFields, validateFormOrThrowError(configFormFields, formFields, tenantId)
+	return formFields, validateFormOrThrowError(configFormFields, checkPasswordPolicy, formFields, tenantId, userContext)
 }
 
-func validateFormOrThrowError(configFormFields []epmodels.NormalisedFormField, inputs []epmodels.TypeFormField, tenantId string) error {
+// normaliseEmailFormField replaces the value of the email form field with the
+// email returned by the NormaliseEmail config
+func normaliseEmailFormField(options epmodels.APIOptions, formFields []epmodels.TypeFormField, tenantId string, userContext supertokens.UserContext) error {
+	for i := range formFields {
+		if formFields[i].ID == "email" {
+			email, err := options.Config.NormaliseEmail(formFields[i].Value, tenantId, userContext)
+			if err != nil {
+				return err
+			}
+			formFields[i].Value = email
+		}
+	}
+	return nil
+}
+
+// getFormFieldsWithoutPassword returns the form fields passed to the OnSignUp
+// and OnSignIn hooks
+func getFormFieldsWithoutPassword(formFields []epmodels.TypeFormField) []epmodels.TypeFormField {
+	result := []epmodels.TypeFormField{}
+	for _, formField := range formFields {
+		if formField.ID != "password" {
+			result = append(result, formField)
+		}
+	}
+	return result
+}
+
+// validateFormOrThrowError checks the password with checkPasswordPolicy, if
+// it is not nil, so that the failed rules are part of the field error
+func validateFormOrThrowError(configFormFields []epmodels.NormalisedFormField, checkPasswordPolicy func(password string, tenantId string, userContext supertokens.UserContext) []epmodels.PasswordRuleViolation, inputs []epmodels.TypeFormField, tenantId string, userContext supertokens.UserContext) error {
 	var validationErrors []errors.ErrorPayload
 	if len(configFormFields) != len(inputs) {
 		return supertokens.BadInputError{
@@ -106,13 +135,28 @@ func validateFormOrThrowError(configFormFields []epmodels.NormalisedFormField, i
 			}
 		}
 		if input.Value == "" && !field.Optional {
-			validationErrors = append(validationErrors, errors.ErrorPayload{ID: field.ID, ErrorMsg: "Field is not optional"})
+			validationErrors = append(validationErrors, errors.ErrorPayload{ID: field.ID, ErrorMsg: supertokens.Translate(supertokens.MessageFieldNotOptional, userContext)})
 		} else {
-			err := field.Validate(input.Value, tenantId)
+			var err *string
+			var failedRules []epmodels.PasswordRuleViolation
+			if field.ID == "password" && checkPasswordPolicy != nil {
+				failedRules = checkPasswordPolicy(input.Value, tenantId, userContext)
+				if len(failedRules) > 0 {
+					err = &failedRules[0].ErrorMsg
+				}
+			} else {
+				err = field.Validate(input.Value, tenantId)
+			}
 			if err != nil {
+				policyID := supertokens.ShadowModeFormFieldPolicyPrefix + field.ID
+				if supertokens.IsPolicyInShadowMode(policyID) {
+					supertokens.ReportShadowModeViolation(policyID, *err, userContext)
+					continue
+				}
 				validationErrors = append(validationErrors, errors.ErrorPayload{
-					ID:       field.ID,
-					ErrorMsg: *err,
+					ID:          field.ID,
+					ErrorMsg:    supertokens.TranslateMessage(*err, userContext),
+					FailedRules: failedRules,
 				})
 			}
 		}
@@ -127,14 +171,21 @@ func validateFormOrThrowError(configFormFields []epmodels.NormalisedFormField, i
 }
 
 func GetPasswordResetLink(appInfo supertokens.NormalisedAppinfo, recipeID string, token string, tenantId string, request *http.Request, userContext supertokens.UserContext) (string, error) {
+	return GetPasswordResetLinkWithPath(appInfo, "/reset-password", recipeID, token, tenantId, request, userContext)
+}
+
+// GetPasswordResetLinkWithPath is like GetPasswordResetLink, for a password
+// reset page at the normalised path, relative to the WebsiteBasePath
+func GetPasswordResetLinkWithPath(appInfo supertokens.NormalisedAppinfo, path string, recipeID string, token string, tenantId string, request *http.Request, userContext supertokens.UserContext) (string, error) {
 	websiteDomain, err := appInfo.GetOrigin(request, userContext)
 	if err != nil {
 		return "", err
 	}
 	return fmt.Sprintf(
-		"%s%s/reset-password?token=%s&rid=%s&tenantId=%s",
+		"%s%s%s?token=%s&rid=%s&tenantId=%s",
 		websiteDomain.GetAsStringDangerous(),
 		appInfo.WebsiteBasePath.GetAsStringDangerous(),
+		path,
 		token,
 		recipeID,
 		tenantId,
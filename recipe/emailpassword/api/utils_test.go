@@ -0,0 +1,171 @@
+/* Copyright (c) 2023, VRAI Labs and/or its affiliates. All rights reserved.
+ *
+ * This software is licensed under the Apache License, Version 2.0 (the
+ * "License") as published by the Apache Software Foundation.
+ *
+ * You may not use this file except in compliance with the License. You may
+ * obtain a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+ * License for the specific language governing permissions and limitations
+ * under the License.
+ */
+
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/supertokens/supertokens-golang/recipe/emailpassword/epmodels"
+	"github.com/supertokens/supertokens-golang/recipe/emailpassword/errors"
+	"github.com/supertokens/supertokens-golang/recipe/session"
+	"github.com/supertokens/supertokens-golang/supertokens"
+)
+
+var shadowModeTestFormFields = []epmodels.NormalisedFormField{{
+	ID: "email",
+	Validate: func(value interface{}, tenantId string) *string {
+		return nil
+	},
+}, {
+	ID: "password",
+	Validate: func(value interface{}, tenantId string) *string {
+		msg := "Password must contain at least 8 characters"
+		return &msg
+	},
+}}
+
+var shadowModeTestInputs = []epmodels.TypeFormField{
+	{ID: "email", Value: "test@example.com"},
+	{ID: "password", Value: "short"},
+}
+
+func TestThatFormFieldInShadowModeIsReportedButNotEnforced(t *testing.T) {
+	violations := initWithShadowMode(t, []string{supertokens.ShadowModeFormFieldPolicyPrefix + "password"})
+	defer resetAll()
+
+	err := validateFormOrThrowError(shadowModeTestFormFields, nil, shadowModeTestInputs, "public", &map[string]interface{}{})
+	assert.NoError(t, err)
+	assert.Equal(t, []supertokens.ShadowModeViolation{{
+		PolicyID: supertokens.ShadowModeFormFieldPolicyPrefix + "password",
+		Reason:   "Password must contain at least 8 characters",
+	}}, *violations)
+}
+
+func TestThatFormFieldNotInShadowModeIsEnforced(t *testing.T) {
+	violations := initWithShadowMode(t, []string{})
+	defer resetAll()
+
+	err := validateFormOrThrowError(shadowModeTestFormFields, nil, shadowModeTestInputs, "public", &map[string]interface{}{})
+	assert.Equal(t, errors.FieldError{
+		Msg: "Error in input formFields",
+		Payload: []errors.ErrorPayload{{
+			ID:       "password",
+			ErrorMsg: "Password must contain at least 8 characters",
+		}},
+	}, err)
+	assert.Empty(t, *violations)
+}
+
+func TestThatFormFieldErrorsAreTranslated(t *testing.T) {
+	resetAll()
+	defer resetAll()
+	err := supertokens.Init(supertokens.TypeInput{
+		Supertokens: &supertokens.ConnectionInfo{
+			ConnectionURI: "http://localhost:8080",
+		},
+		AppInfo: supertokens.AppInfo{
+			APIDomain:     "api.supertokens.io",
+			AppName:       "SuperTokens",
+			WebsiteDomain: "supertokens.io",
+		},
+		Localization: &supertokens.LocalizationInput{
+			Translations: map[string]map[supertokens.MessageKey]string{
+				"fr": {
+					supertokens.MessageFieldNotOptional:           "Ce champ est obligatoire",
+					"Password must contain at least 8 characters": "Le mot de passe doit contenir au moins 8 caractères",
+				},
+			},
+		},
+		RecipeList: []supertokens.Recipe{
+			session.Init(nil),
+		},
+	})
+	assert.NoError(t, err)
+
+	req := httptest.NewRequest(http.MethodPost, "/auth/signup", nil)
+	req.Header.Set("Accept-Language", "fr")
+	err = validateFormOrThrowError(shadowModeTestFormFields, nil, []epmodels.TypeFormField{
+		{ID: "email", Value: ""},
+		{ID: "password", Value: "short"},
+	}, "public", supertokens.MakeDefaultUserContextFromAPI(req))
+	assert.Equal(t, errors.FieldError{
+		Msg: "Error in input formFields",
+		Payload: []errors.ErrorPayload{{
+			ID:       "email",
+			ErrorMsg: "Ce champ est obligatoire",
+		}, {
+			ID:       "password",
+			ErrorMsg: "Le mot de passe doit contenir au moins 8 caractères",
+		}},
+	}, err)
+}
+
+func TestThatPasswordPolicyFailedRulesAreReturned(t *testing.T) {
+	checkPasswordPolicy := func(password string, tenantId string, userContext supertokens.UserContext) []epmodels.PasswordRuleViolation {
+		return []epmodels.PasswordRuleViolation{
+			{Rule: "MIN_LENGTH", ErrorMsg: "Password must contain at least 12 characters"},
+			{Rule: "SYMBOL", ErrorMsg: "Password must contain at least one symbol"},
+		}
+	}
+	err := validateFormOrThrowError(shadowModeTestFormFields, checkPasswordPolicy, shadowModeTestInputs, "public", &map[string]interface{}{})
+	assert.Equal(t, errors.FieldError{
+		Msg: "Error in input formFields",
+		Payload: []errors.ErrorPayload{{
+			ID:       "password",
+			ErrorMsg: "Password must contain at least 12 characters",
+			FailedRules: []epmodels.PasswordRuleViolation{
+				{Rule: "MIN_LENGTH", ErrorMsg: "Password must contain at least 12 characters"},
+				{Rule: "SYMBOL", ErrorMsg: "Password must contain at least one symbol"},
+			},
+		}},
+	}, err)
+}
+
+func resetAll() {
+	supertokens.ResetForTest()
+	session.ResetForTest()
+}
+
+func initWithShadowMode(t *testing.T, policies []string) *[]supertokens.ShadowModeViolation {
+	violations := []supertokens.ShadowModeViolation{}
+	resetAll()
+	err := supertokens.Init(supertokens.TypeInput{
+		Supertokens: &supertokens.ConnectionInfo{
+			ConnectionURI: "http://localhost:8080",
+		},
+		AppInfo: supertokens.AppInfo{
+			APIDomain:     "api.supertokens.io",
+			AppName:       "SuperTokens",
+			WebsiteDomain: "supertokens.io",
+		},
+		ShadowMode: &supertokens.ShadowModeInput{
+			Policies: policies,
+			OnViolation: func(violation supertokens.ShadowModeViolation, userContext supertokens.UserContext) {
+				violations = append(violations, violation)
+			},
+		},
+		RecipeList: []supertokens.Recipe{
+			session.Init(nil),
+		},
+	})
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	return &violations
+}
@@ -0,0 +1,82 @@
+/* Copyright (c) 2023, VRAI Labs and/or its affiliates. All rights reserved.
+ *
+ * This software is licensed under the Apache License, Version 2.0 (the
+ * "License") as published by the Apache Software Foundation.
+ *
+ * You may not use this file except in compliance with the License. You may
+ * obtain a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+ * License for the specific language governing permissions and limitations
+ * under the License.
+ */
+
+package emailpassword
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/supertokens/supertokens-golang/recipe/emailpassword/api"
+	"github.com/supertokens/supertokens-golang/recipe/emailpassword/epmodels"
+	"github.com/supertokens/supertokens-golang/supertokens"
+)
+
+func TestOnSignUpAndOnSignInHooks(t *testing.T) {
+	errProvisioning := errors.New("could not provision the user")
+	type hookCall struct {
+		hook       string
+		user       epmodels.User
+		formFields []epmodels.TypeFormField
+		req        *http.Request
+	}
+	calls := []hookCall{}
+	config, err := validateAndNormaliseUserInput(nil, supertokens.NormalisedAppinfo{}, &epmodels.TypeInput{
+		OnSignUp: func(user epmodels.User, formFields []epmodels.TypeFormField, tenantId string, req *http.Request, userContext supertokens.UserContext) error {
+			calls = append(calls, hookCall{"signUp", user, formFields, req})
+			return errProvisioning
+		},
+		OnSignIn: func(user epmodels.User, formFields []epmodels.TypeFormField, tenantId string, req *http.Request, userContext supertokens.UserContext) error {
+			calls = append(calls, hookCall{"signIn", user, formFields, req})
+			return errProvisioning
+		},
+	})
+	assert.NoError(t, err)
+
+	user := epmodels.User{ID: "user1", Email: "test@example.com"}
+	signUp := func(email string, password string, tenantId string, userContext supertokens.UserContext) (epmodels.SignUpResponse, error) {
+		return epmodels.SignUpResponse{OK: &struct{ User epmodels.User }{User: user}}, nil
+	}
+	signIn := func(email string, password string, tenantId string, userContext supertokens.UserContext) (epmodels.SignInResponse, error) {
+		return epmodels.SignInResponse{OK: &struct{ User epmodels.User }{User: user}}, nil
+	}
+	req := httptest.NewRequest(http.MethodPost, "/auth/signup", nil)
+	options := epmodels.APIOptions{
+		Config:               config,
+		RecipeImplementation: epmodels.RecipeInterface{SignUp: &signUp, SignIn: &signIn},
+		Req:                  req,
+	}
+	formFields := []epmodels.TypeFormField{
+		{ID: "email", Value: "test@example.com"},
+		{ID: "password", Value: "validPass123"},
+		{ID: "name", Value: "Jane"},
+	}
+	apiImplementation := api.MakeAPIImplementation()
+	userContext := &map[string]interface{}{}
+
+	// errors of the hooks fail the request before the session is created
+	_, err = (*apiImplementation.SignUpPOST)(formFields, "public", options, userContext)
+	assert.Equal(t, errProvisioning, err)
+	_, err = (*apiImplementation.SignInPOST)(formFields[:2], "public", options, userContext)
+	assert.Equal(t, errProvisioning, err)
+
+	assert.Equal(t, []hookCall{
+		{"signUp", user, []epmodels.TypeFormField{{ID: "email", Value: "test@example.com"}, {ID: "name", Value: "Jane"}}, req},
+		{"signIn", user, []epmodels.TypeFormField{{ID: "email", Value: "test@example.com"}}, req},
+	}, calls)
+}
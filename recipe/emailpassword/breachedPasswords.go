@@ -0,0 +1,183 @@
+/* Copyright (c) 2023, VRAI Labs and/or its affiliates. All rights reserved.
+ *
+ * This software is licensed under the Apache License, Version 2.0 (the
+ * "License") as published by the Apache Software Foundation.
+ *
+ * You may not use this file except in compliance with the License. You may
+ * obtain a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+ * License for the specific language governing permissions and limitations
+ * under the License.
+ */
+
+package emailpassword
+
+import (
+	"bufio"
+	"context"
+	"crypto/sha1"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/supertokens/supertokens-golang/recipe/emailpassword/epmodels"
+	"github.com/supertokens/supertokens-golang/supertokens"
+)
+
+const (
+	// BreachedPasswordAction_REJECT fails sign up and password changes that
+	// use a breached password
+	BreachedPasswordAction_REJECT = "REJECT"
+	// BreachedPasswordAction_WARN only calls OnBreachedPassword
+	BreachedPasswordAction_WARN = "WARN"
+
+	// PasswordRule_BREACHED is the rule of breached passwords
+	PasswordRule_BREACHED = "BREACHED"
+	// PasswordRule_VALIDATE is the rule of the Validate function of the
+	// password form field, when it is used with BreachedPasswordCheck
+	PasswordRule_VALIDATE = "VALIDATE"
+)
+
+const (
+	defaultHaveIBeenPwnedAPIURL  = "https://api.pwnedpasswords.com/range/"
+	defaultHaveIBeenPwnedTimeout = 5 * time.Second
+)
+
+type HaveIBeenPwnedSettings struct {
+	// APIURL defaults to the Pwned Passwords range API
+	APIURL string
+	// Timeout of requests to the API. Defaults to 5 seconds.
+	Timeout time.Duration
+}
+
+// MakeHaveIBeenPwnedChecker returns a breached password checker using the
+// range API of Have I Been Pwned. Only the first 5 characters of the SHA-1
+// hash of the password are sent (k-anonymity), and the response is padded so
+// that its size does not reveal anything either.
+func MakeHaveIBeenPwnedChecker(settings HaveIBeenPwnedSettings) *epmodels.BreachedPasswordChecker {
+	apiURL := settings.APIURL
+	if apiURL == "" {
+		apiURL = defaultHaveIBeenPwnedAPIURL
+	}
+	timeout := settings.Timeout
+	if timeout == 0 {
+		timeout = defaultHaveIBeenPwnedTimeout
+	}
+
+	getBreachCount := func(password string, userContext supertokens.UserContext) (int, error) {
+		hash := sha1.Sum([]byte(password))
+		hexHash := strings.ToUpper(hex.EncodeToString(hash[:]))
+		prefix, suffix := hexHash[:5], hexHash[5:]
+
+		ctx := context.Background()
+		if request := supertokens.GetRequestFromUserContext(userContext); request != nil {
+			ctx = request.Context()
+		}
+		ctx, cancel := context.WithTimeout(ctx, timeout)
+		defer cancel()
+
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, apiURL+prefix, nil)
+		if err != nil {
+			return 0, err
+		}
+		req.Header.Set("Add-Padding", "true")
+		resp, err := supertokens.GetEgressHTTPClient().Do(req)
+		if err != nil {
+			return 0, err
+		}
+		defer resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			return 0, fmt.Errorf("breached password check resulted in %d status", resp.StatusCode)
+		}
+
+		scanner := bufio.NewScanner(resp.Body)
+		for scanner.Scan() {
+			line := strings.TrimSpace(scanner.Text())
+			parts := strings.SplitN(line, ":", 2)
+			if len(parts) != 2 || parts[0] != suffix {
+				continue
+			}
+			// padding entries have a count of 0
+			return strconv.Atoi(parts[1])
+		}
+		return 0, scanner.Err()
+	}
+
+	return &epmodels.BreachedPasswordChecker{
+		GetBreachCount: &getBreachCount,
+	}
+}
+
+func normaliseBreachedPasswordCheckInput(config *epmodels.BreachedPasswordCheckInput) (*epmodels.NormalisedBreachedPasswordCheckConfig, error) {
+	if config == nil {
+		return nil, nil
+	}
+	result := &epmodels.NormalisedBreachedPasswordCheckConfig{
+		Checker:            *MakeHaveIBeenPwnedChecker(HaveIBeenPwnedSettings{}),
+		Action:             BreachedPasswordAction_REJECT,
+		OnBreachedPassword: config.OnBreachedPassword,
+	}
+	if config.Checker != nil {
+		if config.Checker.GetBreachCount == nil {
+			return nil, errors.New("breached password checker must implement GetBreachCount")
+		}
+		result.Checker = *config.Checker
+	}
+	if config.Action != nil {
+		if *config.Action != BreachedPasswordAction_REJECT && *config.Action != BreachedPasswordAction_WARN {
+			return nil, errors.New("BreachedPasswordCheck Action must be REJECT or WARN")
+		}
+		result.Action = *config.Action
+	}
+	return result, nil
+}
+
+// passwordFormFieldChecker adapts the Validate function of the password form
+// field to a password policy checker, for BreachedPasswordCheck without
+// PasswordPolicy
+func passwordFormFieldChecker(validate func(value interface{}, tenantId string) *string) func(password string, tenantId string, userContext supertokens.UserContext) []epmodels.PasswordRuleViolation {
+	return func(password string, tenantId string, userContext supertokens.UserContext) []epmodels.PasswordRuleViolation {
+		message := validate(password, tenantId)
+		if message == nil {
+			return []epmodels.PasswordRuleViolation{}
+		}
+		return []epmodels.PasswordRuleViolation{{Rule: PasswordRule_VALIDATE, ErrorMsg: supertokens.TranslateMessage(*message, userContext)}}
+	}
+}
+
+// withBreachedPasswordCheck checks passwords that follow the other rules
+// against data breaches. The check is skipped if the checker fails, so that
+// an outage of the breach database does not prevent signing up.
+func withBreachedPasswordCheck(config *epmodels.NormalisedBreachedPasswordCheckConfig, checkPasswordPolicy func(password string, tenantId string, userContext supertokens.UserContext) []epmodels.PasswordRuleViolation) func(password string, tenantId string, userContext supertokens.UserContext) []epmodels.PasswordRuleViolation {
+	return func(password string, tenantId string, userContext supertokens.UserContext) []epmodels.PasswordRuleViolation {
+		violations := checkPasswordPolicy(password, tenantId, userContext)
+		if len(violations) > 0 {
+			return violations
+		}
+		breachCount, err := (*config.Checker.GetBreachCount)(password, userContext)
+		if err != nil {
+			supertokens.LogDebugMessage("withBreachedPasswordCheck: Skipping the breached password check: " + err.Error())
+			return violations
+		}
+		if breachCount == 0 {
+			return violations
+		}
+		if config.OnBreachedPassword != nil {
+			config.OnBreachedPassword(breachCount, tenantId, userContext)
+		}
+		if config.Action == BreachedPasswordAction_WARN {
+			return violations
+		}
+		return append(violations, epmodels.PasswordRuleViolation{
+			Rule:     PasswordRule_BREACHED,
+			ErrorMsg: supertokens.Translate(supertokens.MessagePasswordBreached, userContext),
+		})
+	}
+}
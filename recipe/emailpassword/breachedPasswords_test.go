@@ -0,0 +1,96 @@
+/* Copyright (c) 2023, VRAI Labs and/or its affiliates. All rights reserved.
+ *
+ * This software is licensed under the Apache License, Version 2.0 (the
+ * "License") as published by the Apache Software Foundation.
+ *
+ * You may not use this file except in compliance with the License. You may
+ * obtain a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+ * License for the specific language governing permissions and limitations
+ * under the License.
+ */
+
+package emailpassword
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/supertokens/supertokens-golang/recipe/emailpassword/epmodels"
+	"github.com/supertokens/supertokens-golang/supertokens"
+)
+
+func TestHaveIBeenPwnedChecker(t *testing.T) {
+	// SHA-1 of "password1" is E38AD214943DAAD1D64C102FAEC29DE4AFE9DA3D
+	requestedPaths := []string{}
+	testServer := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		requestedPaths = append(requestedPaths, r.URL.Path)
+		assert.Equal(t, "true", r.Header.Get("Add-Padding"))
+		fmt.Fprint(rw, "0018A45C4D1DEF81644B54AB7F969B88D65:0\r\n214943DAAD1D64C102FAEC29DE4AFE9DA3D:2413945\r\n")
+	}))
+	defer testServer.Close()
+
+	checker := MakeHaveIBeenPwnedChecker(HaveIBeenPwnedSettings{APIURL: testServer.URL + "/range/"})
+	userContext := &map[string]interface{}{}
+	count, err := (*checker.GetBreachCount)("password1", userContext)
+	assert.NoError(t, err)
+	assert.Equal(t, 2413945, count)
+	assert.Equal(t, []string{"/range/E38AD"}, requestedPaths)
+
+	count, err = (*checker.GetBreachCount)("a password that was never breached", userContext)
+	assert.NoError(t, err)
+	assert.Equal(t, 0, count)
+}
+
+func TestBreachedPasswordCheck(t *testing.T) {
+	breachCounts := map[string]int{"password1": 100}
+	var checkerErr error
+	getBreachCount := func(password string, userContext supertokens.UserContext) (int, error) {
+		return breachCounts[password], checkerErr
+	}
+	checker := &epmodels.BreachedPasswordChecker{GetBreachCount: &getBreachCount}
+	reported := []int{}
+	onBreachedPassword := func(breachCount int, tenantId string, userContext supertokens.UserContext) {
+		reported = append(reported, breachCount)
+	}
+	userContext := &map[string]interface{}{}
+
+	invalidAction := "BLOCK"
+	_, err := validateAndNormaliseUserInput(nil, supertokens.NormalisedAppinfo{}, &epmodels.TypeInput{
+		BreachedPasswordCheck: &epmodels.BreachedPasswordCheckInput{Checker: checker, Action: &invalidAction},
+	})
+	assert.Error(t, err)
+
+	// without a PasswordPolicy, the password form field validator is used
+	config, err := validateAndNormaliseUserInput(nil, supertokens.NormalisedAppinfo{}, &epmodels.TypeInput{
+		BreachedPasswordCheck: &epmodels.BreachedPasswordCheckInput{Checker: checker, OnBreachedPassword: onBreachedPassword},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{PasswordRule_VALIDATE}, getFailedPasswordRules(config.CheckPasswordPolicy("short", "public", userContext)))
+	assert.Equal(t, []string{PasswordRule_BREACHED}, getFailedPasswordRules(config.CheckPasswordPolicy("password1", "public", userContext)))
+	assert.Empty(t, config.CheckPasswordPolicy("not-breached-42", "public", userContext))
+	assert.Equal(t, []int{100}, reported)
+
+	// the check is skipped if the checker fails
+	checkerErr = errors.New("breach database unavailable")
+	assert.Empty(t, config.CheckPasswordPolicy("password1", "public", userContext))
+	checkerErr = nil
+
+	warn := BreachedPasswordAction_WARN
+	config, err = validateAndNormaliseUserInput(nil, supertokens.NormalisedAppinfo{}, &epmodels.TypeInput{
+		PasswordPolicy:        &epmodels.PasswordPolicy{RequireSymbol: true},
+		BreachedPasswordCheck: &epmodels.BreachedPasswordCheckInput{Checker: checker, Action: &warn, OnBreachedPassword: onBreachedPassword},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{PasswordRule_SYMBOL}, getFailedPasswordRules(config.CheckPasswordPolicy("password1", "public", userContext)))
+	breachCounts["password1!"] = 5
+	assert.Empty(t, config.CheckPasswordPolicy("password1!", "public", userContext))
+	assert.Equal(t, []int{100, 5}, reported)
+}
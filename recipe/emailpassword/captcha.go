@@ -0,0 +1,132 @@
+/* Copyright (c) 2023, VRAI Labs and/or its affiliates. All rights reserved.
+ *
+ * This software is licensed under the Apache License, Version 2.0 (the
+ * "License") as published by the Apache Software Foundation.
+ *
+ * You may not use this file except in compliance with the License. You may
+ * obtain a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+ * License for the specific language governing permissions and limitations
+ * under the License.
+ */
+
+package emailpassword
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/supertokens/supertokens-golang/recipe/emailpassword/epmodels"
+	"github.com/supertokens/supertokens-golang/supertokens"
+)
+
+const (
+	// ReCaptchaVerifyURL is the verification API of Google reCAPTCHA
+	ReCaptchaVerifyURL = "https://www.google.com/recaptcha/api/siteverify"
+	// HCaptchaVerifyURL is the verification API of hCaptcha
+	HCaptchaVerifyURL = "https://api.hcaptcha.com/siteverify"
+	// TurnstileVerifyURL is the verification API of Cloudflare Turnstile
+	TurnstileVerifyURL = "https://challenges.cloudflare.com/turnstile/v0/siteverify"
+)
+
+const defaultCaptchaVerifierTimeout = 5 * time.Second
+
+func normaliseCaptchaInput(config *epmodels.CaptchaInput) (*epmodels.NormalisedCaptchaConfig, error) {
+	if config == nil {
+		return nil, nil
+	}
+	if config.Verify == nil {
+		return nil, errors.New("Captcha needs Verify to be set")
+	}
+	result := &epmodels.NormalisedCaptchaConfig{
+		FormFieldID: "captchaToken",
+		Verify:      config.Verify,
+	}
+	if config.FormFieldID != nil {
+		if *config.FormFieldID == "" || *config.FormFieldID == "email" || *config.FormFieldID == "password" {
+			return nil, errors.New("Captcha FormFieldID must not be empty, email or password")
+		}
+		result.FormFieldID = *config.FormFieldID
+	}
+	return result, nil
+}
+
+type CaptchaVerifierSettings struct {
+	// VerifyURL is the verification API of the captcha service, e.g.
+	// ReCaptchaVerifyURL, HCaptchaVerifyURL or TurnstileVerifyURL
+	VerifyURL string
+	// Secret is the secret key of the site
+	Secret string
+	// MinScore rejects captchas with a lower score, for services that score
+	// them, such as reCAPTCHA v3
+	MinScore *float64
+	// Timeout of requests to the API. Defaults to 5 seconds.
+	Timeout time.Duration
+}
+
+// MakeCaptchaVerifier returns a Verify function for captcha services with a
+// siteverify API, which reCAPTCHA, hCaptcha and Turnstile share. Missing
+// tokens are not valid.
+func MakeCaptchaVerifier(settings CaptchaVerifierSettings) func(token string, apiID string, tenantId string, req *http.Request, userContext supertokens.UserContext) (bool, error) {
+	timeout := settings.Timeout
+	if timeout == 0 {
+		timeout = defaultCaptchaVerifierTimeout
+	}
+
+	return func(token string, apiID string, tenantId string, req *http.Request, userContext supertokens.UserContext) (bool, error) {
+		if token == "" {
+			return false, nil
+		}
+		form := url.Values{}
+		form.Set("secret", settings.Secret)
+		form.Set("response", token)
+		ctx := context.Background()
+		if req != nil {
+			ctx = req.Context()
+			if ip := supertokens.GetClientIP(req, userContext); ip != "" {
+				form.Set("remoteip", ip)
+			}
+		}
+		ctx, cancel := context.WithTimeout(ctx, timeout)
+		defer cancel()
+
+		verifyReq, err := http.NewRequestWithContext(ctx, http.MethodPost, settings.VerifyURL, strings.NewReader(form.Encode()))
+		if err != nil {
+			return false, err
+		}
+		verifyReq.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		resp, err := supertokens.GetEgressHTTPClient().Do(verifyReq)
+		if err != nil {
+			return false, err
+		}
+		defer resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			return false, fmt.Errorf("captcha verification resulted in %d status", resp.StatusCode)
+		}
+
+		var result struct {
+			Success bool     `json:"success"`
+			Score   *float64 `json:"score"`
+		}
+		err = json.NewDecoder(resp.Body).Decode(&result)
+		if err != nil {
+			return false, err
+		}
+		if !result.Success {
+			return false, nil
+		}
+		if settings.MinScore != nil && (result.Score == nil || *result.Score < *settings.MinScore) {
+			return false, nil
+		}
+		return true, nil
+	}
+}
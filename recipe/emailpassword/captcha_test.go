@@ -0,0 +1,112 @@
+/* Copyright (c) 2023, VRAI Labs and/or its affiliates. All rights reserved.
+ *
+ * This software is licensed under the Apache License, Version 2.0 (the
+ * "License") as published by the Apache Software Foundation.
+ *
+ * You may not use this file except in compliance with the License. You may
+ * obtain a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+ * License for the specific language governing permissions and limitations
+ * under the License.
+ */
+
+package emailpassword
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/supertokens/supertokens-golang/recipe/emailpassword/api"
+	"github.com/supertokens/supertokens-golang/recipe/emailpassword/constants"
+	"github.com/supertokens/supertokens-golang/recipe/emailpassword/epmodels"
+	"github.com/supertokens/supertokens-golang/supertokens"
+)
+
+func TestCaptchaConfigValidation(t *testing.T) {
+	_, err := normaliseCaptchaInput(&epmodels.CaptchaInput{})
+	assert.Error(t, err)
+
+	verify := func(token string, apiID string, tenantId string, req *http.Request, userContext supertokens.UserContext) (bool, error) {
+		return true, nil
+	}
+	formFieldID := "password"
+	_, err = normaliseCaptchaInput(&epmodels.CaptchaInput{FormFieldID: &formFieldID, Verify: verify})
+	assert.Error(t, err)
+
+	config, err := normaliseCaptchaInput(&epmodels.CaptchaInput{Verify: verify})
+	assert.NoError(t, err)
+	assert.Equal(t, "captchaToken", config.FormFieldID)
+}
+
+func TestCaptchaVerifier(t *testing.T) {
+	testServer := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		r.ParseForm()
+		assert.Equal(t, "secret", r.PostForm.Get("secret"))
+		switch r.PostForm.Get("response") {
+		case "valid":
+			fmt.Fprint(rw, `{"success": true, "score": 0.9}`)
+		case "low-score":
+			fmt.Fprint(rw, `{"success": true, "score": 0.1}`)
+		default:
+			fmt.Fprint(rw, `{"success": false, "error-codes": ["invalid-input-response"]}`)
+		}
+	}))
+	defer testServer.Close()
+
+	minScore := 0.5
+	verify := MakeCaptchaVerifier(CaptchaVerifierSettings{VerifyURL: testServer.URL, Secret: "secret", MinScore: &minScore})
+	userContext := &map[string]interface{}{}
+	for token, expected := range map[string]bool{"valid": true, "low-score": false, "invalid": false, "": false} {
+		valid, err := verify(token, constants.SignInAPI, "public", nil, userContext)
+		assert.NoError(t, err)
+		assert.Equal(t, expected, valid, token)
+	}
+}
+
+func TestCaptchaOfSignIn(t *testing.T) {
+	verifiedAPIs := []string{}
+	config, err := validateAndNormaliseUserInput(nil, supertokens.NormalisedAppinfo{}, &epmodels.TypeInput{
+		Captcha: &epmodels.CaptchaInput{
+			Verify: func(token string, apiID string, tenantId string, req *http.Request, userContext supertokens.UserContext) (bool, error) {
+				verifiedAPIs = append(verifiedAPIs, apiID)
+				return token == "valid", nil
+			},
+		},
+	})
+	assert.NoError(t, err)
+
+	signInCalls := 0
+	signInPOST := func(formFields []epmodels.TypeFormField, tenantId string, options epmodels.APIOptions, userContext supertokens.UserContext) (epmodels.SignInPOSTResponse, error) {
+		signInCalls++
+		assert.Len(t, formFields, 2)
+		return epmodels.SignInPOSTResponse{WrongCredentialsError: &struct{}{}}, nil
+	}
+	signIn := func(captchaToken string) map[string]interface{} {
+		body := `{"formFields": [{"id": "email", "value": "test@example.com"}, {"id": "password", "value": "validPass123"}, {"id": "captchaToken", "value": "` + captchaToken + `"}]}`
+		res := httptest.NewRecorder()
+		options := epmodels.APIOptions{
+			Config: config,
+			Req:    httptest.NewRequest(http.MethodPost, "/auth/signin", strings.NewReader(body)),
+			Res:    res,
+		}
+		err := api.SignInAPI(epmodels.APIInterface{SignInPOST: &signInPOST}, "public", options, &map[string]interface{}{})
+		assert.NoError(t, err)
+		var result map[string]interface{}
+		json.Unmarshal(res.Body.Bytes(), &result)
+		return result
+	}
+
+	assert.Equal(t, "CAPTCHA_FAILED_ERROR", signIn("invalid")["status"])
+	assert.Equal(t, 0, signInCalls)
+	assert.Equal(t, "WRONG_CREDENTIALS_ERROR", signIn("valid")["status"])
+	assert.Equal(t, 1, signInCalls)
+	assert.Equal(t, []string{constants.SignInAPI, constants.SignInAPI}, verifiedAPIs)
+}
@@ -0,0 +1,63 @@
+/* Copyright (c) 2023, VRAI Labs and/or its affiliates. All rights reserved.
+ *
+ * This software is licensed under the Apache License, Version 2.0 (the
+ * "License") as published by the Apache Software Foundation.
+ *
+ * You may not use this file except in compliance with the License. You may
+ * obtain a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+ * License for the specific language governing permissions and limitations
+ * under the License.
+ */
+
+package emailpassword
+
+import (
+	"strings"
+
+	"github.com/supertokens/supertokens-golang/supertokens"
+)
+
+var gmailDomains = map[string]bool{
+	"gmail.com":      true,
+	"googlemail.com": true,
+}
+
+type EmailNormaliserSettings struct {
+	// Lowercase makes emails lowercase
+	Lowercase bool
+	// RemovePlusTags removes the +tag of the local part of emails, e.g.
+	// jane+news@example.com becomes jane@example.com
+	RemovePlusTags bool
+	// RemoveGmailDots removes the dots of the local part of Gmail addresses,
+	// which Gmail ignores
+	RemoveGmailDots bool
+}
+
+// MakeEmailNormaliser returns a NormaliseEmail function for the config of the
+// recipe. Emails without an @ are not changed.
+func MakeEmailNormaliser(settings EmailNormaliserSettings) func(email string, tenantId string, userContext supertokens.UserContext) (string, error) {
+	return func(email string, tenantId string, userContext supertokens.UserContext) (string, error) {
+		at := strings.LastIndex(email, "@")
+		if at == -1 {
+			return email, nil
+		}
+		localPart, domain := email[:at], email[at+1:]
+		if settings.Lowercase {
+			localPart = strings.ToLower(localPart)
+			domain = strings.ToLower(domain)
+		}
+		if settings.RemovePlusTags {
+			if plus := strings.Index(localPart, "+"); plus > 0 {
+				localPart = localPart[:plus]
+			}
+		}
+		if settings.RemoveGmailDots && gmailDomains[strings.ToLower(domain)] {
+			localPart = strings.ReplaceAll(localPart, ".", "")
+		}
+		return localPart + "@" + domain, nil
+	}
+}
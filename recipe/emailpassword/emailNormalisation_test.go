@@ -0,0 +1,102 @@
+/* Copyright (c) 2023, VRAI Labs and/or its affiliates. All rights reserved.
+ *
+ * This software is licensed under the Apache License, Version 2.0 (the
+ * "License") as published by the Apache Software Foundation.
+ *
+ * You may not use this file except in compliance with the License. You may
+ * obtain a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+ * License for the specific language governing permissions and limitations
+ * under the License.
+ */
+
+package emailpassword
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/supertokens/supertokens-golang/recipe/emailpassword/epmodels"
+	"github.com/supertokens/supertokens-golang/supertokens"
+)
+
+func TestEmailNormaliser(t *testing.T) {
+	normaliseEmail := MakeEmailNormaliser(EmailNormaliserSettings{Lowercase: true, RemovePlusTags: true, RemoveGmailDots: true})
+	userContext := &map[string]interface{}{}
+	for email, expected := range map[string]string{
+		"Jane.Doe+news@GMail.com":   "janedoe@gmail.com",
+		"jane.doe+news@example.com": "jane.doe@example.com",
+		"+tag@example.com":          "+tag@example.com",
+		"not-an-email":              "not-an-email",
+	} {
+		normalised, err := normaliseEmail(email, "public", userContext)
+		assert.NoError(t, err)
+		assert.Equal(t, expected, normalised)
+		// normalising a normalised email does not change it
+		again, err := normaliseEmail(normalised, "public", userContext)
+		assert.NoError(t, err)
+		assert.Equal(t, normalised, again)
+	}
+
+	normaliseEmail = MakeEmailNormaliser(EmailNormaliserSettings{})
+	normalised, err := normaliseEmail("Jane.Doe+news@GMail.com", "public", userContext)
+	assert.NoError(t, err)
+	assert.Equal(t, "Jane.Doe+news@GMail.com", normalised)
+}
+
+func TestNormaliseEmailOfRecipeFunctions(t *testing.T) {
+	signedUp := []interface{}{}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/apiversion", func(rw http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(rw).Encode(map[string]interface{}{"versions": []string{"3.0"}})
+	})
+	mux.HandleFunc("/public/recipe/signup", func(rw http.ResponseWriter, r *http.Request) {
+		var body map[string]interface{}
+		json.NewDecoder(r.Body).Decode(&body)
+		signedUp = append(signedUp, body["email"])
+		json.NewEncoder(rw).Encode(map[string]interface{}{
+			"status": "OK",
+			"user":   map[string]interface{}{"id": "user1", "email": body["email"], "timeJoined": 1700000000000},
+		})
+	})
+	mux.HandleFunc("/public/recipe/user", func(rw http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(rw).Encode(map[string]interface{}{
+			"status": "OK",
+			"user":   map[string]interface{}{"id": "user1", "email": r.URL.Query().Get("email"), "timeJoined": 1700000000000},
+		})
+	})
+	testServer := httptest.NewServer(mux)
+	defer testServer.Close()
+
+	resetAll()
+	defer resetAll()
+	err := supertokens.Init(supertokens.TypeInput{
+		Supertokens: &supertokens.ConnectionInfo{
+			ConnectionURI: testServer.URL,
+		},
+		AppInfo: supertokens.AppInfo{
+			AppName:       "SuperTokens",
+			WebsiteDomain: "supertokens.io",
+			APIDomain:     "api.supertokens.io",
+		},
+		RecipeList: []supertokens.Recipe{Init(&epmodels.TypeInput{
+			NormaliseEmail: MakeEmailNormaliser(EmailNormaliserSettings{Lowercase: true, RemoveGmailDots: true}),
+		})},
+	})
+	assert.NoError(t, err)
+
+	response, err := SignUp("public", "Jane.Doe@gmail.com", "validPass123")
+	assert.NoError(t, err)
+	assert.Equal(t, "janedoe@gmail.com", response.OK.User.Email)
+	assert.Equal(t, []interface{}{"janedoe@gmail.com"}, signedUp)
+
+	user, err := GetUserByEmail("public", "JANE.DOE@gmail.com")
+	assert.NoError(t, err)
+	assert.Equal(t, "janedoe@gmail.com", user.Email)
+}
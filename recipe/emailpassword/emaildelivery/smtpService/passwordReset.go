@@ -933,23 +933,24 @@ const passwordResetTemplate = `<!doctype html>
 
 </html>`
 
-func getPasswordResetEmailContent(input emaildelivery.PasswordResetType) (emaildelivery.EmailContent, error) {
+func getPasswordResetEmailContent(input emaildelivery.PasswordResetType, userContext supertokens.UserContext) (emaildelivery.EmailContent, error) {
 	stInstance, err := supertokens.GetInstanceOrThrowError()
 	if err != nil {
 		panic("Please call supertokens.Init function before using the Middleware")
 	}
-	bodyHtml := getPasswordResetEmailHTML(stInstance.AppInfo.AppName, input.User.Email, input.PasswordResetLink)
+	subject := supertokens.Translate(supertokens.MessagePasswordResetEmailSubject, userContext)
+	bodyHtml := getPasswordResetEmailHTML(stInstance.AppInfo.AppName, subject, input.User.Email, input.PasswordResetLink)
 	return emaildelivery.EmailContent{
 		Body:    bodyHtml,
 		IsHtml:  true,
-		Subject: "Password reset instructions",
+		Subject: subject,
 		ToEmail: input.User.Email,
 	}, nil
 }
 
-func getPasswordResetEmailHTML(appName string, email string, resetLink string) string {
+func getPasswordResetEmailHTML(appName string, subject string, email string, resetLink string) string {
 	emailBody := passwordResetTemplate
-	emailBody = strings.Replace(emailBody, "*|MC:SUBJECT|*", "Password reset instructions", -1)
+	emailBody = strings.Replace(emailBody, "*|MC:SUBJECT|*", subject, -1)
 	emailBody = strings.Replace(emailBody, "${appname}", appName, -1)
 	emailBody = strings.Replace(emailBody, "${resetLink}", resetLink, -1)
 	emailBody = strings.Replace(emailBody, "${toEmail}", email, -1)
@@ -30,7 +30,7 @@ func MakeServiceImplementation(settings emaildelivery.SMTPSettings) emaildeliver
 
 	getContent := func(input emaildelivery.EmailType, userContext supertokens.UserContext) (emaildelivery.EmailContent, error) {
 		if input.PasswordReset != nil {
-			return getPasswordResetEmailContent(*input.PasswordReset)
+			return getPasswordResetEmailContent(*input.PasswordReset, userContext)
 		} else {
 			return emaildelivery.EmailContent{}, errors.New("should never come here")
 		}
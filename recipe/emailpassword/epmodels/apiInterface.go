@@ -17,6 +17,7 @@ package epmodels
 
 import (
 	"net/http"
+	"time"
 
 	"github.com/supertokens/supertokens-golang/ingredients/emaildelivery"
 	"github.com/supertokens/supertokens-golang/recipe/session/sessmodels"
@@ -65,6 +66,7 @@ type SignInPOSTResponse struct {
 		Session sessmodels.SessionContainer
 	}
 	WrongCredentialsError *struct{}
+	AccountLockedError    *struct{ LockedUntil time.Time }
 	GeneralError          *supertokens.GeneralErrorResponse
 }
 
@@ -16,7 +16,11 @@
 package epmodels
 
 import (
+	"net/http"
+	"time"
+
 	"github.com/supertokens/supertokens-golang/ingredients/emaildelivery"
+	"github.com/supertokens/supertokens-golang/supertokens"
 )
 
 type TypeNormalisedInput struct {
@@ -25,11 +29,36 @@ type TypeNormalisedInput struct {
 	ResetPasswordUsingTokenFeature TypeNormalisedInputResetPasswordUsingTokenFeature
 	Override                       OverrideStruct
 	GetEmailDeliveryConfig         func(recipeImpl RecipeInterface) emaildelivery.TypeInputWithService
+	// CheckPasswordPolicy is nil if neither PasswordPolicy nor
+	// BreachedPasswordCheck are set
+	CheckPasswordPolicy func(password string, tenantId string, userContext supertokens.UserContext) []PasswordRuleViolation
+	PasswordReset       NormalisedPasswordResetConfig
+	// AccountLockout is nil if it is not enabled
+	AccountLockout *NormalisedAccountLockoutConfig
+	// Captcha is nil if it is not enabled
+	Captcha        *NormalisedCaptchaConfig
+	NormaliseEmail func(email string, tenantId string, userContext supertokens.UserContext) (string, error)
+	OnSignUp       func(user User, formFields []TypeFormField, tenantId string, req *http.Request, userContext supertokens.UserContext) error
+	OnSignIn       func(user User, formFields []TypeFormField, tenantId string, req *http.Request, userContext supertokens.UserContext) error
 }
 
+// OverrideStruct is used to change the behaviour of the emailpassword recipe without forking it.
+// Both functions receive the default implementation and return the one the recipe uses. To
+// wrap an API, e.g. to check an invite code before signing up, keep a copy of the original
+// pointer and replace the field with a new one that calls it:
+//
+//	originalSignUpPOST := *originalImplementation.SignUpPOST
+//	(*originalImplementation.SignUpPOST) = func(...) (SignUpPOSTResponse, error) {
+//		// pre processing
+//		return originalSignUpPOST(...)
+//	}
+//
+// Setting an API to nil disables it, and its route is no longer served by the middleware.
 type OverrideStruct struct {
+	// Functions overrides the recipe functions, e.g. SignUp or UpdateEmailOrPassword
 	Functions func(originalImplementation RecipeInterface) RecipeInterface
-	APIs      func(originalImplementation APIInterface) APIInterface
+	// APIs overrides the recipe APIs, e.g. SignUpPOST, SignInPOST or PasswordResetPOST
+	APIs func(originalImplementation APIInterface) APIInterface
 }
 
 type TypeInputFormField struct {
@@ -61,6 +90,8 @@ type TypeNormalisedInputResetPasswordUsingTokenFeature struct {
 	FormFieldsForPasswordResetForm []NormalisedFormField
 }
 
+// User is a user of the emailpassword recipe. TimeJoined is in milliseconds
+// since the epoch, and TenantIds lists the tenants the user belongs to.
 type User struct {
 	ID         string   `json:"id"`
 	Email      string   `json:"email"`
@@ -71,7 +102,203 @@ type User struct {
 type TypeInput struct {
 	SignUpFeature *TypeInputSignUp
 	Override      *OverrideStruct
+	// EmailDelivery sends the emails of the recipe, such as password reset
+	// emails. Set Service to emailpassword.MakeSMTPService to send them
+	// through your SMTP server, or to your own implementation of SendEmail.
+	// Override wraps the service, e.g. to change the content of some emails
+	// and delegate the others to the original implementation. By default,
+	// emails are sent by the SuperTokens backend.
 	EmailDelivery *emaildelivery.TypeInput
+	// PasswordPolicy replaces the default password validation (at least 8
+	// characters, with a letter and a number) at sign up, password reset
+	// and in UpdateEmailOrPassword. It is used instead of the Validate
+	// function of the password form field. All rules are checked, and the
+	// ones that failed are listed in the failedRules of the password field
+	// error.
+	PasswordPolicy *PasswordPolicy
+	// BreachedPasswordCheck checks new passwords against the passwords of
+	// known data breaches, at sign up, password reset and in
+	// UpdateEmailOrPassword. It runs after the other password checks, and is
+	// skipped if the checker fails.
+	BreachedPasswordCheck *BreachedPasswordCheckInput
+	// PasswordReset changes the validity of password reset tokens, the link
+	// sent in password reset emails, and what happens after a reset
+	PasswordReset *PasswordResetInput
+	// AccountLockout locks accounts for a while after repeated failed sign
+	// ins. SignInPOST then returns ACCOUNT_LOCKED_ERROR, even with the right
+	// password, until the lock expires or emailpassword.UnlockAccount is
+	// called.
+	AccountLockout *AccountLockoutInput
+	// Captcha verifies a captcha (e.g. reCAPTCHA, hCaptcha or Turnstile)
+	// before sign ups and sign ins. The sign up and sign in APIs return
+	// CAPTCHA_FAILED_ERROR if it is not valid.
+	Captcha *CaptchaInput
+	// NormaliseEmail changes emails before they are used to sign up, sign in
+	// or find users, e.g. to remove the dots of Gmail addresses so that they
+	// cannot be used for duplicate accounts. The normalised email is the one
+	// that is stored, and to which emails are sent. It must return the same
+	// email when called with its result. emailpassword.MakeEmailNormaliser
+	// returns a normaliser for common rules. By default, emails are not
+	// changed.
+	NormaliseEmail func(email string, tenantId string, userContext supertokens.UserContext) (string, error)
+	// OnSignUp is called after a user signs up with the sign up API, before
+	// the session is created, e.g. to add the user to your own database or
+	// to send analytics events. The form fields do not include the password.
+	// Returning an error fails the request, but does not delete the user.
+	OnSignUp func(user User, formFields []TypeFormField, tenantId string, req *http.Request, userContext supertokens.UserContext) error
+	// OnSignIn is called after a user signs in with the sign in API, before
+	// the session is created. The form fields do not include the password.
+	// Returning an error fails the request.
+	OnSignIn func(user User, formFields []TypeFormField, tenantId string, req *http.Request, userContext supertokens.UserContext) error
+}
+
+type CaptchaInput struct {
+	// FormFieldID is the ID of the form field with the captcha token. The
+	// field is removed from the form fields before they are validated.
+	// Defaults to captchaToken.
+	FormFieldID *string
+	// Verify returns false if the captcha is not valid. The token is empty
+	// if the form field is missing, and apiID is the path of the API, e.g.
+	// constants.SignInAPI. emailpassword.MakeCaptchaVerifier returns a
+	// verifier for the common captcha services.
+	Verify func(token string, apiID string, tenantId string, req *http.Request, userContext supertokens.UserContext) (bool, error)
+}
+
+type NormalisedCaptchaConfig struct {
+	FormFieldID string
+	Verify      func(token string, apiID string, tenantId string, req *http.Request, userContext supertokens.UserContext) (bool, error)
+}
+
+type AccountLockoutInput struct {
+	// MaxFailedAttempts defaults to 5
+	MaxFailedAttempts *uint64
+	// FailureWindow is the time in which failed attempts are counted.
+	// Defaults to 15 minutes.
+	FailureWindow time.Duration
+	// LockoutDuration defaults to 15 minutes
+	LockoutDuration time.Duration
+	// Store defaults to emailpassword.MakeInMemoryAccountLockoutStore, which
+	// is not shared by the instances of the backend
+	Store *AccountLockoutStore
+	// OnAccountLocked is called when an account is locked, e.g. to notify
+	// the user
+	OnAccountLocked func(tenantId string, email string, lockedUntil time.Time, userContext supertokens.UserContext)
+}
+
+// AccountLockoutStore keeps the failed sign in attempts and the locks of
+// accounts, by a key identifying the tenant and email
+type AccountLockoutStore struct {
+	// RecordFailedAttempt records a failed sign in and returns the number of
+	// failed attempts in the window
+	RecordFailedAttempt *func(key string, window time.Duration, userContext supertokens.UserContext) (uint64, error)
+	// Lock locks the account until lockedUntil and clears its failed attempts
+	Lock *func(key string, lockedUntil time.Time, userContext supertokens.UserContext) error
+	// GetLockedUntil returns when the lock of the account expires, or nil if
+	// it is not locked
+	GetLockedUntil *func(key string, userContext supertokens.UserContext) (*time.Time, error)
+	// Reset clears the failed attempts and the lock of the account
+	Reset *func(key string, userContext supertokens.UserContext) error
+}
+
+type NormalisedAccountLockoutConfig struct {
+	MaxFailedAttempts uint64
+	FailureWindow     time.Duration
+	LockoutDuration   time.Duration
+	Store             AccountLockoutStore
+	OnAccountLocked   func(tenantId string, email string, lockedUntil time.Time, userContext supertokens.UserContext)
+}
+
+type PasswordResetInput struct {
+	// TokenValidity shortens the validity of password reset tokens. Tokens
+	// are also limited by the validity configured in the SuperTokens core
+	// (one hour by default), so a longer TokenValidity has no effect.
+	TokenValidity time.Duration
+	// TokenStore keeps the expiry of the tokens created when TokenValidity
+	// is set. Tokens it does not know are rejected, so it must be shared by
+	// all instances of the backend. Defaults to
+	// emailpassword.MakeInMemoryPasswordResetTokenStore.
+	TokenStore *PasswordResetTokenStore
+	// Path of the password reset page on the website, relative to the
+	// WebsiteBasePath. Defaults to /reset-password.
+	Path *string
+	// GetPasswordResetLink builds the link sent in password reset emails,
+	// e.g. to open a mobile app with a deep link. It is used instead of
+	// Path.
+	GetPasswordResetLink func(token string, tenantId string, req *http.Request, userContext supertokens.UserContext) (string, error)
+	// OnPasswordReset is called after a password is reset using a token,
+	// e.g. to revoke the sessions of the user. It is not called with cores
+	// that do not return the user ID of the token.
+	OnPasswordReset func(userID string, tenantId string, userContext supertokens.UserContext) error
+}
+
+// PasswordResetTokenStore keeps the expiry of password reset tokens, by the
+// hash of the token
+type PasswordResetTokenStore struct {
+	SaveTokenExpiry *func(tokenHash string, expiresAt time.Time, userContext supertokens.UserContext) error
+	GetTokenExpiry  *func(tokenHash string, userContext supertokens.UserContext) (*time.Time, error)
+}
+
+type NormalisedPasswordResetConfig struct {
+	// TokenValidity is 0 if the validity is only checked by the core
+	TokenValidity        time.Duration
+	TokenStore           PasswordResetTokenStore
+	GetPasswordResetLink func(token string, tenantId string, req *http.Request, userContext supertokens.UserContext) (string, error)
+	OnPasswordReset      func(userID string, tenantId string, userContext supertokens.UserContext) error
+}
+
+// BreachedPasswordChecker finds how many times a password appeared in data
+// breaches
+type BreachedPasswordChecker struct {
+	GetBreachCount *func(password string, userContext supertokens.UserContext) (int, error)
+}
+
+type BreachedPasswordCheckInput struct {
+	// Checker defaults to emailpassword.MakeHaveIBeenPwnedChecker
+	Checker *BreachedPasswordChecker
+	// Action is REJECT (the default) to refuse breached passwords, or WARN
+	// to accept them and only call OnBreachedPassword
+	Action *string
+	// OnBreachedPassword is called when a breached password is used, e.g.
+	// to log it or to ask the user to change it later
+	OnBreachedPassword func(breachCount int, tenantId string, userContext supertokens.UserContext)
+}
+
+type NormalisedBreachedPasswordCheckConfig struct {
+	Checker            BreachedPasswordChecker
+	Action             string
+	OnBreachedPassword func(breachCount int, tenantId string, userContext supertokens.UserContext)
+}
+
+type PasswordPolicy struct {
+	// MinLength defaults to 8
+	MinLength *int
+	// MaxLength defaults to 100
+	MaxLength *int
+	// RequireLetter and RequireNumber default to true
+	RequireLetter    *bool
+	RequireNumber    *bool
+	RequireLowercase bool
+	RequireUppercase bool
+	RequireSymbol    bool
+	// DenyList contains passwords that are not allowed, e.g. the most
+	// common ones. They are compared case insensitively.
+	DenyList []string
+	// CustomRules are checked after the built in rules
+	CustomRules []PasswordRule
+}
+
+type PasswordRule struct {
+	ID string
+	// Validate returns the error message if the password does not follow
+	// the rule, or nil
+	Validate func(password string, tenantId string, userContext supertokens.UserContext) *string
+}
+
+// PasswordRuleViolation is a rule of the PasswordPolicy that a password does
+// not follow
+type PasswordRuleViolation struct {
+	Rule     string `json:"rule"`
+	ErrorMsg string `json:"error"`
 }
 
 type TypeFormField struct {
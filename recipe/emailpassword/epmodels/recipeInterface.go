@@ -25,6 +25,18 @@ type RecipeInterface struct {
 	CreateResetPasswordToken *func(userID string, tenantId string, userContext supertokens.UserContext) (CreateResetPasswordTokenResponse, error)
 	ResetPasswordUsingToken  *func(token string, newPassword string, tenantId string, userContext supertokens.UserContext) (ResetPasswordUsingTokenResponse, error)
 	UpdateEmailOrPassword    *func(userId string, email *string, password *string, applyPasswordPolicy *bool, tenantIdForPasswordPolicy string, userContext supertokens.UserContext) (UpdateEmailOrPasswordResponse, error)
+	// ImportUserWithPasswordHash creates a user with the password hash of
+	// another system, or replaces the password hash of an existing user
+	ImportUserWithPasswordHash *func(email string, passwordHash string, hashingAlgorithm string, tenantId string, userContext supertokens.UserContext) (ImportUserWithPasswordHashResponse, error)
+}
+
+type ImportUserWithPasswordHashResponse struct {
+	OK *struct {
+		User User
+		// DidUserAlreadyExist is true if the password hash of an existing
+		// user was replaced
+		DidUserAlreadyExist bool
+	}
 }
 
 type SignUpResponse struct {
@@ -64,4 +76,6 @@ type UpdateEmailOrPasswordResponse struct {
 
 type PasswordPolicyViolatedError struct {
 	FailureReason string
+	// FailedRules is only set if a PasswordPolicy is configured
+	FailedRules []PasswordRuleViolation
 }
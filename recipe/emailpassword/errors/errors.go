@@ -15,6 +15,8 @@
 
 package errors
 
+import "github.com/supertokens/supertokens-golang/recipe/emailpassword/epmodels"
+
 type FieldError struct {
 	Msg     string
 	Payload []ErrorPayload
@@ -23,6 +25,9 @@ type FieldError struct {
 type ErrorPayload struct {
 	ID       string `json:"id"`
 	ErrorMsg string `json:"error"`
+	// FailedRules lists the rules of the PasswordPolicy that the password
+	// does not follow
+	FailedRules []epmodels.PasswordRuleViolation `json:"failedRules,omitempty"`
 }
 
 func (err FieldError) Error() string {
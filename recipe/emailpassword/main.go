@@ -49,6 +49,8 @@ func SignIn(tenantId string, email string, password string, userContext ...super
 	return (*instance.RecipeImpl.SignIn)(email, password, tenantId, userContext[0])
 }
 
+// GetUserByID returns the emailpassword user with the ID, or nil if there is
+// no such user. Users of other recipes are not returned.
 func GetUserByID(userID string, userContext ...supertokens.UserContext) (*epmodels.User, error) {
 	instance, err := GetRecipeInstanceOrThrowError()
 	if err != nil {
@@ -60,6 +62,8 @@ func GetUserByID(userID string, userContext ...supertokens.UserContext) (*epmode
 	return (*instance.RecipeImpl.GetUserByID)(userID, userContext[0])
 }
 
+// GetUserByEmail returns the emailpassword user of the tenant with the email,
+// or nil if there is no such user
 func GetUserByEmail(tenantId string, email string, userContext ...supertokens.UserContext) (*epmodels.User, error) {
 	instance, err := GetRecipeInstanceOrThrowError()
 	if err != nil {
@@ -71,6 +75,9 @@ func GetUserByEmail(tenantId string, email string, userContext ...supertokens.Us
 	return (*instance.RecipeImpl.GetUserByEmail)(email, tenantId, userContext[0])
 }
 
+// CreateResetPasswordToken creates a single use token to reset the password
+// of a user, e.g. for resets started by an admin or links sent by SMS. Pass it
+// to ResetPasswordUsingToken, or build a link with CreateResetPasswordLink.
 func CreateResetPasswordToken(tenantId string, userID string, userContext ...supertokens.UserContext) (epmodels.CreateResetPasswordTokenResponse, error) {
 	instance, err := GetRecipeInstanceOrThrowError()
 	if err != nil {
@@ -82,10 +89,13 @@ func CreateResetPasswordToken(tenantId string, userID string, userContext ...sup
 	return (*instance.RecipeImpl.CreateResetPasswordToken)(userID, tenantId, userContext[0])
 }
 
+// ResetPasswordUsingToken consumes a token of CreateResetPasswordToken and
+// sets the new password. Unlike the password reset API, it does not check the
+// new password against the password policy.
 func ResetPasswordUsingToken(tenantId string, token string, newPassword string, userContext ...supertokens.UserContext) (epmodels.ResetPasswordUsingTokenResponse, error) {
 	instance, err := GetRecipeInstanceOrThrowError()
 	if err != nil {
-		return epmodels.ResetPasswordUsingTokenResponse{}, nil
+		return epmodels.ResetPasswordUsingTokenResponse{}, err
 	}
 	if len(userContext) == 0 {
 		userContext = append(userContext, &map[string]interface{}{})
@@ -93,10 +103,16 @@ func ResetPasswordUsingToken(tenantId string, token string, newPassword string,
 	return (*instance.RecipeImpl.ResetPasswordUsingToken)(token, newPassword, tenantId, userContext[0])
 }
 
+// UpdateEmailOrPassword changes the email and/or the password of a user, e.g.
+// from an account settings page. Nil values are not changed. The new password
+// is checked against the password policy of tenantIdForPasswordPolicy (the
+// public tenant by default), unless applyPasswordPolicy is false, and the new
+// email is normalised for that tenant. An email used by another emailpassword
+// user returns EmailAlreadyExistsError.
 func UpdateEmailOrPassword(userId string, email *string, password *string, applyPasswordPolicy *bool, tenantIdForPasswordPolicy *string, userContext ...supertokens.UserContext) (epmodels.UpdateEmailOrPasswordResponse, error) {
 	instance, err := GetRecipeInstanceOrThrowError()
 	if err != nil {
-		return epmodels.UpdateEmailOrPasswordResponse{}, nil
+		return epmodels.UpdateEmailOrPasswordResponse{}, err
 	}
 	if len(userContext) == 0 {
 		userContext = append(userContext, &map[string]interface{}{})
@@ -108,6 +124,40 @@ func UpdateEmailOrPassword(userId string, email *string, password *string, apply
 	return (*instance.RecipeImpl.UpdateEmailOrPassword)(userId, email, password, applyPasswordPolicy, *tenantIdForPasswordPolicy, userContext[0])
 }
 
+// ImportUserWithPasswordHash creates a user with a bcrypt or argon2 password
+// hash exported from another system (see PasswordHashingAlgorithm_BCRYPT and
+// PasswordHashingAlgorithm_ARGON2), so that migrated users can sign in with
+// their existing password. The password hash of an existing user with the
+// email is replaced.
+func ImportUserWithPasswordHash(tenantId string, email string, passwordHash string, hashingAlgorithm string, userContext ...supertokens.UserContext) (epmodels.ImportUserWithPasswordHashResponse, error) {
+	instance, err := GetRecipeInstanceOrThrowError()
+	if err != nil {
+		return epmodels.ImportUserWithPasswordHashResponse{}, err
+	}
+	if len(userContext) == 0 {
+		userContext = append(userContext, &map[string]interface{}{})
+	}
+	return (*instance.RecipeImpl.ImportUserWithPasswordHash)(email, passwordHash, hashingAlgorithm, tenantId, userContext[0])
+}
+
+// UnlockAccount removes the lock and the failed sign in attempts of the
+// account with the email, when AccountLockout is enabled
+func UnlockAccount(tenantId string, email string, userContext ...supertokens.UserContext) error {
+	instance, err := GetRecipeInstanceOrThrowError()
+	if err != nil {
+		return err
+	}
+	if len(userContext) == 0 {
+		userContext = append(userContext, &map[string]interface{}{})
+	}
+	if instance.Config.AccountLockout == nil {
+		return nil
+	}
+	return (*instance.Config.AccountLockout.Store.Reset)(api.GetAccountLockoutKey(tenantId, email), userContext[0])
+}
+
+// SendEmail sends an email with the EmailDelivery service of the recipe,
+// including its override
 func SendEmail(input emaildelivery.EmailType, userContext ...supertokens.UserContext) error {
 	instance, err := GetRecipeInstanceOrThrowError()
 	if err != nil {
@@ -138,14 +188,7 @@ func CreateResetPasswordLink(tenantId string, userID string, userContext ...supe
 		return epmodels.CreateResetPasswordLinkResponse{}, err
 	}
 
-	link, err := api.GetPasswordResetLink(
-		instance.RecipeModule.GetAppInfo(),
-		instance.RecipeModule.GetRecipeID(),
-		tokenResponse.OK.Token,
-		tenantId,
-		supertokens.GetRequestFromUserContext(userContext[0]),
-		userContext[0],
-	)
+	link, err := instance.Config.PasswordReset.GetPasswordResetLink(tokenResponse.OK.Token, tenantId, supertokens.GetRequestFromUserContext(userContext[0]), userContext[0])
 
 	if err != nil {
 		return epmodels.CreateResetPasswordLinkResponse{}, err
@@ -200,6 +243,9 @@ func SendResetPasswordEmail(tenantId string, userID string, userContext ...super
 	}, nil
 }
 
+// MakeSMTPService returns an EmailDelivery service that sends the emails of
+// the recipe through an SMTP server. The Override of the config can change
+// the content of the emails (GetContent) or how they are sent (SendRawEmail).
 func MakeSMTPService(config emaildelivery.SMTPServiceConfig) *emaildelivery.EmailDeliveryInterface {
 	return smtpService.MakeSMTPService(config)
 }
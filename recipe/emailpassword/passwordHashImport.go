@@ -0,0 +1,50 @@
+/* Copyright (c) 2023, VRAI Labs and/or its affiliates. All rights reserved.
+ *
+ * This software is licensed under the Apache License, Version 2.0 (the
+ * "License") as published by the Apache Software Foundation.
+ *
+ * You may not use this file except in compliance with the License. You may
+ * obtain a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+ * License for the specific language governing permissions and limitations
+ * under the License.
+ */
+
+package emailpassword
+
+import (
+	"errors"
+	"strings"
+)
+
+const (
+	// PasswordHashingAlgorithm_BCRYPT is for bcrypt hashes, e.g. of Rails
+	// (has_secure_password) or Django (after removing its bcrypt$ prefix)
+	PasswordHashingAlgorithm_BCRYPT = "BCRYPT"
+	// PasswordHashingAlgorithm_ARGON2 is for argon2 hashes in the PHC string
+	// format, e.g. of Django (after removing its argon2 prefix)
+	PasswordHashingAlgorithm_ARGON2 = "ARGON2"
+)
+
+var passwordHashPrefixes = map[string][]string{
+	PasswordHashingAlgorithm_BCRYPT: {"$2a$", "$2b$", "$2x$", "$2y$"},
+	PasswordHashingAlgorithm_ARGON2: {"$argon2id$", "$argon2i$", "$argon2d$"},
+}
+
+// validatePasswordHash checks the format of a hash before it is sent to the
+// core, so that hashes of the wrong algorithm fail with a clear error
+func validatePasswordHash(passwordHash string, hashingAlgorithm string) error {
+	prefixes, ok := passwordHashPrefixes[hashingAlgorithm]
+	if !ok {
+		return errors.New("hashingAlgorithm must be BCRYPT or ARGON2")
+	}
+	for _, prefix := range prefixes {
+		if strings.HasPrefix(passwordHash, prefix) {
+			return nil
+		}
+	}
+	return errors.New("passwordHash is not a valid " + strings.ToLower(hashingAlgorithm) + " hash")
+}
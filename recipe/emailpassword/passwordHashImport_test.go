@@ -0,0 +1,86 @@
+/* Copyright (c) 2023, VRAI Labs and/or its affiliates. All rights reserved.
+ *
+ * This software is licensed under the Apache License, Version 2.0 (the
+ * "License") as published by the Apache Software Foundation.
+ *
+ * You may not use this file except in compliance with the License. You may
+ * obtain a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+ * License for the specific language governing permissions and limitations
+ * under the License.
+ */
+
+package emailpassword
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/supertokens/supertokens-golang/supertokens"
+)
+
+func TestImportUserWithPasswordHash(t *testing.T) {
+	imported := []map[string]interface{}{}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/apiversion", func(rw http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(rw).Encode(map[string]interface{}{"versions": []string{"3.0"}})
+	})
+	mux.HandleFunc("/public/recipe/user/passwordhash/import", func(rw http.ResponseWriter, r *http.Request) {
+		var body map[string]interface{}
+		json.NewDecoder(r.Body).Decode(&body)
+		imported = append(imported, body)
+		json.NewEncoder(rw).Encode(map[string]interface{}{
+			"status":              "OK",
+			"didUserAlreadyExist": len(imported) > 1,
+			"user": map[string]interface{}{
+				"id":         "user1",
+				"email":      body["email"],
+				"timeJoined": 1700000000000,
+				"tenantIds":  []string{"public"},
+			},
+		})
+	})
+	testServer := httptest.NewServer(mux)
+	defer testServer.Close()
+
+	resetAll()
+	defer resetAll()
+	err := supertokens.Init(supertokens.TypeInput{
+		Supertokens: &supertokens.ConnectionInfo{
+			ConnectionURI: testServer.URL,
+		},
+		AppInfo: supertokens.AppInfo{
+			AppName:       "SuperTokens",
+			WebsiteDomain: "supertokens.io",
+			APIDomain:     "api.supertokens.io",
+		},
+		RecipeList: []supertokens.Recipe{Init(nil)},
+	})
+	assert.NoError(t, err)
+
+	bcryptHash := "$2a$10$N9qo8uLOickgx2ZMRZoMyeIjZAgcfl7p92ldGxad68LJZdL17lhWy"
+	response, err := ImportUserWithPasswordHash("public", "test@example.com", bcryptHash, PasswordHashingAlgorithm_BCRYPT)
+	assert.NoError(t, err)
+	assert.Equal(t, "user1", response.OK.User.ID)
+	assert.False(t, response.OK.DidUserAlreadyExist)
+	assert.Equal(t, bcryptHash, imported[0]["passwordHash"])
+	assert.Equal(t, "BCRYPT", imported[0]["hashingAlgorithm"])
+
+	argon2Hash := "$argon2id$v=19$m=65536,t=2,p=1$c29tZXNhbHQ$3EcDNSMObdgOGUJ2PBWkyA"
+	response, err = ImportUserWithPasswordHash("public", "test@example.com", argon2Hash, PasswordHashingAlgorithm_ARGON2)
+	assert.NoError(t, err)
+	assert.True(t, response.OK.DidUserAlreadyExist)
+
+	// hashes that do not match the algorithm are not sent to the core
+	_, err = ImportUserWithPasswordHash("public", "test@example.com", bcryptHash, PasswordHashingAlgorithm_ARGON2)
+	assert.Error(t, err)
+	_, err = ImportUserWithPasswordHash("public", "test@example.com", "pbkdf2_sha256$260000$salt$hash", "PBKDF2")
+	assert.Error(t, err)
+	assert.Len(t, imported, 2)
+}
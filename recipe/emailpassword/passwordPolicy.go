@@ -0,0 +1,142 @@
+/* Copyright (c) 2023, VRAI Labs and/or its affiliates. All rights reserved.
+ *
+ * This software is licensed under the Apache License, Version 2.0 (the
+ * "License") as published by the Apache Software Foundation.
+ *
+ * You may not use this file except in compliance with the License. You may
+ * obtain a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+ * License for the specific language governing permissions and limitations
+ * under the License.
+ */
+
+package emailpassword
+
+import (
+	"errors"
+	"reflect"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/supertokens/supertokens-golang/recipe/emailpassword/epmodels"
+	"github.com/supertokens/supertokens-golang/supertokens"
+)
+
+// IDs of the built in rules of the password policy
+const (
+	PasswordRule_MIN_LENGTH = "MIN_LENGTH"
+	PasswordRule_MAX_LENGTH = "MAX_LENGTH"
+	PasswordRule_LETTER     = "LETTER"
+	PasswordRule_NUMBER     = "NUMBER"
+	PasswordRule_LOWERCASE  = "LOWERCASE"
+	PasswordRule_UPPERCASE  = "UPPERCASE"
+	PasswordRule_SYMBOL     = "SYMBOL"
+	PasswordRule_DENY_LIST  = "DENY_LIST"
+)
+
+const (
+	defaultPasswordMinLength = 8
+	defaultPasswordMaxLength = 100
+)
+
+func makePasswordPolicyChecker(policy *epmodels.PasswordPolicy) (func(password string, tenantId string, userContext supertokens.UserContext) []epmodels.PasswordRuleViolation, error) {
+	minLength := defaultPasswordMinLength
+	if policy.MinLength != nil {
+		minLength = *policy.MinLength
+	}
+	maxLength := defaultPasswordMaxLength
+	if policy.MaxLength != nil {
+		maxLength = *policy.MaxLength
+	}
+	if minLength < 1 || maxLength <= minLength {
+		return nil, errors.New("PasswordPolicy MinLength must be at least 1 and less than MaxLength")
+	}
+	requireLetter := policy.RequireLetter == nil || *policy.RequireLetter
+	requireNumber := policy.RequireNumber == nil || *policy.RequireNumber
+	deniedPasswords := map[string]bool{}
+	for _, password := range policy.DenyList {
+		deniedPasswords[strings.ToLower(password)] = true
+	}
+	for _, rule := range policy.CustomRules {
+		if rule.ID == "" || rule.Validate == nil {
+			return nil, errors.New("PasswordPolicy CustomRules need an ID and a Validate function")
+		}
+	}
+
+	return func(password string, tenantId string, userContext supertokens.UserContext) []epmodels.PasswordRuleViolation {
+		violations := []epmodels.PasswordRuleViolation{}
+		addViolation := func(rule string, message string) {
+			violations = append(violations, epmodels.PasswordRuleViolation{Rule: rule, ErrorMsg: message})
+		}
+
+		length := len([]rune(password))
+		if length < minLength {
+			message := supertokens.Translate(supertokens.MessagePasswordMinLength, userContext)
+			addViolation(PasswordRule_MIN_LENGTH, strings.ReplaceAll(message, "${minLength}", strconv.Itoa(minLength)))
+		}
+		if length >= maxLength {
+			message := supertokens.Translate(supertokens.MessagePasswordMaxLength, userContext)
+			addViolation(PasswordRule_MAX_LENGTH, strings.ReplaceAll(message, "${maxLength}", strconv.Itoa(maxLength)))
+		}
+
+		hasLetter, hasNumber, hasLowercase, hasUppercase, hasSymbol := false, false, false, false, false
+		for _, r := range password {
+			switch {
+			case unicode.IsLetter(r):
+				hasLetter = true
+				hasLowercase = hasLowercase || unicode.IsLower(r)
+				hasUppercase = hasUppercase || unicode.IsUpper(r)
+			case unicode.IsDigit(r):
+				hasNumber = true
+			case !unicode.IsSpace(r):
+				hasSymbol = true
+			}
+		}
+		if requireLetter && !hasLetter {
+			addViolation(PasswordRule_LETTER, supertokens.Translate(supertokens.MessagePasswordWithoutAlphabet, userContext))
+		}
+		if requireNumber && !hasNumber {
+			addViolation(PasswordRule_NUMBER, supertokens.Translate(supertokens.MessagePasswordWithoutNumber, userContext))
+		}
+		if policy.RequireLowercase && !hasLowercase {
+			addViolation(PasswordRule_LOWERCASE, supertokens.Translate(supertokens.MessagePasswordWithoutLowercase, userContext))
+		}
+		if policy.RequireUppercase && !hasUppercase {
+			addViolation(PasswordRule_UPPERCASE, supertokens.Translate(supertokens.MessagePasswordWithoutUppercase, userContext))
+		}
+		if policy.RequireSymbol && !hasSymbol {
+			addViolation(PasswordRule_SYMBOL, supertokens.Translate(supertokens.MessagePasswordWithoutSymbol, userContext))
+		}
+		if deniedPasswords[strings.ToLower(password)] {
+			addViolation(PasswordRule_DENY_LIST, supertokens.Translate(supertokens.MessagePasswordDenied, userContext))
+		}
+
+		for _, rule := range policy.CustomRules {
+			message := rule.Validate(password, tenantId, userContext)
+			if message != nil {
+				addViolation(rule.ID, supertokens.TranslateMessage(*message, userContext))
+			}
+		}
+		return violations
+	}, nil
+}
+
+// passwordPolicyFormFieldValidator adapts the password policy to the Validate
+// function of the password form field, returning the first failed rule
+func passwordPolicyFormFieldValidator(checkPasswordPolicy func(password string, tenantId string, userContext supertokens.UserContext) []epmodels.PasswordRuleViolation) func(value interface{}, tenantId string) *string {
+	return func(value interface{}, tenantId string) *string {
+		if reflect.TypeOf(value).Kind() != reflect.String {
+			msg := "Development bug: Please make sure the password field yields a string"
+			return &msg
+		}
+		violations := checkPasswordPolicy(value.(string), tenantId, &map[string]interface{}{})
+		if len(violations) == 0 {
+			return nil
+		}
+		return &violations[0].ErrorMsg
+	}
+}
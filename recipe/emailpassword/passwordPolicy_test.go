@@ -0,0 +1,95 @@
+/* Copyright (c) 2023, VRAI Labs and/or its affiliates. All rights reserved.
+ *
+ * This software is licensed under the Apache License, Version 2.0 (the
+ * "License") as published by the Apache Software Foundation.
+ *
+ * You may not use this file except in compliance with the License. You may
+ * obtain a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+ * License for the specific language governing permissions and limitations
+ * under the License.
+ */
+
+package emailpassword
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/supertokens/supertokens-golang/recipe/emailpassword/epmodels"
+	"github.com/supertokens/supertokens-golang/supertokens"
+)
+
+func getFailedPasswordRules(violations []epmodels.PasswordRuleViolation) []string {
+	rules := []string{}
+	for _, violation := range violations {
+		rules = append(rules, violation.Rule)
+	}
+	return rules
+}
+
+func TestPasswordPolicyRules(t *testing.T) {
+	minLength := 12
+	checkPasswordPolicy, err := makePasswordPolicyChecker(&epmodels.PasswordPolicy{
+		MinLength:        &minLength,
+		RequireUppercase: true,
+		RequireSymbol:    true,
+		DenyList:         []string{"Password1234!"},
+		CustomRules: []epmodels.PasswordRule{{
+			ID: "NO_EMAIL",
+			Validate: func(password string, tenantId string, userContext supertokens.UserContext) *string {
+				if strings.Contains(password, "@") {
+					msg := "Password must not contain an email"
+					return &msg
+				}
+				return nil
+			},
+		}},
+	})
+	assert.NoError(t, err)
+	userContext := &map[string]interface{}{}
+
+	violations := checkPasswordPolicy("short1", "public", userContext)
+	assert.Equal(t, []string{PasswordRule_MIN_LENGTH, PasswordRule_UPPERCASE, PasswordRule_SYMBOL}, getFailedPasswordRules(violations))
+	assert.Equal(t, "Password must contain at least 12 characters", violations[0].ErrorMsg)
+
+	assert.Equal(t, []string{PasswordRule_DENY_LIST}, getFailedPasswordRules(checkPasswordPolicy("PASSWORD1234!", "public", userContext)))
+	assert.Equal(t, []string{"NO_EMAIL"}, getFailedPasswordRules(checkPasswordPolicy("Me@example.com-2023", "public", userContext)))
+	assert.Empty(t, checkPasswordPolicy("Correct-Horse-42", "public", userContext))
+
+	validate := passwordPolicyFormFieldValidator(checkPasswordPolicy)
+	assert.Equal(t, "Password must contain at least 12 characters", *validate("short1A!", "public"))
+	assert.Nil(t, validate("Correct-Horse-42", "public"))
+}
+
+func TestPasswordPolicyConfig(t *testing.T) {
+	minLength := 10
+	maxLength := 10
+	_, err := validateAndNormaliseUserInput(nil, supertokens.NormalisedAppinfo{}, &epmodels.TypeInput{
+		PasswordPolicy: &epmodels.PasswordPolicy{MinLength: &minLength, MaxLength: &maxLength},
+	})
+	assert.Error(t, err)
+
+	_, err = validateAndNormaliseUserInput(nil, supertokens.NormalisedAppinfo{}, &epmodels.TypeInput{
+		PasswordPolicy: &epmodels.PasswordPolicy{CustomRules: []epmodels.PasswordRule{{ID: "NO_VALIDATE"}}},
+	})
+	assert.Error(t, err)
+
+	config, err := validateAndNormaliseUserInput(nil, supertokens.NormalisedAppinfo{}, &epmodels.TypeInput{
+		PasswordPolicy: &epmodels.PasswordPolicy{MinLength: &minLength},
+	})
+	assert.NoError(t, err)
+	assert.NotNil(t, config.CheckPasswordPolicy)
+	for _, formField := range config.ResetPasswordUsingTokenFeature.FormFieldsForPasswordResetForm {
+		assert.NotNil(t, formField.Validate("abcdefgh1", "public"))
+	}
+	for _, formField := range config.SignInFeature.FormFields {
+		if formField.ID == "password" {
+			assert.Nil(t, formField.Validate("abcdefgh1", "public"))
+		}
+	}
+}
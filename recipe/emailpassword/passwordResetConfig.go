@@ -0,0 +1,134 @@
+/* Copyright (c) 2023, VRAI Labs and/or its affiliates. All rights reserved.
+ *
+ * This software is licensed under the Apache License, Version 2.0 (the
+ * "License") as published by the Apache Software Foundation.
+ *
+ * You may not use this file except in compliance with the License. You may
+ * obtain a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+ * License for the specific language governing permissions and limitations
+ * under the License.
+ */
+
+package emailpassword
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/supertokens/supertokens-golang/recipe/emailpassword/api"
+	"github.com/supertokens/supertokens-golang/recipe/emailpassword/epmodels"
+	"github.com/supertokens/supertokens-golang/supertokens"
+)
+
+func normalisePasswordResetInput(recipeInstance *Recipe, appInfo supertokens.NormalisedAppinfo, config *epmodels.PasswordResetInput) (epmodels.NormalisedPasswordResetConfig, error) {
+	path := "/reset-password"
+	result := epmodels.NormalisedPasswordResetConfig{
+		GetPasswordResetLink: func(token string, tenantId string, req *http.Request, userContext supertokens.UserContext) (string, error) {
+			return api.GetPasswordResetLinkWithPath(appInfo, path, recipeInstance.RecipeModule.GetRecipeID(), token, tenantId, req, userContext)
+		},
+	}
+	if config == nil {
+		return result, nil
+	}
+
+	if config.Path != nil {
+		if config.GetPasswordResetLink != nil {
+			return epmodels.NormalisedPasswordResetConfig{}, errors.New("PasswordReset Path cannot be used with GetPasswordResetLink")
+		}
+		normalisedPath, err := supertokens.NewNormalisedURLPath(*config.Path)
+		if err != nil {
+			return epmodels.NormalisedPasswordResetConfig{}, err
+		}
+		path = normalisedPath.GetAsStringDangerous()
+	}
+	if config.GetPasswordResetLink != nil {
+		result.GetPasswordResetLink = config.GetPasswordResetLink
+	}
+
+	if config.TokenValidity < 0 {
+		return epmodels.NormalisedPasswordResetConfig{}, errors.New("PasswordReset TokenValidity cannot be negative")
+	}
+	result.TokenValidity = config.TokenValidity
+	if config.TokenStore != nil {
+		if config.TokenStore.SaveTokenExpiry == nil || config.TokenStore.GetTokenExpiry == nil {
+			return epmodels.NormalisedPasswordResetConfig{}, errors.New("password reset token store must implement SaveTokenExpiry and GetTokenExpiry")
+		}
+		result.TokenStore = *config.TokenStore
+	} else if result.TokenValidity > 0 {
+		result.TokenStore = MakeInMemoryPasswordResetTokenStore()
+	}
+
+	result.OnPasswordReset = config.OnPasswordReset
+	return result, nil
+}
+
+func hashPasswordResetToken(token string) string {
+	hash := sha256.Sum256([]byte(token))
+	return base64.RawURLEncoding.EncodeToString(hash[:])
+}
+
+// savePasswordResetTokenExpiry is called after the core creates a token
+func savePasswordResetTokenExpiry(config epmodels.NormalisedPasswordResetConfig, token string, userContext supertokens.UserContext) error {
+	if config.TokenValidity == 0 {
+		return nil
+	}
+	return (*config.TokenStore.SaveTokenExpiry)(hashPasswordResetToken(token), time.Now().Add(config.TokenValidity), userContext)
+}
+
+// isPasswordResetTokenExpired is called before the token is sent to the
+// core, which consumes it
+func isPasswordResetTokenExpired(config epmodels.NormalisedPasswordResetConfig, token string, userContext supertokens.UserContext) (bool, error) {
+	if config.TokenValidity == 0 {
+		return false, nil
+	}
+	expiresAt, err := (*config.TokenStore.GetTokenExpiry)(hashPasswordResetToken(token), userContext)
+	if err != nil {
+		return false, err
+	}
+	return expiresAt == nil || !time.Now().Before(*expiresAt), nil
+}
+
+// MakeInMemoryPasswordResetTokenStore returns a password reset token store
+// that keeps the expiry of the tokens in process memory.
+func MakeInMemoryPasswordResetTokenStore() epmodels.PasswordResetTokenStore {
+	var mutex sync.Mutex
+	expiries := map[string]time.Time{}
+
+	saveTokenExpiry := func(tokenHash string, expiresAt time.Time, userContext supertokens.UserContext) error {
+		mutex.Lock()
+		defer mutex.Unlock()
+
+		now := time.Now()
+		for hash, existing := range expiries {
+			if !now.Before(existing) {
+				delete(expiries, hash)
+			}
+		}
+		expiries[tokenHash] = expiresAt
+		return nil
+	}
+
+	getTokenExpiry := func(tokenHash string, userContext supertokens.UserContext) (*time.Time, error) {
+		mutex.Lock()
+		defer mutex.Unlock()
+
+		expiresAt, ok := expiries[tokenHash]
+		if !ok {
+			return nil, nil
+		}
+		return &expiresAt, nil
+	}
+
+	return epmodels.PasswordResetTokenStore{
+		SaveTokenExpiry: &saveTokenExpiry,
+		GetTokenExpiry:  &getTokenExpiry,
+	}
+}
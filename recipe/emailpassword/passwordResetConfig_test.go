@@ -0,0 +1,125 @@
+/* Copyright (c) 2023, VRAI Labs and/or its affiliates. All rights reserved.
+ *
+ * This software is licensed under the Apache License, Version 2.0 (the
+ * "License") as published by the Apache Software Foundation.
+ *
+ * You may not use this file except in compliance with the License. You may
+ * obtain a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+ * License for the specific language governing permissions and limitations
+ * under the License.
+ */
+
+package emailpassword
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/supertokens/supertokens-golang/recipe/emailpassword/epmodels"
+	"github.com/supertokens/supertokens-golang/supertokens"
+)
+
+func TestPasswordResetConfigValidation(t *testing.T) {
+	path := "/reset"
+	_, err := validateAndNormaliseUserInput(nil, supertokens.NormalisedAppinfo{}, &epmodels.TypeInput{
+		PasswordReset: &epmodels.PasswordResetInput{
+			Path: &path,
+			GetPasswordResetLink: func(token string, tenantId string, req *http.Request, userContext supertokens.UserContext) (string, error) {
+				return "myapp://reset-password?token=" + token, nil
+			},
+		},
+	})
+	assert.Error(t, err)
+
+	_, err = validateAndNormaliseUserInput(nil, supertokens.NormalisedAppinfo{}, &epmodels.TypeInput{
+		PasswordReset: &epmodels.PasswordResetInput{TokenValidity: -time.Minute},
+	})
+	assert.Error(t, err)
+
+	_, err = validateAndNormaliseUserInput(nil, supertokens.NormalisedAppinfo{}, &epmodels.TypeInput{
+		PasswordReset: &epmodels.PasswordResetInput{TokenValidity: time.Minute, TokenStore: &epmodels.PasswordResetTokenStore{}},
+	})
+	assert.Error(t, err)
+
+	config, err := validateAndNormaliseUserInput(nil, supertokens.NormalisedAppinfo{}, &epmodels.TypeInput{
+		PasswordReset: &epmodels.PasswordResetInput{TokenValidity: time.Minute},
+	})
+	assert.NoError(t, err)
+	assert.NotNil(t, config.PasswordReset.TokenStore.GetTokenExpiry)
+}
+
+func TestPasswordResetCustomisation(t *testing.T) {
+	resetRequests := 0
+	mux := http.NewServeMux()
+	mux.HandleFunc("/apiversion", func(rw http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(rw).Encode(map[string]interface{}{"versions": []string{"3.0"}})
+	})
+	mux.HandleFunc("/public/recipe/user/password/reset/token", func(rw http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(rw).Encode(map[string]interface{}{"status": "OK", "token": "token-" + time.Now().Format(time.RFC3339Nano)})
+	})
+	mux.HandleFunc("/public/recipe/user/password/reset", func(rw http.ResponseWriter, r *http.Request) {
+		resetRequests++
+		json.NewEncoder(rw).Encode(map[string]interface{}{"status": "OK", "userId": "user1"})
+	})
+	testServer := httptest.NewServer(mux)
+	defer testServer.Close()
+
+	resetUsers := []string{}
+	path := "/account/new-password"
+	resetAll()
+	defer resetAll()
+	err := supertokens.Init(supertokens.TypeInput{
+		Supertokens: &supertokens.ConnectionInfo{
+			ConnectionURI: testServer.URL,
+		},
+		AppInfo: supertokens.AppInfo{
+			AppName:       "SuperTokens",
+			WebsiteDomain: "supertokens.io",
+			APIDomain:     "api.supertokens.io",
+		},
+		RecipeList: []supertokens.Recipe{Init(&epmodels.TypeInput{
+			PasswordReset: &epmodels.PasswordResetInput{
+				TokenValidity: 50 * time.Millisecond,
+				Path:          &path,
+				OnPasswordReset: func(userID string, tenantId string, userContext supertokens.UserContext) error {
+					resetUsers = append(resetUsers, userID)
+					return nil
+				},
+			},
+		})},
+	})
+	assert.NoError(t, err)
+
+	linkResponse, err := CreateResetPasswordLink("public", "user1")
+	assert.NoError(t, err)
+	assert.True(t, strings.HasPrefix(linkResponse.OK.Link, "https://supertokens.io/auth/account/new-password?token=token-"))
+
+	tokenResponse, err := CreateResetPasswordToken("public", "user1")
+	assert.NoError(t, err)
+	resetResponse, err := ResetPasswordUsingToken("public", tokenResponse.OK.Token, "validPass123")
+	assert.NoError(t, err)
+	assert.NotNil(t, resetResponse.OK)
+	assert.Equal(t, []string{"user1"}, resetUsers)
+
+	// expired tokens, and tokens the store does not know, are not sent to the core
+	tokenResponse, err = CreateResetPasswordToken("public", "user1")
+	assert.NoError(t, err)
+	time.Sleep(60 * time.Millisecond)
+	resetResponse, err = ResetPasswordUsingToken("public", tokenResponse.OK.Token, "validPass123")
+	assert.NoError(t, err)
+	assert.NotNil(t, resetResponse.ResetPasswordInvalidTokenError)
+	resetResponse, err = ResetPasswordUsingToken("public", "unknown-token", "validPass123")
+	assert.NoError(t, err)
+	assert.NotNil(t, resetResponse.ResetPasswordInvalidTokenError)
+	assert.Equal(t, 1, resetRequests)
+	assert.Equal(t, []string{"user1"}, resetUsers)
+}
@@ -50,7 +50,10 @@ func MakeRecipe(recipeId string, appInfo supertokens.NormalisedAppinfo, config *
 	if err != nil {
 		return Recipe{}, err
 	}
-	verifiedConfig := validateAndNormaliseUserInput(r, appInfo, config)
+	verifiedConfig, err := validateAndNormaliseUserInput(r, appInfo, config)
+	if err != nil {
+		return Recipe{}, err
+	}
 	r.Config = verifiedConfig
 	r.APIImpl = verifiedConfig.Override.APIs(api.MakeAPIImplementation())
 	var getEmailPasswordConfig = func() epmodels.TypeNormalisedInput {
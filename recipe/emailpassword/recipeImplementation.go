@@ -22,6 +22,10 @@ import (
 
 func MakeRecipeImplementation(querier supertokens.Querier, getEmailPasswordConfig func() epmodels.TypeNormalisedInput) epmodels.RecipeInterface {
 	signUp := func(email, password string, tenantId string, userContext supertokens.UserContext) (epmodels.SignUpResponse, error) {
+		email, err := getEmailPasswordConfig().NormaliseEmail(email, tenantId, userContext)
+		if err != nil {
+			return epmodels.SignUpResponse{}, err
+		}
 		response, err := querier.SendPostRequest(tenantId+"/recipe/signup", map[string]interface{}{
 			"email":    email,
 			"password": password,
@@ -45,6 +49,10 @@ func MakeRecipeImplementation(querier supertokens.Querier, getEmailPasswordConfi
 	}
 
 	signIn := func(email, password string, tenantId string, userContext supertokens.UserContext) (epmodels.SignInResponse, error) {
+		email, err := getEmailPasswordConfig().NormaliseEmail(email, tenantId, userContext)
+		if err != nil {
+			return epmodels.SignInResponse{}, err
+		}
 		response, err := querier.SendPostRequest(tenantId+"/recipe/signin", map[string]interface{}{
 			"email":    email,
 			"password": password,
@@ -86,6 +94,10 @@ func MakeRecipeImplementation(querier supertokens.Querier, getEmailPasswordConfi
 	}
 
 	getUserByEmail := func(email string, tenantId string, userContext supertokens.UserContext) (*epmodels.User, error) {
+		email, err := getEmailPasswordConfig().NormaliseEmail(email, tenantId, userContext)
+		if err != nil {
+			return nil, err
+		}
 		response, err := querier.SendGetRequest(tenantId+"/recipe/user", map[string]string{
 			"email": email,
 		}, userContext)
@@ -112,8 +124,13 @@ func MakeRecipeImplementation(querier supertokens.Querier, getEmailPasswordConfi
 		}
 		status, ok := response["status"]
 		if ok && status.(string) == "OK" {
+			token := response["token"].(string)
+			err = savePasswordResetTokenExpiry(getEmailPasswordConfig().PasswordReset, token, userContext)
+			if err != nil {
+				return epmodels.CreateResetPasswordTokenResponse{}, err
+			}
 			return epmodels.CreateResetPasswordTokenResponse{
-				OK: &struct{ Token string }{Token: response["token"].(string)},
+				OK: &struct{ Token string }{Token: token},
 			}, nil
 		}
 		return epmodels.CreateResetPasswordTokenResponse{
@@ -122,13 +139,25 @@ func MakeRecipeImplementation(querier supertokens.Querier, getEmailPasswordConfi
 	}
 
 	resetPasswordUsingToken := func(token, newPassword string, tenantId string, userContext supertokens.UserContext) (epmodels.ResetPasswordUsingTokenResponse, error) {
+		passwordResetConfig := getEmailPasswordConfig().PasswordReset
+		expired, err := isPasswordResetTokenExpired(passwordResetConfig, token, userContext)
+		if err != nil {
+			return epmodels.ResetPasswordUsingTokenResponse{}, err
+		}
+		if expired {
+			supertokens.LogDebugMessage("resetPasswordUsingToken: Returning RESET_PASSWORD_INVALID_TOKEN_ERROR because the token is expired")
+			return epmodels.ResetPasswordUsingTokenResponse{
+				ResetPasswordInvalidTokenError: &struct{}{},
+			}, nil
+		}
+
 		response, err := querier.SendPostRequest(tenantId+"/recipe/user/password/reset", map[string]interface{}{
 			"method":      "token",
 			"token":       token,
 			"newPassword": newPassword,
 		}, userContext)
 		if err != nil {
-			return epmodels.ResetPasswordUsingTokenResponse{}, nil
+			return epmodels.ResetPasswordUsingTokenResponse{}, err
 		}
 
 		if response["status"].(string) == "OK" {
@@ -136,6 +165,12 @@ func MakeRecipeImplementation(querier supertokens.Querier, getEmailPasswordConfi
 			if ok {
 				// using CDI >= 2.12
 				userIdStr := userId.(string)
+				if passwordResetConfig.OnPasswordReset != nil {
+					err = passwordResetConfig.OnPasswordReset(userIdStr, tenantId, userContext)
+					if err != nil {
+						return epmodels.ResetPasswordUsingTokenResponse{}, err
+					}
+				}
 				return epmodels.ResetPasswordUsingTokenResponse{
 					OK: &struct {
 						UserId *string
@@ -163,10 +198,24 @@ func MakeRecipeImplementation(querier supertokens.Querier, getEmailPasswordConfi
 			"userId": userId,
 		}
 		if email != nil {
-			requestBody["email"] = email
+			normalisedEmail, err := getEmailPasswordConfig().NormaliseEmail(*email, tenantIdForPasswordPolicy, userContext)
+			if err != nil {
+				return epmodels.UpdateEmailOrPasswordResponse{}, err
+			}
+			requestBody["email"] = normalisedEmail
 		}
 		if password != nil {
-			if applyPasswordPolicy == nil || *applyPasswordPolicy {
+			checkPasswordPolicy := getEmailPasswordConfig().CheckPasswordPolicy
+			if (applyPasswordPolicy == nil || *applyPasswordPolicy) && checkPasswordPolicy != nil {
+				violations := checkPasswordPolicy(*password, tenantIdForPasswordPolicy, userContext)
+				if len(violations) > 0 {
+					errResponse := epmodels.PasswordPolicyViolatedError{
+						FailureReason: violations[0].ErrorMsg,
+						FailedRules:   violations,
+					}
+					return epmodels.UpdateEmailOrPasswordResponse{PasswordPolicyViolatedError: &errResponse}, nil
+				}
+			} else if applyPasswordPolicy == nil || *applyPasswordPolicy {
 				formFields := getEmailPasswordConfig().SignUpFeature.FormFields
 				for i := range formFields {
 					if formFields[i].ID == "password" {
@@ -201,13 +250,47 @@ func MakeRecipeImplementation(querier supertokens.Querier, getEmailPasswordConfi
 			}, nil
 		}
 	}
+	importUserWithPasswordHash := func(email string, passwordHash string, hashingAlgorithm string, tenantId string, userContext supertokens.UserContext) (epmodels.ImportUserWithPasswordHashResponse, error) {
+		err := validatePasswordHash(passwordHash, hashingAlgorithm)
+		if err != nil {
+			return epmodels.ImportUserWithPasswordHashResponse{}, err
+		}
+		email, err = getEmailPasswordConfig().NormaliseEmail(email, tenantId, userContext)
+		if err != nil {
+			return epmodels.ImportUserWithPasswordHashResponse{}, err
+		}
+		response, err := querier.SendPostRequest(tenantId+"/recipe/user/passwordhash/import", map[string]interface{}{
+			"email":            email,
+			"passwordHash":     passwordHash,
+			"hashingAlgorithm": hashingAlgorithm,
+		}, userContext)
+		if err != nil {
+			return epmodels.ImportUserWithPasswordHashResponse{}, err
+		}
+		user, err := parseUser(response["user"])
+		if err != nil {
+			return epmodels.ImportUserWithPasswordHashResponse{}, err
+		}
+		didUserAlreadyExist, _ := response["didUserAlreadyExist"].(bool)
+		return epmodels.ImportUserWithPasswordHashResponse{
+			OK: &struct {
+				User                epmodels.User
+				DidUserAlreadyExist bool
+			}{
+				User:                *user,
+				DidUserAlreadyExist: didUserAlreadyExist,
+			},
+		}, nil
+	}
+
 	return epmodels.RecipeInterface{
-		SignUp:                   &signUp,
-		SignIn:                   &signIn,
-		GetUserByID:              &getUserByID,
-		GetUserByEmail:           &getUserByEmail,
-		CreateResetPasswordToken: &createResetPasswordToken,
-		ResetPasswordUsingToken:  &resetPasswordUsingToken,
-		UpdateEmailOrPassword:    &updateEmailOrPassword,
+		SignUp:                     &signUp,
+		SignIn:                     &signIn,
+		GetUserByID:                &getUserByID,
+		GetUserByEmail:             &getUserByEmail,
+		CreateResetPasswordToken:   &createResetPasswordToken,
+		ResetPasswordUsingToken:    &resetPasswordUsingToken,
+		UpdateEmailOrPassword:      &updateEmailOrPassword,
+		ImportUserWithPasswordHash: &importUserWithPasswordHash,
 	}
 }
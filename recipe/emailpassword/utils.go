@@ -26,7 +26,7 @@ import (
 	"github.com/supertokens/supertokens-golang/supertokens"
 )
 
-func validateAndNormaliseUserInput(recipeInstance *Recipe, appInfo supertokens.NormalisedAppinfo, config *epmodels.TypeInput) epmodels.TypeNormalisedInput {
+func validateAndNormaliseUserInput(recipeInstance *Recipe, appInfo supertokens.NormalisedAppinfo, config *epmodels.TypeInput) (epmodels.TypeNormalisedInput, error) {
 
 	typeNormalisedInput := makeTypeNormalisedInput(recipeInstance)
 
@@ -35,6 +35,65 @@ func validateAndNormaliseUserInput(recipeInstance *Recipe, appInfo supertokens.N
 		typeNormalisedInput.ResetPasswordUsingTokenFeature = validateAndNormaliseResetPasswordUsingTokenConfig(typeNormalisedInput.SignUpFeature)
 	}
 
+	if config != nil && config.PasswordPolicy != nil {
+		checkPasswordPolicy, err := makePasswordPolicyChecker(config.PasswordPolicy)
+		if err != nil {
+			return epmodels.TypeNormalisedInput{}, err
+		}
+		typeNormalisedInput.CheckPasswordPolicy = checkPasswordPolicy
+		for i := range typeNormalisedInput.SignUpFeature.FormFields {
+			if typeNormalisedInput.SignUpFeature.FormFields[i].ID == "password" {
+				typeNormalisedInput.SignUpFeature.FormFields[i].Validate = passwordPolicyFormFieldValidator(checkPasswordPolicy)
+			}
+		}
+	}
+
+	if config != nil && config.BreachedPasswordCheck != nil {
+		breachedPasswordCheck, err := normaliseBreachedPasswordCheckInput(config.BreachedPasswordCheck)
+		if err != nil {
+			return epmodels.TypeNormalisedInput{}, err
+		}
+		checkPasswordPolicy := typeNormalisedInput.CheckPasswordPolicy
+		if checkPasswordPolicy == nil {
+			for _, formField := range typeNormalisedInput.SignUpFeature.FormFields {
+				if formField.ID == "password" {
+					checkPasswordPolicy = passwordFormFieldChecker(formField.Validate)
+				}
+			}
+		}
+		typeNormalisedInput.CheckPasswordPolicy = withBreachedPasswordCheck(breachedPasswordCheck, checkPasswordPolicy)
+	}
+
+	var passwordResetInput *epmodels.PasswordResetInput
+	if config != nil {
+		passwordResetInput = config.PasswordReset
+	}
+	passwordReset, err := normalisePasswordResetInput(recipeInstance, appInfo, passwordResetInput)
+	if err != nil {
+		return epmodels.TypeNormalisedInput{}, err
+	}
+	typeNormalisedInput.PasswordReset = passwordReset
+
+	if config != nil {
+		accountLockout, err := normaliseAccountLockoutInput(config.AccountLockout)
+		if err != nil {
+			return epmodels.TypeNormalisedInput{}, err
+		}
+		typeNormalisedInput.AccountLockout = accountLockout
+
+		captcha, err := normaliseCaptchaInput(config.Captcha)
+		if err != nil {
+			return epmodels.TypeNormalisedInput{}, err
+		}
+		typeNormalisedInput.Captcha = captcha
+
+		if config.NormaliseEmail != nil {
+			typeNormalisedInput.NormaliseEmail = config.NormaliseEmail
+		}
+		typeNormalisedInput.OnSignUp = config.OnSignUp
+		typeNormalisedInput.OnSignIn = config.OnSignIn
+	}
+
 	// we must call this after validateAndNormaliseSignupConfig
 	typeNormalisedInput.SignInFeature = validateAndNormaliseSignInConfig(typeNormalisedInput.SignUpFeature)
 
@@ -66,7 +125,7 @@ func validateAndNormaliseUserInput(recipeInstance *Recipe, appInfo supertokens.N
 		}
 	}
 
-	return typeNormalisedInput
+	return typeNormalisedInput, nil
 }
 
 func makeTypeNormalisedInput(recipeInstance *Recipe) epmodels.TypeNormalisedInput {
@@ -75,6 +134,9 @@ func makeTypeNormalisedInput(recipeInstance *Recipe) epmodels.TypeNormalisedInpu
 		SignUpFeature:                  signUpConfig,
 		SignInFeature:                  validateAndNormaliseSignInConfig(signUpConfig),
 		ResetPasswordUsingTokenFeature: validateAndNormaliseResetPasswordUsingTokenConfig(signUpConfig),
+		NormaliseEmail: func(email string, tenantId string, userContext supertokens.UserContext) (string, error) {
+			return email, nil
+		},
 		Override: epmodels.OverrideStruct{
 			Functions: func(originalImplementation epmodels.RecipeInterface) epmodels.RecipeInterface {
 				return originalImplementation
@@ -222,21 +284,21 @@ func defaultPasswordValidator(value interface{}, tenantId string) *string {
 		return &msg
 	}
 	if len(value.(string)) < 8 {
-		msg := "Password must contain at least 8 characters, including a number"
+		msg := supertokens.DefaultMessages[supertokens.MessagePasswordTooShort]
 		return &msg
 	}
 	if len(value.(string)) >= 100 {
-		msg := "Password's length must be lesser than 100 characters"
+		msg := supertokens.DefaultMessages[supertokens.MessagePasswordTooLong]
 		return &msg
 	}
 	alphaCheck, err := regexp.Match(`^.*[A-Za-z]+.*$`, []byte(value.(string)))
 	if err != nil || !alphaCheck {
-		msg := "Password must contain at least one alphabet"
+		msg := supertokens.DefaultMessages[supertokens.MessagePasswordWithoutAlphabet]
 		return &msg
 	}
 	numCheck, err := regexp.Match(`^.*[0-9]+.*$`, []byte(value.(string)))
 	if err != nil || !numCheck {
-		msg := "Password must contain at least one number"
+		msg := supertokens.DefaultMessages[supertokens.MessagePasswordWithoutNumber]
 		return &msg
 	}
 	return nil
@@ -249,7 +311,7 @@ func defaultEmailValidator(value interface{}, tenantId string) *string {
 	}
 	emailCheck, err := regexp.Match(`^(([^<>()\[\]\\.,;:\s@"]+(\.[^<>()\[\]\\.,;:\s@"]+)*)|(".+"))@((\[[0-9]{1,3}\.[0-9]{1,3}\.[0-9]{1,3}\.[0-9]{1,3}\])|(([a-zA-Z\-0-9]+\.)+[a-zA-Z]{2,}))$`, []byte(value.(string)))
 	if err != nil || !emailCheck {
-		msg := "Email is invalid"
+		msg := supertokens.DefaultMessages[supertokens.MessageEmailInvalid]
 		return &msg
 	}
 	return nil
@@ -941,23 +941,24 @@ const emailVerificationTemplate = `<!doctype html>
 
 </html>`
 
-func getEmailVerifyEmailContent(input emaildelivery.EmailVerificationType) (emaildelivery.EmailContent, error) {
+func getEmailVerifyEmailContent(input emaildelivery.EmailVerificationType, userContext supertokens.UserContext) (emaildelivery.EmailContent, error) {
 	stInstance, err := supertokens.GetInstanceOrThrowError()
 	if err != nil {
 		panic("Please call supertokens.Init function before using the Middleware")
 	}
-	bodyHtml := getEmailVerifyEmailHTML(stInstance.AppInfo.AppName, input.User.Email, input.EmailVerifyLink)
+	subject := supertokens.Translate(supertokens.MessageEmailVerifyEmailSubject, userContext)
+	bodyHtml := getEmailVerifyEmailHTML(stInstance.AppInfo.AppName, subject, input.User.Email, input.EmailVerifyLink)
 	return emaildelivery.EmailContent{
 		Body:    bodyHtml,
 		IsHtml:  true,
-		Subject: "Email verification instructions",
+		Subject: subject,
 		ToEmail: input.User.Email,
 	}, nil
 }
 
-func getEmailVerifyEmailHTML(appName string, email string, verificationLink string) string {
+func getEmailVerifyEmailHTML(appName string, subject string, email string, verificationLink string) string {
 	emailBody := emailVerificationTemplate
-	emailBody = strings.Replace(emailBody, "*|MC:SUBJECT|*", "Email verification instructions", -1)
+	emailBody = strings.Replace(emailBody, "*|MC:SUBJECT|*", subject, -1)
 	emailBody = strings.Replace(emailBody, "${appname}", appName, -1)
 	emailBody = strings.Replace(emailBody, "${verificationLink}", verificationLink, -1)
 	emailBody = strings.Replace(emailBody, "${toEmail}", email, -1)
@@ -21,7 +21,10 @@ import (
 	"github.com/supertokens/supertokens-golang/ingredients/emaildelivery"
 	"github.com/supertokens/supertokens-golang/recipe/emailverification/api"
 	"github.com/supertokens/supertokens-golang/recipe/emailverification/emaildelivery/smtpService"
+	"github.com/supertokens/supertokens-golang/recipe/emailverification/evclaims"
 	"github.com/supertokens/supertokens-golang/recipe/emailverification/evmodels"
+	"github.com/supertokens/supertokens-golang/recipe/session/claims"
+	"github.com/supertokens/supertokens-golang/recipe/session/sessmodels"
 	"github.com/supertokens/supertokens-golang/supertokens"
 )
 
@@ -247,3 +250,33 @@ func SendEmailVerificationEmail(tenantId string, userID string, email *string, u
 func MakeSMTPService(config emaildelivery.SMTPServiceConfig) *emaildelivery.EmailDeliveryInterface {
 	return smtpService.MakeSMTPService(config)
 }
+
+// RequireVerifiedEmail can be passed as the OverrideGlobalClaimValidators of
+// session.VerifySession to require a verified email on a route, e.g. when
+// the recipe is in OPTIONAL mode:
+//
+//	session.VerifySession(&sessmodels.VerifySessionOptions{
+//		OverrideGlobalClaimValidators: emailverification.RequireVerifiedEmail,
+//	}, handler)
+//
+// The claim is added to new sessions by the recipe and refetched when it is
+// older than 5 minutes, or 10 seconds while the email is not verified.
+func RequireVerifiedEmail(globalClaimValidators []claims.SessionClaimValidator, sessionContainer sessmodels.SessionContainer, userContext supertokens.UserContext) ([]claims.SessionClaimValidator, error) {
+	validators := []claims.SessionClaimValidator{}
+	for _, validator := range globalClaimValidators {
+		if validator.ID != evclaims.EmailVerificationClaim.Key {
+			validators = append(validators, validator)
+		}
+	}
+	return append(validators, evclaims.EmailVerificationClaimValidators.IsVerified(nil, nil)), nil
+}
+
+// RefreshEmailVerifiedClaim fetches whether the email of the session's user
+// is verified and updates the claim in the session, e.g. after the email was
+// verified in another browser.
+func RefreshEmailVerifiedClaim(sessionContainer sessmodels.SessionContainer, userContext ...supertokens.UserContext) error {
+	if len(userContext) == 0 {
+		userContext = append(userContext, &map[string]interface{}{})
+	}
+	return sessionContainer.FetchAndSetClaimWithContext(evclaims.EmailVerificationClaim, userContext[0])
+}
@@ -123,6 +123,7 @@ func recipeInit(config evmodels.TypeInput) supertokens.Recipe {
 				return nil, err
 			}
 			singletonInstance = &recipe
+			singletonInstance.RecipeModule.DependsOn(session.RECIPE_ID)
 
 			supertokens.AddPostInitCallback(func() error {
 				sessionRecipe, err := session.GetRecipeInstanceOrThrowError()
@@ -0,0 +1,44 @@
+/*
+ * Copyright (c) 2023, VRAI Labs and/or its affiliates. All rights reserved.
+ *
+ * This software is licensed under the Apache License, Version 2.0 (the
+ * "License") as published by the Apache Software Foundation.
+ *
+ * You may not use this file except in compliance with the License. You may
+ * obtain a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+ * License for the specific language governing permissions and limitations
+ * under the License.
+ */
+
+package emailverification
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/supertokens/supertokens-golang/recipe/emailverification/evclaims"
+	"github.com/supertokens/supertokens-golang/recipe/session/claims"
+)
+
+func TestRequireVerifiedEmail(t *testing.T) {
+	otherValidator := claims.SessionClaimValidator{ID: "other"}
+	validators, err := RequireVerifiedEmail([]claims.SessionClaimValidator{
+		otherValidator,
+		evclaims.EmailVerificationClaimValidators.IsTrue(nil, nil),
+	}, nil, &map[string]interface{}{})
+	assert.NoError(t, err)
+	assert.Len(t, validators, 2)
+	assert.Equal(t, "other", validators[0].ID)
+	assert.Equal(t, "st-ev", validators[1].ID)
+
+	now := time.Now().UnixMilli()
+	verified := validators[1].Validate(map[string]interface{}{"st-ev": map[string]interface{}{"v": true, "t": float64(now)}}, &map[string]interface{}{})
+	assert.True(t, verified.IsValid)
+	notVerified := validators[1].Validate(map[string]interface{}{"st-ev": map[string]interface{}{"v": false, "t": float64(now)}}, &map[string]interface{}{})
+	assert.False(t, notVerified.IsValid)
+}
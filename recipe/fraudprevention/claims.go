@@ -0,0 +1,118 @@
+/* Copyright (c) 2023, VRAI Labs and/or its affiliates. All rights reserved.
+ *
+ * This software is licensed under the Apache License, Version 2.0 (the
+ * "License") as published by the Apache Software Foundation.
+ *
+ * You may not use this file except in compliance with the License. You may
+ * obtain a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+ * License for the specific language governing permissions and limitations
+ * under the License.
+ */
+
+package fraudprevention
+
+import (
+	"github.com/supertokens/supertokens-golang/ingredients/riskscoring"
+	"github.com/supertokens/supertokens-golang/recipe/fraudprevention/fpclaims"
+	"github.com/supertokens/supertokens-golang/recipe/fraudprevention/fpmodels"
+	"github.com/supertokens/supertokens-golang/recipe/session/claims"
+	"github.com/supertokens/supertokens-golang/supertokens"
+)
+
+func init() {
+	// automatically called when this package is imported
+	fpclaims.RiskClaim, fpclaims.RiskClaimValidators = NewRiskClaim()
+}
+
+// NewRiskClaim creates the claim that stores the risk score and decision in
+// the access token. The value is fetched when the session is created, so the
+// score is computed on every sign in and sign up.
+func NewRiskClaim() (*claims.TypeSessionClaim, fpclaims.TypeRiskClaimValidators) {
+	fetchValue := func(userId string, tenantId string, userContext supertokens.UserContext) (interface{}, error) {
+		recipe, err := getRecipeInstanceOrThrowError()
+		if err != nil {
+			return nil, err
+		}
+
+		input := riskscoring.RiskInput{
+			Action:   riskscoring.RiskActionSignIn,
+			UserID:   userId,
+			TenantID: tenantId,
+			Request:  supertokens.GetRequestFromUserContext(userContext),
+		}
+		if supertokens.IsSignUpFromUserContext(userContext) {
+			input.Action = riskscoring.RiskActionSignUp
+		}
+		if input.Request != nil {
+			input.IPAddress = supertokens.GetClientIP(input.Request, userContext)
+			input.UserAgent = input.Request.UserAgent()
+		}
+
+		assessment, err := (*recipe.RecipeImpl.AssessRisk)(input, userContext)
+		if err != nil {
+			return nil, err
+		}
+		return map[string]interface{}{
+			"score":    assessment.Score,
+			"decision": string(assessment.Decision),
+		}, nil
+	}
+
+	riskClaim, _ := claims.PrimitiveClaim(riskClaimKey, fetchValue, nil)
+
+	validators := fpclaims.TypeRiskClaimValidators{
+		IsNotBlocked: func(id *string) claims.SessionClaimValidator {
+			return makeRiskDecisionValidator(riskClaim, id, []fpmodels.RiskDecision{fpmodels.RiskDecisionBlock})
+		},
+		IsAllowed: func(id *string) claims.SessionClaimValidator {
+			return makeRiskDecisionValidator(riskClaim, id, []fpmodels.RiskDecision{fpmodels.RiskDecisionBlock, fpmodels.RiskDecisionStepUp})
+		},
+	}
+
+	return riskClaim, validators
+}
+
+func makeRiskDecisionValidator(riskClaim *claims.TypeSessionClaim, id *string, rejectedDecisions []fpmodels.RiskDecision) claims.SessionClaimValidator {
+	validatorId := riskClaim.Key
+	if id != nil {
+		validatorId = *id
+	}
+	return claims.SessionClaimValidator{
+		ID:    validatorId,
+		Claim: riskClaim,
+		ShouldRefetch: func(payload map[string]interface{}, userContext supertokens.UserContext) bool {
+			return riskClaim.GetValueFromPayload(payload, userContext) == nil
+		},
+		Validate: func(payload map[string]interface{}, userContext supertokens.UserContext) claims.ClaimValidationResult {
+			value, ok := riskClaim.GetValueFromPayload(payload, userContext).(map[string]interface{})
+			if !ok {
+				return claims.ClaimValidationResult{
+					IsValid: false,
+					Reason: map[string]interface{}{
+						"message": "value does not exist",
+					},
+				}
+			}
+			decision, _ := value["decision"].(string)
+			for _, rejectedDecision := range rejectedDecisions {
+				if decision == string(rejectedDecision) {
+					return claims.ClaimValidationResult{
+						IsValid: false,
+						Reason: map[string]interface{}{
+							"message":  "risk score too high",
+							"decision": decision,
+							"score":    value["score"],
+						},
+					}
+				}
+			}
+			return claims.ClaimValidationResult{
+				IsValid: true,
+			}
+		},
+	}
+}
@@ -0,0 +1,61 @@
+/* Copyright (c) 2023, VRAI Labs and/or its affiliates. All rights reserved.
+ *
+ * This software is licensed under the Apache License, Version 2.0 (the
+ * "License") as published by the Apache Software Foundation.
+ *
+ * You may not use this file except in compliance with the License. You may
+ * obtain a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+ * License for the specific language governing permissions and limitations
+ * under the License.
+ */
+
+package fpmodels
+
+import "github.com/supertokens/supertokens-golang/ingredients/riskscoring"
+
+type RiskDecision string
+
+const (
+	RiskDecisionAllow  RiskDecision = "ALLOW"
+	RiskDecisionStepUp RiskDecision = "STEP_UP"
+	RiskDecisionBlock  RiskDecision = "BLOCK"
+)
+
+type TypeInput struct {
+	RiskScoring riskscoring.TypeInput
+	// StepUpThreshold is the score (0 - 100) at or above which the user must
+	// complete an additional factor before the session is treated as trusted
+	StepUpThreshold *float64
+	// BlockThreshold is the score (0 - 100) at or above which sessions are
+	// rejected by a global claim validator
+	BlockThreshold *float64
+	// FailOpen allows sessions to be created if the risk scoring service
+	// fails or times out. The failure is recorded in the assessment's signals.
+	// Defaults to true.
+	FailOpen *bool
+
+	Override *OverrideStruct
+}
+
+type TypeNormalisedInput struct {
+	RiskScoringService riskscoring.RiskScoringInterface
+	StepUpThreshold    *float64
+	BlockThreshold     *float64
+	FailOpen           bool
+
+	Override OverrideStruct
+}
+
+type OverrideStruct struct {
+	Functions func(originalImplementation RecipeInterface) RecipeInterface
+}
+
+type RiskAssessment struct {
+	Score    float64
+	Decision RiskDecision
+	Signals  map[string]interface{}
+}
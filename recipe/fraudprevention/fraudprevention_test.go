@@ -0,0 +1,173 @@
+/* Copyright (c) 2023, VRAI Labs and/or its affiliates. All rights reserved.
+ *
+ * This software is licensed under the Apache License, Version 2.0 (the
+ * "License") as published by the Apache Software Foundation.
+ *
+ * You may not use this file except in compliance with the License. You may
+ * obtain a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+ * License for the specific language governing permissions and limitations
+ * under the License.
+ */
+
+package fraudprevention
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/supertokens/supertokens-golang/ingredients/riskscoring"
+	"github.com/supertokens/supertokens-golang/recipe/fraudprevention/fpclaims"
+	"github.com/supertokens/supertokens-golang/recipe/fraudprevention/fpmodels"
+	"github.com/supertokens/supertokens-golang/supertokens"
+)
+
+func TestRiskDecisionUsesThresholds(t *testing.T) {
+	stepUp := 40.0
+	block := 80.0
+	config := fpmodels.TypeNormalisedInput{
+		StepUpThreshold: &stepUp,
+		BlockThreshold:  &block,
+	}
+
+	assert.Equal(t, fpmodels.RiskDecisionAllow, getRiskDecision(10, config))
+	assert.Equal(t, fpmodels.RiskDecisionStepUp, getRiskDecision(40, config))
+	assert.Equal(t, fpmodels.RiskDecisionBlock, getRiskDecision(95, config))
+	assert.Equal(t, fpmodels.RiskDecisionAllow, getRiskDecision(95, fpmodels.TypeNormalisedInput{}))
+}
+
+func TestInvalidConfigIsRejected(t *testing.T) {
+	service := riskscoring.MakeSEONService(riskscoring.SEONSettings{LicenseKey: "key"})
+
+	_, err := validateAndNormaliseUserInput(supertokens.NormalisedAppinfo{}, fpmodels.TypeInput{})
+	assert.Error(t, err)
+
+	invalid := 120.0
+	_, err = validateAndNormaliseUserInput(supertokens.NormalisedAppinfo{}, fpmodels.TypeInput{
+		RiskScoring:    riskscoring.TypeInput{Service: service},
+		BlockThreshold: &invalid,
+	})
+	assert.Error(t, err)
+
+	stepUp := 90.0
+	block := 50.0
+	_, err = validateAndNormaliseUserInput(supertokens.NormalisedAppinfo{}, fpmodels.TypeInput{
+		RiskScoring:     riskscoring.TypeInput{Service: service},
+		StepUpThreshold: &stepUp,
+		BlockThreshold:  &block,
+	})
+	assert.Error(t, err)
+}
+
+func TestRiskClaimValidators(t *testing.T) {
+	payloadWithDecision := func(decision fpmodels.RiskDecision) map[string]interface{} {
+		return fpclaims.RiskClaim.AddToPayload_internal(map[string]interface{}{}, map[string]interface{}{
+			"score":    50.0,
+			"decision": string(decision),
+		}, &map[string]interface{}{})
+	}
+
+	isNotBlocked := fpclaims.RiskClaimValidators.IsNotBlocked(nil)
+	isAllowed := fpclaims.RiskClaimValidators.IsAllowed(nil)
+
+	assert.True(t, isNotBlocked.Validate(payloadWithDecision(fpmodels.RiskDecisionStepUp), &map[string]interface{}{}).IsValid)
+	assert.False(t, isNotBlocked.Validate(payloadWithDecision(fpmodels.RiskDecisionBlock), &map[string]interface{}{}).IsValid)
+	assert.True(t, isAllowed.Validate(payloadWithDecision(fpmodels.RiskDecisionAllow), &map[string]interface{}{}).IsValid)
+	assert.False(t, isAllowed.Validate(payloadWithDecision(fpmodels.RiskDecisionStepUp), &map[string]interface{}{}).IsValid)
+	assert.False(t, isAllowed.Validate(map[string]interface{}{}, &map[string]interface{}{}).IsValid)
+	assert.True(t, isAllowed.ShouldRefetch(map[string]interface{}{}, &map[string]interface{}{}))
+}
+
+func TestSEONServiceReadsFraudScore(t *testing.T) {
+	var receivedBody map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "key", r.Header.Get("X-API-KEY"))
+		json.NewDecoder(r.Body).Decode(&receivedBody)
+		json.NewEncoder(rw).Encode(map[string]interface{}{
+			"success": true,
+			"data": map[string]interface{}{
+				"fraud_score": 72.5,
+			},
+		})
+	}))
+	defer server.Close()
+
+	service := riskscoring.MakeSEONService(riskscoring.SEONSettings{LicenseKey: "key", APIURL: server.URL})
+	score, err := (*service.GetRiskScore)(riskscoring.RiskInput{UserID: "user", IPAddress: "1.2.3.4"}, &map[string]interface{}{})
+	assert.NoError(t, err)
+	assert.Equal(t, 72.5, score.Score)
+	assert.Equal(t, "user", receivedBody["user_id"])
+	assert.Equal(t, "1.2.3.4", receivedBody["ip"])
+	assert.Equal(t, "account_login", receivedBody["action_type"])
+
+	_, err = (*service.GetRiskScore)(riskscoring.RiskInput{Action: riskscoring.RiskActionSignUp, UserID: "user"}, &map[string]interface{}{})
+	assert.NoError(t, err)
+	assert.Equal(t, "account_register", receivedBody["action_type"])
+}
+
+func TestRiskScoringRequestsTimeOut(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		select {
+		case <-r.Context().Done():
+		case <-time.After(time.Second):
+		}
+	}))
+	defer server.Close()
+
+	service := riskscoring.MakeCastleService(riskscoring.CastleSettings{APISecret: "secret", APIURL: server.URL, Timeout: 50 * time.Millisecond})
+	start := time.Now()
+	_, err := (*service.GetRiskScore)(riskscoring.RiskInput{UserID: "user"}, &map[string]interface{}{})
+	assert.Error(t, err)
+	assert.Less(t, time.Since(start), time.Second)
+}
+
+func TestRiskAssessmentFailsOpenByDefault(t *testing.T) {
+	getRiskScore := func(input riskscoring.RiskInput, userContext supertokens.UserContext) (riskscoring.RiskScore, error) {
+		return riskscoring.RiskScore{}, errors.New("provider is down")
+	}
+	service := &riskscoring.RiskScoringInterface{GetRiskScore: &getRiskScore}
+	ingredient := riskscoring.MakeIngredient(riskscoring.TypeInputWithService{Service: *service})
+
+	config, err := validateAndNormaliseUserInput(supertokens.NormalisedAppinfo{}, fpmodels.TypeInput{
+		RiskScoring: riskscoring.TypeInput{Service: service},
+	})
+	assert.NoError(t, err)
+	assessment, err := (*makeRecipeImplementation(config, ingredient).AssessRisk)(riskscoring.RiskInput{UserID: "user"}, &map[string]interface{}{})
+	assert.NoError(t, err)
+	assert.Equal(t, fpmodels.RiskDecisionAllow, assessment.Decision)
+	assert.Equal(t, "provider is down", assessment.Signals["error"])
+
+	failOpen := false
+	config, err = validateAndNormaliseUserInput(supertokens.NormalisedAppinfo{}, fpmodels.TypeInput{
+		RiskScoring: riskscoring.TypeInput{Service: service},
+		FailOpen:    &failOpen,
+	})
+	assert.NoError(t, err)
+	_, err = (*makeRecipeImplementation(config, ingredient).AssessRisk)(riskscoring.RiskInput{UserID: "user"}, &map[string]interface{}{})
+	assert.Error(t, err)
+}
+
+func TestCastleServiceScalesRisk(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		_, password, ok := r.BasicAuth()
+		assert.True(t, ok)
+		assert.Equal(t, "secret", password)
+		json.NewEncoder(rw).Encode(map[string]interface{}{
+			"risk": 0.25,
+		})
+	}))
+	defer server.Close()
+
+	service := riskscoring.MakeCastleService(riskscoring.CastleSettings{APISecret: "secret", APIURL: server.URL})
+	score, err := (*service.GetRiskScore)(riskscoring.RiskInput{UserID: "user"}, &map[string]interface{}{})
+	assert.NoError(t, err)
+	assert.Equal(t, 25.0, score.Score)
+}
@@ -0,0 +1,121 @@
+/* Copyright (c) 2023, VRAI Labs and/or its affiliates. All rights reserved.
+ *
+ * This software is licensed under the Apache License, Version 2.0 (the
+ * "License") as published by the Apache Software Foundation.
+ *
+ * You may not use this file except in compliance with the License. You may
+ * obtain a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+ * License for the specific language governing permissions and limitations
+ * under the License.
+ */
+
+package fraudprevention
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/supertokens/supertokens-golang/ingredients/riskscoring"
+	"github.com/supertokens/supertokens-golang/recipe/fraudprevention/fpclaims"
+	"github.com/supertokens/supertokens-golang/recipe/fraudprevention/fpmodels"
+	"github.com/supertokens/supertokens-golang/recipe/session"
+	"github.com/supertokens/supertokens-golang/supertokens"
+)
+
+const RECIPE_ID = "fraudprevention"
+
+type Recipe struct {
+	RecipeModule supertokens.RecipeModule
+	Config       fpmodels.TypeNormalisedInput
+	RecipeImpl   fpmodels.RecipeInterface
+	RiskScoring  riskscoring.Ingredient
+}
+
+var singletonInstance *Recipe
+
+func MakeRecipe(recipeId string, appInfo supertokens.NormalisedAppinfo, config fpmodels.TypeInput, onSuperTokensAPIError func(err error, req *http.Request, res http.ResponseWriter)) (Recipe, error) {
+	r := &Recipe{}
+	verifiedConfig, err := validateAndNormaliseUserInput(appInfo, config)
+	if err != nil {
+		return Recipe{}, err
+	}
+	r.Config = verifiedConfig
+
+	r.RiskScoring = riskscoring.MakeIngredient(riskscoring.TypeInputWithService{
+		Service:  verifiedConfig.RiskScoringService,
+		Override: config.RiskScoring.Override,
+	})
+
+	recipeImplementation := makeRecipeImplementation(verifiedConfig, r.RiskScoring)
+	r.RecipeImpl = verifiedConfig.Override.Functions(recipeImplementation)
+
+	recipeModuleInstance := supertokens.MakeRecipeModule(recipeId, appInfo, r.handleAPIRequest, r.getAllCORSHeaders, r.getAPIsHandled, nil, r.handleError, onSuperTokensAPIError)
+	r.RecipeModule = recipeModuleInstance
+
+	return *r, nil
+}
+
+func getRecipeInstanceOrThrowError() (*Recipe, error) {
+	if singletonInstance != nil {
+		return singletonInstance, nil
+	}
+	return nil, errors.New("Initialisation not done. Did you forget to call the init function?")
+}
+
+func recipeInit(config fpmodels.TypeInput) supertokens.Recipe {
+	return func(appInfo supertokens.NormalisedAppinfo, onSuperTokensAPIError func(err error, req *http.Request, res http.ResponseWriter)) (*supertokens.RecipeModule, error) {
+		if singletonInstance == nil {
+			recipe, err := MakeRecipe(RECIPE_ID, appInfo, config, onSuperTokensAPIError)
+			if err != nil {
+				return nil, err
+			}
+			singletonInstance = &recipe
+			singletonInstance.RecipeModule.DependsOn(session.RECIPE_ID)
+
+			supertokens.AddPostInitCallback(func() error {
+				sessionRecipe, err := session.GetRecipeInstanceOrThrowError()
+				if err != nil {
+					return err
+				}
+
+				sessionRecipe.AddClaimFromOtherRecipe(fpclaims.RiskClaim)
+
+				if recipe.Config.BlockThreshold != nil {
+					sessionRecipe.AddClaimValidatorFromOtherRecipe(
+						fpclaims.RiskClaimValidators.IsNotBlocked(nil),
+					)
+				}
+				return nil
+			})
+
+			return &singletonInstance.RecipeModule, nil
+		}
+		return nil, errors.New("Fraud prevention recipe has already been initialised. Please check your code for bugs.")
+	}
+}
+
+// implement RecipeModule
+
+func (r *Recipe) getAPIsHandled() ([]supertokens.APIHandled, error) {
+	return []supertokens.APIHandled{}, nil
+}
+
+func (r *Recipe) handleAPIRequest(id string, tenantId string, req *http.Request, res http.ResponseWriter, theirHandler http.HandlerFunc, _ supertokens.NormalisedURLPath, _ string, userContext supertokens.UserContext) error {
+	return errors.New("should never come here")
+}
+
+func (r *Recipe) getAllCORSHeaders() []string {
+	return []string{}
+}
+
+func (r *Recipe) handleError(err error, req *http.Request, res http.ResponseWriter, userContext supertokens.UserContext) (bool, error) {
+	return false, nil
+}
+
+func ResetForTest() {
+	singletonInstance = nil
+}
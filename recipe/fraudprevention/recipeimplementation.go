@@ -0,0 +1,50 @@
+/* Copyright (c) 2023, VRAI Labs and/or its affiliates. All rights reserved.
+ *
+ * This software is licensed under the Apache License, Version 2.0 (the
+ * "License") as published by the Apache Software Foundation.
+ *
+ * You may not use this file except in compliance with the License. You may
+ * obtain a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+ * License for the specific language governing permissions and limitations
+ * under the License.
+ */
+
+package fraudprevention
+
+import (
+	"github.com/supertokens/supertokens-golang/ingredients/riskscoring"
+	"github.com/supertokens/supertokens-golang/recipe/fraudprevention/fpmodels"
+	"github.com/supertokens/supertokens-golang/supertokens"
+)
+
+func makeRecipeImplementation(config fpmodels.TypeNormalisedInput, riskScoring riskscoring.Ingredient) fpmodels.RecipeInterface {
+	assessRisk := func(input riskscoring.RiskInput, userContext supertokens.UserContext) (fpmodels.RiskAssessment, error) {
+		score, err := (*riskScoring.IngredientInterfaceImpl.GetRiskScore)(input, userContext)
+		if err != nil {
+			if !config.FailOpen {
+				return fpmodels.RiskAssessment{}, err
+			}
+			supertokens.LogDebugMessage("assessRisk: Allowing the request since the risk scoring service failed: " + err.Error())
+			return fpmodels.RiskAssessment{
+				Score:    0,
+				Decision: fpmodels.RiskDecisionAllow,
+				Signals: map[string]interface{}{
+					"error": err.Error(),
+				},
+			}, nil
+		}
+		return fpmodels.RiskAssessment{
+			Score:    score.Score,
+			Decision: getRiskDecision(score.Score, config),
+			Signals:  score.Signals,
+		}, nil
+	}
+
+	return fpmodels.RecipeInterface{
+		AssessRisk: &assessRisk,
+	}
+}
@@ -0,0 +1,77 @@
+/* Copyright (c) 2023, VRAI Labs and/or its affiliates. All rights reserved.
+ *
+ * This software is licensed under the Apache License, Version 2.0 (the
+ * "License") as published by the Apache Software Foundation.
+ *
+ * You may not use this file except in compliance with the License. You may
+ * obtain a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+ * License for the specific language governing permissions and limitations
+ * under the License.
+ */
+
+package fraudprevention
+
+import (
+	"errors"
+
+	"github.com/supertokens/supertokens-golang/recipe/fraudprevention/fpmodels"
+	"github.com/supertokens/supertokens-golang/supertokens"
+)
+
+func validateAndNormaliseUserInput(appInfo supertokens.NormalisedAppinfo, config fpmodels.TypeInput) (fpmodels.TypeNormalisedInput, error) {
+	if config.RiskScoring.Service == nil {
+		return fpmodels.TypeNormalisedInput{}, errors.New("please provide a risk scoring service in the RiskScoring config")
+	}
+	if err := validateThreshold(config.StepUpThreshold, "StepUpThreshold"); err != nil {
+		return fpmodels.TypeNormalisedInput{}, err
+	}
+	if err := validateThreshold(config.BlockThreshold, "BlockThreshold"); err != nil {
+		return fpmodels.TypeNormalisedInput{}, err
+	}
+	if config.StepUpThreshold != nil && config.BlockThreshold != nil && *config.StepUpThreshold > *config.BlockThreshold {
+		return fpmodels.TypeNormalisedInput{}, errors.New("StepUpThreshold cannot be greater than BlockThreshold")
+	}
+
+	typeNormalisedInput := fpmodels.TypeNormalisedInput{
+		RiskScoringService: *config.RiskScoring.Service,
+		StepUpThreshold:    config.StepUpThreshold,
+		BlockThreshold:     config.BlockThreshold,
+		FailOpen:           true,
+		Override: fpmodels.OverrideStruct{
+			Functions: func(originalImplementation fpmodels.RecipeInterface) fpmodels.RecipeInterface {
+				return originalImplementation
+			},
+		},
+	}
+
+	if config.FailOpen != nil {
+		typeNormalisedInput.FailOpen = *config.FailOpen
+	}
+
+	if config.Override != nil && config.Override.Functions != nil {
+		typeNormalisedInput.Override.Functions = config.Override.Functions
+	}
+
+	return typeNormalisedInput, nil
+}
+
+func validateThreshold(threshold *float64, name string) error {
+	if threshold != nil && (*threshold < 0 || *threshold > 100) {
+		return errors.New(name + " must be between 0 and 100")
+	}
+	return nil
+}
+
+func getRiskDecision(score float64, config fpmodels.TypeNormalisedInput) fpmodels.RiskDecision {
+	if config.BlockThreshold != nil && score >= *config.BlockThreshold {
+		return fpmodels.RiskDecisionBlock
+	}
+	if config.StepUpThreshold != nil && score >= *config.StepUpThreshold {
+		return fpmodels.RiskDecisionStepUp
+	}
+	return fpmodels.RiskDecisionAllow
+}
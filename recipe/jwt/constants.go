@@ -18,3 +18,9 @@ package jwt
 const (
 	GetJWKSAPI = "/jwt/jwks.json"
 )
+
+const defaultSigningAlgorithm = "RS256"
+
+// supportedSigningAlgorithms are the algorithms that JWTs can be signed
+// with. The keys are generated by the core.
+var supportedSigningAlgorithms = []string{"RS256", "ES256"}
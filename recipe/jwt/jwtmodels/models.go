@@ -18,19 +18,30 @@ package jwtmodels
 type JsonWebKeys struct {
 	Kty string `json:"kty"`
 	Kid string `json:"kid"`
-	N   string `json:"n"`
-	E   string `json:"e"`
+	// N and E are set for RSA keys
+	N string `json:"n,omitempty"`
+	E string `json:"e,omitempty"`
+	// Crv, X and Y are set for EC keys
+	Crv string `json:"crv,omitempty"`
+	X   string `json:"x,omitempty"`
+	Y   string `json:"y,omitempty"`
 	Alg string `json:"alg"`
 	Use string `json:"use"`
 }
 
 type TypeInput struct {
 	JwtValiditySeconds *uint64
-	Override           *OverrideStruct
+	// SigningAlgorithm is the algorithm that JWTs are signed with, "RS256"
+	// (the default) or "ES256". The keys are generated by the core, and
+	// creating a JWT fails with UnsupportedAlgorithmError if the core does
+	// not support the algorithm.
+	SigningAlgorithm *string
+	Override         *OverrideStruct
 }
 
 type TypeNormalisedInput struct {
 	JwtValiditySeconds uint64
+	SigningAlgorithm   string
 	Override           OverrideStruct
 }
 
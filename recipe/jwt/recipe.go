@@ -37,7 +37,14 @@ var singletonInstance *Recipe
 
 func MakeRecipe(recipeId string, appInfo supertokens.NormalisedAppinfo, config *jwtmodels.TypeInput, onSuperTokensAPIError func(err error, req *http.Request, res http.ResponseWriter)) (Recipe, error) {
 	r := &Recipe{}
-	verifiedConfig := validateAndNormaliseUserInput(appInfo, config)
+	verifiedConfig, err := validateAndNormaliseUserInput(appInfo, config)
+	if err != nil {
+		return Recipe{}, err
+	}
+	err = supertokens.ValidateJWTAlgorithmForFIPSMode(verifiedConfig.SigningAlgorithm)
+	if err != nil {
+		return Recipe{}, err
+	}
 	r.Config = verifiedConfig
 	r.APIImpl = verifiedConfig.Override.APIs(api.MakeAPIImplementation())
 
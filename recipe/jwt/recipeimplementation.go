@@ -43,7 +43,7 @@ func makeRecipeImplementation(querier supertokens.Querier, config jwtmodels.Type
 		response, err := querier.SendPostRequest("/recipe/jwt", map[string]interface{}{
 			"payload":             payload,
 			"validity":            validitySeconds,
-			"algorithm":           "RS256",
+			"algorithm":           config.SigningAlgorithm,
 			"jwksDomain":          appInfo.APIDomain.GetAsStringDangerous(),
 			"useStaticSigningKey": shouldUseStaticSigningKey,
 		}, userContext)
@@ -73,13 +73,23 @@ func makeRecipeImplementation(querier supertokens.Querier, config jwtmodels.Type
 		keys := []jwtmodels.JsonWebKeys{}
 
 		for _, v := range response["keys"].([]interface{}) {
+			key := v.(map[string]interface{})
+			// RSA keys have n and e, EC keys have crv, x and y
+			n, _ := key["n"].(string)
+			e, _ := key["e"].(string)
+			crv, _ := key["crv"].(string)
+			x, _ := key["x"].(string)
+			y, _ := key["y"].(string)
 			keys = append(keys, jwtmodels.JsonWebKeys{
-				Kty: (v.(map[string]interface{}))["kty"].(string),
-				Kid: (v.(map[string]interface{}))["kid"].(string),
-				N:   (v.(map[string]interface{}))["n"].(string),
-				E:   (v.(map[string]interface{}))["e"].(string),
-				Alg: (v.(map[string]interface{}))["alg"].(string),
-				Use: (v.(map[string]interface{}))["use"].(string),
+				Kty: key["kty"].(string),
+				Kid: key["kid"].(string),
+				N:   n,
+				E:   e,
+				Crv: crv,
+				X:   x,
+				Y:   y,
+				Alg: key["alg"].(string),
+				Use: key["use"].(string),
 			})
 		}
 
@@ -0,0 +1,88 @@
+/* Copyright (c) 2023, VRAI Labs and/or its affiliates. All rights reserved.
+ *
+ * This software is licensed under the Apache License, Version 2.0 (the
+ * "License") as published by the Apache Software Foundation.
+ *
+ * You may not use this file except in compliance with the License. You may
+ * obtain a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+ * License for the specific language governing permissions and limitations
+ * under the License.
+ */
+
+package jwt
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/supertokens/supertokens-golang/recipe/jwt/jwtmodels"
+	"github.com/supertokens/supertokens-golang/supertokens"
+)
+
+func TestSigningAlgorithmIsSentToTheCoreAndECKeysAreServed(t *testing.T) {
+	var requestedAlgorithm interface{}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/apiversion", func(rw http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(rw).Encode(map[string]interface{}{"versions": []string{"3.0"}})
+	})
+	mux.HandleFunc("/recipe/jwt", func(rw http.ResponseWriter, r *http.Request) {
+		var body map[string]interface{}
+		json.NewDecoder(r.Body).Decode(&body)
+		requestedAlgorithm = body["algorithm"]
+		json.NewEncoder(rw).Encode(map[string]interface{}{"status": "OK", "jwt": "header.payload.signature"})
+	})
+	mux.HandleFunc("/.well-known/jwks.json", func(rw http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(rw).Encode(map[string]interface{}{"keys": []interface{}{
+			map[string]interface{}{"kty": "EC", "kid": "s-1", "crv": "P-256", "x": "x-coordinate", "y": "y-coordinate", "alg": "ES256", "use": "sig"},
+		}})
+	})
+	testServer := httptest.NewServer(mux)
+	defer testServer.Close()
+
+	resetAll()
+	defer resetAll()
+	algorithm := "ES256"
+	err := supertokens.Init(supertokens.TypeInput{
+		Supertokens: &supertokens.ConnectionInfo{
+			ConnectionURI: testServer.URL,
+		},
+		AppInfo: supertokens.AppInfo{
+			APIDomain:     "api.supertokens.io",
+			AppName:       "SuperTokens",
+			WebsiteDomain: "supertokens.io",
+		},
+		RecipeList: []supertokens.Recipe{
+			Init(&jwtmodels.TypeInput{SigningAlgorithm: &algorithm}),
+		},
+	})
+	assert.NoError(t, err)
+
+	response, err := CreateJWT(map[string]interface{}{}, nil, nil)
+	assert.NoError(t, err)
+	assert.NotNil(t, response.OK)
+	assert.Equal(t, "ES256", requestedAlgorithm)
+
+	jwks, err := GetJWKS()
+	assert.NoError(t, err)
+	assert.Equal(t, []jwtmodels.JsonWebKeys{{Kty: "EC", Kid: "s-1", Crv: "P-256", X: "x-coordinate", Y: "y-coordinate", Alg: "ES256", Use: "sig"}}, jwks.OK.Keys)
+	serialised, err := json.Marshal(jwks.OK.Keys[0])
+	assert.NoError(t, err)
+	assert.NotContains(t, string(serialised), `"n"`)
+}
+
+func TestUnsupportedSigningAlgorithmsAreRejected(t *testing.T) {
+	algorithm := "HS256"
+	_, err := validateAndNormaliseUserInput(supertokens.NormalisedAppinfo{}, &jwtmodels.TypeInput{SigningAlgorithm: &algorithm})
+	assert.EqualError(t, err, "SigningAlgorithm must be one of RS256, ES256")
+
+	config, err := validateAndNormaliseUserInput(supertokens.NormalisedAppinfo{}, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, "RS256", config.SigningAlgorithm)
+}
@@ -16,11 +16,14 @@
 package jwt
 
 import (
+	"errors"
+	"strings"
+
 	"github.com/supertokens/supertokens-golang/recipe/jwt/jwtmodels"
 	"github.com/supertokens/supertokens-golang/supertokens"
 )
 
-func validateAndNormaliseUserInput(appInfo supertokens.NormalisedAppinfo, config *jwtmodels.TypeInput) jwtmodels.TypeNormalisedInput {
+func validateAndNormaliseUserInput(appInfo supertokens.NormalisedAppinfo, config *jwtmodels.TypeInput) (jwtmodels.TypeNormalisedInput, error) {
 
 	typeNormalisedInput := makeTypeNormalisedInput(appInfo)
 
@@ -28,6 +31,13 @@ func validateAndNormaliseUserInput(appInfo supertokens.NormalisedAppinfo, config
 		typeNormalisedInput.JwtValiditySeconds = *config.JwtValiditySeconds
 	}
 
+	if config != nil && config.SigningAlgorithm != nil {
+		if !supertokens.DoesSliceContainString(*config.SigningAlgorithm, supportedSigningAlgorithms) {
+			return jwtmodels.TypeNormalisedInput{}, errors.New("SigningAlgorithm must be one of " + strings.Join(supportedSigningAlgorithms, ", "))
+		}
+		typeNormalisedInput.SigningAlgorithm = *config.SigningAlgorithm
+	}
+
 	if config != nil && config.Override != nil {
 		if config.Override.Functions != nil {
 			typeNormalisedInput.Override.Functions = config.Override.Functions
@@ -37,12 +47,13 @@ func validateAndNormaliseUserInput(appInfo supertokens.NormalisedAppinfo, config
 		}
 	}
 
-	return typeNormalisedInput
+	return typeNormalisedInput, nil
 }
 
 func makeTypeNormalisedInput(appInfo supertokens.NormalisedAppinfo) jwtmodels.TypeNormalisedInput {
 	return jwtmodels.TypeNormalisedInput{
 		JwtValiditySeconds: 3153600000, // 100 years in seconds
+		SigningAlgorithm:   defaultSigningAlgorithm,
 		Override: jwtmodels.OverrideStruct{
 			Functions: func(originalImplementation jwtmodels.RecipeInterface) jwtmodels.RecipeInterface {
 				return originalImplementation
@@ -82,41 +82,31 @@ func makeRecipeImplementation(querier supertokens.Querier, config multitenancymo
 	}
 
 	getTenant := func(tenantId string, userContext supertokens.UserContext) (*multitenancymodels.Tenant, error) {
-		tenantResponse, err := querier.SendGetRequest(fmt.Sprintf("/%s/recipe/multitenancy/tenant", tenantId), map[string]string{}, userContext)
+		var tenantResponse struct {
+			multitenancymodels.Tenant
+			Status string `json:"status"`
+		}
+		err := querier.SendGetRequestInto(fmt.Sprintf("/%s/recipe/multitenancy/tenant", tenantId), map[string]string{}, &tenantResponse, userContext)
 		if err != nil {
 			return nil, err
 		}
-		status, ok := tenantResponse["status"].(string)
-		if ok {
-			if status == "TENANT_NOT_FOUND_ERROR" {
-				return nil, nil
-			}
-
-			result := &multitenancymodels.Tenant{}
-			err = supertokens.MapToStruct(tenantResponse, result)
-			if err != nil {
-				return nil, err
-			}
-
-			if status == "OK" {
-				return result, nil
-			}
+		if tenantResponse.Status == "TENANT_NOT_FOUND_ERROR" {
+			return nil, nil
+		}
+		if tenantResponse.Status == "OK" {
+			return &tenantResponse.Tenant, nil
 		}
 
 		return nil, errors.New("should not come here")
 	}
 
 	listAllTenants := func(userContext supertokens.UserContext) (multitenancymodels.ListAllTenantsResponse, error) {
-		tenantsResponse, err := querier.SendGetRequest("/recipe/multitenancy/tenant/list", map[string]string{}, userContext)
-		if err != nil {
-			return multitenancymodels.ListAllTenantsResponse{}, err
-		}
 		result := multitenancymodels.ListAllTenantsResponse{
 			OK: &struct {
 				Tenants []multitenancymodels.Tenant `json:"tenants"`
 			}{},
 		}
-		err = supertokens.MapToStruct(tenantsResponse, result.OK)
+		err := querier.SendGetRequestInto("/recipe/multitenancy/tenant/list", map[string]string{}, result.OK, userContext)
 		if err != nil {
 			return multitenancymodels.ListAllTenantsResponse{}, err
 		}
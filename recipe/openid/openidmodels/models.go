@@ -23,13 +23,16 @@ import (
 type TypeInput struct {
 	Issuer             *string
 	JwtValiditySeconds *uint64
-	Override           *OverrideStruct
+	// SigningAlgorithm is passed to the JWT recipe, see jwtmodels.TypeInput
+	SigningAlgorithm *string
+	Override         *OverrideStruct
 }
 
 type TypeNormalisedInput struct {
 	IssuerDomain       supertokens.NormalisedURLDomain
 	IssuerPath         supertokens.NormalisedURLPath
 	JwtValiditySeconds *uint64
+	SigningAlgorithm   *string
 	Override           OverrideStruct
 }
 
@@ -52,6 +52,7 @@ func MakeRecipe(recipeId string, appInfo supertokens.NormalisedAppinfo, config *
 
 	jwtRecipe, err := jwt.MakeRecipe(recipeId, appInfo, &jwtmodels.TypeInput{
 		JwtValiditySeconds: verifiedConfig.JwtValiditySeconds,
+		SigningAlgorithm:   verifiedConfig.SigningAlgorithm,
 		Override:           verifiedConfig.Override.JwtFeature,
 	}, onSuperTokensAPIError)
 	if err != nil {
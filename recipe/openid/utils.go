@@ -42,6 +42,8 @@ func validateAndNormaliseUserInput(appInfo supertokens.NormalisedAppinfo, config
 		if result.IssuerPath.GetAsStringDangerous() != appInfo.APIBasePath.GetAsStringDangerous() {
 			return openidmodels.TypeNormalisedInput{}, errors.New("The path of the issuer URL must be equal to the apiBasePath. The default value is /auth")
 		}
+
+		result.SigningAlgorithm = config.SigningAlgorithm
 	}
 
 	if config != nil && config.Override != nil {
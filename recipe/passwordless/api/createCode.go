@@ -75,7 +75,7 @@ func CreateCode(apiImplementation plessmodels.APIInterface, tenantId string, opt
 		}
 		if validateErr != nil {
 			return supertokens.Send200Response(options.Res, supertokens.ConvertGeneralErrorToJsonResponse(supertokens.GeneralErrorResponse{
-				Message: *validateErr,
+				Message: supertokens.TranslateMessage(*validateErr, userContext),
 			}))
 		}
 	}
@@ -89,7 +89,7 @@ func CreateCode(apiImplementation plessmodels.APIInterface, tenantId string, opt
 		}
 		if validateErr != nil {
 			return supertokens.Send200Response(options.Res, supertokens.ConvertGeneralErrorToJsonResponse(supertokens.GeneralErrorResponse{
-				Message: *validateErr,
+				Message: supertokens.TranslateMessage(*validateErr, userContext),
 			}))
 		}
 
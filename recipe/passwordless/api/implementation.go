@@ -36,6 +36,19 @@ func MakeAPIImplementation() plessmodels.APIInterface {
 		}
 
 		if response.OK == nil {
+			reason := "RESTART_FLOW_ERROR"
+			if response.IncorrectUserInputCodeError != nil {
+				reason = "INCORRECT_USER_INPUT_CODE_ERROR"
+			} else if response.ExpiredUserInputCodeError != nil {
+				reason = "EXPIRED_USER_INPUT_CODE_ERROR"
+			}
+			supertokens.WriteAuditLog(supertokens.AuditEntry{
+				Action:   supertokens.AuditActionSignIn,
+				Result:   supertokens.AuditResultFailure,
+				Reason:   reason,
+				TenantId: tenantId,
+				RecipeID: options.RecipeID,
+			}, userContext)
 			return plessmodels.ConsumeCodePOSTResponse{
 				IncorrectUserInputCodeError: response.IncorrectUserInputCodeError,
 				ExpiredUserInputCodeError:   response.ExpiredUserInputCodeError,
@@ -61,11 +74,40 @@ func MakeAPIImplementation() plessmodels.APIInterface {
 			}
 		}
 
+		if response.OK.CreatedNewUser {
+			supertokens.SetIsSignUpInUserContext(userContext)
+		}
 		session, err := session.CreateNewSession(options.Req, options.Res, tenantId, user.ID, map[string]interface{}{}, map[string]interface{}{}, userContext)
 		if err != nil {
 			return plessmodels.ConsumeCodePOSTResponse{}, err
 		}
 
+		eventType := supertokens.EventSignIn
+		auditAction := supertokens.AuditActionSignIn
+		if response.OK.CreatedNewUser {
+			eventType = supertokens.EventSignUp
+			auditAction = supertokens.AuditActionSignUp
+		}
+		supertokens.EmitEvent(supertokens.Event{
+			Type:     eventType,
+			TenantId: tenantId,
+			UserID:   user.ID,
+			Data: map[string]interface{}{
+				"recipeId":      options.RecipeID,
+				"sessionHandle": session.GetHandle(),
+			},
+		}, userContext)
+		supertokens.WriteAuditLog(supertokens.AuditEntry{
+			Action:   auditAction,
+			Result:   supertokens.AuditResultSuccess,
+			UserID:   user.ID,
+			TenantId: tenantId,
+			RecipeID: options.RecipeID,
+			Data: map[string]interface{}{
+				"sessionHandle": session.GetHandle(),
+			},
+		}, userContext)
+
 		return plessmodels.ConsumeCodePOSTResponse{
 			OK: &struct {
 				CreatedNewUser bool
@@ -387,7 +429,7 @@ func MakeAPIImplementation() plessmodels.APIInterface {
 
 		return plessmodels.ResendCodePOSTResponse{
 			GeneralError: &supertokens.GeneralErrorResponse{
-				Message: "Failed to generate a one time code. Please try again",
+				Message: supertokens.Translate(supertokens.MessageOTPGenerationFailed, userContext),
 			},
 		}, nil
 	}
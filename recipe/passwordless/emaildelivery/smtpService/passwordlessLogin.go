@@ -2836,21 +2836,22 @@ const otpLoginTemplate = `<!doctype html>
 
 </html>`
 
-func getPasswordlessLoginEmailContent(input emaildelivery.PasswordlessLoginType) (emaildelivery.EmailContent, error) {
+func getPasswordlessLoginEmailContent(input emaildelivery.PasswordlessLoginType, userContext supertokens.UserContext) (emaildelivery.EmailContent, error) {
 	stInstance, err := supertokens.GetInstanceOrThrowError()
 	if err != nil {
 		panic("Please call supertokens.Init function before using the Middleware")
 	}
-	bodyHtml := getPasswordlessLoginEmailHTML(stInstance.AppInfo.AppName, input.CodeLifetime, input.UrlWithLinkCode, input.UserInputCode, input.Email)
+	subject := supertokens.Translate(supertokens.MessagePasswordlessEmailSubject, userContext)
+	bodyHtml := getPasswordlessLoginEmailHTML(stInstance.AppInfo.AppName, subject, input.CodeLifetime, input.UrlWithLinkCode, input.UserInputCode, input.Email)
 	return emaildelivery.EmailContent{
 		Body:    bodyHtml,
 		IsHtml:  true,
-		Subject: "Login to your account",
+		Subject: subject,
 		ToEmail: input.Email,
 	}, nil
 }
 
-func getPasswordlessLoginEmailHTML(appName string, codeLifetime uint64, urlWithLinkCode *string, userInputCode *string, email string) string {
+func getPasswordlessLoginEmailHTML(appName string, subject string, codeLifetime uint64, urlWithLinkCode *string, userInputCode *string, email string) string {
 	var emailBody string
 
 	if urlWithLinkCode != nil && userInputCode != nil {
@@ -2865,7 +2866,7 @@ func getPasswordlessLoginEmailHTML(appName string, codeLifetime uint64, urlWithL
 
 	humanisedCodeLifetime := supertokens.HumaniseMilliseconds(codeLifetime)
 
-	emailBody = strings.Replace(emailBody, "*|MC:SUBJECT|*", "Login to your account", -1)
+	emailBody = strings.Replace(emailBody, "*|MC:SUBJECT|*", subject, -1)
 	emailBody = strings.Replace(emailBody, "${appname}", appName, -1)
 	emailBody = strings.Replace(emailBody, "${toEmail}", email, -1)
 	emailBody = strings.Replace(emailBody, "${time}", humanisedCodeLifetime, -1)
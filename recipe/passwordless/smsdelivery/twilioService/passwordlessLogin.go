@@ -23,45 +23,32 @@ import (
 	"github.com/supertokens/supertokens-golang/supertokens"
 )
 
-const magicLinkLoginTemplate = `Click ${magicLink} to login to ${appname}
-
-This is valid for ${time}.`
-const otpLoginTemplate = `OTP to login is ${otp} for ${appname}
-
-This is valid for ${time}.`
-const magicLinkAndOtpLoginTemplate = `OTP to login is ${otp} for ${appname}
-
-Or click ${magicLink} to login.
-
-This is valid for ${time}.`
-
-func getPasswordlessLoginSmsContent(input smsdelivery.PasswordlessLoginType) smsdelivery.SMSContent {
+func getPasswordlessLoginSmsContent(input smsdelivery.PasswordlessLoginType, userContext supertokens.UserContext) smsdelivery.SMSContent {
 	stInstance, err := supertokens.GetInstanceOrThrowError()
 	if err != nil {
 		panic("Please call supertokens.Init function before using the Middleware")
 	}
 	return smsdelivery.SMSContent{
-		Body:          getPasswordlessLoginSmsBody(stInstance.AppInfo.AppName, input.CodeLifetime, input.UrlWithLinkCode, input.UserInputCode),
+		Body:          getPasswordlessLoginSmsBody(stInstance.AppInfo.AppName, input.CodeLifetime, input.UrlWithLinkCode, input.UserInputCode, userContext),
 		ToPhoneNumber: input.PhoneNumber,
 	}
 }
 
-func getPasswordlessLoginSmsBody(appName string, codeLifetime uint64, urlWithLinkCode *string, userInputCode *string) string {
+func getPasswordlessLoginSmsBody(appName string, codeLifetime uint64, urlWithLinkCode *string, userInputCode *string, userContext supertokens.UserContext) string {
 	var smsBody string
 
 	if urlWithLinkCode != nil && userInputCode != nil {
-		smsBody = magicLinkAndOtpLoginTemplate
+		smsBody = supertokens.Translate(supertokens.MessagePasswordlessSMSMagicLinkOTP, userContext)
 	} else if urlWithLinkCode != nil {
-		smsBody = magicLinkLoginTemplate
+		smsBody = supertokens.Translate(supertokens.MessagePasswordlessSMSMagicLink, userContext)
 	} else if userInputCode != nil {
-		smsBody = otpLoginTemplate
+		smsBody = supertokens.Translate(supertokens.MessagePasswordlessSMSOTP, userContext)
 	} else {
 		// Should never come here
 	}
 
 	humanisedCodeLifetime := supertokens.HumaniseMilliseconds(codeLifetime)
 
-	smsBody = strings.Replace(smsBody, "*|MC:SUBJECT|*", "Login to your account", -1)
 	smsBody = strings.Replace(smsBody, "${appname}", appName, -1)
 	smsBody = strings.Replace(smsBody, "${time}", humanisedCodeLifetime, -1)
 	if urlWithLinkCode != nil {
@@ -159,7 +159,7 @@ func DefaultValidateEmailAddress(value interface{}, tenantId string) *string {
 	}
 	check, err := regexp.Match(`^(([^<>()\[\]\\.,;:\s@"]+(\.[^<>()\[\]\\.,;:\s@"]+)*)|(".+"))@((\[[0-9]{1,3}\.[0-9]{1,3}\.[0-9]{1,3}\.[0-9]{1,3}\])|(([a-zA-Z\-0-9]+\.)+[a-zA-Z]{2,}))$`, []byte(value.(string)))
 	if err != nil || !check {
-		msg := "Email is invalid"
+		msg := supertokens.DefaultMessages[supertokens.MessageEmailInvalid]
 		return &msg
 	}
 	return nil
@@ -173,11 +173,11 @@ func DefaultValidatePhoneNumber(value interface{}, tenantId string) *string {
 
 	parsedPhoneNumber, err := phonenumbers.Parse(value.(string), "")
 	if err != nil {
-		msg := "Phone number is invalid"
+		msg := supertokens.DefaultMessages[supertokens.MessagePhoneNumberInvalid]
 		return &msg
 	}
 	if !phonenumbers.IsValidNumber(parsedPhoneNumber) {
-		msg := "Phone number is invalid"
+		msg := supertokens.DefaultMessages[supertokens.MessagePhoneNumberInvalid]
 		return &msg
 	}
 	return nil
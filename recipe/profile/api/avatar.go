@@ -0,0 +1,127 @@
+/* Copyright (c) 2023, VRAI Labs and/or its affiliates. All rights reserved.
+ *
+ * This software is licensed under the Apache License, Version 2.0 (the
+ * "License") as published by the Apache Software Foundation.
+ *
+ * You may not use this file except in compliance with the License. You may
+ * obtain a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+ * License for the specific language governing permissions and limitations
+ * under the License.
+ */
+
+package api
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/supertokens/supertokens-golang/recipe/profile/profilemodels"
+	"github.com/supertokens/supertokens-golang/recipe/session"
+	"github.com/supertokens/supertokens-golang/supertokens"
+)
+
+func Avatar(apiImplementation profilemodels.APIInterface, options profilemodels.APIOptions, userContext supertokens.UserContext) error {
+	if options.Req.Method == http.MethodPost {
+		return avatarPOST(apiImplementation, options, userContext)
+	}
+	return avatarGET(apiImplementation, options, userContext)
+}
+
+func avatarPOST(apiImplementation profilemodels.APIInterface, options profilemodels.APIOptions, userContext supertokens.UserContext) error {
+	if apiImplementation.AvatarPOST == nil ||
+		(*apiImplementation.AvatarPOST) == nil {
+		options.OtherHandler(options.Res, options.Req)
+		return nil
+	}
+
+	sessionContainer, err := session.GetSession(options.Req, options.Res, nil, userContext)
+	if err != nil {
+		return err
+	}
+
+	// we read one byte more than allowed so that oversized avatars are
+	// rejected by validation instead of being silently truncated
+	data, err := io.ReadAll(io.LimitReader(options.Req.Body, int64(options.Config.MaxAvatarSizeInBytes)+1))
+	if err != nil {
+		return err
+	}
+
+	response, err := (*apiImplementation.AvatarPOST)(profilemodels.Avatar{
+		ContentType: http.DetectContentType(data),
+		Data:        data,
+	}, sessionContainer, options, userContext)
+	if err != nil {
+		return err
+	}
+
+	var result map[string]interface{}
+	if response.OK != nil {
+		result = map[string]interface{}{
+			"status":    "OK",
+			"avatarUrl": response.OK.AvatarURL,
+		}
+	} else if response.InvalidAvatarError != nil {
+		result = map[string]interface{}{
+			"status": "INVALID_AVATAR_ERROR",
+			"reason": response.InvalidAvatarError.Reason,
+		}
+	} else if response.GeneralError != nil {
+		result = supertokens.ConvertGeneralErrorToJsonResponse(*response.GeneralError)
+	} else {
+		return supertokens.ErrorIfNoResponse(options.Res)
+	}
+	return supertokens.Send200Response(options.Res, result)
+}
+
+func avatarGET(apiImplementation profilemodels.APIInterface, options profilemodels.APIOptions, userContext supertokens.UserContext) error {
+	if apiImplementation.AvatarGET == nil ||
+		(*apiImplementation.AvatarGET) == nil {
+		options.OtherHandler(options.Res, options.Req)
+		return nil
+	}
+
+	userID := options.Req.URL.Query().Get("userId")
+	if userID == "" {
+		return supertokens.BadInputError{Msg: "Please provide the userId as a GET param"}
+	}
+
+	response, err := (*apiImplementation.AvatarGET)(userID, options, userContext)
+	if err != nil {
+		return err
+	}
+
+	if response.OK != nil {
+		avatar := response.OK.Avatar
+		hash := sha256.Sum256(avatar.Data)
+		etag := `"` + hex.EncodeToString(hash[:]) + `"`
+
+		options.Res.Header().Set("Cache-Control", fmt.Sprintf("public, max-age=%d", options.Config.CacheMaxAgeInSeconds))
+		options.Res.Header().Set("ETag", etag)
+		if options.Req.Header.Get("If-None-Match") == etag {
+			options.Res.WriteHeader(http.StatusNotModified)
+			return nil
+		}
+
+		contentType := avatar.ContentType
+		if contentType == "" {
+			contentType = http.DetectContentType(avatar.Data)
+		}
+		options.Res.Header().Set("Content-Type", contentType)
+		options.Res.Header().Set("X-Content-Type-Options", "nosniff")
+		options.Res.WriteHeader(http.StatusOK)
+		_, err = options.Res.Write(avatar.Data)
+		return err
+	} else if response.UnknownAvatarError != nil {
+		return supertokens.SendNon200ResponseWithMessage(options.Res, "Avatar not found", http.StatusNotFound)
+	} else if response.GeneralError != nil {
+		return supertokens.Send200Response(options.Res, supertokens.ConvertGeneralErrorToJsonResponse(*response.GeneralError))
+	}
+	return supertokens.ErrorIfNoResponse(options.Res)
+}
@@ -0,0 +1,52 @@
+/* Copyright (c) 2023, VRAI Labs and/or its affiliates. All rights reserved.
+ *
+ * This software is licensed under the Apache License, Version 2.0 (the
+ * "License") as published by the Apache Software Foundation.
+ *
+ * You may not use this file except in compliance with the License. You may
+ * obtain a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+ * License for the specific language governing permissions and limitations
+ * under the License.
+ */
+
+package api
+
+import (
+	"github.com/supertokens/supertokens-golang/recipe/profile/profilemodels"
+	"github.com/supertokens/supertokens-golang/recipe/session/sessmodels"
+	"github.com/supertokens/supertokens-golang/supertokens"
+)
+
+func MakeAPIImplementation() profilemodels.APIInterface {
+	avatarPOST := func(avatar profilemodels.Avatar, sessionContainer sessmodels.SessionContainer, options profilemodels.APIOptions, userContext supertokens.UserContext) (profilemodels.AvatarPOSTResponse, error) {
+		userID := sessionContainer.GetUserIDWithContext(userContext)
+		response, err := (*options.RecipeImplementation.UpdateAvatar)(userID, avatar, userContext)
+		if err != nil {
+			return profilemodels.AvatarPOSTResponse{}, err
+		}
+		return profilemodels.AvatarPOSTResponse{
+			OK:                 response.OK,
+			InvalidAvatarError: response.InvalidAvatarError,
+		}, nil
+	}
+
+	avatarGET := func(userID string, options profilemodels.APIOptions, userContext supertokens.UserContext) (profilemodels.AvatarGETResponse, error) {
+		response, err := (*options.RecipeImplementation.GetAvatar)(userID, userContext)
+		if err != nil {
+			return profilemodels.AvatarGETResponse{}, err
+		}
+		return profilemodels.AvatarGETResponse{
+			OK:                 response.OK,
+			UnknownAvatarError: response.UnknownAvatarError,
+		}, nil
+	}
+
+	return profilemodels.APIInterface{
+		AvatarPOST: &avatarPOST,
+		AvatarGET:  &avatarGET,
+	}
+}
@@ -0,0 +1,192 @@
+/* Copyright (c) 2023, VRAI Labs and/or its affiliates. All rights reserved.
+ *
+ * This software is licensed under the Apache License, Version 2.0 (the
+ * "License") as published by the Apache Software Foundation.
+ *
+ * You may not use this file except in compliance with the License. You may
+ * obtain a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+ * License for the specific language governing permissions and limitations
+ * under the License.
+ */
+
+package profile
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/supertokens/supertokens-golang/recipe/profile/avatarstorage/localStorage"
+	"github.com/supertokens/supertokens-golang/recipe/profile/profilemodels"
+	"github.com/supertokens/supertokens-golang/recipe/session"
+	"github.com/supertokens/supertokens-golang/recipe/session/sessmodels"
+	"github.com/supertokens/supertokens-golang/recipe/usermetadata"
+	"github.com/supertokens/supertokens-golang/supertokens"
+	"github.com/supertokens/supertokens-golang/test/unittesting"
+)
+
+func TestAvatarUploadAndDownload(t *testing.T) {
+	testServer := setUpAvatarTestServer(t)
+	defer AfterEach()
+	defer testServer.Close()
+
+	accessToken := createTestSession(t, testServer.URL, "userId")
+
+	avatar := append(pngHeader, []byte("avatar")...)
+	req, err := http.NewRequest(http.MethodPost, testServer.URL+"/auth/user/avatar", bytes.NewReader(avatar))
+	assert.NoError(t, err)
+	req.Header.Set("Authorization", "Bearer "+accessToken)
+	req.Header.Set("Content-Type", "image/png")
+	res, err := http.DefaultClient.Do(req)
+	assert.NoError(t, err)
+	assert.Equal(t, 200, res.StatusCode)
+
+	var response map[string]interface{}
+	err = json.NewDecoder(res.Body).Decode(&response)
+	res.Body.Close()
+	assert.NoError(t, err)
+	assert.Equal(t, "OK", response["status"])
+	avatarURL := response["avatarUrl"].(string)
+	assert.Contains(t, avatarURL, "/auth/user/avatar?userId=userId&v=")
+
+	metadata, err := usermetadata.GetUserMetadata("userId")
+	assert.NoError(t, err)
+	assert.Equal(t, avatarURL, metadata[avatarURLMetadataKey])
+
+	res, err = http.Get(testServer.URL + "/auth/user/avatar?userId=userId")
+	assert.NoError(t, err)
+	data, err := io.ReadAll(res.Body)
+	res.Body.Close()
+	assert.NoError(t, err)
+	assert.Equal(t, 200, res.StatusCode)
+	assert.Equal(t, avatar, data)
+	assert.Equal(t, "image/png", res.Header.Get("Content-Type"))
+	assert.Equal(t, "nosniff", res.Header.Get("X-Content-Type-Options"))
+	assert.Equal(t, "public, max-age=60", res.Header.Get("Cache-Control"))
+
+	etag := res.Header.Get("ETag")
+	assert.NotEmpty(t, etag)
+
+	req, err = http.NewRequest(http.MethodGet, testServer.URL+"/auth/user/avatar?userId=userId", nil)
+	assert.NoError(t, err)
+	req.Header.Set("If-None-Match", etag)
+	res, err = http.DefaultClient.Do(req)
+	assert.NoError(t, err)
+	data, err = io.ReadAll(res.Body)
+	res.Body.Close()
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusNotModified, res.StatusCode)
+	assert.Empty(t, data)
+	assert.Equal(t, etag, res.Header.Get("ETag"))
+}
+
+func TestAvatarUploadRequiresSession(t *testing.T) {
+	testServer := setUpAvatarTestServer(t)
+	defer AfterEach()
+	defer testServer.Close()
+
+	res, err := http.Post(testServer.URL+"/auth/user/avatar", "image/png", bytes.NewReader(pngHeader))
+	assert.NoError(t, err)
+	res.Body.Close()
+	assert.Equal(t, 401, res.StatusCode)
+}
+
+func TestInvalidAvatarIsRejected(t *testing.T) {
+	testServer := setUpAvatarTestServer(t)
+	defer AfterEach()
+	defer testServer.Close()
+
+	accessToken := createTestSession(t, testServer.URL, "userId")
+
+	req, err := http.NewRequest(http.MethodPost, testServer.URL+"/auth/user/avatar", bytes.NewReader([]byte("<html></html>")))
+	assert.NoError(t, err)
+	req.Header.Set("Authorization", "Bearer "+accessToken)
+	req.Header.Set("Content-Type", "image/png")
+	res, err := http.DefaultClient.Do(req)
+	assert.NoError(t, err)
+
+	var response map[string]interface{}
+	err = json.NewDecoder(res.Body).Decode(&response)
+	res.Body.Close()
+	assert.NoError(t, err)
+	assert.Equal(t, 200, res.StatusCode)
+	assert.Equal(t, "INVALID_AVATAR_ERROR", response["status"])
+
+	metadata, err := usermetadata.GetUserMetadata("userId")
+	assert.NoError(t, err)
+	assert.Nil(t, metadata[avatarURLMetadataKey])
+}
+
+func TestUnknownAvatarReturns404(t *testing.T) {
+	testServer := setUpAvatarTestServer(t)
+	defer AfterEach()
+	defer testServer.Close()
+
+	res, err := http.Get(testServer.URL + "/auth/user/avatar?userId=unknown")
+	assert.NoError(t, err)
+	res.Body.Close()
+	assert.Equal(t, http.StatusNotFound, res.StatusCode)
+
+	res, err = http.Get(testServer.URL + "/auth/user/avatar")
+	assert.NoError(t, err)
+	res.Body.Close()
+	assert.Equal(t, http.StatusBadRequest, res.StatusCode)
+}
+
+func setUpAvatarTestServer(t *testing.T) *httptest.Server {
+	cacheMaxAge := uint64(60)
+	configValue := supertokens.TypeInput{
+		Supertokens: &supertokens.ConnectionInfo{
+			ConnectionURI: "http://localhost:8080",
+		},
+		AppInfo: supertokens.AppInfo{
+			APIDomain:     "api.supertokens.io",
+			AppName:       "SuperTokens",
+			WebsiteDomain: "supertokens.io",
+		},
+		RecipeList: []supertokens.Recipe{
+			session.Init(&sessmodels.TypeInput{
+				GetTokenTransferMethod: func(req *http.Request, forCreateNewSession bool, userContext supertokens.UserContext) sessmodels.TokenTransferMethod {
+					return sessmodels.HeaderTransferMethod
+				},
+			}),
+			usermetadata.Init(nil),
+			Init(profilemodels.TypeInput{
+				Storage:              localStorage.MakeLocalStorage(t.TempDir()),
+				CacheMaxAgeInSeconds: &cacheMaxAge,
+			}),
+		},
+	}
+
+	BeforeEach()
+	unittesting.StartUpST("localhost", "8080")
+	err := supertokens.Init(configValue)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/create", func(rw http.ResponseWriter, r *http.Request) {
+		_, err := session.CreateNewSession(r, rw, "public", r.URL.Query().Get("userId"), map[string]interface{}{}, map[string]interface{}{})
+		if err != nil {
+			rw.WriteHeader(500)
+		}
+	})
+	return httptest.NewServer(supertokens.Middleware(mux))
+}
+
+func createTestSession(t *testing.T, serverURL string, userID string) string {
+	res, err := http.Post(serverURL+"/create?userId="+userID, "application/json", nil)
+	assert.NoError(t, err)
+	res.Body.Close()
+	assert.Equal(t, 200, res.StatusCode)
+	return res.Header.Get("st-access-token")
+}
@@ -0,0 +1,88 @@
+/* Copyright (c) 2023, VRAI Labs and/or its affiliates. All rights reserved.
+ *
+ * This software is licensed under the Apache License, Version 2.0 (the
+ * "License") as published by the Apache Software Foundation.
+ *
+ * You may not use this file except in compliance with the License. You may
+ * obtain a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+ * License for the specific language governing permissions and limitations
+ * under the License.
+ */
+
+package localStorage
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"net/http"
+	"os"
+	"path/filepath"
+
+	"github.com/supertokens/supertokens-golang/recipe/profile/profilemodels"
+	"github.com/supertokens/supertokens-golang/supertokens"
+)
+
+// MakeLocalStorage stores avatars as files in directory, one file per user.
+// It is meant for development and single instance deployments; use an object
+// store backed implementation when running more than one API server.
+func MakeLocalStorage(directory string) *profilemodels.AvatarStorage {
+	getFilePath := func(userID string) string {
+		// the user ID is hashed so that it can never escape the directory
+		hash := sha256.Sum256([]byte(userID))
+		return filepath.Join(directory, hex.EncodeToString(hash[:]))
+	}
+
+	storeAvatar := func(userID string, avatar profilemodels.Avatar, userContext supertokens.UserContext) (string, error) {
+		err := os.MkdirAll(directory, 0755)
+		if err != nil {
+			return "", err
+		}
+
+		tmpFile, err := os.CreateTemp(directory, ".avatar-*")
+		if err != nil {
+			return "", err
+		}
+		defer os.Remove(tmpFile.Name())
+
+		_, err = tmpFile.Write(avatar.Data)
+		if closeErr := tmpFile.Close(); err == nil {
+			err = closeErr
+		}
+		if err != nil {
+			return "", err
+		}
+
+		// rename is atomic, so readers never see a partially written avatar
+		return "", os.Rename(tmpFile.Name(), getFilePath(userID))
+	}
+
+	getAvatar := func(userID string, userContext supertokens.UserContext) (profilemodels.GetAvatarResponse, error) {
+		data, err := os.ReadFile(getFilePath(userID))
+		if err != nil {
+			if errors.Is(err, os.ErrNotExist) {
+				return profilemodels.GetAvatarResponse{
+					UnknownAvatarError: &struct{}{},
+				}, nil
+			}
+			return profilemodels.GetAvatarResponse{}, err
+		}
+		return profilemodels.GetAvatarResponse{
+			OK: &struct{ Avatar profilemodels.Avatar }{
+				Avatar: profilemodels.Avatar{
+					ContentType: http.DetectContentType(data),
+					Data:        data,
+				},
+			},
+		}, nil
+	}
+
+	return &profilemodels.AvatarStorage{
+		StoreAvatar: &storeAvatar,
+		GetAvatar:   &getAvatar,
+	}
+}
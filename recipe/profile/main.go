@@ -0,0 +1,47 @@
+/* Copyright (c) 2023, VRAI Labs and/or its affiliates. All rights reserved.
+ *
+ * This software is licensed under the Apache License, Version 2.0 (the
+ * "License") as published by the Apache Software Foundation.
+ *
+ * You may not use this file except in compliance with the License. You may
+ * obtain a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+ * License for the specific language governing permissions and limitations
+ * under the License.
+ */
+
+package profile
+
+import (
+	"github.com/supertokens/supertokens-golang/recipe/profile/profilemodels"
+	"github.com/supertokens/supertokens-golang/supertokens"
+)
+
+func Init(config profilemodels.TypeInput) supertokens.Recipe {
+	return recipeInit(config)
+}
+
+func UpdateAvatar(userID string, avatar profilemodels.Avatar, userContext ...supertokens.UserContext) (profilemodels.UpdateAvatarResponse, error) {
+	instance, err := getRecipeInstanceOrThrowError()
+	if err != nil {
+		return profilemodels.UpdateAvatarResponse{}, err
+	}
+	if len(userContext) == 0 {
+		userContext = append(userContext, &map[string]interface{}{})
+	}
+	return (*instance.RecipeImpl.UpdateAvatar)(userID, avatar, userContext[0])
+}
+
+func GetAvatar(userID string, userContext ...supertokens.UserContext) (profilemodels.GetAvatarResponse, error) {
+	instance, err := getRecipeInstanceOrThrowError()
+	if err != nil {
+		return profilemodels.GetAvatarResponse{}, err
+	}
+	if len(userContext) == 0 {
+		userContext = append(userContext, &map[string]interface{}{})
+	}
+	return (*instance.RecipeImpl.GetAvatar)(userID, userContext[0])
+}
@@ -0,0 +1,74 @@
+/* Copyright (c) 2023, VRAI Labs and/or its affiliates. All rights reserved.
+ *
+ * This software is licensed under the Apache License, Version 2.0 (the
+ * "License") as published by the Apache Software Foundation.
+ *
+ * You may not use this file except in compliance with the License. You may
+ * obtain a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+ * License for the specific language governing permissions and limitations
+ * under the License.
+ */
+
+package profile
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/supertokens/supertokens-golang/recipe/profile/avatarstorage/localStorage"
+	"github.com/supertokens/supertokens-golang/recipe/profile/profilemodels"
+	"github.com/supertokens/supertokens-golang/supertokens"
+)
+
+var pngHeader = []byte("\x89PNG\x0D\x0A\x1A\x0A")
+
+func TestThatStorageIsRequired(t *testing.T) {
+	_, err := validateAndNormaliseUserInput(supertokens.NormalisedAppinfo{}, profilemodels.TypeInput{})
+	assert.Error(t, err)
+}
+
+func TestAvatarValidation(t *testing.T) {
+	config, err := validateAndNormaliseUserInput(supertokens.NormalisedAppinfo{}, profilemodels.TypeInput{
+		Storage:              localStorage.MakeLocalStorage(t.TempDir()),
+		MaxAvatarSizeInBytes: 64,
+	})
+	assert.NoError(t, err)
+
+	assert.Equal(t, "", validateAvatar(config, profilemodels.Avatar{Data: pngHeader}))
+	assert.Equal(t, "Avatar is empty", validateAvatar(config, profilemodels.Avatar{}))
+	assert.Equal(t, "Avatar must be at most 64 bytes", validateAvatar(config, profilemodels.Avatar{Data: append(pngHeader, bytes.Repeat([]byte{0}, 64)...)}))
+
+	// the claimed content type is ignored, only the sniffed one counts
+	assert.Equal(t, "Avatar content type text/html; charset=utf-8 is not allowed", validateAvatar(config, profilemodels.Avatar{
+		ContentType: "image/png",
+		Data:        []byte("<html><script>alert(1)</script></html>"),
+	}))
+}
+
+func TestLocalStorageRoundTrip(t *testing.T) {
+	storage := localStorage.MakeLocalStorage(t.TempDir())
+	userContext := &map[string]interface{}{}
+
+	res, err := (*storage.GetAvatar)("userId", userContext)
+	assert.NoError(t, err)
+	assert.NotNil(t, res.UnknownAvatarError)
+
+	avatarURL, err := (*storage.StoreAvatar)("userId", profilemodels.Avatar{Data: pngHeader}, userContext)
+	assert.NoError(t, err)
+	assert.Equal(t, "", avatarURL)
+
+	res, err = (*storage.GetAvatar)("userId", userContext)
+	assert.NoError(t, err)
+	assert.NotNil(t, res.OK)
+	assert.Equal(t, "image/png", res.OK.Avatar.ContentType)
+	assert.Equal(t, pngHeader, res.OK.Avatar.Data)
+
+	res, err = (*storage.GetAvatar)("../userId", userContext)
+	assert.NoError(t, err)
+	assert.NotNil(t, res.UnknownAvatarError)
+}
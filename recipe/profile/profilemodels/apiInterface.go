@@ -0,0 +1,56 @@
+/* Copyright (c) 2023, VRAI Labs and/or its affiliates. All rights reserved.
+ *
+ * This software is licensed under the Apache License, Version 2.0 (the
+ * "License") as published by the Apache Software Foundation.
+ *
+ * You may not use this file except in compliance with the License. You may
+ * obtain a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+ * License for the specific language governing permissions and limitations
+ * under the License.
+ */
+
+package profilemodels
+
+import (
+	"net/http"
+
+	"github.com/supertokens/supertokens-golang/recipe/session/sessmodels"
+	"github.com/supertokens/supertokens-golang/supertokens"
+)
+
+type APIOptions struct {
+	RecipeImplementation RecipeInterface
+	AppInfo              supertokens.NormalisedAppinfo
+	Config               TypeNormalisedInput
+	RecipeID             string
+	Req                  *http.Request
+	Res                  http.ResponseWriter
+	OtherHandler         http.HandlerFunc
+}
+
+type APIInterface struct {
+	AvatarPOST *func(avatar Avatar, sessionContainer sessmodels.SessionContainer, options APIOptions, userContext supertokens.UserContext) (AvatarPOSTResponse, error)
+	AvatarGET  *func(userID string, options APIOptions, userContext supertokens.UserContext) (AvatarGETResponse, error)
+}
+
+type AvatarPOSTResponse struct {
+	OK *struct {
+		AvatarURL string
+	}
+	InvalidAvatarError *struct {
+		Reason string
+	}
+	GeneralError *supertokens.GeneralErrorResponse
+}
+
+type AvatarGETResponse struct {
+	OK *struct {
+		Avatar Avatar
+	}
+	UnknownAvatarError *struct{}
+	GeneralError       *supertokens.GeneralErrorResponse
+}
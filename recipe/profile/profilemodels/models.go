@@ -0,0 +1,71 @@
+/* Copyright (c) 2023, VRAI Labs and/or its affiliates. All rights reserved.
+ *
+ * This software is licensed under the Apache License, Version 2.0 (the
+ * "License") as published by the Apache Software Foundation.
+ *
+ * You may not use this file except in compliance with the License. You may
+ * obtain a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+ * License for the specific language governing permissions and limitations
+ * under the License.
+ */
+
+package profilemodels
+
+import "github.com/supertokens/supertokens-golang/supertokens"
+
+// Avatar is an uploaded profile picture. ContentType is the sniffed MIME type
+// of Data, not the one claimed by the client.
+type Avatar struct {
+	ContentType string
+	Data        []byte
+}
+
+// AvatarStorage lets users plug in where avatars are kept (local disk, S3, GCS, ...).
+//
+// StoreAvatar may return the public URL of the stored avatar. If it returns an
+// empty string, the avatar is served by this recipe's GET /user/avatar API.
+type AvatarStorage struct {
+	StoreAvatar *func(userID string, avatar Avatar, userContext supertokens.UserContext) (string, error)
+	GetAvatar   *func(userID string, userContext supertokens.UserContext) (GetAvatarResponse, error)
+}
+
+type TypeInput struct {
+	Storage              *AvatarStorage
+	MaxAvatarSizeInBytes int
+	AllowedContentTypes  []string
+	CacheMaxAgeInSeconds *uint64
+	Override             *OverrideStruct
+}
+
+type TypeNormalisedInput struct {
+	Storage              AvatarStorage
+	MaxAvatarSizeInBytes int
+	AllowedContentTypes  []string
+	CacheMaxAgeInSeconds uint64
+	Override             OverrideStruct
+}
+
+type OverrideStruct struct {
+	Functions func(originalImplementation RecipeInterface) RecipeInterface
+	APIs      func(originalImplementation APIInterface) APIInterface
+}
+
+type GetAvatarResponse struct {
+	OK *struct {
+		Avatar Avatar
+	}
+	UnknownAvatarError *struct{}
+}
+
+type UpdateAvatarResponse struct {
+	OK *struct {
+		AvatarURL string
+	}
+	InvalidAvatarError *struct {
+		Reason string
+	}
+}
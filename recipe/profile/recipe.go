@@ -0,0 +1,125 @@
+/* Copyright (c) 2023, VRAI Labs and/or its affiliates. All rights reserved.
+ *
+ * This software is licensed under the Apache License, Version 2.0 (the
+ * "License") as published by the Apache Software Foundation.
+ *
+ * You may not use this file except in compliance with the License. You may
+ * obtain a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+ * License for the specific language governing permissions and limitations
+ * under the License.
+ */
+
+package profile
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/supertokens/supertokens-golang/recipe/profile/api"
+	"github.com/supertokens/supertokens-golang/recipe/profile/profilemodels"
+	"github.com/supertokens/supertokens-golang/recipe/session"
+	"github.com/supertokens/supertokens-golang/recipe/usermetadata"
+	"github.com/supertokens/supertokens-golang/supertokens"
+)
+
+const RECIPE_ID = "profile"
+
+type Recipe struct {
+	RecipeModule supertokens.RecipeModule
+	Config       profilemodels.TypeNormalisedInput
+	RecipeImpl   profilemodels.RecipeInterface
+	APIImpl      profilemodels.APIInterface
+}
+
+var singletonInstance *Recipe
+
+func MakeRecipe(recipeId string, appInfo supertokens.NormalisedAppinfo, config profilemodels.TypeInput, onSuperTokensAPIError func(err error, req *http.Request, res http.ResponseWriter)) (Recipe, error) {
+	r := &Recipe{}
+	verifiedConfig, err := validateAndNormaliseUserInput(appInfo, config)
+	if err != nil {
+		return Recipe{}, err
+	}
+	r.Config = verifiedConfig
+	r.APIImpl = verifiedConfig.Override.APIs(api.MakeAPIImplementation())
+	r.RecipeImpl = verifiedConfig.Override.Functions(makeRecipeImplementation(verifiedConfig, appInfo))
+
+	recipeModuleInstance := supertokens.MakeRecipeModule(recipeId, appInfo, r.handleAPIRequest, r.getAllCORSHeaders, r.getAPIsHandled, nil, r.handleError, onSuperTokensAPIError)
+	r.RecipeModule = recipeModuleInstance
+
+	return *r, nil
+}
+
+func getRecipeInstanceOrThrowError() (*Recipe, error) {
+	if singletonInstance != nil {
+		return singletonInstance, nil
+	}
+	return nil, errors.New("Initialisation not done. Did you forget to call the init function?")
+}
+
+func recipeInit(config profilemodels.TypeInput) supertokens.Recipe {
+	return func(appInfo supertokens.NormalisedAppinfo, onSuperTokensAPIError func(err error, req *http.Request, res http.ResponseWriter)) (*supertokens.RecipeModule, error) {
+		if singletonInstance == nil {
+			recipe, err := MakeRecipe(RECIPE_ID, appInfo, config, onSuperTokensAPIError)
+			if err != nil {
+				return nil, err
+			}
+			singletonInstance = &recipe
+			singletonInstance.RecipeModule.DependsOn(session.RECIPE_ID, usermetadata.RECIPE_ID)
+			return &singletonInstance.RecipeModule, nil
+		}
+		return nil, errors.New("Profile recipe has already been initialised. Please check your code for bugs.")
+	}
+}
+
+// implement RecipeModule
+
+func (r *Recipe) getAPIsHandled() ([]supertokens.APIHandled, error) {
+	avatarAPINormalised, err := supertokens.NewNormalisedURLPath(AvatarAPI)
+	if err != nil {
+		return nil, err
+	}
+
+	return []supertokens.APIHandled{{
+		Method:                 http.MethodPost,
+		PathWithoutAPIBasePath: avatarAPINormalised,
+		ID:                     AvatarAPI,
+		Disabled:               r.APIImpl.AvatarPOST == nil,
+	}, {
+		Method:                 http.MethodGet,
+		PathWithoutAPIBasePath: avatarAPINormalised,
+		ID:                     AvatarAPI,
+		Disabled:               r.APIImpl.AvatarGET == nil,
+	}}, nil
+}
+
+func (r *Recipe) handleAPIRequest(id string, tenantId string, req *http.Request, res http.ResponseWriter, theirHandler http.HandlerFunc, _ supertokens.NormalisedURLPath, _ string, userContext supertokens.UserContext) error {
+	options := profilemodels.APIOptions{
+		Config:               r.Config,
+		RecipeID:             r.RecipeModule.GetRecipeID(),
+		RecipeImplementation: r.RecipeImpl,
+		AppInfo:              r.RecipeModule.GetAppInfo(),
+		Req:                  req,
+		Res:                  res,
+		OtherHandler:         theirHandler,
+	}
+	if id == AvatarAPI {
+		return api.Avatar(r.APIImpl, options, userContext)
+	}
+	return errors.New("should never come here")
+}
+
+func (r *Recipe) getAllCORSHeaders() []string {
+	return []string{}
+}
+
+func (r *Recipe) handleError(err error, req *http.Request, res http.ResponseWriter, userContext supertokens.UserContext) (bool, error) {
+	return false, nil
+}
+
+func ResetForTest() {
+	singletonInstance = nil
+}
@@ -0,0 +1,67 @@
+/* Copyright (c) 2023, VRAI Labs and/or its affiliates. All rights reserved.
+ *
+ * This software is licensed under the Apache License, Version 2.0 (the
+ * "License") as published by the Apache Software Foundation.
+ *
+ * You may not use this file except in compliance with the License. You may
+ * obtain a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+ * License for the specific language governing permissions and limitations
+ * under the License.
+ */
+
+package profile
+
+import (
+	"net/http"
+
+	"github.com/supertokens/supertokens-golang/recipe/profile/profilemodels"
+	"github.com/supertokens/supertokens-golang/recipe/usermetadata"
+	"github.com/supertokens/supertokens-golang/supertokens"
+)
+
+func makeRecipeImplementation(config profilemodels.TypeNormalisedInput, appInfo supertokens.NormalisedAppinfo) profilemodels.RecipeInterface {
+	updateAvatar := func(userID string, avatar profilemodels.Avatar, userContext supertokens.UserContext) (profilemodels.UpdateAvatarResponse, error) {
+		if reason := validateAvatar(config, avatar); reason != "" {
+			return profilemodels.UpdateAvatarResponse{
+				InvalidAvatarError: &struct{ Reason string }{
+					Reason: reason,
+				},
+			}, nil
+		}
+		avatar.ContentType = http.DetectContentType(avatar.Data)
+
+		avatarURL, err := (*config.Storage.StoreAvatar)(userID, avatar, userContext)
+		if err != nil {
+			return profilemodels.UpdateAvatarResponse{}, err
+		}
+		if avatarURL == "" {
+			avatarURL = getAvatarURL(appInfo, userID, avatar.Data)
+		}
+
+		_, err = usermetadata.UpdateUserMetadata(userID, map[string]interface{}{
+			avatarURLMetadataKey: avatarURL,
+		}, userContext)
+		if err != nil {
+			return profilemodels.UpdateAvatarResponse{}, err
+		}
+
+		return profilemodels.UpdateAvatarResponse{
+			OK: &struct{ AvatarURL string }{
+				AvatarURL: avatarURL,
+			},
+		}, nil
+	}
+
+	getAvatar := func(userID string, userContext supertokens.UserContext) (profilemodels.GetAvatarResponse, error) {
+		return (*config.Storage.GetAvatar)(userID, userContext)
+	}
+
+	return profilemodels.RecipeInterface{
+		UpdateAvatar: &updateAvatar,
+		GetAvatar:    &getAvatar,
+	}
+}
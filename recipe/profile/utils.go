@@ -0,0 +1,107 @@
+/* Copyright (c) 2023, VRAI Labs and/or its affiliates. All rights reserved.
+ *
+ * This software is licensed under the Apache License, Version 2.0 (the
+ * "License") as published by the Apache Software Foundation.
+ *
+ * You may not use this file except in compliance with the License. You may
+ * obtain a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+ * License for the specific language governing permissions and limitations
+ * under the License.
+ */
+
+package profile
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"github.com/supertokens/supertokens-golang/recipe/profile/profilemodels"
+	"github.com/supertokens/supertokens-golang/supertokens"
+)
+
+func validateAndNormaliseUserInput(appInfo supertokens.NormalisedAppinfo, config profilemodels.TypeInput) (profilemodels.TypeNormalisedInput, error) {
+	typeNormalisedInput := makeTypeNormalisedInput(appInfo)
+
+	if config.Storage == nil {
+		return profilemodels.TypeNormalisedInput{}, errors.New("please provide a Storage implementation for the profile recipe")
+	}
+	if config.Storage.StoreAvatar == nil || config.Storage.GetAvatar == nil {
+		return profilemodels.TypeNormalisedInput{}, errors.New("the profile recipe Storage must implement both StoreAvatar and GetAvatar")
+	}
+	typeNormalisedInput.Storage = *config.Storage
+
+	if config.MaxAvatarSizeInBytes < 0 {
+		return profilemodels.TypeNormalisedInput{}, errors.New("MaxAvatarSizeInBytes cannot be negative")
+	}
+	if config.MaxAvatarSizeInBytes != 0 {
+		typeNormalisedInput.MaxAvatarSizeInBytes = config.MaxAvatarSizeInBytes
+	}
+
+	if len(config.AllowedContentTypes) != 0 {
+		typeNormalisedInput.AllowedContentTypes = config.AllowedContentTypes
+	}
+
+	if config.CacheMaxAgeInSeconds != nil {
+		typeNormalisedInput.CacheMaxAgeInSeconds = *config.CacheMaxAgeInSeconds
+	}
+
+	if config.Override != nil {
+		if config.Override.Functions != nil {
+			typeNormalisedInput.Override.Functions = config.Override.Functions
+		}
+		if config.Override.APIs != nil {
+			typeNormalisedInput.Override.APIs = config.Override.APIs
+		}
+	}
+
+	return typeNormalisedInput, nil
+}
+
+func makeTypeNormalisedInput(appInfo supertokens.NormalisedAppinfo) profilemodels.TypeNormalisedInput {
+	return profilemodels.TypeNormalisedInput{
+		MaxAvatarSizeInBytes: defaultMaxAvatarSizeInBytes,
+		AllowedContentTypes:  defaultAllowedContentTypes,
+		CacheMaxAgeInSeconds: defaultCacheMaxAgeInSeconds,
+		Override: profilemodels.OverrideStruct{
+			Functions: func(originalImplementation profilemodels.RecipeInterface) profilemodels.RecipeInterface {
+				return originalImplementation
+			},
+			APIs: func(originalImplementation profilemodels.APIInterface) profilemodels.APIInterface {
+				return originalImplementation
+			},
+		},
+	}
+}
+
+// validateAvatar returns a non empty reason if the avatar should be rejected.
+// The content type is always sniffed from the data, so a client cannot upload
+// arbitrary content by lying in the Content-Type header.
+func validateAvatar(config profilemodels.TypeNormalisedInput, avatar profilemodels.Avatar) string {
+	if len(avatar.Data) == 0 {
+		return "Avatar is empty"
+	}
+	if len(avatar.Data) > config.MaxAvatarSizeInBytes {
+		return fmt.Sprintf("Avatar must be at most %d bytes", config.MaxAvatarSizeInBytes)
+	}
+	contentType := http.DetectContentType(avatar.Data)
+	if !supertokens.DoesSliceContainString(contentType, config.AllowedContentTypes) {
+		return "Avatar content type " + contentType + " is not allowed"
+	}
+	return ""
+}
+
+func getAvatarURL(appInfo supertokens.NormalisedAppinfo, userID string, data []byte) string {
+	// the hash acts as a version so that browsers fetch the new avatar after an update
+	hash := sha256.Sum256(data)
+	return appInfo.APIDomain.GetAsStringDangerous() +
+		appInfo.APIBasePath.GetAsStringDangerous() +
+		AvatarAPI + "?userId=" + url.QueryEscape(userID) + "&v=" + hex.EncodeToString(hash[:8])
+}
@@ -43,7 +43,7 @@ func GetInfoFromAccessToken(jwtInfo sessmodels.ParsedJWTInfo, jwks *keyfunc.JWKS
 	var payload map[string]interface{}
 
 	if jwtInfo.Version >= 3 {
-		parsedToken, parseError := jwt.Parse(jwtInfo.RawTokenString, jwks.Keyfunc)
+		parsedToken, parseError := jwt.Parse(jwtInfo.RawTokenString, jwks.Keyfunc, supertokens.GetJWTParserOptions()...)
 		if parseError != nil {
 			supertokens.LogDebugMessage(fmt.Sprintf("GetInfoFromAccessToken: Returning TryRefreshTokenError because access token parsing failed - %s", parseError))
 			return nil, sterrors.TryRefreshTokenError{
@@ -80,7 +80,7 @@ func GetInfoFromAccessToken(jwtInfo sessmodels.ParsedJWTInfo, jwks *keyfunc.JWKS
 			parsedToken, parseErr := jwt.Parse(jwtInfo.RawTokenString, func(token *jwt.Token) (interface{}, error) {
 				// The key returned here is used by Parse to verify the JWT
 				return key, nil
-			})
+			}, supertokens.GetJWTParserOptions()...)
 
 			if parseErr != nil && errors.Is(parseErr, jwt.ErrSignatureInvalid) {
 				continue
@@ -0,0 +1,96 @@
+/* Copyright (c) 2023, VRAI Labs and/or its affiliates. All rights reserved.
+ *
+ * This software is licensed under the Apache License, Version 2.0 (the
+ * "License") as published by the Apache Software Foundation.
+ *
+ * You may not use this file except in compliance with the License. You may
+ * obtain a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+ * License for the specific language governing permissions and limitations
+ * under the License.
+ */
+
+package session
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/supertokens/supertokens-golang/recipe/session/sessmodels"
+	"github.com/supertokens/supertokens-golang/supertokens"
+)
+
+func initWithAccessTokenClaims(t *testing.T, config *sessmodels.TypeInput) *map[string]interface{} {
+	var payloadSentToCore map[string]interface{}
+	config.Override = &sessmodels.OverrideStruct{
+		Functions: func(originalImplementation sessmodels.RecipeInterface) sessmodels.RecipeInterface {
+			createNewSession := func(userID string, accessTokenPayload map[string]interface{}, sessionDataInDatabase map[string]interface{}, disableAntiCsrf *bool, tenantId string, userContext supertokens.UserContext) (sessmodels.SessionContainer, error) {
+				payloadSentToCore = accessTokenPayload
+				return nil, errors.New("not creating the session")
+			}
+			originalImplementation.CreateNewSession = &createNewSession
+			return originalImplementation
+		},
+	}
+	err := supertokens.Init(supertokens.TypeInput{
+		Supertokens: &supertokens.ConnectionInfo{
+			ConnectionURI: "http://localhost:8080",
+		},
+		AppInfo: supertokens.AppInfo{
+			AppName:       "SuperTokens",
+			WebsiteDomain: "supertokens.io",
+			APIDomain:     "api.supertokens.io",
+		},
+		RecipeList: []supertokens.Recipe{Init(config)},
+	})
+	assert.NoError(t, err)
+	return &payloadSentToCore
+}
+
+func TestAccessTokensGetTheConfiguredIssuerAudienceAndStaticClaims(t *testing.T) {
+	resetAll()
+	defer resetAll()
+	issuer := "https://auth.example.com"
+	payload := initWithAccessTokenClaims(t, &sessmodels.TypeInput{
+		AccessTokenIssuer:       &issuer,
+		AccessTokenAudience:     []string{"billing", "reports"},
+		AccessTokenStaticClaims: map[string]interface{}{"env": "prod", "role": "user"},
+	})
+
+	_, err := CreateNewSessionWithoutRequestResponse("public", "user1", map[string]interface{}{"role": "admin", "iss": "someone else", "sub": "user2"}, nil, nil)
+	assert.EqualError(t, err, "not creating the session")
+	assert.Equal(t, map[string]interface{}{
+		"iss":  "https://auth.example.com",
+		"aud":  []string{"billing", "reports"},
+		"env":  "prod",
+		"role": "admin",
+	}, *payload)
+}
+
+func TestAccessTokensGetTheDefaultIssuerAndASingleAudienceAsAString(t *testing.T) {
+	resetAll()
+	defer resetAll()
+	payload := initWithAccessTokenClaims(t, &sessmodels.TypeInput{
+		AccessTokenAudience: []string{"billing"},
+	})
+
+	_, err := CreateNewSessionWithoutRequestResponse("public", "user1", nil, nil, nil)
+	assert.EqualError(t, err, "not creating the session")
+	assert.Equal(t, map[string]interface{}{
+		"iss": "https://api.supertokens.io/auth",
+		"aud": "billing",
+	}, *payload)
+}
+
+func TestStaticClaimsCannotOverrideReservedClaims(t *testing.T) {
+	for _, claim := range []string{"iss", "aud", "sub", "sessionHandle"} {
+		_, err := ValidateAndNormaliseUserInput(supertokens.NormalisedAppinfo{}, &sessmodels.TypeInput{
+			AccessTokenStaticClaims: map[string]interface{}{claim: "value"},
+		})
+		assert.EqualError(t, err, "AccessTokenStaticClaims cannot contain "+claim)
+	}
+}
@@ -0,0 +1,75 @@
+/* Copyright (c) 2023, VRAI Labs and/or its affiliates. All rights reserved.
+ *
+ * This software is licensed under the Apache License, Version 2.0 (the
+ * "License") as published by the Apache Software Foundation.
+ *
+ * You may not use this file except in compliance with the License. You may
+ * obtain a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+ * License for the specific language governing permissions and limitations
+ * under the License.
+ */
+
+package session
+
+import (
+	"errors"
+	"fmt"
+
+	sessionErrors "github.com/supertokens/supertokens-golang/recipe/session/errors"
+	"github.com/supertokens/supertokens-golang/recipe/session/sessmodels"
+	"github.com/supertokens/supertokens-golang/supertokens"
+)
+
+// defaultAccessTokenPayloadWarnAtBytes leaves room for the claims added by
+// SuperTokens, the JWT header and signature, and the base64 encoding of the
+// payload within the 4KB cookie limit of browsers
+const defaultAccessTokenPayloadWarnAtBytes = 2048
+
+func normaliseAccessTokenPayloadSizeInput(config *sessmodels.AccessTokenPayloadSizeInput) (sessmodels.NormalisedAccessTokenPayloadSizeConfig, error) {
+	result := sessmodels.NormalisedAccessTokenPayloadSizeConfig{
+		WarnAtBytes: defaultAccessTokenPayloadWarnAtBytes,
+		OnLargePayload: func(size int, userContext supertokens.UserContext) {
+			supertokens.LogDebugMessage(fmt.Sprintf("The access token payload is %d bytes, which may make the access token too large to be stored in a cookie", size))
+		},
+	}
+	if config == nil {
+		return result, nil
+	}
+	if config.WarnAtBytes != nil {
+		if *config.WarnAtBytes <= 0 {
+			return sessmodels.NormalisedAccessTokenPayloadSizeConfig{}, errors.New("AccessTokenPayloadSize.WarnAtBytes must be positive")
+		}
+		result.WarnAtBytes = *config.WarnAtBytes
+	}
+	if config.MaxBytes != nil {
+		if *config.MaxBytes < 0 {
+			return sessmodels.NormalisedAccessTokenPayloadSizeConfig{}, errors.New("AccessTokenPayloadSize.MaxBytes cannot be negative")
+		}
+		result.MaxBytes = *config.MaxBytes
+	}
+	if config.OnLargePayload != nil {
+		result.OnLargePayload = config.OnLargePayload
+	}
+	return result, nil
+}
+
+// checkAccessTokenPayloadSize is called before a payload is sent to the core
+// to create a session or update its access token
+func checkAccessTokenPayloadSize(config sessmodels.TypeNormalisedInput, accessTokenPayload map[string]interface{}, userContext supertokens.UserContext) error {
+	payloadJSON, err := supertokens.JSONMarshal(accessTokenPayload)
+	if err != nil {
+		return err
+	}
+	size := len(payloadJSON)
+	if config.AccessTokenPayloadSize.MaxBytes > 0 && size > config.AccessTokenPayloadSize.MaxBytes {
+		return sessionErrors.PayloadTooLargeError{Size: size, MaxSize: config.AccessTokenPayloadSize.MaxBytes}
+	}
+	if config.AccessTokenPayloadSize.WarnAtBytes > 0 && size > config.AccessTokenPayloadSize.WarnAtBytes && config.AccessTokenPayloadSize.OnLargePayload != nil {
+		config.AccessTokenPayloadSize.OnLargePayload(size, userContext)
+	}
+	return nil
+}
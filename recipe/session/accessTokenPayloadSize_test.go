@@ -0,0 +1,69 @@
+/* Copyright (c) 2023, VRAI Labs and/or its affiliates. All rights reserved.
+ *
+ * This software is licensed under the Apache License, Version 2.0 (the
+ * "License") as published by the Apache Software Foundation.
+ *
+ * You may not use this file except in compliance with the License. You may
+ * obtain a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+ * License for the specific language governing permissions and limitations
+ * under the License.
+ */
+
+package session
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	sessionErrors "github.com/supertokens/supertokens-golang/recipe/session/errors"
+	"github.com/supertokens/supertokens-golang/recipe/session/sessmodels"
+	"github.com/supertokens/supertokens-golang/supertokens"
+)
+
+func TestAccessTokenPayloadSizeDefaults(t *testing.T) {
+	config, err := normaliseAccessTokenPayloadSizeInput(nil)
+	assert.NoError(t, err)
+	assert.Equal(t, 2048, config.WarnAtBytes)
+	assert.Equal(t, 0, config.MaxBytes)
+	assert.NotNil(t, config.OnLargePayload)
+
+	zero := 0
+	_, err = normaliseAccessTokenPayloadSizeInput(&sessmodels.AccessTokenPayloadSizeInput{WarnAtBytes: &zero})
+	assert.Error(t, err)
+}
+
+func TestLargeAccessTokenPayloadsAreReportedAndRejected(t *testing.T) {
+	warnAtBytes := 100
+	maxBytes := 1000
+	reportedSizes := []int{}
+	accessTokenPayloadSize, err := normaliseAccessTokenPayloadSizeInput(&sessmodels.AccessTokenPayloadSizeInput{
+		WarnAtBytes: &warnAtBytes,
+		MaxBytes:    &maxBytes,
+		OnLargePayload: func(size int, userContext supertokens.UserContext) {
+			reportedSizes = append(reportedSizes, size)
+		},
+	})
+	assert.NoError(t, err)
+	config := sessmodels.TypeNormalisedInput{AccessTokenPayloadSize: accessTokenPayloadSize}
+	userContext := &map[string]interface{}{}
+
+	assert.NoError(t, checkAccessTokenPayloadSize(config, map[string]interface{}{"role": "admin"}, userContext))
+	assert.Empty(t, reportedSizes)
+
+	assert.NoError(t, checkAccessTokenPayloadSize(config, map[string]interface{}{"data": strings.Repeat("a", 200)}, userContext))
+	assert.Equal(t, []int{211}, reportedSizes)
+
+	err = checkAccessTokenPayloadSize(config, map[string]interface{}{"data": strings.Repeat("a", 2000)}, userContext)
+	assert.True(t, errors.Is(err, ErrPayloadTooLarge))
+	var payloadTooLargeErr sessionErrors.PayloadTooLargeError
+	assert.True(t, errors.As(err, &payloadTooLargeErr))
+	assert.Equal(t, 2011, payloadTooLargeErr.Size)
+	assert.Equal(t, 1000, payloadTooLargeErr.MaxSize)
+	assert.Len(t, reportedSizes, 1)
+}
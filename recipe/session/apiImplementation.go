@@ -51,6 +51,7 @@ func MakeAPIImplementation() sessmodels.APIInterface {
 					SessionRequired:               verifySessionOptions.SessionRequired,
 					CheckDatabase:                 verifySessionOptions.CheckDatabase,
 					OverrideGlobalClaimValidators: verifySessionOptions.OverrideGlobalClaimValidators,
+					Authorize:                     verifySessionOptions.Authorize,
 				}
 			}
 
@@ -73,6 +74,24 @@ func MakeAPIImplementation() sessmodels.APIInterface {
 			if err != nil {
 				return sessmodels.SignOutPOSTResponse{}, err
 			}
+			supertokens.EmitEvent(supertokens.Event{
+				Type:     supertokens.EventSignOut,
+				TenantId: sessionContainer.GetTenantIdWithContext(userContext),
+				UserID:   sessionContainer.GetUserIDWithContext(userContext),
+				Data: map[string]interface{}{
+					"sessionHandle": sessionContainer.GetHandleWithContext(userContext),
+				},
+			}, userContext)
+			supertokens.WriteAuditLog(supertokens.AuditEntry{
+				Action:   supertokens.AuditActionSignOut,
+				Result:   supertokens.AuditResultSuccess,
+				UserID:   sessionContainer.GetUserIDWithContext(userContext),
+				TenantId: sessionContainer.GetTenantIdWithContext(userContext),
+				RecipeID: RECIPE_ID,
+				Data: map[string]interface{}{
+					"sessionHandle": sessionContainer.GetHandleWithContext(userContext),
+				},
+			}, userContext)
 		}
 
 		return sessmodels.SignOutPOSTResponse{
@@ -80,9 +99,54 @@ func MakeAPIImplementation() sessmodels.APIInterface {
 		}, nil
 	}
 
+	userSessionsGET := func(sessionContainer sessmodels.SessionContainer, options sessmodels.APIOptions, userContext supertokens.UserContext) (sessmodels.UserSessionsGETResponse, error) {
+		sessions, err := listUserSessions(options.RecipeImplementation, sessionContainer.GetUserIDWithContext(userContext), sessionContainer.GetTenantIdWithContext(userContext), true, sessionContainer.GetHandleWithContext(userContext), userContext)
+		if err != nil {
+			return sessmodels.UserSessionsGETResponse{}, err
+		}
+		return sessmodels.UserSessionsGETResponse{
+			OK: &struct{ Sessions []sessmodels.UserSession }{
+				Sessions: sessions,
+			},
+		}, nil
+	}
+
+	revokeUserSessionPOST := func(sessionHandle string, sessionContainer sessmodels.SessionContainer, options sessmodels.APIOptions, userContext supertokens.UserContext) (sessmodels.RevokeUserSessionPOSTResponse, error) {
+		userID := sessionContainer.GetUserIDWithContext(userContext)
+		sessionInfo, err := (*options.RecipeImplementation.GetSessionInformation)(sessionHandle, userContext)
+		if err != nil {
+			return sessmodels.RevokeUserSessionPOSTResponse{}, err
+		}
+		if sessionInfo == nil || sessionInfo.UserId != userID {
+			return sessmodels.RevokeUserSessionPOSTResponse{
+				UnknownSessionError: &struct{}{},
+			}, nil
+		}
+		_, err = (*options.RecipeImplementation.RevokeSession)(sessionHandle, userContext)
+		if err != nil {
+			return sessmodels.RevokeUserSessionPOSTResponse{}, err
+		}
+		supertokens.WriteAuditLog(supertokens.AuditEntry{
+			Action:   supertokens.AuditActionSignOut,
+			Result:   supertokens.AuditResultSuccess,
+			UserID:   userID,
+			TenantId: sessionInfo.TenantId,
+			RecipeID: RECIPE_ID,
+			Data: map[string]interface{}{
+				"sessionHandle":          sessionHandle,
+				"revokedBySessionHandle": sessionContainer.GetHandleWithContext(userContext),
+			},
+		}, userContext)
+		return sessmodels.RevokeUserSessionPOSTResponse{
+			OK: &struct{}{},
+		}, nil
+	}
+
 	return sessmodels.APIInterface{
-		RefreshPOST:   &refreshPOST,
-		VerifySession: &verifySession,
-		SignOutPOST:   &signOutPOST,
+		RefreshPOST:           &refreshPOST,
+		VerifySession:         &verifySession,
+		SignOutPOST:           &signOutPOST,
+		UserSessionsGET:       &userSessionsGET,
+		RevokeUserSessionPOST: &revokeUserSessionPOST,
 	}
 }
@@ -0,0 +1,73 @@
+/* Copyright (c) 2023, VRAI Labs and/or its affiliates. All rights reserved.
+ *
+ * This software is licensed under the Apache License, Version 2.0 (the
+ * "License") as published by the Apache Software Foundation.
+ *
+ * You may not use this file except in compliance with the License. You may
+ * obtain a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+ * License for the specific language governing permissions and limitations
+ * under the License.
+ */
+
+package session
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/supertokens/supertokens-golang/recipe/multitenancy/multitenancymodels"
+	"github.com/supertokens/supertokens-golang/recipe/session/claims"
+	"github.com/supertokens/supertokens-golang/supertokens"
+)
+
+// getClaimsWithMaxStaleness returns the claims that declare a max staleness,
+// out of the claims of the validators and the ones added by other recipes
+func getClaimsWithMaxStaleness(claimValidators []claims.SessionClaimValidator, claimsAddedByOtherRecipes []*claims.TypeSessionClaim) []*claims.TypeSessionClaim {
+	result := []*claims.TypeSessionClaim{}
+	seenKeys := map[string]bool{}
+	addClaim := func(claim *claims.TypeSessionClaim) {
+		if claim == nil || claim.MaxStalenessInSeconds == nil || claim.GetLastRefetchTime == nil || seenKeys[claim.Key] {
+			return
+		}
+		seenKeys[claim.Key] = true
+		result = append(result, claim)
+	}
+	for _, validator := range claimValidators {
+		addClaim(validator.Claim)
+	}
+	for _, claim := range claimsAddedByOtherRecipes {
+		addClaim(claim)
+	}
+	return result
+}
+
+// refreshStaleClaims refetches the claims in the access token payload that
+// were fetched longer ago than their max staleness. Claims missing from the
+// payload are not added.
+func refreshStaleClaims(userId string, accessTokenPayload map[string]interface{}, claimsToCheck []*claims.TypeSessionClaim, userContext supertokens.UserContext) (map[string]interface{}, error) {
+	nowInMillis := time.Now().UnixNano() / 1000000
+	for _, claim := range claimsToCheck {
+		lastRefetchTime := claim.GetLastRefetchTime(accessTokenPayload, userContext)
+		if lastRefetchTime == nil || *lastRefetchTime >= nowInMillis-*claim.MaxStalenessInSeconds*1000 {
+			continue
+		}
+		supertokens.LogDebugMessage("refreshStaleClaims refetching " + claim.Key)
+		tenantId, ok := accessTokenPayload["tId"].(string)
+		if !ok {
+			tenantId = multitenancymodels.DefaultTenantId
+		}
+		value, err := claim.FetchValue(userId, tenantId, userContext)
+		if err != nil {
+			return nil, err
+		}
+		supertokens.LogDebugMessage(fmt.Sprint("refreshStaleClaims ", claim.Key, " refetch result ", value))
+		if value != nil {
+			accessTokenPayload = claim.AddToPayload_internal(accessTokenPayload, value, userContext)
+		}
+	}
+	return accessTokenPayload, nil
+}
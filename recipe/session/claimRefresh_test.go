@@ -0,0 +1,61 @@
+/* Copyright (c) 2023, VRAI Labs and/or its affiliates. All rights reserved.
+ *
+ * This software is licensed under the Apache License, Version 2.0 (the
+ * "License") as published by the Apache Software Foundation.
+ *
+ * You may not use this file except in compliance with the License. You may
+ * obtain a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+ * License for the specific language governing permissions and limitations
+ * under the License.
+ */
+
+package session
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/supertokens/supertokens-golang/recipe/session/claims"
+	"github.com/supertokens/supertokens-golang/supertokens"
+)
+
+func TestRefreshStaleClaims(t *testing.T) {
+	fetches := 0
+	roles, rolesValidators := claims.PrimitiveArrayClaim("roles", func(userId string, tenantId string, userContext supertokens.UserContext) (interface{}, error) {
+		fetches++
+		return []interface{}{"admin"}, nil
+	}, nil)
+	maxStaleness := int64(60)
+	roles.MaxStalenessInSeconds = &maxStaleness
+	other, _ := claims.PrimitiveClaim("other", nil, nil)
+
+	claimsToCheck := getClaimsWithMaxStaleness([]claims.SessionClaimValidator{rolesValidators.Includes("user", nil, nil)}, []*claims.TypeSessionClaim{roles, other})
+	assert.Equal(t, []*claims.TypeSessionClaim{roles}, claimsToCheck)
+
+	userContext := &map[string]interface{}{}
+	recentlyFetched := time.Now().UnixNano()/1000000 - 1000
+	payload := map[string]interface{}{"roles": map[string]interface{}{"v": []interface{}{"user"}, "t": float64(recentlyFetched)}}
+	payload, err := refreshStaleClaims("user1", payload, claimsToCheck, userContext)
+	assert.NoError(t, err)
+	assert.Equal(t, 0, fetches)
+	assert.Equal(t, []interface{}{"user"}, roles.GetValueFromPayload(payload, userContext))
+
+	stale := time.Now().UnixNano()/1000000 - 61000
+	payload["roles"] = map[string]interface{}{"v": []interface{}{"user"}, "t": float64(stale)}
+	payload, err = refreshStaleClaims("user1", payload, claimsToCheck, userContext)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, fetches)
+	assert.Equal(t, []interface{}{"admin"}, roles.GetValueFromPayload(payload, userContext))
+	assert.Greater(t, *roles.GetLastRefetchTime(payload, userContext), stale)
+
+	// claims missing from the payload are not added
+	payload, err = refreshStaleClaims("user1", map[string]interface{}{}, claimsToCheck, userContext)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, fetches)
+	assert.Nil(t, payload["roles"])
+}
@@ -42,6 +42,11 @@ type TypeSessionClaim struct {
 	GetValueFromPayload               func(payload map[string]interface{}, userContext supertokens.UserContext) interface{}
 	GetLastRefetchTime                func(payload map[string]interface{}, userContext supertokens.UserContext) *int64
 	Build                             func(userId string, tenantId string, payloadToUpdate map[string]interface{}, userContext supertokens.UserContext) (map[string]interface{}, error)
+	// MaxStalenessInSeconds, if set, makes the session recipe refetch the
+	// claim during session verification once its value in the access token
+	// payload is older than this, even if no validator of the claim asks for
+	// it. The new value is merged into the access token payload.
+	MaxStalenessInSeconds *int64
 }
 
 type SessionClaimValidator struct {
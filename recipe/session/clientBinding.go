@@ -0,0 +1,189 @@
+/* Copyright (c) 2023, VRAI Labs and/or its affiliates. All rights reserved.
+ *
+ * This software is licensed under the Apache License, Version 2.0 (the
+ * "License") as published by the Apache Software Foundation.
+ *
+ * You may not use this file except in compliance with the License. You may
+ * obtain a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+ * License for the specific language governing permissions and limitations
+ * under the License.
+ */
+
+package session
+
+import (
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"errors"
+	"net"
+
+	sessionErrors "github.com/supertokens/supertokens-golang/recipe/session/errors"
+	"github.com/supertokens/supertokens-golang/recipe/session/sessmodels"
+	"github.com/supertokens/supertokens-golang/supertokens"
+)
+
+// clientBindingKey is the access token payload key holding the hashes of the
+// IP prefix and fingerprint of the client that created the session
+const clientBindingKey = "st-cb"
+
+const (
+	defaultClientBindingIPv4PrefixLength = 24
+	defaultClientBindingIPv6PrefixLength = 64
+)
+
+func normaliseClientBindingInput(config *sessmodels.ClientBindingInput) (*sessmodels.NormalisedClientBindingConfig, error) {
+	if config == nil {
+		return nil, nil
+	}
+	if !config.BindToIP && config.GetFingerprint == nil {
+		return nil, errors.New("ClientBinding needs BindToIP or GetFingerprint to be set")
+	}
+	result := &sessmodels.NormalisedClientBindingConfig{
+		BindToIP:         config.BindToIP,
+		IPv4PrefixLength: defaultClientBindingIPv4PrefixLength,
+		IPv6PrefixLength: defaultClientBindingIPv6PrefixLength,
+		GetFingerprint:   config.GetFingerprint,
+		Policy:           ClientBindingPolicy_REJECT,
+		OnMismatch:       config.OnMismatch,
+	}
+	if config.IPv4PrefixLength != nil {
+		if *config.IPv4PrefixLength < 1 || *config.IPv4PrefixLength > 32 {
+			return nil, errors.New("ClientBinding IPv4PrefixLength must be between 1 and 32")
+		}
+		result.IPv4PrefixLength = *config.IPv4PrefixLength
+	}
+	if config.IPv6PrefixLength != nil {
+		if *config.IPv6PrefixLength < 1 || *config.IPv6PrefixLength > 128 {
+			return nil, errors.New("ClientBinding IPv6PrefixLength must be between 1 and 128")
+		}
+		result.IPv6PrefixLength = *config.IPv6PrefixLength
+	}
+	if config.Policy != nil {
+		if *config.Policy != ClientBindingPolicy_REJECT && *config.Policy != ClientBindingPolicy_REAUTH && *config.Policy != ClientBindingPolicy_REPORT {
+			return nil, errors.New("ClientBinding Policy must be one of REJECT, REAUTH or REPORT")
+		}
+		result.Policy = *config.Policy
+	}
+	return result, nil
+}
+
+func hashClientBindingValue(value string) string {
+	hash := sha256.Sum256([]byte(value))
+	return base64.RawURLEncoding.EncodeToString(hash[:])
+}
+
+// getClientIPPrefix returns the network of the IP in CIDR notation, or an
+// empty string if it is not a valid IP
+func getClientIPPrefix(config *sessmodels.NormalisedClientBindingConfig, ipStr string) string {
+	ip := net.ParseIP(ipStr)
+	if ip == nil {
+		return ""
+	}
+	ipNet := net.IPNet{IP: ip, Mask: net.CIDRMask(config.IPv6PrefixLength, 128)}
+	if ip4 := ip.To4(); ip4 != nil {
+		ipNet = net.IPNet{IP: ip4, Mask: net.CIDRMask(config.IPv4PrefixLength, 32)}
+	}
+	ipNet.IP = ipNet.IP.Mask(ipNet.Mask)
+	return ipNet.String()
+}
+
+// getClientBindingHashes returns the hashes identifying the client of the
+// request in userContext, or nil if there is no request
+func getClientBindingHashes(config sessmodels.TypeNormalisedInput, userContext supertokens.UserContext) (map[string]interface{}, error) {
+	req := supertokens.GetRequestFromUserContext(userContext)
+	if req == nil {
+		return nil, nil
+	}
+	result := map[string]interface{}{}
+	if config.ClientBinding.BindToIP {
+		prefix := getClientIPPrefix(config.ClientBinding, supertokens.GetClientIP(req, userContext))
+		if prefix == "" {
+			return nil, errors.New("client binding is enabled but the IP of the client could not be found")
+		}
+		result["ip"] = hashClientBindingValue(prefix)
+	}
+	if config.ClientBinding.GetFingerprint != nil {
+		fingerprint, err := config.ClientBinding.GetFingerprint(req, userContext)
+		if err != nil {
+			return nil, err
+		}
+		result["fp"] = hashClientBindingValue(fingerprint)
+	}
+	return result, nil
+}
+
+// addClientBinding returns a copy of the access token payload of a new
+// session with the hashes identifying the client. Sessions created without a
+// request are not bound.
+func addClientBinding(config sessmodels.TypeNormalisedInput, accessTokenPayload map[string]interface{}, userContext supertokens.UserContext) (map[string]interface{}, error) {
+	if config.ClientBinding == nil {
+		return accessTokenPayload, nil
+	}
+	hashes, err := getClientBindingHashes(config, userContext)
+	if err != nil || hashes == nil {
+		return accessTokenPayload, err
+	}
+	result := map[string]interface{}{}
+	for k, v := range accessTokenPayload {
+		result[k] = v
+	}
+	result[clientBindingKey] = hashes
+	return result, nil
+}
+
+func clientBindingMatches(expected map[string]interface{}, actual map[string]interface{}) bool {
+	for key, value := range expected {
+		expectedHash, _ := value.(string)
+		actualHash, _ := actual[key].(string)
+		if subtle.ConstantTimeCompare([]byte(expectedHash), []byte(actualHash)) != 1 {
+			return false
+		}
+	}
+	return true
+}
+
+// checkClientBinding is called after a session is verified. Sessions that
+// are not bound (because they were created before the binding was enabled or
+// without a request) and verifications without a request are not checked.
+func checkClientBinding(config sessmodels.TypeNormalisedInput, querier supertokens.Querier, sessionHandle string, userID string, accessTokenPayload map[string]interface{}, userContext supertokens.UserContext) error {
+	if config.ClientBinding == nil {
+		return nil
+	}
+	expected, ok := accessTokenPayload[clientBindingKey].(map[string]interface{})
+	if !ok {
+		return nil
+	}
+	actual, err := getClientBindingHashes(config, userContext)
+	if err != nil || actual == nil {
+		return err
+	}
+	if clientBindingMatches(expected, actual) {
+		return nil
+	}
+
+	if config.ClientBinding.OnMismatch != nil {
+		config.ClientBinding.OnMismatch(sessionHandle, userID, supertokens.GetRequestFromUserContext(userContext), userContext)
+	}
+	switch config.ClientBinding.Policy {
+	case ClientBindingPolicy_REPORT:
+		supertokens.LogDebugMessage("getSession: Session is used by another client, reporting it only")
+		return nil
+	case ClientBindingPolicy_REAUTH:
+		supertokens.LogDebugMessage("getSession: Revoking session because it is used by another client")
+		_, err = revokeSessionHelper(querier, sessionHandle, userContext)
+		if err != nil {
+			return err
+		}
+		clearTokens := true
+		return sessionErrors.UnauthorizedError{Msg: "session is bound to another client", ClearTokens: &clearTokens}
+	default:
+		supertokens.LogDebugMessage("getSession: Returning UNAUTHORISED because the session is bound to another client")
+		clearTokens := false
+		return sessionErrors.UnauthorizedError{Msg: "session is bound to another client", ClearTokens: &clearTokens}
+	}
+}
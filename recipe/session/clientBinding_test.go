@@ -0,0 +1,148 @@
+/* Copyright (c) 2023, VRAI Labs and/or its affiliates. All rights reserved.
+ *
+ * This software is licensed under the Apache License, Version 2.0 (the
+ * "License") as published by the Apache Software Foundation.
+ *
+ * You may not use this file except in compliance with the License. You may
+ * obtain a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+ * License for the specific language governing permissions and limitations
+ * under the License.
+ */
+
+package session
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	sessionErrors "github.com/supertokens/supertokens-golang/recipe/session/errors"
+	"github.com/supertokens/supertokens-golang/recipe/session/sessmodels"
+	"github.com/supertokens/supertokens-golang/supertokens"
+)
+
+func userContextFromClient(remoteAddr string, userAgent string) supertokens.UserContext {
+	req := httptest.NewRequest(http.MethodGet, "/admin", nil)
+	req.RemoteAddr = remoteAddr
+	req.Header.Set("User-Agent", userAgent)
+	return supertokens.MakeDefaultUserContextFromAPI(req)
+}
+
+func TestClientBindingConfigValidation(t *testing.T) {
+	config, err := normaliseClientBindingInput(nil)
+	assert.NoError(t, err)
+	assert.Nil(t, config)
+
+	_, err = normaliseClientBindingInput(&sessmodels.ClientBindingInput{})
+	assert.Error(t, err)
+
+	tooLong := 33
+	_, err = normaliseClientBindingInput(&sessmodels.ClientBindingInput{BindToIP: true, IPv4PrefixLength: &tooLong})
+	assert.Error(t, err)
+
+	policy := "IGNORE"
+	_, err = normaliseClientBindingInput(&sessmodels.ClientBindingInput{BindToIP: true, Policy: &policy})
+	assert.Error(t, err)
+
+	config, err = normaliseClientBindingInput(&sessmodels.ClientBindingInput{BindToIP: true})
+	assert.NoError(t, err)
+	assert.Equal(t, 24, config.IPv4PrefixLength)
+	assert.Equal(t, 64, config.IPv6PrefixLength)
+	assert.Equal(t, ClientBindingPolicy_REJECT, config.Policy)
+}
+
+func TestClientIPPrefix(t *testing.T) {
+	config, err := normaliseClientBindingInput(&sessmodels.ClientBindingInput{BindToIP: true})
+	assert.NoError(t, err)
+
+	assert.Equal(t, "203.0.113.0/24", getClientIPPrefix(config, "203.0.113.42"))
+	assert.Equal(t, "2001:db8:1:2::/64", getClientIPPrefix(config, "2001:db8:1:2:3:4:5:6"))
+	assert.Equal(t, "", getClientIPPrefix(config, "not-an-ip"))
+}
+
+func TestClientBindingPolicies(t *testing.T) {
+	revoked := []interface{}{}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/apiversion", func(rw http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(rw).Encode(map[string]interface{}{"versions": []string{"3.0"}})
+	})
+	mux.HandleFunc("/recipe/session/remove", func(rw http.ResponseWriter, r *http.Request) {
+		var body map[string]interface{}
+		json.NewDecoder(r.Body).Decode(&body)
+		revoked = append(revoked, body["sessionHandles"].([]interface{})...)
+		json.NewEncoder(rw).Encode(map[string]interface{}{"status": "OK", "sessionHandlesRevoked": body["sessionHandles"]})
+	})
+	testServer := httptest.NewServer(mux)
+	defer testServer.Close()
+
+	resetAll()
+	defer resetAll()
+	err := supertokens.Init(supertokens.TypeInput{
+		Supertokens: &supertokens.ConnectionInfo{
+			ConnectionURI: testServer.URL,
+		},
+		AppInfo: supertokens.AppInfo{
+			AppName:       "SuperTokens",
+			WebsiteDomain: "supertokens.io",
+			APIDomain:     "api.supertokens.io",
+		},
+		RecipeList: []supertokens.Recipe{Init(nil)},
+	})
+	assert.NoError(t, err)
+	querier, err := supertokens.GetNewQuerierInstanceOrThrowError("session")
+	assert.NoError(t, err)
+
+	mismatches := []string{}
+	makeConfig := func(policy string) sessmodels.TypeNormalisedInput {
+		clientBinding, err := normaliseClientBindingInput(&sessmodels.ClientBindingInput{
+			BindToIP: true,
+			GetFingerprint: func(req *http.Request, userContext supertokens.UserContext) (string, error) {
+				return req.Header.Get("User-Agent"), nil
+			},
+			Policy: &policy,
+			OnMismatch: func(sessionHandle string, userID string, req *http.Request, userContext supertokens.UserContext) {
+				mismatches = append(mismatches, sessionHandle)
+			},
+		})
+		assert.NoError(t, err)
+		return sessmodels.TypeNormalisedInput{ClientBinding: clientBinding}
+	}
+
+	config := makeConfig(ClientBindingPolicy_REJECT)
+	payload, err := addClientBinding(config, map[string]interface{}{"key": "value"}, userContextFromClient("203.0.113.42:1234", "browser-a"))
+	assert.NoError(t, err)
+	assert.Equal(t, "value", payload["key"])
+	assert.NotEmpty(t, payload[clientBindingKey])
+
+	// the IP may change within the bound network
+	err = checkClientBinding(config, *querier, "handle1", "user1", payload, userContextFromClient("203.0.113.7:1234", "browser-a"))
+	assert.NoError(t, err)
+	assert.Empty(t, mismatches)
+
+	err = checkClientBinding(config, *querier, "handle1", "user1", payload, userContextFromClient("198.51.100.1:1234", "browser-a"))
+	assert.IsType(t, sessionErrors.UnauthorizedError{}, err)
+	assert.False(t, *err.(sessionErrors.UnauthorizedError).ClearTokens)
+	assert.Empty(t, revoked)
+
+	err = checkClientBinding(makeConfig(ClientBindingPolicy_REPORT), *querier, "handle2", "user1", payload, userContextFromClient("203.0.113.42:1234", "browser-b"))
+	assert.NoError(t, err)
+	assert.Empty(t, revoked)
+
+	err = checkClientBinding(makeConfig(ClientBindingPolicy_REAUTH), *querier, "handle3", "user1", payload, userContextFromClient("203.0.113.42:1234", "browser-b"))
+	assert.IsType(t, sessionErrors.UnauthorizedError{}, err)
+	assert.True(t, *err.(sessionErrors.UnauthorizedError).ClearTokens)
+	assert.Equal(t, []interface{}{"handle3"}, revoked)
+	assert.Equal(t, []string{"handle1", "handle2", "handle3"}, mismatches)
+
+	// sessions that are not bound, and verifications without a request, are not checked
+	err = checkClientBinding(config, *querier, "handle4", "user1", map[string]interface{}{}, userContextFromClient("198.51.100.1:1234", "browser-b"))
+	assert.NoError(t, err)
+	err = checkClientBinding(config, *querier, "handle4", "user1", payload, &map[string]interface{}{})
+	assert.NoError(t, err)
+}
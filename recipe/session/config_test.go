@@ -174,7 +174,7 @@ func TestSuperTokensInitWith0RecipeModules(t *testing.T) {
 }
 
 func TestSuperTokensInitWithConfigForSessionModules(t *testing.T) {
-	cookieDomain := "testDomain"
+	cookieDomain := ".Supertokens.io"
 	sessionExpiredStatusCode := 111
 	cookieSecure := true
 	configValue := supertokens.TypeInput{
@@ -208,7 +208,7 @@ func TestSuperTokensInitWithConfigForSessionModules(t *testing.T) {
 	if err != nil {
 		t.Error(err.Error())
 	}
-	assert.Equal(t, *sessionSingletonInstance.Config.CookieDomain, "testdomain")
+	assert.Equal(t, *sessionSingletonInstance.Config.CookieDomain, "supertokens.io")
 	assert.Equal(t, sessionSingletonInstance.Config.SessionExpiredStatusCode, 111)
 	assert.Equal(t, sessionSingletonInstance.Config.CookieSecure, true)
 }
@@ -715,9 +715,11 @@ func TestSuperTokensInitWithDifferentWebAndApiDomainWithDefaultCookieSecure(t *t
 	unittesting.StartUpST("localhost", "8080")
 	defer AfterEach()
 	err := supertokens.Init(configValue)
-	// The following error is not expected to happen on supertokens init anymore
-	// Since your API and website domain are different, for sessions to work, please use https on your apiDomain and dont set cookieSecure to false.
 	assert.NoError(t, err)
+	sessionSingletonInstance, err := getRecipeInstanceOrThrowError()
+	assert.NoError(t, err)
+	// SameSite=None cookies have to be secure
+	assert.True(t, sessionSingletonInstance.Config.CookieSecure)
 }
 func TestSuperTokensInitWithDifferentWebAndApiDomainWithCookieSecureValueSetToFalse(t *testing.T) {
 	apiBasePath0 := "test/"
@@ -744,9 +746,7 @@ func TestSuperTokensInitWithDifferentWebAndApiDomainWithCookieSecureValueSetToFa
 	unittesting.StartUpST("localhost", "8080")
 	defer AfterEach()
 	err := supertokens.Init(configValue)
-	// The following error is not expected to happen on supertokens init anymore
-	// Since your API and website domain are different, for sessions to work, please use https on your apiDomain and dont set cookieSecure to false.
-	assert.NoError(t, err)
+	assert.EqualError(t, err, "Since your API and website domains are on different sites, session cookies need SameSite=None, which browsers only accept for secure cookies, but CookieSecure is false. Please set CookieSecure to true and use https for your APIDomain, or host them on the same site")
 }
 
 func TestSuperTokensForTheDefaultCookieValues(t *testing.T) {
@@ -1512,3 +1512,46 @@ func TestOriginFunctionIsUsedOverOrigin(t *testing.T) {
 	}
 	assert.Equal(t, "https://test.io", origin.GetAsStringDangerous())
 }
+
+func TestCookieDefaultsAreDerivedFromTheAPIAndWebsiteDomains(t *testing.T) {
+	crossSiteAppInfo, err := supertokens.NormaliseInputAppInfoOrThrowError(supertokens.AppInfo{
+		AppName:       "SuperTokens",
+		APIDomain:     "http://localhost:8000",
+		WebsiteDomain: "http://supertokens.io",
+	})
+	assert.NoError(t, err)
+	config, err := ValidateAndNormaliseUserInput(crossSiteAppInfo, nil)
+	assert.NoError(t, err)
+	sameSite, err := config.GetCookieSameSite(nil, &map[string]interface{}{})
+	assert.NoError(t, err)
+	assert.Equal(t, "none", sameSite)
+	assert.True(t, config.CookieSecure)
+	antiCsrf, err := config.AntiCsrfFunctionOrString.FunctionValue(nil, &map[string]interface{}{})
+	assert.NoError(t, err)
+	assert.Equal(t, "VIA_CUSTOM_HEADER", antiCsrf)
+
+	False := false
+	_, err = ValidateAndNormaliseUserInput(crossSiteAppInfo, &sessmodels.TypeInput{CookieSecure: &False})
+	assert.Error(t, err)
+
+	True := true
+	_, err = ValidateAndNormaliseUserInput(crossSiteAppInfo, &sessmodels.TypeInput{CookieSecure: &False, HeaderBasedAuthOnly: &True})
+	assert.NoError(t, err)
+
+	sameSiteAppInfo, err := supertokens.NormaliseInputAppInfoOrThrowError(supertokens.AppInfo{
+		AppName:       "SuperTokens",
+		APIDomain:     "http://localhost:8000",
+		WebsiteDomain: "http://localhost:3000",
+	})
+	assert.NoError(t, err)
+	config, err = ValidateAndNormaliseUserInput(sameSiteAppInfo, nil)
+	assert.NoError(t, err)
+	sameSite, err = config.GetCookieSameSite(nil, &map[string]interface{}{})
+	assert.NoError(t, err)
+	assert.Equal(t, "lax", sameSite)
+	assert.False(t, config.CookieSecure)
+
+	None := "none"
+	_, err = ValidateAndNormaliseUserInput(sameSiteAppInfo, &sessmodels.TypeInput{CookieSameSite: &None, CookieSecure: &False})
+	assert.EqualError(t, err, "CookieSameSite is none, which browsers only accept for secure cookies, but CookieSecure is false. Please set CookieSecure to true and use https for your APIDomain")
+}
@@ -23,6 +23,9 @@ const (
 	RefreshAPIPath = "/session/refresh"
 	SignoutAPIPath = "/signout"
 
+	UserSessionsAPIPath      = "/session/list"
+	RevokeUserSessionAPIPath = "/session/revoke"
+
 	AntiCSRF_VIA_TOKEN         = "VIA_TOKEN"
 	AntiCSRF_VIA_CUSTOM_HEADER = "VIA_CUSTOM_HEADER"
 	AntiCSRF_NONE              = "NONE"
@@ -30,10 +33,33 @@ const (
 	CookieSameSite_NONE   = "none"
 	CookieSameSite_LAX    = "lax"
 	CookieSameSite_STRICT = "strict"
+
+	ClientBindingPolicy_REJECT = "REJECT"
+	ClientBindingPolicy_REAUTH = "REAUTH"
+	ClientBindingPolicy_REPORT = "REPORT"
+
+	SessionExpiration_SLIDING  = "SLIDING"
+	SessionExpiration_ABSOLUTE = "ABSOLUTE"
+
+	CookieNamePrefix_HOST   = "__Host-"
+	CookieNamePrefix_SECURE = "__Secure-"
+
+	// sessionValuesKey is the key in the session data in database under which
+	// SetValue / GetValue keep their namespaced values
+	sessionValuesKey = "st-values"
+	// userSessionDeviceKey is the key in the session data in database under
+	// which the device that created the session is recorded for the user
+	// sessions API
+	userSessionDeviceKey = "st-device"
 )
 
 var JWKCacheMaxAgeInMs int64 = 60000
 var JWKRefreshRateLimit = 500
+
+// JWKProactiveRefreshWindowInMs is how long before the cached keys expire
+// they are fetched again in the background, so that requests do not wait
+// for the keys to be fetched. 0 disables the background refresh.
+var JWKProactiveRefreshWindowInMs int64 = 10000
 var protectedProps = []string{
 	"sub",
 	"iat",
@@ -62,7 +62,7 @@ func ClearSessionFromAllTokenTransferMethods(config sessmodels.TypeNormalisedInp
 	// In this case: the SDK has attached cookies to the response, but none was sent with the request
 	// We can't know which to clear since we can't reliably query or remove the set-cookie header added to the response (causes issues in some frameworks, i.e.: hapi)
 	// The safe solution in this case is to overwrite all the response cookies/headers with an empty value, which is what we are doing here
-	for _, transferMethod := range AvailableTokenTransferMethods {
+	for _, transferMethod := range getAvailableTokenTransferMethods(config) {
 		err := ClearSession(config, res, transferMethod, req, userContext)
 		if err != nil {
 			return err
@@ -131,6 +131,20 @@ func getCookieNameFromTokenType(tokenType sessmodels.TokenType) (string, error)
 	return "", errors.New("Unknown token type, should never happen.")
 }
 
+// getPrefixedCookieName adds the configured prefix to the cookie name of tokenType.
+// The refresh token cookie is scoped to the refresh API, so it gets the
+// __Secure- prefix when __Host- is configured.
+func getPrefixedCookieName(config sessmodels.TypeNormalisedInput, tokenType sessmodels.TokenType) (string, error) {
+	cookieName, err := getCookieNameFromTokenType(tokenType)
+	if err != nil {
+		return "", err
+	}
+	if config.CookieNamePrefix == CookieNamePrefix_HOST && tokenType == sessmodels.RefreshToken {
+		return CookieNamePrefix_SECURE + cookieName, nil
+	}
+	return config.CookieNamePrefix + cookieName, nil
+}
+
 func getResponseHeaderNameForTokenType(tokenType sessmodels.TokenType) (string, error) {
 	if tokenType == sessmodels.AccessToken {
 		return accessTokenHeaderKey, nil
@@ -141,9 +155,16 @@ func getResponseHeaderNameForTokenType(tokenType sessmodels.TokenType) (string,
 	return "", errors.New("Unknown token type, should never happen.")
 }
 
+// GetToken reads a token from the request. Cookies are read without the
+// prefix set in CookieNamePrefix.
 func GetToken(req *http.Request, tokenType sessmodels.TokenType, transferMethod sessmodels.TokenTransferMethod) (*string, error) {
+	return getToken(sessmodels.TypeNormalisedInput{}, req, tokenType, transferMethod)
+}
+
+func getToken(config sessmodels.TypeNormalisedInput, req *http.Request, tokenType sessmodels.TokenType, transferMethod sessmodels.TokenTransferMethod) (*string, error) {
+	transferMethod = getStorageTransferMethod(config, tokenType, transferMethod)
 	if transferMethod == sessmodels.CookieTransferMethod {
-		cookieName, err := getCookieNameFromTokenType(tokenType)
+		cookieName, err := getPrefixedCookieName(config, tokenType)
 		if err != nil {
 			return nil, err
 		}
@@ -161,9 +182,10 @@ func GetToken(req *http.Request, tokenType sessmodels.TokenType, transferMethod
 }
 
 func setToken(config sessmodels.TypeNormalisedInput, res http.ResponseWriter, tokenType sessmodels.TokenType, value string, expires uint64, transferMethod sessmodels.TokenTransferMethod, request *http.Request, userContext supertokens.UserContext) error {
+	transferMethod = getStorageTransferMethod(config, tokenType, transferMethod)
 	supertokens.LogDebugMessage(fmt.Sprint("setToken: Setting ", tokenType, " token as ", transferMethod))
 	if transferMethod == sessmodels.CookieTransferMethod {
-		cookieName, err := getCookieNameFromTokenType(tokenType)
+		cookieName, err := getPrefixedCookieName(config, tokenType)
 		if err != nil {
 			return err
 		}
@@ -173,7 +195,7 @@ func setToken(config sessmodels.TypeNormalisedInput, res http.ResponseWriter, to
 		} else if tokenType == sessmodels.RefreshToken {
 			pathType = "refreshTokenPath"
 		}
-		setCookie(config, res, cookieName, value, expires, pathType, request, userContext)
+		return setCookie(config, res, cookieName, value, expires, pathType, request, userContext)
 	} else if transferMethod == sessmodels.HeaderTransferMethod {
 		headerName, err := getResponseHeaderNameForTokenType(tokenType)
 		if err != nil {
@@ -206,28 +228,15 @@ func setHeader(res http.ResponseWriter, key, value string, allowDuplicateKey boo
 }
 
 func setCookie(config sessmodels.TypeNormalisedInput, res http.ResponseWriter, name string, value string, expires uint64, pathType string, request *http.Request, userContext supertokens.UserContext) error {
-	var domain string
-	if config.CookieDomain != nil {
-		domain = *config.CookieDomain
-	}
-	secure := config.CookieSecure
-	sameSite, err := config.GetCookieSameSite(request, userContext)
-
+	attributes, err := getCookieAttributes(config, name, pathType, request, userContext)
 	if err != nil {
 		return err
 	}
 
-	path := ""
-	if pathType == "refreshTokenPath" {
-		path = config.RefreshTokenPath.GetAsStringDangerous()
-	} else if pathType == "accessTokenPath" {
-		path = "/"
-	}
-
 	var sameSiteField = http.SameSiteNoneMode
-	if sameSite == "lax" {
+	if attributes.SameSite == "lax" {
 		sameSiteField = http.SameSiteLaxMode
-	} else if sameSite == "strict" {
+	} else if attributes.SameSite == "strict" {
 		sameSiteField = http.SameSiteStrictMode
 	}
 
@@ -236,17 +245,82 @@ func setCookie(config sessmodels.TypeNormalisedInput, res http.ResponseWriter, n
 	cookie := &http.Cookie{
 		Name:     name,
 		Value:    url.QueryEscape(value),
-		Domain:   domain,
-		Secure:   secure,
+		Domain:   attributes.Domain,
+		Secure:   attributes.Secure,
 		HttpOnly: httpOnly,
-		Expires:  time.Unix(int64(expires/1000), 0),
-		Path:     path,
+		Path:     attributes.Path,
 		SameSite: sameSiteField,
 	}
+	if expires != browserSessionCookieExpiry {
+		cookie.Expires = time.Unix(int64(expires/1000), 0)
+	}
 	setCookieValue(res, cookie)
 	return nil
 }
 
+func getCookieAttributes(config sessmodels.TypeNormalisedInput, name string, pathType string, request *http.Request, userContext supertokens.UserContext) (sessmodels.CookieAttributes, error) {
+	attributes := sessmodels.CookieAttributes{
+		Secure: config.CookieSecure,
+	}
+	if config.CookieDomain != nil {
+		attributes.Domain = *config.CookieDomain
+	}
+	sameSite, err := config.GetCookieSameSite(request, userContext)
+	if err != nil {
+		return sessmodels.CookieAttributes{}, err
+	}
+	attributes.SameSite = sameSite
+
+	if pathType == "refreshTokenPath" {
+		attributes.Path = config.RefreshTokenPath.GetAsStringDangerous()
+	} else if pathType == "accessTokenPath" {
+		attributes.Path = config.AccessTokenCookiePath
+	}
+
+	if config.GetCookieAttributes == nil {
+		return attributes, nil
+	}
+	attributes, err = config.GetCookieAttributes(name, attributes, request, userContext)
+	if err != nil {
+		return sessmodels.CookieAttributes{}, err
+	}
+	attributes.SameSite, err = normaliseSameSiteOrThrowError(attributes.SameSite)
+	if err != nil {
+		return sessmodels.CookieAttributes{}, err
+	}
+	if attributes.Domain != "" {
+		domain, err := normaliseSessionScopeOrThrowError(attributes.Domain)
+		if err != nil {
+			return sessmodels.CookieAttributes{}, err
+		}
+		attributes.Domain = *domain
+	}
+	err = validateCookieAttributesForPrefix(name, attributes)
+	if err != nil {
+		return sessmodels.CookieAttributes{}, err
+	}
+	return attributes, nil
+}
+
+// validateCookieAttributesForPrefix checks the attributes that browsers
+// require for cookies named with the __Secure- and __Host- prefixes
+func validateCookieAttributesForPrefix(name string, attributes sessmodels.CookieAttributes) error {
+	if strings.HasPrefix(name, CookieNamePrefix_SECURE) || strings.HasPrefix(name, CookieNamePrefix_HOST) {
+		if !attributes.Secure {
+			return errors.New("the cookie " + name + " must be secure")
+		}
+	}
+	if strings.HasPrefix(name, CookieNamePrefix_HOST) {
+		if attributes.Domain != "" {
+			return errors.New("the cookie " + name + " cannot have a domain")
+		}
+		if attributes.Path != "/" {
+			return errors.New("the path of the cookie " + name + " must be /")
+		}
+	}
+	return nil
+}
+
 func GetAuthmodeFromHeader(req *http.Request) *sessmodels.TokenTransferMethod {
 	val := getHeader(req, authModeHeaderKey)
 	if val == nil {
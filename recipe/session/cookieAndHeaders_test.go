@@ -0,0 +1,228 @@
+/* Copyright (c) 2023, VRAI Labs and/or its affiliates. All rights reserved.
+ *
+ * This software is licensed under the Apache License, Version 2.0 (the
+ * "License") as published by the Apache Software Foundation.
+ *
+ * You may not use this file except in compliance with the License. You may
+ * obtain a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+ * License for the specific language governing permissions and limitations
+ * under the License.
+ */
+
+package session
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/supertokens/supertokens-golang/recipe/session/sessmodels"
+	"github.com/supertokens/supertokens-golang/supertokens"
+)
+
+func makeCookieTestConfig(t *testing.T, config *sessmodels.TypeInput) sessmodels.TypeNormalisedInput {
+	appInfo, err := supertokens.NormaliseInputAppInfoOrThrowError(supertokens.AppInfo{
+		AppName:       "SuperTokens",
+		APIDomain:     "https://api.supertokens.io",
+		WebsiteDomain: "https://supertokens.io",
+	})
+	assert.NoError(t, err)
+	normalisedConfig, err := ValidateAndNormaliseUserInput(appInfo, config)
+	assert.NoError(t, err)
+	return normalisedConfig
+}
+
+func getCookieFromResponse(res *httptest.ResponseRecorder, name string) *http.Cookie {
+	for _, cookie := range res.Result().Cookies() {
+		if cookie.Name == name {
+			return cookie
+		}
+	}
+	return nil
+}
+
+func TestCookieAttributesCanBeChangedPerRequest(t *testing.T) {
+	accessTokenCookiePath := "/api"
+	config := makeCookieTestConfig(t, &sessmodels.TypeInput{
+		AccessTokenCookiePath: &accessTokenCookiePath,
+		GetCookieAttributes: func(cookieName string, attributes sessmodels.CookieAttributes, req *http.Request, userContext supertokens.UserContext) (sessmodels.CookieAttributes, error) {
+			if req.Host == "staging.supertokens.io" {
+				attributes.Domain = ".Staging.supertokens.io"
+				attributes.SameSite = "Strict"
+			}
+			return attributes, nil
+		},
+	})
+
+	req := httptest.NewRequest(http.MethodPost, "/auth/signin", nil)
+	res := httptest.NewRecorder()
+	err := setToken(config, res, sessmodels.AccessToken, "token", 1000, sessmodels.CookieTransferMethod, req, &map[string]interface{}{})
+	assert.NoError(t, err)
+	err = setToken(config, res, sessmodels.RefreshToken, "token", 1000, sessmodels.CookieTransferMethod, req, &map[string]interface{}{})
+	assert.NoError(t, err)
+	accessTokenCookie := getCookieFromResponse(res, accessTokenCookieKey)
+	assert.Equal(t, "/api", accessTokenCookie.Path)
+	assert.Equal(t, "", accessTokenCookie.Domain)
+	assert.True(t, accessTokenCookie.Secure)
+	assert.Equal(t, "/auth/session/refresh", getCookieFromResponse(res, refreshTokenCookieKey).Path)
+
+	req = httptest.NewRequest(http.MethodPost, "https://staging.supertokens.io/auth/signin", nil)
+	res = httptest.NewRecorder()
+	err = setToken(config, res, sessmodels.AccessToken, "token", 1000, sessmodels.CookieTransferMethod, req, &map[string]interface{}{})
+	assert.NoError(t, err)
+	accessTokenCookie = getCookieFromResponse(res, accessTokenCookieKey)
+	assert.Equal(t, "staging.supertokens.io", accessTokenCookie.Domain)
+	assert.Equal(t, http.SameSiteStrictMode, accessTokenCookie.SameSite)
+}
+
+func TestInvalidCookieAttributes(t *testing.T) {
+	appInfo, err := supertokens.NormaliseInputAppInfoOrThrowError(supertokens.AppInfo{
+		AppName:       "SuperTokens",
+		APIDomain:     "https://api.supertokens.io",
+		WebsiteDomain: "https://supertokens.io",
+	})
+	assert.NoError(t, err)
+	accessTokenCookiePath := "api"
+	_, err = ValidateAndNormaliseUserInput(appInfo, &sessmodels.TypeInput{AccessTokenCookiePath: &accessTokenCookiePath})
+	assert.Error(t, err)
+
+	config := makeCookieTestConfig(t, &sessmodels.TypeInput{
+		GetCookieAttributes: func(cookieName string, attributes sessmodels.CookieAttributes, req *http.Request, userContext supertokens.UserContext) (sessmodels.CookieAttributes, error) {
+			if req.Header.Get("x-fail") != "" {
+				return attributes, errors.New("failed")
+			}
+			attributes.SameSite = "sometimes"
+			return attributes, nil
+		},
+	})
+	req := httptest.NewRequest(http.MethodPost, "/auth/signin", nil)
+	err = setToken(config, httptest.NewRecorder(), sessmodels.AccessToken, "token", 1000, sessmodels.CookieTransferMethod, req, &map[string]interface{}{})
+	assert.Error(t, err)
+	req.Header.Set("x-fail", "true")
+	err = setToken(config, httptest.NewRecorder(), sessmodels.AccessToken, "token", 1000, sessmodels.CookieTransferMethod, req, &map[string]interface{}{})
+	assert.EqualError(t, err, "failed")
+}
+
+func TestSessionCookiesWithTheHostPrefix(t *testing.T) {
+	cookieNamePrefix := CookieNamePrefix_HOST
+	config := makeCookieTestConfig(t, &sessmodels.TypeInput{CookieNamePrefix: &cookieNamePrefix})
+
+	req := httptest.NewRequest(http.MethodPost, "/auth/signin", nil)
+	res := httptest.NewRecorder()
+	err := setToken(config, res, sessmodels.AccessToken, "access", 1000, sessmodels.CookieTransferMethod, req, &map[string]interface{}{})
+	assert.NoError(t, err)
+	err = setToken(config, res, sessmodels.RefreshToken, "refresh", 1000, sessmodels.CookieTransferMethod, req, &map[string]interface{}{})
+	assert.NoError(t, err)
+
+	accessTokenCookie := getCookieFromResponse(res, "__Host-sAccessToken")
+	assert.Equal(t, "/", accessTokenCookie.Path)
+	assert.Equal(t, "", accessTokenCookie.Domain)
+	assert.True(t, accessTokenCookie.Secure)
+	refreshTokenCookie := getCookieFromResponse(res, "__Secure-sRefreshToken")
+	assert.Equal(t, "/auth/session/refresh", refreshTokenCookie.Path)
+	assert.True(t, refreshTokenCookie.Secure)
+
+	req = httptest.NewRequest(http.MethodGet, "/user", nil)
+	req.AddCookie(&http.Cookie{Name: "sAccessToken", Value: "old"})
+	req.AddCookie(&http.Cookie{Name: "__Host-sAccessToken", Value: "access"})
+	token, err := getToken(config, req, sessmodels.AccessToken, sessmodels.CookieTransferMethod)
+	assert.NoError(t, err)
+	assert.Equal(t, "access", *token)
+}
+
+func TestCookieNamePrefixValidation(t *testing.T) {
+	appInfo, err := supertokens.NormaliseInputAppInfoOrThrowError(supertokens.AppInfo{
+		AppName:       "SuperTokens",
+		APIDomain:     "https://api.supertokens.io",
+		WebsiteDomain: "https://supertokens.io",
+	})
+	assert.NoError(t, err)
+	hostPrefix := CookieNamePrefix_HOST
+	securePrefix := CookieNamePrefix_SECURE
+	invalidPrefix := "__Other-"
+	False := false
+	cookieDomain := ".supertokens.io"
+	accessTokenCookiePath := "/api"
+
+	_, err = ValidateAndNormaliseUserInput(appInfo, &sessmodels.TypeInput{CookieNamePrefix: &invalidPrefix})
+	assert.Error(t, err)
+	_, err = ValidateAndNormaliseUserInput(appInfo, &sessmodels.TypeInput{CookieNamePrefix: &securePrefix, CookieSecure: &False})
+	assert.Error(t, err)
+	_, err = ValidateAndNormaliseUserInput(appInfo, &sessmodels.TypeInput{CookieNamePrefix: &hostPrefix, CookieDomain: &cookieDomain})
+	assert.Error(t, err)
+	_, err = ValidateAndNormaliseUserInput(appInfo, &sessmodels.TypeInput{CookieNamePrefix: &hostPrefix, AccessTokenCookiePath: &accessTokenCookiePath})
+	assert.Error(t, err)
+	_, err = ValidateAndNormaliseUserInput(appInfo, &sessmodels.TypeInput{CookieNamePrefix: &securePrefix, CookieDomain: &cookieDomain, AccessTokenCookiePath: &accessTokenCookiePath})
+	assert.NoError(t, err)
+
+	// attributes changed per request are checked when the cookie is set
+	config := makeCookieTestConfig(t, &sessmodels.TypeInput{
+		CookieNamePrefix: &hostPrefix,
+		GetCookieAttributes: func(cookieName string, attributes sessmodels.CookieAttributes, req *http.Request, userContext supertokens.UserContext) (sessmodels.CookieAttributes, error) {
+			attributes.Domain = "supertokens.io"
+			return attributes, nil
+		},
+	})
+	req := httptest.NewRequest(http.MethodPost, "/auth/signin", nil)
+	err = setToken(config, httptest.NewRecorder(), sessmodels.AccessToken, "token", 1000, sessmodels.CookieTransferMethod, req, &map[string]interface{}{})
+	assert.Error(t, err)
+}
+
+func TestCookieDomainValidation(t *testing.T) {
+	appInfo, err := supertokens.NormaliseInputAppInfoOrThrowError(supertokens.AppInfo{
+		AppName:       "SuperTokens",
+		APIDomain:     "https://api.example.com",
+		WebsiteDomain: "https://app.example.com",
+	})
+	assert.NoError(t, err)
+
+	for cookieDomain, normalised := range map[string]string{".example.com": "example.com", "https://Example.com": "example.com", "api.example.com": "api.example.com"} {
+		cookieDomain := cookieDomain
+		config, err := ValidateAndNormaliseUserInput(appInfo, &sessmodels.TypeInput{CookieDomain: &cookieDomain})
+		assert.NoError(t, err)
+		assert.Equal(t, normalised, *config.CookieDomain)
+	}
+
+	otherDomain := ".example.org"
+	_, err = ValidateAndNormaliseUserInput(appInfo, &sessmodels.TypeInput{CookieDomain: &otherDomain})
+	assert.EqualError(t, err, "CookieDomain must be the domain of the APIDomain (api.example.com) or one of its parent domains, since browsers reject cookies for other domains")
+
+	partialLabel := "ample.com"
+	_, err = ValidateAndNormaliseUserInput(appInfo, &sessmodels.TypeInput{CookieDomain: &partialLabel})
+	assert.Error(t, err)
+
+	publicSuffix := ".com"
+	_, err = ValidateAndNormaliseUserInput(appInfo, &sessmodels.TypeInput{CookieDomain: &publicSuffix})
+	assert.EqualError(t, err, "CookieDomain cannot be a public suffix (com), since browsers reject cookies for them")
+}
+
+func TestIsAllowedCORSOrigin(t *testing.T) {
+	resetAll()
+	defer resetAll()
+	cookieDomain := ".example.com"
+	err := supertokens.Init(supertokens.TypeInput{
+		Supertokens: &supertokens.ConnectionInfo{
+			ConnectionURI: "http://localhost:8080",
+		},
+		AppInfo: supertokens.AppInfo{
+			AppName:       "SuperTokens",
+			APIDomain:     "https://api.example.com",
+			WebsiteDomain: "https://app.example.com",
+		},
+		RecipeList: []supertokens.Recipe{Init(&sessmodels.TypeInput{CookieDomain: &cookieDomain})},
+	})
+	assert.NoError(t, err)
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	assert.True(t, IsAllowedCORSOrigin(req, "https://app.example.com"))
+	assert.True(t, IsAllowedCORSOrigin(req, "https://admin.example.com"))
+	assert.False(t, IsAllowedCORSOrigin(req, "http://admin.example.com"))
+	assert.False(t, IsAllowedCORSOrigin(req, "https://example.org"))
+	assert.False(t, IsAllowedCORSOrigin(req, "https://evilexample.com"))
+}
@@ -0,0 +1,362 @@
+/* Copyright (c) 2023, VRAI Labs and/or its affiliates. All rights reserved.
+ *
+ * This software is licensed under the Apache License, Version 2.0 (the
+ * "License") as published by the Apache Software Foundation.
+ *
+ * You may not use this file except in compliance with the License. You may
+ * obtain a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+ * License for the specific language governing permissions and limitations
+ * under the License.
+ */
+
+package session
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	sessionErrors "github.com/supertokens/supertokens-golang/recipe/session/errors"
+	"github.com/supertokens/supertokens-golang/recipe/session/sessmodels"
+	"github.com/supertokens/supertokens-golang/supertokens"
+)
+
+const (
+	dpopHeaderName           = "DPoP"
+	dpopConfirmationClaim    = "cnf"
+	dpopThumbprintClaim      = "jkt"
+	defaultDPoPMaxAgeSeconds = 60
+	// dpopClockSkew is how far in the future a proof's iat can be
+	dpopClockSkew = 5 * time.Second
+)
+
+var defaultDPoPAlgorithms = []string{"ES256", "ES384", "RS256", "PS256", "EdDSA"}
+
+func normaliseDPoPInput(config *sessmodels.DPoPInput) (*sessmodels.NormalisedDPoPConfig, error) {
+	if config == nil {
+		return nil, nil
+	}
+	result := &sessmodels.NormalisedDPoPConfig{
+		MaxAge: defaultDPoPMaxAgeSeconds * time.Second,
+	}
+	if config.MaxAgeInSeconds != nil {
+		result.MaxAge = time.Duration(*config.MaxAgeInSeconds) * time.Second
+	}
+	if len(config.AllowedAlgorithms) > 0 {
+		for _, alg := range config.AllowedAlgorithms {
+			if jwt.GetSigningMethod(alg) == nil || strings.HasPrefix(alg, "HS") {
+				return nil, fmt.Errorf("%s cannot be used to sign DPoP proofs", alg)
+			}
+			err := supertokens.ValidateJWTAlgorithmForFIPSMode(alg)
+			if err != nil {
+				return nil, err
+			}
+		}
+		result.AllowedAlgorithms = config.AllowedAlgorithms
+	} else {
+		for _, alg := range defaultDPoPAlgorithms {
+			if supertokens.ValidateJWTAlgorithmForFIPSMode(alg) == nil {
+				result.AllowedAlgorithms = append(result.AllowedAlgorithms, alg)
+			}
+		}
+	}
+	if config.ReplayCache != nil {
+		if config.ReplayCache.StoreIfNew == nil {
+			return nil, errors.New("DPoP replay cache must implement StoreIfNew")
+		}
+		result.ReplayCache = *config.ReplayCache
+	} else {
+		result.ReplayCache = MakeInMemoryDPoPReplayCache()
+	}
+	return result, nil
+}
+
+// MakeInMemoryDPoPReplayCache returns a DPoP replay cache that keeps the IDs
+// of proofs in process memory
+func MakeInMemoryDPoPReplayCache() sessmodels.DPoPReplayCache {
+	var mutex sync.Mutex
+	seenUntil := map[string]time.Time{}
+	nextCleanup := time.Now()
+
+	storeIfNew := func(jti string, expiresAt time.Time, userContext supertokens.UserContext) (bool, error) {
+		mutex.Lock()
+		defer mutex.Unlock()
+
+		now := time.Now()
+		if !now.Before(nextCleanup) {
+			for id, until := range seenUntil {
+				if !now.Before(until) {
+					delete(seenUntil, id)
+				}
+			}
+			nextCleanup = now.Add(time.Minute)
+		}
+		if until, ok := seenUntil[jti]; ok && now.Before(until) {
+			return false, nil
+		}
+		seenUntil[jti] = expiresAt
+		return true, nil
+	}
+	return sessmodels.DPoPReplayCache{StoreIfNew: &storeIfNew}
+}
+
+type dpopProof struct {
+	thumbprint string
+}
+
+// validateDPoPProof checks the DPoP header of the request in the user
+// context. ath is only checked if accessToken is not empty. It returns nil
+// if the request has no proof.
+func validateDPoPProof(config *sessmodels.NormalisedDPoPConfig, appInfo supertokens.NormalisedAppinfo, accessToken string, userContext supertokens.UserContext) (*dpopProof, error) {
+	req := supertokens.GetRequestFromUserContext(userContext)
+	if req == nil {
+		return nil, nil
+	}
+	proofs := req.Header.Values(dpopHeaderName)
+	if len(proofs) == 0 {
+		return nil, nil
+	}
+	if len(proofs) > 1 {
+		return nil, errors.New("more than one DPoP proof was sent")
+	}
+
+	var thumbprint string
+	options := append([]jwt.ParserOption{jwt.WithValidMethods(config.AllowedAlgorithms)}, supertokens.GetJWTParserOptions()...)
+	token, err := jwt.Parse(proofs[0], func(token *jwt.Token) (interface{}, error) {
+		if token.Header["typ"] != "dpop+jwt" {
+			return nil, errors.New("the typ of a DPoP proof must be dpop+jwt")
+		}
+		jwk, ok := token.Header["jwk"].(map[string]interface{})
+		if !ok {
+			return nil, errors.New("the DPoP proof does not contain a jwk")
+		}
+		key, keyThumbprint, err := parseDPoPPublicKey(jwk)
+		if err != nil {
+			return nil, err
+		}
+		thumbprint = keyThumbprint
+		return key, nil
+	}, options...)
+	if err != nil {
+		return nil, fmt.Errorf("invalid DPoP proof: %w", err)
+	}
+	claims, ok := token.Claims.(jwt.MapClaims)
+	if !ok {
+		return nil, errors.New("invalid DPoP proof claims")
+	}
+
+	if htm, _ := claims["htm"].(string); htm != req.Method {
+		return nil, errors.New("the htm of the DPoP proof does not match the request method")
+	}
+	if htu, _ := claims["htu"].(string); !isSameDPoPTargetURI(htu, getDPoPTargetURI(appInfo, req)) {
+		return nil, errors.New("the htu of the DPoP proof does not match the request URL")
+	}
+	iat, _ := claims["iat"].(float64)
+	issuedAt := time.Unix(int64(iat), 0)
+	now := time.Now()
+	if iat == 0 || issuedAt.Before(now.Add(-config.MaxAge)) || issuedAt.After(now.Add(dpopClockSkew)) {
+		return nil, errors.New("the DPoP proof has expired or was issued in the future")
+	}
+	if accessToken != "" {
+		hash := sha256.Sum256([]byte(accessToken))
+		expected := base64.RawURLEncoding.EncodeToString(hash[:])
+		if ath, _ := claims["ath"].(string); subtle.ConstantTimeCompare([]byte(ath), []byte(expected)) != 1 {
+			return nil, errors.New("the ath of the DPoP proof does not match the access token")
+		}
+	}
+	jti, _ := claims["jti"].(string)
+	if jti == "" {
+		return nil, errors.New("the DPoP proof does not have a jti")
+	}
+	isNew, err := (*config.ReplayCache.StoreIfNew)(jti, issuedAt.Add(config.MaxAge+dpopClockSkew), userContext)
+	if err != nil {
+		return nil, err
+	}
+	if !isNew {
+		return nil, errors.New("the DPoP proof was already used")
+	}
+	return &dpopProof{thumbprint: thumbprint}, nil
+}
+
+// getDPoPTargetURI returns the URL of the request as seen by the client,
+// without query or fragment
+func getDPoPTargetURI(appInfo supertokens.NormalisedAppinfo, req *http.Request) string {
+	return appInfo.APIDomain.GetAsStringDangerous() + appInfo.APIGatewayPath.GetAsStringDangerous() + req.URL.Path
+}
+
+func isSameDPoPTargetURI(htu string, expected string) bool {
+	if idx := strings.IndexAny(htu, "?#"); idx >= 0 {
+		htu = htu[:idx]
+	}
+	return strings.EqualFold(strings.TrimSuffix(htu, "/"), strings.TrimSuffix(expected, "/"))
+}
+
+// parseDPoPPublicKey returns the public key of a JWK and its RFC 7638
+// thumbprint
+func parseDPoPPublicKey(jwk map[string]interface{}) (crypto.PublicKey, string, error) {
+	member := func(name string) (string, []byte, error) {
+		value, _ := jwk[name].(string)
+		decoded, err := base64.RawURLEncoding.DecodeString(value)
+		if value == "" || err != nil {
+			return "", nil, fmt.Errorf("invalid %s in DPoP jwk", name)
+		}
+		return value, decoded, nil
+	}
+	if _, hasPrivateKey := jwk["d"]; hasPrivateKey {
+		return nil, "", errors.New("the DPoP jwk must not contain a private key")
+	}
+	kty, _ := jwk["kty"].(string)
+	crv, _ := jwk["crv"].(string)
+	var key crypto.PublicKey
+	var thumbprintMembers string
+	switch kty {
+	case "EC":
+		var curve elliptic.Curve
+		switch crv {
+		case "P-256":
+			curve = elliptic.P256()
+		case "P-384":
+			curve = elliptic.P384()
+		case "P-521":
+			curve = elliptic.P521()
+		default:
+			return nil, "", fmt.Errorf("unsupported DPoP jwk curve: %s", crv)
+		}
+		x, xBytes, err := member("x")
+		if err != nil {
+			return nil, "", err
+		}
+		y, yBytes, err := member("y")
+		if err != nil {
+			return nil, "", err
+		}
+		publicKey := &ecdsa.PublicKey{Curve: curve, X: new(big.Int).SetBytes(xBytes), Y: new(big.Int).SetBytes(yBytes)}
+		if !curve.IsOnCurve(publicKey.X, publicKey.Y) {
+			return nil, "", errors.New("invalid DPoP jwk point")
+		}
+		key = publicKey
+		thumbprintMembers = fmt.Sprintf(`{"crv":%q,"kty":"EC","x":%q,"y":%q}`, crv, x, y)
+	case "RSA":
+		n, nBytes, err := member("n")
+		if err != nil {
+			return nil, "", err
+		}
+		e, eBytes, err := member("e")
+		if err != nil {
+			return nil, "", err
+		}
+		exponent := new(big.Int).SetBytes(eBytes)
+		if !exponent.IsInt64() || exponent.Int64() > 1<<31-1 {
+			return nil, "", errors.New("invalid DPoP jwk exponent")
+		}
+		key = &rsa.PublicKey{N: new(big.Int).SetBytes(nBytes), E: int(exponent.Int64())}
+		thumbprintMembers = fmt.Sprintf(`{"e":%q,"kty":"RSA","n":%q}`, e, n)
+	case "OKP":
+		if crv != "Ed25519" {
+			return nil, "", fmt.Errorf("unsupported DPoP jwk curve: %s", crv)
+		}
+		x, xBytes, err := member("x")
+		if err != nil {
+			return nil, "", err
+		}
+		if len(xBytes) != ed25519.PublicKeySize {
+			return nil, "", errors.New("invalid DPoP jwk key size")
+		}
+		key = ed25519.PublicKey(xBytes)
+		thumbprintMembers = fmt.Sprintf(`{"crv":"Ed25519","kty":"OKP","x":%q}`, x)
+	default:
+		return nil, "", fmt.Errorf("unsupported DPoP jwk key type: %s", kty)
+	}
+	hash := sha256.Sum256([]byte(thumbprintMembers))
+	return key, base64.RawURLEncoding.EncodeToString(hash[:]), nil
+}
+
+func getDPoPThumbprintFromPayload(accessTokenPayload map[string]interface{}) string {
+	confirmation, _ := accessTokenPayload[dpopConfirmationClaim].(map[string]interface{})
+	thumbprint, _ := confirmation[dpopThumbprintClaim].(string)
+	return thumbprint
+}
+
+func invalidDPoPProofError(err error) error {
+	supertokens.LogDebugMessage("DPoP: " + err.Error())
+	clearTokens := false
+	return sessionErrors.UnauthorizedError{Msg: err.Error(), ClearTokens: &clearTokens}
+}
+
+// addDPoPBinding binds a new session to the key of the request's DPoP proof,
+// if it has one, by adding the key's thumbprint to the access token payload
+func addDPoPBinding(config sessmodels.TypeNormalisedInput, appInfo supertokens.NormalisedAppinfo, accessTokenPayload map[string]interface{}, userContext supertokens.UserContext) (map[string]interface{}, error) {
+	if config.DPoP == nil {
+		return accessTokenPayload, nil
+	}
+	proof, err := validateDPoPProof(config.DPoP, appInfo, "", userContext)
+	if err != nil {
+		return nil, invalidDPoPProofError(err)
+	}
+	if proof == nil {
+		return accessTokenPayload, nil
+	}
+	result := map[string]interface{}{}
+	for k, v := range accessTokenPayload {
+		result[k] = v
+	}
+	result[dpopConfirmationClaim] = map[string]interface{}{dpopThumbprintClaim: proof.thumbprint}
+	return result, nil
+}
+
+// checkDPoPBinding makes sure that requests using a sender constrained
+// access token come with a proof signed by the key the session is bound to
+func checkDPoPBinding(config sessmodels.TypeNormalisedInput, appInfo supertokens.NormalisedAppinfo, accessToken string, accessTokenPayload map[string]interface{}, userContext supertokens.UserContext) error {
+	if config.DPoP == nil {
+		return nil
+	}
+	thumbprint := getDPoPThumbprintFromPayload(accessTokenPayload)
+	if thumbprint == "" {
+		return nil
+	}
+	proof, err := validateDPoPProof(config.DPoP, appInfo, accessToken, userContext)
+	if err != nil {
+		return invalidDPoPProofError(err)
+	}
+	if proof == nil {
+		return invalidDPoPProofError(errors.New("the session is DPoP bound but the request has no DPoP proof"))
+	}
+	if proof.thumbprint != thumbprint {
+		return invalidDPoPProofError(errors.New("the DPoP proof is signed with another key than the one the session is bound to"))
+	}
+	return nil
+}
+
+// checkDPoPBindingOnRefresh is called after a session is refreshed. If the
+// session is DPoP bound and the refresh does not come with a proof from the
+// same key, the session is revoked since its refresh token was probably
+// stolen.
+func checkDPoPBindingOnRefresh(config sessmodels.TypeNormalisedInput, appInfo supertokens.NormalisedAppinfo, querier supertokens.Querier, sessionHandle string, accessTokenPayload map[string]interface{}, userContext supertokens.UserContext) error {
+	err := checkDPoPBinding(config, appInfo, "", accessTokenPayload, userContext)
+	if err == nil {
+		return nil
+	}
+	_, revokeErr := revokeSessionHelper(querier, sessionHandle, userContext)
+	if revokeErr != nil {
+		return revokeErr
+	}
+	clearTokens := true
+	return sessionErrors.UnauthorizedError{Msg: err.Error(), ClearTokens: &clearTokens}
+}
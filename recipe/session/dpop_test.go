@@ -0,0 +1,166 @@
+/* Copyright (c) 2023, VRAI Labs and/or its affiliates. All rights reserved.
+ *
+ * This software is licensed under the Apache License, Version 2.0 (the
+ * "License") as published by the Apache Software Foundation.
+ *
+ * You may not use this file except in compliance with the License. You may
+ * obtain a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+ * License for the specific language governing permissions and limitations
+ * under the License.
+ */
+
+package session
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	sessionErrors "github.com/supertokens/supertokens-golang/recipe/session/errors"
+	"github.com/supertokens/supertokens-golang/recipe/session/sessmodels"
+	"github.com/supertokens/supertokens-golang/supertokens"
+)
+
+type dpopTestClient struct {
+	key *ecdsa.PrivateKey
+	jwk map[string]interface{}
+}
+
+func makeDPoPTestClient(t *testing.T) dpopTestClient {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NoError(t, err)
+	return dpopTestClient{key: key, jwk: map[string]interface{}{
+		"kty": "EC",
+		"crv": "P-256",
+		"x":   base64.RawURLEncoding.EncodeToString(key.X.FillBytes(make([]byte, 32))),
+		"y":   base64.RawURLEncoding.EncodeToString(key.Y.FillBytes(make([]byte, 32))),
+	}}
+}
+
+func (c dpopTestClient) proof(t *testing.T, method string, url string, accessToken string, jti string, issuedAt time.Time) string {
+	claims := jwt.MapClaims{"htm": method, "htu": url, "jti": jti, "iat": issuedAt.Unix()}
+	if accessToken != "" {
+		hash := sha256.Sum256([]byte(accessToken))
+		claims["ath"] = base64.RawURLEncoding.EncodeToString(hash[:])
+	}
+	token := jwt.NewWithClaims(jwt.SigningMethodES256, claims)
+	token.Header["typ"] = "dpop+jwt"
+	token.Header["jwk"] = c.jwk
+	signed, err := token.SignedString(c.key)
+	assert.NoError(t, err)
+	return signed
+}
+
+func dpopTestUserContext(method string, path string, proof string) supertokens.UserContext {
+	req := httptest.NewRequest(method, path, nil)
+	if proof != "" {
+		req.Header.Set(dpopHeaderName, proof)
+	}
+	return supertokens.MakeDefaultUserContextFromAPI(req)
+}
+
+func makeDPoPTestConfig(t *testing.T) (sessmodels.TypeNormalisedInput, supertokens.NormalisedAppinfo) {
+	dpop, err := normaliseDPoPInput(&sessmodels.DPoPInput{})
+	assert.NoError(t, err)
+	apiDomain, err := supertokens.NewNormalisedURLDomain("https://api.example.com")
+	assert.NoError(t, err)
+	gatewayPath, err := supertokens.NewNormalisedURLPath("")
+	assert.NoError(t, err)
+	return sessmodels.TypeNormalisedInput{DPoP: dpop}, supertokens.NormalisedAppinfo{APIDomain: apiDomain, APIGatewayPath: gatewayPath}
+}
+
+func TestDPoPConfigValidation(t *testing.T) {
+	config, err := normaliseDPoPInput(nil)
+	assert.NoError(t, err)
+	assert.Nil(t, config)
+
+	config, err = normaliseDPoPInput(&sessmodels.DPoPInput{})
+	assert.NoError(t, err)
+	assert.Equal(t, time.Minute, config.MaxAge)
+	assert.Equal(t, defaultDPoPAlgorithms, config.AllowedAlgorithms)
+
+	_, err = normaliseDPoPInput(&sessmodels.DPoPInput{AllowedAlgorithms: []string{"HS256"}})
+	assert.Error(t, err)
+	_, err = normaliseDPoPInput(&sessmodels.DPoPInput{ReplayCache: &sessmodels.DPoPReplayCache{}})
+	assert.Error(t, err)
+}
+
+func TestSessionsCreatedWithADPoPProofAreBoundToItsKey(t *testing.T) {
+	config, appInfo := makeDPoPTestConfig(t)
+	client := makeDPoPTestClient(t)
+
+	payload, err := addDPoPBinding(config, appInfo, map[string]interface{}{"key": "value"}, dpopTestUserContext(http.MethodPost, "/auth/signin", ""))
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{"key": "value"}, payload)
+
+	proof := client.proof(t, http.MethodPost, "https://api.example.com/auth/signin", "", "jti1", time.Now())
+	payload, err = addDPoPBinding(config, appInfo, map[string]interface{}{"key": "value"}, dpopTestUserContext(http.MethodPost, "/auth/signin", proof))
+	assert.NoError(t, err)
+	_, thumbprint, err := parseDPoPPublicKey(client.jwk)
+	assert.NoError(t, err)
+	assert.Equal(t, thumbprint, getDPoPThumbprintFromPayload(payload))
+	assert.Equal(t, "value", payload["key"])
+
+	// unbound sessions do not need a proof
+	assert.NoError(t, checkDPoPBinding(config, appInfo, "token", map[string]interface{}{}, dpopTestUserContext(http.MethodGet, "/user", "")))
+}
+
+func TestDPoPProofsAreCheckedForBoundSessions(t *testing.T) {
+	config, appInfo := makeDPoPTestConfig(t)
+	client := makeDPoPTestClient(t)
+	_, thumbprint, err := parseDPoPPublicKey(client.jwk)
+	assert.NoError(t, err)
+	payload := map[string]interface{}{dpopConfirmationClaim: map[string]interface{}{dpopThumbprintClaim: thumbprint}}
+	url := "https://api.example.com/user"
+
+	check := func(proof string) error {
+		return checkDPoPBinding(config, appInfo, "token", payload, dpopTestUserContext(http.MethodGet, "/user?id=1", proof))
+	}
+
+	assert.NoError(t, check(client.proof(t, http.MethodGet, url, "token", "jti1", time.Now())))
+
+	// replayed proof
+	assert.Error(t, check(client.proof(t, http.MethodGet, url, "token", "jti1", time.Now())))
+	// no proof
+	assert.Error(t, check(""))
+	// wrong method, URL, access token or age
+	assert.Error(t, check(client.proof(t, http.MethodPost, url, "token", "jti2", time.Now())))
+	assert.Error(t, check(client.proof(t, http.MethodGet, "https://other.example.com/user", "token", "jti3", time.Now())))
+	assert.Error(t, check(client.proof(t, http.MethodGet, url, "other-token", "jti4", time.Now())))
+	assert.Error(t, check(client.proof(t, http.MethodGet, url, "token", "jti5", time.Now().Add(-2*time.Minute))))
+	// another key
+	err = check(makeDPoPTestClient(t).proof(t, http.MethodGet, url, "token", "jti6", time.Now()))
+	assert.IsType(t, sessionErrors.UnauthorizedError{}, err)
+	assert.False(t, *err.(sessionErrors.UnauthorizedError).ClearTokens)
+
+	assert.NoError(t, check(client.proof(t, http.MethodGet, url, "token", "jti7", time.Now())))
+}
+
+func TestInMemoryDPoPReplayCache(t *testing.T) {
+	cache := MakeInMemoryDPoPReplayCache()
+	userContext := &map[string]interface{}{}
+
+	isNew, err := (*cache.StoreIfNew)("jti", time.Now().Add(50*time.Millisecond), userContext)
+	assert.NoError(t, err)
+	assert.True(t, isNew)
+	isNew, err = (*cache.StoreIfNew)("jti", time.Now().Add(50*time.Millisecond), userContext)
+	assert.NoError(t, err)
+	assert.False(t, isNew)
+
+	time.Sleep(60 * time.Millisecond)
+	isNew, err = (*cache.StoreIfNew)("jti", time.Now().Add(50*time.Millisecond), userContext)
+	assert.NoError(t, err)
+	assert.True(t, isNew)
+}
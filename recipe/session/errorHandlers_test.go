@@ -0,0 +1,90 @@
+/* Copyright (c) 2023, VRAI Labs and/or its affiliates. All rights reserved.
+ *
+ * This software is licensed under the Apache License, Version 2.0 (the
+ * "License") as published by the Apache Software Foundation.
+ *
+ * You may not use this file except in compliance with the License. You may
+ * obtain a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+ * License for the specific language governing permissions and limitations
+ * under the License.
+ */
+
+package session
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/supertokens/supertokens-golang/recipe/session/claims"
+	"github.com/supertokens/supertokens-golang/recipe/session/errors"
+	"github.com/supertokens/supertokens-golang/recipe/session/sessmodels"
+	"github.com/supertokens/supertokens-golang/supertokens"
+)
+
+func TestCustomErrorHandlers(t *testing.T) {
+	writeJSON := func(res http.ResponseWriter, statusCode int, code string) error {
+		res.Header().Set("Content-Type", "application/json")
+		res.WriteHeader(statusCode)
+		return json.NewEncoder(res).Encode(map[string]string{"code": code})
+	}
+
+	resetAll()
+	defer resetAll()
+	err := supertokens.Init(supertokens.TypeInput{
+		Supertokens: &supertokens.ConnectionInfo{
+			ConnectionURI: "http://localhost:8080",
+		},
+		AppInfo: supertokens.AppInfo{
+			AppName:       "SuperTokens",
+			WebsiteDomain: "supertokens.io",
+			APIDomain:     "api.supertokens.io",
+		},
+		RecipeList: []supertokens.Recipe{Init(&sessmodels.TypeInput{
+			ErrorHandlers: &sessmodels.ErrorHandlers{
+				OnUnauthorised: func(message string, req *http.Request, res http.ResponseWriter) error {
+					return writeJSON(res, http.StatusUnauthorized, "LOGIN_REQUIRED")
+				},
+				OnTryRefreshToken: func(message string, req *http.Request, res http.ResponseWriter) error {
+					return writeJSON(res, http.StatusUnauthorized, "REFRESH_REQUIRED")
+				},
+				OnTokenTheftDetected: func(sessionHandle string, userID string, req *http.Request, res http.ResponseWriter) error {
+					return writeJSON(res, http.StatusConflict, "THEFT:"+sessionHandle+":"+userID)
+				},
+				OnInvalidClaim: func(validationErrors []claims.ClaimValidationError, req *http.Request, res http.ResponseWriter) error {
+					return writeJSON(res, http.StatusPaymentRequired, "CLAIM:"+validationErrors[0].ID)
+				},
+			},
+		})},
+	})
+	assert.NoError(t, err)
+	instance, err := getRecipeInstanceOrThrowError()
+	assert.NoError(t, err)
+
+	for _, testCase := range []struct {
+		err        error
+		statusCode int
+		code       string
+	}{
+		{errors.UnauthorizedError{Msg: "unauthorised"}, http.StatusUnauthorized, "LOGIN_REQUIRED"},
+		{errors.TryRefreshTokenError{Msg: "expired"}, http.StatusUnauthorized, "REFRESH_REQUIRED"},
+		{fmt.Errorf("wrapped: %w", errors.TokenTheftDetectedError{Msg: "theft", Payload: errors.TokenTheftDetectedErrorPayload{SessionHandle: "handle1", UserID: "user1"}}), http.StatusConflict, "THEFT:handle1:user1"},
+		{fmt.Errorf("wrapped: %w", errors.InvalidClaimError{Msg: "invalid claims", InvalidClaims: []claims.ClaimValidationError{{ID: "st-ev"}}}), http.StatusPaymentRequired, "CLAIM:st-ev"},
+	} {
+		res := httptest.NewRecorder()
+		handled, err := instance.handleError(testCase.err, httptest.NewRequest(http.MethodGet, "/", nil), res, &map[string]interface{}{})
+		assert.True(t, handled)
+		assert.NoError(t, err)
+		assert.Equal(t, testCase.statusCode, res.Code)
+		var body map[string]string
+		assert.NoError(t, json.NewDecoder(res.Body).Decode(&body))
+		assert.Equal(t, testCase.code, body["code"])
+	}
+}
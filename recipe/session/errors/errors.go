@@ -15,7 +15,12 @@
 
 package errors
 
-import "github.com/supertokens/supertokens-golang/recipe/session/claims"
+import (
+	"errors"
+	"fmt"
+
+	"github.com/supertokens/supertokens-golang/recipe/session/claims"
+)
 
 const (
 	UnauthorizedErrorStr       = "UNAUTHORISED"
@@ -24,6 +29,18 @@ const (
 	InvalidClaimsErrorStr      = "INVALID_CLAIMS"
 )
 
+// Sentinel errors that the errors of this package match with errors.Is, e.g.
+// errors.Is(err, ErrTryRefreshToken). Use errors.As to get the details of an
+// error, such as the session handle and user ID of a TokenTheftDetectedError.
+var (
+	ErrUnauthorized       = errors.New("unauthorised")
+	ErrTryRefreshToken    = errors.New("try refresh token")
+	ErrTokenTheftDetected = errors.New("token theft detected")
+	ErrInvalidClaims      = errors.New("invalid claims")
+	ErrPayloadTooLarge    = errors.New("access token payload too large")
+	ErrSessionSuspended   = errors.New("session suspended")
+)
+
 // TryRefreshTokenError used for when the refresh API needs to be called
 type TryRefreshTokenError struct {
 	Msg string
@@ -33,6 +50,10 @@ func (err TryRefreshTokenError) Error() string {
 	return err.Msg
 }
 
+func (err TryRefreshTokenError) Is(target error) bool {
+	return target == ErrTryRefreshToken
+}
+
 // TokenTheftDetectedError used for when token theft has happened for a session
 type TokenTheftDetectedError struct {
 	Msg     string
@@ -48,6 +69,10 @@ func (err TokenTheftDetectedError) Error() string {
 	return err.Msg
 }
 
+func (err TokenTheftDetectedError) Is(target error) bool {
+	return target == ErrTokenTheftDetected
+}
+
 // UnauthorizedError used for when the user has been logged out
 type UnauthorizedError struct {
 	Msg         string
@@ -58,6 +83,10 @@ func (err UnauthorizedError) Error() string {
 	return err.Msg
 }
 
+func (err UnauthorizedError) Is(target error) bool {
+	return target == ErrUnauthorized
+}
+
 type InvalidClaimError struct {
 	Msg           string
 	InvalidClaims []claims.ClaimValidationError
@@ -66,3 +95,38 @@ type InvalidClaimError struct {
 func (err InvalidClaimError) Error() string {
 	return err.Msg
 }
+
+func (err InvalidClaimError) Is(target error) bool {
+	return target == ErrInvalidClaims
+}
+
+// PayloadTooLargeError is returned when an access token payload is larger
+// than the configured AccessTokenPayloadSize.MaxBytes
+type PayloadTooLargeError struct {
+	Size    int
+	MaxSize int
+}
+
+func (err PayloadTooLargeError) Error() string {
+	return fmt.Sprintf("access token payload is %d bytes, more than the maximum of %d bytes", err.Size, err.MaxSize)
+}
+
+func (err PayloadTooLargeError) Is(target error) bool {
+	return target == ErrPayloadTooLarge
+}
+
+// SessionSuspendedError is returned when verifying a session that was
+// suspended with session.SuspendSession
+type SessionSuspendedError struct {
+	SessionHandle string
+	UserID        string
+	Reason        string
+}
+
+func (err SessionSuspendedError) Error() string {
+	return "session is suspended"
+}
+
+func (err SessionSuspendedError) Is(target error) bool {
+	return target == ErrSessionSuspended
+}
@@ -0,0 +1,128 @@
+/* Copyright (c) 2023, VRAI Labs and/or its affiliates. All rights reserved.
+ *
+ * This software is licensed under the Apache License, Version 2.0 (the
+ * "License") as published by the Apache Software Foundation.
+ *
+ * You may not use this file except in compliance with the License. You may
+ * obtain a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+ * License for the specific language governing permissions and limitations
+ * under the License.
+ */
+
+package session
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"reflect"
+
+	"github.com/supertokens/supertokens-golang/recipe/session/claims"
+	"github.com/supertokens/supertokens-golang/recipe/session/sessmodels"
+	"github.com/supertokens/supertokens-golang/supertokens"
+)
+
+// frontTokenCookieKey is the cookie in which the frontend SDKs keep the front
+// token, on the website domain
+const frontTokenCookieKey = "sFrontToken"
+
+var errFrontTokenMismatch = errors.New("front token does not belong to the access token")
+
+func buildFrontToken(config sessmodels.TypeNormalisedInput, userId string, atExpiry uint64, jwtPayload map[string]interface{}) string {
+	return BuildFrontToken(userId, atExpiry, getFrontTokenPayload(config, jwtPayload))
+}
+
+func getFrontTokenPayload(config sessmodels.TypeNormalisedInput, jwtPayload map[string]interface{}) map[string]interface{} {
+	if config.FrontTokenPayloadFields == nil {
+		return jwtPayload
+	}
+	frontTokenPayload := map[string]interface{}{}
+	for _, field := range config.FrontTokenPayloadFields {
+		if value, ok := jwtPayload[field]; ok {
+			frontTokenPayload[field] = value
+		}
+	}
+	return frontTokenPayload
+}
+
+// GetFrontTokenFromRequest returns the front token that the frontend SDKs
+// keep in a cookie on the website domain, or that was sent in the front-token
+// header, e.g. to a server rendering pages on the website domain.
+func GetFrontTokenFromRequest(req *http.Request) *string {
+	frontToken := GetCookieValue(req, frontTokenCookieKey)
+	if frontToken == nil {
+		frontToken = getHeader(req, frontTokenHeaderKey)
+	}
+	if frontToken != nil && *frontToken == "remove" {
+		return nil
+	}
+	return frontToken
+}
+
+// ParseFrontToken decodes a front token into the user ID, the expiry of the
+// access token (in ms) and the fields of the access token payload it carries.
+// The front token is not signed, so it should only be used for display
+// purposes unless it is checked with VerifyFrontToken.
+func ParseFrontToken(frontToken string) (*TokenInfo, error) {
+	decoded, err := base64.StdEncoding.DecodeString(frontToken)
+	if err != nil {
+		return nil, err
+	}
+	var tokenInfo TokenInfo
+	err = json.Unmarshal(decoded, &tokenInfo)
+	if err != nil {
+		return nil, err
+	}
+	if tokenInfo.Uid == "" {
+		return nil, errors.New("front token does not have a user ID")
+	}
+	return &tokenInfo, nil
+}
+
+// VerifyFrontToken parses a front token and checks that it was built from the
+// given access token, after verifying the access token (without calling the
+// core or validating the session's claims). It returns a TryRefreshTokenError
+// if the access token expired.
+func VerifyFrontToken(frontToken string, accessToken string, userContext ...supertokens.UserContext) (*TokenInfo, error) {
+	tokenInfo, err := ParseFrontToken(frontToken)
+	if err != nil {
+		return nil, err
+	}
+
+	antiCsrfCheck := false
+	checkDatabase := false
+	sessionContainer, err := GetSessionWithoutRequestResponse(accessToken, nil, &sessmodels.VerifySessionOptions{
+		AntiCsrfCheck: &antiCsrfCheck,
+		CheckDatabase: &checkDatabase,
+		OverrideGlobalClaimValidators: func(globalClaimValidators []claims.SessionClaimValidator, sessionContainer sessmodels.SessionContainer, userContext supertokens.UserContext) ([]claims.SessionClaimValidator, error) {
+			return []claims.SessionClaimValidator{}, nil
+		},
+	}, userContext...)
+	if err != nil {
+		return nil, err
+	}
+
+	if tokenInfo.Uid != sessionContainer.GetUserID() {
+		return nil, errFrontTokenMismatch
+	}
+	accessTokenPayload := sessionContainer.GetAccessTokenPayload()
+	exp, ok := accessTokenPayload["exp"].(float64)
+	if !ok || uint64(exp) != tokenInfo.Ate/1000 {
+		return nil, errFrontTokenMismatch
+	}
+	frontTokenPayload, ok := tokenInfo.Up.(map[string]interface{})
+	if !ok {
+		return nil, errFrontTokenMismatch
+	}
+	for k, v := range frontTokenPayload {
+		if accessTokenValue, ok := accessTokenPayload[k]; !ok || !reflect.DeepEqual(v, accessTokenValue) {
+			return nil, errFrontTokenMismatch
+		}
+	}
+	return tokenInfo, nil
+}
@@ -0,0 +1,118 @@
+/* Copyright (c) 2023, VRAI Labs and/or its affiliates. All rights reserved.
+ *
+ * This software is licensed under the Apache License, Version 2.0 (the
+ * "License") as published by the Apache Software Foundation.
+ *
+ * You may not use this file except in compliance with the License. You may
+ * obtain a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+ * License for the specific language governing permissions and limitations
+ * under the License.
+ */
+
+package session
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/supertokens/supertokens-golang/recipe/session/sessmodels"
+	"github.com/supertokens/supertokens-golang/supertokens"
+)
+
+func TestFrontTokenOnlyCarriesTheConfiguredPayloadFields(t *testing.T) {
+	config := sessmodels.TypeNormalisedInput{FrontTokenPayloadFields: []string{"role", "st-ev"}}
+	frontToken := buildFrontToken(config, "user1", 5000, map[string]interface{}{"role": "admin", "secret": "value", "sub": "user1"})
+
+	tokenInfo, err := ParseFrontToken(frontToken)
+	assert.NoError(t, err)
+	assert.Equal(t, &TokenInfo{Uid: "user1", Ate: 5000, Up: map[string]interface{}{"role": "admin"}}, tokenInfo)
+
+	frontToken = buildFrontToken(sessmodels.TypeNormalisedInput{}, "user1", 5000, map[string]interface{}{"role": "admin", "secret": "value"})
+	tokenInfo, err = ParseFrontToken(frontToken)
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{"role": "admin", "secret": "value"}, tokenInfo.Up)
+
+	_, err = ParseFrontToken("not a front token")
+	assert.Error(t, err)
+}
+
+func TestGetFrontTokenFromRequest(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, "/dashboard", nil)
+	assert.Nil(t, GetFrontTokenFromRequest(req))
+
+	req.Header.Set("front-token", "from-header")
+	assert.Equal(t, "from-header", *GetFrontTokenFromRequest(req))
+
+	req.AddCookie(&http.Cookie{Name: "sFrontToken", Value: "from-cookie"})
+	assert.Equal(t, "from-cookie", *GetFrontTokenFromRequest(req))
+}
+
+func TestVerifyFrontToken(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	assert.NoError(t, err)
+	expiry := time.Now().Add(time.Hour)
+	token := jwt.NewWithClaims(jwt.SigningMethodRS256, jwt.MapClaims{"sub": "user1", "sessionHandle": "handle1", "refreshTokenHash1": "hash", "iat": time.Now().Unix(), "exp": expiry.Unix(), "tId": "public", "role": "admin"})
+	token.Header["kid"] = "d-1"
+	accessToken, err := token.SignedString(key)
+	assert.NoError(t, err)
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/apiversion", func(rw http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(rw).Encode(map[string]interface{}{"versions": []string{"3.0"}})
+	})
+	mux.HandleFunc("/.well-known/jwks.json", func(rw http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(rw).Encode(map[string]interface{}{"keys": []interface{}{map[string]interface{}{
+			"kty": "RSA",
+			"kid": "d-1",
+			"alg": "RS256",
+			"use": "sig",
+			"n":   base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+			"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+		}}})
+	})
+	testServer := httptest.NewServer(mux)
+	defer testServer.Close()
+
+	resetAll()
+	defer resetAll()
+	err = supertokens.Init(supertokens.TypeInput{
+		Supertokens: &supertokens.ConnectionInfo{
+			ConnectionURI: testServer.URL,
+		},
+		AppInfo: supertokens.AppInfo{
+			AppName:       "SuperTokens",
+			WebsiteDomain: "supertokens.io",
+			APIDomain:     "api.supertokens.io",
+		},
+		RecipeList: []supertokens.Recipe{Init(nil)},
+	})
+	assert.NoError(t, err)
+
+	ate := uint64(expiry.Unix()) * 1000
+	tokenInfo, err := VerifyFrontToken(BuildFrontToken("user1", ate, map[string]interface{}{"role": "admin"}), accessToken)
+	assert.NoError(t, err)
+	assert.Equal(t, "user1", tokenInfo.Uid)
+	assert.Equal(t, map[string]interface{}{"role": "admin"}, tokenInfo.Up)
+
+	_, err = VerifyFrontToken(BuildFrontToken("user1", ate, map[string]interface{}{"role": "superadmin"}), accessToken)
+	assert.Equal(t, errFrontTokenMismatch, err)
+
+	_, err = VerifyFrontToken(BuildFrontToken("user2", ate, map[string]interface{}{}), accessToken)
+	assert.Equal(t, errFrontTokenMismatch, err)
+
+	_, err = VerifyFrontToken(BuildFrontToken("user1", ate+3600000, map[string]interface{}{}), accessToken)
+	assert.Equal(t, errFrontTokenMismatch, err)
+}
@@ -0,0 +1,147 @@
+/* Copyright (c) 2023, VRAI Labs and/or its affiliates. All rights reserved.
+ *
+ * This software is licensed under the Apache License, Version 2.0 (the
+ * "License") as published by the Apache Software Foundation.
+ *
+ * You may not use this file except in compliance with the License. You may
+ * obtain a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+ * License for the specific language governing permissions and limitations
+ * under the License.
+ */
+
+package session
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"net/http"
+	"strings"
+
+	"github.com/supertokens/supertokens-golang/recipe/session/claims"
+	"github.com/supertokens/supertokens-golang/recipe/session/sessmodels"
+	"github.com/supertokens/supertokens-golang/supertokens"
+)
+
+const (
+	// guestSessionKey is the access token payload key marking guest sessions
+	guestSessionKey = "st-guest"
+	// GuestUserIDPrefix starts the user IDs of guest sessions
+	GuestUserIDPrefix = "guest-"
+	// GuestSessionValidatorID is the ID of the global claim validator that
+	// rejects guest sessions
+	GuestSessionValidatorID = "st-guest"
+)
+
+func normaliseGuestSessionsInput(config *sessmodels.GuestSessionsInput) *sessmodels.NormalisedGuestSessionsConfig {
+	if config == nil {
+		return nil
+	}
+	return &sessmodels.NormalisedGuestSessionsConfig{
+		OnUpgrade: config.OnUpgrade,
+	}
+}
+
+func isGuestSessionPayload(accessTokenPayload map[string]interface{}) bool {
+	isGuest, _ := accessTokenPayload[guestSessionKey].(bool)
+	return isGuest
+}
+
+// guestSessionValidator is added to the global claim validators when guest
+// sessions are enabled, so that guests can only use the APIs that allow them
+var guestSessionValidator = claims.SessionClaimValidator{
+	ID: GuestSessionValidatorID,
+	Validate: func(payload map[string]interface{}, userContext supertokens.UserContext) claims.ClaimValidationResult {
+		if isGuestSessionPayload(payload) {
+			return claims.ClaimValidationResult{
+				IsValid: false,
+				Reason:  map[string]interface{}{"message": "guest sessions are not allowed"},
+			}
+		}
+		return claims.ClaimValidationResult{IsValid: true}
+	},
+}
+
+func generateGuestUserID() (string, error) {
+	id := make([]byte, 16)
+	_, err := rand.Read(id)
+	if err != nil {
+		return "", err
+	}
+	return GuestUserIDPrefix + hex.EncodeToString(id), nil
+}
+
+// getGuestSessionPayloadToKeep returns the keys of a guest's access token
+// payload that are kept when it signs up. The keys set by SuperTokens
+// (including claims, which are built again for the new user) are dropped.
+func getGuestSessionPayloadToKeep(guestPayload map[string]interface{}) map[string]interface{} {
+	result := map[string]interface{}{}
+	for k, v := range guestPayload {
+		if k == "iss" || k == "aud" || strings.HasPrefix(k, "st-") || supertokens.DoesSliceContainString(k, protectedProps) {
+			continue
+		}
+		result[k] = v
+	}
+	return result
+}
+
+// upgradeGuestSession is called when a session is created for a request that
+// has a guest session, e.g. on sign up. The guest's access token payload and
+// session data are copied into the new session (the values passed for the
+// new session win), OnUpgrade is called, and the guest session is revoked.
+func upgradeGuestSession(config sessmodels.TypeNormalisedInput, req *http.Request, recipeImpl sessmodels.RecipeInterface, userID string, accessTokenPayload map[string]interface{}, sessionDataInDatabase map[string]interface{}, userContext supertokens.UserContext) (map[string]interface{}, map[string]interface{}, error) {
+	if config.GuestSessions == nil || req == nil || isGuestSessionPayload(accessTokenPayload) {
+		return accessTokenPayload, sessionDataInDatabase, nil
+	}
+	False := false
+	var guestSession sessmodels.SessionContainer
+	for _, tokenTransferMethod := range getAvailableTokenTransferMethods(config) {
+		token, err := getToken(config, req, sessmodels.AccessToken, tokenTransferMethod)
+		if err != nil || token == nil {
+			continue
+		}
+		existingSession, err := (*recipeImpl.GetSession)(token, nil, &sessmodels.VerifySessionOptions{
+			AntiCsrfCheck:   &False,
+			SessionRequired: &False,
+		}, userContext)
+		if err == nil && existingSession != nil && isGuestSessionPayload(existingSession.GetAccessTokenPayloadWithContext(userContext)) {
+			guestSession = existingSession
+			break
+		}
+	}
+	if guestSession == nil {
+		return accessTokenPayload, sessionDataInDatabase, nil
+	}
+	supertokens.LogDebugMessage("createNewSession: Upgrading the guest session of the request")
+
+	newAccessTokenPayload := getGuestSessionPayloadToKeep(guestSession.GetAccessTokenPayloadWithContext(userContext))
+	for k, v := range accessTokenPayload {
+		newAccessTokenPayload[k] = v
+	}
+	guestSessionData, err := guestSession.GetSessionDataInDatabaseWithContext(userContext)
+	if err != nil {
+		return nil, nil, err
+	}
+	newSessionDataInDatabase := map[string]interface{}{}
+	for k, v := range guestSessionData {
+		newSessionDataInDatabase[k] = v
+	}
+	for k, v := range sessionDataInDatabase {
+		newSessionDataInDatabase[k] = v
+	}
+
+	if config.GuestSessions.OnUpgrade != nil {
+		err = config.GuestSessions.OnUpgrade(guestSession.GetUserIDWithContext(userContext), userID, userContext)
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+	_, err = (*recipeImpl.RevokeSession)(guestSession.GetHandleWithContext(userContext), userContext)
+	if err != nil {
+		return nil, nil, err
+	}
+	return newAccessTokenPayload, newSessionDataInDatabase, nil
+}
@@ -0,0 +1,165 @@
+/* Copyright (c) 2023, VRAI Labs and/or its affiliates. All rights reserved.
+ *
+ * This software is licensed under the Apache License, Version 2.0 (the
+ * "License") as published by the Apache Software Foundation.
+ *
+ * You may not use this file except in compliance with the License. You may
+ * obtain a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+ * License for the specific language governing permissions and limitations
+ * under the License.
+ */
+
+package session
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/supertokens/supertokens-golang/recipe/session/claims"
+	"github.com/supertokens/supertokens-golang/recipe/session/errors"
+	"github.com/supertokens/supertokens-golang/recipe/session/sessmodels"
+	"github.com/supertokens/supertokens-golang/supertokens"
+)
+
+func TestGuestSessionUpgrade(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	assert.NoError(t, err)
+	revoked := []interface{}{}
+	createdSessions := []map[string]interface{}{}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/apiversion", func(rw http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(rw).Encode(map[string]interface{}{"versions": []string{"3.0"}})
+	})
+	mux.HandleFunc("/", func(rw http.ResponseWriter, r *http.Request) {
+		if strings.HasSuffix(r.URL.Path, "/recipe/session/remove") {
+			var body map[string]interface{}
+			json.NewDecoder(r.Body).Decode(&body)
+			revoked = append(revoked, body["sessionHandles"].([]interface{})...)
+			json.NewEncoder(rw).Encode(map[string]interface{}{"status": "OK", "sessionHandlesRevoked": body["sessionHandles"]})
+			return
+		}
+		if strings.HasSuffix(r.URL.Path, "/recipe/session") && r.Method == http.MethodPost {
+			var body map[string]interface{}
+			json.NewDecoder(r.Body).Decode(&body)
+			createdSessions = append(createdSessions, body)
+			userID := body["userId"].(string)
+			claims := jwt.MapClaims{"sub": userID, "sessionHandle": "handle2", "iat": time.Now().Unix(), "exp": time.Now().Add(time.Hour).Unix(), "tId": "public"}
+			for k, v := range body["userDataInJWT"].(map[string]interface{}) {
+				claims[k] = v
+			}
+			token := jwt.NewWithClaims(jwt.SigningMethodRS256, claims)
+			token.Header["kid"] = "d-1"
+			signed, _ := token.SignedString(key)
+			json.NewEncoder(rw).Encode(map[string]interface{}{
+				"status":       "OK",
+				"session":      map[string]interface{}{"handle": "handle2", "userId": userID, "userDataInJWT": body["userDataInJWT"], "tenantId": "public"},
+				"accessToken":  map[string]interface{}{"token": signed, "expiry": time.Now().Add(time.Hour).UnixMilli(), "createdTime": time.Now().UnixMilli()},
+				"refreshToken": map[string]interface{}{"token": "refresh2", "expiry": time.Now().Add(time.Hour).UnixMilli(), "createdTime": time.Now().UnixMilli()},
+			})
+			return
+		}
+		rw.WriteHeader(http.StatusNotFound)
+	})
+	testServer := httptest.NewServer(mux)
+	defer testServer.Close()
+
+	upgrades := [][]string{}
+	False := false
+	resetAll()
+	defer resetAll()
+	err = supertokens.Init(supertokens.TypeInput{
+		Supertokens: &supertokens.ConnectionInfo{
+			ConnectionURI: testServer.URL,
+		},
+		AppInfo: supertokens.AppInfo{
+			AppName:       "SuperTokens",
+			WebsiteDomain: "supertokens.io",
+			APIDomain:     "api.supertokens.io",
+		},
+		RecipeList: []supertokens.Recipe{Init(&sessmodels.TypeInput{
+			RevokeExistingSessionOnCreate: &False,
+			GuestSessions: &sessmodels.GuestSessionsInput{
+				OnUpgrade: func(guestUserID string, userID string, userContext supertokens.UserContext) error {
+					upgrades = append(upgrades, []string{guestUserID, userID})
+					return nil
+				},
+			},
+		})},
+	})
+	assert.NoError(t, err)
+
+	guestSession, err := CreateGuestSession(httptest.NewRequest(http.MethodPost, "/cart", nil), httptest.NewRecorder(), "public", map[string]interface{}{"cart": "c1"}, nil)
+	assert.NoError(t, err)
+	assert.True(t, IsGuestSession(guestSession))
+	guestUserID := createdSessions[0]["userId"].(string)
+	assert.True(t, strings.HasPrefix(guestUserID, GuestUserIDPrefix))
+	assert.Equal(t, true, createdSessions[0]["userDataInJWT"].(map[string]interface{})[guestSessionKey])
+
+	instance, err := getRecipeInstanceOrThrowError()
+	assert.NoError(t, err)
+	getSession := func(accessToken *string, antiCsrfToken *string, options *sessmodels.VerifySessionOptions, userContext supertokens.UserContext) (sessmodels.SessionContainer, error) {
+		if *accessToken != "guest" {
+			return nil, errors.TryRefreshTokenError{Msg: "expired"}
+		}
+		return &sessmodels.TypeSessionContainer{
+			GetHandleWithContext: func(userContext supertokens.UserContext) string { return "handle1" },
+			GetUserIDWithContext: func(userContext supertokens.UserContext) string { return guestUserID },
+			GetAccessTokenPayloadWithContext: func(userContext supertokens.UserContext) map[string]interface{} {
+				return map[string]interface{}{"sub": guestUserID, "sessionHandle": "handle1", guestSessionKey: true, "cart": "c1", "theme": "light"}
+			},
+			GetSessionDataInDatabaseWithContext: func(userContext supertokens.UserContext) (map[string]interface{}, error) {
+				return map[string]interface{}{"cartItems": []interface{}{"book"}}, nil
+			},
+		}, nil
+	}
+	instance.RecipeImpl.GetSession = &getSession
+
+	req := httptest.NewRequest(http.MethodPost, "/auth/signup", nil)
+	req.AddCookie(&http.Cookie{Name: "sAccessToken", Value: "guest"})
+	newSession, err := CreateNewSession(req, httptest.NewRecorder(), "public", "user1", map[string]interface{}{"theme": "dark"}, map[string]interface{}{"plan": "pro"})
+	assert.NoError(t, err)
+	assert.False(t, IsGuestSession(newSession))
+
+	created := createdSessions[1]
+	assert.Equal(t, "user1", created["userId"])
+	payload := created["userDataInJWT"].(map[string]interface{})
+	assert.Equal(t, "c1", payload["cart"])
+	assert.Equal(t, "dark", payload["theme"])
+	assert.Nil(t, payload[guestSessionKey])
+	assert.Equal(t, map[string]interface{}{"cartItems": []interface{}{"book"}, "plan": "pro"}, created["userDataInDatabase"])
+	assert.Equal(t, [][]string{{guestUserID, "user1"}}, upgrades)
+	assert.Equal(t, []interface{}{"handle1"}, revoked)
+}
+
+func TestGuestSessionValidators(t *testing.T) {
+	userContext := &map[string]interface{}{}
+	guestPayload := map[string]interface{}{guestSessionKey: true}
+	assert.False(t, guestSessionValidator.Validate(guestPayload, userContext).IsValid)
+	assert.True(t, guestSessionValidator.Validate(map[string]interface{}{}, userContext).IsValid)
+
+	makeSession := func(payload map[string]interface{}) sessmodels.SessionContainer {
+		return &sessmodels.TypeSessionContainer{
+			GetAccessTokenPayloadWithContext: func(userContext supertokens.UserContext) map[string]interface{} { return payload },
+		}
+	}
+	options := WithGuestSessionsAllowed(nil)
+	globalClaimValidators := []claims.SessionClaimValidator{guestSessionValidator}
+	validators, err := options.OverrideGlobalClaimValidators(globalClaimValidators, makeSession(guestPayload), userContext)
+	assert.NoError(t, err)
+	assert.Empty(t, validators)
+	validators, err = options.OverrideGlobalClaimValidators(globalClaimValidators, makeSession(map[string]interface{}{}), userContext)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(validators))
+}
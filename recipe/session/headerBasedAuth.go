@@ -0,0 +1,81 @@
+/* Copyright (c) 2023, VRAI Labs and/or its affiliates. All rights reserved.
+ *
+ * This software is licensed under the Apache License, Version 2.0 (the
+ * "License") as published by the Apache Software Foundation.
+ *
+ * You may not use this file except in compliance with the License. You may
+ * obtain a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+ * License for the specific language governing permissions and limitations
+ * under the License.
+ */
+
+package session
+
+import (
+	"net/http"
+
+	"github.com/supertokens/supertokens-golang/recipe/session/sessmodels"
+)
+
+// HeaderTokens are the session tokens a response sent in headers. A field is
+// nil if the response did not change that token, and an empty string if the
+// token was removed (e.g. on sign out) and should be deleted by the client.
+type HeaderTokens struct {
+	AccessToken   *string
+	RefreshToken  *string
+	FrontToken    *string
+	AntiCsrfToken *string
+}
+
+func getAvailableTokenTransferMethods(config sessmodels.TypeNormalisedInput) []sessmodels.TokenTransferMethod {
+	if config.HeaderBasedAuthOnly || config.HybridTokenTransfer {
+		return []sessmodels.TokenTransferMethod{sessmodels.HeaderTransferMethod}
+	}
+	return AvailableTokenTransferMethods
+}
+
+// getStorageTransferMethod returns where a token of a session using
+// transferMethod is stored. With HybridTokenTransfer, sessions use header
+// based auth but their refresh tokens are kept in cookies.
+func getStorageTransferMethod(config sessmodels.TypeNormalisedInput, tokenType sessmodels.TokenType, transferMethod sessmodels.TokenTransferMethod) sessmodels.TokenTransferMethod {
+	if config.HybridTokenTransfer && tokenType == sessmodels.RefreshToken {
+		return sessmodels.CookieTransferMethod
+	}
+	return transferMethod
+}
+
+// GetTokensFromResponseHeaders returns the session tokens set in the headers
+// of a response, e.g. by Go clients of an API using header based sessions.
+func GetTokensFromResponseHeaders(header http.Header) HeaderTokens {
+	getValue := func(key string) *string {
+		values, ok := header[http.CanonicalHeaderKey(key)]
+		if !ok || len(values) == 0 {
+			return nil
+		}
+		value := values[0]
+		return &value
+	}
+	tokens := HeaderTokens{
+		AccessToken:   getValue(accessTokenHeaderKey),
+		RefreshToken:  getValue(refreshTokenHeaderKey),
+		FrontToken:    getValue(frontTokenHeaderKey),
+		AntiCsrfToken: getValue(antiCsrfHeaderKey),
+	}
+	if tokens.FrontToken != nil && *tokens.FrontToken == "remove" {
+		removed := ""
+		tokens.FrontToken = &removed
+	}
+	return tokens
+}
+
+// SetTokenInRequest makes a request use header based sessions and sends the
+// token in its Authorization header: the refresh token for requests to the
+// refresh API and the access token for all other requests.
+func SetTokenInRequest(req *http.Request, token string) {
+	req.Header.Set(authModeHeaderKey, string(sessmodels.HeaderTransferMethod))
+	req.Header.Set(authorizationHeaderKey, "Bearer "+token)
+}
@@ -0,0 +1,153 @@
+/* Copyright (c) 2023, VRAI Labs and/or its affiliates. All rights reserved.
+ *
+ * This software is licensed under the Apache License, Version 2.0 (the
+ * "License") as published by the Apache Software Foundation.
+ *
+ * You may not use this file except in compliance with the License. You may
+ * obtain a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+ * License for the specific language governing permissions and limitations
+ * under the License.
+ */
+
+package session
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/supertokens/supertokens-golang/recipe/session/sessmodels"
+	"github.com/supertokens/supertokens-golang/supertokens"
+)
+
+func TestHeaderBasedAuthOnly(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	assert.NoError(t, err)
+	signAccessToken := func(sessionHandle string) string {
+		claims := jwt.MapClaims{"sub": "user1", "sessionHandle": sessionHandle, "refreshTokenHash1": "hash", "iat": time.Now().Unix(), "exp": time.Now().Add(time.Hour).Unix(), "tId": "public"}
+		token := jwt.NewWithClaims(jwt.SigningMethodRS256, claims)
+		token.Header["kid"] = "d-1"
+		signed, _ := token.SignedString(key)
+		return signed
+	}
+	sessionResponse := func(refreshToken string) map[string]interface{} {
+		return map[string]interface{}{
+			"status":       "OK",
+			"session":      map[string]interface{}{"handle": "handle1", "userId": "user1", "userDataInJWT": map[string]interface{}{}, "tenantId": "public"},
+			"accessToken":  map[string]interface{}{"token": signAccessToken("handle1"), "expiry": time.Now().Add(time.Hour).UnixMilli(), "createdTime": time.Now().UnixMilli()},
+			"refreshToken": map[string]interface{}{"token": refreshToken, "expiry": time.Now().Add(time.Hour).UnixMilli(), "createdTime": time.Now().UnixMilli()},
+		}
+	}
+
+	var refreshTokenSentToCore interface{}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/apiversion", func(rw http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(rw).Encode(map[string]interface{}{"versions": []string{"3.0"}})
+	})
+	mux.HandleFunc("/.well-known/jwks.json", func(rw http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(rw).Encode(map[string]interface{}{"keys": []interface{}{map[string]interface{}{
+			"kty": "RSA",
+			"kid": "d-1",
+			"alg": "RS256",
+			"use": "sig",
+			"n":   base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+			"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+		}}})
+	})
+	mux.HandleFunc("/", func(rw http.ResponseWriter, r *http.Request) {
+		switch {
+		case strings.HasSuffix(r.URL.Path, "/recipe/session/refresh"):
+			var body map[string]interface{}
+			json.NewDecoder(r.Body).Decode(&body)
+			refreshTokenSentToCore = body["refreshToken"]
+			json.NewEncoder(rw).Encode(sessionResponse("refresh2"))
+		case strings.HasSuffix(r.URL.Path, "/recipe/session") && r.Method == http.MethodPost:
+			json.NewEncoder(rw).Encode(sessionResponse("refresh1"))
+		default:
+			rw.WriteHeader(http.StatusNotFound)
+		}
+	})
+	testServer := httptest.NewServer(mux)
+	defer testServer.Close()
+
+	resetAll()
+	defer resetAll()
+	True := true
+	err = supertokens.Init(supertokens.TypeInput{
+		Supertokens: &supertokens.ConnectionInfo{
+			ConnectionURI: testServer.URL,
+		},
+		AppInfo: supertokens.AppInfo{
+			AppName:       "SuperTokens",
+			WebsiteDomain: "supertokens.io",
+			APIDomain:     "api.supertokens.io",
+		},
+		RecipeList: []supertokens.Recipe{Init(&sessmodels.TypeInput{
+			HeaderBasedAuthOnly: &True,
+		})},
+	})
+	assert.NoError(t, err)
+
+	// the client asks for cookies and has a stale access token cookie, but only headers are used
+	req := httptest.NewRequest(http.MethodPost, "/auth/signin", nil)
+	req.Header.Set("st-auth-mode", "cookie")
+	req.AddCookie(&http.Cookie{Name: "sAccessToken", Value: "stale"})
+	res := httptest.NewRecorder()
+	_, err = CreateNewSession(req, res, "public", "user1", nil, nil)
+	assert.NoError(t, err)
+	assert.Empty(t, res.Header().Values("Set-Cookie"))
+
+	tokens := GetTokensFromResponseHeaders(res.Header())
+	assert.NotNil(t, tokens.AccessToken)
+	assert.Equal(t, "refresh1", *tokens.RefreshToken)
+	assert.NotNil(t, tokens.FrontToken)
+	assert.Nil(t, tokens.AntiCsrfToken)
+
+	req = httptest.NewRequest(http.MethodGet, "/orders", nil)
+	SetTokenInRequest(req, *tokens.AccessToken)
+	sessionContainer, err := GetSession(req, httptest.NewRecorder(), nil)
+	assert.NoError(t, err)
+	assert.Equal(t, "user1", sessionContainer.GetUserID())
+
+	req = httptest.NewRequest(http.MethodPost, "/auth/session/refresh", nil)
+	SetTokenInRequest(req, *tokens.RefreshToken)
+	res = httptest.NewRecorder()
+	_, err = RefreshSession(req, res)
+	assert.NoError(t, err)
+	assert.Equal(t, "refresh1", refreshTokenSentToCore)
+	assert.Empty(t, res.Header().Values("Set-Cookie"))
+	assert.Equal(t, "refresh2", *GetTokensFromResponseHeaders(res.Header()).RefreshToken)
+
+	instance, err := getRecipeInstanceOrThrowError()
+	assert.NoError(t, err)
+	res = httptest.NewRecorder()
+	err = ClearSessionFromAllTokenTransferMethods(instance.Config, req, res, &map[string]interface{}{})
+	assert.NoError(t, err)
+	assert.Empty(t, res.Header().Values("Set-Cookie"))
+	tokens = GetTokensFromResponseHeaders(res.Header())
+	assert.Equal(t, "", *tokens.AccessToken)
+	assert.Equal(t, "", *tokens.RefreshToken)
+	assert.Equal(t, "", *tokens.FrontToken)
+}
+
+func TestHeaderBasedAuthOnlyCannotBeUsedWithGetTokenTransferMethod(t *testing.T) {
+	True := true
+	_, err := ValidateAndNormaliseUserInput(supertokens.NormalisedAppinfo{}, &sessmodels.TypeInput{
+		HeaderBasedAuthOnly:    &True,
+		GetTokenTransferMethod: defaultGetTokenTransferMethod,
+	})
+	assert.EqualError(t, err, "HeaderBasedAuthOnly cannot be used together with GetTokenTransferMethod")
+}
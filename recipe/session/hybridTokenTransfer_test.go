@@ -0,0 +1,157 @@
+/* Copyright (c) 2023, VRAI Labs and/or its affiliates. All rights reserved.
+ *
+ * This software is licensed under the Apache License, Version 2.0 (the
+ * "License") as published by the Apache Software Foundation.
+ *
+ * You may not use this file except in compliance with the License. You may
+ * obtain a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+ * License for the specific language governing permissions and limitations
+ * under the License.
+ */
+
+package session
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	sessionErrors "github.com/supertokens/supertokens-golang/recipe/session/errors"
+	"github.com/supertokens/supertokens-golang/recipe/session/sessmodels"
+	"github.com/supertokens/supertokens-golang/supertokens"
+)
+
+func TestHybridTokenTransfer(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	assert.NoError(t, err)
+	sessionResponse := func(refreshToken string) map[string]interface{} {
+		claims := jwt.MapClaims{"sub": "user1", "sessionHandle": "handle1", "refreshTokenHash1": "hash", "iat": time.Now().Unix(), "exp": time.Now().Add(time.Hour).Unix(), "tId": "public"}
+		token := jwt.NewWithClaims(jwt.SigningMethodRS256, claims)
+		token.Header["kid"] = "d-1"
+		accessToken, _ := token.SignedString(key)
+		return map[string]interface{}{
+			"status":       "OK",
+			"session":      map[string]interface{}{"handle": "handle1", "userId": "user1", "userDataInJWT": map[string]interface{}{}, "tenantId": "public"},
+			"accessToken":  map[string]interface{}{"token": accessToken, "expiry": time.Now().Add(time.Hour).UnixMilli(), "createdTime": time.Now().UnixMilli()},
+			"refreshToken": map[string]interface{}{"token": refreshToken, "expiry": time.Now().Add(time.Hour).UnixMilli(), "createdTime": time.Now().UnixMilli()},
+		}
+	}
+
+	var refreshTokenSentToCore interface{}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/apiversion", func(rw http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(rw).Encode(map[string]interface{}{"versions": []string{"3.0"}})
+	})
+	mux.HandleFunc("/.well-known/jwks.json", func(rw http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(rw).Encode(map[string]interface{}{"keys": []interface{}{map[string]interface{}{
+			"kty": "RSA",
+			"kid": "d-1",
+			"alg": "RS256",
+			"use": "sig",
+			"n":   base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+			"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+		}}})
+	})
+	mux.HandleFunc("/", func(rw http.ResponseWriter, r *http.Request) {
+		switch {
+		case strings.HasSuffix(r.URL.Path, "/recipe/session/refresh"):
+			var body map[string]interface{}
+			json.NewDecoder(r.Body).Decode(&body)
+			refreshTokenSentToCore = body["refreshToken"]
+			json.NewEncoder(rw).Encode(sessionResponse("refresh2"))
+		case strings.HasSuffix(r.URL.Path, "/recipe/session") && r.Method == http.MethodPost:
+			json.NewEncoder(rw).Encode(sessionResponse("refresh1"))
+		default:
+			rw.WriteHeader(http.StatusNotFound)
+		}
+	})
+	testServer := httptest.NewServer(mux)
+	defer testServer.Close()
+
+	resetAll()
+	defer resetAll()
+	True := true
+	antiCsrf := AntiCSRF_VIA_CUSTOM_HEADER
+	err = supertokens.Init(supertokens.TypeInput{
+		Supertokens: &supertokens.ConnectionInfo{
+			ConnectionURI: testServer.URL,
+		},
+		AppInfo: supertokens.AppInfo{
+			AppName:       "SuperTokens",
+			WebsiteDomain: "supertokens.io",
+			APIDomain:     "api.supertokens.io",
+		},
+		RecipeList: []supertokens.Recipe{Init(&sessmodels.TypeInput{
+			HybridTokenTransfer: &True,
+			AntiCsrf:            &antiCsrf,
+		})},
+	})
+	assert.NoError(t, err)
+
+	req := httptest.NewRequest(http.MethodPost, "/auth/signin", nil)
+	res := httptest.NewRecorder()
+	_, err = CreateNewSession(req, res, "public", "user1", nil, nil)
+	assert.NoError(t, err)
+
+	tokens := GetTokensFromResponseHeaders(res.Header())
+	assert.NotNil(t, tokens.AccessToken)
+	assert.NotNil(t, tokens.FrontToken)
+	assert.Nil(t, tokens.RefreshToken)
+	cookies := res.Result().Cookies()
+	assert.Len(t, cookies, 1)
+	assert.Equal(t, "sRefreshToken", cookies[0].Name)
+	assert.Equal(t, "refresh1", cookies[0].Value)
+	assert.True(t, cookies[0].HttpOnly)
+
+	req = httptest.NewRequest(http.MethodGet, "/orders", nil)
+	SetTokenInRequest(req, *tokens.AccessToken)
+	sessionContainer, err := GetSession(req, httptest.NewRecorder(), nil)
+	assert.NoError(t, err)
+	assert.Equal(t, "user1", sessionContainer.GetUserID())
+
+	// the refresh token cookie needs the anti-csrf header
+	req = httptest.NewRequest(http.MethodPost, "/auth/session/refresh", nil)
+	req.AddCookie(&http.Cookie{Name: "sRefreshToken", Value: "refresh1"})
+	_, err = RefreshSession(req, httptest.NewRecorder())
+	assert.IsType(t, sessionErrors.UnauthorizedError{}, err)
+	assert.Nil(t, refreshTokenSentToCore)
+
+	req.Header.Set("rid", "session")
+	res = httptest.NewRecorder()
+	_, err = RefreshSession(req, res)
+	assert.NoError(t, err)
+	assert.Equal(t, "refresh1", refreshTokenSentToCore)
+	assert.NotNil(t, GetTokensFromResponseHeaders(res.Header()).AccessToken)
+	cookies = res.Result().Cookies()
+	assert.Len(t, cookies, 1)
+	assert.Equal(t, "refresh2", cookies[0].Value)
+}
+
+func TestHybridTokenTransferConfigValidation(t *testing.T) {
+	True := true
+	_, err := ValidateAndNormaliseUserInput(supertokens.NormalisedAppinfo{}, &sessmodels.TypeInput{
+		HybridTokenTransfer: &True,
+		HeaderBasedAuthOnly: &True,
+	})
+	assert.EqualError(t, err, "HybridTokenTransfer cannot be used together with HeaderBasedAuthOnly")
+
+	antiCsrf := AntiCSRF_VIA_TOKEN
+	_, err = ValidateAndNormaliseUserInput(supertokens.NormalisedAppinfo{}, &sessmodels.TypeInput{
+		HybridTokenTransfer: &True,
+		AntiCsrf:            &antiCsrf,
+	})
+	assert.EqualError(t, err, "HybridTokenTransfer cannot be used together with AntiCsrf VIA_TOKEN. Please use VIA_CUSTOM_HEADER instead")
+}
@@ -0,0 +1,164 @@
+/* Copyright (c) 2023, VRAI Labs and/or its affiliates. All rights reserved.
+ *
+ * This software is licensed under the Apache License, Version 2.0 (the
+ * "License") as published by the Apache Software Foundation.
+ *
+ * You may not use this file except in compliance with the License. You may
+ * obtain a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+ * License for the specific language governing permissions and limitations
+ * under the License.
+ */
+
+package session
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/supertokens/supertokens-golang/recipe/session/sessmodels"
+	"github.com/supertokens/supertokens-golang/supertokens"
+)
+
+type jwksTestServer struct {
+	mutex      sync.Mutex
+	key        *rsa.PrivateKey
+	fetchCount int32
+	server     *httptest.Server
+}
+
+func newJWKSTestServer(t *testing.T) *jwksTestServer {
+	jwksServer := &jwksTestServer{}
+	jwksServer.rotateKey(t)
+	mux := http.NewServeMux()
+	mux.HandleFunc("/apiversion", func(rw http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(rw).Encode(map[string]interface{}{"versions": []string{"3.0"}})
+	})
+	mux.HandleFunc("/.well-known/jwks.json", func(rw http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&jwksServer.fetchCount, 1)
+		jwksServer.mutex.Lock()
+		defer jwksServer.mutex.Unlock()
+		json.NewEncoder(rw).Encode(map[string]interface{}{"keys": []interface{}{map[string]interface{}{
+			"kty": "RSA",
+			"kid": "d-1",
+			"alg": "RS256",
+			"use": "sig",
+			"n":   base64.RawURLEncoding.EncodeToString(jwksServer.key.N.Bytes()),
+			"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(jwksServer.key.E)).Bytes()),
+		}}})
+	})
+	jwksServer.server = httptest.NewServer(mux)
+	return jwksServer
+}
+
+// rotateKey replaces the signing key, keeping the same kid, so that the
+// keys cached by the SDK cannot verify new tokens
+func (s *jwksTestServer) rotateKey(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	assert.NoError(t, err)
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.key = key
+}
+
+func (s *jwksTestServer) signAccessToken(key *rsa.PrivateKey) string {
+	if key == nil {
+		s.mutex.Lock()
+		key = s.key
+		s.mutex.Unlock()
+	}
+	claims := jwt.MapClaims{"sub": "user1", "sessionHandle": "handle1", "refreshTokenHash1": "hash", "iat": time.Now().Unix(), "exp": time.Now().Add(time.Hour).Unix(), "tId": "public"}
+	token := jwt.NewWithClaims(jwt.SigningMethodRS256, claims)
+	token.Header["kid"] = "d-1"
+	signed, _ := token.SignedString(key)
+	return signed
+}
+
+func initWithJWKSTestServer(t *testing.T, jwksServer *jwksTestServer) {
+	resetAll()
+	err := supertokens.Init(supertokens.TypeInput{
+		Supertokens: &supertokens.ConnectionInfo{
+			ConnectionURI: jwksServer.server.URL,
+		},
+		AppInfo: supertokens.AppInfo{
+			AppName:       "SuperTokens",
+			WebsiteDomain: "supertokens.io",
+			APIDomain:     "api.supertokens.io",
+		},
+		RecipeList: []supertokens.Recipe{Init(nil)},
+	})
+	assert.NoError(t, err)
+}
+
+func TestAccessTokensSignedWithARotatedKeyAreVerifiedWithRefetchedKeys(t *testing.T) {
+	jwksServer := newJWKSTestServer(t)
+	defer jwksServer.server.Close()
+	initWithJWKSTestServer(t, jwksServer)
+	defer resetAll()
+	False := false
+	options := &sessmodels.VerifySessionOptions{AntiCsrfCheck: &False}
+
+	sessionContainer, err := GetSessionWithoutRequestResponse(jwksServer.signAccessToken(nil), nil, options)
+	assert.NoError(t, err)
+	assert.Equal(t, "handle1", sessionContainer.GetHandle())
+	assert.Equal(t, int32(1), atomic.LoadInt32(&jwksServer.fetchCount))
+
+	jwksServer.rotateKey(t)
+	sessionContainer, err = GetSessionWithoutRequestResponse(jwksServer.signAccessToken(nil), nil, options)
+	assert.NoError(t, err)
+	assert.Equal(t, "handle1", sessionContainer.GetHandle())
+	assert.Equal(t, int32(2), atomic.LoadInt32(&jwksServer.fetchCount))
+
+	// tokens signed with unknown keys do not make every request fetch the keys
+	otherKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	assert.NoError(t, err)
+	_, err = GetSessionWithoutRequestResponse(jwksServer.signAccessToken(otherKey), nil, options)
+	assert.Error(t, err)
+	assert.Equal(t, int32(2), atomic.LoadInt32(&jwksServer.fetchCount))
+}
+
+func TestJWKSAreRefreshedBeforeTheyExpire(t *testing.T) {
+	originalCacheAge := JWKCacheMaxAgeInMs
+	originalProactiveRefreshWindow := JWKProactiveRefreshWindowInMs
+	JWKCacheMaxAgeInMs = 2000
+	JWKProactiveRefreshWindowInMs = 1500
+	defer func() {
+		JWKCacheMaxAgeInMs = originalCacheAge
+		JWKProactiveRefreshWindowInMs = originalProactiveRefreshWindow
+	}()
+
+	jwksServer := newJWKSTestServer(t)
+	defer jwksServer.server.Close()
+	initWithJWKSTestServer(t, jwksServer)
+	defer resetAll()
+	False := false
+	options := &sessmodels.VerifySessionOptions{AntiCsrfCheck: &False}
+
+	_, err := GetSessionWithoutRequestResponse(jwksServer.signAccessToken(nil), nil, options)
+	assert.NoError(t, err)
+	assert.Equal(t, int32(1), atomic.LoadInt32(&jwksServer.fetchCount))
+
+	time.Sleep(600 * time.Millisecond)
+	_, err = GetSessionWithoutRequestResponse(jwksServer.signAccessToken(nil), nil, options)
+	assert.NoError(t, err)
+	assert.Eventually(t, func() bool {
+		return atomic.LoadInt32(&jwksServer.fetchCount) == 2
+	}, time.Second, 10*time.Millisecond)
+	assert.Eventually(t, func() bool {
+		return atomic.LoadInt32(&jwksRefreshingInBackground) == 0
+	}, time.Second, 10*time.Millisecond)
+}
@@ -17,15 +17,14 @@ package session
 
 import (
 	"encoding/base64"
-	"encoding/json"
 	"errors"
 	"fmt"
 	"reflect"
 	"strconv"
 	"strings"
 
-	"github.com/golang-jwt/jwt/v5"
 	"github.com/supertokens/supertokens-golang/recipe/session/sessmodels"
+	"github.com/supertokens/supertokens-golang/supertokens"
 )
 
 var HEADERS = []string{
@@ -47,7 +46,7 @@ func ParseJWTWithoutSignatureVerification(token string) (sessmodels.ParsedJWTInf
 	latestAccessTokenVersion := 3
 	var kid *string
 	if len(splittedInput) != 3 {
-		errors.New("Invalid JWT")
+		return sessmodels.ParsedJWTInfo{}, errors.New("Invalid JWT")
 	}
 
 	// V1&V2 is functionally identical, plus all legacy tokens should be V2 now.
@@ -58,13 +57,14 @@ func ParseJWTWithoutSignatureVerification(token string) (sessmodels.ParsedJWTInf
 
 	// If err != nil, it is a V3 token (or above)
 	if err != nil {
-		unverifiedToken, _, rawParseError := new(jwt.Parser).ParseUnverified(token, jwt.MapClaims{})
-		if rawParseError != nil {
-			return sessmodels.ParsedJWTInfo{}, rawParseError
+		// the header and payload are decoded with the configured JSON codec,
+		// since this runs for every request with a session
+		parsedHeader := map[string]interface{}{}
+		err = decodeJWTSegment(splittedInput[0], &parsedHeader)
+		if err != nil {
+			return sessmodels.ParsedJWTInfo{}, err
 		}
 
-		parsedHeader := unverifiedToken.Header
-
 		versionInHeader, ok := parsedHeader["version"]
 
 		if !ok {
@@ -96,11 +96,8 @@ func ParseJWTWithoutSignatureVerification(token string) (sessmodels.ParsedJWTInf
 
 		version = versionNumber
 
-		claims, ok := unverifiedToken.Claims.(jwt.MapClaims)
-
-		if ok {
-			payload = claims
-		} else {
+		err = decodeJWTSegment(splittedInput[1], &payload)
+		if err != nil {
 			return sessmodels.ParsedJWTInfo{}, errors.New("Invalid JWT")
 		}
 	} else {
@@ -111,7 +108,7 @@ func ParseJWTWithoutSignatureVerification(token string) (sessmodels.ParsedJWTInf
 		}
 
 		decodedJson := map[string]interface{}{}
-		err = json.Unmarshal(bytes, &decodedJson)
+		err = supertokens.JSONUnmarshal(bytes, &decodedJson)
 
 		if err != nil {
 			return sessmodels.ParsedJWTInfo{}, err
@@ -131,3 +128,11 @@ func ParseJWTWithoutSignatureVerification(token string) (sessmodels.ParsedJWTInf
 		KID:            kid,
 	}, nil
 }
+
+func decodeJWTSegment(segment string, out interface{}) error {
+	decoded, err := base64.RawURLEncoding.DecodeString(strings.TrimRight(segment, "="))
+	if err != nil {
+		return err
+	}
+	return supertokens.JSONUnmarshal(decoded, out)
+}
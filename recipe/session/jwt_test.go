@@ -0,0 +1,47 @@
+/* Copyright (c) 2023, VRAI Labs and/or its affiliates. All rights reserved.
+ *
+ * This software is licensed under the Apache License, Version 2.0 (the
+ * "License") as published by the Apache Software Foundation.
+ *
+ * You may not use this file except in compliance with the License. You may
+ * obtain a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+ * License for the specific language governing permissions and limitations
+ * under the License.
+ */
+
+package session
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"testing"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseJWTWithoutSignatureVerification(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	assert.NoError(t, err)
+	token := jwt.NewWithClaims(jwt.SigningMethodRS256, jwt.MapClaims{"sub": "user1", "exp": 1000})
+	token.Header["kid"] = "d-1"
+	token.Header["version"] = "4"
+	signed, err := token.SignedString(key)
+	assert.NoError(t, err)
+
+	parsed, err := ParseJWTWithoutSignatureVerification(signed)
+	assert.NoError(t, err)
+	assert.Equal(t, 4, parsed.Version)
+	assert.Equal(t, "d-1", *parsed.KID)
+	assert.Equal(t, "user1", parsed.Payload["sub"])
+	assert.Equal(t, float64(1000), parsed.Payload["exp"])
+
+	_, err = ParseJWTWithoutSignatureVerification("a.b")
+	assert.Error(t, err)
+	_, err = ParseJWTWithoutSignatureVerification("e30.!.sig")
+	assert.Error(t, err)
+}
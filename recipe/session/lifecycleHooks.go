@@ -0,0 +1,100 @@
+/* Copyright (c) 2023, VRAI Labs and/or its affiliates. All rights reserved.
+ *
+ * This software is licensed under the Apache License, Version 2.0 (the
+ * "License") as published by the Apache Software Foundation.
+ *
+ * You may not use this file except in compliance with the License. You may
+ * obtain a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+ * License for the specific language governing permissions and limitations
+ * under the License.
+ */
+
+package session
+
+import (
+	"net/http"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/supertokens/supertokens-golang/recipe/session/sessmodels"
+	"github.com/supertokens/supertokens-golang/supertokens"
+)
+
+func callSessionLifecycleHook(hook sessmodels.SessionLifecycleHook, sessionHandle string, userID string, userContext supertokens.UserContext) {
+	if hook == nil {
+		return
+	}
+	hook(sessionHandle, userID, supertokens.GetRequestFromUserContext(userContext), userContext)
+}
+
+// getUserIDsBeforeRevoking returns the user ID of each session that exists,
+// so that OnSessionRevoked can be called with it once the sessions are
+// revoked. It returns nil if OnSessionRevoked is not set.
+func getUserIDsBeforeRevoking(config sessmodels.TypeNormalisedInput, querier supertokens.Querier, sessionHandles []string, userContext supertokens.UserContext) map[string]string {
+	if config.OnSessionRevoked == nil {
+		return nil
+	}
+	result := map[string]string{}
+	for _, sessionHandle := range sessionHandles {
+		sessionInfo, err := getSessionInformationHelper(querier, sessionHandle, userContext)
+		if err != nil {
+			supertokens.LogDebugMessage("getUserIDsBeforeRevoking: Failed to fetch the session: " + err.Error())
+			continue
+		}
+		if sessionInfo != nil {
+			result[sessionInfo.SessionHandle] = sessionInfo.UserId
+		}
+	}
+	return result
+}
+
+func callOnSessionRevoked(config sessmodels.TypeNormalisedInput, revokedSessionHandles []string, userIDs map[string]string, userContext supertokens.UserContext) {
+	for _, sessionHandle := range revokedSessionHandles {
+		if userID, ok := userIDs[sessionHandle]; ok {
+			callSessionLifecycleHook(config.OnSessionRevoked, sessionHandle, userID, userContext)
+		}
+	}
+}
+
+// callOnSessionExpired identifies the session of a failed refresh from the
+// access token in the request. The signature of the access token is checked,
+// but not its expiry, since it is expected to have expired.
+func callOnSessionExpired(config sessmodels.TypeNormalisedInput, req *http.Request, tokenTransferMethod sessmodels.TokenTransferMethod, userContext supertokens.UserContext) {
+	if config.OnSessionExpired == nil {
+		return
+	}
+	accessToken, err := getToken(config, req, sessmodels.AccessToken, tokenTransferMethod)
+	if err != nil || accessToken == nil {
+		return
+	}
+	jwtInfo, err := ParseJWTWithoutSignatureVerification(*accessToken)
+	if err != nil || jwtInfo.Version < 3 {
+		return
+	}
+	combinedJwks, err := GetCombinedJWKS()
+	if err != nil {
+		supertokens.LogDebugMessage("callOnSessionExpired: Failed to fetch the JWKS: " + err.Error())
+		return
+	}
+	parserOptions := append(supertokens.GetJWTParserOptions(), jwt.WithoutClaimsValidation())
+	parsedToken, err := jwt.Parse(jwtInfo.RawTokenString, combinedJwks.Keyfunc, parserOptions...)
+	if err != nil {
+		return
+	}
+	claims, ok := parsedToken.Claims.(jwt.MapClaims)
+	if !ok {
+		return
+	}
+	sessionHandle, ok := claims["sessionHandle"].(string)
+	if !ok {
+		return
+	}
+	userID, ok := claims["sub"].(string)
+	if !ok {
+		return
+	}
+	config.OnSessionExpired(sessionHandle, userID, req, userContext)
+}
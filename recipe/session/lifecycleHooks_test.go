@@ -0,0 +1,153 @@
+/* Copyright (c) 2023, VRAI Labs and/or its affiliates. All rights reserved.
+ *
+ * This software is licensed under the Apache License, Version 2.0 (the
+ * "License") as published by the Apache Software Foundation.
+ *
+ * You may not use this file except in compliance with the License. You may
+ * obtain a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+ * License for the specific language governing permissions and limitations
+ * under the License.
+ */
+
+package session
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/supertokens/supertokens-golang/recipe/session/sessmodels"
+	"github.com/supertokens/supertokens-golang/supertokens"
+)
+
+func TestSessionLifecycleHooks(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	assert.NoError(t, err)
+	signAccessToken := func(sessionHandle string, exp time.Time) string {
+		claims := jwt.MapClaims{"sub": "user1", "sessionHandle": sessionHandle, "refreshTokenHash1": "hash", "iat": time.Now().Unix(), "exp": exp.Unix(), "tId": "public"}
+		token := jwt.NewWithClaims(jwt.SigningMethodRS256, claims)
+		token.Header["kid"] = "d-1"
+		signed, _ := token.SignedString(key)
+		return signed
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/apiversion", func(rw http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(rw).Encode(map[string]interface{}{"versions": []string{"3.0"}})
+	})
+	mux.HandleFunc("/.well-known/jwks.json", func(rw http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(rw).Encode(map[string]interface{}{"keys": []interface{}{map[string]interface{}{
+			"kty": "RSA",
+			"kid": "d-1",
+			"alg": "RS256",
+			"use": "sig",
+			"n":   base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+			"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+		}}})
+	})
+	mux.HandleFunc("/", func(rw http.ResponseWriter, r *http.Request) {
+		switch {
+		case strings.HasSuffix(r.URL.Path, "/recipe/session/refresh"):
+			json.NewEncoder(rw).Encode(map[string]interface{}{"status": "UNAUTHORISED", "message": "refresh token expired"})
+		case strings.HasSuffix(r.URL.Path, "/recipe/session/remove"):
+			var body map[string]interface{}
+			json.NewDecoder(r.Body).Decode(&body)
+			if body["userId"] != nil {
+				json.NewEncoder(rw).Encode(map[string]interface{}{"status": "OK", "sessionHandlesRevoked": []string{"handle2", "handle3"}})
+				return
+			}
+			json.NewEncoder(rw).Encode(map[string]interface{}{"status": "OK", "sessionHandlesRevoked": body["sessionHandles"]})
+		case strings.HasSuffix(r.URL.Path, "/recipe/session") && r.Method == http.MethodGet:
+			json.NewEncoder(rw).Encode(map[string]interface{}{
+				"status":             "OK",
+				"sessionHandle":      r.URL.Query().Get("sessionHandle"),
+				"userId":             "user1",
+				"tenantId":           "public",
+				"userDataInDatabase": map[string]interface{}{},
+				"userDataInJWT":      map[string]interface{}{},
+				"expiry":             2000,
+				"timeCreated":        1000,
+			})
+		case strings.HasSuffix(r.URL.Path, "/recipe/session") && r.Method == http.MethodPost:
+			json.NewEncoder(rw).Encode(map[string]interface{}{
+				"status":       "OK",
+				"session":      map[string]interface{}{"handle": "handle1", "userId": "user1", "userDataInJWT": map[string]interface{}{}, "tenantId": "public"},
+				"accessToken":  map[string]interface{}{"token": signAccessToken("handle1", time.Now().Add(time.Hour)), "expiry": time.Now().Add(time.Hour).UnixMilli(), "createdTime": time.Now().UnixMilli()},
+				"refreshToken": map[string]interface{}{"token": "refresh1", "expiry": time.Now().Add(time.Hour).UnixMilli(), "createdTime": time.Now().UnixMilli()},
+			})
+		default:
+			rw.WriteHeader(http.StatusNotFound)
+		}
+	})
+	testServer := httptest.NewServer(mux)
+	defer testServer.Close()
+
+	events := []string{}
+	hook := func(event string) sessmodels.SessionLifecycleHook {
+		return func(sessionHandle string, userID string, req *http.Request, userContext supertokens.UserContext) {
+			hasRequest := "without request"
+			if req != nil {
+				hasRequest = "with request"
+			}
+			events = append(events, event+" "+sessionHandle+" "+userID+" "+hasRequest)
+		}
+	}
+
+	resetAll()
+	defer resetAll()
+	err = supertokens.Init(supertokens.TypeInput{
+		Supertokens: &supertokens.ConnectionInfo{
+			ConnectionURI: testServer.URL,
+		},
+		AppInfo: supertokens.AppInfo{
+			AppName:       "SuperTokens",
+			WebsiteDomain: "supertokens.io",
+			APIDomain:     "api.supertokens.io",
+		},
+		RecipeList: []supertokens.Recipe{Init(&sessmodels.TypeInput{
+			OnSessionCreated:   hook("created"),
+			OnSessionRefreshed: hook("refreshed"),
+			OnSessionRevoked:   hook("revoked"),
+			OnSessionExpired:   hook("expired"),
+		})},
+	})
+	assert.NoError(t, err)
+
+	req := httptest.NewRequest(http.MethodPost, "/auth/signin", nil)
+	_, err = CreateNewSession(req, httptest.NewRecorder(), "public", "user1", nil, nil)
+	assert.NoError(t, err)
+
+	_, err = RevokeSession("handle1")
+	assert.NoError(t, err)
+
+	_, err = RevokeAllSessionsForUser("user1", nil)
+	assert.NoError(t, err)
+
+	req = httptest.NewRequest(http.MethodPost, "/auth/session/refresh", nil)
+	req.Header.Set("rid", "session")
+	req.AddCookie(&http.Cookie{Name: "sRefreshToken", Value: "refresh4"})
+	req.AddCookie(&http.Cookie{Name: "sAccessToken", Value: signAccessToken("handle4", time.Now().Add(-time.Hour))})
+	_, err = RefreshSession(req, httptest.NewRecorder())
+	assert.Error(t, err)
+
+	assert.Equal(t, []string{
+		"created handle1 user1 with request",
+		"revoked handle1 user1 without request",
+		"revoked handle2 user1 without request",
+		"revoked handle3 user1 without request",
+		"expired handle4 user1 with request",
+	}, events)
+}
@@ -0,0 +1,136 @@
+/* Copyright (c) 2023, VRAI Labs and/or its affiliates. All rights reserved.
+ *
+ * This software is licensed under the Apache License, Version 2.0 (the
+ * "License") as published by the Apache Software Foundation.
+ *
+ * You may not use this file except in compliance with the License. You may
+ * obtain a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+ * License for the specific language governing permissions and limitations
+ * under the License.
+ */
+
+package session
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/supertokens/supertokens-golang/recipe/session/sessmodels"
+	"github.com/supertokens/supertokens-golang/supertokens"
+)
+
+const (
+	// LocalSessionStatus_OK is returned for requests with a valid access token
+	LocalSessionStatus_OK = "OK"
+	// LocalSessionStatus_NEEDS_REFRESH is returned for requests with an
+	// access token that is expired or cannot be verified. The frontend
+	// should refresh the session before the page is rendered again.
+	LocalSessionStatus_NEEDS_REFRESH = "NEEDS_REFRESH"
+	// LocalSessionStatus_NO_SESSION is returned for requests without an
+	// access token
+	LocalSessionStatus_NO_SESSION = "NO_SESSION"
+	// LocalSessionStatus_UNKNOWN is returned when the signing keys have not
+	// been fetched from the core yet. They are then fetched in the
+	// background, and the page should let the frontend check the session.
+	LocalSessionStatus_UNKNOWN = "UNKNOWN"
+)
+
+// LocalSessionState is the session of a request as read by
+// GetLocalSessionState. The other fields are only set if Status is OK.
+type LocalSessionState struct {
+	Status             string
+	UserID             string
+	TenantId           string
+	SessionHandle      string
+	AccessTokenPayload map[string]interface{}
+}
+
+// GetLocalSessionState reads and verifies the access token of a request
+// using only the signing keys already cached by this process, so that it
+// never waits for the core. It is meant for latency critical server side
+// rendering. Unlike GetSession, it does not check anti-csrf tokens, claims
+// or whether the session was revoked, so it should not be used to authorise
+// changes.
+func GetLocalSessionState(req *http.Request) (LocalSessionState, error) {
+	instance, err := getRecipeInstanceOrThrowError()
+	if err != nil {
+		return LocalSessionState{}, err
+	}
+	return getLocalSessionState(instance.Config, req)
+}
+
+func getLocalSessionState(config sessmodels.TypeNormalisedInput, req *http.Request) (LocalSessionState, error) {
+	var accessToken *string
+	for _, tokenTransferMethod := range getAvailableTokenTransferMethods(config) {
+		token, err := getToken(config, req, sessmodels.AccessToken, tokenTransferMethod)
+		if err != nil {
+			return LocalSessionState{}, err
+		}
+		if token != nil && *token != "" {
+			accessToken = token
+			break
+		}
+	}
+	if accessToken == nil {
+		return LocalSessionState{Status: LocalSessionStatus_NO_SESSION}, nil
+	}
+
+	parsedAccessToken, err := ParseJWTWithoutSignatureVerification(*accessToken)
+	if err != nil || parsedAccessToken.Version < 3 {
+		supertokens.LogDebugMessage("getLocalSessionState: Returning NEEDS_REFRESH because the access token could not be parsed")
+		return LocalSessionState{Status: LocalSessionStatus_NEEDS_REFRESH}, nil
+	}
+
+	mutex.RLock()
+	cachedJWKS := jwksCache
+	mutex.RUnlock()
+	if cachedJWKS == nil {
+		supertokens.LogDebugMessage("getLocalSessionState: Returning UNKNOWN because the JWKS are not cached")
+		refreshJWKSInBackground()
+		return LocalSessionState{Status: LocalSessionStatus_UNKNOWN}, nil
+	}
+
+	// the keys are looked up in the cache only, since the Keyfunc of the
+	// cached JWKS fetches them again for unknown key IDs
+	keys := cachedJWKS.JWKS.ReadOnlyKeys()
+	parsedToken, err := jwt.Parse(*accessToken, func(token *jwt.Token) (interface{}, error) {
+		kid, _ := token.Header["kid"].(string)
+		key, ok := keys[kid]
+		if !ok {
+			return nil, errors.New("unknown key ID " + kid)
+		}
+		return key, nil
+	}, supertokens.GetJWTParserOptions()...)
+	if err != nil || !parsedToken.Valid {
+		supertokens.LogDebugMessage(fmt.Sprintf("getLocalSessionState: Returning NEEDS_REFRESH because the access token could not be verified - %v", err))
+		return LocalSessionState{Status: LocalSessionStatus_NEEDS_REFRESH}, nil
+	}
+
+	payload := parsedAccessToken.Payload
+	if ValidateAccessTokenStructure(payload, parsedAccessToken.Version) != nil {
+		return LocalSessionState{Status: LocalSessionStatus_NEEDS_REFRESH}, nil
+	}
+	usesDynamicKey := parsedAccessToken.KID != nil && strings.HasPrefix(*parsedAccessToken.KID, "d-")
+	if usesDynamicKey != config.UseDynamicAccessTokenSigningKey || checkAccessTokenLifetime(payload) != nil {
+		return LocalSessionState{Status: LocalSessionStatus_NEEDS_REFRESH}, nil
+	}
+
+	tenantId, ok := payload["tId"].(string)
+	if !ok {
+		tenantId = supertokens.DefaultTenantId
+	}
+	return LocalSessionState{
+		Status:             LocalSessionStatus_OK,
+		UserID:             payload["sub"].(string),
+		TenantId:           tenantId,
+		SessionHandle:      payload["sessionHandle"].(string),
+		AccessTokenPayload: payload,
+	}, nil
+}
@@ -0,0 +1,92 @@
+/* Copyright (c) 2023, VRAI Labs and/or its affiliates. All rights reserved.
+ *
+ * This software is licensed under the Apache License, Version 2.0 (the
+ * "License") as published by the Apache Software Foundation.
+ *
+ * You may not use this file except in compliance with the License. You may
+ * obtain a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+ * License for the specific language governing permissions and limitations
+ * under the License.
+ */
+
+package session
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/MicahParks/keyfunc/v2"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/supertokens/supertokens-golang/recipe/session/sessmodels"
+)
+
+func TestGetLocalSessionState(t *testing.T) {
+	resetAll()
+	defer resetAll()
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	assert.NoError(t, err)
+	otherKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	assert.NoError(t, err)
+	signAccessToken := func(signingKey *rsa.PrivateKey, kid string, exp time.Time) string {
+		claims := jwt.MapClaims{"sub": "user1", "sessionHandle": "handle1", "refreshTokenHash1": "hash", "iat": time.Now().Unix(), "exp": exp.Unix(), "tId": "public", "role": "admin"}
+		token := jwt.NewWithClaims(jwt.SigningMethodRS256, claims)
+		token.Header["kid"] = kid
+		signed, _ := token.SignedString(signingKey)
+		return signed
+	}
+	makeRequest := func(accessToken string) *http.Request {
+		req := httptest.NewRequest(http.MethodGet, "/dashboard", nil)
+		if accessToken != "" {
+			req.AddCookie(&http.Cookie{Name: "sAccessToken", Value: accessToken})
+		}
+		return req
+	}
+	config := sessmodels.TypeNormalisedInput{UseDynamicAccessTokenSigningKey: true}
+	validToken := signAccessToken(key, "d-1", time.Now().Add(time.Hour))
+
+	state, err := getLocalSessionState(config, makeRequest(""))
+	assert.NoError(t, err)
+	assert.Equal(t, LocalSessionStatus_NO_SESSION, state.Status)
+
+	// no keys are cached and there is no core to fetch them from
+	state, err = getLocalSessionState(config, makeRequest(validToken))
+	assert.NoError(t, err)
+	assert.Equal(t, LocalSessionStatus_UNKNOWN, state.Status)
+
+	jwksCache = &sessmodels.GetJWKSResult{
+		JWKS: keyfunc.NewGiven(map[string]keyfunc.GivenKey{
+			"d-1": keyfunc.NewGivenRSA(&key.PublicKey, keyfunc.GivenKeyOptions{Algorithm: "RS256"}),
+		}),
+		LastFetched: time.Now().UnixMilli(),
+	}
+
+	state, err = getLocalSessionState(config, makeRequest(validToken))
+	assert.NoError(t, err)
+	assert.Equal(t, LocalSessionStatus_OK, state.Status)
+	assert.Equal(t, "user1", state.UserID)
+	assert.Equal(t, "public", state.TenantId)
+	assert.Equal(t, "handle1", state.SessionHandle)
+	assert.Equal(t, "admin", state.AccessTokenPayload["role"])
+
+	for _, accessToken := range []string{
+		signAccessToken(key, "d-1", time.Now().Add(-time.Minute)),
+		signAccessToken(otherKey, "d-1", time.Now().Add(time.Hour)),
+		signAccessToken(otherKey, "d-2", time.Now().Add(time.Hour)),
+		"not-a-jwt",
+	} {
+		state, err = getLocalSessionState(config, makeRequest(accessToken))
+		assert.NoError(t, err)
+		assert.Equal(t, LocalSessionStatus_NEEDS_REFRESH, state.Status)
+		assert.Empty(t, state.UserID)
+	}
+}
@@ -17,12 +17,16 @@ package session
 
 import (
 	"context"
+	defaultErrors "errors"
 	"net/http"
+	"time"
 
 	"github.com/supertokens/supertokens-golang/recipe/jwt/jwtmodels"
 	"github.com/supertokens/supertokens-golang/recipe/openid/openidmodels"
 	"github.com/supertokens/supertokens-golang/recipe/session/claims"
+	"github.com/supertokens/supertokens-golang/recipe/session/errors"
 	"github.com/supertokens/supertokens-golang/recipe/session/sessmodels"
+	"github.com/supertokens/supertokens-golang/recipe/userroles/userrolesclaims"
 	"github.com/supertokens/supertokens-golang/supertokens"
 )
 
@@ -44,7 +48,14 @@ func CreateNewSession(req *http.Request, res http.ResponseWriter, tenantId strin
 	return CreateNewSessionInRequest(req, res, tenantId, config, appInfo, *instance, instance.RecipeImpl, userID, accessTokenPayload, sessionDataInDatabase, userContext[0])
 }
 
-func CreateNewSessionWithoutRequestResponse(tenantId string, userID string, accessTokenPayload map[string]interface{}, sessionDataInDatabase map[string]interface{}, disableAntiCSRF *bool, userContext ...supertokens.UserContext) (sessmodels.SessionContainer, error) {
+// RegenerateSession replaces the session with a new one, with a new session
+// handle and new tokens, and revokes the old one. The access token payload and
+// session data in database are kept, and claims are built again. The keys that
+// SuperTokens binds to one session (lifetimes, client and DPoP bindings, guest
+// marker) are not copied. Use it after privilege changes (sign in with a second
+// factor, sudo mode, ...) so that the identifiers of the old session can no
+// longer be used.
+func RegenerateSession(req *http.Request, res http.ResponseWriter, sessionContainer sessmodels.SessionContainer, userContext ...supertokens.UserContext) (sessmodels.SessionContainer, error) {
 	instance, err := getRecipeInstanceOrThrowError()
 	if err != nil {
 		return nil, err
@@ -52,27 +63,48 @@ func CreateNewSessionWithoutRequestResponse(tenantId string, userID string, acce
 	if len(userContext) == 0 {
 		userContext = append(userContext, &map[string]interface{}{})
 	}
-
-	claimsAddedByOtherRecipes := instance.GetClaimsAddedByOtherRecipes()
-	finalAccessTokenPayload := accessTokenPayload
-	if finalAccessTokenPayload == nil {
-		finalAccessTokenPayload = map[string]interface{}{}
+	sessionDataInDatabase, err := sessionContainer.GetSessionDataInDatabaseWithContext(userContext[0])
+	if err != nil {
+		return nil, err
 	}
+	accessTokenPayload, err := getRegeneratedSessionPayload(sessionContainer.GetAccessTokenPayloadWithContext(userContext[0]))
+	if err != nil {
+		return nil, err
+	}
+	// the old session is revoked below, even if the request does not carry
+	// its tokens, so it is not also revoked as the existing session of the
+	// request
+	config := instance.Config
+	config.RevokeExistingSessionOnCreate = false
+	newSession, err := CreateNewSessionInRequest(req, res, sessionContainer.GetTenantIdWithContext(userContext[0]), config, instance.RecipeModule.GetAppInfo(), *instance, instance.RecipeImpl, sessionContainer.GetUserIDWithContext(userContext[0]), accessTokenPayload, sessionDataInDatabase, userContext[0])
+	if err != nil {
+		return nil, err
+	}
+	// the tokens of the new session are already in the response, so the old
+	// session is revoked without clearing them
+	_, err = (*instance.RecipeImpl.RevokeSession)(sessionContainer.GetHandleWithContext(userContext[0]), userContext[0])
+	if err != nil {
+		return nil, err
+	}
+	return newSession, nil
+}
 
-	appInfo := instance.RecipeModule.GetAppInfo()
-	issuer := appInfo.APIDomain.GetAsStringDangerous() + appInfo.APIBasePath.GetAsStringDangerous()
-
-	finalAccessTokenPayload["iss"] = issuer
-
-	for _, protectedProp := range protectedProps {
-		delete(finalAccessTokenPayload, protectedProp)
+// CreateNewSessionWithoutRequestResponse creates a session without reading or
+// writing cookies and headers, e.g. in CLIs, gRPC handlers or background jobs.
+// Use GetAllSessionTokensDangerously on the result to get the access, refresh,
+// front and anti-csrf tokens and deliver them to the client yourself.
+func CreateNewSessionWithoutRequestResponse(tenantId string, userID string, accessTokenPayload map[string]interface{}, sessionDataInDatabase map[string]interface{}, disableAntiCSRF *bool, userContext ...supertokens.UserContext) (sessmodels.SessionContainer, error) {
+	instance, err := getRecipeInstanceOrThrowError()
+	if err != nil {
+		return nil, err
+	}
+	if len(userContext) == 0 {
+		userContext = append(userContext, &map[string]interface{}{})
 	}
 
-	for _, claim := range claimsAddedByOtherRecipes {
-		finalAccessTokenPayload, err = claim.Build(userID, tenantId, finalAccessTokenPayload, userContext[0])
-		if err != nil {
-			return nil, err
-		}
+	finalAccessTokenPayload, err := buildAccessTokenPayload(instance.Config, instance.RecipeModule.GetAppInfo(), *instance, tenantId, userID, accessTokenPayload, userContext[0])
+	if err != nil {
+		return nil, err
 	}
 
 	_disableAntiCSRF := false
@@ -81,7 +113,7 @@ func CreateNewSessionWithoutRequestResponse(tenantId string, userID string, acce
 		_disableAntiCSRF = *disableAntiCSRF
 	}
 
-	return (*instance.RecipeImpl.CreateNewSession)(userID, accessTokenPayload, sessionDataInDatabase, &_disableAntiCSRF, tenantId, userContext[0])
+	return (*instance.RecipeImpl.CreateNewSession)(userID, finalAccessTokenPayload, sessionDataInDatabase, &_disableAntiCSRF, tenantId, userContext[0])
 }
 
 func GetSession(req *http.Request, res http.ResponseWriter, options *sessmodels.VerifySessionOptions, userContext ...supertokens.UserContext) (sessmodels.SessionContainer, error) {
@@ -97,6 +129,11 @@ func GetSession(req *http.Request, res http.ResponseWriter, options *sessmodels.
 	return GetSessionFromRequest(req, res, config, options, instance.RecipeImpl, userContext[0])
 }
 
+// GetSessionWithoutRequestResponse verifies an access token that was not sent
+// in a request, e.g. by message consumers, cron jobs or non-HTTP servers, and
+// checks the session's claims. Pass antiCSRFToken only if the token was issued
+// with anti-csrf enabled and options.AntiCsrfCheck is true. Returns nil if the
+// token is invalid and options.SessionRequired is false.
 func GetSessionWithoutRequestResponse(accessToken string, antiCSRFToken *string, options *sessmodels.VerifySessionOptions, userContext ...supertokens.UserContext) (sessmodels.SessionContainer, error) {
 	instance, err := getRecipeInstanceOrThrowError()
 	if err != nil {
@@ -118,9 +155,6 @@ func GetSessionWithoutRequestResponse(accessToken string, antiCSRFToken *string,
 			overrideGlobalClaimValidators = options.OverrideGlobalClaimValidators
 		}
 
-		if err != nil {
-			return nil, err
-		}
 		claimValidators, err := GetRequiredClaimValidators(result, overrideGlobalClaimValidators, userContext[0])
 
 		if err != nil {
@@ -138,6 +172,11 @@ func GetSessionWithoutRequestResponse(accessToken string, antiCSRFToken *string,
 	return result, nil
 }
 
+// GetSessionInformation returns the user ID, tenant ID, access token payload,
+// session data in database, expiry and creation time of a session, e.g. for
+// admin tools or background jobs that work on sessions outside of a request.
+//
+// Returns nil if the session does not exist.
 func GetSessionInformation(sessionHandle string, userContext ...supertokens.UserContext) (*sessmodels.SessionInformation, error) {
 	instance, err := getRecipeInstanceOrThrowError()
 	if err != nil {
@@ -178,6 +217,10 @@ func RefreshSessionWithoutRequestResponse(refreshToken string, disableAntiCSRF *
 	return (*instance.RecipeImpl.RefreshSession)(refreshToken, antiCSRFToken, _disableAntiCSRF, userContext[0])
 }
 
+// RevokeAllSessionsForUser revokes the sessions of the user, e.g. after a
+// password change or when the account is compromised, and returns the handles
+// of the revoked sessions. If tenantId is nil, the sessions of all tenants are
+// revoked.
 func RevokeAllSessionsForUser(userID string, tenantId *string, userContext ...supertokens.UserContext) ([]string, error) {
 	instance, err := getRecipeInstanceOrThrowError()
 	if err != nil {
@@ -197,6 +240,9 @@ func RevokeAllSessionsForUser(userID string, tenantId *string, userContext ...su
 	return (*instance.RecipeImpl.RevokeAllSessionsForUser)(userID, *tenantId, revokeAcrossAllTenants, userContext[0])
 }
 
+// GetAllSessionHandlesForUser returns the handles of the user's sessions,
+// for all tenants if tenantId is nil. See also UserSessionsAPI in the recipe
+// config for an API that lists the signed in user's sessions.
 func GetAllSessionHandlesForUser(userID string, tenantId *string, userContext ...supertokens.UserContext) ([]string, error) {
 	instance, err := getRecipeInstanceOrThrowError()
 	if err != nil {
@@ -217,6 +263,27 @@ func GetAllSessionHandlesForUser(userID string, tenantId *string, userContext ..
 	return (*instance.RecipeImpl.GetAllSessionHandlesForUser)(userID, *tenantId, fetchAcrossAllTenants, userContext[0])
 }
 
+// ListDevicesForUser returns the sessions of the user with the device that
+// created them (name, user agent and IP by default) and when they were last
+// active, e.g. for an "active devices" page. Devices are only recorded when
+// UserSessionsAPI is enabled in the recipe config. If tenantId is nil, the
+// sessions of all tenants are returned.
+func ListDevicesForUser(userID string, tenantId *string, userContext ...supertokens.UserContext) ([]sessmodels.UserSession, error) {
+	instance, err := getRecipeInstanceOrThrowError()
+	if err != nil {
+		return nil, err
+	}
+	if len(userContext) == 0 {
+		userContext = append(userContext, &map[string]interface{}{})
+	}
+	fetchAcrossAllTenants := tenantId == nil
+	tenantIdStr := supertokens.DefaultTenantId
+	if tenantId != nil {
+		tenantIdStr = *tenantId
+	}
+	return listUserSessions(instance.RecipeImpl, userID, tenantIdStr, fetchAcrossAllTenants, "", userContext[0])
+}
+
 func RevokeSession(sessionHandle string, userContext ...supertokens.UserContext) (bool, error) {
 	instance, err := getRecipeInstanceOrThrowError()
 	if err != nil {
@@ -239,6 +306,39 @@ func RevokeMultipleSessions(sessionHandles []string, userContext ...supertokens.
 	return (*instance.RecipeImpl.RevokeMultipleSessions)(sessionHandles, userContext[0])
 }
 
+// RevokeSessionsMatching revokes the sessions of the user that match filter,
+// e.g. sessions created from a suspicious IP range before a given time, and
+// returns the handles of the revoked sessions. Other sessions of the user are
+// kept. If tenantId is nil, the sessions of all tenants are checked.
+func RevokeSessionsMatching(userID string, tenantId *string, filter SessionFilter, userContext ...supertokens.UserContext) ([]string, error) {
+	instance, err := getRecipeInstanceOrThrowError()
+	if err != nil {
+		return nil, err
+	}
+	if len(userContext) == 0 {
+		userContext = append(userContext, &map[string]interface{}{})
+	}
+	fetchAcrossAllTenants := tenantId == nil
+	tenantIdStr := supertokens.DefaultTenantId
+	if tenantId != nil {
+		tenantIdStr = *tenantId
+	}
+	sessions, err := listUserSessions(instance.RecipeImpl, userID, tenantIdStr, fetchAcrossAllTenants, "", userContext[0])
+	if err != nil {
+		return nil, err
+	}
+	sessionHandles := []string{}
+	for _, userSession := range sessions {
+		if filter(userSession) {
+			sessionHandles = append(sessionHandles, userSession.SessionHandle)
+		}
+	}
+	if len(sessionHandles) == 0 {
+		return []string{}, nil
+	}
+	return (*instance.RecipeImpl.RevokeMultipleSessions)(sessionHandles, userContext[0])
+}
+
 func UpdateSessionDataInDatabase(sessionHandle string, newSessionData map[string]interface{}, userContext ...supertokens.UserContext) (bool, error) {
 	instance, err := getRecipeInstanceOrThrowError()
 	if err != nil {
@@ -250,6 +350,115 @@ func UpdateSessionDataInDatabase(sessionHandle string, newSessionData map[string
 	return (*instance.RecipeImpl.UpdateSessionDataInDatabase)(sessionHandle, newSessionData, userContext[0])
 }
 
+// MergeIntoSessionDataInDatabase merges update into the session data in
+// database of the session, e.g. from a backend job. Keys set to nil are
+// removed. This does a read followed by a write of the session data, so
+// concurrent updates to the same session can overwrite each other.
+//
+// Returns false if the session does not exist.
+func MergeIntoSessionDataInDatabase(sessionHandle string, update map[string]interface{}, userContext ...supertokens.UserContext) (bool, error) {
+	instance, err := getRecipeInstanceOrThrowError()
+	if err != nil {
+		return false, err
+	}
+	if len(userContext) == 0 {
+		userContext = append(userContext, &map[string]interface{}{})
+	}
+	sessionInfo, err := (*instance.RecipeImpl.GetSessionInformation)(sessionHandle, userContext[0])
+	if err != nil || sessionInfo == nil {
+		return false, err
+	}
+	sessionData := mergeIntoSessionData(sessionInfo.SessionDataInDatabase, update)
+	return (*instance.RecipeImpl.UpdateSessionDataInDatabase)(sessionHandle, sessionData, userContext[0])
+}
+
+// IsUserOnline returns true if the user verified a session within the
+// presence TTL. Presence must be enabled in the session recipe config.
+func IsUserOnline(userID string, userContext ...supertokens.UserContext) (bool, error) {
+	onlineUsers, err := GetOnlineUsers([]string{userID}, userContext...)
+	if err != nil {
+		return false, err
+	}
+	return onlineUsers[userID], nil
+}
+
+// GetOnlineUsers returns whether each of the given users is online
+func GetOnlineUsers(userIDs []string, userContext ...supertokens.UserContext) (map[string]bool, error) {
+	instance, err := getRecipeInstanceOrThrowError()
+	if err != nil {
+		return nil, err
+	}
+	if instance.Config.Presence == nil {
+		return nil, defaultErrors.New("presence tracking is disabled. Please set Presence in the session recipe config")
+	}
+	if len(userContext) == 0 {
+		userContext = append(userContext, &map[string]interface{}{})
+	}
+	return (*instance.Config.Presence.Store.GetOnlineUsers)(userIDs, userContext[0])
+}
+
+// SuspendSession makes verifying the session fail with a
+// SessionSuspendedError until ResumeSession is called, e.g. while an account
+// is under review. Unlike RevokeSession, the session is kept, so the user
+// does not have to sign in again once it is resumed. It returns false if the
+// session does not exist. SessionSuspension must be enabled in the session
+// recipe config.
+func SuspendSession(sessionHandle string, reason string, userContext ...supertokens.UserContext) (bool, error) {
+	instance, err := getRecipeInstanceOrThrowError()
+	if err != nil {
+		return false, err
+	}
+	if instance.Config.SessionSuspension == nil {
+		return false, defaultErrors.New("session suspension is disabled. Please set SessionSuspension in the session recipe config")
+	}
+	if len(userContext) == 0 {
+		userContext = append(userContext, &map[string]interface{}{})
+	}
+	sessionInfo, err := (*instance.RecipeImpl.GetSessionInformation)(sessionHandle, userContext[0])
+	if err != nil || sessionInfo == nil {
+		return false, err
+	}
+	expiresAt := time.UnixMilli(int64(sessionInfo.Expiry))
+	err = (*instance.Config.SessionSuspension.Store.Suspend)(sessionHandle, reason, expiresAt, userContext[0])
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// ResumeSession lets a session suspended with SuspendSession be verified
+// again
+func ResumeSession(sessionHandle string, userContext ...supertokens.UserContext) error {
+	instance, err := getRecipeInstanceOrThrowError()
+	if err != nil {
+		return err
+	}
+	if instance.Config.SessionSuspension == nil {
+		return defaultErrors.New("session suspension is disabled. Please set SessionSuspension in the session recipe config")
+	}
+	if len(userContext) == 0 {
+		userContext = append(userContext, &map[string]interface{}{})
+	}
+	return (*instance.Config.SessionSuspension.Store.Resume)(sessionHandle, userContext[0])
+}
+
+// IsSessionSuspended returns true if the session was suspended with
+// SuspendSession and not resumed since
+func IsSessionSuspended(sessionHandle string, userContext ...supertokens.UserContext) (bool, error) {
+	instance, err := getRecipeInstanceOrThrowError()
+	if err != nil {
+		return false, err
+	}
+	if instance.Config.SessionSuspension == nil {
+		return false, nil
+	}
+	if len(userContext) == 0 {
+		userContext = append(userContext, &map[string]interface{}{})
+	}
+	reason, err := (*instance.Config.SessionSuspension.Store.GetSuspensionReason)(sessionHandle, userContext[0])
+	return reason != nil, err
+}
+
 func CreateJWT(payload map[string]interface{}, validitySecondsPointer *uint64, useStaticSigningKey *bool, userContext ...supertokens.UserContext) (jwtmodels.CreateJWTResponse, error) {
 	instance, err := getRecipeInstanceOrThrowError()
 	if err != nil {
@@ -261,6 +470,9 @@ func CreateJWT(payload map[string]interface{}, validitySecondsPointer *uint64, u
 	return (*instance.OpenIdRecipe.RecipeImpl.CreateJWT)(payload, validitySecondsPointer, useStaticSigningKey, userContext[0])
 }
 
+// GetJWKS returns the public keys that access tokens (and JWTs created with
+// CreateJWT) are signed with. They are also served at /jwt/jwks.json under
+// the API base path, so that other services can verify access tokens.
 func GetJWKS(userContext ...supertokens.UserContext) (jwtmodels.GetJWKSResponse, error) {
 	instance, err := getRecipeInstanceOrThrowError()
 	if err != nil {
@@ -375,6 +587,12 @@ func ValidateClaimsInJWTPayload(
 	return invalidClaims, nil
 }
 
+// MergeIntoAccessTokenPayload merges accessTokenPayloadUpdate into the access
+// token payload of the session, e.g. after a role or plan change. Keys set to
+// nil are removed. The user gets the new payload on the next refresh, without
+// having to sign in again.
+//
+// Returns false if the session does not exist.
 func MergeIntoAccessTokenPayload(sessionHandle string, accessTokenPayloadUpdate map[string]interface{}, userContext ...supertokens.UserContext) (bool, error) {
 	instance, err := getRecipeInstanceOrThrowError()
 	if err != nil {
@@ -446,3 +664,124 @@ func GetSessionFromRequestContext(ctx context.Context) sessmodels.SessionContain
 	temp := value.(sessmodels.SessionContainer)
 	return temp
 }
+
+// NewAuthorizationError returns the error that VerifySessionOptions.Authorize
+// returns to reject a request. It is sent to the frontend like a failed claim
+// validation, with the id "authorization" and reason.
+func NewAuthorizationError(reason string) error {
+	return errors.InvalidClaimError{
+		Msg: "invalid claims",
+		InvalidClaims: []claims.ClaimValidationError{{
+			ID:     "authorization",
+			Reason: reason,
+		}},
+	}
+}
+
+// WithClaimValidators returns an OverrideGlobalClaimValidators for
+// VerifySessionOptions that checks the given validators in addition to the
+// global ones, e.g. WithClaimValidators(userroles.RequireRole("admin")).
+func WithClaimValidators(validators ...claims.SessionClaimValidator) func(globalClaimValidators []claims.SessionClaimValidator, sessionContainer sessmodels.SessionContainer, userContext supertokens.UserContext) ([]claims.SessionClaimValidator, error) {
+	return func(globalClaimValidators []claims.SessionClaimValidator, sessionContainer sessmodels.SessionContainer, userContext supertokens.UserContext) ([]claims.SessionClaimValidator, error) {
+		result := make([]claims.SessionClaimValidator, 0, len(globalClaimValidators)+len(validators))
+		result = append(result, globalClaimValidators...)
+		return append(result, validators...), nil
+	}
+}
+
+// VerifySessionWithRoles is like VerifySession, but also requires the user to
+// have all the given roles. Other users get a response with the
+// InvalidClaimStatusCode (403 by default) and the failed st-role claim
+// validation in its body. The userroles recipe must be initialised.
+func VerifySessionWithRoles(options *sessmodels.VerifySessionOptions, roles []string, otherHandler http.HandlerFunc) http.HandlerFunc {
+	return VerifySession(WithRequiredRoles(options, roles), otherHandler)
+}
+
+// WithRequiredRoles returns a copy of options that also requires the user to
+// have all the given roles, for framework adapters that wrap VerifySession.
+// It can be called before supertokens.Init; the verification fails with an
+// error if the userroles recipe is not initialised by then.
+func WithRequiredRoles(options *sessmodels.VerifySessionOptions, roles []string) *sessmodels.VerifySessionOptions {
+	requiredRoles := make([]interface{}, len(roles))
+	for i, role := range roles {
+		requiredRoles[i] = role
+	}
+
+	result := sessmodels.VerifySessionOptions{}
+	if options != nil {
+		result = *options
+	}
+	overrideGlobalClaimValidators := result.OverrideGlobalClaimValidators
+	result.OverrideGlobalClaimValidators = func(globalClaimValidators []claims.SessionClaimValidator, sessionContainer sessmodels.SessionContainer, userContext supertokens.UserContext) ([]claims.SessionClaimValidator, error) {
+		if overrideGlobalClaimValidators != nil {
+			var err error
+			globalClaimValidators, err = overrideGlobalClaimValidators(globalClaimValidators, sessionContainer, userContext)
+			if err != nil {
+				return nil, err
+			}
+		}
+		if userrolesclaims.UserRoleClaim == nil {
+			return nil, defaultErrors.New("the userroles recipe is not initialised. You should add userroles.Init to the RecipeList before using VerifySessionWithRoles")
+		}
+		roleValidator := userrolesclaims.UserRoleClaimValidators.IncludesAll(requiredRoles, nil, nil)
+		return WithClaimValidators(roleValidator)(globalClaimValidators, sessionContainer, userContext)
+	}
+	return &result
+}
+
+// CreateGuestSession creates a session for a visitor that did not sign up
+// yet, with a generated user ID. GuestSessions must be enabled in the session
+// recipe config. The session is upgraded when a session is created for the
+// same request later on, e.g. on sign up.
+func CreateGuestSession(req *http.Request, res http.ResponseWriter, tenantId string, accessTokenPayload map[string]interface{}, sessionDataInDatabase map[string]interface{}, userContext ...supertokens.UserContext) (sessmodels.SessionContainer, error) {
+	instance, err := getRecipeInstanceOrThrowError()
+	if err != nil {
+		return nil, err
+	}
+	if instance.Config.GuestSessions == nil {
+		return nil, defaultErrors.New("guest sessions are disabled. Please set GuestSessions in the session recipe config")
+	}
+	if len(userContext) == 0 {
+		userContext = append(userContext, &map[string]interface{}{})
+	}
+	guestUserID, err := generateGuestUserID()
+	if err != nil {
+		return nil, err
+	}
+	guestAccessTokenPayload := map[string]interface{}{}
+	for k, v := range accessTokenPayload {
+		guestAccessTokenPayload[k] = v
+	}
+	guestAccessTokenPayload[guestSessionKey] = true
+	return CreateNewSessionInRequest(req, res, tenantId, instance.Config, instance.RecipeModule.GetAppInfo(), *instance, instance.RecipeImpl, guestUserID, guestAccessTokenPayload, sessionDataInDatabase, userContext[0])
+}
+
+// IsGuestSession returns true if the session was created with
+// CreateGuestSession
+func IsGuestSession(sessionContainer sessmodels.SessionContainer, userContext ...supertokens.UserContext) bool {
+	if len(userContext) == 0 {
+		userContext = append(userContext, &map[string]interface{}{})
+	}
+	return isGuestSessionPayload(sessionContainer.GetAccessTokenPayloadWithContext(userContext[0]))
+}
+
+// WithGuestSessionsAllowed returns a copy of options that also lets guest
+// sessions through. The global claim validators are not checked for guest
+// sessions, since their user does not exist.
+func WithGuestSessionsAllowed(options *sessmodels.VerifySessionOptions) *sessmodels.VerifySessionOptions {
+	result := sessmodels.VerifySessionOptions{}
+	if options != nil {
+		result = *options
+	}
+	overrideGlobalClaimValidators := result.OverrideGlobalClaimValidators
+	result.OverrideGlobalClaimValidators = func(globalClaimValidators []claims.SessionClaimValidator, sessionContainer sessmodels.SessionContainer, userContext supertokens.UserContext) ([]claims.SessionClaimValidator, error) {
+		if IsGuestSession(sessionContainer, userContext) {
+			return []claims.SessionClaimValidator{}, nil
+		}
+		if overrideGlobalClaimValidators != nil {
+			return overrideGlobalClaimValidators(globalClaimValidators, sessionContainer, userContext)
+		}
+		return globalClaimValidators, nil
+	}
+	return &result
+}
@@ -0,0 +1,105 @@
+/* Copyright (c) 2023, VRAI Labs and/or its affiliates. All rights reserved.
+ *
+ * This software is licensed under the Apache License, Version 2.0 (the
+ * "License") as published by the Apache Software Foundation.
+ *
+ * You may not use this file except in compliance with the License. You may
+ * obtain a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+ * License for the specific language governing permissions and limitations
+ * under the License.
+ */
+
+package session
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/supertokens/supertokens-golang/recipe/session/sessmodels"
+	"github.com/supertokens/supertokens-golang/supertokens"
+)
+
+func TestOverrideWrapsFunctionsAndDisablesAPIs(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	assert.NoError(t, err)
+	var createdSession map[string]interface{}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/apiversion", func(rw http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(rw).Encode(map[string]interface{}{"versions": []string{"3.0"}})
+	})
+	mux.HandleFunc("/", func(rw http.ResponseWriter, r *http.Request) {
+		if strings.HasSuffix(r.URL.Path, "/recipe/session") && r.Method == http.MethodPost {
+			json.NewDecoder(r.Body).Decode(&createdSession)
+			claims := jwt.MapClaims{"sub": "user1", "sessionHandle": "handle1", "iat": time.Now().Unix(), "exp": time.Now().Add(time.Hour).Unix(), "tId": "public"}
+			token := jwt.NewWithClaims(jwt.SigningMethodRS256, claims)
+			token.Header["kid"] = "d-1"
+			signed, _ := token.SignedString(key)
+			json.NewEncoder(rw).Encode(map[string]interface{}{
+				"status":       "OK",
+				"session":      map[string]interface{}{"handle": "handle1", "userId": "user1", "userDataInJWT": createdSession["userDataInJWT"], "tenantId": "public"},
+				"accessToken":  map[string]interface{}{"token": signed, "expiry": time.Now().Add(time.Hour).UnixMilli(), "createdTime": time.Now().UnixMilli()},
+				"refreshToken": map[string]interface{}{"token": "refresh1", "expiry": time.Now().Add(time.Hour).UnixMilli(), "createdTime": time.Now().UnixMilli()},
+			})
+			return
+		}
+		rw.WriteHeader(http.StatusNotFound)
+	})
+	testServer := httptest.NewServer(mux)
+	defer testServer.Close()
+
+	resetAll()
+	defer resetAll()
+	err = supertokens.Init(supertokens.TypeInput{
+		Supertokens: &supertokens.ConnectionInfo{
+			ConnectionURI: testServer.URL,
+		},
+		AppInfo: supertokens.AppInfo{
+			AppName:       "SuperTokens",
+			WebsiteDomain: "supertokens.io",
+			APIDomain:     "api.supertokens.io",
+		},
+		RecipeList: []supertokens.Recipe{Init(&sessmodels.TypeInput{
+			Override: &sessmodels.OverrideStruct{
+				Functions: func(originalImplementation sessmodels.RecipeInterface) sessmodels.RecipeInterface {
+					originalCreateNewSession := *originalImplementation.CreateNewSession
+					(*originalImplementation.CreateNewSession) = func(userID string, accessTokenPayload map[string]interface{}, sessionDataInDatabase map[string]interface{}, disableAntiCsrf *bool, tenantId string, userContext supertokens.UserContext) (sessmodels.SessionContainer, error) {
+						if accessTokenPayload == nil {
+							accessTokenPayload = map[string]interface{}{}
+						}
+						accessTokenPayload["role"] = "admin"
+						return originalCreateNewSession(userID, accessTokenPayload, sessionDataInDatabase, disableAntiCsrf, tenantId, userContext)
+					}
+					return originalImplementation
+				},
+				APIs: func(originalImplementation sessmodels.APIInterface) sessmodels.APIInterface {
+					originalImplementation.SignOutPOST = nil
+					return originalImplementation
+				},
+			},
+		})},
+	})
+	assert.NoError(t, err)
+
+	sessionContainer, err := CreateNewSessionWithoutRequestResponse("public", "user1", nil, nil, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, "handle1", sessionContainer.GetHandle())
+	assert.Equal(t, "admin", createdSession["userDataInJWT"].(map[string]interface{})["role"])
+
+	routes, err := supertokens.GetAPIRoutes()
+	assert.NoError(t, err)
+	for _, route := range routes {
+		assert.NotEqual(t, "/signout", route.PathWithoutAPIBasePath)
+	}
+}
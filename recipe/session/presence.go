@@ -0,0 +1,104 @@
+/* Copyright (c) 2023, VRAI Labs and/or its affiliates. All rights reserved.
+ *
+ * This software is licensed under the Apache License, Version 2.0 (the
+ * "License") as published by the Apache Software Foundation.
+ *
+ * You may not use this file except in compliance with the License. You may
+ * obtain a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+ * License for the specific language governing permissions and limitations
+ * under the License.
+ */
+
+package session
+
+import (
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/supertokens/supertokens-golang/recipe/session/sessmodels"
+	"github.com/supertokens/supertokens-golang/supertokens"
+)
+
+const defaultPresenceTTLInSeconds = 300
+
+func normalisePresenceInput(config *sessmodels.PresenceInput) (*sessmodels.NormalisedPresenceConfig, error) {
+	if config == nil {
+		return nil, nil
+	}
+	result := &sessmodels.NormalisedPresenceConfig{
+		TTLInSeconds: defaultPresenceTTLInSeconds,
+	}
+	if config.TTLInSeconds != nil {
+		result.TTLInSeconds = *config.TTLInSeconds
+	}
+	if config.Store != nil {
+		if config.Store.MarkUserActive == nil || config.Store.GetOnlineUsers == nil {
+			return nil, errors.New("presence store must implement both MarkUserActive and GetOnlineUsers")
+		}
+		result.Store = *config.Store
+	} else {
+		result.Store = MakeInMemoryPresenceStore()
+	}
+	return result, nil
+}
+
+// markUserActive is best effort: failing to record presence should never fail
+// the request that verified the session.
+func markUserActive(config sessmodels.TypeNormalisedInput, userID string, userContext supertokens.UserContext) {
+	if config.Presence == nil {
+		return
+	}
+	ttl := time.Duration(config.Presence.TTLInSeconds) * time.Second
+	err := (*config.Presence.Store.MarkUserActive)(userID, ttl, userContext)
+	if err != nil {
+		supertokens.LogDebugMessage("markUserActive: Failed to record presence: " + err.Error())
+	}
+}
+
+// MakeInMemoryPresenceStore returns a presence store that keeps the last
+// activity of users in process memory.
+func MakeInMemoryPresenceStore() sessmodels.PresenceStore {
+	var mutex sync.Mutex
+	onlineUntil := map[string]time.Time{}
+	nextCleanup := time.Now()
+
+	markUserActive := func(userID string, ttl time.Duration, userContext supertokens.UserContext) error {
+		mutex.Lock()
+		defer mutex.Unlock()
+
+		now := time.Now()
+		if !now.Before(nextCleanup) {
+			for id, until := range onlineUntil {
+				if !now.Before(until) {
+					delete(onlineUntil, id)
+				}
+			}
+			nextCleanup = now.Add(ttl)
+		}
+		onlineUntil[userID] = now.Add(ttl)
+		return nil
+	}
+
+	getOnlineUsers := func(userIDs []string, userContext supertokens.UserContext) (map[string]bool, error) {
+		mutex.Lock()
+		defer mutex.Unlock()
+
+		now := time.Now()
+		result := map[string]bool{}
+		for _, userID := range userIDs {
+			until, ok := onlineUntil[userID]
+			result[userID] = ok && now.Before(until)
+		}
+		return result, nil
+	}
+
+	return sessmodels.PresenceStore{
+		MarkUserActive: &markUserActive,
+		GetOnlineUsers: &getOnlineUsers,
+	}
+}
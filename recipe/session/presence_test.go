@@ -0,0 +1,77 @@
+/* Copyright (c) 2023, VRAI Labs and/or its affiliates. All rights reserved.
+ *
+ * This software is licensed under the Apache License, Version 2.0 (the
+ * "License") as published by the Apache Software Foundation.
+ *
+ * You may not use this file except in compliance with the License. You may
+ * obtain a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+ * License for the specific language governing permissions and limitations
+ * under the License.
+ */
+
+package session
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/supertokens/supertokens-golang/recipe/session/sessmodels"
+	"github.com/supertokens/supertokens-golang/supertokens"
+)
+
+func TestInMemoryPresenceStore(t *testing.T) {
+	store := MakeInMemoryPresenceStore()
+	userContext := &map[string]interface{}{}
+
+	err := (*store.MarkUserActive)("user1", 50*time.Millisecond, userContext)
+	assert.NoError(t, err)
+
+	onlineUsers, err := (*store.GetOnlineUsers)([]string{"user1", "user2"}, userContext)
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]bool{"user1": true, "user2": false}, onlineUsers)
+
+	time.Sleep(60 * time.Millisecond)
+
+	onlineUsers, err = (*store.GetOnlineUsers)([]string{"user1"}, userContext)
+	assert.NoError(t, err)
+	assert.False(t, onlineUsers["user1"])
+}
+
+func TestPresenceIsDisabledByDefault(t *testing.T) {
+	config, err := normalisePresenceInput(nil)
+	assert.NoError(t, err)
+	assert.Nil(t, config)
+
+	ttl := uint64(10)
+	config, err = normalisePresenceInput(&sessmodels.PresenceInput{TTLInSeconds: &ttl})
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(10), config.TTLInSeconds)
+	assert.NotNil(t, config.Store.MarkUserActive)
+}
+
+func TestThatIncompletePresenceStoreFailsInit(t *testing.T) {
+	markUserActive := func(userID string, ttl time.Duration, userContext supertokens.UserContext) error {
+		return nil
+	}
+	_, err := normalisePresenceInput(&sessmodels.PresenceInput{
+		Store: &sessmodels.PresenceStore{
+			MarkUserActive: &markUserActive,
+		},
+	})
+	assert.Error(t, err)
+
+	getOnlineUsers := func(userIDs []string, userContext supertokens.UserContext) (map[string]bool, error) {
+		return map[string]bool{}, nil
+	}
+	_, err = normalisePresenceInput(&sessmodels.PresenceInput{
+		Store: &sessmodels.PresenceStore{
+			GetOnlineUsers: &getOnlineUsers,
+		},
+	})
+	assert.Error(t, err)
+}
@@ -78,6 +78,9 @@ func MakeRecipe(recipeId string, appInfo supertokens.NormalisedAppinfo, config *
 	supertokens.LogDebugMessage("session init: SessionExpiredStatusCode: " + strconv.Itoa(verifiedConfig.SessionExpiredStatusCode))
 
 	r.Config = verifiedConfig
+	if verifiedConfig.GuestSessions != nil {
+		r.claimValidatorsAddedByOtherRecipes = append(r.claimValidatorsAddedByOtherRecipes, guestSessionValidator)
+	}
 	r.APIImpl = verifiedConfig.Override.APIs(MakeAPIImplementation())
 
 	querierInstance, err := supertokens.GetNewQuerierInstanceOrThrowError(recipeId)
@@ -87,7 +90,8 @@ func MakeRecipe(recipeId string, appInfo supertokens.NormalisedAppinfo, config *
 	recipeImplementation := MakeRecipeImplementation(*querierInstance, verifiedConfig, appInfo)
 
 	openIdRecipe, err := openid.MakeRecipe(recipeId, appInfo, &openidmodels.TypeInput{
-		Override: verifiedConfig.Override.OpenIdFeature,
+		SigningAlgorithm: verifiedConfig.JWTSigningAlgorithm,
+		Override:         verifiedConfig.Override.OpenIdFeature,
 	}, onSuperTokensAPIError)
 
 	if err != nil {
@@ -136,6 +140,14 @@ func (r *Recipe) getAPIsHandled() ([]supertokens.APIHandled, error) {
 	if err != nil {
 		return nil, err
 	}
+	userSessionsAPIPathNormalised, err := supertokens.NewNormalisedURLPath(UserSessionsAPIPath)
+	if err != nil {
+		return nil, err
+	}
+	revokeUserSessionAPIPathNormalised, err := supertokens.NewNormalisedURLPath(RevokeUserSessionAPIPath)
+	if err != nil {
+		return nil, err
+	}
 	resp := []supertokens.APIHandled{{
 		Method:                 http.MethodPost,
 		PathWithoutAPIBasePath: refreshAPIPathNormalised,
@@ -146,6 +158,16 @@ func (r *Recipe) getAPIsHandled() ([]supertokens.APIHandled, error) {
 		PathWithoutAPIBasePath: signoutAPIPathNormalised,
 		ID:                     SignoutAPIPath,
 		Disabled:               r.APIImpl.SignOutPOST == nil,
+	}, {
+		Method:                 http.MethodGet,
+		PathWithoutAPIBasePath: userSessionsAPIPathNormalised,
+		ID:                     UserSessionsAPIPath,
+		Disabled:               r.APIImpl.UserSessionsGET == nil || r.Config.UserSessionsAPI == nil,
+	}, {
+		Method:                 http.MethodPost,
+		PathWithoutAPIBasePath: revokeUserSessionAPIPathNormalised,
+		ID:                     RevokeUserSessionAPIPath,
+		Disabled:               r.APIImpl.RevokeUserSessionPOST == nil || r.Config.UserSessionsAPI == nil,
 	}}
 
 	jwtAPIs, err := r.OpenIdRecipe.RecipeModule.GetAPIsHandled()
@@ -172,6 +194,10 @@ func (r *Recipe) handleAPIRequest(id string, tenantId string, req *http.Request,
 		return HandleRefreshAPI(r.APIImpl, options, userContext)
 	} else if id == SignoutAPIPath {
 		return SignOutAPI(r.APIImpl, options, userContext)
+	} else if id == UserSessionsAPIPath {
+		return UserSessionsAPI(r.APIImpl, options, userContext)
+	} else if id == RevokeUserSessionAPIPath {
+		return RevokeUserSessionAPI(r.APIImpl, options, userContext)
 	} else {
 		return r.OpenIdRecipe.RecipeModule.HandleAPIRequest(id, tenantId, req, res, theirhandler, path, method, userContext)
 	}
@@ -180,13 +206,20 @@ func (r *Recipe) handleAPIRequest(id string, tenantId string, req *http.Request,
 func (r *Recipe) getAllCORSHeaders() []string {
 	resp := GetCORSAllowedHeaders()
 	resp = append(resp, r.OpenIdRecipe.RecipeModule.GetAllCORSHeaders()...)
+	if r.Config.DPoP != nil {
+		resp = append(resp, dpopHeaderName)
+	}
 	return resp
 }
 
 func (r *Recipe) handleError(err error, req *http.Request, res http.ResponseWriter, userContext supertokens.UserContext) (bool, error) {
-	if defaultErrors.As(err, &errors.UnauthorizedError{}) {
+	var unauthErr errors.UnauthorizedError
+	var tokenTheftErr errors.TokenTheftDetectedError
+	var invalidClaimErr errors.InvalidClaimError
+	var refreshThrottledErr refreshThrottledError
+	var sessionSuspendedErr errors.SessionSuspendedError
+	if defaultErrors.As(err, &unauthErr) {
 		supertokens.LogDebugMessage("errorHandler: returning UNAUTHORISED")
-		unauthErr := err.(errors.UnauthorizedError)
 		if unauthErr.ClearTokens == nil || *unauthErr.ClearTokens {
 			supertokens.LogDebugMessage("errorHandler: Clearing tokens because of UNAUTHORISED response")
 			ClearSessionFromAllTokenTransferMethods(r.Config, req, res, userContext)
@@ -195,15 +228,27 @@ func (r *Recipe) handleError(err error, req *http.Request, res http.ResponseWrit
 	} else if defaultErrors.As(err, &errors.TryRefreshTokenError{}) {
 		supertokens.LogDebugMessage("errorHandler: returning TRY_REFRESH_TOKEN")
 		return true, r.Config.ErrorHandlers.OnTryRefreshToken(err.Error(), req, res)
-	} else if defaultErrors.As(err, &errors.TokenTheftDetectedError{}) {
+	} else if defaultErrors.As(err, &tokenTheftErr) {
 		supertokens.LogDebugMessage("errorHandler: clearing tokens because of TOKEN_THEFT_DETECTED response")
 		ClearSessionFromAllTokenTransferMethods(r.Config, req, res, userContext)
-		errs := err.(errors.TokenTheftDetectedError)
-		return true, r.Config.ErrorHandlers.OnTokenTheftDetected(errs.Payload.SessionHandle, errs.Payload.UserID, req, res)
-	} else if defaultErrors.As(err, &errors.InvalidClaimError{}) {
+		var onTokenTheftErr error
+		if r.Config.OnTokenTheft != nil {
+			onTokenTheftErr = r.Config.OnTokenTheft(tokenTheftErr.Payload.SessionHandle, tokenTheftErr.Payload.UserID, req, userContext)
+		}
+		err = r.Config.ErrorHandlers.OnTokenTheftDetected(tokenTheftErr.Payload.SessionHandle, tokenTheftErr.Payload.UserID, req, res)
+		if onTokenTheftErr != nil {
+			return true, onTokenTheftErr
+		}
+		return true, err
+	} else if defaultErrors.As(err, &invalidClaimErr) {
 		supertokens.LogDebugMessage("errorHandler: returning INVALID_CLAIMS")
-		errs := err.(errors.InvalidClaimError)
-		return true, r.Config.ErrorHandlers.OnInvalidClaim(errs.InvalidClaims, req, res)
+		return true, r.Config.ErrorHandlers.OnInvalidClaim(invalidClaimErr.InvalidClaims, req, res)
+	} else if defaultErrors.As(err, &refreshThrottledErr) {
+		supertokens.LogDebugMessage("errorHandler: returning TOO_MANY_REQUESTS")
+		return true, sendRefreshThrottledResponse(refreshThrottledErr, res)
+	} else if defaultErrors.As(err, &sessionSuspendedErr) {
+		supertokens.LogDebugMessage("errorHandler: returning SESSION_SUSPENDED")
+		return true, r.Config.ErrorHandlers.OnSessionSuspended(sessionSuspendedErr.SessionHandle, sessionSuspendedErr.Reason, req, res)
 	} else {
 		return r.OpenIdRecipe.RecipeModule.HandleError(err, req, res, userContext)
 	}
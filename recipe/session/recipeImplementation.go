@@ -17,11 +17,11 @@ package session
 
 import (
 	"bytes"
-	"encoding/json"
 	defaultErrors "errors"
 	"fmt"
 	"reflect"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/MicahParks/keyfunc/v2"
@@ -35,6 +35,14 @@ import (
 var jwksCache *sessmodels.GetJWKSResult = nil
 var mutex sync.RWMutex
 
+// jwksRefreshingInBackground is 1 while the keys are fetched before the
+// cached ones expire
+var jwksRefreshingInBackground int32
+
+// lastJWKSRefetch is when the keys were last fetched again because a token
+// could not be verified with the cached ones, in milliseconds
+var lastJWKSRefetch int64
+
 func getJWKSFromCacheIfPresent() *sessmodels.GetJWKSResult {
 	mutex.RLock()
 	defer mutex.RUnlock()
@@ -49,6 +57,9 @@ func getJWKSFromCacheIfPresent() *sessmodels.GetJWKSResult {
 		// if it has a valid cache entry from one of the core URLs. It will only attempt to fetch
 		// from the cores again after the entry in the cache is expired
 		if (currentTime - jwksCache.LastFetched) < JWKCacheMaxAgeInMs {
+			if JWKProactiveRefreshWindowInMs > 0 && (currentTime-jwksCache.LastFetched) >= JWKCacheMaxAgeInMs-JWKProactiveRefreshWindowInMs {
+				refreshJWKSInBackground()
+			}
 			if supertokens.IsRunningInTestMode() {
 				if len(returnedFromCache) == cap(returnedFromCache) { // need to clear the channel if full because it's not being consumed in the test
 					close(returnedFromCache)
@@ -77,10 +88,16 @@ func getJWKS() (*keyfunc.JWKS, error) {
 		return resultFromCache.JWKS, nil
 	}
 
-	var lastError error
-
 	mutex.Lock()
 	defer mutex.Unlock()
+	return fetchJWKS(corePaths)
+}
+
+// fetchJWKS fetches the keys from the first core that responds and caches
+// them. The caller must hold the write lock of mutex.
+func fetchJWKS(corePaths []string) (*keyfunc.JWKS, error) {
+	var lastError error
+
 	for _, path := range corePaths {
 		if supertokens.IsRunningInTestMode() {
 			urlsAttemptedForJWKSFetch = append(urlsAttemptedForJWKSFetch, path)
@@ -124,6 +141,47 @@ func getJWKS() (*keyfunc.JWKS, error) {
 	return nil, lastError
 }
 
+// refreshJWKSInBackground fetches the keys again before the cached ones
+// expire. The cached keys keep being used until the new ones are fetched, and
+// if fetching fails they are fetched again once they expire.
+func refreshJWKSInBackground() {
+	if !atomic.CompareAndSwapInt32(&jwksRefreshingInBackground, 0, 1) {
+		return
+	}
+	go func() {
+		defer atomic.StoreInt32(&jwksRefreshingInBackground, 0)
+		corePaths := supertokens.GetAllCoreUrlsForPath("/.well-known/jwks.json")
+		if len(corePaths) == 0 {
+			return
+		}
+		mutex.Lock()
+		defer mutex.Unlock()
+		_, err := fetchJWKS(corePaths)
+		if err != nil {
+			supertokens.LogDebugMessage("refreshJWKSInBackground: Failed to fetch the JWKS: " + err.Error())
+		}
+	}()
+}
+
+// refetchJWKS drops the cached keys and fetches them again, e.g. when a token
+// signed with a newly rotated key could not be verified with the cached keys.
+// It returns nil if the keys were fetched again less than JWKRefreshRateLimit
+// milliseconds ago, so that invalid tokens cannot make every request fetch them.
+func refetchJWKS() (*keyfunc.JWKS, error) {
+	corePaths := supertokens.GetAllCoreUrlsForPath("/.well-known/jwks.json")
+	if len(corePaths) == 0 {
+		return nil, nil
+	}
+	mutex.Lock()
+	defer mutex.Unlock()
+	currentTime := time.Now().UnixNano() / int64(time.Millisecond)
+	if currentTime-lastJWKSRefetch < int64(JWKRefreshRateLimit) {
+		return nil, nil
+	}
+	lastJWKSRefetch = currentTime
+	return fetchJWKS(corePaths)
+}
+
 /*
 *
 This function fetches all JWKs from the first available core instance. This combines the other JWKS functions to become
@@ -148,10 +206,30 @@ func GetCombinedJWKS() (*keyfunc.JWKS, error) {
 
 func MakeRecipeImplementation(querier supertokens.Querier, config sessmodels.TypeNormalisedInput, appInfo supertokens.NormalisedAppinfo) sessmodels.RecipeInterface {
 	var result sessmodels.RecipeInterface
+	verificationCache := newVerificationCache(config.VerificationCache)
 
 	createNewSession := func(userID string, accessTokenPayload map[string]interface{}, sessionDataInDatabase map[string]interface{}, disableAntiCsrf *bool, tenantId string, userContext supertokens.UserContext) (sessmodels.SessionContainer, error) {
 		supertokens.LogDebugMessage("createNewSession: Started")
 
+		accessTokenPayload = addSessionExpiration(config, accessTokenPayload)
+		accessTokenPayload, err := addRefreshTokenBinding(config, accessTokenPayload, userContext)
+		if err != nil {
+			return nil, err
+		}
+		accessTokenPayload, err = addDPoPBinding(config, appInfo, accessTokenPayload, userContext)
+		if err != nil {
+			return nil, err
+		}
+		accessTokenPayload, err = addClientBinding(config, accessTokenPayload, userContext)
+		if err != nil {
+			return nil, err
+		}
+		err = checkAccessTokenPayloadSize(config, accessTokenPayload, userContext)
+		if err != nil {
+			return nil, err
+		}
+		sessionDataInDatabase = addSessionDevice(config, sessionDataInDatabase, userContext)
+
 		sessionResponse, err := createNewSessionHelper(
 			config, querier, userID, disableAntiCsrf != nil && *disableAntiCsrf == true, accessTokenPayload, sessionDataInDatabase, tenantId, userContext,
 		)
@@ -160,13 +238,15 @@ func MakeRecipeImplementation(querier supertokens.Querier, config sessmodels.Typ
 		}
 
 		supertokens.LogDebugMessage("createNewSession: Finished")
+		markUserActive(config, sessionResponse.Session.UserID, userContext)
+		callSessionLifecycleHook(config.OnSessionCreated, sessionResponse.Session.Handle, sessionResponse.Session.UserID, userContext)
 
 		parsedJWT, parseErr := ParseJWTWithoutSignatureVerification(sessionResponse.AccessToken.Token)
 		if parseErr != nil {
 			return nil, parseErr
 		}
 
-		frontToken := BuildFrontToken(sessionResponse.Session.UserID, sessionResponse.AccessToken.Expiry, parsedJWT.Payload)
+		frontToken := buildFrontToken(config, sessionResponse.Session.UserID, sessionResponse.AccessToken.Expiry, parsedJWT.Payload)
 		session := sessionResponse.Session
 		sessionContainerInput := makeSessionContainerInput(sessionResponse.AccessToken.Token, session.Handle, session.UserID, session.TenantId, parsedJWT.Payload, result, frontToken, sessionResponse.AntiCsrfToken, nil, &sessionResponse.RefreshToken, true)
 		return newSessionContainer(config, &sessionContainerInput), nil
@@ -242,12 +322,43 @@ func MakeRecipeImplementation(querier supertokens.Querier, config sessmodels.Typ
 			doAntiCsrfCheck = false
 		}
 
-		response, err := getSessionHelper(config, querier, *accessToken, antiCsrfToken, doAntiCsrfCheck, alwaysCheckCore, userContext)
+		// only verifications that call the core are cached
+		cacheKey := getVerificationCacheKey(*accessTokenString, antiCsrfToken, doAntiCsrfCheck)
+		response, isCached := sessmodels.GetSessionResponse{}, false
+		if alwaysCheckCore {
+			response, isCached = verificationCache.get(cacheKey)
+		}
+		if isCached {
+			supertokens.LogDebugMessage("getSession: Using cached verification result")
+		} else {
+			response, err = getSessionHelper(config, querier, *accessToken, antiCsrfToken, doAntiCsrfCheck, alwaysCheckCore, userContext)
+			if err != nil {
+				return nil, err
+			}
+			if alwaysCheckCore {
+				verificationCache.set(cacheKey, response)
+			}
+		}
+
+		err = checkDPoPBinding(config, appInfo, *accessTokenString, accessToken.Payload, userContext)
+		if err != nil {
+			return nil, err
+		}
+		err = checkClientBinding(config, querier, response.Session.Handle, response.Session.UserID, accessToken.Payload, userContext)
+		if err != nil {
+			return nil, err
+		}
+		err = checkAccessTokenLifetime(accessToken.Payload)
+		if err != nil {
+			return nil, err
+		}
+		err = checkSessionSuspended(config, response.Session.Handle, response.Session.UserID, userContext)
 		if err != nil {
 			return nil, err
 		}
 
 		supertokens.LogDebugMessage("getSession: Success!")
+		markUserActive(config, response.Session.UserID, userContext)
 		var payload map[string]interface{}
 
 		if accessToken.Version >= 3 {
@@ -274,7 +385,7 @@ func MakeRecipeImplementation(querier supertokens.Querier, config sessmodels.Typ
 			accessTokenStringForSession = response.AccessToken.Token
 		}
 
-		frontToken := BuildFrontToken(response.Session.UserID, response.Session.ExpiryTime, payload)
+		frontToken := buildFrontToken(config, response.Session.UserID, response.Session.ExpiryTime, payload)
 		session := response.Session
 
 		sessionContainerInput := makeSessionContainerInput(accessTokenStringForSession, session.Handle, session.UserID, session.TenantId, payload, result, frontToken, antiCsrfToken, nil, nil, !accessTokenNil)
@@ -294,7 +405,9 @@ func MakeRecipeImplementation(querier supertokens.Querier, config sessmodels.Typ
 
 		supertokens.LogDebugMessage("refreshSession: Started")
 
-		response, err := refreshSessionHelper(config, querier, refreshToken, antiCsrfToken, disableAntiCsrf, userContext)
+		response, err := refreshWithDeduplication(config, refreshToken, antiCsrfToken, userContext, func() (sessmodels.CreateOrRefreshAPIResponse, error) {
+			return refreshSessionHelper(config, querier, refreshToken, antiCsrfToken, disableAntiCsrf, userContext)
+		})
 		if err != nil {
 			return nil, err
 		}
@@ -305,8 +418,23 @@ func MakeRecipeImplementation(querier supertokens.Querier, config sessmodels.Typ
 			return nil, err
 		}
 
+		err = checkRefreshTokenBinding(config, querier, response.Session.Handle, responseToken.Payload, userContext)
+		if err != nil {
+			return nil, err
+		}
+		err = checkDPoPBindingOnRefresh(config, appInfo, querier, response.Session.Handle, responseToken.Payload, userContext)
+		if err != nil {
+			return nil, err
+		}
+		err = checkRefreshTokenLifetime(querier, response.Session.Handle, responseToken.Payload, userContext)
+		if err != nil {
+			return nil, err
+		}
+		touchSessionDevice(config, querier, response.Session.Handle, userContext)
+		callSessionLifecycleHook(config.OnSessionRefreshed, response.Session.Handle, response.Session.UserID, userContext)
+
 		session := response.Session
-		frontToken := BuildFrontToken(session.UserID, response.AccessToken.Expiry, responseToken.Payload)
+		frontToken := buildFrontToken(config, session.UserID, response.AccessToken.Expiry, responseToken.Payload)
 
 		sessionContainerInput := makeSessionContainerInput(response.AccessToken.Token, session.Handle, session.UserID, session.TenantId, responseToken.Payload, result, frontToken, response.AntiCsrfToken, nil, &response.RefreshToken, true)
 		sessionContainer := newSessionContainer(config, &sessionContainerInput)
@@ -315,7 +443,15 @@ func MakeRecipeImplementation(querier supertokens.Querier, config sessmodels.Typ
 	}
 
 	revokeAllSessionsForUser := func(userID string, tenantId string, revokeAcrossAllTenants *bool, userContext supertokens.UserContext) ([]string, error) {
-		return revokeAllSessionsForUserHelper(querier, userID, tenantId, revokeAcrossAllTenants, userContext)
+		verificationCache.invalidateUser(userID)
+		revokedSessionHandles, err := revokeAllSessionsForUserHelper(querier, userID, tenantId, revokeAcrossAllTenants, userContext)
+		// verifications running during the revocation may have cached the
+		// sessions again
+		verificationCache.invalidateUser(userID)
+		for _, sessionHandle := range revokedSessionHandles {
+			callSessionLifecycleHook(config.OnSessionRevoked, sessionHandle, userID, userContext)
+		}
+		return revokedSessionHandles, err
 	}
 
 	getAllSessionHandlesForUser := func(userID string, tenantId string, fetchAcrossAllTenants *bool, userContext supertokens.UserContext) ([]string, error) {
@@ -323,11 +459,27 @@ func MakeRecipeImplementation(querier supertokens.Querier, config sessmodels.Typ
 	}
 
 	revokeSession := func(sessionHandle string, userContext supertokens.UserContext) (bool, error) {
-		return revokeSessionHelper(querier, sessionHandle, userContext)
+		verificationCache.invalidateSessions(sessionHandle)
+		userIDs := getUserIDsBeforeRevoking(config, querier, []string{sessionHandle}, userContext)
+		revoked, err := revokeSessionHelper(querier, sessionHandle, userContext)
+		// verifications running during the revocation may have cached the
+		// session again
+		verificationCache.invalidateSessions(sessionHandle)
+		if err == nil && revoked {
+			callOnSessionRevoked(config, []string{sessionHandle}, userIDs, userContext)
+		}
+		return revoked, err
 	}
 
 	revokeMultipleSessions := func(sessionHandles []string, userContext supertokens.UserContext) ([]string, error) {
-		return revokeMultipleSessionsHelper(querier, sessionHandles, userContext)
+		verificationCache.invalidateSessions(sessionHandles...)
+		userIDs := getUserIDsBeforeRevoking(config, querier, sessionHandles, userContext)
+		revokedSessionHandles, err := revokeMultipleSessionsHelper(querier, sessionHandles, userContext)
+		verificationCache.invalidateSessions(sessionHandles...)
+		if err == nil {
+			callOnSessionRevoked(config, revokedSessionHandles, userIDs, userContext)
+		}
+		return revokedSessionHandles, err
 	}
 
 	updateSessionDataInDatabase := func(sessionHandle string, newSessionData map[string]interface{}, userContext supertokens.UserContext) (bool, error) {
@@ -335,7 +487,17 @@ func MakeRecipeImplementation(querier supertokens.Querier, config sessmodels.Typ
 	}
 
 	regenerateAccessToken := func(accessToken string, newAccessTokenPayload *map[string]interface{}, userContext supertokens.UserContext) (*sessmodels.RegenerateAccessTokenResponse, error) {
-		return regenerateAccessTokenHelper(querier, newAccessTokenPayload, accessToken, userContext)
+		if newAccessTokenPayload != nil {
+			err := checkAccessTokenPayloadSize(config, *newAccessTokenPayload, userContext)
+			if err != nil {
+				return nil, err
+			}
+		}
+		response, err := regenerateAccessTokenHelper(querier, newAccessTokenPayload, accessToken, userContext)
+		if response != nil {
+			verificationCache.invalidateSessions(response.Session.Handle)
+		}
+		return response, err
 	}
 
 	mergeIntoAccessTokenPayload := func(sessionHandle string, accessTokenPayloadUpdate map[string]interface{}, userContext supertokens.UserContext) (bool, error) {
@@ -365,7 +527,15 @@ func MakeRecipeImplementation(querier supertokens.Querier, config sessmodels.Typ
 			}
 		}
 
-		return updateAccessTokenPayloadHelper(querier, sessionHandle, newAccessTokenPayload, userContext)
+		err = checkAccessTokenPayloadSize(config, newAccessTokenPayload, userContext)
+		if err != nil {
+			return false, err
+		}
+
+		verificationCache.invalidateSessions(sessionHandle)
+		updated, err := updateAccessTokenPayloadHelper(querier, sessionHandle, newAccessTokenPayload, userContext)
+		verificationCache.invalidateSessions(sessionHandle)
+		return updated, err
 	}
 
 	getGlobalClaimValidators := func(userId string, claimValidatorsAddedByOtherRecipes []claims.SessionClaimValidator, tenantId string, userContext supertokens.UserContext) ([]claims.SessionClaimValidator, error) {
@@ -374,7 +544,16 @@ func MakeRecipeImplementation(querier supertokens.Querier, config sessmodels.Typ
 
 	validateClaims := func(userId string, accessTokenPayload map[string]interface{}, claimValidators []claims.SessionClaimValidator, userContext supertokens.UserContext) (sessmodels.ValidateClaimsResult, error) {
 		accessTokenPayloadUpdate := map[string]interface{}{}
-		origSessionClaimPayloadJSON, err := json.Marshal(accessTokenPayload)
+		origSessionClaimPayloadJSON, err := supertokens.JSONMarshal(accessTokenPayload)
+		if err != nil {
+			return sessmodels.ValidateClaimsResult{}, err
+		}
+
+		claimsAddedByOtherRecipes := []*claims.TypeSessionClaim{}
+		if instance, err := getRecipeInstanceOrThrowError(); err == nil {
+			claimsAddedByOtherRecipes = instance.GetClaimsAddedByOtherRecipes()
+		}
+		accessTokenPayload, err = refreshStaleClaims(userId, accessTokenPayload, getClaimsWithMaxStaleness(claimValidators, claimsAddedByOtherRecipes), userContext)
 		if err != nil {
 			return sessmodels.ValidateClaimsResult{}, err
 		}
@@ -401,7 +580,7 @@ func MakeRecipeImplementation(querier supertokens.Querier, config sessmodels.Typ
 			}
 		}
 
-		newSessionClaimPayloadJSON, err := json.Marshal(accessTokenPayload)
+		newSessionClaimPayloadJSON, err := supertokens.JSONMarshal(accessTokenPayload)
 		if err != nil {
 			return sessmodels.ValidateClaimsResult{}, err
 		}
@@ -0,0 +1,66 @@
+/* Copyright (c) 2023, VRAI Labs and/or its affiliates. All rights reserved.
+ *
+ * This software is licensed under the Apache License, Version 2.0 (the
+ * "License") as published by the Apache Software Foundation.
+ *
+ * You may not use this file except in compliance with the License. You may
+ * obtain a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+ * License for the specific language governing permissions and limitations
+ * under the License.
+ */
+
+package session
+
+import (
+	defaultErrors "errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/supertokens/supertokens-golang/recipe/session/errors"
+	"github.com/supertokens/supertokens-golang/recipe/session/sessmodels"
+	"github.com/supertokens/supertokens-golang/supertokens"
+)
+
+func TestOnTokenTheftIsCalledBeforeTheTokenTheftErrorHandler(t *testing.T) {
+	calls := []string{}
+	var onTokenTheftErr error
+	config := makeCookieTestConfig(t, &sessmodels.TypeInput{
+		OnTokenTheft: func(sessionHandle string, userID string, req *http.Request, userContext supertokens.UserContext) error {
+			calls = append(calls, "OnTokenTheft "+sessionHandle+" "+userID)
+			return onTokenTheftErr
+		},
+		ErrorHandlers: &sessmodels.ErrorHandlers{
+			OnTokenTheftDetected: func(sessionHandle string, userID string, req *http.Request, res http.ResponseWriter) error {
+				calls = append(calls, "OnTokenTheftDetected "+sessionHandle+" "+userID)
+				return nil
+			},
+		},
+	})
+	recipe := Recipe{Config: config}
+	theftErr := errors.TokenTheftDetectedError{
+		Msg: "token theft detected",
+		Payload: errors.TokenTheftDetectedErrorPayload{
+			SessionHandle: "handle1",
+			UserID:        "user1",
+		},
+	}
+
+	req := httptest.NewRequest(http.MethodPost, "/auth/session/refresh", nil)
+	handled, err := recipe.handleError(theftErr, req, httptest.NewRecorder(), &map[string]interface{}{})
+	assert.True(t, handled)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"OnTokenTheft handle1 user1", "OnTokenTheftDetected handle1 user1"}, calls)
+
+	calls = []string{}
+	onTokenTheftErr = defaultErrors.New("alerting failed")
+	handled, err = recipe.handleError(theftErr, req, httptest.NewRecorder(), &map[string]interface{}{})
+	assert.True(t, handled)
+	assert.EqualError(t, err, "alerting failed")
+	assert.Equal(t, []string{"OnTokenTheft handle1 user1", "OnTokenTheftDetected handle1 user1"}, calls)
+}
@@ -0,0 +1,213 @@
+/* Copyright (c) 2023, VRAI Labs and/or its affiliates. All rights reserved.
+ *
+ * This software is licensed under the Apache License, Version 2.0 (the
+ * "License") as published by the Apache Software Foundation.
+ *
+ * You may not use this file except in compliance with the License. You may
+ * obtain a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+ * License for the specific language governing permissions and limitations
+ * under the License.
+ */
+
+package session
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/supertokens/supertokens-golang/recipe/session/sessmodels"
+	"github.com/supertokens/supertokens-golang/supertokens"
+)
+
+const (
+	defaultRefreshDeduplicationGracePeriodInSeconds = 10
+	// refreshLockTTL bounds how long a refresh can hold the lock of its
+	// refresh token, in case the server holding it goes away
+	refreshLockTTL = 10 * time.Second
+)
+
+func normaliseRefreshDeduplicationInput(config *sessmodels.RefreshDeduplicationInput) (*sessmodels.NormalisedRefreshDeduplicationConfig, error) {
+	if config == nil {
+		return nil, nil
+	}
+	result := &sessmodels.NormalisedRefreshDeduplicationConfig{
+		GracePeriod: defaultRefreshDeduplicationGracePeriodInSeconds * time.Second,
+	}
+	if config.GracePeriodInSeconds != nil {
+		if *config.GracePeriodInSeconds == 0 {
+			return nil, errors.New("RefreshDeduplication.GracePeriodInSeconds must be positive")
+		}
+		result.GracePeriod = time.Duration(*config.GracePeriodInSeconds) * time.Second
+	}
+	if config.Store != nil {
+		if config.Store.Lock == nil || config.Store.GetResult == nil || config.Store.SetResult == nil {
+			return nil, errors.New("refresh deduplication store must implement Lock, GetResult and SetResult")
+		}
+		result.Store = *config.Store
+	} else {
+		result.Store = MakeInMemoryRefreshDeduplicationStore()
+	}
+	if config.ReportReuse != nil {
+		result.ReportReuse = *config.ReportReuse
+	}
+	return result, nil
+}
+
+func getRefreshDeduplicationKey(refreshToken string, antiCsrfToken *string) string {
+	hash := sha256.New()
+	hash.Write([]byte(refreshToken))
+	if antiCsrfToken != nil {
+		hash.Write([]byte{0})
+		hash.Write([]byte(*antiCsrfToken))
+	}
+	return base64.RawURLEncoding.EncodeToString(hash.Sum(nil))
+}
+
+// refreshWithDeduplication calls refresh at most once per refresh token (and
+// anti-csrf token) within the grace period, and returns its result to the
+// other callers. Failures of the store are logged, and the refresh then goes
+// to the core as if deduplication was disabled.
+func refreshWithDeduplication(config sessmodels.TypeNormalisedInput, refreshToken string, antiCsrfToken *string, userContext supertokens.UserContext, refresh func() (sessmodels.CreateOrRefreshAPIResponse, error)) (sessmodels.CreateOrRefreshAPIResponse, error) {
+	if config.RefreshDeduplication == nil {
+		return refresh()
+	}
+	store := config.RefreshDeduplication.Store
+	key := getRefreshDeduplicationKey(refreshToken, antiCsrfToken)
+	clientIP, userAgent := getRefreshClient(userContext)
+	// requests waiting for the lock are parallel refreshes, which are not
+	// reported as reuse if they come from the same client
+	arrivedAt := time.Now()
+
+	unlock, err := (*store.Lock)(key, refreshLockTTL, userContext)
+	if err != nil {
+		supertokens.LogDebugMessage("refreshWithDeduplication: Failed to lock the refresh token: " + err.Error())
+		return refresh()
+	}
+	defer unlock()
+
+	result, err := (*store.GetResult)(key, userContext)
+	if err != nil {
+		supertokens.LogDebugMessage("refreshWithDeduplication: Failed to get the result of a previous refresh: " + err.Error())
+	} else if result != nil {
+		supertokens.LogDebugMessage("refreshWithDeduplication: Returning the result of a previous refresh")
+		if arrivedAt.After(result.RefreshedAt) || clientIP != result.ClientIP || userAgent != result.UserAgent {
+			reportRefreshTokenReuse(config, result.Response, userContext)
+		}
+		return result.Response, nil
+	}
+
+	response, err := refresh()
+	if err != nil {
+		return sessmodels.CreateOrRefreshAPIResponse{}, err
+	}
+	refreshedAt := time.Now()
+	err = (*store.SetResult)(key, sessmodels.RefreshDeduplicationResult{
+		Response:    response,
+		RefreshedAt: refreshedAt,
+		ClientIP:    clientIP,
+		UserAgent:   userAgent,
+	}, refreshedAt.Add(config.RefreshDeduplication.GracePeriod), userContext)
+	if err != nil {
+		supertokens.LogDebugMessage("refreshWithDeduplication: Failed to store the result of the refresh: " + err.Error())
+	}
+	return response, nil
+}
+
+func getRefreshClient(userContext supertokens.UserContext) (string, string) {
+	req := supertokens.GetRequestFromUserContext(userContext)
+	if req == nil {
+		return "", ""
+	}
+	return supertokens.GetClientIP(req, userContext), req.UserAgent()
+}
+
+func reportRefreshTokenReuse(config sessmodels.TypeNormalisedInput, result sessmodels.CreateOrRefreshAPIResponse, userContext supertokens.UserContext) {
+	if !config.RefreshDeduplication.ReportReuse || config.OnTokenTheft == nil {
+		return
+	}
+	err := config.OnTokenTheft(result.Session.Handle, result.Session.UserID, supertokens.GetRequestFromUserContext(userContext), userContext)
+	if err != nil {
+		supertokens.LogDebugMessage("refreshWithDeduplication: OnTokenTheft returned an error for a reused refresh token: " + err.Error())
+	}
+}
+
+// MakeInMemoryRefreshDeduplicationStore returns a refresh deduplication
+// store that keeps locks and results in process memory. Its locks are held
+// until released, since they cannot outlive the process holding them.
+func MakeInMemoryRefreshDeduplicationStore() sessmodels.RefreshDeduplicationStore {
+	type refreshResult struct {
+		result    sessmodels.RefreshDeduplicationResult
+		expiresAt time.Time
+	}
+	type refreshLock struct {
+		mutex   sync.Mutex
+		waiting int
+	}
+
+	var mutex sync.Mutex
+	locks := map[string]*refreshLock{}
+	results := map[string]refreshResult{}
+	nextCleanup := time.Now()
+
+	lock := func(key string, ttl time.Duration, userContext supertokens.UserContext) (func(), error) {
+		mutex.Lock()
+		keyLock, ok := locks[key]
+		if !ok {
+			keyLock = &refreshLock{}
+			locks[key] = keyLock
+		}
+		keyLock.waiting++
+		mutex.Unlock()
+
+		keyLock.mutex.Lock()
+		return func() {
+			keyLock.mutex.Unlock()
+			mutex.Lock()
+			defer mutex.Unlock()
+			keyLock.waiting--
+			if keyLock.waiting == 0 {
+				delete(locks, key)
+			}
+		}, nil
+	}
+
+	getResult := func(key string, userContext supertokens.UserContext) (*sessmodels.RefreshDeduplicationResult, error) {
+		mutex.Lock()
+		defer mutex.Unlock()
+		result, ok := results[key]
+		if !ok || !time.Now().Before(result.expiresAt) {
+			return nil, nil
+		}
+		return &result.result, nil
+	}
+
+	setResult := func(key string, result sessmodels.RefreshDeduplicationResult, expiresAt time.Time, userContext supertokens.UserContext) error {
+		mutex.Lock()
+		defer mutex.Unlock()
+
+		now := time.Now()
+		if !now.Before(nextCleanup) {
+			for k, r := range results {
+				if !now.Before(r.expiresAt) {
+					delete(results, k)
+				}
+			}
+			nextCleanup = expiresAt
+		}
+		results[key] = refreshResult{result: result, expiresAt: expiresAt}
+		return nil
+	}
+
+	return sessmodels.RefreshDeduplicationStore{
+		Lock:      &lock,
+		GetResult: &getResult,
+		SetResult: &setResult,
+	}
+}
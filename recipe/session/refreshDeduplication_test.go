@@ -0,0 +1,188 @@
+/* Copyright (c) 2023, VRAI Labs and/or its affiliates. All rights reserved.
+ *
+ * This software is licensed under the Apache License, Version 2.0 (the
+ * "License") as published by the Apache Software Foundation.
+ *
+ * You may not use this file except in compliance with the License. You may
+ * obtain a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+ * License for the specific language governing permissions and limitations
+ * under the License.
+ */
+
+package session
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/supertokens/supertokens-golang/recipe/session/sessmodels"
+	"github.com/supertokens/supertokens-golang/supertokens"
+)
+
+func TestParallelRefreshesWithTheSameTokenGetTheSameResult(t *testing.T) {
+	refreshDeduplication, err := normaliseRefreshDeduplicationInput(&sessmodels.RefreshDeduplicationInput{})
+	assert.NoError(t, err)
+	config := sessmodels.TypeNormalisedInput{RefreshDeduplication: refreshDeduplication}
+
+	var calls int32
+	refresh := func() (sessmodels.CreateOrRefreshAPIResponse, error) {
+		call := atomic.AddInt32(&calls, 1)
+		time.Sleep(10 * time.Millisecond)
+		return sessmodels.CreateOrRefreshAPIResponse{
+			RefreshToken: sessmodels.CreateOrRefreshAPIResponseToken{Token: "refresh" + strconv.Itoa(int(call))},
+		}, nil
+	}
+
+	var wg sync.WaitGroup
+	results := make([]sessmodels.CreateOrRefreshAPIResponse, 10)
+	for i := range results {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			result, err := refreshWithDeduplication(config, "refresh0", nil, &map[string]interface{}{}, refresh)
+			assert.NoError(t, err)
+			results[i] = result
+		}(i)
+	}
+	wg.Wait()
+
+	assert.Equal(t, int32(1), calls)
+	for _, result := range results {
+		assert.Equal(t, "refresh1", result.RefreshToken.Token)
+	}
+
+	// other refresh tokens and anti-csrf tokens are refreshed separately
+	antiCsrfToken := "csrf"
+	result, err := refreshWithDeduplication(config, "refresh0", &antiCsrfToken, &map[string]interface{}{}, refresh)
+	assert.NoError(t, err)
+	assert.Equal(t, "refresh2", result.RefreshToken.Token)
+	result, err = refreshWithDeduplication(config, "other", nil, &map[string]interface{}{}, refresh)
+	assert.NoError(t, err)
+	assert.Equal(t, "refresh3", result.RefreshToken.Token)
+}
+
+func TestFailedRefreshesAreNotDeduplicated(t *testing.T) {
+	refreshDeduplication, err := normaliseRefreshDeduplicationInput(&sessmodels.RefreshDeduplicationInput{})
+	assert.NoError(t, err)
+	config := sessmodels.TypeNormalisedInput{RefreshDeduplication: refreshDeduplication}
+
+	calls := 0
+	refresh := func() (sessmodels.CreateOrRefreshAPIResponse, error) {
+		calls++
+		return sessmodels.CreateOrRefreshAPIResponse{}, errors.New("core unavailable")
+	}
+	_, err = refreshWithDeduplication(config, "refresh0", nil, &map[string]interface{}{}, refresh)
+	assert.EqualError(t, err, "core unavailable")
+	_, err = refreshWithDeduplication(config, "refresh0", nil, &map[string]interface{}{}, refresh)
+	assert.EqualError(t, err, "core unavailable")
+	assert.Equal(t, 2, calls)
+}
+
+func TestRefreshDeduplicationFallsBackWhenTheStoreFails(t *testing.T) {
+	lock := func(key string, ttl time.Duration, userContext supertokens.UserContext) (func(), error) {
+		return nil, errors.New("redis unavailable")
+	}
+	getResult := func(key string, userContext supertokens.UserContext) (*sessmodels.RefreshDeduplicationResult, error) {
+		return nil, nil
+	}
+	setResult := func(key string, result sessmodels.RefreshDeduplicationResult, expiresAt time.Time, userContext supertokens.UserContext) error {
+		return nil
+	}
+	refreshDeduplication, err := normaliseRefreshDeduplicationInput(&sessmodels.RefreshDeduplicationInput{
+		Store: &sessmodels.RefreshDeduplicationStore{Lock: &lock, GetResult: &getResult, SetResult: &setResult},
+	})
+	assert.NoError(t, err)
+	config := sessmodels.TypeNormalisedInput{RefreshDeduplication: refreshDeduplication}
+
+	result, err := refreshWithDeduplication(config, "refresh0", nil, &map[string]interface{}{}, func() (sessmodels.CreateOrRefreshAPIResponse, error) {
+		return sessmodels.CreateOrRefreshAPIResponse{RefreshToken: sessmodels.CreateOrRefreshAPIResponseToken{Token: "refresh1"}}, nil
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, "refresh1", result.RefreshToken.Token)
+}
+
+func TestRefreshTokenReuseIsReportedToOnTokenTheft(t *testing.T) {
+	True := true
+	refreshDeduplication, err := normaliseRefreshDeduplicationInput(&sessmodels.RefreshDeduplicationInput{ReportReuse: &True})
+	assert.NoError(t, err)
+	var mutex sync.Mutex
+	reports := []string{}
+	config := sessmodels.TypeNormalisedInput{
+		RefreshDeduplication: refreshDeduplication,
+		OnTokenTheft: func(sessionHandle string, userID string, req *http.Request, userContext supertokens.UserContext) error {
+			mutex.Lock()
+			defer mutex.Unlock()
+			reports = append(reports, sessionHandle+" "+userID)
+			return nil
+		},
+	}
+	refresh := func() (sessmodels.CreateOrRefreshAPIResponse, error) {
+		time.Sleep(50 * time.Millisecond)
+		return sessmodels.CreateOrRefreshAPIResponse{
+			Session: sessmodels.SessionStruct{Handle: "handle1", UserID: "user1"},
+		}, nil
+	}
+	makeUserContext := func(remoteAddr string, userAgent string) supertokens.UserContext {
+		req := httptest.NewRequest(http.MethodPost, "/auth/session/refresh", nil)
+		req.RemoteAddr = remoteAddr
+		req.Header.Set("User-Agent", userAgent)
+		return supertokens.MakeDefaultUserContextFromAPI(req)
+	}
+
+	// parallel refreshes of several tabs of one browser are not reported
+	var wg sync.WaitGroup
+	for i := 0; i < 5; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := refreshWithDeduplication(config, "refresh0", nil, makeUserContext("203.0.113.1:1234", "browser"), refresh)
+			assert.NoError(t, err)
+		}()
+	}
+	wg.Wait()
+	assert.Empty(t, reports)
+
+	// reuse after the refresh has completed is reported
+	_, err = refreshWithDeduplication(config, "refresh0", nil, makeUserContext("203.0.113.1:1234", "browser"), refresh)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"handle1 user1"}, reports)
+
+	config.RefreshDeduplication.ReportReuse = false
+	_, err = refreshWithDeduplication(config, "refresh0", nil, makeUserContext("203.0.113.1:1234", "browser"), refresh)
+	assert.NoError(t, err)
+	assert.Len(t, reports, 1)
+	config.RefreshDeduplication.ReportReuse = true
+
+	// parallel refreshes from other clients are reported
+	reports = []string{}
+	wg.Add(2)
+	for _, userAgent := range []string{"browser", "other"} {
+		go func(userAgent string) {
+			defer wg.Done()
+			_, err := refreshWithDeduplication(config, "refresh1", nil, makeUserContext("203.0.113.1:1234", userAgent), refresh)
+			assert.NoError(t, err)
+		}(userAgent)
+	}
+	wg.Wait()
+	assert.Equal(t, []string{"handle1 user1"}, reports)
+}
+
+func TestRefreshDeduplicationConfigValidation(t *testing.T) {
+	gracePeriod := uint64(0)
+	_, err := normaliseRefreshDeduplicationInput(&sessmodels.RefreshDeduplicationInput{GracePeriodInSeconds: &gracePeriod})
+	assert.EqualError(t, err, "RefreshDeduplication.GracePeriodInSeconds must be positive")
+
+	_, err = normaliseRefreshDeduplicationInput(&sessmodels.RefreshDeduplicationInput{Store: &sessmodels.RefreshDeduplicationStore{}})
+	assert.EqualError(t, err, "refresh deduplication store must implement Lock, GetResult and SetResult")
+}
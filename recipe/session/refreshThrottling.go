@@ -0,0 +1,119 @@
+/* Copyright (c) 2023, VRAI Labs and/or its affiliates. All rights reserved.
+ *
+ * This software is licensed under the Apache License, Version 2.0 (the
+ * "License") as published by the Apache Software Foundation.
+ *
+ * You may not use this file except in compliance with the License. You may
+ * obtain a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+ * License for the specific language governing permissions and limitations
+ * under the License.
+ */
+
+package session
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/supertokens/supertokens-golang/recipe/session/sessmodels"
+	"github.com/supertokens/supertokens-golang/supertokens"
+)
+
+const (
+	defaultMaxRefreshesPerIP       = 60
+	defaultMaxRefreshesPerSession  = 10
+	defaultRefreshThrottlingWindow = time.Minute
+)
+
+func normaliseRefreshThrottlingInput(config *sessmodels.RefreshThrottlingInput) (*sessmodels.NormalisedRefreshThrottlingConfig, error) {
+	if config == nil {
+		return nil, nil
+	}
+	result := &sessmodels.NormalisedRefreshThrottlingConfig{
+		MaxRefreshesPerIP:      defaultMaxRefreshesPerIP,
+		MaxRefreshesPerSession: defaultMaxRefreshesPerSession,
+		Window:                 defaultRefreshThrottlingWindow,
+	}
+	if config.MaxRefreshesPerIP != nil {
+		result.MaxRefreshesPerIP = *config.MaxRefreshesPerIP
+	}
+	if config.MaxRefreshesPerSession != nil {
+		result.MaxRefreshesPerSession = *config.MaxRefreshesPerSession
+	}
+	if config.Window != 0 {
+		result.Window = config.Window
+	}
+	if config.Store != nil {
+		if config.Store.Increment == nil {
+			return nil, errors.New("refresh throttling store must implement Increment")
+		}
+		result.Store = *config.Store
+	} else {
+		result.Store = supertokens.MakeInMemoryRateLimiterStore()
+	}
+	return result, nil
+}
+
+// refreshThrottledError is returned by RefreshSessionInRequest when a
+// refresh limit is reached. It is sent to the frontend as a 429 response.
+type refreshThrottledError struct {
+	retryAfter time.Duration
+}
+
+func (err refreshThrottledError) Error() string {
+	return "Too many refresh requests"
+}
+
+func sendRefreshThrottledResponse(err refreshThrottledError, res http.ResponseWriter) error {
+	retryAfter := int(err.retryAfter.Seconds())
+	if retryAfter < 1 {
+		retryAfter = 1
+	}
+	res.Header().Set("Retry-After", strconv.Itoa(retryAfter))
+	return supertokens.SendNon200ResponseWithMessage(res, err.Error(), supertokens.RateLimitStatusCode)
+}
+
+func checkRefreshLimit(config sessmodels.TypeNormalisedInput, key string, maxRefreshes uint64, userContext supertokens.UserContext) error {
+	if maxRefreshes == 0 {
+		return nil
+	}
+	result, err := (*config.RefreshThrottling.Store.Increment)(key, config.RefreshThrottling.Window, userContext)
+	if err != nil {
+		return err
+	}
+	if result.Hits > maxRefreshes {
+		supertokens.LogDebugMessage("refreshSession: Throttling refresh for key: " + key)
+		return refreshThrottledError{retryAfter: result.ResetAfter}
+	}
+	return nil
+}
+
+// checkRefreshIPLimit is called before a refresh token is sent to the core,
+// so that guessing refresh tokens from one IP is throttled
+func checkRefreshIPLimit(config sessmodels.TypeNormalisedInput, req *http.Request, userContext supertokens.UserContext) error {
+	if config.RefreshThrottling == nil {
+		return nil
+	}
+	return checkRefreshLimit(config, "session-refresh:ip:"+supertokens.GetClientIP(req, userContext), config.RefreshThrottling.MaxRefreshesPerIP, userContext)
+}
+
+// checkRefreshSessionLimit is also called before the refresh token is sent
+// to the core. The session of a refresh token is only known once the core has
+// checked it, so the limit is kept per refresh token: a client gets a new one
+// on each refresh, so only clients that keep refreshing with the same token
+// are throttled, and they can still use it once the window has passed.
+func checkRefreshSessionLimit(config sessmodels.TypeNormalisedInput, refreshToken string, userContext supertokens.UserContext) error {
+	if config.RefreshThrottling == nil {
+		return nil
+	}
+	hash := sha256.Sum256([]byte(refreshToken))
+	return checkRefreshLimit(config, "session-refresh:token:"+base64.RawURLEncoding.EncodeToString(hash[:]), config.RefreshThrottling.MaxRefreshesPerSession, userContext)
+}
@@ -0,0 +1,137 @@
+/* Copyright (c) 2023, VRAI Labs and/or its affiliates. All rights reserved.
+ *
+ * This software is licensed under the Apache License, Version 2.0 (the
+ * "License") as published by the Apache Software Foundation.
+ *
+ * You may not use this file except in compliance with the License. You may
+ * obtain a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+ * License for the specific language governing permissions and limitations
+ * under the License.
+ */
+
+package session
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/supertokens/supertokens-golang/recipe/session/sessmodels"
+	"github.com/supertokens/supertokens-golang/supertokens"
+)
+
+func TestRefreshThrottlingDefaults(t *testing.T) {
+	config, err := normaliseRefreshThrottlingInput(nil)
+	assert.NoError(t, err)
+	assert.Nil(t, config)
+
+	config, err = normaliseRefreshThrottlingInput(&sessmodels.RefreshThrottlingInput{})
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(60), config.MaxRefreshesPerIP)
+	assert.Equal(t, uint64(10), config.MaxRefreshesPerSession)
+	assert.Equal(t, time.Minute, config.Window)
+	assert.NotNil(t, config.Store.Increment)
+
+	_, err = normaliseRefreshThrottlingInput(&sessmodels.RefreshThrottlingInput{Store: &supertokens.RateLimiterStore{}})
+	assert.Error(t, err)
+}
+
+func TestRefreshesAreThrottledPerIPAndPerSession(t *testing.T) {
+	maxRefreshesPerIP := uint64(2)
+	maxRefreshesPerSession := uint64(1)
+	refreshThrottling, err := normaliseRefreshThrottlingInput(&sessmodels.RefreshThrottlingInput{
+		MaxRefreshesPerIP:      &maxRefreshesPerIP,
+		MaxRefreshesPerSession: &maxRefreshesPerSession,
+	})
+	assert.NoError(t, err)
+	config := sessmodels.TypeNormalisedInput{RefreshThrottling: refreshThrottling}
+
+	makeRequest := func(remoteAddr string) *http.Request {
+		req := httptest.NewRequest(http.MethodPost, "/auth/session/refresh", nil)
+		req.RemoteAddr = remoteAddr
+		return req
+	}
+	userContext := &map[string]interface{}{}
+
+	assert.NoError(t, checkRefreshIPLimit(config, makeRequest("203.0.113.1:1234"), userContext))
+	assert.NoError(t, checkRefreshIPLimit(config, makeRequest("203.0.113.1:1234"), userContext))
+	err = checkRefreshIPLimit(config, makeRequest("203.0.113.1:1234"), userContext)
+	assert.True(t, errors.As(err, &refreshThrottledError{}))
+	assert.NoError(t, checkRefreshIPLimit(config, makeRequest("203.0.113.2:1234"), userContext))
+
+	assert.NoError(t, checkRefreshSessionLimit(config, "refreshToken1", userContext))
+	err = checkRefreshSessionLimit(config, "refreshToken1", userContext)
+	assert.True(t, errors.As(err, &refreshThrottledError{}))
+	assert.NoError(t, checkRefreshSessionLimit(config, "refreshToken2", userContext))
+
+	res := httptest.NewRecorder()
+	assert.NoError(t, sendRefreshThrottledResponse(err.(refreshThrottledError), res))
+	assert.Equal(t, http.StatusTooManyRequests, res.Code)
+	assert.NotEmpty(t, res.Header().Get("Retry-After"))
+}
+
+func TestRefreshThrottlingIsDisabledByDefault(t *testing.T) {
+	config := sessmodels.TypeNormalisedInput{}
+	for i := 0; i < 100; i++ {
+		assert.NoError(t, checkRefreshIPLimit(config, httptest.NewRequest(http.MethodPost, "/auth/session/refresh", nil), &map[string]interface{}{}))
+		assert.NoError(t, checkRefreshSessionLimit(config, "refreshToken1", &map[string]interface{}{}))
+	}
+}
+
+func TestThrottledRefreshDoesNotReachTheCore(t *testing.T) {
+	maxRefreshesPerSession := uint64(1)
+	refreshThrottling, err := normaliseRefreshThrottlingInput(&sessmodels.RefreshThrottlingInput{
+		MaxRefreshesPerSession: &maxRefreshesPerSession,
+	})
+	assert.NoError(t, err)
+	config := sessmodels.TypeNormalisedInput{
+		RefreshThrottling:        refreshThrottling,
+		AntiCsrfFunctionOrString: sessmodels.AntiCsrfFunctionOrString{StrValue: AntiCSRF_NONE},
+		GetTokenTransferMethod: func(req *http.Request, forCreateNewSession bool, userContext supertokens.UserContext) sessmodels.TokenTransferMethod {
+			return sessmodels.AnyTransferMethod
+		},
+	}
+
+	coreErr := errors.New("refreshed at the core")
+	refreshedTokens := []string{}
+	revokeCalls := 0
+	refreshSession := func(refreshToken string, antiCsrfToken *string, disableAntiCsrf bool, userContext supertokens.UserContext) (sessmodels.SessionContainer, error) {
+		refreshedTokens = append(refreshedTokens, refreshToken)
+		return nil, coreErr
+	}
+	revokeSession := func(sessionHandle string, userContext supertokens.UserContext) (bool, error) {
+		revokeCalls++
+		return true, nil
+	}
+	recipeImpl := sessmodels.RecipeInterface{RefreshSession: &refreshSession, RevokeSession: &revokeSession}
+
+	refresh := func(refreshToken string) (*httptest.ResponseRecorder, error) {
+		req := httptest.NewRequest(http.MethodPost, "/auth/session/refresh", nil)
+		req.Header.Set("Authorization", "Bearer "+refreshToken)
+		res := httptest.NewRecorder()
+		_, err := RefreshSessionInRequest(req, res, config, recipeImpl, &map[string]interface{}{})
+		return res, err
+	}
+
+	_, err = refresh("refreshToken1")
+	assert.Equal(t, coreErr, err)
+
+	res, err := refresh("refreshToken1")
+	assert.True(t, errors.As(err, &refreshThrottledError{}))
+	assert.Equal(t, []string{"refreshToken1"}, refreshedTokens)
+	assert.Equal(t, 0, revokeCalls)
+	assert.Empty(t, res.Header().Values("Set-Cookie"))
+	assert.Empty(t, res.Header().Get("front-token"))
+
+	// the new refresh token of the session is not throttled
+	_, err = refresh("refreshToken2")
+	assert.Equal(t, coreErr, err)
+	assert.Equal(t, []string{"refreshToken1", "refreshToken2"}, refreshedTokens)
+}
@@ -0,0 +1,115 @@
+/* Copyright (c) 2023, VRAI Labs and/or its affiliates. All rights reserved.
+ *
+ * This software is licensed under the Apache License, Version 2.0 (the
+ * "License") as published by the Apache Software Foundation.
+ *
+ * You may not use this file except in compliance with the License. You may
+ * obtain a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+ * License for the specific language governing permissions and limitations
+ * under the License.
+ */
+
+package session
+
+import (
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"net/http"
+
+	sessionErrors "github.com/supertokens/supertokens-golang/recipe/session/errors"
+	"github.com/supertokens/supertokens-golang/recipe/session/sessmodels"
+	"github.com/supertokens/supertokens-golang/supertokens"
+)
+
+// refreshTokenBindingKey is the access token payload key holding the hash of
+// the binding material of the client that created the session
+const refreshTokenBindingKey = "st-rtb"
+
+func normaliseRefreshTokenBindingInput(config *sessmodels.RefreshTokenBindingInput) *sessmodels.NormalisedRefreshTokenBindingConfig {
+	if config == nil {
+		return nil
+	}
+	result := &sessmodels.NormalisedRefreshTokenBindingConfig{
+		GetBindingMaterial: GetClientCertificateFingerprint,
+	}
+	if config.GetBindingMaterial != nil {
+		result.GetBindingMaterial = config.GetBindingMaterial
+	}
+	return result
+}
+
+// GetClientCertificateFingerprint returns the hex encoded SHA-256 fingerprint
+// of the client certificate of the request's TLS connection, or an empty
+// string if the client did not send one
+func GetClientCertificateFingerprint(req *http.Request, userContext supertokens.UserContext) (string, error) {
+	if req == nil || req.TLS == nil || len(req.TLS.PeerCertificates) == 0 {
+		return "", nil
+	}
+	fingerprint := sha256.Sum256(req.TLS.PeerCertificates[0].Raw)
+	return hex.EncodeToString(fingerprint[:]), nil
+}
+
+func getRefreshTokenBindingHash(config sessmodels.TypeNormalisedInput, userContext supertokens.UserContext) (string, error) {
+	req := supertokens.GetRequestFromUserContext(userContext)
+	if req == nil {
+		return "", nil
+	}
+	material, err := config.RefreshTokenBinding.GetBindingMaterial(req, userContext)
+	if err != nil || material == "" {
+		return "", err
+	}
+	hash := sha256.Sum256([]byte(material))
+	return base64.RawURLEncoding.EncodeToString(hash[:]), nil
+}
+
+// addRefreshTokenBinding returns a copy of the access token payload of a new
+// session with the hash of the client's binding material
+func addRefreshTokenBinding(config sessmodels.TypeNormalisedInput, accessTokenPayload map[string]interface{}, userContext supertokens.UserContext) (map[string]interface{}, error) {
+	if config.RefreshTokenBinding == nil {
+		return accessTokenPayload, nil
+	}
+	hash, err := getRefreshTokenBindingHash(config, userContext)
+	if err != nil {
+		return nil, err
+	}
+	if hash == "" {
+		return nil, errors.New("refresh token binding is enabled but the client of the request could not be identified")
+	}
+	result := map[string]interface{}{}
+	for k, v := range accessTokenPayload {
+		result[k] = v
+	}
+	result[refreshTokenBindingKey] = hash
+	return result, nil
+}
+
+// checkRefreshTokenBinding is called after a session is refreshed. If the
+// refresh comes from another client than the one that created the session,
+// the session is revoked since its refresh token was probably stolen.
+func checkRefreshTokenBinding(config sessmodels.TypeNormalisedInput, querier supertokens.Querier, sessionHandle string, accessTokenPayload map[string]interface{}, userContext supertokens.UserContext) error {
+	if config.RefreshTokenBinding == nil {
+		return nil
+	}
+	expected, _ := accessTokenPayload[refreshTokenBindingKey].(string)
+	hash, err := getRefreshTokenBindingHash(config, userContext)
+	if err != nil {
+		return err
+	}
+	if expected != "" && subtle.ConstantTimeCompare([]byte(expected), []byte(hash)) == 1 {
+		return nil
+	}
+	supertokens.LogDebugMessage("refreshSession: Revoking session because the refresh token is bound to another client")
+	_, err = revokeSessionHelper(querier, sessionHandle, userContext)
+	if err != nil {
+		return err
+	}
+	clearTokens := true
+	return sessionErrors.UnauthorizedError{Msg: "refresh token is bound to another client", ClearTokens: &clearTokens}
+}
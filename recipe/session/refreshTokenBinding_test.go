@@ -0,0 +1,129 @@
+/* Copyright (c) 2023, VRAI Labs and/or its affiliates. All rights reserved.
+ *
+ * This software is licensed under the Apache License, Version 2.0 (the
+ * "License") as published by the Apache Software Foundation.
+ *
+ * You may not use this file except in compliance with the License. You may
+ * obtain a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+ * License for the specific language governing permissions and limitations
+ * under the License.
+ */
+
+package session
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	sessionErrors "github.com/supertokens/supertokens-golang/recipe/session/errors"
+	"github.com/supertokens/supertokens-golang/recipe/session/sessmodels"
+	"github.com/supertokens/supertokens-golang/supertokens"
+)
+
+func userContextWithClientCertificate(certificate string) supertokens.UserContext {
+	req := httptest.NewRequest(http.MethodPost, "/auth/session/refresh", nil)
+	if certificate != "" {
+		req.TLS = &tls.ConnectionState{PeerCertificates: []*x509.Certificate{{Raw: []byte(certificate)}}}
+	}
+	return supertokens.MakeDefaultUserContextFromAPI(req)
+}
+
+func TestRefreshTokenBindingIsDisabledByDefault(t *testing.T) {
+	config := sessmodels.TypeNormalisedInput{RefreshTokenBinding: normaliseRefreshTokenBindingInput(nil)}
+	assert.Nil(t, config.RefreshTokenBinding)
+
+	payload, err := addRefreshTokenBinding(config, map[string]interface{}{"key": "value"}, &map[string]interface{}{})
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{"key": "value"}, payload)
+}
+
+func TestNewSessionsAreBoundToTheClientCertificate(t *testing.T) {
+	config := sessmodels.TypeNormalisedInput{
+		RefreshTokenBinding: normaliseRefreshTokenBindingInput(&sessmodels.RefreshTokenBindingInput{}),
+	}
+
+	payload, err := addRefreshTokenBinding(config, map[string]interface{}{"key": "value"}, userContextWithClientCertificate("cert-a"))
+	assert.NoError(t, err)
+	assert.Equal(t, "value", payload["key"])
+	assert.NotEmpty(t, payload[refreshTokenBindingKey])
+
+	otherPayload, err := addRefreshTokenBinding(config, nil, userContextWithClientCertificate("cert-b"))
+	assert.NoError(t, err)
+	assert.NotEqual(t, payload[refreshTokenBindingKey], otherPayload[refreshTokenBindingKey])
+
+	_, err = addRefreshTokenBinding(config, nil, userContextWithClientCertificate(""))
+	assert.Error(t, err)
+	_, err = addRefreshTokenBinding(config, nil, &map[string]interface{}{})
+	assert.Error(t, err)
+}
+
+func TestRefreshFromAnotherClientRevokesTheSession(t *testing.T) {
+	revoked := []interface{}{}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/apiversion", func(rw http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(rw).Encode(map[string]interface{}{"versions": []string{"3.0"}})
+	})
+	mux.HandleFunc("/recipe/session/remove", func(rw http.ResponseWriter, r *http.Request) {
+		var body map[string]interface{}
+		json.NewDecoder(r.Body).Decode(&body)
+		revoked = append(revoked, body["sessionHandles"].([]interface{})...)
+		json.NewEncoder(rw).Encode(map[string]interface{}{"status": "OK", "sessionHandlesRevoked": body["sessionHandles"]})
+	})
+	testServer := httptest.NewServer(mux)
+	defer testServer.Close()
+
+	resetAll()
+	defer resetAll()
+	err := supertokens.Init(supertokens.TypeInput{
+		Supertokens: &supertokens.ConnectionInfo{
+			ConnectionURI: testServer.URL,
+		},
+		AppInfo: supertokens.AppInfo{
+			AppName:       "SuperTokens",
+			WebsiteDomain: "supertokens.io",
+			APIDomain:     "api.supertokens.io",
+		},
+		RecipeList: []supertokens.Recipe{Init(nil)},
+	})
+	assert.NoError(t, err)
+	querier, err := supertokens.GetNewQuerierInstanceOrThrowError("session")
+	assert.NoError(t, err)
+
+	config := sessmodels.TypeNormalisedInput{
+		RefreshTokenBinding: normaliseRefreshTokenBindingInput(&sessmodels.RefreshTokenBindingInput{
+			GetBindingMaterial: func(req *http.Request, userContext supertokens.UserContext) (string, error) {
+				return req.Header.Get("x-device-id"), nil
+			},
+		}),
+	}
+	makeUserContext := func(deviceID string) supertokens.UserContext {
+		req := httptest.NewRequest(http.MethodPost, "/auth/session/refresh", nil)
+		req.Header.Set("x-device-id", deviceID)
+		return supertokens.MakeDefaultUserContextFromAPI(req)
+	}
+	payload, err := addRefreshTokenBinding(config, nil, makeUserContext("device1"))
+	assert.NoError(t, err)
+
+	err = checkRefreshTokenBinding(config, *querier, "handle1", payload, makeUserContext("device1"))
+	assert.NoError(t, err)
+	assert.Empty(t, revoked)
+
+	err = checkRefreshTokenBinding(config, *querier, "handle1", payload, makeUserContext("device2"))
+	assert.IsType(t, sessionErrors.UnauthorizedError{}, err)
+	assert.True(t, *err.(sessionErrors.UnauthorizedError).ClearTokens)
+	assert.Equal(t, []interface{}{"handle1"}, revoked)
+
+	// sessions created before binding was enabled cannot be refreshed
+	err = checkRefreshTokenBinding(config, *querier, "handle2", map[string]interface{}{}, makeUserContext("device1"))
+	assert.Error(t, err)
+	assert.Equal(t, []interface{}{"handle1", "handle2"}, revoked)
+}
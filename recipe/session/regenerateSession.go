@@ -0,0 +1,55 @@
+/* Copyright (c) 2023, VRAI Labs and/or its affiliates. All rights reserved.
+ *
+ * This software is licensed under the Apache License, Version 2.0 (the
+ * "License") as published by the Apache Software Foundation.
+ *
+ * You may not use this file except in compliance with the License. You may
+ * obtain a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+ * License for the specific language governing permissions and limitations
+ * under the License.
+ */
+
+package session
+
+import (
+	"github.com/supertokens/supertokens-golang/supertokens"
+)
+
+// sessionBoundPayloadKeys are the keys that SuperTokens keeps in the access
+// token payload for one session: its lifetimes, the client and key it is bound
+// to, and the guest marker
+var sessionBoundPayloadKeys = []string{
+	sessionLifetimeKey,
+	sessionExpirationKey,
+	clientBindingKey,
+	refreshTokenBindingKey,
+	dpopConfirmationClaim,
+	guestSessionKey,
+}
+
+// getRegeneratedSessionPayload returns the access token payload of the
+// session replacing one with oldPayload. Protected props and the keys bound
+// to the old session are dropped, so that the new session gets its own
+// lifetimes and bindings. The choice of WithRememberMe is kept, with a new
+// refresh token lifetime, and guest sessions are replaced by guest sessions,
+// so that they are not upgraded (and revoked) when the new one is created.
+func getRegeneratedSessionPayload(oldPayload map[string]interface{}) (map[string]interface{}, error) {
+	result := map[string]interface{}{}
+	for k, v := range oldPayload {
+		if k == "iss" || k == "aud" || supertokens.DoesSliceContainString(k, protectedProps) || supertokens.DoesSliceContainString(k, sessionBoundPayloadKeys) {
+			continue
+		}
+		result[k] = v
+	}
+	if isGuestSessionPayload(oldPayload) {
+		result[guestSessionKey] = true
+	}
+	if rememberMe, ok := result[rememberMeKey].(bool); ok {
+		return WithRememberMe(result, rememberMe)
+	}
+	return result, nil
+}
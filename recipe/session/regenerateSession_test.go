@@ -0,0 +1,170 @@
+/* Copyright (c) 2023, VRAI Labs and/or its affiliates. All rights reserved.
+ *
+ * This software is licensed under the Apache License, Version 2.0 (the
+ * "License") as published by the Apache Software Foundation.
+ *
+ * You may not use this file except in compliance with the License. You may
+ * obtain a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+ * License for the specific language governing permissions and limitations
+ * under the License.
+ */
+
+package session
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/supertokens/supertokens-golang/recipe/session/sessmodels"
+	"github.com/supertokens/supertokens-golang/supertokens"
+)
+
+func TestRegenerateSessionKeepsPayloadAndDataAndRevokesTheOldSession(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	assert.NoError(t, err)
+	var createdSession map[string]interface{}
+	revoked := []interface{}{}
+	revokedHooks := []string{}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/apiversion", func(rw http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(rw).Encode(map[string]interface{}{"versions": []string{"3.0"}})
+	})
+	mux.HandleFunc("/.well-known/jwks.json", func(rw http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(rw).Encode(map[string]interface{}{"keys": []interface{}{map[string]interface{}{
+			"kty": "RSA",
+			"kid": "d-1",
+			"alg": "RS256",
+			"use": "sig",
+			"n":   base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+			"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+		}}})
+	})
+	mux.HandleFunc("/", func(rw http.ResponseWriter, r *http.Request) {
+		if strings.HasSuffix(r.URL.Path, "/recipe/session") && r.Method == http.MethodGet {
+			json.NewEncoder(rw).Encode(map[string]interface{}{
+				"status":             "OK",
+				"sessionHandle":      r.URL.Query().Get("sessionHandle"),
+				"userId":             "user1",
+				"tenantId":           "public",
+				"userDataInDatabase": map[string]interface{}{},
+				"userDataInJWT":      map[string]interface{}{},
+				"expiry":             2000,
+				"timeCreated":        1000,
+			})
+			return
+		}
+		if strings.HasSuffix(r.URL.Path, "/recipe/session/remove") {
+			var body map[string]interface{}
+			json.NewDecoder(r.Body).Decode(&body)
+			revoked = append(revoked, body["sessionHandles"].([]interface{})...)
+			json.NewEncoder(rw).Encode(map[string]interface{}{"status": "OK", "sessionHandlesRevoked": body["sessionHandles"]})
+			return
+		}
+		if strings.HasSuffix(r.URL.Path, "/recipe/session") && r.Method == http.MethodPost {
+			json.NewDecoder(r.Body).Decode(&createdSession)
+			claims := jwt.MapClaims{"sub": "user1", "sessionHandle": "handle2", "iat": time.Now().Unix(), "exp": time.Now().Add(time.Hour).Unix(), "tId": "public"}
+			for k, v := range createdSession["userDataInJWT"].(map[string]interface{}) {
+				claims[k] = v
+			}
+			token := jwt.NewWithClaims(jwt.SigningMethodRS256, claims)
+			token.Header["kid"] = "d-1"
+			signed, _ := token.SignedString(key)
+			json.NewEncoder(rw).Encode(map[string]interface{}{
+				"status":       "OK",
+				"session":      map[string]interface{}{"handle": "handle2", "userId": "user1", "userDataInJWT": createdSession["userDataInJWT"], "tenantId": "public"},
+				"accessToken":  map[string]interface{}{"token": signed, "expiry": time.Now().Add(time.Hour).UnixMilli(), "createdTime": time.Now().UnixMilli()},
+				"refreshToken": map[string]interface{}{"token": "refresh2", "expiry": time.Now().Add(time.Hour).UnixMilli(), "createdTime": time.Now().UnixMilli()},
+			})
+			return
+		}
+		rw.WriteHeader(http.StatusNotFound)
+	})
+	testServer := httptest.NewServer(mux)
+	defer testServer.Close()
+
+	resetAll()
+	defer resetAll()
+	err = supertokens.Init(supertokens.TypeInput{
+		Supertokens: &supertokens.ConnectionInfo{
+			ConnectionURI: testServer.URL,
+		},
+		AppInfo: supertokens.AppInfo{
+			AppName:       "SuperTokens",
+			WebsiteDomain: "supertokens.io",
+			APIDomain:     "api.supertokens.io",
+		},
+		RecipeList: []supertokens.Recipe{Init(&sessmodels.TypeInput{
+			OnSessionRevoked: func(sessionHandle string, userID string, req *http.Request, userContext supertokens.UserContext) {
+				revokedHooks = append(revokedHooks, sessionHandle)
+			},
+		})},
+	})
+	assert.NoError(t, err)
+
+	oldPayload := map[string]interface{}{
+		"sub":           "user1",
+		"sessionHandle": "handle1",
+		"exp":           1000,
+		"iss":           "https://old.supertokens.io",
+		"role":          "admin",
+		"st-rm":         false,
+		"st-lt":         map[string]interface{}{"exp": 1000},
+		"st-exps":       "ABSOLUTE",
+		"st-cb":         map[string]interface{}{"ip": "203.0.113.0/24"},
+		"st-rtb":        "binding",
+		"cnf":           map[string]interface{}{"jkt": "thumbprint"},
+	}
+	oldSession := &sessmodels.TypeSessionContainer{
+		GetUserIDWithContext:   func(userContext supertokens.UserContext) string { return "user1" },
+		GetTenantIdWithContext: func(userContext supertokens.UserContext) string { return "public" },
+		GetHandleWithContext:   func(userContext supertokens.UserContext) string { return "handle1" },
+		GetAccessTokenPayloadWithContext: func(userContext supertokens.UserContext) map[string]interface{} {
+			return oldPayload
+		},
+		GetSessionDataInDatabaseWithContext: func(userContext supertokens.UserContext) (map[string]interface{}, error) {
+			return map[string]interface{}{"cart": "3"}, nil
+		},
+	}
+
+	// the request carries the tokens of the old session, which is revoked
+	// once even though RevokeExistingSessionOnCreate is true by default
+	oldAccessToken := jwt.NewWithClaims(jwt.SigningMethodRS256, jwt.MapClaims{"sub": "user1", "sessionHandle": "handle1", "refreshTokenHash1": "hash", "iat": time.Now().Unix(), "exp": time.Now().Add(time.Hour).Unix(), "tId": "public"})
+	oldAccessToken.Header["kid"] = "d-1"
+	signedOldAccessToken, err := oldAccessToken.SignedString(key)
+	assert.NoError(t, err)
+	req := httptest.NewRequest(http.MethodPost, "/auth/sudo", nil)
+	req.Header.Set("Authorization", "Bearer "+signedOldAccessToken)
+	res := httptest.NewRecorder()
+	newSession, err := RegenerateSession(req, res, oldSession)
+	assert.NoError(t, err)
+	assert.Equal(t, "handle2", newSession.GetHandle())
+	assert.Equal(t, "admin", newSession.GetAccessTokenPayload()["role"])
+
+	createdPayload := createdSession["userDataInJWT"].(map[string]interface{})
+	assert.Equal(t, "admin", createdPayload["role"])
+	assert.Equal(t, false, createdPayload["st-rm"])
+	for _, key := range []string{"sessionHandle", "st-exps", "st-cb", "st-rtb", "cnf", "st-guest"} {
+		assert.Nil(t, createdPayload[key], key)
+	}
+	assert.NotEqual(t, "https://old.supertokens.io", createdPayload["iss"])
+	// the lifetime set by WithRememberMe starts again
+	assert.Greater(t, createdPayload["st-lt"].(map[string]interface{})["exp"], float64(time.Now().UnixMilli()))
+	assert.Equal(t, map[string]interface{}{"cart": "3"}, createdSession["userDataInDatabase"])
+	assert.Equal(t, []interface{}{"handle1"}, revoked)
+	assert.Equal(t, []string{"handle1"}, revokedHooks)
+	assert.NotEmpty(t, res.Header().Get("front-token"))
+}
@@ -0,0 +1,103 @@
+/* Copyright (c) 2023, VRAI Labs and/or its affiliates. All rights reserved.
+ *
+ * This software is licensed under the Apache License, Version 2.0 (the
+ * "License") as published by the Apache Software Foundation.
+ *
+ * You may not use this file except in compliance with the License. You may
+ * obtain a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+ * License for the specific language governing permissions and limitations
+ * under the License.
+ */
+
+package session
+
+import (
+	"errors"
+	"math"
+	"time"
+
+	"github.com/supertokens/supertokens-golang/recipe/session/sessmodels"
+)
+
+// rememberMeKey is the access token payload key holding the choice passed
+// to WithRememberMe
+const rememberMeKey = "st-rm"
+
+const defaultRefreshTokenLifetimeWhenNotRemembered = 24 * time.Hour
+
+// browserSessionCookieExpiry is passed to setCookie for cookies that should
+// be deleted when the browser is closed
+const browserSessionCookieExpiry uint64 = math.MaxUint64
+
+func normaliseRememberMeInput(config *sessmodels.RememberMeInput) (sessmodels.NormalisedRememberMeConfig, error) {
+	result := sessmodels.NormalisedRememberMeConfig{
+		RefreshTokenLifetimeWhenNotRemembered: defaultRefreshTokenLifetimeWhenNotRemembered,
+	}
+	if config == nil {
+		return result, nil
+	}
+	if config.RefreshTokenLifetimeWhenNotRemembered != nil {
+		if *config.RefreshTokenLifetimeWhenNotRemembered <= 0 {
+			return sessmodels.NormalisedRememberMeConfig{}, errors.New("RefreshTokenLifetimeWhenNotRemembered must be positive")
+		}
+		result.RefreshTokenLifetimeWhenNotRemembered = *config.RefreshTokenLifetimeWhenNotRemembered
+	}
+	if config.RefreshTokenLifetimeWhenRemembered != nil {
+		if *config.RefreshTokenLifetimeWhenRemembered <= 0 {
+			return sessmodels.NormalisedRememberMeConfig{}, errors.New("RefreshTokenLifetimeWhenRemembered must be positive")
+		}
+		result.RefreshTokenLifetimeWhenRemembered = config.RefreshTokenLifetimeWhenRemembered
+	}
+	return result, nil
+}
+
+// WithRememberMe returns a copy of accessTokenPayload for a session created
+// from a sign in form with a "keep me signed in" checkbox:
+//
+//	payload, err := session.WithRememberMe(nil, rememberMe)
+//	sessionContainer, err := session.CreateNewSession(req, res, tenantId, userID, payload, nil)
+//
+// Sessions that are not remembered can be refreshed for
+// RefreshTokenLifetimeWhenNotRemembered (see RememberMe in the recipe
+// config), and their cookies are deleted when the browser is closed.
+// Remembered sessions use RefreshTokenLifetimeWhenRemembered and persistent
+// cookies. It can be combined with WithSessionLifetime, in which case the
+// last refresh token lifetime set wins.
+func WithRememberMe(accessTokenPayload map[string]interface{}, rememberMe bool) (map[string]interface{}, error) {
+	instance, err := getRecipeInstanceOrThrowError()
+	if err != nil {
+		return nil, err
+	}
+	refreshTokenLifetime := instance.Config.RememberMe.RefreshTokenLifetimeWhenRemembered
+	if !rememberMe {
+		refreshTokenLifetime = &instance.Config.RememberMe.RefreshTokenLifetimeWhenNotRemembered
+	}
+
+	result := map[string]interface{}{}
+	for k, v := range accessTokenPayload {
+		result[k] = v
+	}
+	result[rememberMeKey] = rememberMe
+	if refreshTokenLifetime != nil {
+		sessionLifetime := map[string]interface{}{}
+		if existing, ok := accessTokenPayload[sessionLifetimeKey].(map[string]interface{}); ok {
+			for k, v := range existing {
+				sessionLifetime[k] = v
+			}
+		}
+		sessionLifetime["exp"] = time.Now().Add(*refreshTokenLifetime).UnixMilli()
+		result[sessionLifetimeKey] = sessionLifetime
+	}
+	return result, nil
+}
+
+// isSessionRemembered is false only for sessions created with WithRememberMe
+// and rememberMe set to false
+func isSessionRemembered(accessTokenPayload map[string]interface{}) bool {
+	rememberMe, ok := accessTokenPayload[rememberMeKey].(bool)
+	return !ok || rememberMe
+}
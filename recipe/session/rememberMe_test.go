@@ -0,0 +1,113 @@
+/* Copyright (c) 2023, VRAI Labs and/or its affiliates. All rights reserved.
+ *
+ * This software is licensed under the Apache License, Version 2.0 (the
+ * "License") as published by the Apache Software Foundation.
+ *
+ * You may not use this file except in compliance with the License. You may
+ * obtain a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+ * License for the specific language governing permissions and limitations
+ * under the License.
+ */
+
+package session
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/supertokens/supertokens-golang/recipe/session/sessmodels"
+	"github.com/supertokens/supertokens-golang/supertokens"
+)
+
+func TestWithRememberMe(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	assert.NoError(t, err)
+	var createdSession map[string]interface{}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/apiversion", func(rw http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(rw).Encode(map[string]interface{}{"versions": []string{"3.0"}})
+	})
+	mux.HandleFunc("/", func(rw http.ResponseWriter, r *http.Request) {
+		if strings.HasSuffix(r.URL.Path, "/recipe/session") && r.Method == http.MethodPost {
+			json.NewDecoder(r.Body).Decode(&createdSession)
+			claims := jwt.MapClaims{"sub": "user1", "sessionHandle": "handle1", "iat": time.Now().Unix(), "exp": time.Now().Add(time.Hour).Unix(), "tId": "public"}
+			for k, v := range createdSession["userDataInJWT"].(map[string]interface{}) {
+				claims[k] = v
+			}
+			token := jwt.NewWithClaims(jwt.SigningMethodRS256, claims)
+			token.Header["kid"] = "d-1"
+			signed, _ := token.SignedString(key)
+			json.NewEncoder(rw).Encode(map[string]interface{}{
+				"status":       "OK",
+				"session":      map[string]interface{}{"handle": "handle1", "userId": "user1", "userDataInJWT": createdSession["userDataInJWT"], "tenantId": "public"},
+				"accessToken":  map[string]interface{}{"token": signed, "expiry": time.Now().Add(time.Hour).UnixMilli(), "createdTime": time.Now().UnixMilli()},
+				"refreshToken": map[string]interface{}{"token": "refresh1", "expiry": time.Now().Add(100 * 24 * time.Hour).UnixMilli(), "createdTime": time.Now().UnixMilli()},
+			})
+			return
+		}
+		rw.WriteHeader(http.StatusNotFound)
+	})
+	testServer := httptest.NewServer(mux)
+	defer testServer.Close()
+
+	resetAll()
+	defer resetAll()
+	notRemembered := 2 * time.Hour
+	err = supertokens.Init(supertokens.TypeInput{
+		Supertokens: &supertokens.ConnectionInfo{
+			ConnectionURI: testServer.URL,
+		},
+		AppInfo: supertokens.AppInfo{
+			AppName:       "SuperTokens",
+			WebsiteDomain: "supertokens.io",
+			APIDomain:     "api.supertokens.io",
+		},
+		RecipeList: []supertokens.Recipe{Init(&sessmodels.TypeInput{
+			RememberMe: &sessmodels.RememberMeInput{RefreshTokenLifetimeWhenNotRemembered: &notRemembered},
+		})},
+	})
+	assert.NoError(t, err)
+
+	createSession := func(rememberMe bool) map[string]*http.Cookie {
+		payload, err := WithRememberMe(map[string]interface{}{"role": "admin"}, rememberMe)
+		assert.NoError(t, err)
+		req := httptest.NewRequest(http.MethodPost, "/auth/signin", nil)
+		req.Header.Set("st-auth-mode", "cookie")
+		res := httptest.NewRecorder()
+		_, err = CreateNewSession(req, res, "public", "user1", payload, nil)
+		assert.NoError(t, err)
+		cookies := map[string]*http.Cookie{}
+		for _, cookie := range res.Result().Cookies() {
+			cookies[cookie.Name] = cookie
+		}
+		return cookies
+	}
+
+	cookies := createSession(false)
+	userDataInJWT := createdSession["userDataInJWT"].(map[string]interface{})
+	assert.Equal(t, "admin", userDataInJWT["role"])
+	assert.Equal(t, false, userDataInJWT[rememberMeKey])
+	expiresAt := userDataInJWT[sessionLifetimeKey].(map[string]interface{})["exp"].(float64)
+	assert.InDelta(t, time.Now().Add(notRemembered).UnixMilli(), expiresAt, 60000)
+	assert.True(t, cookies["sAccessToken"].Expires.IsZero())
+	assert.True(t, cookies["sRefreshToken"].Expires.IsZero())
+
+	cookies = createSession(true)
+	userDataInJWT = createdSession["userDataInJWT"].(map[string]interface{})
+	assert.Equal(t, true, userDataInJWT[rememberMeKey])
+	assert.Nil(t, userDataInJWT[sessionLifetimeKey])
+	assert.True(t, cookies["sAccessToken"].Expires.After(time.Now().Add(24*time.Hour)))
+	assert.True(t, cookies["sRefreshToken"].Expires.After(time.Now().Add(24*time.Hour)))
+}
@@ -0,0 +1,55 @@
+/* Copyright (c) 2023, VRAI Labs and/or its affiliates. All rights reserved.
+ *
+ * This software is licensed under the Apache License, Version 2.0 (the
+ * "License") as published by the Apache Software Foundation.
+ *
+ * You may not use this file except in compliance with the License. You may
+ * obtain a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+ * License for the specific language governing permissions and limitations
+ * under the License.
+ */
+
+package session
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/supertokens/supertokens-golang/recipe/session/sessmodels"
+	"github.com/supertokens/supertokens-golang/supertokens"
+)
+
+func TestRemoveFromAccessTokenPayloadOnlyRemovesTheGivenKeys(t *testing.T) {
+	var newPayload map[string]interface{}
+	regenerateAccessToken := func(accessToken string, newAccessTokenPayload *map[string]interface{}, userContext supertokens.UserContext) (*sessmodels.RegenerateAccessTokenResponse, error) {
+		newPayload = *newAccessTokenPayload
+		return &sessmodels.RegenerateAccessTokenResponse{
+			Status: "OK",
+			Session: sessmodels.SessionStruct{
+				Handle:                "handle1",
+				UserID:                "user1",
+				UserDataInAccessToken: newPayload,
+			},
+		}, nil
+	}
+	recipeImpl := sessmodels.RecipeInterface{RegenerateAccessToken: &regenerateAccessToken}
+
+	input := makeSessionContainerInput("accessToken", "handle1", "user1", "public", map[string]interface{}{
+		"sub":   "user1",
+		"exp":   1000,
+		"role":  "admin",
+		"plan":  "pro",
+		"theme": "dark",
+	}, recipeImpl, "frontToken", nil, nil, nil, false)
+	sessionContainer := newSessionContainer(sessmodels.TypeNormalisedInput{}, &input)
+
+	err := sessionContainer.RemoveFromAccessTokenPayload("role", "theme", "missing")
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{"plan": "pro"}, newPayload)
+	assert.Equal(t, "pro", sessionContainer.GetAccessTokenPayload()["plan"])
+	assert.Nil(t, sessionContainer.GetAccessTokenPayload()["role"])
+}
@@ -0,0 +1,110 @@
+/* Copyright (c) 2023, VRAI Labs and/or its affiliates. All rights reserved.
+ *
+ * This software is licensed under the Apache License, Version 2.0 (the
+ * "License") as published by the Apache Software Foundation.
+ *
+ * You may not use this file except in compliance with the License. You may
+ * obtain a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+ * License for the specific language governing permissions and limitations
+ * under the License.
+ */
+
+package session
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/supertokens/supertokens-golang/recipe/session/errors"
+	"github.com/supertokens/supertokens-golang/recipe/session/sessmodels"
+	"github.com/supertokens/supertokens-golang/supertokens"
+)
+
+func TestCreateNewSessionRevokesTheExistingSession(t *testing.T) {
+	for _, revokeExistingSession := range []bool{true, false} {
+		key, err := rsa.GenerateKey(rand.Reader, 2048)
+		assert.NoError(t, err)
+		revoked := []interface{}{}
+		mux := http.NewServeMux()
+		mux.HandleFunc("/apiversion", func(rw http.ResponseWriter, r *http.Request) {
+			json.NewEncoder(rw).Encode(map[string]interface{}{"versions": []string{"3.0"}})
+		})
+		mux.HandleFunc("/", func(rw http.ResponseWriter, r *http.Request) {
+			if strings.HasSuffix(r.URL.Path, "/recipe/session/remove") {
+				var body map[string]interface{}
+				json.NewDecoder(r.Body).Decode(&body)
+				revoked = append(revoked, body["sessionHandles"].([]interface{})...)
+				json.NewEncoder(rw).Encode(map[string]interface{}{"status": "OK", "sessionHandlesRevoked": body["sessionHandles"]})
+				return
+			}
+			if strings.HasSuffix(r.URL.Path, "/recipe/session") && r.Method == http.MethodPost {
+				claims := jwt.MapClaims{"sub": "user1", "sessionHandle": "handle2", "iat": time.Now().Unix(), "exp": time.Now().Add(time.Hour).Unix(), "tId": "public"}
+				token := jwt.NewWithClaims(jwt.SigningMethodRS256, claims)
+				token.Header["kid"] = "d-1"
+				signed, _ := token.SignedString(key)
+				json.NewEncoder(rw).Encode(map[string]interface{}{
+					"status":       "OK",
+					"session":      map[string]interface{}{"handle": "handle2", "userId": "user1", "userDataInJWT": map[string]interface{}{}, "tenantId": "public"},
+					"accessToken":  map[string]interface{}{"token": signed, "expiry": time.Now().Add(time.Hour).UnixMilli(), "createdTime": time.Now().UnixMilli()},
+					"refreshToken": map[string]interface{}{"token": "refresh2", "expiry": time.Now().Add(time.Hour).UnixMilli(), "createdTime": time.Now().UnixMilli()},
+				})
+				return
+			}
+			rw.WriteHeader(http.StatusNotFound)
+		})
+		testServer := httptest.NewServer(mux)
+
+		resetAll()
+		err = supertokens.Init(supertokens.TypeInput{
+			Supertokens: &supertokens.ConnectionInfo{
+				ConnectionURI: testServer.URL,
+			},
+			AppInfo: supertokens.AppInfo{
+				AppName:       "SuperTokens",
+				WebsiteDomain: "supertokens.io",
+				APIDomain:     "api.supertokens.io",
+			},
+			RecipeList: []supertokens.Recipe{Init(&sessmodels.TypeInput{
+				RevokeExistingSessionOnCreate: &revokeExistingSession,
+			})},
+		})
+		assert.NoError(t, err)
+		instance, err := getRecipeInstanceOrThrowError()
+		assert.NoError(t, err)
+		getSession := func(accessToken *string, antiCsrfToken *string, options *sessmodels.VerifySessionOptions, userContext supertokens.UserContext) (sessmodels.SessionContainer, error) {
+			if *accessToken != "planted" {
+				return nil, errors.TryRefreshTokenError{Msg: "expired"}
+			}
+			return &sessmodels.TypeSessionContainer{
+				GetHandleWithContext: func(userContext supertokens.UserContext) string { return "handle1" },
+			}, nil
+		}
+		instance.RecipeImpl.GetSession = &getSession
+
+		req := httptest.NewRequest(http.MethodPost, "/auth/signin", nil)
+		req.AddCookie(&http.Cookie{Name: "sAccessToken", Value: "planted"})
+		req.Header.Set("Authorization", "Bearer expired")
+		newSession, err := CreateNewSession(req, httptest.NewRecorder(), "public", "user1", nil, nil)
+		assert.NoError(t, err)
+		assert.Equal(t, "handle2", newSession.GetHandle())
+		if revokeExistingSession {
+			assert.Equal(t, []interface{}{"handle1"}, revoked)
+		} else {
+			assert.Empty(t, revoked)
+		}
+		testServer.Close()
+	}
+	resetAll()
+}
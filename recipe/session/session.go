@@ -191,7 +191,7 @@ func newSessionContainer(config sessmodels.TypeNormalisedInput, session *Session
 
 			session.userDataInAccessToken = payload
 			session.accessToken = response.AccessToken.Token
-			session.frontToken = BuildFrontToken(session.userID, response.AccessToken.Expiry, payload)
+			session.frontToken = buildFrontToken(config, session.userID, response.AccessToken.Expiry, payload)
 			session.accessTokenUpdated = true
 
 			if session.requestResponseInfo != nil {
@@ -257,6 +257,14 @@ func newSessionContainer(config sessmodels.TypeNormalisedInput, session *Session
 		return nil
 	}
 
+	sessionContainer.RemoveFromAccessTokenPayloadWithContext = func(keys []string, userContext supertokens.UserContext) error {
+		update := map[string]interface{}{}
+		for _, key := range keys {
+			update[key] = nil
+		}
+		return sessionContainer.MergeIntoAccessTokenPayloadWithContext(update, userContext)
+	}
+
 	sessionContainer.FetchAndSetClaimWithContext = func(claim *claims.TypeSessionClaim, userContext supertokens.UserContext) error {
 		update, err := claim.Build(sessionContainer.GetUserIDWithContext(userContext), "public", nil, userContext)
 		if err != nil {
@@ -290,7 +298,13 @@ func newSessionContainer(config sessmodels.TypeNormalisedInput, session *Session
 			}
 
 			if session.refreshToken != nil {
-				err = setToken(config, info.Res, sessmodels.RefreshToken, session.refreshToken.Token, session.refreshToken.Expiry, info.TokenTransferMethod, session.requestResponseInfo.Req, supertokens.SetRequestInUserContextIfNotDefined(userContext, session.requestResponseInfo.Req))
+				refreshTokenCookieExpiry := session.refreshToken.Expiry
+				if !isSessionRemembered(session.userDataInAccessToken) {
+					refreshTokenCookieExpiry = browserSessionCookieExpiry
+				} else if expiresAt, ok := getSessionLifetimeValue(session.userDataInAccessToken, "exp"); ok && uint64(expiresAt) < refreshTokenCookieExpiry {
+					refreshTokenCookieExpiry = uint64(expiresAt)
+				}
+				err = setToken(config, info.Res, sessmodels.RefreshToken, session.refreshToken.Token, refreshTokenCookieExpiry, info.TokenTransferMethod, session.requestResponseInfo.Req, supertokens.SetRequestInUserContextIfNotDefined(userContext, session.requestResponseInfo.Req))
 
 				if err != nil {
 					return err
@@ -339,6 +353,9 @@ func newSessionContainer(config sessmodels.TypeNormalisedInput, session *Session
 	sessionContainer.MergeIntoAccessTokenPayload = func(accessTokenPayloadUpdate map[string]interface{}) error {
 		return sessionContainer.MergeIntoAccessTokenPayloadWithContext(accessTokenPayloadUpdate, &map[string]interface{}{})
 	}
+	sessionContainer.RemoveFromAccessTokenPayload = func(keys ...string) error {
+		return sessionContainer.RemoveFromAccessTokenPayloadWithContext(keys, &map[string]interface{}{})
+	}
 
 	sessionContainer.AssertClaims = func(claimValidators []claims.SessionClaimValidator) error {
 		return sessionContainer.AssertClaimsWithContext(claimValidators, &map[string]interface{}{})
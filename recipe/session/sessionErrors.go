@@ -0,0 +1,47 @@
+/* Copyright (c) 2023, VRAI Labs and/or its affiliates. All rights reserved.
+ *
+ * This software is licensed under the Apache License, Version 2.0 (the
+ * "License") as published by the Apache Software Foundation.
+ *
+ * You may not use this file except in compliance with the License. You may
+ * obtain a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+ * License for the specific language governing permissions and limitations
+ * under the License.
+ */
+
+package session
+
+import "github.com/supertokens/supertokens-golang/recipe/session/errors"
+
+// Errors returned by GetSession, RefreshSession, VerifySession and the other
+// session functions match these with errors.Is, also when they are wrapped:
+//
+//	if errors.Is(err, session.ErrTryRefreshToken) { ... }
+//
+// The session handle and user ID of a detected token theft can be read with
+// errors.As and the TokenTheftDetectedError type of the session/errors
+// package.
+var (
+	// ErrUnauthorized is matched by errors for requests without a valid
+	// session, e.g. a missing access token or a revoked session
+	ErrUnauthorized = errors.ErrUnauthorized
+	// ErrTryRefreshToken is matched by errors for expired access tokens,
+	// after which the frontend should call the refresh API
+	ErrTryRefreshToken = errors.ErrTryRefreshToken
+	// ErrTokenTheftDetected is matched by errors for refresh tokens used
+	// after they were rotated
+	ErrTokenTheftDetected = errors.ErrTokenTheftDetected
+	// ErrInvalidClaims is matched by errors for sessions that fail claim
+	// validation
+	ErrInvalidClaims = errors.ErrInvalidClaims
+	// ErrPayloadTooLarge is matched by errors for access token payloads
+	// larger than AccessTokenPayloadSize.MaxBytes
+	ErrPayloadTooLarge = errors.ErrPayloadTooLarge
+	// ErrSessionSuspended is matched by errors for sessions suspended with
+	// SuspendSession
+	ErrSessionSuspended = errors.ErrSessionSuspended
+)
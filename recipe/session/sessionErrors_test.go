@@ -0,0 +1,65 @@
+/* Copyright (c) 2023, VRAI Labs and/or its affiliates. All rights reserved.
+ *
+ * This software is licensed under the Apache License, Version 2.0 (the
+ * "License") as published by the Apache Software Foundation.
+ *
+ * You may not use this file except in compliance with the License. You may
+ * obtain a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+ * License for the specific language governing permissions and limitations
+ * under the License.
+ */
+
+package session
+
+import (
+	defaultErrors "errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/supertokens/supertokens-golang/recipe/session/errors"
+	"github.com/supertokens/supertokens-golang/recipe/session/sessmodels"
+	"github.com/supertokens/supertokens-golang/supertokens"
+)
+
+func TestSessionErrorsMatchSentinelErrors(t *testing.T) {
+	theft := fmt.Errorf("refreshing: %w", errors.TokenTheftDetectedError{
+		Msg:     "token theft detected",
+		Payload: errors.TokenTheftDetectedErrorPayload{SessionHandle: "handle1", UserID: "user1"},
+	})
+	assert.ErrorIs(t, theft, ErrTokenTheftDetected)
+	assert.NotErrorIs(t, theft, ErrUnauthorized)
+	var theftError errors.TokenTheftDetectedError
+	assert.True(t, defaultErrors.As(theft, &theftError))
+	assert.Equal(t, "handle1", theftError.Payload.SessionHandle)
+	assert.Equal(t, "user1", theftError.Payload.UserID)
+
+	assert.ErrorIs(t, errors.UnauthorizedError{Msg: "unauthorised"}, ErrUnauthorized)
+	assert.ErrorIs(t, errors.TryRefreshTokenError{Msg: "expired"}, ErrTryRefreshToken)
+	assert.ErrorIs(t, errors.InvalidClaimError{Msg: "invalid claims"}, ErrInvalidClaims)
+	assert.NotErrorIs(t, errors.TryRefreshTokenError{Msg: "expired"}, ErrUnauthorized)
+}
+
+func TestGetSessionWithoutRequestResponseReturnsSentinelErrors(t *testing.T) {
+	resetAll()
+	defer resetAll()
+	err := supertokens.Init(supertokens.TypeInput{
+		Supertokens: &supertokens.ConnectionInfo{
+			ConnectionURI: "http://localhost:8080",
+		},
+		AppInfo: supertokens.AppInfo{
+			AppName:       "SuperTokens",
+			WebsiteDomain: "supertokens.io",
+			APIDomain:     "api.supertokens.io",
+		},
+		RecipeList: []supertokens.Recipe{Init(&sessmodels.TypeInput{})},
+	})
+	assert.NoError(t, err)
+
+	_, err = GetSessionWithoutRequestResponse("not-a-jwt", nil, nil)
+	assert.ErrorIs(t, err, ErrUnauthorized)
+}
@@ -0,0 +1,68 @@
+/* Copyright (c) 2023, VRAI Labs and/or its affiliates. All rights reserved.
+ *
+ * This software is licensed under the Apache License, Version 2.0 (the
+ * "License") as published by the Apache Software Foundation.
+ *
+ * You may not use this file except in compliance with the License. You may
+ * obtain a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+ * License for the specific language governing permissions and limitations
+ * under the License.
+ */
+
+package session
+
+import (
+	"net"
+	"time"
+
+	"github.com/supertokens/supertokens-golang/recipe/session/sessmodels"
+)
+
+// SessionFilter selects sessions of a user, see RevokeSessionsMatching.
+// Device info is only recorded if UserSessionsAPI is enabled in the recipe
+// config, so filters on it never match sessions created without it.
+type SessionFilter func(userSession sessmodels.UserSession) bool
+
+// SessionCreatedBefore matches sessions created before t
+func SessionCreatedBefore(t time.Time) SessionFilter {
+	return func(userSession sessmodels.UserSession) bool {
+		return userSession.TimeCreated < uint64(t.UnixMilli())
+	}
+}
+
+// SessionFromIPRange matches sessions created from an IP in ipRange
+func SessionFromIPRange(ipRange *net.IPNet) SessionFilter {
+	return func(userSession sessmodels.UserSession) bool {
+		ipStr, ok := userSession.Device["ip"].(string)
+		if !ok {
+			return false
+		}
+		ip := net.ParseIP(ipStr)
+		return ip != nil && ipRange.Contains(ip)
+	}
+}
+
+// SessionWithDeviceInfo matches sessions whose device info has value for
+// key, e.g. a device label returned by GetDeviceInfo
+func SessionWithDeviceInfo(key string, value interface{}) SessionFilter {
+	return func(userSession sessmodels.UserSession) bool {
+		deviceValue, ok := userSession.Device[key]
+		return ok && deviceValue == value
+	}
+}
+
+// AllSessionFilters matches sessions that match all of the filters
+func AllSessionFilters(filters ...SessionFilter) SessionFilter {
+	return func(userSession sessmodels.UserSession) bool {
+		for _, filter := range filters {
+			if !filter(userSession) {
+				return false
+			}
+		}
+		return true
+	}
+}
@@ -0,0 +1,109 @@
+/* Copyright (c) 2023, VRAI Labs and/or its affiliates. All rights reserved.
+ *
+ * This software is licensed under the Apache License, Version 2.0 (the
+ * "License") as published by the Apache Software Foundation.
+ *
+ * You may not use this file except in compliance with the License. You may
+ * obtain a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+ * License for the specific language governing permissions and limitations
+ * under the License.
+ */
+
+package session
+
+import (
+	"encoding/json"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/supertokens/supertokens-golang/supertokens"
+)
+
+func TestRevokeSessionsMatching(t *testing.T) {
+	now := time.Now()
+	sessions := map[string]map[string]interface{}{
+		"old-office": {"timeCreated": now.Add(-48 * time.Hour).UnixMilli(), "ip": "10.0.0.5", "label": "laptop"},
+		"old-vpn":    {"timeCreated": now.Add(-48 * time.Hour).UnixMilli(), "ip": "203.0.113.7", "label": "phone"},
+		"new-vpn":    {"timeCreated": now.UnixMilli(), "ip": "203.0.113.8", "label": "phone"},
+	}
+	revoked := []interface{}{}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/apiversion", func(rw http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(rw).Encode(map[string]interface{}{"versions": []string{"3.0"}})
+	})
+	mux.HandleFunc("/", func(rw http.ResponseWriter, r *http.Request) {
+		if strings.HasSuffix(r.URL.Path, "/recipe/session/user") {
+			json.NewEncoder(rw).Encode(map[string]interface{}{"status": "OK", "sessionHandles": []string{"old-office", "old-vpn", "new-vpn"}})
+			return
+		}
+		if strings.HasSuffix(r.URL.Path, "/recipe/session/remove") {
+			var body map[string]interface{}
+			json.NewDecoder(r.Body).Decode(&body)
+			revoked = append(revoked, body["sessionHandles"].([]interface{})...)
+			json.NewEncoder(rw).Encode(map[string]interface{}{"status": "OK", "sessionHandlesRevoked": body["sessionHandles"]})
+			return
+		}
+		if strings.HasSuffix(r.URL.Path, "/recipe/session") && r.Method == http.MethodGet {
+			handle := r.URL.Query().Get("sessionHandle")
+			session := sessions[handle]
+			json.NewEncoder(rw).Encode(map[string]interface{}{
+				"status":        "OK",
+				"sessionHandle": handle,
+				"userId":        "user1",
+				"tenantId":      "public",
+				"userDataInDatabase": map[string]interface{}{
+					userSessionDeviceKey: map[string]interface{}{
+						"info": map[string]interface{}{"ip": session["ip"], "label": session["label"]},
+					},
+				},
+				"userDataInJWT": map[string]interface{}{},
+				"expiry":        now.Add(time.Hour).UnixMilli(),
+				"timeCreated":   session["timeCreated"],
+			})
+			return
+		}
+		rw.WriteHeader(http.StatusNotFound)
+	})
+	testServer := httptest.NewServer(mux)
+	defer testServer.Close()
+
+	resetAll()
+	defer resetAll()
+	err := supertokens.Init(supertokens.TypeInput{
+		Supertokens: &supertokens.ConnectionInfo{
+			ConnectionURI: testServer.URL,
+		},
+		AppInfo: supertokens.AppInfo{
+			AppName:       "SuperTokens",
+			WebsiteDomain: "supertokens.io",
+			APIDomain:     "api.supertokens.io",
+		},
+		RecipeList: []supertokens.Recipe{Init(nil)},
+	})
+	assert.NoError(t, err)
+
+	_, vpnRange, err := net.ParseCIDR("203.0.113.0/24")
+	assert.NoError(t, err)
+	revokedHandles, err := RevokeSessionsMatching("user1", nil, AllSessionFilters(
+		SessionFromIPRange(vpnRange),
+		SessionCreatedBefore(now.Add(-time.Hour)),
+	))
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"old-vpn"}, revokedHandles)
+	assert.Equal(t, []interface{}{"old-vpn"}, revoked)
+
+	revoked = []interface{}{}
+	revokedHandles, err = RevokeSessionsMatching("user1", nil, SessionWithDeviceInfo("label", "tablet"))
+	assert.NoError(t, err)
+	assert.Empty(t, revokedHandles)
+	assert.Empty(t, revoked)
+}
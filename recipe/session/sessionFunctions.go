@@ -16,7 +16,6 @@
 package session
 
 import (
-	"encoding/json"
 	defaultErrors "errors"
 	"fmt"
 	"strings"
@@ -41,17 +40,8 @@ func createNewSessionHelper(config sessmodels.TypeNormalisedInput, querier super
 		"useDynamicSigningKey": config.UseDynamicAccessTokenSigningKey,
 	}
 
-	response, err := querier.SendPostRequest(tenantId+"/recipe/session", requestBody, userContext)
-	if err != nil {
-		return sessmodels.CreateOrRefreshAPIResponse{}, err
-	}
-
-	responseByte, err := json.Marshal(response)
-	if err != nil {
-		return sessmodels.CreateOrRefreshAPIResponse{}, err
-	}
 	var resp sessmodels.CreateOrRefreshAPIResponse
-	err = json.Unmarshal(responseByte, &resp)
+	err := querier.SendPostRequestInto(tenantId+"/recipe/session", requestBody, &resp, userContext)
 	if err != nil {
 		return sessmodels.CreateOrRefreshAPIResponse{}, err
 	}
@@ -59,6 +49,13 @@ func createNewSessionHelper(config sessmodels.TypeNormalisedInput, querier super
 	return resp, nil
 }
 
+// isAccessTokenExpired reads the expiry of a v3 (or later) access token
+// without verifying its signature
+func isAccessTokenExpired(parsedAccessToken sessmodels.ParsedJWTInfo) bool {
+	exp := sanitizeNumberInputAsUint64(parsedAccessToken.Payload["exp"])
+	return exp == nil || *exp*1000 < GetCurrTimeInMS()
+}
+
 func getSessionHelper(config sessmodels.TypeNormalisedInput, querier supertokens.Querier, parsedAccessToken sessmodels.ParsedJWTInfo, antiCsrfToken *string, doAntiCsrfCheck, alwaysCheckCore bool, userContext supertokens.UserContext) (sessmodels.GetSessionResponse, error) {
 	var accessTokenInfo *AccessTokenInfoStruct = nil
 	var err error = nil
@@ -71,6 +68,17 @@ func getSessionHelper(config sessmodels.TypeNormalisedInput, querier supertokens
 	}
 
 	accessTokenInfo, err = GetInfoFromAccessToken(parsedAccessToken, combinedJwks, config.AntiCsrfFunctionOrString.StrValue == AntiCSRF_VIA_TOKEN && doAntiCsrfCheck)
+	if err != nil && defaultErrors.As(err, &errors.TryRefreshTokenError{}) && parsedAccessToken.Version >= 3 && !isAccessTokenExpired(parsedAccessToken) {
+		// The token may be signed with a key that was rotated in after the keys
+		// were cached, so we verify it once more with freshly fetched keys
+		refetchedJwks, refetchError := refetchJWKS()
+		if refetchError != nil {
+			supertokens.LogDebugMessage(fmt.Sprintf("getSessionHelper: Failed to fetch the JWKs again - %s", refetchError))
+		} else if refetchedJwks != nil {
+			supertokens.LogDebugMessage("getSessionHelper: Verifying the access token again with freshly fetched JWKs")
+			accessTokenInfo, err = GetInfoFromAccessToken(parsedAccessToken, refetchedJwks, config.AntiCsrfFunctionOrString.StrValue == AntiCSRF_VIA_TOKEN && doAntiCsrfCheck)
+		}
+	}
 	if err != nil {
 		if !defaultErrors.As(err, &errors.TryRefreshTokenError{}) {
 			supertokens.LogDebugMessage("getSessionHelper: Returning TryRefreshTokenError because GetInfoFromAccessToken returned an error")
@@ -162,24 +170,17 @@ func getSessionHelper(config sessmodels.TypeNormalisedInput, querier supertokens
 	if supertokens.IsRunningInTestMode() {
 		didGetSessionCallCore = true
 	}
-	response, err := querier.SendPostRequest("/recipe/session/verify", requestBody, userContext)
+	var response struct {
+		sessmodels.GetSessionResponse
+		Message string `json:"message"`
+	}
+	err = querier.SendPostRequestInto("/recipe/session/verify", requestBody, &response, userContext)
 	if err != nil {
 		return sessmodels.GetSessionResponse{}, err
 	}
 
-	status := response["status"]
-	if status.(string) == "OK" {
-		delete(response, "status")
-
-		responseByte, err := json.Marshal(response)
-		if err != nil {
-			return sessmodels.GetSessionResponse{}, err
-		}
-		var result sessmodels.GetSessionResponse
-		err = json.Unmarshal(responseByte, &result)
-		if err != nil {
-			return sessmodels.GetSessionResponse{}, err
-		}
+	if response.Status == "OK" {
+		result := response.GetSessionResponse
 
 		var expiryToSet uint64
 
@@ -193,12 +194,12 @@ func getSessionHelper(config sessmodels.TypeNormalisedInput, querier supertokens
 
 		result.Session.ExpiryTime = expiryToSet
 		return result, nil
-	} else if response["status"].(string) == errors.UnauthorizedErrorStr {
+	} else if response.Status == errors.UnauthorizedErrorStr {
 		supertokens.LogDebugMessage("getSession: Returning UNAUTHORISED because of core response")
-		return sessmodels.GetSessionResponse{}, errors.UnauthorizedError{Msg: response["message"].(string)}
+		return sessmodels.GetSessionResponse{}, errors.UnauthorizedError{Msg: response.Message}
 	} else {
 		supertokens.LogDebugMessage("getSession: Returning TRY_REFRESH_TOKEN because of core response")
-		return sessmodels.GetSessionResponse{}, errors.TryRefreshTokenError{Msg: response["message"].(string)}
+		return sessmodels.GetSessionResponse{}, errors.TryRefreshTokenError{Msg: response.Message}
 	}
 }
 
@@ -237,29 +238,24 @@ func refreshSessionHelper(config sessmodels.TypeNormalisedInput, querier superto
 		return sessmodels.CreateOrRefreshAPIResponse{}, defaultErrors.New("Please either use VIA_TOKEN, NONE or call with doAntiCsrfCheck false")
 	}
 
-	response, err := querier.SendPostRequest("/recipe/session/refresh", requestBody, userContext)
+	var response struct {
+		sessmodels.CreateOrRefreshAPIResponse
+		Status  string `json:"status"`
+		Message string `json:"message"`
+	}
+	err := querier.SendPostRequestInto("/recipe/session/refresh", requestBody, &response, userContext)
 	if err != nil {
 		return sessmodels.CreateOrRefreshAPIResponse{}, err
 	}
-	if response["status"] == "OK" {
-		delete(response, "status")
-		responseByte, err := json.Marshal(response)
-		if err != nil {
-			return sessmodels.CreateOrRefreshAPIResponse{}, err
-		}
-		var result sessmodels.CreateOrRefreshAPIResponse
-		err = json.Unmarshal(responseByte, &result)
-		if err != nil {
-			return sessmodels.CreateOrRefreshAPIResponse{}, err
-		}
-		return result, nil
-	} else if response["status"].(string) == errors.UnauthorizedErrorStr {
+	if response.Status == "OK" {
+		return response.CreateOrRefreshAPIResponse, nil
+	} else if response.Status == errors.UnauthorizedErrorStr {
 		supertokens.LogDebugMessage("refreshSession: Returning UNAUTHORISED because of core response")
-		return sessmodels.CreateOrRefreshAPIResponse{}, errors.UnauthorizedError{Msg: response["message"].(string)}
+		return sessmodels.CreateOrRefreshAPIResponse{}, errors.UnauthorizedError{Msg: response.Message}
 	} else {
 		sessionInfo := errors.TokenTheftDetectedErrorPayload{
-			SessionHandle: (response["session"].(map[string]interface{}))["handle"].(string),
-			UserID:        (response["session"].(map[string]interface{}))["userId"].(string),
+			SessionHandle: response.Session.Handle,
+			UserID:        response.Session.UserID,
 		}
 
 		supertokens.LogDebugMessage("refreshSession: Returning TOKEN_THEFT_DETECTED because of core response")
@@ -364,6 +360,21 @@ func updateSessionDataInDatabaseHelper(querier supertokens.Querier, sessionHandl
 	return true, nil
 }
 
+func mergeIntoSessionData(sessionData map[string]interface{}, update map[string]interface{}) map[string]interface{} {
+	result := map[string]interface{}{}
+	for k, v := range sessionData {
+		result[k] = v
+	}
+	for k, v := range update {
+		if v == nil {
+			delete(result, k)
+		} else {
+			result[k] = v
+		}
+	}
+	return result
+}
+
 func updateAccessTokenPayloadHelper(querier supertokens.Querier, sessionHandle string, newAccessTokenPayload map[string]interface{}, userContext supertokens.UserContext) (bool, error) {
 	if newAccessTokenPayload == nil {
 		newAccessTokenPayload = map[string]interface{}{}
@@ -385,24 +396,16 @@ func regenerateAccessTokenHelper(querier supertokens.Querier, newAccessTokenPayl
 	if newAccessTokenPayload == nil {
 		newAccessTokenPayload = &map[string]interface{}{}
 	}
-	response, err := querier.SendPostRequest("/recipe/session/regenerate", map[string]interface{}{
+	var resp sessmodels.RegenerateAccessTokenResponse
+	err := querier.SendPostRequestInto("/recipe/session/regenerate", map[string]interface{}{
 		"accessToken":   accessToken,
 		"userDataInJWT": newAccessTokenPayload,
-	}, userContext)
+	}, &resp, userContext)
 	if err != nil {
 		return nil, err
 	}
-	if response["status"].(string) == errors.UnauthorizedErrorStr {
+	if resp.Status == errors.UnauthorizedErrorStr {
 		return nil, nil
 	}
-	responseByte, err := json.Marshal(response)
-	if err != nil {
-		return nil, err
-	}
-	var resp sessmodels.RegenerateAccessTokenResponse
-	err = json.Unmarshal(responseByte, &resp)
-	if err != nil {
-		return nil, err
-	}
 	return &resp, nil
 }
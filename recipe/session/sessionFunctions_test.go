@@ -0,0 +1,84 @@
+/* Copyright (c) 2023, VRAI Labs and/or its affiliates. All rights reserved.
+ *
+ * This software is licensed under the Apache License, Version 2.0 (the
+ * "License") as published by the Apache Software Foundation.
+ *
+ * You may not use this file except in compliance with the License. You may
+ * obtain a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+ * License for the specific language governing permissions and limitations
+ * under the License.
+ */
+
+package session
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/supertokens/supertokens-golang/supertokens"
+)
+
+func TestMergeIntoSessionDataInDatabase(t *testing.T) {
+	var updatedSessionData map[string]interface{}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/apiversion", func(rw http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(rw).Encode(map[string]interface{}{"versions": []string{"3.0"}})
+	})
+	mux.HandleFunc("/recipe/session", func(rw http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("sessionHandle") != "handle1" {
+			json.NewEncoder(rw).Encode(map[string]interface{}{"status": "UNAUTHORISED"})
+			return
+		}
+		json.NewEncoder(rw).Encode(map[string]interface{}{
+			"status":             "OK",
+			"sessionHandle":      "handle1",
+			"userId":             "user1",
+			"tenantId":           "public",
+			"expiry":             2000,
+			"timeCreated":        1000,
+			"userDataInJWT":      map[string]interface{}{},
+			"userDataInDatabase": map[string]interface{}{"plan": "free", "cart": 3, "theme": "dark"},
+		})
+	})
+	mux.HandleFunc("/recipe/session/data", func(rw http.ResponseWriter, r *http.Request) {
+		var body map[string]interface{}
+		json.NewDecoder(r.Body).Decode(&body)
+		updatedSessionData = body["userDataInDatabase"].(map[string]interface{})
+		json.NewEncoder(rw).Encode(map[string]interface{}{"status": "OK"})
+	})
+	testServer := httptest.NewServer(mux)
+	defer testServer.Close()
+
+	resetAll()
+	defer resetAll()
+	err := supertokens.Init(supertokens.TypeInput{
+		Supertokens: &supertokens.ConnectionInfo{
+			ConnectionURI: testServer.URL,
+		},
+		AppInfo: supertokens.AppInfo{
+			AppName:       "SuperTokens",
+			WebsiteDomain: "supertokens.io",
+			APIDomain:     "api.supertokens.io",
+		},
+		RecipeList: []supertokens.Recipe{Init(nil)},
+	})
+	assert.NoError(t, err)
+
+	ok, err := MergeIntoSessionDataInDatabase("handle1", map[string]interface{}{"plan": "pro", "cart": nil})
+	assert.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, map[string]interface{}{"plan": "pro", "theme": "dark"}, updatedSessionData)
+
+	updatedSessionData = nil
+	ok, err = MergeIntoSessionDataInDatabase("handle2", map[string]interface{}{"plan": "pro"})
+	assert.NoError(t, err)
+	assert.False(t, ok)
+	assert.Nil(t, updatedSessionData)
+}
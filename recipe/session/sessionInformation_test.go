@@ -0,0 +1,84 @@
+/* Copyright (c) 2023, VRAI Labs and/or its affiliates. All rights reserved.
+ *
+ * This software is licensed under the Apache License, Version 2.0 (the
+ * "License") as published by the Apache Software Foundation.
+ *
+ * You may not use this file except in compliance with the License. You may
+ * obtain a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+ * License for the specific language governing permissions and limitations
+ * under the License.
+ */
+
+package session
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/supertokens/supertokens-golang/supertokens"
+)
+
+func TestGetSessionInformationByHandle(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/apiversion", func(rw http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(rw).Encode(map[string]interface{}{"versions": []string{"3.0"}})
+	})
+	mux.HandleFunc("/", func(rw http.ResponseWriter, r *http.Request) {
+		if strings.HasSuffix(r.URL.Path, "/recipe/session") && r.Method == http.MethodGet {
+			if r.URL.Query().Get("sessionHandle") != "handle1" {
+				json.NewEncoder(rw).Encode(map[string]interface{}{"status": "UNAUTHORISED"})
+				return
+			}
+			json.NewEncoder(rw).Encode(map[string]interface{}{
+				"status":             "OK",
+				"sessionHandle":      "handle1",
+				"userId":             "user1",
+				"tenantId":           "public",
+				"userDataInDatabase": map[string]interface{}{"cart": "3"},
+				"userDataInJWT":      map[string]interface{}{"role": "admin"},
+				"expiry":             2000,
+				"timeCreated":        1000,
+			})
+			return
+		}
+		rw.WriteHeader(http.StatusNotFound)
+	})
+	testServer := httptest.NewServer(mux)
+	defer testServer.Close()
+
+	resetAll()
+	defer resetAll()
+	err := supertokens.Init(supertokens.TypeInput{
+		Supertokens: &supertokens.ConnectionInfo{
+			ConnectionURI: testServer.URL,
+		},
+		AppInfo: supertokens.AppInfo{
+			AppName:       "SuperTokens",
+			WebsiteDomain: "supertokens.io",
+			APIDomain:     "api.supertokens.io",
+		},
+		RecipeList: []supertokens.Recipe{Init(nil)},
+	})
+	assert.NoError(t, err)
+
+	sessionInformation, err := GetSessionInformation("handle1")
+	assert.NoError(t, err)
+	assert.Equal(t, "user1", sessionInformation.UserId)
+	assert.Equal(t, "public", sessionInformation.TenantId)
+	assert.Equal(t, map[string]interface{}{"cart": "3"}, sessionInformation.SessionDataInDatabase)
+	assert.Equal(t, map[string]interface{}{"role": "admin"}, sessionInformation.CustomClaimsInAccessTokenPayload)
+	assert.Equal(t, uint64(2000), sessionInformation.Expiry)
+	assert.Equal(t, uint64(1000), sessionInformation.TimeCreated)
+
+	sessionInformation, err = GetSessionInformation("unknown")
+	assert.NoError(t, err)
+	assert.Nil(t, sessionInformation)
+}
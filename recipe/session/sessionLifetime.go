@@ -0,0 +1,201 @@
+/* Copyright (c) 2023, VRAI Labs and/or its affiliates. All rights reserved.
+ *
+ * This software is licensed under the Apache License, Version 2.0 (the
+ * "License") as published by the Apache Software Foundation.
+ *
+ * You may not use this file except in compliance with the License. You may
+ * obtain a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+ * License for the specific language governing permissions and limitations
+ * under the License.
+ */
+
+package session
+
+import (
+	"errors"
+	"time"
+
+	sessionErrors "github.com/supertokens/supertokens-golang/recipe/session/errors"
+	"github.com/supertokens/supertokens-golang/recipe/session/sessmodels"
+	"github.com/supertokens/supertokens-golang/supertokens"
+)
+
+// sessionLifetimeKey is the access token payload key holding the lifetime
+// set with WithSessionLifetime
+const sessionLifetimeKey = "st-lt"
+
+// WithSessionLifetime returns a copy of accessTokenPayload that makes the
+// session created with it shorter lived than the validity configured in the
+// core, e.g. for admin consoles:
+//
+//	validity := 5 * time.Minute
+//	payload, err := session.WithSessionLifetime(nil, sessmodels.SessionLifetime{AccessTokenValidity: &validity})
+//	sessionContainer, err := session.CreateNewSession(req, res, tenantId, userID, payload, nil)
+//
+// Access tokens older than AccessTokenValidity are rejected with a try
+// refresh token error, and the session is revoked when it is refreshed after
+// RefreshTokenLifetime. Lifetimes longer than the core's have no effect.
+// Lifetimes already in accessTokenPayload (e.g. from WithRememberMe) are kept
+// unless they are set again.
+func WithSessionLifetime(accessTokenPayload map[string]interface{}, lifetime sessmodels.SessionLifetime) (map[string]interface{}, error) {
+	sessionLifetime := map[string]interface{}{}
+	if existing, ok := accessTokenPayload[sessionLifetimeKey].(map[string]interface{}); ok {
+		for k, v := range existing {
+			sessionLifetime[k] = v
+		}
+	}
+	if lifetime.AccessTokenValidity != nil {
+		if *lifetime.AccessTokenValidity < time.Second {
+			return nil, errors.New("AccessTokenValidity must be at least one second")
+		}
+		sessionLifetime["at"] = int64(lifetime.AccessTokenValidity.Seconds())
+	}
+	if lifetime.RefreshTokenLifetime != nil {
+		if *lifetime.RefreshTokenLifetime <= 0 {
+			return nil, errors.New("RefreshTokenLifetime must be positive")
+		}
+		sessionLifetime["exp"] = time.Now().Add(*lifetime.RefreshTokenLifetime).UnixMilli()
+	}
+	result := map[string]interface{}{}
+	for k, v := range accessTokenPayload {
+		result[k] = v
+	}
+	result[sessionLifetimeKey] = sessionLifetime
+	return result, nil
+}
+
+func getSessionLifetimeValue(accessTokenPayload map[string]interface{}, key string) (int64, bool) {
+	sessionLifetime, ok := accessTokenPayload[sessionLifetimeKey].(map[string]interface{})
+	if !ok {
+		return 0, false
+	}
+	// the values are int64 when set locally and float64 once they come back
+	// from the core
+	switch value := sessionLifetime[key].(type) {
+	case float64:
+		return int64(value), true
+	case int64:
+		return value, true
+	}
+	return 0, false
+}
+
+func checkAccessTokenLifetime(accessTokenPayload map[string]interface{}) error {
+	validityInSeconds, ok := getSessionLifetimeValue(accessTokenPayload, "at")
+	if !ok {
+		return nil
+	}
+	issuedAt, ok := accessTokenPayload["iat"].(float64)
+	if !ok {
+		return nil
+	}
+	if time.Now().Unix() < int64(issuedAt)+validityInSeconds {
+		return nil
+	}
+	supertokens.LogDebugMessage("getSession: Returning TRY_REFRESH_TOKEN because the access token is older than the lifetime of the session")
+	return sessionErrors.TryRefreshTokenError{Msg: "access token expired"}
+}
+
+func checkRefreshTokenLifetime(querier supertokens.Querier, sessionHandle string, accessTokenPayload map[string]interface{}, userContext supertokens.UserContext) error {
+	expiresAt, ok := getSessionLifetimeValue(accessTokenPayload, "exp")
+	if !ok || time.Now().UnixMilli() < expiresAt {
+		return nil
+	}
+	supertokens.LogDebugMessage("refreshSession: Revoking session because it is older than its refresh token lifetime")
+	_, err := revokeSessionHelper(querier, sessionHandle, userContext)
+	if err != nil {
+		return err
+	}
+	clearTokens := true
+	return sessionErrors.UnauthorizedError{Msg: "session expired", ClearTokens: &clearTokens}
+}
+
+// sessionExpirationKey is the access token payload key holding the
+// expiration strategy chosen for a single session
+const sessionExpirationKey = "st-exps"
+
+func normaliseSessionExpirationInput(config *sessmodels.SessionExpirationInput) (sessmodels.NormalisedSessionExpirationConfig, error) {
+	result := sessmodels.NormalisedSessionExpirationConfig{
+		Strategy: SessionExpiration_SLIDING,
+	}
+	if config == nil {
+		return result, nil
+	}
+	if config.Strategy != nil {
+		if *config.Strategy != SessionExpiration_SLIDING && *config.Strategy != SessionExpiration_ABSOLUTE {
+			return sessmodels.NormalisedSessionExpirationConfig{}, errors.New("SessionExpiration Strategy must be either SLIDING or ABSOLUTE")
+		}
+		result.Strategy = *config.Strategy
+	}
+	if result.Strategy == SessionExpiration_ABSOLUTE {
+		if config.MaxSessionAge == nil || *config.MaxSessionAge <= 0 {
+			return sessmodels.NormalisedSessionExpirationConfig{}, errors.New("SessionExpiration MaxSessionAge must be positive when using the ABSOLUTE strategy")
+		}
+		result.MaxSessionAge = *config.MaxSessionAge
+	}
+	return result, nil
+}
+
+func setSessionExpiresAt(accessTokenPayload map[string]interface{}, expiresAt time.Time) map[string]interface{} {
+	sessionLifetime := map[string]interface{}{}
+	if existing, ok := accessTokenPayload[sessionLifetimeKey].(map[string]interface{}); ok {
+		for k, v := range existing {
+			sessionLifetime[k] = v
+		}
+	}
+	sessionLifetime["exp"] = expiresAt.UnixMilli()
+
+	result := map[string]interface{}{}
+	for k, v := range accessTokenPayload {
+		result[k] = v
+	}
+	result[sessionLifetimeKey] = sessionLifetime
+	return result
+}
+
+// WithSlidingExpiration returns a copy of accessTokenPayload for a session
+// whose lifetime is extended by refreshes, even if the ABSOLUTE strategy is
+// configured. Lifetimes set with WithSessionLifetime or WithRememberMe still
+// apply.
+func WithSlidingExpiration(accessTokenPayload map[string]interface{}) map[string]interface{} {
+	result := map[string]interface{}{}
+	for k, v := range accessTokenPayload {
+		result[k] = v
+	}
+	result[sessionExpirationKey] = SessionExpiration_SLIDING
+	return result
+}
+
+// WithAbsoluteExpiration returns a copy of accessTokenPayload for a session
+// that cannot be refreshed once maxSessionAge has passed since it was
+// created, whatever the configured strategy.
+func WithAbsoluteExpiration(accessTokenPayload map[string]interface{}, maxSessionAge time.Duration) (map[string]interface{}, error) {
+	if maxSessionAge <= 0 {
+		return nil, errors.New("maxSessionAge must be positive")
+	}
+	result := setSessionExpiresAt(accessTokenPayload, time.Now().Add(maxSessionAge))
+	result[sessionExpirationKey] = SessionExpiration_ABSOLUTE
+	return result, nil
+}
+
+// addSessionExpiration caps the lifetime of a new session at MaxSessionAge
+// when the ABSOLUTE strategy applies to it. An earlier end set with
+// WithSessionLifetime, WithRememberMe or WithAbsoluteExpiration is kept.
+func addSessionExpiration(config sessmodels.TypeNormalisedInput, accessTokenPayload map[string]interface{}) map[string]interface{} {
+	strategy := config.SessionExpiration.Strategy
+	if sessionStrategy, ok := accessTokenPayload[sessionExpirationKey].(string); ok {
+		strategy = sessionStrategy
+	}
+	if strategy != SessionExpiration_ABSOLUTE || config.SessionExpiration.MaxSessionAge == 0 {
+		return accessTokenPayload
+	}
+	expiresAt := time.Now().Add(config.SessionExpiration.MaxSessionAge)
+	if existing, ok := getSessionLifetimeValue(accessTokenPayload, "exp"); ok && existing <= expiresAt.UnixMilli() {
+		return accessTokenPayload
+	}
+	return setSessionExpiresAt(accessTokenPayload, expiresAt)
+}
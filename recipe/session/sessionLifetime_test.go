@@ -0,0 +1,205 @@
+/* Copyright (c) 2023, VRAI Labs and/or its affiliates. All rights reserved.
+ *
+ * This software is licensed under the Apache License, Version 2.0 (the
+ * "License") as published by the Apache Software Foundation.
+ *
+ * You may not use this file except in compliance with the License. You may
+ * obtain a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+ * License for the specific language governing permissions and limitations
+ * under the License.
+ */
+
+package session
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	sessionErrors "github.com/supertokens/supertokens-golang/recipe/session/errors"
+	"github.com/supertokens/supertokens-golang/recipe/session/sessmodels"
+	"github.com/supertokens/supertokens-golang/supertokens"
+)
+
+// payloadFromCore goes through JSON like payloads that come back from the core
+func payloadFromCore(t *testing.T, payload map[string]interface{}) map[string]interface{} {
+	payloadJSON, err := json.Marshal(payload)
+	assert.NoError(t, err)
+	result := map[string]interface{}{}
+	assert.NoError(t, json.Unmarshal(payloadJSON, &result))
+	return result
+}
+
+func TestWithSessionLifetime(t *testing.T) {
+	validity := time.Minute
+	payload, err := WithSessionLifetime(map[string]interface{}{"key": "value"}, sessmodels.SessionLifetime{AccessTokenValidity: &validity})
+	assert.NoError(t, err)
+	assert.Equal(t, "value", payload["key"])
+
+	payload = payloadFromCore(t, payload)
+	payload["iat"] = float64(time.Now().Unix())
+	assert.NoError(t, checkAccessTokenLifetime(payload))
+	payload["iat"] = float64(time.Now().Add(-2 * time.Minute).Unix())
+	assert.IsType(t, sessionErrors.TryRefreshTokenError{}, checkAccessTokenLifetime(payload))
+
+	// sessions without a lifetime are not affected
+	assert.NoError(t, checkAccessTokenLifetime(map[string]interface{}{"iat": float64(0)}))
+
+	tooShort := time.Millisecond
+	_, err = WithSessionLifetime(nil, sessmodels.SessionLifetime{AccessTokenValidity: &tooShort})
+	assert.Error(t, err)
+	negative := -time.Hour
+	_, err = WithSessionLifetime(nil, sessmodels.SessionLifetime{RefreshTokenLifetime: &negative})
+	assert.Error(t, err)
+}
+
+func TestWithSessionLifetimeKeepsTheLifetimeOfWithRememberMe(t *testing.T) {
+	resetAll()
+	defer resetAll()
+	err := supertokens.Init(supertokens.TypeInput{
+		Supertokens: &supertokens.ConnectionInfo{
+			ConnectionURI: "http://localhost:8080",
+		},
+		AppInfo: supertokens.AppInfo{
+			AppName:       "SuperTokens",
+			WebsiteDomain: "supertokens.io",
+			APIDomain:     "api.supertokens.io",
+		},
+		RecipeList: []supertokens.Recipe{Init(nil)},
+	})
+	assert.NoError(t, err)
+
+	payload, err := WithRememberMe(nil, false)
+	assert.NoError(t, err)
+	expiresAt := payload[sessionLifetimeKey].(map[string]interface{})["exp"]
+	validity := time.Minute
+	payload, err = WithSessionLifetime(payload, sessmodels.SessionLifetime{AccessTokenValidity: &validity})
+	assert.NoError(t, err)
+
+	assert.Equal(t, false, payload[rememberMeKey])
+	assert.Equal(t, map[string]interface{}{"at": int64(60), "exp": expiresAt}, payload[sessionLifetimeKey])
+}
+
+func TestSessionsAreRevokedWhenRefreshedAfterTheirLifetime(t *testing.T) {
+	revoked := []interface{}{}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/apiversion", func(rw http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(rw).Encode(map[string]interface{}{"versions": []string{"3.0"}})
+	})
+	mux.HandleFunc("/recipe/session/remove", func(rw http.ResponseWriter, r *http.Request) {
+		var body map[string]interface{}
+		json.NewDecoder(r.Body).Decode(&body)
+		revoked = append(revoked, body["sessionHandles"].([]interface{})...)
+		json.NewEncoder(rw).Encode(map[string]interface{}{"status": "OK", "sessionHandlesRevoked": body["sessionHandles"]})
+	})
+	testServer := httptest.NewServer(mux)
+	defer testServer.Close()
+
+	resetAll()
+	defer resetAll()
+	err := supertokens.Init(supertokens.TypeInput{
+		Supertokens: &supertokens.ConnectionInfo{
+			ConnectionURI: testServer.URL,
+		},
+		AppInfo: supertokens.AppInfo{
+			AppName:       "SuperTokens",
+			WebsiteDomain: "supertokens.io",
+			APIDomain:     "api.supertokens.io",
+		},
+		RecipeList: []supertokens.Recipe{Init(nil)},
+	})
+	assert.NoError(t, err)
+	querier, err := supertokens.GetNewQuerierInstanceOrThrowError("session")
+	assert.NoError(t, err)
+
+	lifetime := time.Hour
+	payload, err := WithSessionLifetime(nil, sessmodels.SessionLifetime{RefreshTokenLifetime: &lifetime})
+	assert.NoError(t, err)
+	payload = payloadFromCore(t, payload)
+	assert.NoError(t, checkRefreshTokenLifetime(*querier, "handle1", payload, &map[string]interface{}{}))
+	assert.NoError(t, checkRefreshTokenLifetime(*querier, "handle1", map[string]interface{}{}, &map[string]interface{}{}))
+	assert.Empty(t, revoked)
+
+	payload[sessionLifetimeKey].(map[string]interface{})["exp"] = float64(time.Now().Add(-time.Second).UnixMilli())
+	err = checkRefreshTokenLifetime(*querier, "handle1", payload, &map[string]interface{}{})
+	assert.IsType(t, sessionErrors.UnauthorizedError{}, err)
+	assert.True(t, *err.(sessionErrors.UnauthorizedError).ClearTokens)
+	assert.Equal(t, []interface{}{"handle1"}, revoked)
+}
+
+func TestSessionExpirationConfig(t *testing.T) {
+	config, err := normaliseSessionExpirationInput(nil)
+	assert.NoError(t, err)
+	assert.Equal(t, SessionExpiration_SLIDING, config.Strategy)
+
+	strategy := "FIXED"
+	_, err = normaliseSessionExpirationInput(&sessmodels.SessionExpirationInput{Strategy: &strategy})
+	assert.Error(t, err)
+
+	strategy = SessionExpiration_ABSOLUTE
+	_, err = normaliseSessionExpirationInput(&sessmodels.SessionExpirationInput{Strategy: &strategy})
+	assert.Error(t, err)
+
+	maxSessionAge := 8 * time.Hour
+	config, err = normaliseSessionExpirationInput(&sessmodels.SessionExpirationInput{Strategy: &strategy, MaxSessionAge: &maxSessionAge})
+	assert.NoError(t, err)
+	assert.Equal(t, maxSessionAge, config.MaxSessionAge)
+}
+
+func TestAbsoluteExpirationCapsTheSessionLifetime(t *testing.T) {
+	strategy := SessionExpiration_ABSOLUTE
+	maxSessionAge := 8 * time.Hour
+	sessionExpiration, err := normaliseSessionExpirationInput(&sessmodels.SessionExpirationInput{Strategy: &strategy, MaxSessionAge: &maxSessionAge})
+	assert.NoError(t, err)
+	config := sessmodels.TypeNormalisedInput{SessionExpiration: sessionExpiration}
+
+	payload := payloadFromCore(t, addSessionExpiration(config, map[string]interface{}{"key": "value"}))
+	assert.Equal(t, "value", payload["key"])
+	expiresAt, ok := getSessionLifetimeValue(payload, "exp")
+	assert.True(t, ok)
+	assert.InDelta(t, time.Now().Add(maxSessionAge).UnixMilli(), expiresAt, 1000)
+
+	// a shorter lifetime set for the session is kept
+	shorter, err := WithAbsoluteExpiration(nil, time.Hour)
+	assert.NoError(t, err)
+	payload = payloadFromCore(t, addSessionExpiration(config, shorter))
+	expiresAt, _ = getSessionLifetimeValue(payload, "exp")
+	assert.InDelta(t, time.Now().Add(time.Hour).UnixMilli(), expiresAt, 1000)
+
+	// a longer one is capped
+	longer, err := WithAbsoluteExpiration(nil, 24*time.Hour)
+	assert.NoError(t, err)
+	payload = payloadFromCore(t, addSessionExpiration(config, longer))
+	expiresAt, _ = getSessionLifetimeValue(payload, "exp")
+	assert.InDelta(t, time.Now().Add(maxSessionAge).UnixMilli(), expiresAt, 1000)
+
+	payload = addSessionExpiration(config, WithSlidingExpiration(nil))
+	_, ok = getSessionLifetimeValue(payload, "exp")
+	assert.False(t, ok)
+}
+
+func TestSlidingExpirationIsTheDefault(t *testing.T) {
+	sessionExpiration, err := normaliseSessionExpirationInput(nil)
+	assert.NoError(t, err)
+	config := sessmodels.TypeNormalisedInput{SessionExpiration: sessionExpiration}
+
+	payload := addSessionExpiration(config, map[string]interface{}{})
+	_, ok := getSessionLifetimeValue(payload, "exp")
+	assert.False(t, ok)
+
+	payload, err = WithAbsoluteExpiration(map[string]interface{}{}, time.Hour)
+	assert.NoError(t, err)
+	payload = addSessionExpiration(config, payload)
+	_, ok = getSessionLifetimeValue(payload, "exp")
+	assert.True(t, ok)
+
+	_, err = WithAbsoluteExpiration(nil, 0)
+	assert.Error(t, err)
+}
@@ -31,23 +31,41 @@ import (
 // We are defining this here to reduce the scope of legacy code
 const legacyIdRefreshTokenCookieName = "sIdRefreshToken"
 
-func CreateNewSessionInRequest(req *http.Request, res http.ResponseWriter, tenantId string, config sessmodels.TypeNormalisedInput, appInfo supertokens.NormalisedAppinfo, recipeInstance Recipe, recipeImpl sessmodels.RecipeInterface, userID string, accessTokenPayload map[string]interface{}, sessionDataInDatabase map[string]interface{}, userContext supertokens.UserContext) (sessmodels.SessionContainer, error) {
-	supertokens.LogDebugMessage("createNewSession: Started")
-
-	claimsAddedByOtherRecipes := recipeInstance.GetClaimsAddedByOtherRecipes()
-	finalAccessTokenPayload := accessTokenPayload
-	if finalAccessTokenPayload == nil {
-		finalAccessTokenPayload = map[string]interface{}{}
+// buildAccessTokenPayload adds the configured static claims, iss and aud to
+// the payload of a new session, removes the claims set by the core and adds
+// the claims of other recipes
+func buildAccessTokenPayload(config sessmodels.TypeNormalisedInput, appInfo supertokens.NormalisedAppinfo, recipeInstance Recipe, tenantId string, userID string, accessTokenPayload map[string]interface{}, userContext supertokens.UserContext) (map[string]interface{}, error) {
+	finalAccessTokenPayload := map[string]interface{}{}
+	for k, v := range config.AccessTokenStaticClaims {
+		finalAccessTokenPayload[k] = v
+	}
+	for k, v := range accessTokenPayload {
+		finalAccessTokenPayload[k] = v
 	}
 
-	issuer := appInfo.APIDomain.GetAsStringDangerous() + appInfo.APIBasePath.GetAsStringDangerous()
+	issuer := config.AccessTokenIssuer
+	if issuer == "" {
+		issuer = appInfo.APIDomain.GetAsStringDangerous() + appInfo.APIBasePath.GetAsStringDangerous()
+	}
 	finalAccessTokenPayload["iss"] = issuer
 
+	if len(config.AccessTokenAudience) == 1 {
+		finalAccessTokenPayload["aud"] = config.AccessTokenAudience[0]
+	} else if len(config.AccessTokenAudience) > 1 {
+		finalAccessTokenPayload["aud"] = config.AccessTokenAudience
+	}
+
 	for _, protectedProp := range protectedProps {
 		delete(finalAccessTokenPayload, protectedProp)
 	}
 
-	for _, claim := range claimsAddedByOtherRecipes {
+	// guest sessions do not get the claims of other recipes, since their
+	// user does not exist
+	if isGuestSessionPayload(finalAccessTokenPayload) {
+		return finalAccessTokenPayload, nil
+	}
+
+	for _, claim := range recipeInstance.GetClaimsAddedByOtherRecipes() {
 		_finalAccessTokenPayload, err := claim.Build(userID, tenantId, finalAccessTokenPayload, userContext)
 		if err != nil {
 			return nil, err
@@ -55,6 +73,22 @@ func CreateNewSessionInRequest(req *http.Request, res http.ResponseWriter, tenan
 
 		finalAccessTokenPayload = _finalAccessTokenPayload
 	}
+	return finalAccessTokenPayload, nil
+}
+
+func CreateNewSessionInRequest(req *http.Request, res http.ResponseWriter, tenantId string, config sessmodels.TypeNormalisedInput, appInfo supertokens.NormalisedAppinfo, recipeInstance Recipe, recipeImpl sessmodels.RecipeInterface, userID string, accessTokenPayload map[string]interface{}, sessionDataInDatabase map[string]interface{}, userContext supertokens.UserContext) (sessmodels.SessionContainer, error) {
+	supertokens.LogDebugMessage("createNewSession: Started")
+	userContext = supertokens.SetRequestInUserContextIfNotDefined(userContext, req)
+
+	accessTokenPayload, sessionDataInDatabase, err := upgradeGuestSession(config, req, recipeImpl, userID, accessTokenPayload, sessionDataInDatabase, userContext)
+	if err != nil {
+		return nil, err
+	}
+
+	finalAccessTokenPayload, err := buildAccessTokenPayload(config, appInfo, recipeInstance, tenantId, userID, accessTokenPayload, userContext)
+	if err != nil {
+		return nil, err
+	}
 
 	supertokens.LogDebugMessage("createNewSession: Access token payload built")
 
@@ -95,6 +129,10 @@ func CreateNewSessionInRequest(req *http.Request, res http.ResponseWriter, tenan
 		return nil, defaultErrors.New("Since your API and website domain are different, for sessions to work, please use https on your apiDomain and dont set cookieSecure to false.")
 	}
 
+	if config.RevokeExistingSessionOnCreate {
+		revokeExistingSession(config, req, recipeImpl, userContext)
+	}
+
 	disableAntiCSRF := outputTokenTransferMethod == sessmodels.HeaderTransferMethod
 
 	sessionResponse, err := (*recipeImpl.CreateNewSession)(userID, finalAccessTokenPayload, sessionDataInDatabase, &disableAntiCSRF, tenantId, userContext)
@@ -105,9 +143,9 @@ func CreateNewSessionInRequest(req *http.Request, res http.ResponseWriter, tenan
 
 	supertokens.LogDebugMessage("createNewSession: Session created in core built")
 
-	for _, tokenTransferMethod := range AvailableTokenTransferMethods {
+	for _, tokenTransferMethod := range getAvailableTokenTransferMethods(config) {
 		if tokenTransferMethod != outputTokenTransferMethod {
-			token, err := GetToken(req, sessmodels.AccessToken, tokenTransferMethod)
+			token, err := getToken(config, req, sessmodels.AccessToken, tokenTransferMethod)
 			if err != nil {
 				return nil, err
 			}
@@ -129,6 +167,38 @@ func CreateNewSessionInRequest(req *http.Request, res http.ResponseWriter, tenan
 	return sessionResponse, nil
 }
 
+// revokeExistingSession revokes the session of the access token sent with a
+// request that creates a new session. Tokens that cannot be verified are
+// left alone, since they are replaced by the new session's tokens anyway,
+// and failures are only logged so that they do not prevent signing in.
+func revokeExistingSession(config sessmodels.TypeNormalisedInput, req *http.Request, recipeImpl sessmodels.RecipeInterface, userContext supertokens.UserContext) {
+	False := false
+	revokedSessionHandles := map[string]bool{}
+	for _, tokenTransferMethod := range getAvailableTokenTransferMethods(config) {
+		token, err := getToken(config, req, sessmodels.AccessToken, tokenTransferMethod)
+		if err != nil || token == nil {
+			continue
+		}
+		existingSession, err := (*recipeImpl.GetSession)(token, nil, &sessmodels.VerifySessionOptions{
+			AntiCsrfCheck:   &False,
+			SessionRequired: &False,
+		}, userContext)
+		if err != nil || existingSession == nil {
+			continue
+		}
+		sessionHandle := existingSession.GetHandleWithContext(userContext)
+		if revokedSessionHandles[sessionHandle] {
+			continue
+		}
+		revokedSessionHandles[sessionHandle] = true
+		supertokens.LogDebugMessage("createNewSession: Revoking the session that the request already had")
+		_, err = (*recipeImpl.RevokeSession)(sessionHandle, userContext)
+		if err != nil {
+			supertokens.LogDebugMessage("createNewSession: Failed to revoke the existing session: " + err.Error())
+		}
+	}
+}
+
 func GetSessionFromRequest(req *http.Request, res http.ResponseWriter, config sessmodels.TypeNormalisedInput, options *sessmodels.VerifySessionOptions, recipeImpl sessmodels.RecipeInterface, userContext supertokens.UserContext) (sessmodels.SessionContainer, error) {
 	idRefreshToken := GetCookieValue(req, legacyIdRefreshTokenCookieName)
 	if idRefreshToken != nil {
@@ -144,8 +214,8 @@ func GetSessionFromRequest(req *http.Request, res http.ResponseWriter, config se
 	accessTokens := map[sessmodels.TokenTransferMethod]*sessmodels.ParsedJWTInfo{}
 
 	// We check all token transfer methods for available access tokens
-	for _, tokenTransferMethod := range AvailableTokenTransferMethods {
-		token, err := GetToken(req, sessmodels.AccessToken, tokenTransferMethod)
+	for _, tokenTransferMethod := range getAvailableTokenTransferMethods(config) {
+		token, err := getToken(config, req, sessmodels.AccessToken, tokenTransferMethod)
 		if err != nil {
 			return nil, err
 		}
@@ -275,6 +345,13 @@ func GetSessionFromRequest(req *http.Request, res http.ResponseWriter, config se
 			return nil, err
 		}
 
+		if options != nil && options.Authorize != nil {
+			err = options.Authorize(sessionResult, userContext)
+			if err != nil {
+				return nil, err
+			}
+		}
+
 		// requestTransferMethod can only be nil here if the user has overridden GetSession
 		// to load the session by a custom method in that (very niche) case they also need to
 		// override how the session is attached to the response.
@@ -308,8 +385,8 @@ func RefreshSessionInRequest(req *http.Request, res http.ResponseWriter, config
 	refreshTokens := map[sessmodels.TokenTransferMethod]*string{}
 	// We check all token transfer methods for available refresh tokens
 	// We do this so that we can later clear all we are not overwriting
-	for _, tokenTransferMethod := range AvailableTokenTransferMethods {
-		token, err := GetToken(req, sessmodels.RefreshToken, tokenTransferMethod)
+	for _, tokenTransferMethod := range getAvailableTokenTransferMethods(config) {
+		token, err := getToken(config, req, sessmodels.RefreshToken, tokenTransferMethod)
 		if err != nil {
 			return nil, err
 		}
@@ -348,7 +425,9 @@ func RefreshSessionInRequest(req *http.Request, res http.ResponseWriter, config
 	}
 
 	antiCsrfToken := GetAntiCsrfTokenFromHeaders(req)
-	disableAntiCSRF := requestTokenTransferMethod == sessmodels.HeaderTransferMethod
+	// refresh tokens of hybrid sessions are sent in cookies, so they need the
+	// anti-csrf checks of cookie based sessions
+	disableAntiCSRF := requestTokenTransferMethod == sessmodels.HeaderTransferMethod && !config.HybridTokenTransfer
 	antiCsrf := config.AntiCsrfFunctionOrString.StrValue
 	if antiCsrf == "" {
 		antiCsrfTemp, err := config.AntiCsrfFunctionOrString.FunctionValue(req, userContext)
@@ -373,6 +452,15 @@ func RefreshSessionInRequest(req *http.Request, res http.ResponseWriter, config
 		disableAntiCSRF = true
 	}
 
+	err := checkRefreshIPLimit(config, req, userContext)
+	if err != nil {
+		return nil, err
+	}
+	err = checkRefreshSessionLimit(config, *refreshToken, userContext)
+	if err != nil {
+		return nil, err
+	}
+
 	result, err := (*recipeImpl.RefreshSession)(*refreshToken, antiCsrfToken, disableAntiCSRF, userContext)
 
 	if err != nil {
@@ -390,14 +478,28 @@ func RefreshSessionInRequest(req *http.Request, res http.ResponseWriter, config
 
 		if isUnauthorisedErr {
 			supertokens.LogDebugMessage("RefreshSessionInRequest: Returning UnauthorizedError because RefreshSession returned an error")
+			callOnSessionExpired(config, req, requestTokenTransferMethod, userContext)
 		}
 
+		auditEntry := supertokens.AuditEntry{
+			Action:   supertokens.AuditActionSessionRefresh,
+			Result:   supertokens.AuditResultFailure,
+			Reason:   err.Error(),
+			RecipeID: RECIPE_ID,
+		}
+		if isTokenTheftDetectedErr {
+			auditEntry.Reason = "TOKEN_THEFT_DETECTED"
+		} else if isUnauthorisedErr {
+			auditEntry.Reason = "UNAUTHORISED"
+		}
+		supertokens.WriteAuditLog(auditEntry, userContext)
+
 		return nil, err
 	}
 
 	supertokens.LogDebugMessage("refreshSession: Attaching refreshed session info as " + string(requestTokenTransferMethod))
 
-	for _, tokenTransferMethod := range AvailableTokenTransferMethods {
+	for _, tokenTransferMethod := range getAvailableTokenTransferMethods(config) {
 		if tokenTransferMethod != requestTokenTransferMethod && refreshTokens[tokenTransferMethod] != nil {
 			ClearSession(config, res, tokenTransferMethod, req, userContext)
 		}
@@ -416,5 +518,24 @@ func RefreshSessionInRequest(req *http.Request, res http.ResponseWriter, config
 		setCookie(config, res, legacyIdRefreshTokenCookieName, "", 0, "accessTokenPath", req, userContext)
 	}
 
+	supertokens.EmitEvent(supertokens.Event{
+		Type:     supertokens.EventSessionRefresh,
+		TenantId: (*result).GetTenantIdWithContext(userContext),
+		UserID:   (*result).GetUserIDWithContext(userContext),
+		Data: map[string]interface{}{
+			"sessionHandle": (*result).GetHandleWithContext(userContext),
+		},
+	}, userContext)
+	supertokens.WriteAuditLog(supertokens.AuditEntry{
+		Action:   supertokens.AuditActionSessionRefresh,
+		Result:   supertokens.AuditResultSuccess,
+		UserID:   (*result).GetUserIDWithContext(userContext),
+		TenantId: (*result).GetTenantIdWithContext(userContext),
+		RecipeID: RECIPE_ID,
+		Data: map[string]interface{}{
+			"sessionHandle": (*result).GetHandleWithContext(userContext),
+		},
+	}, userContext)
+
 	return result, nil
 }
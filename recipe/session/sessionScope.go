@@ -0,0 +1,100 @@
+/* Copyright (c) 2023, VRAI Labs and/or its affiliates. All rights reserved.
+ *
+ * This software is licensed under the Apache License, Version 2.0 (the
+ * "License") as published by the Apache Software Foundation.
+ *
+ * You may not use this file except in compliance with the License. You may
+ * obtain a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+ * License for the specific language governing permissions and limitations
+ * under the License.
+ */
+
+package session
+
+import (
+	"errors"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/supertokens/supertokens-golang/supertokens"
+	"golang.org/x/net/publicsuffix"
+)
+
+func isHostnameInSessionScope(hostname string, cookieDomain string) bool {
+	return hostname == cookieDomain || strings.HasSuffix(hostname, "."+cookieDomain)
+}
+
+// validateCookieDomain checks that browsers accept cookies for cookieDomain
+// from the API domain
+func validateCookieDomain(cookieDomain string, appInfo supertokens.NormalisedAppinfo) error {
+	apiURL, err := url.Parse(appInfo.APIDomain.GetAsStringDangerous())
+	if err != nil {
+		return err
+	}
+	apiHostname := apiURL.Hostname()
+	if apiHostname == "" {
+		return nil
+	}
+	if !isHostnameInSessionScope(apiHostname, cookieDomain) {
+		return errors.New("CookieDomain must be the domain of the APIDomain (" + apiHostname + ") or one of its parent domains, since browsers reject cookies for other domains")
+	}
+
+	isAnIP, err := supertokens.IsAnIPAddress(cookieDomain)
+	if err != nil {
+		return err
+	}
+	if isAnIP || cookieDomain == "localhost" {
+		return nil
+	}
+	publicSuffix, icann := publicsuffix.PublicSuffix(cookieDomain)
+	if icann && publicSuffix == cookieDomain {
+		return errors.New("CookieDomain cannot be a public suffix (" + cookieDomain + "), since browsers reject cookies for them")
+	}
+	return nil
+}
+
+// IsAllowedCORSOrigin reports whether credentialed CORS requests (with
+// cookies) from origin should be allowed: if it is the website domain or, if
+// CookieDomain is set to share sessions across subdomains, one of the
+// subdomains of CookieDomain with the same scheme as the website domain. It
+// can be used as the AllowOriginRequestFunc of github.com/rs/cors, together
+// with AllowCredentials and supertokens.GetAllCORSHeaders.
+func IsAllowedCORSOrigin(req *http.Request, origin string, userContext ...supertokens.UserContext) bool {
+	instance, err := getRecipeInstanceOrThrowError()
+	if err != nil {
+		return false
+	}
+	if len(userContext) == 0 {
+		userContext = append(userContext, &map[string]interface{}{})
+	}
+
+	websiteOrigin, err := instance.RecipeModule.GetAppInfo().GetOrigin(req, userContext[0])
+	if err != nil {
+		return false
+	}
+	normalisedOrigin, err := supertokens.NewNormalisedURLDomain(origin)
+	if err != nil {
+		return false
+	}
+	if normalisedOrigin.GetAsStringDangerous() == websiteOrigin.GetAsStringDangerous() {
+		return true
+	}
+
+	if instance.Config.CookieDomain == nil {
+		return false
+	}
+	originURL, err := url.Parse(normalisedOrigin.GetAsStringDangerous())
+	if err != nil {
+		return false
+	}
+	websiteURL, err := url.Parse(websiteOrigin.GetAsStringDangerous())
+	if err != nil {
+		return false
+	}
+	return originURL.Scheme == websiteURL.Scheme && isHostnameInSessionScope(originURL.Hostname(), *instance.Config.CookieDomain)
+}
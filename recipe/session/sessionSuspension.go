@@ -0,0 +1,114 @@
+/* Copyright (c) 2023, VRAI Labs and/or its affiliates. All rights reserved.
+ *
+ * This software is licensed under the Apache License, Version 2.0 (the
+ * "License") as published by the Apache Software Foundation.
+ *
+ * You may not use this file except in compliance with the License. You may
+ * obtain a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+ * License for the specific language governing permissions and limitations
+ * under the License.
+ */
+
+package session
+
+import (
+	"errors"
+	"net/http"
+	"sync"
+	"time"
+
+	sessionErrors "github.com/supertokens/supertokens-golang/recipe/session/errors"
+	"github.com/supertokens/supertokens-golang/recipe/session/sessmodels"
+	"github.com/supertokens/supertokens-golang/supertokens"
+)
+
+func normaliseSessionSuspensionInput(config *sessmodels.SessionSuspensionInput) (*sessmodels.NormalisedSessionSuspensionConfig, error) {
+	if config == nil {
+		return nil, nil
+	}
+	if config.Store != nil {
+		if config.Store.Suspend == nil || config.Store.Resume == nil || config.Store.GetSuspensionReason == nil {
+			return nil, errors.New("suspended session store must implement Suspend, Resume and GetSuspensionReason")
+		}
+		return &sessmodels.NormalisedSessionSuspensionConfig{Store: *config.Store}, nil
+	}
+	return &sessmodels.NormalisedSessionSuspensionConfig{Store: MakeInMemorySuspendedSessionStore()}, nil
+}
+
+// checkSessionSuspended is called after a session is verified
+func checkSessionSuspended(config sessmodels.TypeNormalisedInput, sessionHandle string, userID string, userContext supertokens.UserContext) error {
+	if config.SessionSuspension == nil {
+		return nil
+	}
+	reason, err := (*config.SessionSuspension.Store.GetSuspensionReason)(sessionHandle, userContext)
+	if err != nil || reason == nil {
+		return err
+	}
+	supertokens.LogDebugMessage("getSession: Returning SESSION_SUSPENDED because the session is suspended")
+	return sessionErrors.SessionSuspendedError{
+		SessionHandle: sessionHandle,
+		UserID:        userID,
+		Reason:        *reason,
+	}
+}
+
+func sendSessionSuspendedResponse(reason string, response http.ResponseWriter) error {
+	return supertokens.SendNon200Response(response, http.StatusForbidden, map[string]interface{}{
+		"message": "session suspended",
+		"reason":  reason,
+	})
+}
+
+// MakeInMemorySuspendedSessionStore returns a suspended session store that
+// keeps the suspensions in process memory.
+func MakeInMemorySuspendedSessionStore() sessmodels.SuspendedSessionStore {
+	type suspension struct {
+		reason    string
+		expiresAt time.Time
+	}
+	var mutex sync.Mutex
+	suspensions := map[string]suspension{}
+
+	suspend := func(sessionHandle string, reason string, expiresAt time.Time, userContext supertokens.UserContext) error {
+		mutex.Lock()
+		defer mutex.Unlock()
+
+		now := time.Now()
+		for handle, existing := range suspensions {
+			if !now.Before(existing.expiresAt) {
+				delete(suspensions, handle)
+			}
+		}
+		suspensions[sessionHandle] = suspension{reason: reason, expiresAt: expiresAt}
+		return nil
+	}
+
+	resume := func(sessionHandle string, userContext supertokens.UserContext) error {
+		mutex.Lock()
+		defer mutex.Unlock()
+
+		delete(suspensions, sessionHandle)
+		return nil
+	}
+
+	getSuspensionReason := func(sessionHandle string, userContext supertokens.UserContext) (*string, error) {
+		mutex.Lock()
+		defer mutex.Unlock()
+
+		existing, ok := suspensions[sessionHandle]
+		if !ok || !time.Now().Before(existing.expiresAt) {
+			return nil, nil
+		}
+		return &existing.reason, nil
+	}
+
+	return sessmodels.SuspendedSessionStore{
+		Suspend:             &suspend,
+		Resume:              &resume,
+		GetSuspensionReason: &getSuspensionReason,
+	}
+}
@@ -0,0 +1,120 @@
+/* Copyright (c) 2023, VRAI Labs and/or its affiliates. All rights reserved.
+ *
+ * This software is licensed under the Apache License, Version 2.0 (the
+ * "License") as published by the Apache Software Foundation.
+ *
+ * You may not use this file except in compliance with the License. You may
+ * obtain a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+ * License for the specific language governing permissions and limitations
+ * under the License.
+ */
+
+package session
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/supertokens/supertokens-golang/recipe/session/sessmodels"
+	"github.com/supertokens/supertokens-golang/supertokens"
+)
+
+func TestSessionSuspension(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/apiversion", func(rw http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(rw).Encode(map[string]interface{}{"versions": []string{"3.0"}})
+	})
+	mux.HandleFunc("/recipe/session", func(rw http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("sessionHandle") != "handle1" {
+			json.NewEncoder(rw).Encode(map[string]interface{}{"status": "UNAUTHORISED", "message": "Session does not exist."})
+			return
+		}
+		json.NewEncoder(rw).Encode(map[string]interface{}{
+			"status":             "OK",
+			"sessionHandle":      "handle1",
+			"userId":             "user1",
+			"userDataInDatabase": map[string]interface{}{},
+			"userDataInJWT":      map[string]interface{}{},
+			"expiry":             time.Now().Add(time.Hour).UnixMilli(),
+			"timeCreated":        time.Now().UnixMilli(),
+			"tenantId":           "public",
+		})
+	})
+	testServer := httptest.NewServer(mux)
+	defer testServer.Close()
+
+	resetAll()
+	defer resetAll()
+	err := supertokens.Init(supertokens.TypeInput{
+		Supertokens: &supertokens.ConnectionInfo{
+			ConnectionURI: testServer.URL,
+		},
+		AppInfo: supertokens.AppInfo{
+			AppName:       "SuperTokens",
+			WebsiteDomain: "supertokens.io",
+			APIDomain:     "api.supertokens.io",
+		},
+		RecipeList: []supertokens.Recipe{Init(&sessmodels.TypeInput{
+			SessionSuspension: &sessmodels.SessionSuspensionInput{},
+		})},
+	})
+	assert.NoError(t, err)
+	instance, err := getRecipeInstanceOrThrowError()
+	assert.NoError(t, err)
+	userContext := &map[string]interface{}{}
+
+	suspended, err := SuspendSession("missing", "account under review")
+	assert.NoError(t, err)
+	assert.False(t, suspended)
+
+	suspended, err = SuspendSession("handle1", "account under review")
+	assert.NoError(t, err)
+	assert.True(t, suspended)
+	isSuspended, err := IsSessionSuspended("handle1")
+	assert.NoError(t, err)
+	assert.True(t, isSuspended)
+
+	err = checkSessionSuspended(instance.Config, "handle1", "user1", userContext)
+	assert.True(t, errors.Is(err, ErrSessionSuspended))
+
+	res := httptest.NewRecorder()
+	handled, err := instance.handleError(err, httptest.NewRequest(http.MethodGet, "/", nil), res, userContext)
+	assert.True(t, handled)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusForbidden, res.Code)
+	var body map[string]interface{}
+	assert.NoError(t, json.NewDecoder(res.Body).Decode(&body))
+	assert.Equal(t, "account under review", body["reason"])
+	assert.Empty(t, res.Header().Values("Set-Cookie"))
+
+	err = ResumeSession("handle1")
+	assert.NoError(t, err)
+	err = checkSessionSuspended(instance.Config, "handle1", "user1", userContext)
+	assert.NoError(t, err)
+}
+
+func TestInMemorySuspendedSessionStoreExpiry(t *testing.T) {
+	store := MakeInMemorySuspendedSessionStore()
+	userContext := &map[string]interface{}{}
+
+	err := (*store.Suspend)("handle1", "payment hold", time.Now().Add(-time.Second), userContext)
+	assert.NoError(t, err)
+	reason, err := (*store.GetSuspensionReason)("handle1", userContext)
+	assert.NoError(t, err)
+	assert.Nil(t, reason)
+
+	err = (*store.Suspend)("handle2", "payment hold", time.Now().Add(time.Hour), userContext)
+	assert.NoError(t, err)
+	reason, err = (*store.GetSuspensionReason)("handle2", userContext)
+	assert.NoError(t, err)
+	assert.Equal(t, "payment hold", *reason)
+}
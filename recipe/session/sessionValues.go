@@ -0,0 +1,173 @@
+/* Copyright (c) 2023, VRAI Labs and/or its affiliates. All rights reserved.
+ *
+ * This software is licensed under the Apache License, Version 2.0 (the
+ * "License") as published by the Apache Software Foundation.
+ *
+ * You may not use this file except in compliance with the License. You may
+ * obtain a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+ * License for the specific language governing permissions and limitations
+ * under the License.
+ */
+
+package session
+
+import (
+	"errors"
+	"time"
+
+	"github.com/supertokens/supertokens-golang/supertokens"
+)
+
+// SetValue stores value under namespace / key in the session's data in
+// database. If ttl is not nil, the value stops being returned by GetValue
+// once it expires. Namespaces let different parts of an app keep data in the
+// same session without overwriting each other's keys.
+//
+// This does a read followed by a write of the session data, so concurrent
+// updates to the same session can overwrite each other.
+//
+// Returns false if the session does not exist.
+func SetValue[T any](sessionHandle string, namespace string, key string, value T, ttl *time.Duration, userContext ...supertokens.UserContext) (bool, error) {
+	return updateSessionValues(sessionHandle, func(sessionData map[string]interface{}) {
+		var expiresAt *int64
+		if ttl != nil {
+			expiresAtValue := time.Now().Add(*ttl).UnixMilli()
+			expiresAt = &expiresAtValue
+		}
+		setValueInSessionData(sessionData, namespace, key, value, expiresAt)
+	}, userContext...)
+}
+
+// GetValue returns the value stored under namespace / key by SetValue. The
+// second return value is false if there is no such value, or if it expired.
+func GetValue[T any](sessionHandle string, namespace string, key string, userContext ...supertokens.UserContext) (T, bool, error) {
+	var result T
+	sessionInfo, err := GetSessionInformation(sessionHandle, userContext...)
+	if err != nil || sessionInfo == nil {
+		return result, false, err
+	}
+	rawValue, ok := getValueFromSessionData(sessionInfo.SessionDataInDatabase, namespace, key, time.Now().UnixMilli())
+	if !ok {
+		return result, false, nil
+	}
+	// values come back from the core as generic JSON, so we go through JSON
+	// once more to get them into the caller's type
+	err = convertThroughJSON(rawValue, &result)
+	if err != nil {
+		return result, false, errors.New("the value stored under " + namespace + "/" + key + " does not match the requested type: " + err.Error())
+	}
+	return result, true, nil
+}
+
+// RemoveValue removes the value stored under namespace / key by SetValue.
+//
+// Returns false if the session does not exist.
+func RemoveValue(sessionHandle string, namespace string, key string, userContext ...supertokens.UserContext) (bool, error) {
+	return updateSessionValues(sessionHandle, func(sessionData map[string]interface{}) {
+		namespaceValues := getNamespaceValues(sessionData, namespace)
+		delete(namespaceValues, key)
+		if len(namespaceValues) == 0 {
+			delete(getAllSessionValues(sessionData), namespace)
+		}
+	}, userContext...)
+}
+
+func updateSessionValues(sessionHandle string, update func(sessionData map[string]interface{}), userContext ...supertokens.UserContext) (bool, error) {
+	sessionInfo, err := GetSessionInformation(sessionHandle, userContext...)
+	if err != nil || sessionInfo == nil {
+		return false, err
+	}
+	sessionData := sessionInfo.SessionDataInDatabase
+	if sessionData == nil {
+		sessionData = map[string]interface{}{}
+	}
+	update(sessionData)
+	removeExpiredValues(sessionData, time.Now().UnixMilli())
+	return UpdateSessionDataInDatabase(sessionHandle, sessionData, userContext...)
+}
+
+func getAllSessionValues(sessionData map[string]interface{}) map[string]interface{} {
+	values, ok := sessionData[sessionValuesKey].(map[string]interface{})
+	if !ok {
+		values = map[string]interface{}{}
+		sessionData[sessionValuesKey] = values
+	}
+	return values
+}
+
+func getNamespaceValues(sessionData map[string]interface{}, namespace string) map[string]interface{} {
+	allValues := getAllSessionValues(sessionData)
+	namespaceValues, ok := allValues[namespace].(map[string]interface{})
+	if !ok {
+		namespaceValues = map[string]interface{}{}
+		allValues[namespace] = namespaceValues
+	}
+	return namespaceValues
+}
+
+func setValueInSessionData(sessionData map[string]interface{}, namespace string, key string, value interface{}, expiresAt *int64) {
+	entry := map[string]interface{}{
+		"v": value,
+	}
+	if expiresAt != nil {
+		entry["exp"] = *expiresAt
+	}
+	getNamespaceValues(sessionData, namespace)[key] = entry
+}
+
+func getValueFromSessionData(sessionData map[string]interface{}, namespace string, key string, now int64) (interface{}, bool) {
+	allValues, ok := sessionData[sessionValuesKey].(map[string]interface{})
+	if !ok {
+		return nil, false
+	}
+	namespaceValues, ok := allValues[namespace].(map[string]interface{})
+	if !ok {
+		return nil, false
+	}
+	entry, ok := namespaceValues[key].(map[string]interface{})
+	if !ok || isSessionValueExpired(entry, now) {
+		return nil, false
+	}
+	value, ok := entry["v"]
+	return value, ok
+}
+
+func isSessionValueExpired(entry map[string]interface{}, now int64) bool {
+	// exp is an int64 when set locally and a float64 once it comes back from the core
+	switch exp := entry["exp"].(type) {
+	case int64:
+		return exp <= now
+	case float64:
+		return int64(exp) <= now
+	}
+	return false
+}
+
+func removeExpiredValues(sessionData map[string]interface{}, now int64) {
+	allValues, ok := sessionData[sessionValuesKey].(map[string]interface{})
+	if !ok {
+		return
+	}
+	for namespace, namespaceValues := range allValues {
+		namespaceValuesMap, ok := namespaceValues.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		for key, entry := range namespaceValuesMap {
+			entryMap, ok := entry.(map[string]interface{})
+			if ok && isSessionValueExpired(entryMap, now) {
+				delete(namespaceValuesMap, key)
+			}
+		}
+		if len(namespaceValuesMap) == 0 {
+			delete(allValues, namespace)
+		}
+	}
+	if len(allValues) == 0 {
+		delete(sessionData, sessionValuesKey)
+	}
+}
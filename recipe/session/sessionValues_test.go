@@ -0,0 +1,71 @@
+/* Copyright (c) 2023, VRAI Labs and/or its affiliates. All rights reserved.
+ *
+ * This software is licensed under the Apache License, Version 2.0 (the
+ * "License") as published by the Apache Software Foundation.
+ *
+ * You may not use this file except in compliance with the License. You may
+ * obtain a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+ * License for the specific language governing permissions and limitations
+ * under the License.
+ */
+
+package session
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSessionValuesAreNamespaced(t *testing.T) {
+	sessionData := map[string]interface{}{"appKey": "appValue"}
+
+	setValueInSessionData(sessionData, "cart", "items", 3, nil)
+	setValueInSessionData(sessionData, "wizard", "items", "step2", nil)
+
+	value, ok := getValueFromSessionData(sessionData, "cart", "items", 0)
+	assert.True(t, ok)
+	assert.Equal(t, 3, value)
+
+	value, ok = getValueFromSessionData(sessionData, "wizard", "items", 0)
+	assert.True(t, ok)
+	assert.Equal(t, "step2", value)
+
+	_, ok = getValueFromSessionData(sessionData, "other", "items", 0)
+	assert.False(t, ok)
+
+	// data set outside of SetValue is left untouched
+	assert.Equal(t, "appValue", sessionData["appKey"])
+}
+
+func TestExpiredSessionValuesAreIgnoredAndRemoved(t *testing.T) {
+	expiresAt := int64(1000)
+	sessionData := map[string]interface{}{}
+	setValueInSessionData(sessionData, "ns", "shortLived", true, &expiresAt)
+	setValueInSessionData(sessionData, "ns", "longLived", true, nil)
+
+	_, ok := getValueFromSessionData(sessionData, "ns", "shortLived", 999)
+	assert.True(t, ok)
+	_, ok = getValueFromSessionData(sessionData, "ns", "shortLived", 1000)
+	assert.False(t, ok)
+
+	// once the data comes back from the core, numbers are float64
+	sessionData[sessionValuesKey].(map[string]interface{})["ns"].(map[string]interface{})["shortLived"].(map[string]interface{})["exp"] = float64(1000)
+	_, ok = getValueFromSessionData(sessionData, "ns", "shortLived", 1001)
+	assert.False(t, ok)
+
+	removeExpiredValues(sessionData, 1001)
+	assert.Equal(t, map[string]interface{}{
+		sessionValuesKey: map[string]interface{}{
+			"ns": map[string]interface{}{
+				"longLived": map[string]interface{}{"v": true},
+			},
+		},
+	}, sessionData)
+
+	removeExpiredValues(map[string]interface{}{}, 1001)
+}
@@ -1230,9 +1230,11 @@ This test verifies that the SDK calls the well known API properly in the normal
 func TestThatJWKSIsFetchedAsExpected(t *testing.T) {
 	originalRefreshlimit := JWKRefreshRateLimit
 	originalCacheAge := JWKCacheMaxAgeInMs
+	originalProactiveRefreshWindow := JWKProactiveRefreshWindowInMs
 
 	JWKRefreshRateLimit = 100
 	JWKCacheMaxAgeInMs = 2000
+	JWKProactiveRefreshWindowInMs = 0
 
 	lastLineBeforeTest := unittesting.GetInfoLogData(t, "").LastLine
 
@@ -1296,6 +1298,7 @@ func TestThatJWKSIsFetchedAsExpected(t *testing.T) {
 
 	JWKRefreshRateLimit = originalRefreshlimit
 	JWKCacheMaxAgeInMs = originalCacheAge
+	JWKProactiveRefreshWindowInMs = originalProactiveRefreshWindow
 }
 
 /*
@@ -1312,9 +1315,11 @@ cache expired and the keys need to be refetched.
 func TestThatJWKSResultIsRefreshedProperly(t *testing.T) {
 	originalRefreshlimit := JWKRefreshRateLimit
 	originalCacheAge := JWKCacheMaxAgeInMs
+	originalProactiveRefreshWindow := JWKProactiveRefreshWindowInMs
 
 	JWKRefreshRateLimit = 100
 	JWKCacheMaxAgeInMs = 2000
+	JWKProactiveRefreshWindowInMs = 0
 
 	configValue := supertokens.TypeInput{
 		Supertokens: &supertokens.ConnectionInfo{
@@ -1367,6 +1372,7 @@ func TestThatJWKSResultIsRefreshedProperly(t *testing.T) {
 	assert.True(t, len(newKeys) != 0)
 	JWKRefreshRateLimit = originalRefreshlimit
 	JWKCacheMaxAgeInMs = originalCacheAge
+	JWKProactiveRefreshWindowInMs = originalProactiveRefreshWindow
 }
 
 /*
@@ -1528,9 +1534,11 @@ This test verifies the behaviour of the JWKS cache maintained by the SDK
 func TestJWKSCacheLogic(t *testing.T) {
 	originalRefreshlimit := JWKRefreshRateLimit
 	originalCacheAge := JWKCacheMaxAgeInMs
+	originalProactiveRefreshWindow := JWKProactiveRefreshWindowInMs
 
 	JWKRefreshRateLimit = 100
 	JWKCacheMaxAgeInMs = 2000
+	JWKProactiveRefreshWindowInMs = 0
 
 	configValue := supertokens.TypeInput{
 		Supertokens: &supertokens.ConnectionInfo{
@@ -1583,6 +1591,7 @@ func TestJWKSCacheLogic(t *testing.T) {
 
 	JWKRefreshRateLimit = originalRefreshlimit
 	JWKCacheMaxAgeInMs = originalCacheAge
+	JWKProactiveRefreshWindowInMs = originalProactiveRefreshWindow
 }
 
 /*
@@ -1674,9 +1683,11 @@ This test ensures that the SDK's caching logic for fetching JWKs works fine
 func TestThatJWKSReturnsFromCacheCorrectly(t *testing.T) {
 	originalRefreshlimit := JWKRefreshRateLimit
 	originalCacheAge := JWKCacheMaxAgeInMs
+	originalProactiveRefreshWindow := JWKProactiveRefreshWindowInMs
 
 	JWKRefreshRateLimit = 100
 	JWKCacheMaxAgeInMs = 2000
+	JWKProactiveRefreshWindowInMs = 0
 
 	configValue := supertokens.TypeInput{
 		Supertokens: &supertokens.ConnectionInfo{
@@ -1736,6 +1747,7 @@ func TestThatJWKSReturnsFromCacheCorrectly(t *testing.T) {
 
 	JWKRefreshRateLimit = originalRefreshlimit
 	JWKCacheMaxAgeInMs = originalCacheAge
+	JWKProactiveRefreshWindowInMs = originalProactiveRefreshWindow
 }
 
 /*
@@ -1939,9 +1951,11 @@ func TestSessionVerificationOfJWTBasedOnSessionPayloadWithCheckDatabase(t *testi
 func TestThatLockingForJWKSCacheWorksFine(t *testing.T) {
 	originalRefreshlimit := JWKRefreshRateLimit
 	originalCacheAge := JWKCacheMaxAgeInMs
+	originalProactiveRefreshWindow := JWKProactiveRefreshWindowInMs
 
 	JWKRefreshRateLimit = 100
 	JWKCacheMaxAgeInMs = 2000
+	JWKProactiveRefreshWindowInMs = 0
 
 	configValue := supertokens.TypeInput{
 		Supertokens: &supertokens.ConnectionInfo{
@@ -2029,6 +2043,7 @@ func TestThatLockingForJWKSCacheWorksFine(t *testing.T) {
 
 	JWKRefreshRateLimit = originalRefreshlimit
 	JWKCacheMaxAgeInMs = originalCacheAge
+	JWKProactiveRefreshWindowInMs = originalProactiveRefreshWindow
 }
 
 func TestThatGetSessionThrowsWIthDynamicKeysIfSessionWasCreatedWithStaticKeys(t *testing.T) {
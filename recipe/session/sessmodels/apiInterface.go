@@ -26,9 +26,27 @@ type APIInterface struct {
 	RefreshPOST   *func(options APIOptions, userContext supertokens.UserContext) (SessionContainer, error)
 	SignOutPOST   *func(sessionContainer SessionContainer, options APIOptions, userContext supertokens.UserContext) (SignOutPOSTResponse, error)
 	VerifySession *func(verifySessionOptions *VerifySessionOptions, options APIOptions, userContext supertokens.UserContext) (SessionContainer, error)
+
+	UserSessionsGET       *func(sessionContainer SessionContainer, options APIOptions, userContext supertokens.UserContext) (UserSessionsGETResponse, error)
+	RevokeUserSessionPOST *func(sessionHandle string, sessionContainer SessionContainer, options APIOptions, userContext supertokens.UserContext) (RevokeUserSessionPOSTResponse, error)
 }
 
 type SignOutPOSTResponse struct {
 	OK           *struct{}
 	GeneralError *supertokens.GeneralErrorResponse
 }
+
+type UserSessionsGETResponse struct {
+	OK *struct {
+		Sessions []UserSession
+	}
+	GeneralError *supertokens.GeneralErrorResponse
+}
+
+type RevokeUserSessionPOSTResponse struct {
+	OK *struct{}
+	// UnknownSessionError is returned if the session does not exist or
+	// belongs to another user
+	UnknownSessionError *struct{}
+	GeneralError        *supertokens.GeneralErrorResponse
+}
@@ -99,29 +99,259 @@ type RegenerateAccessTokenResponse struct {
 }
 
 type TypeInput struct {
-	CookieSecure                                 *bool
-	CookieSameSite                               *string
-	SessionExpiredStatusCode                     *int
-	InvalidClaimStatusCode                       *int
-	CookieDomain                                 *string
-	AntiCsrf                                     *string
-	Override                                     *OverrideStruct
-	ErrorHandlers                                *ErrorHandlers
-	GetTokenTransferMethod                       func(req *http.Request, forCreateNewSession bool, userContext supertokens.UserContext) TokenTransferMethod
+	CookieSecure             *bool
+	CookieSameSite           *string
+	SessionExpiredStatusCode *int
+	InvalidClaimStatusCode   *int
+	// CookieDomain shares sessions across subdomains, e.g. ".example.com" for
+	// app.example.com and api.example.com. It must be the domain of the
+	// APIDomain or one of its parent domains. By default session cookies are
+	// only sent to the APIDomain.
+	CookieDomain  *string
+	AntiCsrf      *string
+	Override      *OverrideStruct
+	ErrorHandlers *ErrorHandlers
+	// GetTokenTransferMethod decides whether tokens are sent in cookies or
+	// in the Authorization (Bearer) header. By default new sessions use the
+	// method asked for in the st-auth-mode request header, and both are
+	// accepted when verifying or refreshing a session. Return
+	// HeaderTransferMethod or CookieTransferMethod to allow only one.
+	GetTokenTransferMethod func(req *http.Request, forCreateNewSession bool, userContext supertokens.UserContext) TokenTransferMethod
+	// HeaderBasedAuthOnly sends and accepts tokens only in headers, for
+	// native mobile apps (e.g. React Native) that do not handle cookies.
+	// Access and refresh tokens are returned in the st-access-token and
+	// st-refresh-token response headers and must be sent back in the
+	// Authorization: Bearer header, the refresh token only to the refresh
+	// API. No cookies are set or cleared and anti-csrf checks are not needed.
+	// It cannot be used together with GetTokenTransferMethod.
+	HeaderBasedAuthOnly *bool
+	// HybridTokenTransfer sends access tokens in headers, so that the
+	// frontend can pass them to other APIs, while refresh tokens stay in
+	// httpOnly cookies that scripts cannot read. Access tokens are returned
+	// in the st-access-token response header and must be sent back in the
+	// Authorization: Bearer header. The refresh API reads the refresh token
+	// from its cookie and applies the anti-csrf checks of cookie based
+	// sessions, so AntiCsrf cannot be VIA_TOKEN. It cannot be used together
+	// with HeaderBasedAuthOnly or GetTokenTransferMethod.
+	HybridTokenTransfer *bool
+	// ExposeAccessTokenToFrontendInCookieBasedAuth also sends the access
+	// token in the st-access-token response header of cookie based
+	// sessions, so that the frontend can pass it to other services. Access
+	// tokens are JWTs that can be verified with the keys served at
+	// /jwt/jwks.json (see GetJWKS) without calling the core.
 	ExposeAccessTokenToFrontendInCookieBasedAuth bool
 	UseDynamicAccessTokenSigningKey              *bool
+	Presence                                     *PresenceInput
+	// RefreshTokenBinding binds refresh tokens to the client that created
+	// the session. It is disabled by default.
+	RefreshTokenBinding *RefreshTokenBindingInput
+	// DPoP makes sessions created with a DPoP proof (RFC 9449) sender
+	// constrained. It is disabled by default.
+	DPoP *DPoPInput
+	// ClientBinding binds sessions to the IP prefix and / or a fingerprint
+	// of the client that created them, for high security surfaces like
+	// admin dashboards. It is disabled by default.
+	ClientBinding *ClientBindingInput
+	// UserSessionsAPI records the device that created each session and
+	// enables the APIs that list and revoke the signed in user's own
+	// sessions. It is disabled by default.
+	UserSessionsAPI *UserSessionsAPIInput
+	// AccessTokenCookiePath is the path of the access token cookie. Defaults
+	// to "/". The refresh token cookie is always scoped to the refresh API.
+	AccessTokenCookiePath *string
+	// GetCookieAttributes changes the attributes of a session cookie for a
+	// request, e.g. to use another domain for staging hostnames. It is
+	// called with the attributes derived from the rest of the config.
+	GetCookieAttributes func(cookieName string, attributes CookieAttributes, req *http.Request, userContext supertokens.UserContext) (CookieAttributes, error)
+	// CookieNamePrefix is added to the names of the session cookies, and
+	// can be "__Host-" or "__Secure-". Both require secure cookies, and
+	// "__Host-" also requires that CookieDomain is not set and that the
+	// access token cookie path is "/". The refresh token cookie gets the
+	// "__Secure-" prefix with "__Host-", since it is scoped to the refresh
+	// API. Changing it signs out the users of existing sessions.
+	CookieNamePrefix *string
+	// OnTokenTheft is called when the core reports that a refresh token was
+	// stolen, e.g. to alert the user or to revoke all their sessions. It runs
+	// before ErrorHandlers.OnTokenTheftDetected, which revokes the session by
+	// default, and that still runs if OnTokenTheft returns an error.
+	OnTokenTheft func(sessionHandle string, userID string, req *http.Request, userContext supertokens.UserContext) error
+	// VerificationCache reuses the result of verifying an access token with
+	// the core (when CheckDatabase is true) for a short time. It is disabled
+	// by default.
+	VerificationCache *VerificationCacheInput
+	// RefreshDeduplication makes parallel refreshes with the same refresh
+	// token (e.g. from several browser tabs) wait for each other and get the
+	// same new tokens, instead of being reported as token theft. It is
+	// disabled by default, in which case refresh tokens are strictly single
+	// use: using one again is detected by the core as token theft.
+	RefreshDeduplication *RefreshDeduplicationInput
+	// RefreshThrottling limits how often the refresh API can be called per
+	// IP and per session, answering with 429 when a limit is reached. It is
+	// disabled by default.
+	RefreshThrottling *RefreshThrottlingInput
+	// RememberMe sets the refresh token lifetimes of sessions created with
+	// session.WithRememberMe
+	RememberMe *RememberMeInput
+	// SessionExpiration chooses whether refreshing a session extends its
+	// lifetime (SLIDING, the default) or whether sessions end a fixed time
+	// after they are created (ABSOLUTE), whatever the refreshes
+	SessionExpiration *SessionExpirationInput
+	// RevokeExistingSessionOnCreate revokes the session that a request
+	// already has when a new session is created for it (e.g. on sign in), so
+	// that a session planted by an attacker does not outlive the sign in.
+	// Defaults to true.
+	RevokeExistingSessionOnCreate *bool
+	// OnSessionCreated, OnSessionRefreshed, OnSessionRevoked and
+	// OnSessionExpired are called after the corresponding change to a
+	// session, e.g. to enforce concurrent session limits or for analytics.
+	// req is nil outside of a request. They run synchronously, so slow work
+	// should be moved to a goroutine.
+	OnSessionCreated   SessionLifecycleHook
+	OnSessionRefreshed SessionLifecycleHook
+	// OnSessionRevoked is called for each revoked session. If the user ID is
+	// not known (e.g. when revoking by session handle), the session is fetched
+	// from the core before it is revoked.
+	OnSessionRevoked SessionLifecycleHook
+	// OnSessionExpired is called when a refresh fails because the session
+	// expired or no longer exists, if the request still has the (expired)
+	// access token of the session, e.g. in its cookies.
+	OnSessionExpired SessionLifecycleHook
+	// JWTSigningAlgorithm is the algorithm of JWTs created with
+	// session.CreateJWT, "RS256" (the default) or "ES256". Access tokens are
+	// always signed by the core with its own keys.
+	JWTSigningAlgorithm *string
+	// AccessTokenIssuer is the iss claim of access tokens. Defaults to the
+	// API domain followed by the API base path.
+	AccessTokenIssuer *string
+	// AccessTokenAudience is set as the aud claim of access tokens, as a
+	// string if it has a single value and as an array otherwise. If empty,
+	// no aud claim is added.
+	AccessTokenAudience []string
+	// AccessTokenStaticClaims are added to the payload of every access token.
+	// Payload passed while creating a session takes precedence over them.
+	// They cannot include iss, aud or the claims that are set by SuperTokens
+	// (sub, exp, sessionHandle, ...).
+	AccessTokenStaticClaims map[string]interface{}
+	// AccessTokenPayloadSize warns about, and optionally rejects, access
+	// token payloads large enough to push the access token cookie over the
+	// 4KB that browsers accept, after which they drop it silently
+	AccessTokenPayloadSize *AccessTokenPayloadSizeInput
+	// SessionSuspension enables session.SuspendSession and
+	// session.ResumeSession. Verifying a suspended session fails with a
+	// SessionSuspendedError, but the session can still be refreshed and is
+	// usable again once resumed. It is disabled by default, since the
+	// suspension of each session is looked up whenever it is verified.
+	SessionSuspension *SessionSuspensionInput
+	// GuestSessions enables session.CreateGuestSession, for visitors that
+	// did not sign up yet (e.g. to keep a shopping cart). Guest sessions get
+	// a generated user ID, no claims from other recipes, and are rejected by
+	// VerifySession unless the options come from WithGuestSessionsAllowed.
+	// When a session is created for a request with a guest session (e.g. on
+	// sign up), the guest session is upgraded: its access token payload and
+	// session data are copied into the new session, and it is revoked.
+	GuestSessions *GuestSessionsInput
+	// FrontTokenPayloadFields are the keys of the access token payload that
+	// are copied into the front token, which the frontend (and server side
+	// rendering, see session.ParseFrontToken) can read. nil copies the whole
+	// payload. The keys of claims validated on the frontend (e.g. "st-ev" for
+	// email verification) must be included for those checks to work.
+	FrontTokenPayloadFields []string
+}
+
+type SessionLifecycleHook func(sessionHandle string, userID string, req *http.Request, userContext supertokens.UserContext)
+
+// SessionLifetime shortens the lifetime of a single session below the
+// validity configured in the core
+type SessionLifetime struct {
+	// AccessTokenValidity is how long each access token of the session is
+	// accepted after it is issued
+	AccessTokenValidity *time.Duration
+	// RefreshTokenLifetime is how long the session can be refreshed after it
+	// is created
+	RefreshTokenLifetime *time.Duration
+}
+
+type AccessTokenPayloadSizeInput struct {
+	// WarnAtBytes is the size of the JSON encoded payload above which
+	// OnLargePayload is called. Defaults to 2048.
+	WarnAtBytes *int
+	// MaxBytes is the size of the JSON encoded payload above which creating
+	// a session or updating its payload fails with a PayloadTooLargeError.
+	// Defaults to 0, which does not reject any payload.
+	MaxBytes *int
+	// OnLargePayload is called for payloads larger than WarnAtBytes.
+	// Defaults to logging a debug message.
+	OnLargePayload func(size int, userContext supertokens.UserContext)
+}
+
+type NormalisedAccessTokenPayloadSizeConfig struct {
+	WarnAtBytes    int
+	MaxBytes       int
+	OnLargePayload func(size int, userContext supertokens.UserContext)
+}
+
+type SessionExpirationInput struct {
+	// Strategy is SLIDING (default) or ABSOLUTE. It can be changed for a
+	// single session with WithSlidingExpiration or WithAbsoluteExpiration.
+	Strategy *string
+	// MaxSessionAge is how long sessions can be refreshed after they are
+	// created with the ABSOLUTE strategy. It is required for ABSOLUTE.
+	MaxSessionAge *time.Duration
+}
+
+type NormalisedSessionExpirationConfig struct {
+	Strategy string
+	// MaxSessionAge is 0 with the SLIDING strategy
+	MaxSessionAge time.Duration
+}
+
+// CookieAttributes are the attributes of a session cookie
+type CookieAttributes struct {
+	// Domain is empty for host only cookies
+	Domain string
+	Secure bool
+	// SameSite is one of "lax", "strict" or "none"
+	SameSite string
+	Path     string
 }
 
+// OverrideStruct is used to change the behaviour of the session recipe without forking it.
+// Both functions receive the default implementation and return the one the recipe uses. To
+// wrap a function, keep a copy of the original pointer and replace the field with a new one
+// that calls it, for example:
+//
+//	originalCreateNewSession := *originalImplementation.CreateNewSession
+//	(*originalImplementation.CreateNewSession) = func(...) (SessionContainer, error) {
+//		// pre processing
+//		return originalCreateNewSession(...)
+//	}
+//
+// Setting an API to nil disables it, and its route is no longer served by the middleware.
 type OverrideStruct struct {
-	Functions     func(originalImplementation RecipeInterface) RecipeInterface
+	// Functions overrides the recipe functions, e.g. CreateNewSession or RevokeSession
+	Functions func(originalImplementation RecipeInterface) RecipeInterface
+	// APIs overrides the recipe APIs, e.g. RefreshPOST or SignOutPOST
 	APIs          func(originalImplementation APIInterface) APIInterface
 	OpenIdFeature *openidmodels.OverrideStruct
 }
 
+// ErrorHandlers replace the responses sent for session errors, e.g. to use
+// other status codes or JSON bodies. Handlers that are not set send the
+// default responses. The session tokens are cleared before OnUnauthorised
+// (unless the error says otherwise) and OnTokenTheftDetected are called.
 type ErrorHandlers struct {
-	OnUnauthorised       func(message string, req *http.Request, res http.ResponseWriter) error
+	OnUnauthorised func(message string, req *http.Request, res http.ResponseWriter) error
+	// OnTryRefreshToken is called when the access token expired. The
+	// frontend SDKs call the refresh API when they get the
+	// SessionExpiredStatusCode (401 by default), so changing the status code
+	// breaks automatic refreshing.
+	OnTryRefreshToken    func(message string, req *http.Request, res http.ResponseWriter) error
 	OnTokenTheftDetected func(sessionHandle string, userID string, req *http.Request, res http.ResponseWriter) error
 	OnInvalidClaim       func(validationErrors []claims.ClaimValidationError, req *http.Request, res http.ResponseWriter) error
+	// OnSessionSuspended is called when a suspended session is verified.
+	// The session tokens are kept, so that the session can be used again
+	// once it is resumed.
+	OnSessionSuspended func(sessionHandle string, reason string, req *http.Request, res http.ResponseWriter) error
 }
 
 type TypeNormalisedInput struct {
@@ -137,6 +367,287 @@ type TypeNormalisedInput struct {
 	GetTokenTransferMethod                       func(req *http.Request, forCreateNewSession bool, userContext supertokens.UserContext) TokenTransferMethod
 	ExposeAccessTokenToFrontendInCookieBasedAuth bool
 	UseDynamicAccessTokenSigningKey              bool
+	// Presence is nil if presence tracking is disabled
+	Presence *NormalisedPresenceConfig
+	// RefreshTokenBinding is nil if refresh tokens are not bound to clients
+	RefreshTokenBinding *NormalisedRefreshTokenBindingConfig
+	// DPoP is nil if DPoP proofs are not checked
+	DPoP *NormalisedDPoPConfig
+	// ClientBinding is nil if sessions are not bound to clients
+	ClientBinding *NormalisedClientBindingConfig
+	// UserSessionsAPI is nil if the user sessions APIs are disabled
+	UserSessionsAPI       *NormalisedUserSessionsAPIConfig
+	AccessTokenCookiePath string
+	CookieNamePrefix      string
+	OnTokenTheft          func(sessionHandle string, userID string, req *http.Request, userContext supertokens.UserContext) error
+	// GetCookieAttributes is nil if cookie attributes are not overridden
+	GetCookieAttributes func(cookieName string, attributes CookieAttributes, req *http.Request, userContext supertokens.UserContext) (CookieAttributes, error)
+	// VerificationCache is nil if verification results are not cached
+	VerificationCache *NormalisedVerificationCacheConfig
+	// RefreshDeduplication is nil if refreshes are not deduplicated
+	RefreshDeduplication *NormalisedRefreshDeduplicationConfig
+	// RefreshThrottling is nil if refreshes are not throttled
+	RefreshThrottling             *NormalisedRefreshThrottlingConfig
+	RememberMe                    NormalisedRememberMeConfig
+	SessionExpiration             NormalisedSessionExpirationConfig
+	RevokeExistingSessionOnCreate bool
+	OnSessionCreated              SessionLifecycleHook
+	OnSessionRefreshed            SessionLifecycleHook
+	OnSessionRevoked              SessionLifecycleHook
+	OnSessionExpired              SessionLifecycleHook
+	JWTSigningAlgorithm           *string
+	AccessTokenIssuer             string
+	AccessTokenAudience           []string
+	AccessTokenStaticClaims       map[string]interface{}
+	FrontTokenPayloadFields       []string
+	HeaderBasedAuthOnly           bool
+	HybridTokenTransfer           bool
+	AccessTokenPayloadSize        NormalisedAccessTokenPayloadSizeConfig
+	// SessionSuspension is nil if sessions cannot be suspended
+	SessionSuspension *NormalisedSessionSuspensionConfig
+	// GuestSessions is nil if guest sessions are disabled
+	GuestSessions *NormalisedGuestSessionsConfig
+}
+
+type GuestSessionsInput struct {
+	// OnUpgrade is called when a guest signs up, before the guest session
+	// is revoked, e.g. to move the data the app stored for the guest user ID
+	// to the new user. Returning an error fails the creation of the session.
+	OnUpgrade func(guestUserID string, userID string, userContext supertokens.UserContext) error
+}
+
+type NormalisedGuestSessionsConfig struct {
+	OnUpgrade func(guestUserID string, userID string, userContext supertokens.UserContext) error
+}
+
+// SuspendedSessionStore keeps track of the suspended sessions. Use a shared
+// store (redis, ...) when running more than one API server.
+type SuspendedSessionStore struct {
+	// Suspend stores the suspension of a session until expiresAt, after
+	// which the session cannot be refreshed anymore
+	Suspend *func(sessionHandle string, reason string, expiresAt time.Time, userContext supertokens.UserContext) error
+	Resume  *func(sessionHandle string, userContext supertokens.UserContext) error
+	// GetSuspensionReason returns the reason the session was suspended
+	// for, or nil if it is not suspended
+	GetSuspensionReason *func(sessionHandle string, userContext supertokens.UserContext) (*string, error)
+}
+
+type SessionSuspensionInput struct {
+	// Store defaults to an in memory store
+	Store *SuspendedSessionStore
+}
+
+type NormalisedSessionSuspensionConfig struct {
+	Store SuspendedSessionStore
+}
+
+// PresenceStore keeps track of when users were last active. Use a shared store
+// (redis, ...) when running more than one API server.
+type PresenceStore struct {
+	MarkUserActive *func(userID string, ttl time.Duration, userContext supertokens.UserContext) error
+	GetOnlineUsers *func(userIDs []string, userContext supertokens.UserContext) (map[string]bool, error)
+}
+
+type PresenceInput struct {
+	// TTLInSeconds is how long a user is considered online after their last
+	// session verification. Defaults to 300.
+	TTLInSeconds *uint64
+	// Store defaults to an in memory store
+	Store *PresenceStore
+}
+
+type RefreshTokenBindingInput struct {
+	// GetBindingMaterial returns the value identifying the client of a
+	// request. Defaults to the SHA-256 fingerprint of the client certificate
+	// of the request's TLS connection, for apps using mTLS. Returning an
+	// empty string means that the client cannot be identified.
+	GetBindingMaterial func(req *http.Request, userContext supertokens.UserContext) (string, error)
+}
+
+type NormalisedRefreshTokenBindingConfig struct {
+	GetBindingMaterial func(req *http.Request, userContext supertokens.UserContext) (string, error)
+}
+
+// DPoPReplayCache remembers the IDs (jti) of the DPoP proofs that were used.
+// Use a shared cache (redis, ...) when running more than one API server.
+type DPoPReplayCache struct {
+	// StoreIfNew stores the jti until expiresAt and returns false if it was
+	// already stored
+	StoreIfNew *func(jti string, expiresAt time.Time, userContext supertokens.UserContext) (bool, error)
+}
+
+type DPoPInput struct {
+	// MaxAgeInSeconds is how long a proof is accepted after it is issued.
+	// Defaults to 60.
+	MaxAgeInSeconds *uint64
+	// AllowedAlgorithms defaults to ES256, ES384, RS256, PS256 and EdDSA
+	AllowedAlgorithms []string
+	// ReplayCache defaults to an in memory cache
+	ReplayCache *DPoPReplayCache
+}
+
+type NormalisedDPoPConfig struct {
+	MaxAge            time.Duration
+	AllowedAlgorithms []string
+	ReplayCache       DPoPReplayCache
+}
+
+type ClientBindingInput struct {
+	// BindToIP binds sessions to the network of the client's IP, as
+	// returned by supertokens.GetClientIP
+	BindToIP bool
+	// IPv4PrefixLength is the length of the IPv4 network prefix that must
+	// stay the same. Defaults to 24.
+	IPv4PrefixLength *int
+	// IPv6PrefixLength is the length of the IPv6 network prefix that must
+	// stay the same. Defaults to 64.
+	IPv6PrefixLength *int
+	// GetFingerprint returns a value identifying the client's device (for
+	// example a hash of its user agent and accept headers). Only a hash of
+	// it is stored in the access token payload.
+	GetFingerprint func(req *http.Request, userContext supertokens.UserContext) (string, error)
+	// Policy is what happens when a session is used by another client.
+	// REJECT (default) fails the request, REAUTH also revokes the session
+	// so that the user has to sign in again and REPORT only calls
+	// OnMismatch.
+	Policy *string
+	// OnMismatch is called whenever a session is used by another client,
+	// whatever the policy
+	OnMismatch func(sessionHandle string, userID string, req *http.Request, userContext supertokens.UserContext)
+}
+
+type NormalisedClientBindingConfig struct {
+	BindToIP         bool
+	IPv4PrefixLength int
+	IPv6PrefixLength int
+	GetFingerprint   func(req *http.Request, userContext supertokens.UserContext) (string, error)
+	Policy           string
+	// OnMismatch is nil if mismatches are not reported
+	OnMismatch func(sessionHandle string, userID string, req *http.Request, userContext supertokens.UserContext)
+}
+
+type UserSessionsAPIInput struct {
+	// GetDeviceInfo returns what is stored about the device that creates a
+	// session, and returned by the user sessions API. Defaults to a device
+	// name derived from the user agent (e.g. "Firefox on Windows"), the user
+	// agent and the IP of the request.
+	GetDeviceInfo func(req *http.Request, userContext supertokens.UserContext) map[string]interface{}
+}
+
+type NormalisedUserSessionsAPIConfig struct {
+	GetDeviceInfo func(req *http.Request, userContext supertokens.UserContext) map[string]interface{}
+}
+
+// UserSession is a session of the signed in user, as returned by the user
+// sessions API. Times are in milliseconds since the epoch.
+type UserSession struct {
+	SessionHandle string                 `json:"sessionHandle"`
+	TenantId      string                 `json:"tenantId"`
+	TimeCreated   uint64                 `json:"timeCreated"`
+	Expiry        uint64                 `json:"expiry"`
+	LastActiveAt  uint64                 `json:"lastActiveAt"`
+	Device        map[string]interface{} `json:"device"`
+	IsCurrent     bool                   `json:"isCurrent"`
+}
+
+type VerificationCacheInput struct {
+	// TTL is how long a verification result is reused. Defaults to 5
+	// seconds. Results are never reused after the access token expires.
+	TTL *time.Duration
+	// MaxEntries bounds the number of cached results. Defaults to 10000.
+	MaxEntries *int
+}
+
+type NormalisedVerificationCacheConfig struct {
+	TTL        time.Duration
+	MaxEntries int
+}
+
+// RefreshDeduplicationStore serialises refreshes that use the same refresh
+// token and keeps their result for the others. Keys are derived from the
+// refresh token, and results hold the new session tokens. Use a shared store
+// (redis, ...) when running more than one API server.
+type RefreshDeduplicationStore struct {
+	// Lock blocks until the lock for key is held, and returns a function
+	// that releases it. Locks must be released automatically after ttl.
+	Lock *func(key string, ttl time.Duration, userContext supertokens.UserContext) (func(), error)
+	// GetResult returns the result stored for key, or nil if there is none
+	GetResult *func(key string, userContext supertokens.UserContext) (*RefreshDeduplicationResult, error)
+	// SetResult stores the result of a refresh until expiresAt
+	SetResult *func(key string, result RefreshDeduplicationResult, expiresAt time.Time, userContext supertokens.UserContext) error
+}
+
+// RefreshDeduplicationResult is the result of a refresh kept in a
+// RefreshDeduplicationStore, with the client that did the refresh
+type RefreshDeduplicationResult struct {
+	Response    CreateOrRefreshAPIResponse
+	RefreshedAt time.Time
+	ClientIP    string
+	UserAgent   string
+}
+
+type RefreshThrottlingInput struct {
+	// MaxRefreshesPerIP is the number of refreshes allowed from one IP in
+	// each window. Defaults to 60, 0 disables the limit.
+	MaxRefreshesPerIP *uint64
+	// MaxRefreshesPerSession is the number of refreshes allowed with one
+	// refresh token in each window. Since the refresh token of a session
+	// changes on each refresh, this throttles clients that keep refreshing
+	// without using the new tokens. Defaults to 10, 0 disables the limit.
+	MaxRefreshesPerSession *uint64
+	// Window defaults to one minute
+	Window time.Duration
+	// Store defaults to an in memory store
+	Store *supertokens.RateLimiterStore
+}
+
+type NormalisedRefreshThrottlingConfig struct {
+	MaxRefreshesPerIP      uint64
+	MaxRefreshesPerSession uint64
+	Window                 time.Duration
+	Store                  supertokens.RateLimiterStore
+}
+
+type RefreshDeduplicationInput struct {
+	// GracePeriodInSeconds is how long the result of a refresh is returned
+	// to other requests using the same refresh token. Defaults to 10.
+	GracePeriodInSeconds *uint64
+	// Store defaults to an in memory store
+	Store *RefreshDeduplicationStore
+	// ReportReuse calls OnTokenTheft when a refresh token is used again
+	// within the grace period after its refresh has completed, or by another
+	// client (IP or user agent). Parallel refreshes of one client, e.g. from
+	// several browser tabs, are not reported. The reuse is still allowed, but
+	// reporting it helps to spot refresh tokens used from two places, e.g. on
+	// flaky mobile networks or by an attacker. Defaults to false.
+	ReportReuse *bool
+}
+
+type NormalisedRefreshDeduplicationConfig struct {
+	GracePeriod time.Duration
+	Store       RefreshDeduplicationStore
+	ReportReuse bool
+}
+
+type RememberMeInput struct {
+	// RefreshTokenLifetimeWhenNotRemembered is how long sessions created
+	// without "remember me" can be refreshed. Defaults to 24 hours.
+	RefreshTokenLifetimeWhenNotRemembered *time.Duration
+	// RefreshTokenLifetimeWhenRemembered is how long sessions created with
+	// "remember me" can be refreshed. Defaults to the lifetime configured
+	// in the core.
+	RefreshTokenLifetimeWhenRemembered *time.Duration
+}
+
+type NormalisedRememberMeConfig struct {
+	RefreshTokenLifetimeWhenNotRemembered time.Duration
+	// RefreshTokenLifetimeWhenRemembered is nil to use the core's lifetime
+	RefreshTokenLifetimeWhenRemembered *time.Duration
+}
+
+type NormalisedPresenceConfig struct {
+	TTLInSeconds uint64
+	Store        PresenceStore
 }
 
 type AntiCsrfFunctionOrString struct {
@@ -151,10 +662,17 @@ type JWTNormalisedConfig struct {
 }
 
 type VerifySessionOptions struct {
-	AntiCsrfCheck                 *bool
+	AntiCsrfCheck *bool
+	// SessionRequired set to false lets requests without a session through
+	// instead of responding with a 401. GetSessionFromRequestContext then
+	// returns nil for them. Defaults to true.
 	SessionRequired               *bool
 	CheckDatabase                 *bool
 	OverrideGlobalClaimValidators func(globalClaimValidators []claims.SessionClaimValidator, sessionContainer SessionContainer, userContext supertokens.UserContext) ([]claims.SessionClaimValidator, error)
+	// Authorize runs after the claim validators, for per route checks. Return
+	// session.NewAuthorizationError to respond with the invalid claim status
+	// code (403 by default). Other errors are handled like any other error.
+	Authorize func(sessionContainer SessionContainer, userContext supertokens.UserContext) error
 }
 
 type APIOptions struct {
@@ -173,6 +691,7 @@ type NormalisedErrorHandlers struct {
 	OnTryRefreshToken    func(message string, req *http.Request, res http.ResponseWriter) error
 	OnTokenTheftDetected func(sessionHandle string, userID string, req *http.Request, res http.ResponseWriter) error
 	OnInvalidClaim       func(validationErrors []claims.ClaimValidationError, req *http.Request, res http.ResponseWriter) error
+	OnSessionSuspended   func(sessionHandle string, reason string, req *http.Request, res http.ResponseWriter) error
 }
 
 type SessionTokens struct {
@@ -214,6 +733,9 @@ type TypeSessionContainer struct {
 	GetExpiryWithContext                   func(userContext supertokens.UserContext) (uint64, error)
 
 	MergeIntoAccessTokenPayloadWithContext func(accessTokenPayloadUpdate map[string]interface{}, userContext supertokens.UserContext) error
+	// RemoveFromAccessTokenPayloadWithContext removes the given keys from the access token payload.
+	// Protected props (e.g. "sub" or "exp") cannot be removed.
+	RemoveFromAccessTokenPayloadWithContext func(keys []string, userContext supertokens.UserContext) error
 
 	AssertClaimsWithContext            func(claimValidators []claims.SessionClaimValidator, userContext supertokens.UserContext) error
 	FetchAndSetClaimWithContext        func(claim *claims.TypeSessionClaim, userContext supertokens.UserContext) error
@@ -222,7 +744,8 @@ type TypeSessionContainer struct {
 	RemoveClaimWithContext             func(claim *claims.TypeSessionClaim, userContext supertokens.UserContext) error
 	AttachToRequestResponseWithContext func(info RequestResponseInfo, userContext supertokens.UserContext) error
 
-	MergeIntoAccessTokenPayload func(accessTokenPayloadUpdate map[string]interface{}) error
+	MergeIntoAccessTokenPayload  func(accessTokenPayloadUpdate map[string]interface{}) error
+	RemoveFromAccessTokenPayload func(keys ...string) error
 
 	AssertClaims            func(claimValidators []claims.SessionClaimValidator) error
 	FetchAndSetClaim        func(claim *claims.TypeSessionClaim) error
@@ -235,13 +758,15 @@ type TypeSessionContainer struct {
 type SessionContainer = *TypeSessionContainer
 
 type SessionInformation struct {
-	SessionHandle                    string
-	UserId                           string
-	SessionDataInDatabase            map[string]interface{}
+	SessionHandle         string
+	UserId                string
+	SessionDataInDatabase map[string]interface{}
+	// Expiry is the time in milliseconds since epoch after which the session can no longer be refreshed
 	Expiry                           uint64
 	CustomClaimsInAccessTokenPayload map[string]interface{}
-	TimeCreated                      uint64
-	TenantId                         string
+	// TimeCreated is the time in milliseconds since epoch at which the session was created
+	TimeCreated uint64
+	TenantId    string
 }
 
 type ParsedJWTInfo struct {
@@ -0,0 +1,94 @@
+/* Copyright (c) 2023, VRAI Labs and/or its affiliates. All rights reserved.
+ *
+ * This software is licensed under the Apache License, Version 2.0 (the
+ * "License") as published by the Apache Software Foundation.
+ *
+ * You may not use this file except in compliance with the License. You may
+ * obtain a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+ * License for the specific language governing permissions and limitations
+ * under the License.
+ */
+
+package session
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/supertokens/supertokens-golang/recipe/session/claims"
+	"github.com/supertokens/supertokens-golang/supertokens"
+)
+
+func TestThatClaimInShadowModeIsReportedButNotEnforced(t *testing.T) {
+	violations := initWithShadowMode(t, []string{supertokens.ShadowModeClaimPolicyPrefix + "test-claim"})
+	defer resetAll()
+
+	validationErrors := ValidateClaimsInPayload([]claims.SessionClaimValidator{
+		makeFailingClaimValidator("test-claim"),
+	}, map[string]interface{}{}, &map[string]interface{}{})
+
+	assert.Empty(t, validationErrors)
+	assert.Equal(t, []supertokens.ShadowModeViolation{{
+		PolicyID: supertokens.ShadowModeClaimPolicyPrefix + "test-claim",
+		Reason:   "claim is missing",
+	}}, *violations)
+}
+
+func TestThatClaimNotInShadowModeIsEnforced(t *testing.T) {
+	violations := initWithShadowMode(t, []string{supertokens.ShadowModeClaimPolicyPrefix + "other-claim"})
+	defer resetAll()
+
+	validationErrors := ValidateClaimsInPayload([]claims.SessionClaimValidator{
+		makeFailingClaimValidator("test-claim"),
+	}, map[string]interface{}{}, &map[string]interface{}{})
+
+	assert.Equal(t, []claims.ClaimValidationError{{
+		ID:     "test-claim",
+		Reason: "claim is missing",
+	}}, validationErrors)
+	assert.Empty(t, *violations)
+}
+
+func makeFailingClaimValidator(id string) claims.SessionClaimValidator {
+	return claims.SessionClaimValidator{
+		ID: id,
+		Validate: func(payload map[string]interface{}, userContext supertokens.UserContext) claims.ClaimValidationResult {
+			return claims.ClaimValidationResult{
+				IsValid: false,
+				Reason:  "claim is missing",
+			}
+		},
+	}
+}
+
+func initWithShadowMode(t *testing.T, policies []string) *[]supertokens.ShadowModeViolation {
+	violations := []supertokens.ShadowModeViolation{}
+	resetAll()
+	err := supertokens.Init(supertokens.TypeInput{
+		Supertokens: &supertokens.ConnectionInfo{
+			ConnectionURI: "http://localhost:8080",
+		},
+		AppInfo: supertokens.AppInfo{
+			APIDomain:     "api.supertokens.io",
+			AppName:       "SuperTokens",
+			WebsiteDomain: "supertokens.io",
+		},
+		ShadowMode: &supertokens.ShadowModeInput{
+			Policies: policies,
+			OnViolation: func(violation supertokens.ShadowModeViolation, userContext supertokens.UserContext) {
+				violations = append(violations, violation)
+			},
+		},
+		RecipeList: []supertokens.Recipe{
+			Init(nil),
+		},
+	})
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	return &violations
+}
@@ -0,0 +1,120 @@
+/* Copyright (c) 2023, VRAI Labs and/or its affiliates. All rights reserved.
+ *
+ * This software is licensed under the Apache License, Version 2.0 (the
+ * "License") as published by the Apache Software Foundation.
+ *
+ * You may not use this file except in compliance with the License. You may
+ * obtain a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+ * License for the specific language governing permissions and limitations
+ * under the License.
+ */
+
+package session
+
+import (
+	defaultErrors "errors"
+	"time"
+
+	"github.com/supertokens/supertokens-golang/recipe/session/claims"
+	"github.com/supertokens/supertokens-golang/recipe/session/errors"
+	"github.com/supertokens/supertokens-golang/recipe/session/sessmodels"
+	"github.com/supertokens/supertokens-golang/supertokens"
+)
+
+// CompletedFactorsClaim records the auth factors (e.g. "otp-email" or
+// "totp") completed in a session, and when, in ms since the epoch. It is set
+// by MarkFactorCompleted, not fetched, so it is not part of new sessions.
+var CompletedFactorsClaim, _ = claims.PrimitiveClaim("st-mfa", func(userId string, tenantId string, userContext supertokens.UserContext) (interface{}, error) {
+	return nil, nil
+}, nil)
+
+// MarkFactorCompleted records in the session that the user just completed
+// the auth factor, e.g. after verifying a TOTP code.
+func MarkFactorCompleted(sessionContainer sessmodels.SessionContainer, factorID string, userContext ...supertokens.UserContext) error {
+	if len(userContext) == 0 {
+		userContext = append(userContext, &map[string]interface{}{})
+	}
+	completedFactors := map[string]interface{}{}
+	for factor, completedAt := range GetCompletedFactors(sessionContainer, userContext...) {
+		completedFactors[factor] = completedAt
+	}
+	completedFactors[factorID] = time.Now().UnixNano() / 1000000
+	return sessionContainer.SetClaimValueWithContext(CompletedFactorsClaim, completedFactors, userContext[0])
+}
+
+// GetCompletedFactors returns the auth factors completed in the session and
+// when they were completed, in ms since the epoch
+func GetCompletedFactors(sessionContainer sessmodels.SessionContainer, userContext ...supertokens.UserContext) map[string]int64 {
+	if len(userContext) == 0 {
+		userContext = append(userContext, &map[string]interface{}{})
+	}
+	return getCompletedFactorsFromPayload(sessionContainer.GetAccessTokenPayloadWithContext(userContext[0]), userContext[0])
+}
+
+func getCompletedFactorsFromPayload(payload map[string]interface{}, userContext supertokens.UserContext) map[string]int64 {
+	result := map[string]int64{}
+	value, ok := CompletedFactorsClaim.GetValueFromPayload(payload, userContext).(map[string]interface{})
+	if !ok {
+		return result
+	}
+	for factor, completedAt := range value {
+		switch t := completedAt.(type) {
+		case int64:
+			result[factor] = t
+		case float64:
+			result[factor] = int64(t)
+		}
+	}
+	return result
+}
+
+// RequireRecentFactor returns a validator, to use with WithClaimValidators,
+// for sessions in which one of the given factors was completed within maxAge
+// (or at any time if maxAge is 0), for step up authentication before
+// sensitive actions. Use IsStepUpRequiredError to tell its failures apart.
+func RequireRecentFactor(maxAge time.Duration, factorIDs ...string) claims.SessionClaimValidator {
+	return claims.SessionClaimValidator{
+		ID:    CompletedFactorsClaim.Key,
+		Claim: CompletedFactorsClaim,
+		ShouldRefetch: func(payload map[string]interface{}, userContext supertokens.UserContext) bool {
+			return false
+		},
+		Validate: func(payload map[string]interface{}, userContext supertokens.UserContext) claims.ClaimValidationResult {
+			now := time.Now().UnixNano() / 1000000
+			completedFactors := getCompletedFactorsFromPayload(payload, userContext)
+			for _, factorID := range factorIDs {
+				completedAt, ok := completedFactors[factorID]
+				if ok && (maxAge == 0 || completedAt >= now-maxAge.Milliseconds()) {
+					return claims.ClaimValidationResult{IsValid: true}
+				}
+			}
+			return claims.ClaimValidationResult{
+				IsValid: false,
+				Reason: map[string]interface{}{
+					"message":         "step up required",
+					"factors":         factorIDs,
+					"maxAgeInSeconds": int64(maxAge.Seconds()),
+				},
+			}
+		},
+	}
+}
+
+// IsStepUpRequiredError reports whether err is a failed RequireRecentFactor
+// validation
+func IsStepUpRequiredError(err error) bool {
+	var invalidClaimErr errors.InvalidClaimError
+	if !defaultErrors.As(err, &invalidClaimErr) {
+		return false
+	}
+	for _, invalidClaim := range invalidClaimErr.InvalidClaims {
+		if invalidClaim.ID == CompletedFactorsClaim.Key {
+			return true
+		}
+	}
+	return false
+}
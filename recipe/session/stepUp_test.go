@@ -0,0 +1,72 @@
+/* Copyright (c) 2023, VRAI Labs and/or its affiliates. All rights reserved.
+ *
+ * This software is licensed under the Apache License, Version 2.0 (the
+ * "License") as published by the Apache Software Foundation.
+ *
+ * You may not use this file except in compliance with the License. You may
+ * obtain a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+ * License for the specific language governing permissions and limitations
+ * under the License.
+ */
+
+package session
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/supertokens/supertokens-golang/recipe/session/claims"
+	"github.com/supertokens/supertokens-golang/recipe/session/errors"
+	"github.com/supertokens/supertokens-golang/recipe/session/sessmodels"
+	"github.com/supertokens/supertokens-golang/supertokens"
+)
+
+func TestStepUpWithCompletedFactors(t *testing.T) {
+	payload := map[string]interface{}{}
+	sessionContainer := &sessmodels.TypeSessionContainer{
+		GetAccessTokenPayloadWithContext: func(userContext supertokens.UserContext) map[string]interface{} {
+			return payload
+		},
+		SetClaimValueWithContext: func(claim *claims.TypeSessionClaim, value interface{}, userContext supertokens.UserContext) error {
+			payload = claim.AddToPayload_internal(payload, value, userContext)
+			// the payload goes through JSON when the access token is regenerated
+			serialised, _ := json.Marshal(payload)
+			payload = map[string]interface{}{}
+			return json.Unmarshal(serialised, &payload)
+		},
+	}
+	userContext := &map[string]interface{}{}
+
+	assert.Empty(t, GetCompletedFactors(sessionContainer))
+	assert.False(t, RequireRecentFactor(0, "totp").Validate(payload, userContext).IsValid)
+
+	assert.NoError(t, MarkFactorCompleted(sessionContainer, "password"))
+	assert.NoError(t, MarkFactorCompleted(sessionContainer, "totp"))
+	completedFactors := GetCompletedFactors(sessionContainer)
+	assert.Len(t, completedFactors, 2)
+	assert.InDelta(t, time.Now().UnixMilli(), completedFactors["totp"], 1000)
+
+	assert.True(t, RequireRecentFactor(5*time.Minute, "totp", "otp-email").Validate(payload, userContext).IsValid)
+	assert.False(t, RequireRecentFactor(5*time.Minute, "otp-email").Validate(payload, userContext).IsValid)
+
+	payload[CompletedFactorsClaim.Key].(map[string]interface{})["v"].(map[string]interface{})["totp"] = float64(time.Now().Add(-10 * time.Minute).UnixMilli())
+	assert.True(t, RequireRecentFactor(0, "totp").Validate(payload, userContext).IsValid)
+	result := RequireRecentFactor(5*time.Minute, "totp").Validate(payload, userContext)
+	assert.False(t, result.IsValid)
+	assert.Equal(t, "step up required", result.Reason.(map[string]interface{})["message"])
+}
+
+func TestIsStepUpRequiredError(t *testing.T) {
+	stepUpErr := errors.InvalidClaimError{Msg: "invalid claims", InvalidClaims: []claims.ClaimValidationError{{ID: "st-mfa"}}}
+	assert.True(t, IsStepUpRequiredError(stepUpErr))
+	assert.True(t, IsStepUpRequiredError(fmt.Errorf("wrapped: %w", stepUpErr)))
+	assert.False(t, IsStepUpRequiredError(errors.InvalidClaimError{Msg: "invalid claims", InvalidClaims: []claims.ClaimValidationError{{ID: "st-role"}}}))
+	assert.False(t, IsStepUpRequiredError(errors.UnauthorizedError{Msg: "unauthorised"}))
+}
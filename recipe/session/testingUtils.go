@@ -34,6 +34,7 @@ func resetAll() {
 	returnedFromCache = make(chan bool, 1000)
 	urlsAttemptedForJWKSFetch = []string{}
 	jwksCache = nil
+	lastJWKSRefetch = 0
 }
 
 func BeforeEach() {
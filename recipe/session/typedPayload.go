@@ -0,0 +1,75 @@
+/* Copyright (c) 2023, VRAI Labs and/or its affiliates. All rights reserved.
+ *
+ * This software is licensed under the Apache License, Version 2.0 (the
+ * "License") as published by the Apache Software Foundation.
+ *
+ * You may not use this file except in compliance with the License. You may
+ * obtain a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+ * License for the specific language governing permissions and limitations
+ * under the License.
+ */
+
+package session
+
+import (
+	"errors"
+
+	"github.com/supertokens/supertokens-golang/recipe/session/sessmodels"
+	"github.com/supertokens/supertokens-golang/supertokens"
+)
+
+// GetTypedPayload decodes the access token payload of the session into T
+// through its JSON tags, e.g.
+//
+//	type AppClaims struct {
+//		OrgID string   `json:"orgId"`
+//		Teams []string `json:"teams,omitempty"`
+//	}
+//	claims, err := session.GetTypedPayload[AppClaims](sessionContainer)
+//
+// Claims that T has no field for are ignored.
+func GetTypedPayload[T any](sessionContainer sessmodels.SessionContainer, userContext ...supertokens.UserContext) (T, error) {
+	var result T
+	if len(userContext) == 0 {
+		userContext = append(userContext, &map[string]interface{}{})
+	}
+	err := convertThroughJSON(sessionContainer.GetAccessTokenPayloadWithContext(userContext[0]), &result)
+	if err != nil {
+		return result, errors.New("the access token payload does not match the requested type: " + err.Error())
+	}
+	return result, nil
+}
+
+// SetTypedPayload merges the JSON encoding of value into the access token
+// payload of the session, like MergeIntoAccessTokenPayload. Fields encoded as
+// null remove the claim, so use omitempty for fields that should be left as
+// they are when not set. value must not contain protected claims like sub or
+// exp.
+func SetTypedPayload[T any](sessionContainer sessmodels.SessionContainer, value T, userContext ...supertokens.UserContext) error {
+	if len(userContext) == 0 {
+		userContext = append(userContext, &map[string]interface{}{})
+	}
+	accessTokenPayloadUpdate := map[string]interface{}{}
+	err := convertThroughJSON(value, &accessTokenPayloadUpdate)
+	if err != nil {
+		return errors.New("the value could not be converted to an access token payload, it must encode to a JSON object: " + err.Error())
+	}
+	for k := range accessTokenPayloadUpdate {
+		if supertokens.DoesSliceContainString(k, protectedProps) {
+			return errors.New("the access token payload claim " + k + " is protected and cannot be set")
+		}
+	}
+	return sessionContainer.MergeIntoAccessTokenPayloadWithContext(accessTokenPayloadUpdate, userContext[0])
+}
+
+func convertThroughJSON(value interface{}, out interface{}) error {
+	valueJSON, err := supertokens.JSONMarshal(value)
+	if err != nil {
+		return err
+	}
+	return supertokens.JSONUnmarshal(valueJSON, out)
+}
@@ -0,0 +1,84 @@
+/* Copyright (c) 2023, VRAI Labs and/or its affiliates. All rights reserved.
+ *
+ * This software is licensed under the Apache License, Version 2.0 (the
+ * "License") as published by the Apache Software Foundation.
+ *
+ * You may not use this file except in compliance with the License. You may
+ * obtain a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+ * License for the specific language governing permissions and limitations
+ * under the License.
+ */
+
+package session
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/supertokens/supertokens-golang/recipe/session/sessmodels"
+	"github.com/supertokens/supertokens-golang/supertokens"
+)
+
+type typedPayloadTestClaims struct {
+	OrgID string   `json:"orgId"`
+	Teams []string `json:"teams,omitempty"`
+	Admin *bool    `json:"admin"`
+}
+
+func makeTypedPayloadTestSession(payload map[string]interface{}) sessmodels.SessionContainer {
+	sessionContainer := &sessmodels.TypeSessionContainer{}
+	sessionContainer.GetAccessTokenPayloadWithContext = func(userContext supertokens.UserContext) map[string]interface{} {
+		return payload
+	}
+	sessionContainer.MergeIntoAccessTokenPayloadWithContext = func(accessTokenPayloadUpdate map[string]interface{}, userContext supertokens.UserContext) error {
+		for k, v := range accessTokenPayloadUpdate {
+			if v == nil {
+				delete(payload, k)
+			} else {
+				payload[k] = v
+			}
+		}
+		return nil
+	}
+	return sessionContainer
+}
+
+func TestGetTypedPayload(t *testing.T) {
+	sessionContainer := makeTypedPayloadTestSession(map[string]interface{}{
+		"sub":   "user1",
+		"orgId": "org1",
+		"teams": []interface{}{"a", "b"},
+		"admin": true,
+	})
+
+	claims, err := GetTypedPayload[typedPayloadTestClaims](sessionContainer)
+	assert.NoError(t, err)
+	assert.Equal(t, "org1", claims.OrgID)
+	assert.Equal(t, []string{"a", "b"}, claims.Teams)
+	assert.True(t, *claims.Admin)
+
+	_, err = GetTypedPayload[struct {
+		OrgID int `json:"orgId"`
+	}](sessionContainer)
+	assert.Error(t, err)
+}
+
+func TestSetTypedPayload(t *testing.T) {
+	payload := map[string]interface{}{"sub": "user1", "orgId": "org1", "teams": []interface{}{"a"}, "admin": true}
+	sessionContainer := makeTypedPayloadTestSession(payload)
+
+	err := SetTypedPayload(sessionContainer, typedPayloadTestClaims{OrgID: "org2"})
+	assert.NoError(t, err)
+	// teams is omitted, and the nil admin removes the claim
+	assert.Equal(t, map[string]interface{}{"sub": "user1", "orgId": "org2", "teams": []interface{}{"a"}}, payload)
+
+	err = SetTypedPayload(sessionContainer, map[string]interface{}{"sub": "user2"})
+	assert.Error(t, err)
+	err = SetTypedPayload(sessionContainer, []string{"a"})
+	assert.Error(t, err)
+	assert.Equal(t, "user1", payload["sub"])
+}
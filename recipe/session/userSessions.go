@@ -0,0 +1,277 @@
+/* Copyright (c) 2023, VRAI Labs and/or its affiliates. All rights reserved.
+ *
+ * This software is licensed under the Apache License, Version 2.0 (the
+ * "License") as published by the Apache Software Foundation.
+ *
+ * You may not use this file except in compliance with the License. You may
+ * obtain a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+ * License for the specific language governing permissions and limitations
+ * under the License.
+ */
+
+package session
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/supertokens/supertokens-golang/recipe/session/sessmodels"
+	"github.com/supertokens/supertokens-golang/supertokens"
+)
+
+func normaliseUserSessionsAPIInput(config *sessmodels.UserSessionsAPIInput) *sessmodels.NormalisedUserSessionsAPIConfig {
+	if config == nil {
+		return nil
+	}
+	result := &sessmodels.NormalisedUserSessionsAPIConfig{
+		GetDeviceInfo: defaultGetDeviceInfo,
+	}
+	if config.GetDeviceInfo != nil {
+		result.GetDeviceInfo = config.GetDeviceInfo
+	}
+	return result
+}
+
+func defaultGetDeviceInfo(req *http.Request, userContext supertokens.UserContext) map[string]interface{} {
+	return map[string]interface{}{
+		"name":      getDeviceName(req.UserAgent()),
+		"userAgent": req.UserAgent(),
+		"ip":        supertokens.GetClientIP(req, userContext),
+	}
+}
+
+// the order matters: e.g. Edge user agents also contain "Chrome", and Chrome
+// user agents also contain "Safari"
+var userAgentBrowsers = []struct{ token, name string }{
+	{"Edg/", "Edge"},
+	{"OPR/", "Opera"},
+	{"Firefox/", "Firefox"},
+	{"Chrome/", "Chrome"},
+	{"Safari/", "Safari"},
+}
+
+var userAgentOperatingSystems = []struct{ token, name string }{
+	{"iPhone", "iOS"},
+	{"iPad", "iPadOS"},
+	{"Android", "Android"},
+	{"Windows", "Windows"},
+	{"Mac OS X", "macOS"},
+	{"CrOS", "ChromeOS"},
+	{"Linux", "Linux"},
+}
+
+// getDeviceName returns a name that users can recognise their devices by, such
+// as "Firefox on Windows", or "Unknown device" if the user agent is not known
+func getDeviceName(userAgent string) string {
+	browser := ""
+	for _, b := range userAgentBrowsers {
+		if strings.Contains(userAgent, b.token) {
+			browser = b.name
+			break
+		}
+	}
+	operatingSystem := ""
+	for _, o := range userAgentOperatingSystems {
+		if strings.Contains(userAgent, o.token) {
+			operatingSystem = o.name
+			break
+		}
+	}
+	if browser != "" && operatingSystem != "" {
+		return browser + " on " + operatingSystem
+	}
+	if browser != "" {
+		return browser
+	}
+	if operatingSystem != "" {
+		return operatingSystem
+	}
+	return "Unknown device"
+}
+
+// addSessionDevice records the device that creates a session in its session
+// data in database. Sessions created outside of a request have no device info.
+func addSessionDevice(config sessmodels.TypeNormalisedInput, sessionDataInDatabase map[string]interface{}, userContext supertokens.UserContext) map[string]interface{} {
+	if config.UserSessionsAPI == nil {
+		return sessionDataInDatabase
+	}
+	deviceInfo := map[string]interface{}{}
+	req := supertokens.GetRequestFromUserContext(userContext)
+	if req != nil {
+		deviceInfo = config.UserSessionsAPI.GetDeviceInfo(req, userContext)
+	}
+	result := map[string]interface{}{}
+	for k, v := range sessionDataInDatabase {
+		result[k] = v
+	}
+	result[userSessionDeviceKey] = map[string]interface{}{
+		"info":         deviceInfo,
+		"lastActiveAt": time.Now().UnixMilli(),
+	}
+	return result
+}
+
+// touchSessionDevice updates when the session was last active after it is
+// refreshed. This is best effort: failing to record it should never fail the
+// refresh. It reads and writes the session data, so it can overwrite a
+// concurrent update of the session data in database.
+func touchSessionDevice(config sessmodels.TypeNormalisedInput, querier supertokens.Querier, sessionHandle string, userContext supertokens.UserContext) {
+	if config.UserSessionsAPI == nil {
+		return
+	}
+	sessionInfo, err := getSessionInformationHelper(querier, sessionHandle, userContext)
+	if err != nil || sessionInfo == nil {
+		if err != nil {
+			supertokens.LogDebugMessage("touchSessionDevice: Failed to fetch the session: " + err.Error())
+		}
+		return
+	}
+	sessionData := sessionInfo.SessionDataInDatabase
+	if sessionData == nil {
+		sessionData = map[string]interface{}{}
+	}
+	device, ok := sessionData[userSessionDeviceKey].(map[string]interface{})
+	if !ok {
+		device = map[string]interface{}{"info": map[string]interface{}{}}
+		sessionData[userSessionDeviceKey] = device
+	}
+	device["lastActiveAt"] = time.Now().UnixMilli()
+	_, err = updateSessionDataInDatabaseHelper(querier, sessionHandle, sessionData, userContext)
+	if err != nil {
+		supertokens.LogDebugMessage("touchSessionDevice: Failed to update the session: " + err.Error())
+	}
+}
+
+func getUserSession(sessionInfo sessmodels.SessionInformation, currentSessionHandle string) sessmodels.UserSession {
+	result := sessmodels.UserSession{
+		SessionHandle: sessionInfo.SessionHandle,
+		TenantId:      sessionInfo.TenantId,
+		TimeCreated:   sessionInfo.TimeCreated,
+		Expiry:        sessionInfo.Expiry,
+		LastActiveAt:  sessionInfo.TimeCreated,
+		Device:        map[string]interface{}{},
+		IsCurrent:     sessionInfo.SessionHandle == currentSessionHandle,
+	}
+	device, ok := sessionInfo.SessionDataInDatabase[userSessionDeviceKey].(map[string]interface{})
+	if !ok {
+		return result
+	}
+	if info, ok := device["info"].(map[string]interface{}); ok {
+		result.Device = info
+	}
+	// lastActiveAt is an int64 when set locally and a float64 once it comes
+	// back from the core
+	switch lastActiveAt := device["lastActiveAt"].(type) {
+	case float64:
+		result.LastActiveAt = uint64(lastActiveAt)
+	case int64:
+		result.LastActiveAt = uint64(lastActiveAt)
+	}
+	return result
+}
+
+// listUserSessions returns the sessions of the user in tenantId, or in all
+// tenants if fetchAcrossAllTenants is true.
+func listUserSessions(recipeImpl sessmodels.RecipeInterface, userID string, tenantId string, fetchAcrossAllTenants bool, currentSessionHandle string, userContext supertokens.UserContext) ([]sessmodels.UserSession, error) {
+	sessionHandles, err := (*recipeImpl.GetAllSessionHandlesForUser)(userID, tenantId, &fetchAcrossAllTenants, userContext)
+	if err != nil {
+		return nil, err
+	}
+	sessions := []sessmodels.UserSession{}
+	for _, sessionHandle := range sessionHandles {
+		sessionInfo, err := (*recipeImpl.GetSessionInformation)(sessionHandle, userContext)
+		if err != nil {
+			return nil, err
+		}
+		// the session may have been revoked since the handles were fetched
+		if sessionInfo == nil {
+			continue
+		}
+		sessions = append(sessions, getUserSession(*sessionInfo, currentSessionHandle))
+	}
+	return sessions, nil
+}
+
+func getUserSessionOptions() *sessmodels.VerifySessionOptions {
+	True := true
+	return &sessmodels.VerifySessionOptions{
+		SessionRequired: &True,
+	}
+}
+
+func UserSessionsAPI(apiImplementation sessmodels.APIInterface, options sessmodels.APIOptions, userContext supertokens.UserContext) error {
+	if apiImplementation.UserSessionsGET == nil || (*apiImplementation.UserSessionsGET) == nil {
+		options.OtherHandler.ServeHTTP(options.Res, options.Req)
+		return nil
+	}
+
+	sessionContainer, err := GetSessionFromRequest(options.Req, options.Res, options.Config, getUserSessionOptions(), options.RecipeImplementation, userContext)
+	if err != nil {
+		return err
+	}
+
+	resp, err := (*apiImplementation.UserSessionsGET)(sessionContainer, options, userContext)
+	if err != nil {
+		return err
+	}
+
+	if resp.OK != nil {
+		return supertokens.Send200Response(options.Res, map[string]interface{}{
+			"status":   "OK",
+			"sessions": resp.OK.Sessions,
+		})
+	} else if resp.GeneralError != nil {
+		return supertokens.Send200Response(options.Res, supertokens.ConvertGeneralErrorToJsonResponse(*resp.GeneralError))
+	}
+	return supertokens.ErrorIfNoResponse(options.Res)
+}
+
+func RevokeUserSessionAPI(apiImplementation sessmodels.APIInterface, options sessmodels.APIOptions, userContext supertokens.UserContext) error {
+	if apiImplementation.RevokeUserSessionPOST == nil || (*apiImplementation.RevokeUserSessionPOST) == nil {
+		options.OtherHandler.ServeHTTP(options.Res, options.Req)
+		return nil
+	}
+
+	sessionContainer, err := GetSessionFromRequest(options.Req, options.Res, options.Config, getUserSessionOptions(), options.RecipeImplementation, userContext)
+	if err != nil {
+		return err
+	}
+
+	body, err := supertokens.ReadFromRequest(options.Req)
+	if err != nil {
+		return err
+	}
+	var readBody map[string]interface{}
+	err = json.Unmarshal(body, &readBody)
+	if err != nil {
+		return supertokens.BadInputError{Msg: "Please send a JSON body"}
+	}
+	sessionHandle, ok := readBody["sessionHandle"].(string)
+	if !ok || sessionHandle == "" {
+		return supertokens.BadInputError{Msg: "Please provide the sessionHandle"}
+	}
+
+	resp, err := (*apiImplementation.RevokeUserSessionPOST)(sessionHandle, sessionContainer, options, userContext)
+	if err != nil {
+		return err
+	}
+
+	if resp.OK != nil {
+		return supertokens.Send200Response(options.Res, map[string]interface{}{
+			"status": "OK",
+		})
+	} else if resp.UnknownSessionError != nil {
+		return supertokens.Send200Response(options.Res, map[string]interface{}{
+			"status": "UNKNOWN_SESSION_ERROR",
+		})
+	} else if resp.GeneralError != nil {
+		return supertokens.Send200Response(options.Res, supertokens.ConvertGeneralErrorToJsonResponse(*resp.GeneralError))
+	}
+	return supertokens.ErrorIfNoResponse(options.Res)
+}
@@ -0,0 +1,238 @@
+/* Copyright (c) 2023, VRAI Labs and/or its affiliates. All rights reserved.
+ *
+ * This software is licensed under the Apache License, Version 2.0 (the
+ * "License") as published by the Apache Software Foundation.
+ *
+ * You may not use this file except in compliance with the License. You may
+ * obtain a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+ * License for the specific language governing permissions and limitations
+ * under the License.
+ */
+
+package session
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/supertokens/supertokens-golang/recipe/session/sessmodels"
+	"github.com/supertokens/supertokens-golang/supertokens"
+)
+
+func TestSessionDeviceIsRecordedWhenTheUserSessionsAPIIsEnabled(t *testing.T) {
+	config := sessmodels.TypeNormalisedInput{UserSessionsAPI: normaliseUserSessionsAPIInput(nil)}
+	sessionData := addSessionDevice(config, map[string]interface{}{"key": "value"}, &map[string]interface{}{})
+	assert.Equal(t, map[string]interface{}{"key": "value"}, sessionData)
+
+	config.UserSessionsAPI = normaliseUserSessionsAPIInput(&sessmodels.UserSessionsAPIInput{})
+	req := httptest.NewRequest(http.MethodPost, "/auth/signin", nil)
+	req.Header.Set("User-Agent", "test-agent")
+	sessionData = addSessionDevice(config, map[string]interface{}{"key": "value"}, supertokens.MakeDefaultUserContextFromAPI(req))
+	assert.Equal(t, "value", sessionData["key"])
+
+	userSession := getUserSession(sessmodels.SessionInformation{
+		SessionHandle:         "handle1",
+		TimeCreated:           1000,
+		SessionDataInDatabase: sessionData,
+	}, "handle1")
+	assert.Equal(t, "test-agent", userSession.Device["userAgent"])
+	assert.Equal(t, "Unknown device", userSession.Device["name"])
+	assert.Equal(t, "192.0.2.1", userSession.Device["ip"])
+	assert.Greater(t, userSession.LastActiveAt, uint64(1000))
+	assert.True(t, userSession.IsCurrent)
+
+	// sessions created before the API was enabled
+	userSession = getUserSession(sessmodels.SessionInformation{
+		SessionHandle:         "handle2",
+		TimeCreated:           1000,
+		SessionDataInDatabase: map[string]interface{}{},
+	}, "handle1")
+	assert.Equal(t, map[string]interface{}{}, userSession.Device)
+	assert.Equal(t, uint64(1000), userSession.LastActiveAt)
+	assert.False(t, userSession.IsCurrent)
+}
+
+func TestTouchSessionDeviceUpdatesLastActiveAt(t *testing.T) {
+	var updatedSessionData map[string]interface{}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/apiversion", func(rw http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(rw).Encode(map[string]interface{}{"versions": []string{"3.0"}})
+	})
+	mux.HandleFunc("/recipe/session", func(rw http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(rw).Encode(map[string]interface{}{
+			"status":        "OK",
+			"sessionHandle": "handle1",
+			"userId":        "user1",
+			"tenantId":      "public",
+			"expiry":        2000,
+			"timeCreated":   1000,
+			"userDataInJWT": map[string]interface{}{},
+			"userDataInDatabase": map[string]interface{}{
+				"key":                "value",
+				userSessionDeviceKey: map[string]interface{}{"info": map[string]interface{}{"userAgent": "test-agent"}, "lastActiveAt": 1000},
+			},
+		})
+	})
+	mux.HandleFunc("/recipe/session/data", func(rw http.ResponseWriter, r *http.Request) {
+		var body map[string]interface{}
+		json.NewDecoder(r.Body).Decode(&body)
+		updatedSessionData = body["userDataInDatabase"].(map[string]interface{})
+		json.NewEncoder(rw).Encode(map[string]interface{}{"status": "OK"})
+	})
+	testServer := httptest.NewServer(mux)
+	defer testServer.Close()
+
+	resetAll()
+	defer resetAll()
+	err := supertokens.Init(supertokens.TypeInput{
+		Supertokens: &supertokens.ConnectionInfo{
+			ConnectionURI: testServer.URL,
+		},
+		AppInfo: supertokens.AppInfo{
+			AppName:       "SuperTokens",
+			WebsiteDomain: "supertokens.io",
+			APIDomain:     "api.supertokens.io",
+		},
+		RecipeList: []supertokens.Recipe{Init(nil)},
+	})
+	assert.NoError(t, err)
+	querier, err := supertokens.GetNewQuerierInstanceOrThrowError("session")
+	assert.NoError(t, err)
+
+	touchSessionDevice(sessmodels.TypeNormalisedInput{}, *querier, "handle1", &map[string]interface{}{})
+	assert.Nil(t, updatedSessionData)
+
+	config := sessmodels.TypeNormalisedInput{UserSessionsAPI: normaliseUserSessionsAPIInput(&sessmodels.UserSessionsAPIInput{})}
+	touchSessionDevice(config, *querier, "handle1", &map[string]interface{}{})
+	assert.Equal(t, "value", updatedSessionData["key"])
+	device := updatedSessionData[userSessionDeviceKey].(map[string]interface{})
+	assert.Equal(t, "test-agent", device["info"].(map[string]interface{})["userAgent"])
+	assert.Greater(t, device["lastActiveAt"], float64(1000))
+}
+
+func TestUserSessionsAPIs(t *testing.T) {
+	sessions := map[string]*sessmodels.SessionInformation{
+		"handle1": {SessionHandle: "handle1", UserId: "user1", TenantId: "public", SessionDataInDatabase: map[string]interface{}{}},
+		"handle2": {SessionHandle: "handle2", UserId: "user1", TenantId: "tenant1", SessionDataInDatabase: map[string]interface{}{}},
+		"handle3": {SessionHandle: "handle3", UserId: "user2", TenantId: "public", SessionDataInDatabase: map[string]interface{}{}},
+	}
+	getAllSessionHandlesForUser := func(userID string, tenantId string, fetchAcrossAllTenants *bool, userContext supertokens.UserContext) ([]string, error) {
+		assert.True(t, *fetchAcrossAllTenants)
+		result := []string{}
+		for _, handle := range []string{"handle1", "handle2", "handle3"} {
+			if sessions[handle] != nil && sessions[handle].UserId == userID {
+				result = append(result, handle)
+			}
+		}
+		return result, nil
+	}
+	getSessionInformation := func(sessionHandle string, userContext supertokens.UserContext) (*sessmodels.SessionInformation, error) {
+		return sessions[sessionHandle], nil
+	}
+	revokeSession := func(sessionHandle string, userContext supertokens.UserContext) (bool, error) {
+		delete(sessions, sessionHandle)
+		return true, nil
+	}
+	options := sessmodels.APIOptions{
+		RecipeImplementation: sessmodels.RecipeInterface{
+			GetAllSessionHandlesForUser: &getAllSessionHandlesForUser,
+			GetSessionInformation:       &getSessionInformation,
+			RevokeSession:               &revokeSession,
+		},
+	}
+	sessionContainer := &sessmodels.TypeSessionContainer{
+		GetUserIDWithContext:   func(userContext supertokens.UserContext) string { return "user1" },
+		GetTenantIdWithContext: func(userContext supertokens.UserContext) string { return "public" },
+		GetHandleWithContext:   func(userContext supertokens.UserContext) string { return "handle1" },
+	}
+	userContext := &map[string]interface{}{}
+	apiImpl := MakeAPIImplementation()
+
+	listResponse, err := (*apiImpl.UserSessionsGET)(sessionContainer, options, userContext)
+	assert.NoError(t, err)
+	assert.Len(t, listResponse.OK.Sessions, 2)
+	assert.True(t, listResponse.OK.Sessions[0].IsCurrent)
+	assert.Equal(t, "tenant1", listResponse.OK.Sessions[1].TenantId)
+	assert.False(t, listResponse.OK.Sessions[1].IsCurrent)
+
+	// sessions of other users cannot be revoked
+	revokeResponse, err := (*apiImpl.RevokeUserSessionPOST)("handle3", sessionContainer, options, userContext)
+	assert.NoError(t, err)
+	assert.NotNil(t, revokeResponse.UnknownSessionError)
+	assert.NotNil(t, sessions["handle3"])
+
+	revokeResponse, err = (*apiImpl.RevokeUserSessionPOST)("handle2", sessionContainer, options, userContext)
+	assert.NoError(t, err)
+	assert.NotNil(t, revokeResponse.OK)
+	assert.Nil(t, sessions["handle2"])
+
+	listResponse, err = (*apiImpl.UserSessionsGET)(sessionContainer, options, userContext)
+	assert.NoError(t, err)
+	assert.Len(t, listResponse.OK.Sessions, 1)
+}
+
+func TestGetDeviceName(t *testing.T) {
+	assert.Equal(t, "Chrome on macOS", getDeviceName("Mozilla/5.0 (Macintosh; Intel Mac OS X 10_15_7) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/117.0.0.0 Safari/537.36"))
+	assert.Equal(t, "Edge on Windows", getDeviceName("Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/117.0.0.0 Safari/537.36 Edg/117.0.2045.31"))
+	assert.Equal(t, "Safari on iOS", getDeviceName("Mozilla/5.0 (iPhone; CPU iPhone OS 17_0 like Mac OS X) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/17.0 Mobile/15E148 Safari/604.1"))
+	assert.Equal(t, "Firefox on Android", getDeviceName("Mozilla/5.0 (Android 13; Mobile; rv:109.0) Gecko/117.0 Firefox/117.0"))
+	assert.Equal(t, "Unknown device", getDeviceName("curl/8.1.2"))
+}
+
+func TestListDevicesForUser(t *testing.T) {
+	resetAll()
+	defer resetAll()
+	err := supertokens.Init(supertokens.TypeInput{
+		Supertokens: &supertokens.ConnectionInfo{
+			ConnectionURI: "http://localhost:8080",
+		},
+		AppInfo: supertokens.AppInfo{
+			AppName:       "SuperTokens",
+			WebsiteDomain: "supertokens.io",
+			APIDomain:     "api.supertokens.io",
+		},
+		RecipeList: []supertokens.Recipe{Init(nil)},
+	})
+	assert.NoError(t, err)
+	instance, err := getRecipeInstanceOrThrowError()
+	assert.NoError(t, err)
+
+	getAllSessionHandlesForUser := func(userID string, tenantId string, fetchAcrossAllTenants *bool, userContext supertokens.UserContext) ([]string, error) {
+		assert.Equal(t, "user1", userID)
+		assert.False(t, *fetchAcrossAllTenants)
+		assert.Equal(t, "tenant1", tenantId)
+		return []string{"handle1", "revoked"}, nil
+	}
+	getSessionInformation := func(sessionHandle string, userContext supertokens.UserContext) (*sessmodels.SessionInformation, error) {
+		if sessionHandle == "revoked" {
+			return nil, nil
+		}
+		return &sessmodels.SessionInformation{
+			SessionHandle: sessionHandle,
+			UserId:        "user1",
+			TenantId:      "tenant1",
+			TimeCreated:   1000,
+			SessionDataInDatabase: map[string]interface{}{
+				userSessionDeviceKey: map[string]interface{}{"info": map[string]interface{}{"name": "Firefox on Linux"}, "lastActiveAt": float64(2000)},
+			},
+		}, nil
+	}
+	instance.RecipeImpl.GetAllSessionHandlesForUser = &getAllSessionHandlesForUser
+	instance.RecipeImpl.GetSessionInformation = &getSessionInformation
+
+	tenantId := "tenant1"
+	devices, err := ListDevicesForUser("user1", &tenantId)
+	assert.NoError(t, err)
+	assert.Len(t, devices, 1)
+	assert.Equal(t, "handle1", devices[0].SessionHandle)
+	assert.Equal(t, "Firefox on Linux", devices[0].Device["name"])
+	assert.Equal(t, uint64(2000), devices[0].LastActiveAt)
+	assert.False(t, devices[0].IsCurrent)
+}
@@ -55,6 +55,10 @@ func ValidateAndNormaliseUserInput(appInfo supertokens.NormalisedAppinfo, config
 		if err != nil {
 			return sessmodels.TypeNormalisedInput{}, err
 		}
+		err = validateCookieDomain(*cookieDomain, appInfo)
+		if err != nil {
+			return sessmodels.TypeNormalisedInput{}, err
+		}
 	}
 
 	if config != nil && config.CookieSameSite != nil {
@@ -69,38 +73,35 @@ func ValidateAndNormaliseUserInput(appInfo supertokens.NormalisedAppinfo, config
 		if config != nil && config.CookieSameSite != nil {
 			return normaliseSameSiteOrThrowError(*config.CookieSameSite)
 		}
-		origin, err := appInfo.GetOrigin(request, userContext)
-		if err != nil {
-			return "", err
-		}
-		protocolOfWebsiteDomain, err := GetURLScheme(origin.GetAsStringDangerous())
-		if err != nil {
-			return "", err
-		}
-
-		protocolOfAPIDomain, err := GetURLScheme(appInfo.APIDomain.GetAsStringDangerous())
-		if err != nil {
-			return "", err
-		}
+		return getDefaultCookieSameSite(appInfo, request, userContext)
+	}
 
-		topLevelWebsiteDomain, err := appInfo.GetTopLevelWebsiteDomain(request, userContext)
+	// The same site value is known at init if it is set or if the website
+	// domain does not depend on the request
+	var staticCookieSameSite *string
+	if (config != nil && config.CookieSameSite != nil) || appInfo.HasStaticOrigin {
+		sameSite, err := cookieSameSite(nil, &map[string]interface{}{})
 		if err != nil {
-			return "", err
-		}
-
-		if protocolOfAPIDomain != protocolOfWebsiteDomain || appInfo.TopLevelAPIDomain != topLevelWebsiteDomain {
-			return CookieSameSite_NONE, nil
+			return sessmodels.TypeNormalisedInput{}, err
 		}
-		return CookieSameSite_LAX, nil
+		staticCookieSameSite = &sameSite
 	}
 
 	cookieSecure := false
 	if config == nil || config.CookieSecure == nil {
-		cookieSecure = strings.HasPrefix(appInfo.APIDomain.GetAsStringDangerous(), "https")
+		// Browsers only accept SameSite=None cookies that are secure
+		cookieSecure = strings.HasPrefix(appInfo.APIDomain.GetAsStringDangerous(), "https") || (staticCookieSameSite != nil && *staticCookieSameSite == CookieSameSite_NONE)
 	} else {
 		cookieSecure = *config.CookieSecure
 	}
 
+	if !cookieSecure && staticCookieSameSite != nil && *staticCookieSameSite == CookieSameSite_NONE && (config == nil || config.HeaderBasedAuthOnly == nil || !*config.HeaderBasedAuthOnly) {
+		if config != nil && config.CookieSameSite != nil {
+			return sessmodels.TypeNormalisedInput{}, errors.New("CookieSameSite is none, which browsers only accept for secure cookies, but CookieSecure is false. Please set CookieSecure to true and use https for your APIDomain")
+		}
+		return sessmodels.TypeNormalisedInput{}, errors.New("Since your API and website domains are on different sites, session cookies need SameSite=None, which browsers only accept for secure cookies, but CookieSecure is false. Please set CookieSecure to true and use https for your APIDomain, or host them on the same site")
+	}
+
 	sessionExpiredStatusCode := 401
 	if config != nil && config.SessionExpiredStatusCode != nil {
 		sessionExpiredStatusCode = *config.SessionExpiredStatusCode
@@ -172,6 +173,9 @@ func ValidateAndNormaliseUserInput(appInfo supertokens.NormalisedAppinfo, config
 			}
 			return sendInvalidClaimResponse(*recipeInstance, validationErrors, req, res)
 		},
+		OnSessionSuspended: func(sessionHandle string, reason string, req *http.Request, res http.ResponseWriter) error {
+			return sendSessionSuspendedResponse(reason, res)
+		},
 	}
 
 	if config != nil && config.ErrorHandlers != nil {
@@ -181,9 +185,15 @@ func ValidateAndNormaliseUserInput(appInfo supertokens.NormalisedAppinfo, config
 		if config.ErrorHandlers.OnUnauthorised != nil {
 			errorHandlers.OnUnauthorised = config.ErrorHandlers.OnUnauthorised
 		}
+		if config.ErrorHandlers.OnTryRefreshToken != nil {
+			errorHandlers.OnTryRefreshToken = config.ErrorHandlers.OnTryRefreshToken
+		}
 		if config.ErrorHandlers.OnInvalidClaim != nil {
 			errorHandlers.OnInvalidClaim = config.ErrorHandlers.OnInvalidClaim
 		}
+		if config.ErrorHandlers.OnSessionSuspended != nil {
+			errorHandlers.OnSessionSuspended = config.ErrorHandlers.OnSessionSuspended
+		}
 	}
 
 	refreshAPIPath, err := supertokens.NewNormalisedURLPath(RefreshAPIPath)
@@ -195,6 +205,28 @@ func ValidateAndNormaliseUserInput(appInfo supertokens.NormalisedAppinfo, config
 		config = &sessmodels.TypeInput{}
 	}
 
+	headerBasedAuthOnly := config.HeaderBasedAuthOnly != nil && *config.HeaderBasedAuthOnly
+	if headerBasedAuthOnly {
+		if config.GetTokenTransferMethod != nil {
+			return sessmodels.TypeNormalisedInput{}, errors.New("HeaderBasedAuthOnly cannot be used together with GetTokenTransferMethod")
+		}
+		config.GetTokenTransferMethod = headerOnlyGetTokenTransferMethod
+	}
+
+	hybridTokenTransfer := config.HybridTokenTransfer != nil && *config.HybridTokenTransfer
+	if hybridTokenTransfer {
+		if headerBasedAuthOnly {
+			return sessmodels.TypeNormalisedInput{}, errors.New("HybridTokenTransfer cannot be used together with HeaderBasedAuthOnly")
+		}
+		if config.GetTokenTransferMethod != nil {
+			return sessmodels.TypeNormalisedInput{}, errors.New("HybridTokenTransfer cannot be used together with GetTokenTransferMethod")
+		}
+		if antiCsrfFunctionOrString.StrValue == AntiCSRF_VIA_TOKEN {
+			return sessmodels.TypeNormalisedInput{}, errors.New("HybridTokenTransfer cannot be used together with AntiCsrf VIA_TOKEN. Please use VIA_CUSTOM_HEADER instead")
+		}
+		config.GetTokenTransferMethod = headerOnlyGetTokenTransferMethod
+	}
+
 	if config.GetTokenTransferMethod == nil {
 		config.GetTokenTransferMethod = defaultGetTokenTransferMethod
 	}
@@ -205,6 +237,92 @@ func ValidateAndNormaliseUserInput(appInfo supertokens.NormalisedAppinfo, config
 		useDynamicSigningKey = *config.UseDynamicAccessTokenSigningKey
 	}
 
+	presence, err := normalisePresenceInput(config.Presence)
+	if err != nil {
+		return sessmodels.TypeNormalisedInput{}, err
+	}
+
+	dpop, err := normaliseDPoPInput(config.DPoP)
+	if err != nil {
+		return sessmodels.TypeNormalisedInput{}, err
+	}
+
+	clientBinding, err := normaliseClientBindingInput(config.ClientBinding)
+	if err != nil {
+		return sessmodels.TypeNormalisedInput{}, err
+	}
+
+	verificationCache, err := normaliseVerificationCacheInput(config.VerificationCache)
+	if err != nil {
+		return sessmodels.TypeNormalisedInput{}, err
+	}
+
+	refreshDeduplication, err := normaliseRefreshDeduplicationInput(config.RefreshDeduplication)
+	if err != nil {
+		return sessmodels.TypeNormalisedInput{}, err
+	}
+
+	refreshThrottling, err := normaliseRefreshThrottlingInput(config.RefreshThrottling)
+	if err != nil {
+		return sessmodels.TypeNormalisedInput{}, err
+	}
+
+	rememberMe, err := normaliseRememberMeInput(config.RememberMe)
+	if err != nil {
+		return sessmodels.TypeNormalisedInput{}, err
+	}
+
+	sessionExpiration, err := normaliseSessionExpirationInput(config.SessionExpiration)
+	if err != nil {
+		return sessmodels.TypeNormalisedInput{}, err
+	}
+
+	sessionSuspension, err := normaliseSessionSuspensionInput(config.SessionSuspension)
+	if err != nil {
+		return sessmodels.TypeNormalisedInput{}, err
+	}
+
+	accessTokenPayloadSize, err := normaliseAccessTokenPayloadSizeInput(config.AccessTokenPayloadSize)
+	if err != nil {
+		return sessmodels.TypeNormalisedInput{}, err
+	}
+
+	revokeExistingSessionOnCreate := true
+	if config.RevokeExistingSessionOnCreate != nil {
+		revokeExistingSessionOnCreate = *config.RevokeExistingSessionOnCreate
+	}
+
+	accessTokenCookiePath := "/"
+	if config.AccessTokenCookiePath != nil {
+		accessTokenCookiePath = strings.TrimSpace(*config.AccessTokenCookiePath)
+		if !strings.HasPrefix(accessTokenCookiePath, "/") {
+			return sessmodels.TypeNormalisedInput{}, errors.New("AccessTokenCookiePath must start with /")
+		}
+	}
+
+	cookieNamePrefix := ""
+	if config.CookieNamePrefix != nil {
+		cookieNamePrefix = *config.CookieNamePrefix
+		err = validateCookieNamePrefix(cookieNamePrefix, cookieDomain, cookieSecure, accessTokenCookiePath)
+		if err != nil {
+			return sessmodels.TypeNormalisedInput{}, err
+		}
+	}
+
+	accessTokenIssuer := appInfo.APIDomain.GetAsStringDangerous() + appInfo.APIBasePath.GetAsStringDangerous()
+	if config.AccessTokenIssuer != nil {
+		accessTokenIssuer = strings.TrimSpace(*config.AccessTokenIssuer)
+		if accessTokenIssuer == "" {
+			return sessmodels.TypeNormalisedInput{}, errors.New("AccessTokenIssuer cannot be empty")
+		}
+	}
+
+	for claim := range config.AccessTokenStaticClaims {
+		if claim == "iss" || claim == "aud" || supertokens.DoesSliceContainString(claim, protectedProps) {
+			return sessmodels.TypeNormalisedInput{}, errors.New("AccessTokenStaticClaims cannot contain " + claim)
+		}
+	}
+
 	typeNormalisedInput := sessmodels.TypeNormalisedInput{
 		RefreshTokenPath:         appInfo.APIBasePath.AppendPath(refreshAPIPath),
 		CookieDomain:             cookieDomain,
@@ -215,6 +333,33 @@ func ValidateAndNormaliseUserInput(appInfo supertokens.NormalisedAppinfo, config
 		AntiCsrfFunctionOrString: antiCsrfFunctionOrString,
 		ExposeAccessTokenToFrontendInCookieBasedAuth: config.ExposeAccessTokenToFrontendInCookieBasedAuth,
 		UseDynamicAccessTokenSigningKey:              useDynamicSigningKey,
+		Presence:                                     presence,
+		RefreshTokenBinding:                          normaliseRefreshTokenBindingInput(config.RefreshTokenBinding),
+		DPoP:                                         dpop,
+		ClientBinding:                                clientBinding,
+		UserSessionsAPI:                              normaliseUserSessionsAPIInput(config.UserSessionsAPI),
+		AccessTokenCookiePath:                        accessTokenCookiePath,
+		CookieNamePrefix:                             cookieNamePrefix,
+		OnTokenTheft:                                 config.OnTokenTheft,
+		GetCookieAttributes:                          config.GetCookieAttributes,
+		VerificationCache:                            verificationCache,
+		RefreshDeduplication:                         refreshDeduplication,
+		RefreshThrottling:                            refreshThrottling,
+		RememberMe:                                   rememberMe,
+		SessionExpiration:                            sessionExpiration,
+		RevokeExistingSessionOnCreate:                revokeExistingSessionOnCreate,
+		OnSessionCreated:                             config.OnSessionCreated,
+		OnSessionRefreshed:                           config.OnSessionRefreshed,
+		OnSessionRevoked:                             config.OnSessionRevoked,
+		OnSessionExpired:                             config.OnSessionExpired,
+		JWTSigningAlgorithm:                          config.JWTSigningAlgorithm,
+		AccessTokenIssuer:                            accessTokenIssuer,
+		AccessTokenAudience:                          config.AccessTokenAudience,
+		AccessTokenStaticClaims:                      config.AccessTokenStaticClaims,
+		FrontTokenPayloadFields:                      config.FrontTokenPayloadFields,
+		HeaderBasedAuthOnly:                          headerBasedAuthOnly,
+		HybridTokenTransfer:                          hybridTokenTransfer,
+		AccessTokenPayloadSize:                       accessTokenPayloadSize,
 		ErrorHandlers:                                errorHandlers,
 		GetTokenTransferMethod:                       config.GetTokenTransferMethod,
 		Override: sessmodels.OverrideStruct{
@@ -224,6 +369,8 @@ func ValidateAndNormaliseUserInput(appInfo supertokens.NormalisedAppinfo, config
 				return originalImplementation
 			},
 			OpenIdFeature: nil},
+		SessionSuspension: sessionSuspension,
+		GuestSessions:     normaliseGuestSessionsInput(config.GuestSessions),
 	}
 
 	if config != nil && config.Override != nil {
@@ -241,6 +388,27 @@ func ValidateAndNormaliseUserInput(appInfo supertokens.NormalisedAppinfo, config
 
 var accessTokenCookiesExpiryDurationMillis = 3153600000000
 
+func validateCookieNamePrefix(cookieNamePrefix string, cookieDomain *string, cookieSecure bool, accessTokenCookiePath string) error {
+	if cookieNamePrefix == "" {
+		return nil
+	}
+	if cookieNamePrefix != CookieNamePrefix_HOST && cookieNamePrefix != CookieNamePrefix_SECURE {
+		return errors.New(`CookieNamePrefix must be one of "__Host-" or "__Secure-"`)
+	}
+	if !cookieSecure {
+		return errors.New("CookieNamePrefix requires CookieSecure to be true")
+	}
+	if cookieNamePrefix == CookieNamePrefix_HOST {
+		if cookieDomain != nil {
+			return errors.New(`CookieDomain cannot be set when CookieNamePrefix is "__Host-"`)
+		}
+		if accessTokenCookiePath != "/" {
+			return errors.New(`AccessTokenCookiePath must be "/" when CookieNamePrefix is "__Host-"`)
+		}
+	}
+	return nil
+}
+
 func normaliseSameSiteOrThrowError(sameSite string) (string, error) {
 	sameSite = strings.TrimSpace(sameSite)
 	sameSite = strings.ToLower(sameSite)
@@ -301,7 +469,11 @@ func SetAccessTokenInResponse(config sessmodels.TypeNormalisedInput, res http.Re
 	// This should be safe to do, since this is only the validity of the cookie (set here or on the frontend) but we check the expiration of the JWT anyway.
 	// Even if the token is expired the presence of the token indicates that the user could have a valid refresh
 	// Setting them to infinity would require special case handling on the frontend and just adding 100 years seems enough.
-	setToken(config, res, sessmodels.AccessToken, accessToken, GetCurrTimeInMS()+uint64(accessTokenCookiesExpiryDurationMillis), tokenTransferMethod, request, userContext)
+	accessTokenCookieExpiry := GetCurrTimeInMS() + uint64(accessTokenCookiesExpiryDurationMillis)
+	if parsedAccessToken, err := ParseJWTWithoutSignatureVerification(accessToken); err == nil && !isSessionRemembered(parsedAccessToken.Payload) {
+		accessTokenCookieExpiry = browserSessionCookieExpiry
+	}
+	setToken(config, res, sessmodels.AccessToken, accessToken, accessTokenCookieExpiry, tokenTransferMethod, request, userContext)
 
 	if config.ExposeAccessTokenToFrontendInCookieBasedAuth && tokenTransferMethod == sessmodels.CookieTransferMethod {
 		// We set the expiration to 100 years, because we can't really access the expiration of the refresh token everywhere we are setting it.
@@ -343,6 +515,10 @@ func ValidateClaimsInPayload(claimValidators []claims.SessionClaimValidator, new
 		claimValidationResult := validator.Validate(newAccessTokenPayload, userContext)
 		supertokens.LogDebugMessage(fmt.Sprint("validateClaimsInPayload ", validator.ID, " validation res ", claimValidationResult))
 		if !claimValidationResult.IsValid {
+			if supertokens.IsPolicyInShadowMode(supertokens.ShadowModeClaimPolicyPrefix + validator.ID) {
+				supertokens.ReportShadowModeViolation(supertokens.ShadowModeClaimPolicyPrefix+validator.ID, claimValidationResult.Reason, userContext)
+				continue
+			}
 			validationErrors = append(validationErrors, claims.ClaimValidationError{
 				ID:     validator.ID,
 				Reason: claimValidationResult.Reason,
@@ -352,6 +528,38 @@ func ValidateClaimsInPayload(claimValidators []claims.SessionClaimValidator, new
 	return validationErrors
 }
 
+// getDefaultCookieSameSite is "none" if the API and the website are on
+// different sites (scheme or top level domain) and "lax" otherwise
+func getDefaultCookieSameSite(appInfo supertokens.NormalisedAppinfo, request *http.Request, userContext supertokens.UserContext) (string, error) {
+	origin, err := appInfo.GetOrigin(request, userContext)
+	if err != nil {
+		return "", err
+	}
+	protocolOfWebsiteDomain, err := GetURLScheme(origin.GetAsStringDangerous())
+	if err != nil {
+		return "", err
+	}
+
+	protocolOfAPIDomain, err := GetURLScheme(appInfo.APIDomain.GetAsStringDangerous())
+	if err != nil {
+		return "", err
+	}
+
+	topLevelWebsiteDomain, err := appInfo.GetTopLevelWebsiteDomain(request, userContext)
+	if err != nil {
+		return "", err
+	}
+
+	if protocolOfAPIDomain != protocolOfWebsiteDomain || appInfo.TopLevelAPIDomain != topLevelWebsiteDomain {
+		return CookieSameSite_NONE, nil
+	}
+	return CookieSameSite_LAX, nil
+}
+
+func headerOnlyGetTokenTransferMethod(req *http.Request, forCreateNewSession bool, userContext supertokens.UserContext) sessmodels.TokenTransferMethod {
+	return sessmodels.HeaderTransferMethod
+}
+
 func defaultGetTokenTransferMethod(req *http.Request, forCreateNewSession bool, userContext supertokens.UserContext) sessmodels.TokenTransferMethod {
 	// We allow fallback (checking headers then cookies) by default when validating
 
@@ -0,0 +1,201 @@
+/* Copyright (c) 2023, VRAI Labs and/or its affiliates. All rights reserved.
+ *
+ * This software is licensed under the Apache License, Version 2.0 (the
+ * "License") as published by the Apache Software Foundation.
+ *
+ * You may not use this file except in compliance with the License. You may
+ * obtain a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+ * License for the specific language governing permissions and limitations
+ * under the License.
+ */
+
+package session
+
+import (
+	"container/list"
+	"errors"
+	"reflect"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/supertokens/supertokens-golang/recipe/session/sessmodels"
+)
+
+const (
+	defaultVerificationCacheTTL        = 5 * time.Second
+	defaultVerificationCacheMaxEntries = 10000
+)
+
+func normaliseVerificationCacheInput(config *sessmodels.VerificationCacheInput) (*sessmodels.NormalisedVerificationCacheConfig, error) {
+	if config == nil {
+		return nil, nil
+	}
+	result := &sessmodels.NormalisedVerificationCacheConfig{
+		TTL:        defaultVerificationCacheTTL,
+		MaxEntries: defaultVerificationCacheMaxEntries,
+	}
+	if config.TTL != nil {
+		if *config.TTL <= 0 {
+			return nil, errors.New("VerificationCache.TTL must be positive")
+		}
+		result.TTL = *config.TTL
+	}
+	if config.MaxEntries != nil {
+		if *config.MaxEntries <= 0 {
+			return nil, errors.New("VerificationCache.MaxEntries must be positive")
+		}
+		result.MaxEntries = *config.MaxEntries
+	}
+	return result, nil
+}
+
+// verificationCache keeps the responses of the core to session verification
+// requests, keyed by access token, so that bursts of requests with the same
+// token only call the core once. Entries of a session are dropped when it is
+// revoked or its access token payload is changed from this process, and all
+// entries of a user are dropped when all of their sessions are revoked.
+type verificationCache struct {
+	mutex      sync.Mutex
+	ttl        time.Duration
+	maxEntries int
+	entries    map[string]*list.Element
+	// order holds the keys of entries, oldest first, to evict when full
+	order    *list.List
+	byHandle map[string]map[string]bool
+	byUser   map[string]map[string]bool
+}
+
+type verificationCacheEntry struct {
+	key       string
+	handle    string
+	userID    string
+	expiresAt time.Time
+	response  sessmodels.GetSessionResponse
+}
+
+// newVerificationCache returns nil if the cache is disabled. All methods can
+// be called on a nil cache.
+func newVerificationCache(config *sessmodels.NormalisedVerificationCacheConfig) *verificationCache {
+	if config == nil {
+		return nil
+	}
+	return &verificationCache{
+		ttl:        config.TTL,
+		maxEntries: config.MaxEntries,
+		entries:    map[string]*list.Element{},
+		order:      list.New(),
+		byHandle:   map[string]map[string]bool{},
+		byUser:     map[string]map[string]bool{},
+	}
+}
+
+func getVerificationCacheKey(accessToken string, antiCsrfToken *string, doAntiCsrfCheck bool) string {
+	key := strconv.FormatBool(doAntiCsrfCheck) + ":" + accessToken
+	if antiCsrfToken != nil {
+		key += ":" + *antiCsrfToken
+	}
+	return key
+}
+
+func (c *verificationCache) get(key string) (sessmodels.GetSessionResponse, bool) {
+	if c == nil {
+		return sessmodels.GetSessionResponse{}, false
+	}
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	element, ok := c.entries[key]
+	if !ok {
+		return sessmodels.GetSessionResponse{}, false
+	}
+	entry := element.Value.(*verificationCacheEntry)
+	if !time.Now().Before(entry.expiresAt) {
+		c.remove(element)
+		return sessmodels.GetSessionResponse{}, false
+	}
+	return entry.response, true
+}
+
+// set caches response, unless the core issued a new access token with it
+// (which has to be sent to the client once)
+func (c *verificationCache) set(key string, response sessmodels.GetSessionResponse) {
+	if c == nil || !reflect.DeepEqual(response.AccessToken, sessmodels.CreateOrRefreshAPIResponseToken{}) {
+		return
+	}
+	now := time.Now()
+	expiresAt := now.Add(c.ttl)
+	accessTokenExpiry := time.UnixMilli(int64(response.Session.ExpiryTime))
+	if response.Session.ExpiryTime != 0 && accessTokenExpiry.Before(expiresAt) {
+		expiresAt = accessTokenExpiry
+	}
+	if !now.Before(expiresAt) {
+		return
+	}
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	if element, ok := c.entries[key]; ok {
+		c.remove(element)
+	}
+	for c.order.Len() >= c.maxEntries {
+		c.remove(c.order.Front())
+	}
+	entry := &verificationCacheEntry{
+		key:       key,
+		handle:    response.Session.Handle,
+		userID:    response.Session.UserID,
+		expiresAt: expiresAt,
+		response:  response,
+	}
+	c.entries[key] = c.order.PushBack(entry)
+	if c.byHandle[entry.handle] == nil {
+		c.byHandle[entry.handle] = map[string]bool{}
+	}
+	c.byHandle[entry.handle][key] = true
+	if c.byUser[entry.userID] == nil {
+		c.byUser[entry.userID] = map[string]bool{}
+	}
+	c.byUser[entry.userID][key] = true
+}
+
+func (c *verificationCache) invalidateSessions(sessionHandles ...string) {
+	if c == nil {
+		return
+	}
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	for _, sessionHandle := range sessionHandles {
+		for key := range c.byHandle[sessionHandle] {
+			c.remove(c.entries[key])
+		}
+	}
+}
+
+func (c *verificationCache) invalidateUser(userID string) {
+	if c == nil {
+		return
+	}
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	for key := range c.byUser[userID] {
+		c.remove(c.entries[key])
+	}
+}
+
+// remove must be called with the mutex held
+func (c *verificationCache) remove(element *list.Element) {
+	entry := c.order.Remove(element).(*verificationCacheEntry)
+	delete(c.entries, entry.key)
+	delete(c.byHandle[entry.handle], entry.key)
+	if len(c.byHandle[entry.handle]) == 0 {
+		delete(c.byHandle, entry.handle)
+	}
+	delete(c.byUser[entry.userID], entry.key)
+	if len(c.byUser[entry.userID]) == 0 {
+		delete(c.byUser, entry.userID)
+	}
+}
@@ -0,0 +1,152 @@
+/* Copyright (c) 2023, VRAI Labs and/or its affiliates. All rights reserved.
+ *
+ * This software is licensed under the Apache License, Version 2.0 (the
+ * "License") as published by the Apache Software Foundation.
+ *
+ * You may not use this file except in compliance with the License. You may
+ * obtain a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+ * License for the specific language governing permissions and limitations
+ * under the License.
+ */
+
+package session
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/supertokens/supertokens-golang/recipe/session/sessmodels"
+)
+
+func makeVerificationCacheResponse(handle string, expiry time.Time) sessmodels.GetSessionResponse {
+	return makeVerificationCacheResponseForUser(handle, "user1", expiry)
+}
+
+func makeVerificationCacheResponseForUser(handle string, userID string, expiry time.Time) sessmodels.GetSessionResponse {
+	return sessmodels.GetSessionResponse{
+		Status: "OK",
+		Session: sessmodels.SessionStruct{
+			Handle:     handle,
+			UserID:     userID,
+			ExpiryTime: uint64(expiry.UnixMilli()),
+		},
+	}
+}
+
+func TestVerificationCacheIsDisabledByDefault(t *testing.T) {
+	config, err := normaliseVerificationCacheInput(nil)
+	assert.NoError(t, err)
+	assert.Nil(t, config)
+
+	cache := newVerificationCache(config)
+	cache.set("key", makeVerificationCacheResponse("handle1", time.Now().Add(time.Hour)))
+	_, ok := cache.get("key")
+	assert.False(t, ok)
+	cache.invalidateSessions("handle1")
+}
+
+func TestVerificationCacheExpiresEntries(t *testing.T) {
+	ttl := 50 * time.Millisecond
+	config, err := normaliseVerificationCacheInput(&sessmodels.VerificationCacheInput{TTL: &ttl})
+	assert.NoError(t, err)
+	cache := newVerificationCache(config)
+
+	response := makeVerificationCacheResponse("handle1", time.Now().Add(time.Hour))
+	cache.set("key1", response)
+	cached, ok := cache.get("key1")
+	assert.True(t, ok)
+	assert.Equal(t, response, cached)
+
+	// entries do not outlive the access token
+	cache.set("key2", makeVerificationCacheResponse("handle2", time.Now().Add(10*time.Millisecond)))
+	time.Sleep(20 * time.Millisecond)
+	_, ok = cache.get("key2")
+	assert.False(t, ok)
+	_, ok = cache.get("key1")
+	assert.True(t, ok)
+
+	time.Sleep(40 * time.Millisecond)
+	_, ok = cache.get("key1")
+	assert.False(t, ok)
+}
+
+func TestVerificationCacheDoesNotCacheNewAccessTokens(t *testing.T) {
+	cache := newVerificationCache(&sessmodels.NormalisedVerificationCacheConfig{TTL: time.Minute, MaxEntries: 10})
+	response := makeVerificationCacheResponse("handle1", time.Now().Add(time.Hour))
+	response.AccessToken = sessmodels.CreateOrRefreshAPIResponseToken{Token: "newtoken"}
+	cache.set("key", response)
+	_, ok := cache.get("key")
+	assert.False(t, ok)
+}
+
+func TestVerificationCacheEvictsOldestEntriesWhenFull(t *testing.T) {
+	maxEntries := 2
+	config, err := normaliseVerificationCacheInput(&sessmodels.VerificationCacheInput{MaxEntries: &maxEntries})
+	assert.NoError(t, err)
+	cache := newVerificationCache(config)
+	expiry := time.Now().Add(time.Hour)
+
+	cache.set("key1", makeVerificationCacheResponse("handle1", expiry))
+	cache.set("key2", makeVerificationCacheResponse("handle2", expiry))
+	cache.set("key3", makeVerificationCacheResponse("handle3", expiry))
+
+	_, ok := cache.get("key1")
+	assert.False(t, ok)
+	_, ok = cache.get("key2")
+	assert.True(t, ok)
+	_, ok = cache.get("key3")
+	assert.True(t, ok)
+}
+
+func TestVerificationCacheInvalidatesSessions(t *testing.T) {
+	cache := newVerificationCache(&sessmodels.NormalisedVerificationCacheConfig{TTL: time.Minute, MaxEntries: 10})
+	expiry := time.Now().Add(time.Hour)
+
+	cache.set("key1", makeVerificationCacheResponse("handle1", expiry))
+	cache.set("key2", makeVerificationCacheResponse("handle1", expiry))
+	cache.set("key3", makeVerificationCacheResponse("handle2", expiry))
+	cache.invalidateSessions("handle1")
+
+	_, ok := cache.get("key1")
+	assert.False(t, ok)
+	_, ok = cache.get("key2")
+	assert.False(t, ok)
+	_, ok = cache.get("key3")
+	assert.True(t, ok)
+	assert.Equal(t, 1, cache.order.Len())
+	assert.Len(t, cache.byHandle, 1)
+}
+
+func TestVerificationCacheInvalidatesUsers(t *testing.T) {
+	cache := newVerificationCache(&sessmodels.NormalisedVerificationCacheConfig{TTL: time.Minute, MaxEntries: 10})
+	expiry := time.Now().Add(time.Hour)
+
+	cache.set("key1", makeVerificationCacheResponseForUser("handle1", "user1", expiry))
+	cache.set("key2", makeVerificationCacheResponseForUser("handle2", "user1", expiry))
+	cache.set("key3", makeVerificationCacheResponseForUser("handle3", "user2", expiry))
+	cache.invalidateUser("user1")
+
+	_, ok := cache.get("key1")
+	assert.False(t, ok)
+	_, ok = cache.get("key2")
+	assert.False(t, ok)
+	_, ok = cache.get("key3")
+	assert.True(t, ok)
+	assert.Len(t, cache.byHandle, 1)
+	assert.Len(t, cache.byUser, 1)
+}
+
+func TestVerificationCacheConfigValidation(t *testing.T) {
+	ttl := time.Duration(0)
+	_, err := normaliseVerificationCacheInput(&sessmodels.VerificationCacheInput{TTL: &ttl})
+	assert.EqualError(t, err, "VerificationCache.TTL must be positive")
+
+	maxEntries := 0
+	_, err = normaliseVerificationCacheInput(&sessmodels.VerificationCacheInput{MaxEntries: &maxEntries})
+	assert.EqualError(t, err, "VerificationCache.MaxEntries must be positive")
+}
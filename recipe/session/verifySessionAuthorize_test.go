@@ -0,0 +1,94 @@
+/* Copyright (c) 2023, VRAI Labs and/or its affiliates. All rights reserved.
+ *
+ * This software is licensed under the Apache License, Version 2.0 (the
+ * "License") as published by the Apache Software Foundation.
+ *
+ * You may not use this file except in compliance with the License. You may
+ * obtain a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+ * License for the specific language governing permissions and limitations
+ * under the License.
+ */
+
+package session
+
+import (
+	defaultErrors "errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/supertokens/supertokens-golang/recipe/session/claims"
+	"github.com/supertokens/supertokens-golang/recipe/session/errors"
+	"github.com/supertokens/supertokens-golang/recipe/session/sessmodels"
+	"github.com/supertokens/supertokens-golang/supertokens"
+)
+
+func TestVerifySessionAuthorize(t *testing.T) {
+	resetAll()
+	defer resetAll()
+	err := supertokens.Init(supertokens.TypeInput{
+		Supertokens: &supertokens.ConnectionInfo{
+			ConnectionURI: "http://localhost:8080",
+		},
+		AppInfo: supertokens.AppInfo{
+			AppName:       "SuperTokens",
+			WebsiteDomain: "supertokens.io",
+			APIDomain:     "api.supertokens.io",
+		},
+		RecipeList: []supertokens.Recipe{Init(nil)},
+	})
+	assert.NoError(t, err)
+	instance, err := getRecipeInstanceOrThrowError()
+	assert.NoError(t, err)
+
+	attached := false
+	sessionContainer := &sessmodels.TypeSessionContainer{
+		GetUserIDWithContext:   func(userContext supertokens.UserContext) string { return "user1" },
+		GetTenantIdWithContext: func(userContext supertokens.UserContext) string { return "public" },
+		AssertClaimsWithContext: func(claimValidators []claims.SessionClaimValidator, userContext supertokens.UserContext) error {
+			return nil
+		},
+		AttachToRequestResponseWithContext: func(info sessmodels.RequestResponseInfo, userContext supertokens.UserContext) error {
+			attached = true
+			return nil
+		},
+	}
+	recipeImpl := instance.RecipeImpl
+	getSession := func(accessToken *string, antiCsrfToken *string, options *sessmodels.VerifySessionOptions, userContext supertokens.UserContext) (sessmodels.SessionContainer, error) {
+		return sessionContainer, nil
+	}
+	recipeImpl.GetSession = &getSession
+
+	getSessionWithAuthorize := func(authorize func(sessionContainer sessmodels.SessionContainer, userContext supertokens.UserContext) error) (sessmodels.SessionContainer, error) {
+		req := httptest.NewRequest(http.MethodGet, "/admin", nil)
+		return GetSessionFromRequest(req, httptest.NewRecorder(), instance.Config, &sessmodels.VerifySessionOptions{Authorize: authorize}, recipeImpl, &map[string]interface{}{})
+	}
+
+	result, err := getSessionWithAuthorize(func(sessionContainer sessmodels.SessionContainer, userContext supertokens.UserContext) error {
+		assert.Equal(t, "user1", sessionContainer.GetUserIDWithContext(userContext))
+		return nil
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, sessmodels.SessionContainer(sessionContainer), result)
+	assert.True(t, attached)
+
+	attached = false
+	_, err = getSessionWithAuthorize(func(sessionContainer sessmodels.SessionContainer, userContext supertokens.UserContext) error {
+		return NewAuthorizationError("not an admin")
+	})
+	assert.Equal(t, errors.InvalidClaimError{
+		Msg:           "invalid claims",
+		InvalidClaims: []claims.ClaimValidationError{{ID: "authorization", Reason: "not an admin"}},
+	}, err)
+	assert.False(t, attached)
+
+	_, err = getSessionWithAuthorize(func(sessionContainer sessmodels.SessionContainer, userContext supertokens.UserContext) error {
+		return defaultErrors.New("database unavailable")
+	})
+	assert.EqualError(t, err, "database unavailable")
+}
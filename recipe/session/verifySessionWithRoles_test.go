@@ -0,0 +1,73 @@
+/* Copyright (c) 2023, VRAI Labs and/or its affiliates. All rights reserved.
+ *
+ * This software is licensed under the Apache License, Version 2.0 (the
+ * "License") as published by the Apache Software Foundation.
+ *
+ * You may not use this file except in compliance with the License. You may
+ * obtain a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+ * License for the specific language governing permissions and limitations
+ * under the License.
+ */
+
+package session
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/supertokens/supertokens-golang/recipe/session/claims"
+	"github.com/supertokens/supertokens-golang/recipe/session/sessmodels"
+	"github.com/supertokens/supertokens-golang/recipe/userroles/userrolesclaims"
+	"github.com/supertokens/supertokens-golang/supertokens"
+)
+
+func TestWithRequiredRolesNeedsTheUserRolesRecipe(t *testing.T) {
+	claim, validators := userrolesclaims.UserRoleClaim, userrolesclaims.UserRoleClaimValidators
+	defer func() {
+		userrolesclaims.UserRoleClaim, userrolesclaims.UserRoleClaimValidators = claim, validators
+	}()
+	userrolesclaims.UserRoleClaim = nil
+
+	// routes can be set up before supertokens.Init
+	options := WithRequiredRoles(nil, []string{"admin"})
+	_, err := options.OverrideGlobalClaimValidators([]claims.SessionClaimValidator{}, nil, &map[string]interface{}{})
+	assert.Error(t, err)
+}
+
+func TestWithRequiredRolesAddsARoleValidator(t *testing.T) {
+	claim, validators := userrolesclaims.UserRoleClaim, userrolesclaims.UserRoleClaimValidators
+	defer func() {
+		userrolesclaims.UserRoleClaim, userrolesclaims.UserRoleClaimValidators = claim, validators
+	}()
+	userrolesclaims.UserRoleClaim, userrolesclaims.UserRoleClaimValidators = claims.PrimitiveArrayClaim("st-role", func(userId string, tenantId string, userContext supertokens.UserContext) (interface{}, error) {
+		return []interface{}{}, nil
+	}, nil)
+
+	sessionRequired := false
+	overrideCalled := false
+	options := WithRequiredRoles(&sessmodels.VerifySessionOptions{
+		SessionRequired: &sessionRequired,
+		OverrideGlobalClaimValidators: func(globalClaimValidators []claims.SessionClaimValidator, sessionContainer sessmodels.SessionContainer, userContext supertokens.UserContext) ([]claims.SessionClaimValidator, error) {
+			overrideCalled = true
+			return globalClaimValidators, nil
+		},
+	}, []string{"admin", "editor"})
+	assert.False(t, *options.SessionRequired)
+
+	result, err := options.OverrideGlobalClaimValidators([]claims.SessionClaimValidator{}, nil, &map[string]interface{}{})
+	assert.NoError(t, err)
+	assert.True(t, overrideCalled)
+	assert.Len(t, result, 1)
+	assert.Equal(t, "st-role", result[0].ID)
+
+	now := float64(GetCurrTimeInMS())
+	payload := map[string]interface{}{"st-role": map[string]interface{}{"v": []interface{}{"admin"}, "t": now}}
+	assert.False(t, result[0].Validate(payload, &map[string]interface{}{}).IsValid)
+
+	payload = map[string]interface{}{"st-role": map[string]interface{}{"v": []interface{}{"admin", "editor"}, "t": now}}
+	assert.True(t, result[0].Validate(payload, &map[string]interface{}{}).IsValid)
+}
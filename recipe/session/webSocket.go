@@ -0,0 +1,126 @@
+/* Copyright (c) 2023, VRAI Labs and/or its affiliates. All rights reserved.
+ *
+ * This software is licensed under the Apache License, Version 2.0 (the
+ * "License") as published by the Apache Software Foundation.
+ *
+ * You may not use this file except in compliance with the License. You may
+ * obtain a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+ * License for the specific language governing permissions and limitations
+ * under the License.
+ */
+
+package session
+
+import (
+	defaultErrors "errors"
+	"net/http"
+	"time"
+
+	"github.com/supertokens/supertokens-golang/recipe/session/errors"
+	"github.com/supertokens/supertokens-golang/recipe/session/sessmodels"
+	"github.com/supertokens/supertokens-golang/supertokens"
+)
+
+// VerifySessionForWebSocket verifies the session of a WebSocket upgrade
+// request, before the connection is upgraded:
+//
+//	sessionContainer, err := session.VerifySessionForWebSocket(r, nil)
+//	if err != nil {
+//		supertokens.ErrorHandler(err, r, w)
+//		return
+//	}
+//	conn, err := upgrader.Upgrade(w, r, nil)
+//
+// Browsers cannot set headers on upgrade requests, so they must use cookie
+// based sessions. Since the anti-csrf check does not apply to GET requests,
+// requests sent by browsers from another origin than the website domain are
+// rejected with an unauthorised error. Changes made to the access token
+// during verification (for example by claim validators refetching values)
+// are not sent to the client.
+//
+// The session is only checked once, so long lived connections should call
+// ReverifyWebSocketSession before GetAccessTokenExpiry.
+func VerifySessionForWebSocket(r *http.Request, options *sessmodels.VerifySessionOptions, userContext ...supertokens.UserContext) (sessmodels.SessionContainer, error) {
+	instance, err := getRecipeInstanceOrThrowError()
+	if err != nil {
+		return nil, err
+	}
+	if len(userContext) == 0 {
+		userContext = append(userContext, supertokens.MakeDefaultUserContextFromAPI(r))
+	}
+	if origin := r.Header.Get("Origin"); origin != "" {
+		expectedOrigin, err := instance.RecipeModule.GetAppInfo().GetOrigin(r, userContext[0])
+		if err != nil {
+			return nil, err
+		}
+		normalisedOrigin, err := supertokens.NewNormalisedURLDomain(origin)
+		if err != nil || normalisedOrigin.GetAsStringDangerous() != expectedOrigin.GetAsStringDangerous() {
+			False := false
+			return nil, errors.UnauthorizedError{Msg: "WebSocket request from an unexpected origin", ClearTokens: &False}
+		}
+	}
+	return GetSessionFromRequest(r, &webSocketResponseWriter{header: http.Header{}}, instance.Config, options, instance.RecipeImpl, userContext[0])
+}
+
+// ReverifyWebSocketSession verifies an access token sent by the client on an
+// open connection, typically after it refreshed its session over HTTP, and
+// returns the session to use from now on. The access token must belong to
+// the same session as currentSession, otherwise an unauthorised error is
+// returned and the connection should be closed.
+func ReverifyWebSocketSession(currentSession sessmodels.SessionContainer, accessToken string, options *sessmodels.VerifySessionOptions, userContext ...supertokens.UserContext) (sessmodels.SessionContainer, error) {
+	if len(userContext) == 0 {
+		userContext = append(userContext, &map[string]interface{}{})
+	}
+	reverifyOptions := sessmodels.VerifySessionOptions{}
+	if options != nil {
+		reverifyOptions = *options
+	}
+	False := false
+	reverifyOptions.AntiCsrfCheck = &False
+	sessionContainer, err := GetSessionWithoutRequestResponse(accessToken, nil, &reverifyOptions, userContext...)
+	if err != nil {
+		return nil, err
+	}
+	if sessionContainer == nil {
+		return nil, errors.UnauthorizedError{Msg: "Session does not exist", ClearTokens: &False}
+	}
+	if sessionContainer.GetHandleWithContext(userContext[0]) != currentSession.GetHandleWithContext(userContext[0]) {
+		return nil, errors.UnauthorizedError{Msg: "Access token belongs to another session", ClearTokens: &False}
+	}
+	return sessionContainer, nil
+}
+
+// GetAccessTokenExpiry returns when the access token of sessionContainer
+// expires. After that, requests made with it fail with a try refresh token
+// error, so WebSocket connections should ask the client for a new access
+// token (see ReverifyWebSocketSession) or be closed by then.
+func GetAccessTokenExpiry(sessionContainer sessmodels.SessionContainer, userContext ...supertokens.UserContext) (time.Time, error) {
+	if len(userContext) == 0 {
+		userContext = append(userContext, &map[string]interface{}{})
+	}
+	exp := sanitizeNumberInputAsUint64(sessionContainer.GetAccessTokenPayloadWithContext(userContext[0])["exp"])
+	if exp == nil {
+		return time.Time{}, defaultErrors.New("the access token payload has no exp")
+	}
+	return time.Unix(int64(*exp), 0), nil
+}
+
+// webSocketResponseWriter discards what is written while verifying a
+// session, since upgrade requests are answered by the WebSocket library
+type webSocketResponseWriter struct {
+	header http.Header
+}
+
+func (w *webSocketResponseWriter) Header() http.Header {
+	return w.header
+}
+
+func (w *webSocketResponseWriter) Write(b []byte) (int, error) {
+	return len(b), nil
+}
+
+func (w *webSocketResponseWriter) WriteHeader(statusCode int) {}
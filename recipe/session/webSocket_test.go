@@ -0,0 +1,116 @@
+/* Copyright (c) 2023, VRAI Labs and/or its affiliates. All rights reserved.
+ *
+ * This software is licensed under the Apache License, Version 2.0 (the
+ * "License") as published by the Apache Software Foundation.
+ *
+ * You may not use this file except in compliance with the License. You may
+ * obtain a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+ * License for the specific language governing permissions and limitations
+ * under the License.
+ */
+
+package session
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/supertokens/supertokens-golang/recipe/session/claims"
+	"github.com/supertokens/supertokens-golang/recipe/session/errors"
+	"github.com/supertokens/supertokens-golang/recipe/session/sessmodels"
+	"github.com/supertokens/supertokens-golang/supertokens"
+)
+
+func TestVerifySessionForWebSocket(t *testing.T) {
+	resetAll()
+	defer resetAll()
+	err := supertokens.Init(supertokens.TypeInput{
+		Supertokens: &supertokens.ConnectionInfo{
+			ConnectionURI: "http://localhost:8080",
+		},
+		AppInfo: supertokens.AppInfo{
+			AppName:       "SuperTokens",
+			WebsiteDomain: "https://supertokens.io",
+			APIDomain:     "https://api.supertokens.io",
+		},
+		RecipeList: []supertokens.Recipe{Init(nil)},
+	})
+	assert.NoError(t, err)
+	instance, err := getRecipeInstanceOrThrowError()
+	assert.NoError(t, err)
+
+	exp := time.Now().Add(time.Hour).Truncate(time.Second)
+	makeSession := func(handle string) sessmodels.SessionContainer {
+		return &sessmodels.TypeSessionContainer{
+			GetHandleWithContext:   func(userContext supertokens.UserContext) string { return handle },
+			GetUserIDWithContext:   func(userContext supertokens.UserContext) string { return "user1" },
+			GetTenantIdWithContext: func(userContext supertokens.UserContext) string { return "public" },
+			GetAccessTokenPayloadWithContext: func(userContext supertokens.UserContext) map[string]interface{} {
+				return map[string]interface{}{"exp": float64(exp.Unix())}
+			},
+			AssertClaimsWithContext: func(claimValidators []claims.SessionClaimValidator, userContext supertokens.UserContext) error {
+				return nil
+			},
+			AttachToRequestResponseWithContext: func(info sessmodels.RequestResponseInfo, userContext supertokens.UserContext) error {
+				return nil
+			},
+		}
+	}
+	makeToken := func(handle string, iat int64) string {
+		token := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+			"sub": "user1", "sessionHandle": handle, "refreshTokenHash1": "hash", "iat": iat, "exp": exp.Unix(), "tId": "public",
+		})
+		token.Header["version"] = "4"
+		token.Header["kid"] = "d-1"
+		signed, err := token.SignedString([]byte("secret"))
+		assert.NoError(t, err)
+		return signed
+	}
+	token1 := makeToken("handle1", time.Now().Unix())
+	token2 := makeToken("handle1", time.Now().Unix()+1)
+	token3 := makeToken("handle2", time.Now().Unix())
+	sessions := map[string]sessmodels.SessionContainer{
+		token1: makeSession("handle1"),
+		token2: makeSession("handle1"),
+		token3: makeSession("handle2"),
+	}
+	getSession := func(accessToken *string, antiCsrfToken *string, options *sessmodels.VerifySessionOptions, userContext supertokens.UserContext) (sessmodels.SessionContainer, error) {
+		if accessToken == nil {
+			return nil, errors.UnauthorizedError{Msg: "Session does not exist"}
+		}
+		return sessions[*accessToken], nil
+	}
+	instance.RecipeImpl.GetSession = &getSession
+
+	req := httptest.NewRequest(http.MethodGet, "/ws", nil)
+	req.Header.Set("Origin", "https://supertokens.io")
+	req.AddCookie(&http.Cookie{Name: "sAccessToken", Value: token1})
+	sessionContainer, err := VerifySessionForWebSocket(req, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, sessions[token1], sessionContainer)
+
+	expiry, err := GetAccessTokenExpiry(sessionContainer)
+	assert.NoError(t, err)
+	assert.True(t, exp.Equal(expiry))
+
+	req = httptest.NewRequest(http.MethodGet, "/ws", nil)
+	req.Header.Set("Origin", "https://evil.com")
+	req.AddCookie(&http.Cookie{Name: "sAccessToken", Value: token1})
+	_, err = VerifySessionForWebSocket(req, nil)
+	assert.ErrorAs(t, err, &errors.UnauthorizedError{})
+
+	reverified, err := ReverifyWebSocketSession(sessionContainer, token2, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, sessions[token2], reverified)
+
+	_, err = ReverifyWebSocketSession(sessionContainer, token3, nil)
+	assert.ErrorAs(t, err, &errors.UnauthorizedError{})
+}
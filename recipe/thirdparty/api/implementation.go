@@ -26,6 +26,7 @@ import (
 	"github.com/supertokens/supertokens-golang/recipe/session"
 	"github.com/supertokens/supertokens-golang/recipe/session/sessmodels"
 	"github.com/supertokens/supertokens-golang/recipe/thirdparty/tpmodels"
+	"github.com/supertokens/supertokens-golang/recipe/usermetadata"
 	"github.com/supertokens/supertokens-golang/supertokens"
 )
 
@@ -95,10 +96,56 @@ func MakeAPIImplementation() tpmodels.APIInterface {
 			}
 		}
 
+		profileConfig := options.Config.ProfileFeature
+		if profileConfig.StoreInUserMetadata && userInfo.Profile != nil && (response.OK.CreatedNewUser || profileConfig.RefreshOnEveryLogin) {
+			profile, err := supertokens.StructToMap(*userInfo.Profile)
+			if err != nil {
+				return tpmodels.SignInUpPOSTResponse{}, err
+			}
+			_, err = usermetadata.UpdateUserMetadata(response.OK.User.ID, map[string]interface{}{
+				"profile": profile,
+			}, userContext)
+			if err != nil {
+				return tpmodels.SignInUpPOSTResponse{}, err
+			}
+		}
+
+		if response.OK.CreatedNewUser {
+			supertokens.SetIsSignUpInUserContext(userContext)
+		}
 		session, err := session.CreateNewSession(options.Req, options.Res, tenantId, response.OK.User.ID, nil, nil, userContext)
 		if err != nil {
 			return tpmodels.SignInUpPOSTResponse{}, err
 		}
+
+		eventType := supertokens.EventSignIn
+		auditAction := supertokens.AuditActionSignIn
+		if response.OK.CreatedNewUser {
+			eventType = supertokens.EventSignUp
+			auditAction = supertokens.AuditActionSignUp
+		}
+		supertokens.EmitEvent(supertokens.Event{
+			Type:     eventType,
+			TenantId: tenantId,
+			UserID:   response.OK.User.ID,
+			Data: map[string]interface{}{
+				"recipeId":      options.RecipeID,
+				"thirdPartyId":  provider.ID,
+				"sessionHandle": session.GetHandle(),
+			},
+		}, userContext)
+		supertokens.WriteAuditLog(supertokens.AuditEntry{
+			Action:   auditAction,
+			Result:   supertokens.AuditResultSuccess,
+			UserID:   response.OK.User.ID,
+			TenantId: tenantId,
+			RecipeID: options.RecipeID,
+			Data: map[string]interface{}{
+				"thirdPartyId":  provider.ID,
+				"sessionHandle": session.GetHandle(),
+			},
+		}, userContext)
+
 		return tpmodels.SignInUpPOSTResponse{
 			OK: &struct {
 				CreatedNewUser          bool
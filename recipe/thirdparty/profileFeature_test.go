@@ -0,0 +1,292 @@
+/* Copyright (c) 2021, VRAI Labs and/or its affiliates. All rights reserved.
+ *
+ * This software is licensed under the Apache License, Version 2.0 (the
+ * "License") as published by the Apache Software Foundation.
+ *
+ * You may not use this file except in compliance with the License. You may
+ * obtain a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+ * License for the specific language governing permissions and limitations
+ * under the License.
+ */
+
+package thirdparty
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/supertokens/supertokens-golang/recipe/session"
+	"github.com/supertokens/supertokens-golang/recipe/thirdparty/tpmodels"
+	"github.com/supertokens/supertokens-golang/recipe/usermetadata"
+	"github.com/supertokens/supertokens-golang/supertokens"
+	"github.com/supertokens/supertokens-golang/test/unittesting"
+)
+
+func TestThatProfileIsStoredInUserMetadataOnSignUp(t *testing.T) {
+	configValue := supertokens.TypeInput{
+		Supertokens: &supertokens.ConnectionInfo{
+			ConnectionURI: "http://localhost:8080",
+		},
+		AppInfo: supertokens.AppInfo{
+			APIDomain:     "api.supertokens.io",
+			AppName:       "SuperTokens",
+			WebsiteDomain: "supertokens.io",
+		},
+		RecipeList: []supertokens.Recipe{
+			session.Init(nil),
+			usermetadata.Init(nil),
+			Init(
+				&tpmodels.TypeInput{
+					SignInAndUpFeature: tpmodels.TypeInputSignInAndUp{
+						Providers: []tpmodels.ProviderInput{
+							{
+								Config: tpmodels.ProviderConfig{
+									ThirdPartyId:     "custom",
+									TokenEndpoint:    "http://127.0.0.1:8083/tokenendpoint",
+									UserInfoEndpoint: "http://127.0.0.1:8083/userinfo",
+									Clients: []tpmodels.ProviderClientConfig{
+										{
+											ClientID:     "test",
+											ClientSecret: "test-secret",
+										},
+									},
+								},
+							},
+						},
+					},
+					ProfileFeature: &tpmodels.TypeInputProfileFeature{
+						StoreInUserMetadata: true,
+					},
+				},
+			),
+		},
+	}
+
+	BeforeEach()
+	unittesting.StartUpST("localhost", "8080")
+	defer AfterEach()
+	defer usermetadata.ResetForTest()
+	err := supertokens.Init(configValue)
+	if err != nil {
+		t.Error(err.Error())
+	}
+
+	mux := http.NewServeMux()
+
+	mux.HandleFunc("/tokenendpoint", func(rw http.ResponseWriter, r *http.Request) {
+		rw.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(rw).Encode(map[string]interface{}{
+			"access_token": "accesstoken",
+		})
+	})
+
+	mux.HandleFunc("/userinfo", func(rw http.ResponseWriter, r *http.Request) {
+		rw.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(rw).Encode(map[string]interface{}{
+			"sub":            "testuserid",
+			"email":          "testinguser@supertokens.com",
+			"email_verified": true,
+			"name":           "Testing User",
+			"picture":        "https://example.com/avatar.png",
+			"locale":         "en-GB",
+		})
+	})
+
+	l, err := net.Listen("tcp", "127.0.0.1:8083")
+	if err != nil {
+		t.Error(err.Error())
+	}
+
+	testServer := httptest.NewUnstartedServer(supertokens.Middleware(mux))
+	testServer.Listener.Close()
+	testServer.Listener = l
+	testServer.Start()
+	defer testServer.Close()
+
+	res, err := http.Post(testServer.URL+"/auth/signinup", "application/json", strings.NewReader(`{"thirdPartyId": "custom", "redirectURIInfo": {"redirectURIOnProviderDashboard": "http://127.0.0.1/callback", "redirectURIQueryParams": {"code": "abcdefghj"}}}`))
+	assert.NoError(t, err)
+	assert.Equal(t, 200, res.StatusCode)
+
+	dataInBytes, err := ioutil.ReadAll(res.Body)
+	assert.NoError(t, err)
+	res.Body.Close()
+
+	var response map[string]interface{}
+	err = json.Unmarshal(dataInBytes, &response)
+	assert.NoError(t, err)
+	assert.Equal(t, "OK", response["status"])
+
+	userId := response["user"].(map[string]interface{})["id"].(string)
+	metadata, err := usermetadata.GetUserMetadata(userId)
+	assert.NoError(t, err)
+
+	assert.Equal(t, map[string]interface{}{
+		"name":          "Testing User",
+		"pictureUrl":    "https://example.com/avatar.png",
+		"locale":        "en-GB",
+		"emailVerified": true,
+	}, metadata["profile"])
+}
+
+func TestThatStoringProfileWithoutUserMetadataRecipeFailsInit(t *testing.T) {
+	configValue := supertokens.TypeInput{
+		Supertokens: &supertokens.ConnectionInfo{
+			ConnectionURI: "http://localhost:8080",
+		},
+		AppInfo: supertokens.AppInfo{
+			APIDomain:     "api.supertokens.io",
+			AppName:       "SuperTokens",
+			WebsiteDomain: "supertokens.io",
+		},
+		RecipeList: []supertokens.Recipe{
+			session.Init(nil),
+			Init(
+				&tpmodels.TypeInput{
+					ProfileFeature: &tpmodels.TypeInputProfileFeature{
+						StoreInUserMetadata: true,
+					},
+				},
+			),
+		},
+	}
+
+	BeforeEach()
+	unittesting.StartUpST("localhost", "8080")
+	defer AfterEach()
+	err := supertokens.Init(configValue)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "usermetadata")
+}
+
+func TestThatProfileIsRefreshedOnEveryLoginWhenEnabled(t *testing.T) {
+	profile := signInTwiceWithChangingProfile(t, true)
+	assert.Equal(t, "Renamed User", profile["name"])
+}
+
+func TestThatProfileIsNotRefreshedOnSignInByDefault(t *testing.T) {
+	profile := signInTwiceWithChangingProfile(t, false)
+	assert.Equal(t, "Testing User", profile["name"])
+}
+
+func signInTwiceWithChangingProfile(t *testing.T, refreshOnEveryLogin bool) map[string]interface{} {
+	configValue := supertokens.TypeInput{
+		Supertokens: &supertokens.ConnectionInfo{
+			ConnectionURI: "http://localhost:8080",
+		},
+		AppInfo: supertokens.AppInfo{
+			APIDomain:     "api.supertokens.io",
+			AppName:       "SuperTokens",
+			WebsiteDomain: "supertokens.io",
+		},
+		RecipeList: []supertokens.Recipe{
+			session.Init(nil),
+			usermetadata.Init(nil),
+			Init(
+				&tpmodels.TypeInput{
+					SignInAndUpFeature: tpmodels.TypeInputSignInAndUp{
+						Providers: []tpmodels.ProviderInput{
+							{
+								Config: tpmodels.ProviderConfig{
+									ThirdPartyId:     "custom",
+									TokenEndpoint:    "http://127.0.0.1:8083/tokenendpoint",
+									UserInfoEndpoint: "http://127.0.0.1:8083/userinfo",
+									Clients: []tpmodels.ProviderClientConfig{
+										{
+											ClientID:     "test",
+											ClientSecret: "test-secret",
+										},
+									},
+								},
+							},
+						},
+					},
+					ProfileFeature: &tpmodels.TypeInputProfileFeature{
+						StoreInUserMetadata: true,
+						RefreshOnEveryLogin: refreshOnEveryLogin,
+					},
+				},
+			),
+		},
+	}
+
+	BeforeEach()
+	unittesting.StartUpST("localhost", "8080")
+	defer AfterEach()
+	defer usermetadata.ResetForTest()
+	err := supertokens.Init(configValue)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	name := "Testing User"
+	mux := http.NewServeMux()
+
+	mux.HandleFunc("/tokenendpoint", func(rw http.ResponseWriter, r *http.Request) {
+		rw.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(rw).Encode(map[string]interface{}{
+			"access_token": "accesstoken",
+		})
+	})
+
+	mux.HandleFunc("/userinfo", func(rw http.ResponseWriter, r *http.Request) {
+		rw.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(rw).Encode(map[string]interface{}{
+			"sub":            "testuserid",
+			"email":          "testinguser@supertokens.com",
+			"email_verified": true,
+			"name":           name,
+		})
+	})
+
+	l, err := net.Listen("tcp", "127.0.0.1:8083")
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	testServer := httptest.NewUnstartedServer(supertokens.Middleware(mux))
+	testServer.Listener.Close()
+	testServer.Listener = l
+	testServer.Start()
+	defer testServer.Close()
+
+	signInUp := func() map[string]interface{} {
+		res, err := http.Post(testServer.URL+"/auth/signinup", "application/json", strings.NewReader(`{"thirdPartyId": "custom", "redirectURIInfo": {"redirectURIOnProviderDashboard": "http://127.0.0.1/callback", "redirectURIQueryParams": {"code": "abcdefghj"}}}`))
+		assert.NoError(t, err)
+		assert.Equal(t, 200, res.StatusCode)
+
+		dataInBytes, err := ioutil.ReadAll(res.Body)
+		assert.NoError(t, err)
+		res.Body.Close()
+
+		var response map[string]interface{}
+		err = json.Unmarshal(dataInBytes, &response)
+		assert.NoError(t, err)
+		assert.Equal(t, "OK", response["status"])
+		return response
+	}
+
+	first := signInUp()
+	assert.Equal(t, true, first["createdNewUser"])
+
+	name = "Renamed User"
+	second := signInUp()
+	assert.Equal(t, false, second["createdNewUser"])
+
+	userId := second["user"].(map[string]interface{})["id"].(string)
+	metadata, err := usermetadata.GetUserMetadata(userId)
+	assert.NoError(t, err)
+
+	profile, ok := metadata["profile"].(map[string]interface{})
+	assert.True(t, ok)
+	return profile
+}
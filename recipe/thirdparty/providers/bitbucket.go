@@ -101,9 +101,12 @@ func Bitbucket(input tpmodels.ProviderInput) *tpmodels.TypeProvider {
 				}
 			}
 
+			profile := getUserProfileForBitbucket(rawUserInfoFromProvider.FromUserInfoAPI, isVerified)
+
 			if email == "" {
 				return tpmodels.TypeUserInfo{
 					ThirdPartyUserId:        fmt.Sprint(rawUserInfoFromProvider.FromUserInfoAPI["uuid"]),
+					Profile:                 profile,
 					RawUserInfoFromProvider: rawUserInfoFromProvider,
 				}, nil
 			} else {
@@ -113,6 +116,7 @@ func Bitbucket(input tpmodels.ProviderInput) *tpmodels.TypeProvider {
 						ID:         email,
 						IsVerified: isVerified,
 					},
+					Profile:                 profile,
 					RawUserInfoFromProvider: rawUserInfoFromProvider,
 				}, nil
 			}
@@ -126,3 +130,20 @@ func Bitbucket(input tpmodels.ProviderInput) *tpmodels.TypeProvider {
 
 	return NewProvider(input)
 }
+
+func getUserProfileForBitbucket(userInfo map[string]interface{}, emailVerified bool) *tpmodels.TypeUserProfile {
+	profile := tpmodels.TypeUserProfile{
+		EmailVerified: emailVerified,
+	}
+	if name, ok := userInfo["display_name"].(string); ok {
+		profile.Name = name
+	}
+	if links, ok := userInfo["links"].(map[string]interface{}); ok {
+		if avatar, ok := links["avatar"].(map[string]interface{}); ok {
+			if href, ok := avatar["href"].(string); ok {
+				profile.PictureURL = href
+			}
+		}
+	}
+	return &profile
+}
@@ -177,6 +177,24 @@ func mergeConfig(staticConfig tpmodels.ProviderConfig, coreConfig tpmodels.Provi
 	if coreConfig.UserInfoMap.FromUserInfoAPI.UserId != "" {
 		result.UserInfoMap.FromUserInfoAPI.UserId = coreConfig.UserInfoMap.FromUserInfoAPI.UserId
 	}
+	if coreConfig.UserInfoMap.FromIdTokenPayload.Name != "" {
+		result.UserInfoMap.FromIdTokenPayload.Name = coreConfig.UserInfoMap.FromIdTokenPayload.Name
+	}
+	if coreConfig.UserInfoMap.FromIdTokenPayload.Picture != "" {
+		result.UserInfoMap.FromIdTokenPayload.Picture = coreConfig.UserInfoMap.FromIdTokenPayload.Picture
+	}
+	if coreConfig.UserInfoMap.FromIdTokenPayload.Locale != "" {
+		result.UserInfoMap.FromIdTokenPayload.Locale = coreConfig.UserInfoMap.FromIdTokenPayload.Locale
+	}
+	if coreConfig.UserInfoMap.FromUserInfoAPI.Name != "" {
+		result.UserInfoMap.FromUserInfoAPI.Name = coreConfig.UserInfoMap.FromUserInfoAPI.Name
+	}
+	if coreConfig.UserInfoMap.FromUserInfoAPI.Picture != "" {
+		result.UserInfoMap.FromUserInfoAPI.Picture = coreConfig.UserInfoMap.FromUserInfoAPI.Picture
+	}
+	if coreConfig.UserInfoMap.FromUserInfoAPI.Locale != "" {
+		result.UserInfoMap.FromUserInfoAPI.Locale = coreConfig.UserInfoMap.FromUserInfoAPI.Locale
+	}
 
 	// Merge the clients
 	mergedClients := append([]tpmodels.ProviderClientConfig{}, staticConfig.Clients...)
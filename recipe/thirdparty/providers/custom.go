@@ -48,6 +48,24 @@ func NewProvider(input tpmodels.ProviderInput) *tpmodels.TypeProvider {
 	if input.Config.UserInfoMap.FromUserInfoAPI.EmailVerified == "" {
 		input.Config.UserInfoMap.FromUserInfoAPI.EmailVerified = "email_verified"
 	}
+	if input.Config.UserInfoMap.FromIdTokenPayload.Name == "" {
+		input.Config.UserInfoMap.FromIdTokenPayload.Name = "name"
+	}
+	if input.Config.UserInfoMap.FromIdTokenPayload.Picture == "" {
+		input.Config.UserInfoMap.FromIdTokenPayload.Picture = "picture"
+	}
+	if input.Config.UserInfoMap.FromIdTokenPayload.Locale == "" {
+		input.Config.UserInfoMap.FromIdTokenPayload.Locale = "locale"
+	}
+	if input.Config.UserInfoMap.FromUserInfoAPI.Name == "" {
+		input.Config.UserInfoMap.FromUserInfoAPI.Name = "name"
+	}
+	if input.Config.UserInfoMap.FromUserInfoAPI.Picture == "" {
+		input.Config.UserInfoMap.FromUserInfoAPI.Picture = "picture"
+	}
+	if input.Config.UserInfoMap.FromUserInfoAPI.Locale == "" {
+		input.Config.UserInfoMap.FromUserInfoAPI.Locale = "locale"
+	}
 
 	if input.Config.GenerateFakeEmail == nil {
 		input.Config.GenerateFakeEmail = func(thirdPartyUserId string, tenantId string, userContext supertokens.UserContext) string {
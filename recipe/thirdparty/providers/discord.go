@@ -49,6 +49,10 @@ func Discord(input tpmodels.ProviderInput) *tpmodels.TypeProvider {
 		input.Config.UserInfoMap.FromUserInfoAPI.EmailVerified = "verified"
 	}
 
+	if input.Config.UserInfoMap.FromUserInfoAPI.Name == "" {
+		input.Config.UserInfoMap.FromUserInfoAPI.Name = "global_name"
+	}
+
 	oOverride := input.Override
 
 	input.Override = func(originalImplementation *tpmodels.TypeProvider) *tpmodels.TypeProvider {
@@ -66,6 +70,23 @@ func Discord(input tpmodels.ProviderInput) *tpmodels.TypeProvider {
 			return config, nil
 		}
 
+		oGetUserInfo := originalImplementation.GetUserInfo
+		originalImplementation.GetUserInfo = func(oAuthTokens tpmodels.TypeOAuthTokens, userContext supertokens.UserContext) (tpmodels.TypeUserInfo, error) {
+			userInfo, err := oGetUserInfo(oAuthTokens, userContext)
+			if err != nil {
+				return tpmodels.TypeUserInfo{}, err
+			}
+			profile := tpmodels.TypeUserProfile{}
+			if userInfo.Profile != nil {
+				profile = *userInfo.Profile
+			}
+			setDiscordProfileDefaults(&profile, userInfo.RawUserInfoFromProvider.FromUserInfoAPI)
+			if profile != (tpmodels.TypeUserProfile{}) {
+				userInfo.Profile = &profile
+			}
+			return userInfo, nil
+		}
+
 		if oOverride != nil {
 			originalImplementation = oOverride(originalImplementation)
 		}
@@ -74,3 +95,20 @@ func Discord(input tpmodels.ProviderInput) *tpmodels.TypeProvider {
 
 	return NewProvider(input)
 }
+
+// setDiscordProfileDefaults falls back to the username when the user has no
+// display name, and turns the avatar hash into a URL
+func setDiscordProfileDefaults(profile *tpmodels.TypeUserProfile, userInfo map[string]interface{}) {
+	if profile.Name == "" {
+		if username, ok := userInfo["username"].(string); ok {
+			profile.Name = username
+		}
+	}
+	if profile.PictureURL == "" {
+		id, _ := userInfo["id"].(string)
+		avatar, _ := userInfo["avatar"].(string)
+		if id != "" && avatar != "" {
+			profile.PictureURL = "https://cdn.discordapp.com/avatars/" + id + "/" + avatar + ".png"
+		}
+	}
+}
@@ -41,6 +41,10 @@ func Facebook(input tpmodels.ProviderInput) *tpmodels.TypeProvider {
 		input.Config.UserInfoMap.FromUserInfoAPI.UserId = "id"
 	}
 
+	if input.Config.UserInfoMap.FromUserInfoAPI.Picture == "" {
+		input.Config.UserInfoMap.FromUserInfoAPI.Picture = "picture.data.url"
+	}
+
 	oOverride := input.Override
 
 	input.Override = func(originalImplementation *tpmodels.TypeProvider) *tpmodels.TypeProvider {
@@ -68,7 +72,7 @@ func Facebook(input tpmodels.ProviderInput) *tpmodels.TypeProvider {
 				originalImplementation.Config.UserInfoEndpointQueryParams["access_token"] = oAuthTokens["access_token"]
 			}
 			if _, ok := originalImplementation.Config.UserInfoEndpointQueryParams["fields"]; !ok {
-				originalImplementation.Config.UserInfoEndpointQueryParams["fields"] = "id,email"
+				originalImplementation.Config.UserInfoEndpointQueryParams["fields"] = "id,email,name,picture"
 			}
 			if _, ok := originalImplementation.Config.UserInfoEndpointQueryParams["format"]; !ok {
 				originalImplementation.Config.UserInfoEndpointQueryParams["format"] = "json"
@@ -116,6 +116,7 @@ func Github(input tpmodels.ProviderInput) *tpmodels.TypeProvider {
 			return tpmodels.TypeUserInfo{
 				ThirdPartyUserId:        userInfoResult.ThirdPartyUserId,
 				Email:                   userInfoResult.Email,
+				Profile:                 userInfoResult.Profile,
 				RawUserInfoFromProvider: rawUserInfoResponseFromProvider,
 			}, nil
 		}
@@ -151,5 +152,18 @@ func getSupertokensUserInfoFromRawUserInfoResponseForGithub(rawUserInfoResponse
 		}
 	}
 
+	profile := tpmodels.TypeUserProfile{}
+	user := rawUserInfoResponse.FromUserInfoAPI["user"].(map[string]interface{})
+	if name, ok := user["name"].(string); ok {
+		profile.Name = name
+	}
+	if avatarURL, ok := user["avatar_url"].(string); ok {
+		profile.PictureURL = avatarURL
+	}
+	if result.Email != nil {
+		profile.EmailVerified = result.Email.IsVerified
+	}
+	result.Profile = &profile
+
 	return result, nil
 }
@@ -66,6 +66,7 @@ func Linkedin(input tpmodels.ProviderInput) *tpmodels.TypeProvider {
 			return tpmodels.TypeUserInfo{
 				ThirdPartyUserId:        userInfoResult.ThirdPartyUserId,
 				Email:                   userInfoResult.Email,
+				Profile:                 getUserProfileForLinkedin(rawUserInfoFromProvider.FromUserInfoAPI, userInfoResult.Email),
 				RawUserInfoFromProvider: rawUserInfoFromProvider,
 			}, nil
 		}
@@ -78,3 +79,26 @@ func Linkedin(input tpmodels.ProviderInput) *tpmodels.TypeProvider {
 
 	return NewProvider(input)
 }
+
+func getUserProfileForLinkedin(userInfo map[string]interface{}, email *tpmodels.EmailStruct) *tpmodels.TypeUserProfile {
+	profile := tpmodels.TypeUserProfile{
+		EmailVerified: email.IsVerified,
+	}
+	if name, ok := userInfo["name"].(string); ok {
+		profile.Name = name
+	}
+	if picture, ok := userInfo["picture"].(string); ok {
+		profile.PictureURL = picture
+	}
+	// the locale is returned as {"country": "US", "language": "en"}
+	if locale, ok := userInfo["locale"].(map[string]interface{}); ok {
+		language, _ := locale["language"].(string)
+		country, _ := locale["country"].(string)
+		if language != "" && country != "" {
+			profile.Locale = language + "-" + country
+		} else {
+			profile.Locale = language
+		}
+	}
+	return &profile
+}
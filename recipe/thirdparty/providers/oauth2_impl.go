@@ -127,7 +127,7 @@ func oauth2_GetUserInfo(config tpmodels.ProviderConfigForClientType, oAuthTokens
 		if err != nil {
 			return tpmodels.TypeUserInfo{}, err
 		}
-		token, err := jwt.ParseWithClaims(idToken, claims, jwks.Keyfunc)
+		token, err := jwt.ParseWithClaims(idToken, claims, jwks.Keyfunc, supertokens.GetJWTParserOptions()...)
 		if err != nil {
 			return tpmodels.TypeUserInfo{}, err
 		}
@@ -189,6 +189,7 @@ func oauth2_GetUserInfo(config tpmodels.ProviderConfigForClientType, oAuthTokens
 	return tpmodels.TypeUserInfo{
 		ThirdPartyUserId:        userInfoResult.ThirdPartyUserId,
 		Email:                   userInfoResult.Email,
+		Profile:                 userInfoResult.Profile,
 		RawUserInfoFromProvider: rawUserInfoFromProvider,
 	}, nil
 }
@@ -254,5 +255,55 @@ func oauth2_getSupertokensUserInfoResultFromRawUserInfo(config tpmodels.Provider
 
 	}
 
+	result.Profile = oauth2_getUserProfileFromRawUserInfo(config, rawUserInfoResponse, result.Email)
+
 	return result, nil
 }
+
+func oauth2_getUserProfileFromRawUserInfo(config tpmodels.ProviderConfigForClientType, rawUserInfoResponse tpmodels.TypeRawUserInfoFromProvider, email *tpmodels.EmailStruct) *tpmodels.TypeUserProfile {
+	profile := tpmodels.TypeUserProfile{}
+
+	// Values from the id token payload take precedence over the ones from the user info API,
+	// which is consistent with how the user id and email are resolved above
+	for _, source := range []struct {
+		rawUserInfo map[string]interface{}
+		fields      tpmodels.TypeUserInfoMapFields
+	}{
+		{rawUserInfoResponse.FromUserInfoAPI, config.UserInfoMap.FromUserInfoAPI},
+		{rawUserInfoResponse.FromIdTokenPayload, config.UserInfoMap.FromIdTokenPayload},
+	} {
+		if source.rawUserInfo == nil {
+			continue
+		}
+		if source.fields.Name != "" {
+			if name, ok := accessField(source.rawUserInfo, source.fields.Name); ok {
+				if nameStr, ok := name.(string); ok && nameStr != "" {
+					profile.Name = nameStr
+				}
+			}
+		}
+		if source.fields.Picture != "" {
+			if picture, ok := accessField(source.rawUserInfo, source.fields.Picture); ok {
+				if pictureStr, ok := picture.(string); ok && pictureStr != "" {
+					profile.PictureURL = pictureStr
+				}
+			}
+		}
+		if source.fields.Locale != "" {
+			if locale, ok := accessField(source.rawUserInfo, source.fields.Locale); ok {
+				if localeStr, ok := locale.(string); ok && localeStr != "" {
+					profile.Locale = localeStr
+				}
+			}
+		}
+	}
+
+	if email != nil {
+		profile.EmailVerified = email.IsVerified
+	}
+
+	if profile == (tpmodels.TypeUserProfile{}) {
+		return nil
+	}
+	return &profile
+}
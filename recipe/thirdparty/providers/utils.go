@@ -135,6 +135,7 @@ func doPostRequest(url string, params map[string]interface{}, headers map[string
 }
 
 // JWKS utils
+
 var jwksKeys = map[string]*keyfunc.JWKS{}
 var jwksKeysLock = sync.Mutex{}
 
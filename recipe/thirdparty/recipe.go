@@ -26,6 +26,7 @@ import (
 	"github.com/supertokens/supertokens-golang/recipe/thirdparty/api"
 	"github.com/supertokens/supertokens-golang/recipe/thirdparty/tperrors"
 	"github.com/supertokens/supertokens-golang/recipe/thirdparty/tpmodels"
+	"github.com/supertokens/supertokens-golang/recipe/usermetadata"
 	"github.com/supertokens/supertokens-golang/supertokens"
 )
 
@@ -84,6 +85,9 @@ func recipeInit(config *tpmodels.TypeInput) supertokens.Recipe {
 				return nil, err
 			}
 			singletonInstance = &recipe
+			if recipe.Config.ProfileFeature.StoreInUserMetadata {
+				singletonInstance.RecipeModule.DependsOn(usermetadata.RECIPE_ID)
+			}
 			return &singletonInstance.RecipeModule, nil
 		}
 		return nil, errors.New("ThirdParty recipe has already been initialised. Please check your code for bugs.")
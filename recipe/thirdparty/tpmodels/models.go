@@ -30,9 +30,19 @@ type TypeRawUserInfoFromProvider struct {
 type TypeUserInfo struct {
 	ThirdPartyUserId        string
 	Email                   *EmailStruct
+	Profile                 *TypeUserProfile
 	RawUserInfoFromProvider TypeRawUserInfoFromProvider
 }
 
+// TypeUserProfile is the provider independent view of the standard profile
+// fields returned by a third party provider
+type TypeUserProfile struct {
+	Name          string `json:"name,omitempty"`
+	PictureURL    string `json:"pictureUrl,omitempty"`
+	Locale        string `json:"locale,omitempty"`
+	EmailVerified bool   `json:"emailVerified"`
+}
+
 type EmailStruct struct {
 	ID         string `json:"id"`
 	IsVerified bool   `json:"isVerified"`
@@ -60,6 +70,9 @@ type TypeUserInfoMapFields struct {
 	UserId        string `json:"userId,omitempty"`
 	Email         string `json:"email,omitempty"`
 	EmailVerified string `json:"emailVerified,omitempty"`
+	Name          string `json:"name,omitempty"`
+	Picture       string `json:"picture,omitempty"`
+	Locale        string `json:"locale,omitempty"`
 }
 
 type TypeUserInfoMap struct {
@@ -86,13 +99,29 @@ type TypeNormalisedInputSignInAndUp struct {
 	Providers []ProviderInput
 }
 
+type TypeInputProfileFeature struct {
+	// If true, the normalised profile of the user is saved in the user metadata
+	// (under the "profile" key) when the user signs up. This requires the
+	// usermetadata recipe to be initialised.
+	StoreInUserMetadata bool
+	// If true, the stored profile is updated on every sign in as well.
+	RefreshOnEveryLogin bool
+}
+
+type TypeNormalisedInputProfileFeature struct {
+	StoreInUserMetadata bool
+	RefreshOnEveryLogin bool
+}
+
 type TypeInput struct {
 	SignInAndUpFeature TypeInputSignInAndUp
+	ProfileFeature     *TypeInputProfileFeature
 	Override           *OverrideStruct
 }
 
 type TypeNormalisedInput struct {
 	SignInAndUpFeature TypeNormalisedInputSignInAndUp
+	ProfileFeature     TypeNormalisedInputProfileFeature
 	Override           OverrideStruct
 }
 
@@ -35,6 +35,13 @@ func validateAndNormaliseUserInput(recipeInstance *Recipe, appInfo supertokens.N
 	}
 	typeNormalisedInput.SignInAndUpFeature = signInAndUpFeature
 
+	if config.ProfileFeature != nil {
+		typeNormalisedInput.ProfileFeature = tpmodels.TypeNormalisedInputProfileFeature{
+			StoreInUserMetadata: config.ProfileFeature.StoreInUserMetadata,
+			RefreshOnEveryLogin: config.ProfileFeature.RefreshOnEveryLogin,
+		}
+	}
+
 	if config != nil && config.Override != nil {
 		if config.Override.Functions != nil {
 			typeNormalisedInput.Override.Functions = config.Override.Functions
@@ -28,6 +28,7 @@ import (
 	"github.com/supertokens/supertokens-golang/recipe/thirdpartyemailpassword/api"
 	"github.com/supertokens/supertokens-golang/recipe/thirdpartyemailpassword/recipeimplementation"
 	"github.com/supertokens/supertokens-golang/recipe/thirdpartyemailpassword/tpepmodels"
+	"github.com/supertokens/supertokens-golang/recipe/usermetadata"
 	"github.com/supertokens/supertokens-golang/supertokens"
 )
 
@@ -105,6 +106,7 @@ func MakeRecipe(recipeId string, appInfo supertokens.NormalisedAppinfo, config *
 			SignInAndUpFeature: tpmodels.TypeInputSignInAndUp{
 				Providers: verifiedConfig.Providers,
 			},
+			ProfileFeature: verifiedConfig.ProfileFeature,
 			Override: &tpmodels.OverrideStruct{
 				Functions: func(_ tpmodels.RecipeInterface) tpmodels.RecipeInterface {
 					return recipeimplementation.MakeThirdPartyRecipeImplementation(r.RecipeImpl)
@@ -138,6 +140,9 @@ func recipeInit(config *tpepmodels.TypeInput) supertokens.Recipe {
 				return nil, err
 			}
 			singletonInstance = &recipe
+			if recipe.Config.ProfileFeature != nil && recipe.Config.ProfileFeature.StoreInUserMetadata {
+				singletonInstance.RecipeModule.DependsOn(usermetadata.RECIPE_ID)
+			}
 			return &singletonInstance.RecipeModule, nil
 		}
 		return nil, errors.New("ThirdPartyEmailPassword recipe has already been initialised. Please check your code for bugs.")
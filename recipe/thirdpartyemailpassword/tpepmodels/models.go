@@ -38,15 +38,17 @@ type TypeContext struct {
 }
 
 type TypeInput struct {
-	SignUpFeature *epmodels.TypeInputSignUp
-	Providers     []tpmodels.ProviderInput
-	Override      *OverrideStruct
-	EmailDelivery *emaildelivery.TypeInput
+	SignUpFeature  *epmodels.TypeInputSignUp
+	Providers      []tpmodels.ProviderInput
+	ProfileFeature *tpmodels.TypeInputProfileFeature
+	Override       *OverrideStruct
+	EmailDelivery  *emaildelivery.TypeInput
 }
 
 type TypeNormalisedInput struct {
 	SignUpFeature          *epmodels.TypeInputSignUp
 	Providers              []tpmodels.ProviderInput
+	ProfileFeature         *tpmodels.TypeInputProfileFeature
 	Override               OverrideStruct
 	GetEmailDeliveryConfig func(recipeImpl RecipeInterface, epRecipeImpl epmodels.RecipeInterface) emaildelivery.TypeInputWithService
 }
@@ -28,6 +28,7 @@ import (
 	"github.com/supertokens/supertokens-golang/recipe/thirdpartypasswordless/api"
 	"github.com/supertokens/supertokens-golang/recipe/thirdpartypasswordless/recipeimplementation"
 	"github.com/supertokens/supertokens-golang/recipe/thirdpartypasswordless/tplmodels"
+	"github.com/supertokens/supertokens-golang/recipe/usermetadata"
 	"github.com/supertokens/supertokens-golang/supertokens"
 )
 
@@ -112,6 +113,7 @@ func MakeRecipe(recipeId string, appInfo supertokens.NormalisedAppinfo, config t
 			SignInAndUpFeature: tpmodels.TypeInputSignInAndUp{
 				Providers: verifiedConfig.Providers,
 			},
+			ProfileFeature: verifiedConfig.ProfileFeature,
 			Override: &tpmodels.OverrideStruct{
 				Functions: func(_ tpmodels.RecipeInterface) tpmodels.RecipeInterface {
 					return recipeimplementation.MakeThirdPartyRecipeImplementation(r.RecipeImpl)
@@ -141,6 +143,9 @@ func recipeInit(config tplmodels.TypeInput) supertokens.Recipe {
 				return nil, err
 			}
 			singletonInstance = &recipe
+			if recipe.Config.ProfileFeature != nil && recipe.Config.ProfileFeature.StoreInUserMetadata {
+				singletonInstance.RecipeModule.DependsOn(usermetadata.RECIPE_ID)
+			}
 			return &singletonInstance.RecipeModule, nil
 		}
 		return nil, errors.New("ThirdPartyPasswordless recipe has already been initialised. Please check your code for bugs.")
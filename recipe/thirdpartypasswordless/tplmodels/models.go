@@ -42,6 +42,7 @@ type TypeInput struct {
 	FlowType                  string
 	GetCustomUserInputCode    func(tenantId string, userContext supertokens.UserContext) (string, error)
 	Providers                 []tpmodels.ProviderInput
+	ProfileFeature            *tpmodels.TypeInputProfileFeature
 	Override                  *OverrideStruct
 	EmailDelivery             *emaildelivery.TypeInput
 	SmsDelivery               *smsdelivery.TypeInput
@@ -54,6 +55,7 @@ type TypeNormalisedInput struct {
 	FlowType                  string
 	GetCustomUserInputCode    func(tenantId string, userContext supertokens.UserContext) (string, error)
 	Providers                 []tpmodels.ProviderInput
+	ProfileFeature            *tpmodels.TypeInputProfileFeature
 	Override                  OverrideStruct
 	GetEmailDeliveryConfig    func() emaildelivery.TypeInputWithService
 	GetSmsDeliveryConfig      func() smsdelivery.TypeInputWithService
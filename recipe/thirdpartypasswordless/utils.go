@@ -76,6 +76,7 @@ func validateAndNormaliseUserInput(recipeInstance *Recipe, appInfo supertokens.N
 func makeTypeNormalisedInput(recipeInstance *Recipe, inputConfig tplmodels.TypeInput) tplmodels.TypeNormalisedInput {
 	return tplmodels.TypeNormalisedInput{
 		Providers:                 inputConfig.Providers,
+		ProfileFeature:            inputConfig.ProfileFeature,
 		ContactMethodPhone:        inputConfig.ContactMethodPhone,
 		ContactMethodEmail:        inputConfig.ContactMethodEmail,
 		ContactMethodEmailOrPhone: inputConfig.ContactMethodEmailOrPhone,
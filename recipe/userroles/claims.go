@@ -71,3 +71,26 @@ func NewPermissionClaim() (*claims.TypeSessionClaim, claims.PrimitiveArrayClaimV
 	permissionClaim, primitiveArrayClaimValidators := claims.PrimitiveArrayClaim("st-perm", fetchValue, &defaultMaxAge)
 	return permissionClaim, primitiveArrayClaimValidators
 }
+
+// RequireRole returns a validator for sessions of users that have all the
+// given roles, to use with session.WithClaimValidators in
+// VerifySessionOptions. The roles in the session are refetched when they are
+// older than 5 minutes.
+func RequireRole(roles ...string) claims.SessionClaimValidator {
+	return userrolesclaims.UserRoleClaimValidators.IncludesAll(toInterfaceSlice(roles), nil, nil)
+}
+
+// RequirePermission returns a validator for sessions of users that have all
+// the given permissions through their roles, to use with
+// session.WithClaimValidators in VerifySessionOptions.
+func RequirePermission(permissions ...string) claims.SessionClaimValidator {
+	return userrolesclaims.PermissionClaimValidators.IncludesAll(toInterfaceSlice(permissions), nil, nil)
+}
+
+func toInterfaceSlice(values []string) []interface{} {
+	result := make([]interface{}, len(values))
+	for i, value := range values {
+		result[i] = value
+	}
+	return result
+}
@@ -3,6 +3,7 @@ package userroles
 import (
 	"net/http"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/supertokens/supertokens-golang/recipe/session"
@@ -422,3 +423,24 @@ func TestShouldValidatePermissionsAfterRefetching(t *testing.T) {
 	assert.Contains(t, reason["actualValue"], "a")
 	assert.Contains(t, reason["actualValue"], "b")
 }
+
+func TestRequireRoleAndRequirePermission(t *testing.T) {
+	now := float64(time.Now().UnixMilli())
+	payload := map[string]interface{}{
+		"st-role": map[string]interface{}{"v": []interface{}{"admin", "support"}, "t": now},
+		"st-perm": map[string]interface{}{"v": []interface{}{"billing:read"}, "t": now},
+	}
+	userContext := &map[string]interface{}{}
+
+	assert.True(t, RequireRole("admin").Validate(payload, userContext).IsValid)
+	assert.True(t, RequireRole("admin", "support").Validate(payload, userContext).IsValid)
+	assert.False(t, RequireRole("admin", "owner").Validate(payload, userContext).IsValid)
+	assert.True(t, RequirePermission("billing:read").Validate(payload, userContext).IsValid)
+	assert.False(t, RequirePermission("billing:write").Validate(payload, userContext).IsValid)
+
+	validators, err := session.WithClaimValidators(RequireRole("admin"))([]claims.SessionClaimValidator{{ID: "global"}}, nil, userContext)
+	assert.NoError(t, err)
+	assert.Len(t, validators, 2)
+	assert.Equal(t, "global", validators[0].ID)
+	assert.Equal(t, "st-role", validators[1].ID)
+}
@@ -69,6 +69,7 @@ func recipeInit(config *userrolesmodels.TypeInput) supertokens.Recipe {
 				return nil, err
 			}
 			singletonInstance = &recipe
+			singletonInstance.RecipeModule.DependsOn(session.RECIPE_ID)
 
 			supertokens.AddPostInitCallback(func() error {
 				sessionRecipe, err := session.GetRecipeInstanceOrThrowError()
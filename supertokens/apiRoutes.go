@@ -0,0 +1,76 @@
+/* Copyright (c) 2023, VRAI Labs and/or its affiliates. All rights reserved.
+ *
+ * This software is licensed under the Apache License, Version 2.0 (the
+ * "License") as published by the Apache Software Foundation.
+ *
+ * You may not use this file except in compliance with the License. You may
+ * obtain a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+ * License for the specific language governing permissions and limitations
+ * under the License.
+ */
+
+package supertokens
+
+import "strings"
+
+// APIRoute is an API served by the SuperTokens middleware, for frameworks
+// that need routes to be registered explicitly.
+type APIRoute struct {
+	RecipeID string
+	ID       string
+	Method   string
+	// Path is the path seen by the server, i.e. the API base path without
+	// the API gateway path, followed by the path of the API.
+	Path string
+	// PathWithoutAPIBasePath is the path of the API, e.g. "/signin". APIs can
+	// also be called with a tenant ID between the API base path and this path.
+	PathWithoutAPIBasePath string
+}
+
+// GetAPIRoutes returns the APIs served by the recipes in the RecipeList,
+// leaving out disabled ones. Requests to these routes should still be passed
+// to the handler returned by Middleware, which picks the API to call.
+func GetAPIRoutes() ([]APIRoute, error) {
+	instance, err := GetInstanceOrThrowError()
+	if err != nil {
+		return nil, err
+	}
+	return instance.getAPIRoutes()
+}
+
+func (s *superTokens) getAPIBasePathForRoutes() string {
+	basePath := strings.TrimPrefix(s.AppInfo.APIBasePath.GetAsStringDangerous(), s.AppInfo.APIGatewayPath.GetAsStringDangerous())
+	if basePath == "" {
+		return "/"
+	}
+	return basePath
+}
+
+func (s *superTokens) getAPIRoutes() ([]APIRoute, error) {
+	basePath := s.getAPIBasePathForRoutes()
+	routes := []APIRoute{}
+	for _, recipeModule := range s.RecipeModules {
+		apisHandled, err := recipeModule.GetAPIsHandled()
+		if err != nil {
+			return nil, err
+		}
+		for _, api := range apisHandled {
+			if api.Disabled {
+				continue
+			}
+			apiPath := api.PathWithoutAPIBasePath.GetAsStringDangerous()
+			routes = append(routes, APIRoute{
+				RecipeID:               recipeModule.GetRecipeID(),
+				ID:                     api.ID,
+				Method:                 strings.ToUpper(api.Method),
+				Path:                   strings.TrimSuffix(basePath, "/") + apiPath,
+				PathWithoutAPIBasePath: apiPath,
+			})
+		}
+	}
+	return routes, nil
+}
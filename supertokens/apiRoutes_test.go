@@ -0,0 +1,58 @@
+/* Copyright (c) 2023, VRAI Labs and/or its affiliates. All rights reserved.
+ *
+ * This software is licensed under the Apache License, Version 2.0 (the
+ * "License") as published by the Apache Software Foundation.
+ *
+ * You may not use this file except in compliance with the License. You may
+ * obtain a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+ * License for the specific language governing permissions and limitations
+ * under the License.
+ */
+
+package supertokens
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetAPIRoutesLeavesOutDisabledAPIsAndTheGatewayPath(t *testing.T) {
+	defer ResetForTest()
+	handler := func(tenantId string, req *http.Request, res http.ResponseWriter, userContext UserContext) error {
+		return nil
+	}
+	ResetForTest()
+	apiGatewayPath := "/gateway"
+	err := Init(TypeInput{
+		AppInfo: AppInfo{
+			AppName:        "SuperTokens",
+			APIDomain:      "api.supertokens.io",
+			WebsiteDomain:  "supertokens.io",
+			APIGatewayPath: &apiGatewayPath,
+		},
+		RecipeList: []Recipe{MakeCustomRecipe(CustomRecipeConfig{
+			RecipeID: "apikey",
+			APIs: []CustomRecipeAPI{
+				{Method: "post", Path: "/apikey/verify", ID: "verify", Handler: handler},
+				{Method: "get", Path: "/apikey/list", Disabled: true, Handler: handler},
+			},
+		})},
+	})
+	assert.NoError(t, err)
+
+	routes, err := GetAPIRoutes()
+	assert.NoError(t, err)
+	assert.Equal(t, []APIRoute{{
+		RecipeID:               "apikey",
+		ID:                     "verify",
+		Method:                 http.MethodPost,
+		Path:                   "/auth/apikey/verify",
+		PathWithoutAPIBasePath: "/apikey/verify",
+	}}, routes)
+}
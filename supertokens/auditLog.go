@@ -0,0 +1,164 @@
+/* Copyright (c) 2023, VRAI Labs and/or its affiliates. All rights reserved.
+ *
+ * This software is licensed under the Apache License, Version 2.0 (the
+ * "License") as published by the Apache Software Foundation.
+ *
+ * You may not use this file except in compliance with the License. You may
+ * obtain a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+ * License for the specific language governing permissions and limitations
+ * under the License.
+ */
+
+package supertokens
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+)
+
+type AuditAction string
+
+const (
+	AuditActionSignUp         AuditAction = "SIGN_UP"
+	AuditActionSignIn         AuditAction = "SIGN_IN"
+	AuditActionSignOut        AuditAction = "SIGN_OUT"
+	AuditActionSessionRefresh AuditAction = "SESSION_REFRESH"
+	AuditActionPasswordReset  AuditAction = "PASSWORD_RESET"
+	AuditActionUserDeleted    AuditAction = "USER_DELETED"
+	// AuditActionAPIRequest is written by the middleware for every request
+	// handled by a recipe
+	AuditActionAPIRequest AuditAction = "API_REQUEST"
+)
+
+type AuditResult string
+
+const (
+	AuditResultSuccess AuditResult = "SUCCESS"
+	AuditResultFailure AuditResult = "FAILURE"
+)
+
+// AuditEntry records who did what, when, from where, and with which result
+type AuditEntry struct {
+	Timestamp time.Time   `json:"timestamp"`
+	Action    AuditAction `json:"action"`
+	Result    AuditResult `json:"result"`
+	// Reason is the status returned on failures, e.g. WRONG_CREDENTIALS_ERROR
+	Reason    string                 `json:"reason,omitempty"`
+	UserID    string                 `json:"userId,omitempty"`
+	TenantId  string                 `json:"tenantId,omitempty"`
+	RecipeID  string                 `json:"recipeId,omitempty"`
+	IPAddress string                 `json:"ipAddress,omitempty"`
+	UserAgent string                 `json:"userAgent,omitempty"`
+	Data      map[string]interface{} `json:"data,omitempty"`
+}
+
+// AuditLogger is a sink for audit entries, e.g. a database table or a Kafka
+// topic. Errors returned by Log are logged in debug mode and do not fail the
+// request that is being audited.
+type AuditLogger interface {
+	Log(entry AuditEntry, userContext UserContext) error
+}
+
+// AuditLoggerFunc turns a function into an AuditLogger
+type AuditLoggerFunc func(entry AuditEntry, userContext UserContext) error
+
+func (f AuditLoggerFunc) Log(entry AuditEntry, userContext UserContext) error {
+	return f(entry, userContext)
+}
+
+type jsonAuditLogger struct {
+	lock sync.Mutex
+	w    io.Writer
+}
+
+// MakeJSONAuditLogger writes each entry as a line of JSON, e.g. to os.Stdout
+func MakeJSONAuditLogger(w io.Writer) AuditLogger {
+	return &jsonAuditLogger{w: w}
+}
+
+func (l *jsonAuditLogger) Log(entry AuditEntry, userContext UserContext) error {
+	line, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+	l.lock.Lock()
+	defer l.lock.Unlock()
+	_, err = l.w.Write(append(line, '\n'))
+	return err
+}
+
+// WriteAuditLog sends an entry to the configured AuditLogger. The timestamp,
+// IP address and user agent are filled in if empty, the latter two from the
+// request in the userContext.
+func WriteAuditLog(entry AuditEntry, userContext UserContext) {
+	if superTokensInstance == nil || superTokensInstance.AuditLogger == nil {
+		return
+	}
+	if entry.Timestamp.IsZero() {
+		entry.Timestamp = time.Now().UTC()
+	}
+	req := getRequestFromUserContext(userContext)
+	if req != nil {
+		if entry.IPAddress == "" {
+			entry.IPAddress = GetClientIP(req, userContext)
+		}
+		if entry.UserAgent == "" {
+			entry.UserAgent = req.UserAgent()
+		}
+	}
+	err := superTokensInstance.AuditLogger.Log(entry, userContext)
+	if err != nil {
+		LogDebugMessage(fmt.Sprintf("WriteAuditLog: writing %s entry failed: %s", entry.Action, err.Error()))
+	}
+}
+
+// apiRequestAudit collects the outcome of a request handled by the middleware
+type apiRequestAudit struct {
+	recipeID    string
+	apiID       string
+	tenantId    string
+	err         error
+	rateLimited bool
+}
+
+// writeAPIRequestAuditLog is deferred by the middleware as soon as it knows
+// which API handles a request, so that requests that are rate limited, fail
+// or panic are audited too.
+func writeAPIRequestAuditLog(audit *apiRequestAudit, dw DoneWriter, userContext UserContext) {
+	if r := recover(); r != nil {
+		audit.err = fmt.Errorf("panic: %v", r)
+		defer panic(r)
+	}
+	status := getStatusCode(dw)
+	entry := AuditEntry{
+		Action:   AuditActionAPIRequest,
+		Result:   AuditResultSuccess,
+		TenantId: audit.tenantId,
+		RecipeID: audit.recipeID,
+		Data: map[string]interface{}{
+			"apiId":  audit.apiID,
+			"status": status,
+		},
+	}
+	req := getRequestFromUserContext(userContext)
+	if req != nil {
+		entry.Data["method"] = req.Method
+		entry.Data["path"] = req.URL.Path
+	}
+	if audit.err != nil || audit.rateLimited || status >= 400 {
+		entry.Result = AuditResultFailure
+	}
+	if audit.rateLimited {
+		entry.Reason = "RATE_LIMITED"
+	} else if audit.err != nil {
+		entry.Reason = audit.err.Error()
+	}
+	WriteAuditLog(entry, userContext)
+}
@@ -0,0 +1,194 @@
+/* Copyright (c) 2023, VRAI Labs and/or its affiliates. All rights reserved.
+ *
+ * This software is licensed under the Apache License, Version 2.0 (the
+ * "License") as published by the Apache Software Foundation.
+ *
+ * You may not use this file except in compliance with the License. You may
+ * obtain a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+ * License for the specific language governing permissions and limitations
+ * under the License.
+ */
+
+package supertokens
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMiddlewareWritesAuditLogForHandledRequests(t *testing.T) {
+	defer ResetForTest()
+	ResetForTest()
+	postInitCallbacks = []func() error{}
+	entries := []AuditEntry{}
+	err := Init(TypeInput{
+		AppInfo: AppInfo{
+			AppName:       "SuperTokens",
+			APIDomain:     "api.supertokens.io",
+			WebsiteDomain: "supertokens.io",
+		},
+		RecipeList: []Recipe{MakeCustomRecipe(CustomRecipeConfig{
+			RecipeID: "apikey",
+			APIs: []CustomRecipeAPI{
+				{
+					Method: http.MethodPost,
+					Path:   "/apikey/verify",
+					Handler: func(tenantId string, req *http.Request, res http.ResponseWriter, userContext UserContext) error {
+						if req.Header.Get("x-api-key") != "secret" {
+							return errors.New("invalid API key")
+						}
+						WriteAuditLog(AuditEntry{
+							Action: "API_KEY_VERIFIED",
+							Result: AuditResultSuccess,
+							UserID: "user1",
+						}, userContext)
+						return Send200Response(res, map[string]interface{}{"status": "OK"})
+					},
+				},
+			},
+		})},
+		AuditLogger: AuditLoggerFunc(func(entry AuditEntry, userContext UserContext) error {
+			entries = append(entries, entry)
+			return nil
+		}),
+	})
+	assert.NoError(t, err)
+
+	handler := Middleware(nil)
+	req := httptest.NewRequest(http.MethodPost, "/auth/apikey/verify", nil)
+	req.Header.Set("x-api-key", "secret")
+	req.Header.Set("User-Agent", "test-agent")
+	req.RemoteAddr = "203.0.113.7:1234"
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+
+	assert.Len(t, entries, 2)
+	assert.Equal(t, AuditAction("API_KEY_VERIFIED"), entries[0].Action)
+	assert.Equal(t, "user1", entries[0].UserID)
+	assert.Equal(t, "203.0.113.7", entries[0].IPAddress)
+	assert.Equal(t, "test-agent", entries[0].UserAgent)
+	assert.False(t, entries[0].Timestamp.IsZero())
+
+	assert.Equal(t, AuditActionAPIRequest, entries[1].Action)
+	assert.Equal(t, AuditResultSuccess, entries[1].Result)
+	assert.Equal(t, "apikey", entries[1].RecipeID)
+	assert.Equal(t, "public", entries[1].TenantId)
+	assert.Equal(t, http.StatusOK, entries[1].Data["status"])
+	assert.Equal(t, "/auth/apikey/verify", entries[1].Data["path"])
+
+	entries = []AuditEntry{}
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, "/auth/apikey/verify", nil))
+	assert.Len(t, entries, 1)
+	assert.Equal(t, AuditResultFailure, entries[0].Result)
+	assert.Equal(t, "invalid API key", entries[0].Reason)
+	assert.Equal(t, http.StatusInternalServerError, entries[0].Data["status"])
+
+	// requests not handled by the SDK are not audited
+	entries = []AuditEntry{}
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/other", nil))
+	assert.Len(t, entries, 0)
+}
+
+func TestJSONAuditLogger(t *testing.T) {
+	var buf bytes.Buffer
+	logger := MakeJSONAuditLogger(&buf)
+	timestamp := time.Date(2023, 1, 2, 3, 4, 5, 0, time.UTC)
+	err := logger.Log(AuditEntry{
+		Timestamp: timestamp,
+		Action:    AuditActionSignIn,
+		Result:    AuditResultFailure,
+		Reason:    "WRONG_CREDENTIALS_ERROR",
+		IPAddress: "203.0.113.7",
+	}, &map[string]interface{}{})
+	assert.NoError(t, err)
+	err = logger.Log(AuditEntry{Timestamp: timestamp, Action: AuditActionSignOut, Result: AuditResultSuccess, UserID: "user1"}, &map[string]interface{}{})
+	assert.NoError(t, err)
+
+	lines := bytes.Split(bytes.TrimSpace(buf.Bytes()), []byte("\n"))
+	assert.Len(t, lines, 2)
+	var entry map[string]interface{}
+	assert.NoError(t, json.Unmarshal(lines[0], &entry))
+	assert.Equal(t, map[string]interface{}{
+		"timestamp": "2023-01-02T03:04:05Z",
+		"action":    "SIGN_IN",
+		"result":    "FAILURE",
+		"reason":    "WRONG_CREDENTIALS_ERROR",
+		"ipAddress": "203.0.113.7",
+	}, entry)
+}
+
+func TestWriteAuditLogWithoutLoggerDoesNothing(t *testing.T) {
+	defer ResetForTest()
+	ResetForTest()
+	WriteAuditLog(AuditEntry{Action: AuditActionSignIn}, &map[string]interface{}{})
+}
+
+func TestRateLimitedAndPanickingRequestsAreAudited(t *testing.T) {
+	defer ResetForTest()
+	ResetForTest()
+	postInitCallbacks = []func() error{}
+	entries := []AuditEntry{}
+	err := Init(TypeInput{
+		AppInfo: AppInfo{
+			AppName:       "SuperTokens",
+			APIDomain:     "api.supertokens.io",
+			WebsiteDomain: "supertokens.io",
+		},
+		RecipeList: []Recipe{MakeCustomRecipe(CustomRecipeConfig{
+			RecipeID: "apikey",
+			APIs: []CustomRecipeAPI{
+				{
+					Method: http.MethodPost,
+					Path:   "/apikey/panic",
+					Handler: func(tenantId string, req *http.Request, res http.ResponseWriter, userContext UserContext) error {
+						panic("handler failed")
+					},
+				},
+				{
+					Method: http.MethodPost,
+					Path:   "/apikey/verify",
+					Handler: func(tenantId string, req *http.Request, res http.ResponseWriter, userContext UserContext) error {
+						return Send200Response(res, map[string]interface{}{"status": "OK"})
+					},
+				},
+			},
+		})},
+		RateLimiter: &RateLimiterInput{
+			MaxRequests: 1,
+		},
+		AuditLogger: AuditLoggerFunc(func(entry AuditEntry, userContext UserContext) error {
+			entries = append(entries, entry)
+			return nil
+		}),
+	})
+	assert.NoError(t, err)
+	handler := Middleware(nil)
+
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, "/auth/apikey/verify", nil))
+	res := httptest.NewRecorder()
+	handler.ServeHTTP(res, httptest.NewRequest(http.MethodPost, "/auth/apikey/verify", nil))
+	assert.Equal(t, http.StatusTooManyRequests, res.Code)
+	assert.Len(t, entries, 2)
+	assert.Equal(t, AuditResultSuccess, entries[0].Result)
+	assert.Equal(t, AuditResultFailure, entries[1].Result)
+	assert.Equal(t, "RATE_LIMITED", entries[1].Reason)
+	assert.Equal(t, http.StatusTooManyRequests, entries[1].Data["status"])
+
+	entries = []AuditEntry{}
+	res = httptest.NewRecorder()
+	handler.ServeHTTP(res, httptest.NewRequest(http.MethodPost, "/auth/apikey/panic", nil))
+	assert.Equal(t, http.StatusInternalServerError, res.Code)
+	assert.Len(t, entries, 1)
+	assert.Equal(t, AuditResultFailure, entries[0].Result)
+	assert.Equal(t, "panic: handler failed", entries[0].Reason)
+}
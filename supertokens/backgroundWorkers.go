@@ -0,0 +1,294 @@
+/* Copyright (c) 2023, VRAI Labs and/or its affiliates. All rights reserved.
+ *
+ * This software is licensed under the Apache License, Version 2.0 (the
+ * "License") as published by the Apache Software Foundation.
+ *
+ * You may not use this file except in compliance with the License. You may
+ * obtain a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+ * License for the specific language governing permissions and limitations
+ * under the License.
+ */
+
+package supertokens
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// BackgroundWorker is a task that the SDK (or a recipe) runs in the
+// background between supertokens.Start and supertokens.Stop.
+type BackgroundWorker struct {
+	Name string
+	// If Interval is set, Run is called every Interval. Otherwise Run is
+	// called once and should only return once ctx is done. It is restarted
+	// if it returns (or panics) earlier.
+	Interval time.Duration
+	Run      func(ctx context.Context) error
+}
+
+type BackgroundWorkerHealth struct {
+	Name      string
+	Running   bool
+	LastRunAt *time.Time
+	// LastError is the error returned by (or the panic of) the last run
+	LastError error
+	Panics    int
+}
+
+type backgroundWorkerState struct {
+	worker BackgroundWorker
+	health BackgroundWorkerHealth
+	// cancel stops this worker only, it is nil while the worker is not running
+	cancel context.CancelFunc
+}
+
+const backgroundWorkerRestartDelay = time.Second
+const backgroundTasksWorkerName = "background-tasks"
+const backgroundTasksQueueSize = 100
+
+// detachedBackgroundTasks bounds the number of tasks run on their own
+// goroutine because the background tasks worker is not running or is busy
+var detachedBackgroundTasks = make(chan struct{}, backgroundTasksQueueSize)
+
+var (
+	backgroundWorkersLock   sync.Mutex
+	backgroundWorkers       = []*backgroundWorkerState{}
+	backgroundWorkersCtx    context.Context
+	backgroundWorkersCancel context.CancelFunc
+	backgroundWorkersWG     sync.WaitGroup
+	backgroundTasks         chan func(ctx context.Context)
+	backgroundTasksState    *backgroundWorkerState
+)
+
+// RegisterBackgroundWorker adds a worker that is run once supertokens.Start is
+// called (or right away, if it already has been).
+func RegisterBackgroundWorker(worker BackgroundWorker) error {
+	if worker.Name == "" || worker.Run == nil {
+		return errors.New("background workers need a Name and a Run function")
+	}
+	backgroundWorkersLock.Lock()
+	defer backgroundWorkersLock.Unlock()
+	for _, state := range backgroundWorkers {
+		if state.worker.Name == worker.Name {
+			return fmt.Errorf("a background worker named %s has already been registered", worker.Name)
+		}
+	}
+	state := &backgroundWorkerState{
+		worker: worker,
+		health: BackgroundWorkerHealth{Name: worker.Name},
+	}
+	backgroundWorkers = append(backgroundWorkers, state)
+	if backgroundWorkersCancel != nil {
+		startBackgroundWorker(backgroundWorkersCtx, state)
+	}
+	return nil
+}
+
+// Start runs the background workers of the SDK (e.g. core latency probes and
+// telemetry) until ctx is done or Stop is called. Features that need them
+// fall back to doing their work inline if Start is not called.
+func Start(ctx context.Context) error {
+	backgroundWorkersLock.Lock()
+	defer backgroundWorkersLock.Unlock()
+	if backgroundWorkersCancel != nil {
+		return errors.New("supertokens.Start has already been called")
+	}
+	backgroundWorkersCtx, backgroundWorkersCancel = context.WithCancel(ctx)
+	tasks := make(chan func(ctx context.Context), backgroundTasksQueueSize)
+	backgroundTasks = tasks
+	backgroundTasksState = &backgroundWorkerState{
+		worker: BackgroundWorker{
+			Name: backgroundTasksWorkerName,
+			Run: func(ctx context.Context) error {
+				for {
+					select {
+					case <-ctx.Done():
+						return nil
+					case task := <-tasks:
+						runBackgroundTask(ctx, task)
+					}
+				}
+			},
+		},
+		health: BackgroundWorkerHealth{Name: backgroundTasksWorkerName},
+	}
+	startBackgroundWorker(backgroundWorkersCtx, backgroundTasksState)
+	for _, state := range backgroundWorkers {
+		startBackgroundWorker(backgroundWorkersCtx, state)
+	}
+	return nil
+}
+
+// unregisterBackgroundWorker stops the worker with the given name (if it is
+// running) and removes it, so that a worker with that name can be registered
+// again.
+func unregisterBackgroundWorker(name string) {
+	backgroundWorkersLock.Lock()
+	defer backgroundWorkersLock.Unlock()
+	for idx, state := range backgroundWorkers {
+		if state.worker.Name == name {
+			if state.cancel != nil {
+				state.cancel()
+			}
+			backgroundWorkers = append(backgroundWorkers[:idx], backgroundWorkers[idx+1:]...)
+			return
+		}
+	}
+}
+
+// areBackgroundWorkersRunning returns true between Start and Stop
+func areBackgroundWorkersRunning() bool {
+	backgroundWorkersLock.Lock()
+	defer backgroundWorkersLock.Unlock()
+	return backgroundWorkersCtx != nil && backgroundWorkersCtx.Err() == nil
+}
+
+// Stop stops the background workers and waits for them to return
+func Stop() {
+	backgroundWorkersLock.Lock()
+	if backgroundWorkersCancel == nil {
+		backgroundWorkersLock.Unlock()
+		return
+	}
+	backgroundWorkersCancel()
+	backgroundWorkersCancel = nil
+	backgroundWorkersCtx = nil
+	backgroundTasks = nil
+	backgroundWorkersLock.Unlock()
+
+	backgroundWorkersWG.Wait()
+}
+
+// RunInBackground runs a one off task (e.g. sending telemetry) on the
+// background tasks worker. If the workers are not running, or the queue is
+// full, the task is run on its own goroutine with a background context. It
+// never blocks: if too many tasks are already running that way, the task is
+// dropped.
+func RunInBackground(task func(ctx context.Context)) {
+	backgroundWorkersLock.Lock()
+	tasks := backgroundTasks
+	if backgroundWorkersCtx != nil && backgroundWorkersCtx.Err() != nil {
+		// the context passed to Start is done
+		tasks = nil
+	}
+	backgroundWorkersLock.Unlock()
+	if tasks != nil {
+		select {
+		case tasks <- task:
+			return
+		default:
+			LogDebugMessage("RunInBackground: queue is full, running task on its own goroutine")
+		}
+	}
+	select {
+	case detachedBackgroundTasks <- struct{}{}:
+		go func() {
+			defer func() { <-detachedBackgroundTasks }()
+			runBackgroundTask(context.Background(), task)
+		}()
+	default:
+		LogDebugMessage("RunInBackground: too many tasks are running, dropping task")
+	}
+}
+
+func runBackgroundTask(ctx context.Context, task func(ctx context.Context)) {
+	defer func() {
+		if r := recover(); r != nil {
+			LogDebugMessage(fmt.Sprintf("RunInBackground: task panicked: %v", r))
+		}
+	}()
+	task(ctx)
+}
+
+// GetBackgroundWorkersHealth reports the state of the registered workers
+func GetBackgroundWorkersHealth() []BackgroundWorkerHealth {
+	backgroundWorkersLock.Lock()
+	defer backgroundWorkersLock.Unlock()
+	result := []BackgroundWorkerHealth{}
+	if backgroundTasksState != nil {
+		result = append(result, backgroundTasksState.health)
+	}
+	for _, state := range backgroundWorkers {
+		result = append(result, state.health)
+	}
+	return result
+}
+
+// startBackgroundWorker must be called with backgroundWorkersLock held
+func startBackgroundWorker(ctx context.Context, state *backgroundWorkerState) {
+	ctx, cancel := context.WithCancel(ctx)
+	state.cancel = cancel
+	state.health.Running = true
+	backgroundWorkersWG.Add(1)
+	go func() {
+		defer backgroundWorkersWG.Done()
+		defer func() {
+			backgroundWorkersLock.Lock()
+			state.health.Running = false
+			state.cancel = nil
+			backgroundWorkersLock.Unlock()
+			cancel()
+		}()
+
+		for {
+			runBackgroundWorkerOnce(ctx, state)
+
+			delay := state.worker.Interval
+			if delay <= 0 {
+				if ctx.Err() != nil {
+					return
+				}
+				delay = backgroundWorkerRestartDelay
+			}
+			timer := time.NewTimer(delay)
+			select {
+			case <-ctx.Done():
+				timer.Stop()
+				return
+			case <-timer.C:
+			}
+		}
+	}()
+}
+
+func runBackgroundWorkerOnce(ctx context.Context, state *backgroundWorkerState) {
+	var err error
+	panicked := false
+	func() {
+		defer func() {
+			if r := recover(); r != nil {
+				panicked = true
+				err = fmt.Errorf("background worker %s panicked: %v", state.worker.Name, r)
+			}
+		}()
+		err = state.worker.Run(ctx)
+	}()
+	if err != nil {
+		LogDebugMessage(err.Error())
+	}
+
+	now := time.Now()
+	backgroundWorkersLock.Lock()
+	defer backgroundWorkersLock.Unlock()
+	state.health.LastRunAt = &now
+	state.health.LastError = err
+	if panicked {
+		state.health.Panics++
+	}
+}
+
+func resetBackgroundWorkersForTest() {
+	Stop()
+	backgroundWorkersLock.Lock()
+	defer backgroundWorkersLock.Unlock()
+	backgroundWorkers = []*backgroundWorkerState{}
+	backgroundTasksState = nil
+}
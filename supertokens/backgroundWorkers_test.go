@@ -0,0 +1,190 @@
+/* Copyright (c) 2023, VRAI Labs and/or its affiliates. All rights reserved.
+ *
+ * This software is licensed under the Apache License, Version 2.0 (the
+ * "License") as published by the Apache Software Foundation.
+ *
+ * You may not use this file except in compliance with the License. You may
+ * obtain a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+ * License for the specific language governing permissions and limitations
+ * under the License.
+ */
+
+package supertokens
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBackgroundWorkersRunUntilStopped(t *testing.T) {
+	defer resetBackgroundWorkersForTest()
+	var runs int32
+	err := RegisterBackgroundWorker(BackgroundWorker{
+		Name:     "counter",
+		Interval: 5 * time.Millisecond,
+		Run: func(ctx context.Context) error {
+			atomic.AddInt32(&runs, 1)
+			return nil
+		},
+	})
+	assert.NoError(t, err)
+	assert.Error(t, RegisterBackgroundWorker(BackgroundWorker{Name: "counter", Run: func(ctx context.Context) error { return nil }}))
+
+	assert.Equal(t, int32(0), atomic.LoadInt32(&runs))
+	assert.NoError(t, Start(context.Background()))
+	assert.Error(t, Start(context.Background()))
+	assert.Eventually(t, func() bool {
+		return atomic.LoadInt32(&runs) >= 3
+	}, time.Second, time.Millisecond)
+
+	health := GetBackgroundWorkersHealth()
+	assert.Len(t, health, 2)
+	assert.Equal(t, backgroundTasksWorkerName, health[0].Name)
+	assert.True(t, health[0].Running)
+	assert.Equal(t, "counter", health[1].Name)
+	assert.True(t, health[1].Running)
+	assert.NotNil(t, health[1].LastRunAt)
+
+	Stop()
+	runsAfterStop := atomic.LoadInt32(&runs)
+	time.Sleep(20 * time.Millisecond)
+	assert.Equal(t, runsAfterStop, atomic.LoadInt32(&runs))
+	for _, health := range GetBackgroundWorkersHealth() {
+		assert.False(t, health.Running)
+	}
+}
+
+func TestThatUnregisteredBackgroundWorkerIsStopped(t *testing.T) {
+	defer resetBackgroundWorkersForTest()
+	var runs int32
+	worker := BackgroundWorker{
+		Name:     "counter",
+		Interval: 5 * time.Millisecond,
+		Run: func(ctx context.Context) error {
+			atomic.AddInt32(&runs, 1)
+			return nil
+		},
+	}
+	assert.NoError(t, RegisterBackgroundWorker(worker))
+	assert.NoError(t, Start(context.Background()))
+	assert.Eventually(t, func() bool {
+		return atomic.LoadInt32(&runs) >= 1
+	}, time.Second, time.Millisecond)
+
+	unregisterBackgroundWorker("counter")
+	assert.Len(t, GetBackgroundWorkersHealth(), 1)
+	time.Sleep(10 * time.Millisecond)
+	runsAfterUnregister := atomic.LoadInt32(&runs)
+	time.Sleep(20 * time.Millisecond)
+	assert.Equal(t, runsAfterUnregister, atomic.LoadInt32(&runs))
+
+	// the name can be used again
+	assert.NoError(t, RegisterBackgroundWorker(worker))
+}
+
+func TestThatPanickingBackgroundWorkerIsIsolated(t *testing.T) {
+	defer resetBackgroundWorkersForTest()
+	var healthyRuns int32
+	RegisterBackgroundWorker(BackgroundWorker{
+		Name:     "panics",
+		Interval: 5 * time.Millisecond,
+		Run: func(ctx context.Context) error {
+			panic("boom")
+		},
+	})
+	RegisterBackgroundWorker(BackgroundWorker{
+		Name:     "fails",
+		Interval: 5 * time.Millisecond,
+		Run: func(ctx context.Context) error {
+			atomic.AddInt32(&healthyRuns, 1)
+			return errors.New("core unreachable")
+		},
+	})
+
+	assert.NoError(t, Start(context.Background()))
+	assert.Eventually(t, func() bool {
+		health := GetBackgroundWorkersHealth()
+		return health[1].Panics >= 2 && atomic.LoadInt32(&healthyRuns) >= 2
+	}, time.Second, time.Millisecond)
+
+	health := GetBackgroundWorkersHealth()
+	assert.True(t, health[1].Running)
+	assert.EqualError(t, health[1].LastError, "background worker panics panicked: boom")
+	assert.EqualError(t, health[2].LastError, "core unreachable")
+	assert.Equal(t, 0, health[2].Panics)
+}
+
+func TestRunInBackground(t *testing.T) {
+	defer resetBackgroundWorkersForTest()
+
+	// tasks run on their own goroutine if the workers have not been started
+	release := make(chan bool)
+	ran := make(chan bool)
+	RunInBackground(func(ctx context.Context) {
+		<-release
+		ran <- true
+	})
+	close(release)
+	select {
+	case <-ran:
+	case <-time.After(time.Second):
+		t.Fatal("background task did not run")
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	assert.NoError(t, Start(ctx))
+	done := make(chan bool)
+	RunInBackground(func(ctx context.Context) {
+		panic("isolated")
+	})
+	RunInBackground(func(ctx context.Context) {
+		done <- true
+	})
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("background task did not run")
+	}
+
+	// cancelling the context passed to Start stops the workers
+	cancel()
+	Stop()
+}
+
+func TestThatRunInBackgroundNeverBlocks(t *testing.T) {
+	defer resetBackgroundWorkersForTest()
+	block := make(chan bool)
+
+	var started int32
+	done := make(chan bool)
+	go func() {
+		for i := 0; i < 2*backgroundTasksQueueSize; i++ {
+			RunInBackground(func(ctx context.Context) {
+				atomic.AddInt32(&started, 1)
+				<-block
+			})
+		}
+		done <- true
+	}()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("RunInBackground blocked")
+	}
+	assert.Eventually(t, func() bool {
+		return atomic.LoadInt32(&started) == backgroundTasksQueueSize
+	}, time.Second, time.Millisecond)
+	close(block)
+	assert.Eventually(t, func() bool {
+		return len(detachedBackgroundTasks) == 0
+	}, time.Second, time.Millisecond)
+}
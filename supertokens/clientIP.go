@@ -0,0 +1,118 @@
+/* Copyright (c) 2023, VRAI Labs and/or its affiliates. All rights reserved.
+ *
+ * This software is licensed under the Apache License, Version 2.0 (the
+ * "License") as published by the Apache Software Foundation.
+ *
+ * You may not use this file except in compliance with the License. You may
+ * obtain a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+ * License for the specific language governing permissions and limitations
+ * under the License.
+ */
+
+package supertokens
+
+import (
+	"errors"
+	"net"
+	"net/http"
+	"strings"
+)
+
+type normalisedIPExtractor struct {
+	trustedProxies []*net.IPNet
+	getClientIP    func(req *http.Request, userContext UserContext) string
+}
+
+func normaliseIPExtractorInput(trustedProxies []string, getClientIP func(req *http.Request, userContext UserContext) string) (normalisedIPExtractor, error) {
+	result := normalisedIPExtractor{
+		getClientIP: getClientIP,
+	}
+	for _, proxy := range trustedProxies {
+		if !strings.Contains(proxy, "/") {
+			ip := net.ParseIP(proxy)
+			if ip == nil {
+				return normalisedIPExtractor{}, errors.New("invalid IP address in TrustedProxies: " + proxy)
+			}
+			if ip.To4() != nil {
+				proxy += "/32"
+			} else {
+				proxy += "/128"
+			}
+		}
+		_, ipNet, err := net.ParseCIDR(proxy)
+		if err != nil {
+			return normalisedIPExtractor{}, errors.New("invalid CIDR in TrustedProxies: " + proxy)
+		}
+		result.trustedProxies = append(result.trustedProxies, ipNet)
+	}
+	return result, nil
+}
+
+func (e normalisedIPExtractor) isTrustedProxy(ipStr string) bool {
+	ip := net.ParseIP(ipStr)
+	if ip == nil {
+		return false
+	}
+	for _, ipNet := range e.trustedProxies {
+		if ipNet.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+// extract returns the IP of the client that made the request. Forwarding
+// headers are only honoured if the request came through a trusted proxy,
+// since anyone can set them otherwise.
+func (e normalisedIPExtractor) extract(req *http.Request, userContext UserContext) string {
+	if e.getClientIP != nil {
+		return e.getClientIP(req, userContext)
+	}
+
+	remoteIP, _, err := net.SplitHostPort(req.RemoteAddr)
+	if err != nil {
+		remoteIP = req.RemoteAddr
+	}
+	if !e.isTrustedProxy(remoteIP) {
+		return remoteIP
+	}
+
+	forwardedFor := req.Header.Values("X-Forwarded-For")
+	if len(forwardedFor) > 0 {
+		hops := strings.Split(strings.Join(forwardedFor, ","), ",")
+		// we walk from the right since that part of the header was added by
+		// our own proxies. The first untrusted hop is the client.
+		for i := len(hops) - 1; i >= 0; i-- {
+			hop := strings.TrimSpace(hops[i])
+			if hop == "" {
+				continue
+			}
+			if !e.isTrustedProxy(hop) || i == 0 {
+				return hop
+			}
+		}
+	}
+
+	realIP := strings.TrimSpace(req.Header.Get("X-Real-IP"))
+	if realIP != "" {
+		return realIP
+	}
+	return remoteIP
+}
+
+// GetClientIP returns the IP address of the client making the request,
+// taking TrustedProxies and GetClientIP from the init config into account.
+func GetClientIP(req *http.Request, userContext ...UserContext) string {
+	if len(userContext) == 0 {
+		userContext = append(userContext, MakeDefaultUserContextFromAPI(req))
+	}
+	instance, err := GetInstanceOrThrowError()
+	if err != nil {
+		return normalisedIPExtractor{}.extract(req, userContext[0])
+	}
+	return instance.IPExtractor.extract(req, userContext[0])
+}
@@ -0,0 +1,75 @@
+/* Copyright (c) 2023, VRAI Labs and/or its affiliates. All rights reserved.
+ *
+ * This software is licensed under the Apache License, Version 2.0 (the
+ * "License") as published by the Apache Software Foundation.
+ *
+ * You may not use this file except in compliance with the License. You may
+ * obtain a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+ * License for the specific language governing permissions and limitations
+ * under the License.
+ */
+
+package supertokens
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClientIPExtraction(t *testing.T) {
+	extractor, err := normaliseIPExtractorInput([]string{"10.0.0.0/8", "192.168.1.1"}, nil)
+	assert.NoError(t, err)
+
+	// forwarding headers from untrusted peers are ignored
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.RemoteAddr = "1.2.3.4:5678"
+	req.Header.Set("X-Forwarded-For", "5.6.7.8")
+	assert.Equal(t, "1.2.3.4", extractor.extract(req, nil))
+
+	// the rightmost untrusted hop is the client, spoofed entries to its left are ignored
+	req = httptest.NewRequest(http.MethodGet, "/", nil)
+	req.RemoteAddr = "10.0.0.2:5678"
+	req.Header.Set("X-Forwarded-For", "9.9.9.9, 5.6.7.8, 192.168.1.1")
+	assert.Equal(t, "5.6.7.8", extractor.extract(req, nil))
+
+	// if every hop is trusted, the leftmost one is used
+	req = httptest.NewRequest(http.MethodGet, "/", nil)
+	req.RemoteAddr = "10.0.0.2:5678"
+	req.Header.Set("X-Forwarded-For", "10.1.1.1, 10.2.2.2")
+	assert.Equal(t, "10.1.1.1", extractor.extract(req, nil))
+
+	req = httptest.NewRequest(http.MethodGet, "/", nil)
+	req.RemoteAddr = "192.168.1.1:5678"
+	req.Header.Set("X-Real-IP", "5.6.7.8")
+	assert.Equal(t, "5.6.7.8", extractor.extract(req, nil))
+
+	req = httptest.NewRequest(http.MethodGet, "/", nil)
+	req.RemoteAddr = "192.168.1.1:5678"
+	assert.Equal(t, "192.168.1.1", extractor.extract(req, nil))
+}
+
+func TestClientIPExtractionWithInvalidTrustedProxies(t *testing.T) {
+	_, err := normaliseIPExtractorInput([]string{"not-an-ip"}, nil)
+	assert.Error(t, err)
+
+	_, err = normaliseIPExtractorInput([]string{"10.0.0.0/64"}, nil)
+	assert.Error(t, err)
+}
+
+func TestClientIPExtractionWithCustomFunction(t *testing.T) {
+	extractor, err := normaliseIPExtractorInput(nil, func(req *http.Request, userContext UserContext) string {
+		return req.Header.Get("CF-Connecting-IP")
+	})
+	assert.NoError(t, err)
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("CF-Connecting-IP", "5.6.7.8")
+	assert.Equal(t, "5.6.7.8", extractor.extract(req, nil))
+}
@@ -0,0 +1,147 @@
+/* Copyright (c) 2023, VRAI Labs and/or its affiliates. All rights reserved.
+ *
+ * This software is licensed under the Apache License, Version 2.0 (the
+ * "License") as published by the Apache Software Foundation.
+ *
+ * You may not use this file except in compliance with the License. You may
+ * obtain a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+ * License for the specific language governing permissions and limitations
+ * under the License.
+ */
+
+package supertokens
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+)
+
+// CoreFeature is a capability of the SuperTokens core that depends on its
+// version or on the features enabled by its license
+type CoreFeature string
+
+const (
+	CoreFeatureUserRoles     CoreFeature = "userroles"
+	CoreFeatureUserIdMapping CoreFeature = "useridmapping"
+	CoreFeatureUserSearch    CoreFeature = "usersearch"
+	CoreFeatureMultitenancy  CoreFeature = "multitenancy"
+	CoreFeatureMFA           CoreFeature = "mfa"
+)
+
+type coreFeatureRequirement struct {
+	// minCDIVersion is the first core driver interface version with the
+	// feature
+	minCDIVersion string
+	// licensedFeature is the name of the feature in the core's
+	// /ee/featureflag response, for features that need a license
+	licensedFeature string
+}
+
+var coreFeatureRequirements = map[CoreFeature]coreFeatureRequirement{
+	CoreFeatureUserRoles:     {minCDIVersion: "2.14"},
+	CoreFeatureUserIdMapping: {minCDIVersion: "2.15"},
+	CoreFeatureUserSearch:    {minCDIVersion: "2.20"},
+	CoreFeatureMultitenancy:  {minCDIVersion: "3.0", licensedFeature: "multi_tenancy"},
+	CoreFeatureMFA:           {minCDIVersion: "4.0", licensedFeature: "mfa"},
+}
+
+var (
+	coreLicensedFeatures     map[string]bool
+	coreLicensedFeaturesLock sync.Mutex
+)
+
+// CoreFeatureNotSupportedError is returned by functions that need a feature
+// the core lacks. APIs that fail with it respond with a 501.
+type CoreFeatureNotSupportedError struct {
+	Feature CoreFeature
+}
+
+func (err CoreFeatureNotSupportedError) Error() string {
+	requirement := coreFeatureRequirements[err.Feature]
+	if requirement.licensedFeature != "" {
+		return fmt.Sprintf("the SuperTokens core does not support %s. Please upgrade it and make sure the feature is enabled in its license", err.Feature)
+	}
+	return fmt.Sprintf("the SuperTokens core does not support %s. Please upgrade it", err.Feature)
+}
+
+// CoreSupports tells whether the core has a feature. Since supertokens.Init
+// does not contact the core, the core's API version and licensed features are
+// fetched the first time they are needed, and cached.
+func CoreSupports(feature CoreFeature) (bool, error) {
+	requirement, ok := coreFeatureRequirements[feature]
+	if !ok {
+		return false, fmt.Errorf("unknown core feature: %s", feature)
+	}
+	querier, err := GetNewQuerierInstanceOrThrowError("")
+	if err != nil {
+		return false, err
+	}
+	cdiVersion, err := querier.GetQuerierAPIVersion()
+	if err != nil {
+		return false, err
+	}
+	if MaxVersion(cdiVersion, requirement.minCDIVersion) != cdiVersion {
+		return false, nil
+	}
+	if requirement.licensedFeature == "" {
+		return true, nil
+	}
+	licensedFeatures, err := getCoreLicensedFeatures(querier)
+	if err != nil {
+		return false, err
+	}
+	return licensedFeatures[requirement.licensedFeature], nil
+}
+
+// RequireCoreFeature returns a CoreFeatureNotSupportedError if the core
+// lacks the feature
+func RequireCoreFeature(feature CoreFeature) error {
+	supported, err := CoreSupports(feature)
+	if err != nil {
+		return err
+	}
+	if !supported {
+		return CoreFeatureNotSupportedError{Feature: feature}
+	}
+	return nil
+}
+
+func getCoreLicensedFeatures(querier *Querier) (map[string]bool, error) {
+	coreLicensedFeaturesLock.Lock()
+	defer coreLicensedFeaturesLock.Unlock()
+	if coreLicensedFeatures != nil {
+		return coreLicensedFeatures, nil
+	}
+	response, err := querier.SendGetRequest("/ee/featureflag", nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	features, ok := response["features"].([]interface{})
+	if !ok {
+		return nil, errors.New("unexpected response from the core for /ee/featureflag")
+	}
+	result := map[string]bool{}
+	for _, feature := range features {
+		if name, ok := feature.(string); ok {
+			result[name] = true
+		}
+	}
+	coreLicensedFeatures = result
+	return result, nil
+}
+
+func resetCoreFeaturesForTest() {
+	coreLicensedFeaturesLock.Lock()
+	defer coreLicensedFeaturesLock.Unlock()
+	coreLicensedFeatures = nil
+}
+
+func sendCoreFeatureNotSupportedResponse(err CoreFeatureNotSupportedError, res http.ResponseWriter) error {
+	return SendNon200ResponseWithMessage(res, err.Error(), http.StatusNotImplemented)
+}
@@ -0,0 +1,114 @@
+/* Copyright (c) 2023, VRAI Labs and/or its affiliates. All rights reserved.
+ *
+ * This software is licensed under the Apache License, Version 2.0 (the
+ * "License") as published by the Apache Software Foundation.
+ *
+ * You may not use this file except in compliance with the License. You may
+ * obtain a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+ * License for the specific language governing permissions and limitations
+ * under the License.
+ */
+
+package supertokens
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func initQuerierWithFeatureFlags(t *testing.T, features []string, featureFlagRequests *int32) func() {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/apiversion", func(rw http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(rw).Encode(map[string]interface{}{"versions": cdiSupported})
+	})
+	mux.HandleFunc("/ee/featureflag", func(rw http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(featureFlagRequests, 1)
+		json.NewEncoder(rw).Encode(map[string]interface{}{"status": "OK", "features": features})
+	})
+	server := httptest.NewServer(mux)
+
+	domain, err := NewNormalisedURLDomain(server.URL)
+	assert.NoError(t, err)
+	basePath, err := NewNormalisedURLPath(server.URL)
+	assert.NoError(t, err)
+	ResetQuerierForTest()
+	previousVersion := querierAPIVersion
+	SetQuerierApiVersionForTests("")
+	initQuerier([]QuerierHost{{Domain: domain, BasePath: basePath}}, "", nil, nil, 0, nil, nil)
+	return func() {
+		server.Close()
+		ResetQuerierForTest()
+		SetQuerierApiVersionForTests(previousVersion)
+	}
+}
+
+func TestCoreSupportsChecksVersionAndLicense(t *testing.T) {
+	featureFlagRequests := int32(0)
+	cleanup := initQuerierWithFeatureFlags(t, []string{"multi_tenancy"}, &featureFlagRequests)
+	defer cleanup()
+
+	supported, err := CoreSupports(CoreFeatureUserRoles)
+	assert.NoError(t, err)
+	assert.True(t, supported)
+	assert.Equal(t, int32(0), atomic.LoadInt32(&featureFlagRequests))
+
+	supported, err = CoreSupports(CoreFeatureMultitenancy)
+	assert.NoError(t, err)
+	assert.True(t, supported)
+
+	// the core's CDI version is too old for MFA
+	supported, err = CoreSupports(CoreFeatureMFA)
+	assert.NoError(t, err)
+	assert.False(t, supported)
+
+	_, err = CoreSupports("unknown")
+	assert.Error(t, err)
+
+	// the licensed features are fetched once
+	supported, err = CoreSupports(CoreFeatureMultitenancy)
+	assert.NoError(t, err)
+	assert.True(t, supported)
+	assert.Equal(t, int32(1), atomic.LoadInt32(&featureFlagRequests))
+}
+
+func TestRequireCoreFeatureFailsWithoutLicense(t *testing.T) {
+	featureFlagRequests := int32(0)
+	cleanup := initQuerierWithFeatureFlags(t, []string{}, &featureFlagRequests)
+	defer cleanup()
+
+	err := RequireCoreFeature(CoreFeatureMultitenancy)
+	assert.Equal(t, CoreFeatureNotSupportedError{Feature: CoreFeatureMultitenancy}, err)
+	assert.Contains(t, err.Error(), "license")
+	assert.NoError(t, RequireCoreFeature(CoreFeatureUserIdMapping))
+}
+
+func TestUnsupportedCoreFeaturesAreSentAs501(t *testing.T) {
+	defer ResetForTest()
+	err := initWithTestRecipes(MakeCustomRecipe(CustomRecipeConfig{
+		RecipeID: "custom",
+		APIs: []CustomRecipeAPI{
+			{
+				Method: http.MethodGet,
+				Path:   "/mfa",
+				Handler: func(tenantId string, req *http.Request, res http.ResponseWriter, userContext UserContext) error {
+					return CoreFeatureNotSupportedError{Feature: CoreFeatureMFA}
+				},
+			},
+		},
+	}))
+	assert.NoError(t, err)
+
+	res := httptest.NewRecorder()
+	Middleware(nil).ServeHTTP(res, httptest.NewRequest(http.MethodGet, "/auth/mfa", nil))
+	assert.Equal(t, http.StatusNotImplemented, res.Code)
+	assert.Contains(t, res.Body.String(), "does not support mfa")
+}
@@ -0,0 +1,111 @@
+/* Copyright (c) 2023, VRAI Labs and/or its affiliates. All rights reserved.
+ *
+ * This software is licensed under the Apache License, Version 2.0 (the
+ * "License") as published by the Apache Software Foundation.
+ *
+ * You may not use this file except in compliance with the License. You may
+ * obtain a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+ * License for the specific language governing permissions and limitations
+ * under the License.
+ */
+
+package supertokens
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// CustomRecipeConfig describes a recipe that is written outside of this SDK
+// (for example, API key based auth for internal services). Its APIs are
+// served by the SuperTokens middleware under the API base path, and errors
+// returned by them go through the same error handling as built-in recipes.
+type CustomRecipeConfig struct {
+	// RecipeID must be unique across the RecipeList. Frontends can target the
+	// recipe by sending it in the rid header.
+	RecipeID string
+	APIs     []CustomRecipeAPI
+	// CORSHeaders are added to the headers returned by supertokens.GetAllCORSHeaders
+	CORSHeaders []string
+	// HandleError is called for errors returned by the recipe's handlers (and
+	// by other recipes' handlers). It should return true if it wrote a response
+	// for the error. If nil, errors are passed on to OnSuperTokensAPIError.
+	HandleError func(err error, req *http.Request, res http.ResponseWriter, userContext UserContext) (bool, error)
+	// Dependencies are the IDs of recipes that must also be in the RecipeList
+	Dependencies []string
+}
+
+type CustomRecipeAPI struct {
+	Method string
+	// Path is relative to the API base path, e.g. "/apikey/verify"
+	Path string
+	// ID must be unique within the recipe. Defaults to the method followed by
+	// the path, e.g. "POST /apikey/verify".
+	ID       string
+	Disabled bool
+	Handler  func(tenantId string, req *http.Request, res http.ResponseWriter, userContext UserContext) error
+}
+
+// MakeCustomRecipe returns a recipe that can be added to the RecipeList passed
+// to supertokens.Init.
+func MakeCustomRecipe(config CustomRecipeConfig) Recipe {
+	return func(appInfo NormalisedAppinfo, onSuperTokensAPIError func(err error, req *http.Request, res http.ResponseWriter)) (*RecipeModule, error) {
+		if config.RecipeID == "" {
+			return nil, errors.New("please provide a RecipeID for the custom recipe")
+		}
+
+		apisHandled := []APIHandled{}
+		handlers := map[string]func(tenantId string, req *http.Request, res http.ResponseWriter, userContext UserContext) error{}
+		for _, api := range config.APIs {
+			if api.Handler == nil {
+				return nil, fmt.Errorf("please provide a Handler for %s %s in the %s recipe", api.Method, api.Path, config.RecipeID)
+			}
+			path, err := NewNormalisedURLPath(api.Path)
+			if err != nil {
+				return nil, err
+			}
+			id := api.ID
+			if id == "" {
+				id = strings.ToUpper(api.Method) + " " + api.Path
+			}
+			if _, ok := handlers[id]; ok {
+				return nil, fmt.Errorf("the API ID %s is used more than once in the %s recipe", id, config.RecipeID)
+			}
+			handlers[id] = api.Handler
+			apisHandled = append(apisHandled, APIHandled{
+				Method:                 api.Method,
+				PathWithoutAPIBasePath: path,
+				ID:                     id,
+				Disabled:               api.Disabled,
+			})
+		}
+
+		handleError := config.HandleError
+		if handleError == nil {
+			handleError = func(err error, req *http.Request, res http.ResponseWriter, userContext UserContext) (bool, error) {
+				return false, nil
+			}
+		}
+
+		recipeModule := MakeRecipeModule(config.RecipeID, appInfo, func(id string, tenantId string, req *http.Request, res http.ResponseWriter, theirHandler http.HandlerFunc, path NormalisedURLPath, method string, userContext UserContext) error {
+			handler, ok := handlers[id]
+			if !ok {
+				return errors.New("should never come here")
+			}
+			return handler(tenantId, req, res, userContext)
+		}, func() []string {
+			return config.CORSHeaders
+		}, func() ([]APIHandled, error) {
+			return apisHandled, nil
+		}, nil, handleError, onSuperTokensAPIError)
+		recipeModule.DependsOn(config.Dependencies...)
+
+		return &recipeModule, nil
+	}
+}
@@ -0,0 +1,123 @@
+/* Copyright (c) 2023, VRAI Labs and/or its affiliates. All rights reserved.
+ *
+ * This software is licensed under the Apache License, Version 2.0 (the
+ * "License") as published by the Apache Software Foundation.
+ *
+ * You may not use this file except in compliance with the License. You may
+ * obtain a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+ * License for the specific language governing permissions and limitations
+ * under the License.
+ */
+
+package supertokens
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestThatCustomRecipeAPIsAreServedByMiddleware(t *testing.T) {
+	defer ResetForTest()
+	errInvalidKey := errors.New("invalid API key")
+	err := initWithTestRecipes(MakeCustomRecipe(CustomRecipeConfig{
+		RecipeID: "apikey",
+		APIs: []CustomRecipeAPI{
+			{
+				Method: http.MethodPost,
+				Path:   "/apikey/verify",
+				Handler: func(tenantId string, req *http.Request, res http.ResponseWriter, userContext UserContext) error {
+					if req.Header.Get("x-api-key") != "secret" {
+						return errInvalidKey
+					}
+					return Send200Response(res, map[string]interface{}{"status": "OK", "tenantId": tenantId})
+				},
+			},
+		},
+		CORSHeaders: []string{"x-api-key"},
+		HandleError: func(err error, req *http.Request, res http.ResponseWriter, userContext UserContext) (bool, error) {
+			if err == errInvalidKey {
+				return true, SendNon200ResponseWithMessage(res, err.Error(), 401)
+			}
+			return false, nil
+		},
+	}))
+	assert.NoError(t, err)
+
+	handler := Middleware(nil)
+
+	req := httptest.NewRequest(http.MethodPost, "/auth/apikey/verify", nil)
+	req.Header.Set("x-api-key", "secret")
+	res := httptest.NewRecorder()
+	handler.ServeHTTP(res, req)
+	assert.Equal(t, http.StatusOK, res.Code)
+	assert.JSONEq(t, `{"status": "OK", "tenantId": "public"}`, res.Body.String())
+
+	res = httptest.NewRecorder()
+	handler.ServeHTTP(res, httptest.NewRequest(http.MethodPost, "/auth/apikey/verify", nil))
+	assert.Equal(t, http.StatusUnauthorized, res.Code)
+
+	assert.Contains(t, GetAllCORSHeaders(), "x-api-key")
+}
+
+func TestInvalidCustomRecipeConfig(t *testing.T) {
+	defer ResetForTest()
+	err := initWithTestRecipes(MakeCustomRecipe(CustomRecipeConfig{}))
+	assert.Error(t, err)
+
+	handler := func(tenantId string, req *http.Request, res http.ResponseWriter, userContext UserContext) error {
+		return nil
+	}
+	err = initWithTestRecipes(MakeCustomRecipe(CustomRecipeConfig{
+		RecipeID: "apikey",
+		APIs: []CustomRecipeAPI{
+			{Method: http.MethodGet, Path: "/apikey", Handler: handler},
+			{Method: http.MethodGet, Path: "/apikey", Handler: handler},
+		},
+	}))
+	assert.EqualError(t, err, "the API ID GET /apikey is used more than once in the apikey recipe")
+
+	err = initWithTestRecipes(MakeCustomRecipe(CustomRecipeConfig{
+		RecipeID: "apikey",
+		APIs: []CustomRecipeAPI{
+			{Method: http.MethodGet, Path: "/apikey", ID: "apikey", Handler: handler},
+			{Method: http.MethodPost, Path: "/apikey", ID: "apikey", Handler: handler},
+		},
+	}))
+	assert.EqualError(t, err, "the API ID apikey is used more than once in the apikey recipe")
+}
+
+func TestCustomRecipeCanServeSeveralMethodsOnAPath(t *testing.T) {
+	defer ResetForTest()
+	makeHandler := func(method string) func(tenantId string, req *http.Request, res http.ResponseWriter, userContext UserContext) error {
+		return func(tenantId string, req *http.Request, res http.ResponseWriter, userContext UserContext) error {
+			return Send200Response(res, map[string]interface{}{"method": method})
+		}
+	}
+	err := initWithTestRecipes(MakeCustomRecipe(CustomRecipeConfig{
+		RecipeID: "apikey",
+		APIs: []CustomRecipeAPI{
+			{Method: http.MethodGet, Path: "/apikey", Handler: makeHandler("get")},
+			{Method: http.MethodPost, Path: "/apikey", Handler: makeHandler("post")},
+		},
+	}))
+	assert.NoError(t, err)
+
+	handler := Middleware(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		rw.WriteHeader(http.StatusNotFound)
+	}))
+	for _, method := range []string{http.MethodGet, http.MethodPost} {
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest(method, "/auth/apikey", nil))
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Contains(t, rec.Body.String(), strings.ToLower(method))
+	}
+}
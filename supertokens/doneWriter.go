@@ -61,7 +61,8 @@ func MakeDoneWriter(w http.ResponseWriter) DoneWriter {
 
 type basicWriter struct {
 	http.ResponseWriter
-	done bool
+	done   bool
+	status int
 }
 
 func (w *basicWriter) Write(b []byte) (int, error) {
@@ -73,6 +74,29 @@ func (w *basicWriter) IsDone() bool {
 	return w.done
 }
 
+func (w *basicWriter) WriteHeader(statusCode int) {
+	if w.status == 0 {
+		w.status = statusCode
+	}
+	w.ResponseWriter.WriteHeader(statusCode)
+}
+
+func (w *basicWriter) statusCode() int {
+	if w.status == 0 {
+		return http.StatusOK
+	}
+	return w.status
+}
+
+// getStatusCode returns the status of the response written to w, or 0 if w
+// does not track it
+func getStatusCode(w http.ResponseWriter) int {
+	if sw, ok := w.(interface{ statusCode() int }); ok {
+		return sw.statusCode()
+	}
+	return 0
+}
+
 /////////////////////////////////////////
 
 // fancyWriter is a writer that additionally satisfies http.CloseNotifier,
@@ -0,0 +1,115 @@
+/* Copyright (c) 2023, VRAI Labs and/or its affiliates. All rights reserved.
+ *
+ * This software is licensed under the Apache License, Version 2.0 (the
+ * "License") as published by the Apache Software Foundation.
+ *
+ * You may not use this file except in compliance with the License. You may
+ * obtain a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+ * License for the specific language governing permissions and limitations
+ * under the License.
+ */
+
+package supertokens
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"net/url"
+
+	"golang.org/x/net/proxy"
+)
+
+// EgressProxyConfig routes requests made by the SDK to the core and to the
+// telemetry endpoint through a proxy.
+type EgressProxyConfig struct {
+	// URL of the proxy. http:// and https:// proxies are used with HTTP
+	// CONNECT, and socks5:// proxies with SOCKS5. Credentials can be part of
+	// the URL or set below.
+	URL      string
+	Username string
+	Password string
+}
+
+type egressProxy struct {
+	// proxyURL is set for HTTP proxies
+	proxyURL *url.URL
+	// dialContext is set for SOCKS5 proxies
+	dialContext func(ctx context.Context, network string, addr string) (net.Conn, error)
+}
+
+// egressHTTPClient is used for requests that are not to the core
+var egressHTTPClient = &http.Client{}
+
+func normaliseEgressProxyInput(config *EgressProxyConfig) (*egressProxy, error) {
+	if config == nil {
+		return nil, nil
+	}
+	proxyURL, err := url.Parse(config.URL)
+	if err != nil {
+		return nil, errors.New("EgressProxy.URL is not a valid URL: " + err.Error())
+	}
+	if proxyURL.Host == "" {
+		return nil, errors.New("EgressProxy.URL must contain the host of the proxy")
+	}
+	if config.Username != "" {
+		proxyURL.User = url.UserPassword(config.Username, config.Password)
+	}
+
+	switch proxyURL.Scheme {
+	case "http", "https":
+		return &egressProxy{proxyURL: proxyURL}, nil
+	case "socks5", "socks5h":
+		var auth *proxy.Auth
+		if proxyURL.User != nil {
+			password, _ := proxyURL.User.Password()
+			auth = &proxy.Auth{
+				User:     proxyURL.User.Username(),
+				Password: password,
+			}
+		}
+		dialer, err := proxy.SOCKS5("tcp", proxyURL.Host, auth, proxy.Direct)
+		if err != nil {
+			return nil, err
+		}
+		contextDialer, ok := dialer.(proxy.ContextDialer)
+		if !ok {
+			return nil, errors.New("should never come here")
+		}
+		return &egressProxy{dialContext: contextDialer.DialContext}, nil
+	}
+	return nil, errors.New("EgressProxy.URL must use one of the http, https or socks5 schemes")
+}
+
+func (p *egressProxy) apply(transport *http.Transport) {
+	if p.proxyURL != nil {
+		transport.Proxy = http.ProxyURL(p.proxyURL)
+	}
+	if p.dialContext != nil {
+		transport.Proxy = nil
+		transport.DialContext = p.dialContext
+	}
+}
+
+func makeEgressHTTPClient(egressProxy *egressProxy) *http.Client {
+	if egressProxy == nil {
+		return &http.Client{}
+	}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	egressProxy.apply(transport)
+	return &http.Client{
+		Transport: transport,
+	}
+}
+
+// GetEgressHTTPClient returns the client that recipes should use for requests
+// made on behalf of the SDK (e.g. telemetry), so that they go through the
+// configured EgressProxy.
+func GetEgressHTTPClient() *http.Client {
+	return egressHTTPClient
+}
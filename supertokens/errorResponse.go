@@ -0,0 +1,56 @@
+/* Copyright (c) 2023, VRAI Labs and/or its affiliates. All rights reserved.
+ *
+ * This software is licensed under the Apache License, Version 2.0 (the
+ * "License") as published by the Apache Software Foundation.
+ *
+ * You may not use this file except in compliance with the License. You may
+ * obtain a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+ * License for the specific language governing permissions and limitations
+ * under the License.
+ */
+
+package supertokens
+
+import (
+	"encoding/json"
+	"net/http"
+)
+
+// ErrorResponseSerializer returns the content type and body of the non 200
+// responses sent by the SDK. body is what would be sent as JSON by default,
+// usually {"message": "..."} plus fields specific to the error.
+type ErrorResponseSerializer func(statusCode int, body map[string]interface{}) (contentType string, serialized []byte, err error)
+
+func defaultErrorResponseSerializer(statusCode int, body map[string]interface{}) (string, []byte, error) {
+	serialized, err := json.Marshal(body)
+	return "application/json; charset=utf-8", serialized, err
+}
+
+// ProblemJSONErrorResponseSerializer sends errors as RFC 7807 problem details
+// (application/problem+json). The fields of the default body are kept as
+// extension members so that frontend SDKs can still read them.
+func ProblemJSONErrorResponseSerializer(statusCode int, body map[string]interface{}) (string, []byte, error) {
+	problem := map[string]interface{}{}
+	for key, value := range body {
+		problem[key] = value
+	}
+	problem["type"] = "about:blank"
+	problem["title"] = http.StatusText(statusCode)
+	problem["status"] = statusCode
+	if message, ok := body["message"].(string); ok {
+		problem["detail"] = message
+	}
+	serialized, err := json.Marshal(problem)
+	return "application/problem+json", serialized, err
+}
+
+func getErrorResponseSerializer() ErrorResponseSerializer {
+	if superTokensInstance != nil && superTokensInstance.ErrorResponseSerializer != nil {
+		return superTokensInstance.ErrorResponseSerializer
+	}
+	return defaultErrorResponseSerializer
+}
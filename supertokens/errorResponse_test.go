@@ -0,0 +1,81 @@
+/* Copyright (c) 2023, VRAI Labs and/or its affiliates. All rights reserved.
+ *
+ * This software is licensed under the Apache License, Version 2.0 (the
+ * "License") as published by the Apache Software Foundation.
+ *
+ * You may not use this file except in compliance with the License. You may
+ * obtain a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+ * License for the specific language governing permissions and limitations
+ * under the License.
+ */
+
+package supertokens
+
+import (
+	"errors"
+	"fmt"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestErrorResponsesUseDefaultSerializer(t *testing.T) {
+	defer ResetForTest()
+	ResetForTest()
+
+	res := httptest.NewRecorder()
+	err := SendNon200ResponseWithMessage(res, "unauthorised", 401)
+	assert.NoError(t, err)
+	assert.Equal(t, "application/json; charset=utf-8", res.Header().Get("Content-Type"))
+	assert.JSONEq(t, `{"message": "unauthorised"}`, res.Body.String())
+}
+
+func TestErrorResponsesUseProblemJSONSerializer(t *testing.T) {
+	defer ResetForTest()
+	superTokensInstance = &superTokens{
+		ErrorResponseSerializer: ProblemJSONErrorResponseSerializer,
+	}
+
+	res := httptest.NewRecorder()
+	err := SendNon200Response(res, 403, map[string]interface{}{
+		"message":               "invalid claim",
+		"claimValidationErrors": []string{"st-ev"},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, 403, res.Code)
+	assert.Equal(t, "application/problem+json", res.Header().Get("Content-Type"))
+	assert.JSONEq(t, `{
+		"type": "about:blank",
+		"title": "Forbidden",
+		"status": 403,
+		"detail": "invalid claim",
+		"message": "invalid claim",
+		"claimValidationErrors": ["st-ev"]
+	}`, res.Body.String())
+
+	res = httptest.NewRecorder()
+	defaultOnSuperTokensAPIError(errors.New("core unavailable"), httptest.NewRequest("GET", "/", nil), res)
+	assert.Equal(t, 500, res.Code)
+	assert.Equal(t, "application/problem+json", res.Header().Get("Content-Type"))
+	assert.Contains(t, res.Body.String(), `"detail":"core unavailable"`)
+}
+
+func TestThatCustomErrorResponseSerializerIsUsed(t *testing.T) {
+	defer ResetForTest()
+	superTokensInstance = &superTokens{
+		ErrorResponseSerializer: func(statusCode int, body map[string]interface{}) (string, []byte, error) {
+			return "application/vnd.company.error+json", []byte(fmt.Sprintf(`{"error":{"code":%d}}`, statusCode)), nil
+		},
+	}
+
+	res := httptest.NewRecorder()
+	err := SendUnauthorisedAccess(res)
+	assert.NoError(t, err)
+	assert.Equal(t, "application/vnd.company.error+json", res.Header().Get("Content-Type"))
+	assert.Equal(t, `{"error":{"code":401}}`, res.Body.String())
+}
@@ -0,0 +1,273 @@
+/* Copyright (c) 2023, VRAI Labs and/or its affiliates. All rights reserved.
+ *
+ * This software is licensed under the Apache License, Version 2.0 (the
+ * "License") as published by the Apache Software Foundation.
+ *
+ * You may not use this file except in compliance with the License. You may
+ * obtain a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+ * License for the specific language governing permissions and limitations
+ * under the License.
+ */
+
+package supertokens
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// EventOverflowPolicy decides what happens to new events once the webhook's
+// buffer is full, which happens if the webhook is slower than the rate at
+// which events are emitted.
+type EventOverflowPolicy string
+
+const (
+	// EventOverflowDropOldest drops the oldest buffered event
+	EventOverflowDropOldest EventOverflowPolicy = "DROP_OLDEST"
+	// EventOverflowBlock makes EmitEvent wait up to MaxBlockTime for space in
+	// the buffer, and drops the new event if there is none by then
+	EventOverflowBlock EventOverflowPolicy = "BLOCK"
+	// EventOverflowSpillToDisk writes new events to a file in SpillDirectory.
+	// They are delivered once the events in memory have been.
+	EventOverflowSpillToDisk EventOverflowPolicy = "SPILL_TO_DISK"
+)
+
+const defaultEventBufferSize = 1000
+const defaultEventBufferMaxBlockTime = time.Second
+const eventSpillFileName = "supertokens-events.jsonl"
+
+type EventBufferConfig struct {
+	// Size is the number of events kept in memory. Defaults to 1000.
+	Size int
+	// OverflowPolicy defaults to EventOverflowDropOldest
+	OverflowPolicy EventOverflowPolicy
+	// MaxBlockTime is only used by EventOverflowBlock. Defaults to 1 second.
+	MaxBlockTime time.Duration
+	// SpillDirectory is required by EventOverflowSpillToDisk
+	SpillDirectory string
+}
+
+// eventBuffer queues events in memory (and on disk if configured) and
+// delivers them one at a time on its own goroutine, started on the first
+// event.
+type eventBuffer struct {
+	size           int
+	overflowPolicy EventOverflowPolicy
+	maxBlockTime   time.Duration
+	spillPath      string
+	deliver        func(ctx context.Context, event Event) error
+
+	lock    sync.Mutex
+	events  []Event
+	spilled int
+	ctx     context.Context
+	cancel  context.CancelFunc
+	done    chan struct{}
+	// notEmpty and notFull wake up the delivery loop and blocked emitters
+	notEmpty chan struct{}
+	notFull  chan struct{}
+}
+
+func makeEventBuffer(config *EventBufferConfig, deliver func(ctx context.Context, event Event) error) (*eventBuffer, error) {
+	buffer := &eventBuffer{
+		size:           defaultEventBufferSize,
+		overflowPolicy: EventOverflowDropOldest,
+		maxBlockTime:   defaultEventBufferMaxBlockTime,
+		deliver:        deliver,
+		notEmpty:       make(chan struct{}, 1),
+		notFull:        make(chan struct{}, 1),
+	}
+	if config == nil {
+		return buffer, nil
+	}
+	if config.Size < 0 {
+		return nil, errors.New("the Size of the event buffer cannot be negative")
+	}
+	if config.Size > 0 {
+		buffer.size = config.Size
+	}
+	if config.MaxBlockTime > 0 {
+		buffer.maxBlockTime = config.MaxBlockTime
+	}
+	switch config.OverflowPolicy {
+	case "":
+	case EventOverflowDropOldest, EventOverflowBlock:
+		buffer.overflowPolicy = config.OverflowPolicy
+	case EventOverflowSpillToDisk:
+		if config.SpillDirectory == "" {
+			return nil, errors.New("please provide a SpillDirectory to spill events to disk")
+		}
+		buffer.overflowPolicy = config.OverflowPolicy
+		buffer.spillPath = filepath.Join(config.SpillDirectory, eventSpillFileName)
+	default:
+		return nil, fmt.Errorf("unknown event OverflowPolicy: %s", config.OverflowPolicy)
+	}
+	return buffer, nil
+}
+
+func signal(ch chan struct{}) {
+	select {
+	case ch <- struct{}{}:
+	default:
+	}
+}
+
+// push adds an event to the buffer. It only waits with EventOverflowBlock.
+func (b *eventBuffer) push(event Event) {
+	var deadline <-chan time.Time
+	for {
+		b.lock.Lock()
+		if b.cancel == nil {
+			b.ctx, b.cancel = context.WithCancel(context.Background())
+			b.done = make(chan struct{})
+			go b.run(b.ctx, b.done)
+		}
+		if len(b.events) < b.size && b.spilled == 0 {
+			b.events = append(b.events, event)
+			b.lock.Unlock()
+			signal(b.notEmpty)
+			return
+		}
+
+		switch b.overflowPolicy {
+		case EventOverflowSpillToDisk:
+			err := b.spill([]Event{event})
+			b.lock.Unlock()
+			if err != nil {
+				LogDebugMessage(fmt.Sprintf("EmitEvent: dropping %s event %s, spilling it to disk failed: %s", event.Type, event.ID, err.Error()))
+			}
+			signal(b.notEmpty)
+			return
+		case EventOverflowBlock:
+			b.lock.Unlock()
+			if deadline == nil {
+				deadline = time.After(b.maxBlockTime)
+			}
+			select {
+			case <-b.notFull:
+				continue
+			case <-deadline:
+				LogDebugMessage(fmt.Sprintf("EmitEvent: dropping %s event %s, the event buffer is full", event.Type, event.ID))
+				return
+			}
+		default:
+			dropped := b.events[0]
+			b.events = append(b.events[1:], event)
+			b.lock.Unlock()
+			LogDebugMessage(fmt.Sprintf("EmitEvent: dropping %s event %s, the event buffer is full", dropped.Type, dropped.ID))
+			return
+		}
+	}
+}
+
+func (b *eventBuffer) run(ctx context.Context, done chan struct{}) {
+	defer close(done)
+	for {
+		b.lock.Lock()
+		if len(b.events) == 0 && b.spilled > 0 {
+			err := b.loadSpilled()
+			if err != nil {
+				LogDebugMessage("EmitEvent: reading spilled events failed: " + err.Error())
+			}
+		}
+		if len(b.events) == 0 {
+			b.lock.Unlock()
+			select {
+			case <-ctx.Done():
+				return
+			case <-b.notEmpty:
+			}
+			continue
+		}
+		event := b.events[0]
+		b.events = b.events[1:]
+		b.lock.Unlock()
+		signal(b.notFull)
+
+		err := b.deliver(ctx, event)
+		if ctx.Err() != nil {
+			return
+		}
+		if err != nil {
+			LogDebugMessage(fmt.Sprintf("EmitEvent: delivering %s event %s failed: %s", event.Type, event.ID, err.Error()))
+		}
+	}
+}
+
+// spill must be called with the lock held
+func (b *eventBuffer) spill(events []Event) error {
+	file, err := os.OpenFile(b.spillPath, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	encoder := json.NewEncoder(file)
+	for _, event := range events {
+		err = encoder.Encode(event)
+		if err != nil {
+			return err
+		}
+		b.spilled++
+	}
+	return nil
+}
+
+// loadSpilled moves up to size spilled events into memory. It must be called
+// with the lock held.
+func (b *eventBuffer) loadSpilled() error {
+	file, err := os.Open(b.spillPath)
+	if err != nil {
+		b.spilled = 0
+		return err
+	}
+	spilled := []Event{}
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+	for scanner.Scan() {
+		var event Event
+		if json.Unmarshal(scanner.Bytes(), &event) == nil {
+			spilled = append(spilled, event)
+		}
+	}
+	file.Close()
+	if err := scanner.Err(); err != nil {
+		return err
+	}
+	err = os.Remove(b.spillPath)
+	if err != nil {
+		return err
+	}
+
+	b.spilled = 0
+	if len(spilled) > b.size {
+		b.events = append(b.events, spilled[:b.size]...)
+		return b.spill(spilled[b.size:])
+	}
+	b.events = append(b.events, spilled...)
+	return nil
+}
+
+// stop ends the delivery loop and waits for it to exit. Buffered events
+// that were not delivered yet are dropped, apart from the ones spilled to
+// disk.
+func (b *eventBuffer) stop() {
+	b.lock.Lock()
+	cancel, done := b.cancel, b.done
+	b.cancel, b.done = nil, nil
+	b.lock.Unlock()
+	if cancel != nil {
+		cancel()
+		<-done
+	}
+}
@@ -0,0 +1,263 @@
+/* Copyright (c) 2023, VRAI Labs and/or its affiliates. All rights reserved.
+ *
+ * This software is licensed under the Apache License, Version 2.0 (the
+ * "License") as published by the Apache Software Foundation.
+ *
+ * You may not use this file except in compliance with the License. You may
+ * obtain a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+ * License for the specific language governing permissions and limitations
+ * under the License.
+ */
+
+package supertokens
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+type EventType string
+
+const (
+	EventSignUp         EventType = "SIGN_UP"
+	EventSignIn         EventType = "SIGN_IN"
+	EventSignOut        EventType = "SIGN_OUT"
+	EventSessionRefresh EventType = "SESSION_REFRESH"
+	EventPasswordReset  EventType = "PASSWORD_RESET"
+	EventUserDeleted    EventType = "USER_DELETED"
+	// EventExperimentExposure is emitted by GetExperimentVariant. Its
+	// UserID is the unit ID of the exposure.
+	EventExperimentExposure EventType = "EXPERIMENT_EXPOSURE"
+)
+
+const (
+	WebhookIDHeader        = "st-webhook-id"
+	WebhookTimestampHeader = "st-webhook-timestamp"
+	WebhookSignatureHeader = "st-webhook-signature"
+)
+
+const defaultWebhookMaxRetries = 3
+const defaultWebhookRetryBackoff = time.Second
+const defaultWebhookTimeout = 5 * time.Second
+
+// EventsInput sends auth events (sign up, sign in, ...) to other systems
+type EventsInput struct {
+	// OnEvent is called before the API that caused the event responds
+	OnEvent func(event Event, userContext UserContext)
+	Webhook *WebhookConfig
+}
+
+// WebhookConfig sends events as signed JSON POST requests. Events are queued
+// in a bounded buffer and delivered in order on their own goroutine, so they
+// never delay the API that caused the event. See EventBufferConfig for what
+// happens when the webhook cannot keep up.
+type WebhookConfig struct {
+	URL string
+	// Secret signs the requests, see VerifyWebhookSignature
+	Secret string
+	// EventTypes limits which events are sent. Defaults to all events.
+	EventTypes []EventType
+	// MaxRetries defaults to 3. Retries are made on network errors and
+	// non 2xx responses.
+	MaxRetries *int
+	// RetryBackoff is doubled after each retry. Defaults to 1 second.
+	RetryBackoff time.Duration
+	// Timeout of each attempt. Defaults to 5 seconds.
+	Timeout time.Duration
+	// Buffer defaults to 1000 events in memory, dropping the oldest
+	Buffer *EventBufferConfig
+}
+
+type Event struct {
+	ID        string                 `json:"id"`
+	Type      EventType              `json:"type"`
+	TenantId  string                 `json:"tenantId,omitempty"`
+	UserID    string                 `json:"userId"`
+	Timestamp int64                  `json:"timestamp"`
+	Data      map[string]interface{} `json:"data,omitempty"`
+}
+
+type normalisedEvents struct {
+	onEvent func(event Event, userContext UserContext)
+	webhook *normalisedWebhook
+}
+
+type normalisedWebhook struct {
+	url          string
+	secret       string
+	eventTypes   map[EventType]bool
+	maxRetries   int
+	retryBackoff time.Duration
+	timeout      time.Duration
+	buffer       *eventBuffer
+}
+
+func normaliseEventsInput(config *EventsInput) (normalisedEvents, error) {
+	if config == nil {
+		return normalisedEvents{}, nil
+	}
+	result := normalisedEvents{
+		onEvent: config.OnEvent,
+	}
+	if config.Webhook != nil {
+		if config.Webhook.URL == "" {
+			return normalisedEvents{}, errors.New("please provide the URL of the webhook")
+		}
+		if config.Webhook.Secret == "" {
+			return normalisedEvents{}, errors.New("please provide a secret to sign webhook requests with")
+		}
+		webhook := &normalisedWebhook{
+			url:          config.Webhook.URL,
+			secret:       config.Webhook.Secret,
+			maxRetries:   defaultWebhookMaxRetries,
+			retryBackoff: defaultWebhookRetryBackoff,
+			timeout:      defaultWebhookTimeout,
+		}
+		if len(config.Webhook.EventTypes) > 0 {
+			webhook.eventTypes = map[EventType]bool{}
+			for _, eventType := range config.Webhook.EventTypes {
+				webhook.eventTypes[eventType] = true
+			}
+		}
+		if config.Webhook.MaxRetries != nil {
+			if *config.Webhook.MaxRetries < 0 {
+				return normalisedEvents{}, errors.New("MaxRetries of the webhook cannot be negative")
+			}
+			webhook.maxRetries = *config.Webhook.MaxRetries
+		}
+		if config.Webhook.RetryBackoff > 0 {
+			webhook.retryBackoff = config.Webhook.RetryBackoff
+		}
+		if config.Webhook.Timeout > 0 {
+			webhook.timeout = config.Webhook.Timeout
+		}
+		buffer, err := makeEventBuffer(config.Webhook.Buffer, webhook.deliver)
+		if err != nil {
+			return normalisedEvents{}, err
+		}
+		webhook.buffer = buffer
+		result.webhook = webhook
+	}
+	return result, nil
+}
+
+// EmitEvent sends an event to OnEvent and the webhook. Recipes call this for
+// the built in events, and apps can use it for their own event types. The ID
+// and timestamp are set if empty.
+func EmitEvent(event Event, userContext UserContext) {
+	if superTokensInstance == nil {
+		return
+	}
+	events := superTokensInstance.Events
+	if events.onEvent == nil && events.webhook == nil {
+		return
+	}
+	if event.ID == "" {
+		event.ID = generateEventID()
+	}
+	if event.Timestamp == 0 {
+		event.Timestamp = time.Now().UnixMilli()
+	}
+
+	if events.onEvent != nil {
+		events.onEvent(event, userContext)
+	}
+	webhook := events.webhook
+	if webhook != nil && (webhook.eventTypes == nil || webhook.eventTypes[event.Type]) {
+		webhook.buffer.push(event)
+	}
+}
+
+func (w *normalisedWebhook) deliver(ctx context.Context, event Event) error {
+	body, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	backoff := w.retryBackoff
+	for attempt := 0; ; attempt++ {
+		err = w.send(ctx, event.ID, body)
+		if err == nil || attempt >= w.maxRetries {
+			return err
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(backoff):
+		}
+		backoff *= 2
+	}
+}
+
+func (w *normalisedWebhook) send(ctx context.Context, eventID string, body []byte) error {
+	ctx, cancel := context.WithTimeout(ctx, w.timeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, w.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(WebhookIDHeader, eventID)
+	req.Header.Set(WebhookTimestampHeader, timestamp)
+	req.Header.Set(WebhookSignatureHeader, signWebhookPayload(w.secret, timestamp, body))
+
+	resp, err := GetEgressHTTPClient().Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook responded with status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// signWebhookPayload returns "v1=" followed by the hex encoded
+// HMAC-SHA256 of "<timestamp>.<body>"
+func signWebhookPayload(secret string, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return "v1=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// VerifyWebhookSignature checks the signature of a webhook request, for use
+// by the services receiving events. Requests with a timestamp older than
+// tolerance are rejected to prevent replays.
+func VerifyWebhookSignature(secret string, header http.Header, body []byte, tolerance time.Duration) bool {
+	timestamp := header.Get(WebhookTimestampHeader)
+	seconds, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return false
+	}
+	age := time.Since(time.Unix(seconds, 0))
+	if age > tolerance || age < -tolerance {
+		return false
+	}
+	expected := signWebhookPayload(secret, timestamp, body)
+	return hmac.Equal([]byte(expected), []byte(header.Get(WebhookSignatureHeader)))
+}
+
+func generateEventID() string {
+	id := make([]byte, 16)
+	_, err := rand.Read(id)
+	if err != nil {
+		return strconv.FormatInt(time.Now().UnixNano(), 10)
+	}
+	return hex.EncodeToString(id)
+}
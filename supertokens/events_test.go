@@ -0,0 +1,282 @@
+/* Copyright (c) 2023, VRAI Labs and/or its affiliates. All rights reserved.
+ *
+ * This software is licensed under the Apache License, Version 2.0 (the
+ * "License") as published by the Apache Software Foundation.
+ *
+ * You may not use this file except in compliance with the License. You may
+ * obtain a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+ * License for the specific language governing permissions and limitations
+ * under the License.
+ */
+
+package supertokens
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func initWithEvents(events *EventsInput) error {
+	ResetForTest()
+	postInitCallbacks = []func() error{}
+	return Init(TypeInput{
+		AppInfo: AppInfo{
+			AppName:       "SuperTokens",
+			APIDomain:     "api.supertokens.io",
+			WebsiteDomain: "supertokens.io",
+		},
+		RecipeList: []Recipe{makeTestRecipe("session", func() {})},
+		Events:     events,
+	})
+}
+
+func TestEmitEventCallsOnEvent(t *testing.T) {
+	defer ResetForTest()
+	events := []Event{}
+	err := initWithEvents(&EventsInput{
+		OnEvent: func(event Event, userContext UserContext) {
+			events = append(events, event)
+		},
+	})
+	assert.NoError(t, err)
+
+	EmitEvent(Event{Type: EventSignUp, TenantId: "public", UserID: "user1"}, &map[string]interface{}{})
+
+	assert.Len(t, events, 1)
+	assert.Equal(t, EventSignUp, events[0].Type)
+	assert.Equal(t, "user1", events[0].UserID)
+	assert.Len(t, events[0].ID, 32)
+	assert.NotZero(t, events[0].Timestamp)
+}
+
+func TestEmitEventWithoutConfigDoesNothing(t *testing.T) {
+	defer ResetForTest()
+	ResetForTest()
+	EmitEvent(Event{Type: EventSignUp, UserID: "user1"}, &map[string]interface{}{})
+
+	err := initWithEvents(nil)
+	assert.NoError(t, err)
+	EmitEvent(Event{Type: EventSignUp, UserID: "user1"}, &map[string]interface{}{})
+}
+
+func TestWebhookRequestsAreSignedAndRetried(t *testing.T) {
+	defer ResetForTest()
+	var lock sync.Mutex
+	attempts := 0
+	var received Event
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		lock.Lock()
+		defer lock.Unlock()
+		attempts++
+		if attempts == 1 {
+			rw.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		body, _ := io.ReadAll(r.Body)
+		assert.True(t, VerifyWebhookSignature("secret", r.Header, body, time.Minute))
+		assert.False(t, VerifyWebhookSignature("other-secret", r.Header, body, time.Minute))
+		assert.NoError(t, json.Unmarshal(body, &received))
+		assert.Equal(t, received.ID, r.Header.Get(WebhookIDHeader))
+	}))
+	defer server.Close()
+
+	err := initWithEvents(&EventsInput{
+		Webhook: &WebhookConfig{
+			URL:          server.URL,
+			Secret:       "secret",
+			EventTypes:   []EventType{EventSignIn},
+			RetryBackoff: time.Millisecond,
+		},
+	})
+	assert.NoError(t, err)
+
+	// filtered out by EventTypes
+	EmitEvent(Event{Type: EventSignUp, UserID: "user1"}, &map[string]interface{}{})
+	// queued in the webhook's buffer and delivered on its goroutine
+	EmitEvent(Event{Type: EventSignIn, UserID: "user1", Data: map[string]interface{}{"recipeId": "emailpassword"}}, &map[string]interface{}{})
+
+	assert.Eventually(t, func() bool {
+		lock.Lock()
+		defer lock.Unlock()
+		return attempts == 2
+	}, time.Second, time.Millisecond)
+	lock.Lock()
+	defer lock.Unlock()
+	assert.Equal(t, EventSignIn, received.Type)
+	assert.Equal(t, "user1", received.UserID)
+	assert.Equal(t, "emailpassword", received.Data["recipeId"])
+}
+
+func TestWebhookDeliveryStopsAfterMaxRetries(t *testing.T) {
+	attempts := 0
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		attempts++
+		rw.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	maxRetries := 2
+	events, err := normaliseEventsInput(&EventsInput{
+		Webhook: &WebhookConfig{
+			URL:          server.URL,
+			Secret:       "secret",
+			MaxRetries:   &maxRetries,
+			RetryBackoff: time.Millisecond,
+		},
+	})
+	assert.NoError(t, err)
+	err = events.webhook.deliver(context.Background(), Event{ID: "id", Type: EventSignOut})
+	assert.Error(t, err)
+	assert.Equal(t, 3, attempts)
+}
+
+func TestVerifyWebhookSignatureRejectsOldTimestamps(t *testing.T) {
+	body := []byte(`{"type":"SIGN_UP"}`)
+	timestamp := strconv.FormatInt(time.Now().Add(-time.Hour).Unix(), 10)
+	header := http.Header{}
+	header.Set(WebhookTimestampHeader, timestamp)
+	header.Set(WebhookSignatureHeader, signWebhookPayload("secret", timestamp, body))
+
+	assert.True(t, VerifyWebhookSignature("secret", header, body, 2*time.Hour))
+	assert.False(t, VerifyWebhookSignature("secret", header, body, time.Minute))
+	assert.False(t, VerifyWebhookSignature("secret", header, []byte(`{"type":"SIGN_IN"}`), 2*time.Hour))
+}
+
+func TestEventsConfigValidation(t *testing.T) {
+	_, err := normaliseEventsInput(&EventsInput{Webhook: &WebhookConfig{Secret: "secret"}})
+	assert.Error(t, err)
+	_, err = normaliseEventsInput(&EventsInput{Webhook: &WebhookConfig{URL: "https://example.com"}})
+	assert.Error(t, err)
+	maxRetries := -1
+	_, err = normaliseEventsInput(&EventsInput{Webhook: &WebhookConfig{URL: "https://example.com", Secret: "secret", MaxRetries: &maxRetries}})
+	assert.Error(t, err)
+	_, err = normaliseEventsInput(&EventsInput{Webhook: &WebhookConfig{URL: "https://example.com", Secret: "secret", Buffer: &EventBufferConfig{OverflowPolicy: EventOverflowSpillToDisk}}})
+	assert.Error(t, err)
+	_, err = normaliseEventsInput(&EventsInput{Webhook: &WebhookConfig{URL: "https://example.com", Secret: "secret", Buffer: &EventBufferConfig{OverflowPolicy: "UNKNOWN"}}})
+	assert.Error(t, err)
+}
+
+// blockedDelivery returns a deliver func that blocks until release is
+// closed, and records the IDs of the delivered events
+func blockedDelivery() (func(ctx context.Context, event Event) error, chan struct{}, func() []string) {
+	var lock sync.Mutex
+	delivered := []string{}
+	release := make(chan struct{})
+	deliver := func(ctx context.Context, event Event) error {
+		select {
+		case <-release:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+		lock.Lock()
+		defer lock.Unlock()
+		delivered = append(delivered, event.ID)
+		return nil
+	}
+	return deliver, release, func() []string {
+		lock.Lock()
+		defer lock.Unlock()
+		return append([]string{}, delivered...)
+	}
+}
+
+func TestEventBufferDropsTheOldestEventsWhenFull(t *testing.T) {
+	deliver, release, delivered := blockedDelivery()
+	buffer, err := makeEventBuffer(&EventBufferConfig{Size: 2}, deliver)
+	assert.NoError(t, err)
+	defer buffer.stop()
+
+	// "1" is taken by the delivery loop, "2" is dropped for "4"
+	buffer.push(Event{ID: "1"})
+	assert.Eventually(t, func() bool {
+		buffer.lock.Lock()
+		defer buffer.lock.Unlock()
+		return len(buffer.events) == 0
+	}, time.Second, time.Millisecond)
+	start := time.Now()
+	buffer.push(Event{ID: "2"})
+	buffer.push(Event{ID: "3"})
+	buffer.push(Event{ID: "4"})
+	assert.Less(t, time.Since(start), 100*time.Millisecond)
+
+	close(release)
+	assert.Eventually(t, func() bool {
+		return len(delivered()) == 3
+	}, time.Second, time.Millisecond)
+	assert.Equal(t, []string{"1", "3", "4"}, delivered())
+}
+
+func TestEventBufferBlocksUpToMaxBlockTime(t *testing.T) {
+	deliver, release, delivered := blockedDelivery()
+	buffer, err := makeEventBuffer(&EventBufferConfig{Size: 1, OverflowPolicy: EventOverflowBlock, MaxBlockTime: 50 * time.Millisecond}, deliver)
+	assert.NoError(t, err)
+	defer buffer.stop()
+
+	buffer.push(Event{ID: "1"})
+	assert.Eventually(t, func() bool {
+		buffer.lock.Lock()
+		defer buffer.lock.Unlock()
+		return len(buffer.events) == 0
+	}, time.Second, time.Millisecond)
+	buffer.push(Event{ID: "2"})
+
+	// the buffer stays full, so "3" is dropped after MaxBlockTime
+	start := time.Now()
+	buffer.push(Event{ID: "3"})
+	assert.GreaterOrEqual(t, time.Since(start), 50*time.Millisecond)
+
+	// space frees up while "4" is waiting, so it is kept
+	go func() {
+		time.Sleep(10 * time.Millisecond)
+		close(release)
+	}()
+	buffer.push(Event{ID: "4"})
+
+	assert.Eventually(t, func() bool {
+		return len(delivered()) == 3
+	}, time.Second, time.Millisecond)
+	assert.Equal(t, []string{"1", "2", "4"}, delivered())
+}
+
+func TestEventBufferSpillsToDiskWhenFull(t *testing.T) {
+	deliver, release, delivered := blockedDelivery()
+	directory := t.TempDir()
+	buffer, err := makeEventBuffer(&EventBufferConfig{Size: 1, OverflowPolicy: EventOverflowSpillToDisk, SpillDirectory: directory}, deliver)
+	assert.NoError(t, err)
+	defer buffer.stop()
+
+	buffer.push(Event{ID: "1"})
+	assert.Eventually(t, func() bool {
+		buffer.lock.Lock()
+		defer buffer.lock.Unlock()
+		return len(buffer.events) == 0
+	}, time.Second, time.Millisecond)
+	for _, id := range []string{"2", "3", "4", "5"} {
+		buffer.push(Event{ID: id, Type: EventSignUp})
+	}
+	buffer.lock.Lock()
+	assert.Equal(t, 3, buffer.spilled)
+	buffer.lock.Unlock()
+	assert.FileExists(t, filepath.Join(directory, eventSpillFileName))
+
+	close(release)
+	assert.Eventually(t, func() bool {
+		return len(delivered()) == 5
+	}, time.Second, time.Millisecond)
+	assert.Equal(t, []string{"1", "2", "3", "4", "5"}, delivered())
+	assert.NoFileExists(t, filepath.Join(directory, eventSpillFileName))
+}
@@ -0,0 +1,160 @@
+/* Copyright (c) 2023, VRAI Labs and/or its affiliates. All rights reserved.
+ *
+ * This software is licensed under the Apache License, Version 2.0 (the
+ * "License") as published by the Apache Software Foundation.
+ *
+ * You may not use this file except in compliance with the License. You may
+ * obtain a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+ * License for the specific language governing permissions and limitations
+ * under the License.
+ */
+
+package supertokens
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+)
+
+// ExperimentsInput configures A/B tests of auth flows. Overrides (API
+// overrides, email and SMS templates, ...) call GetExperimentVariant to find
+// out which variant to use.
+type ExperimentsInput struct {
+	Experiments []Experiment
+	// OnExposure is called the first time in each request that a unit is
+	// assigned a variant of an experiment. Use it to record exposures in your
+	// analytics system.
+	OnExposure func(exposure ExperimentExposure, userContext UserContext)
+}
+
+type Experiment struct {
+	ID       string
+	Variants []ExperimentVariant
+}
+
+type ExperimentVariant struct {
+	Name string
+	// Weight is the relative share of units assigned to this variant.
+	// Defaults to 1 if all weights are 0.
+	Weight uint64
+}
+
+type ExperimentExposure struct {
+	ExperimentID string
+	UnitID       string
+	Variant      string
+}
+
+type normalisedExperiments struct {
+	experiments map[string]Experiment
+	onExposure  func(exposure ExperimentExposure, userContext UserContext)
+}
+
+func normaliseExperimentsInput(config *ExperimentsInput) (normalisedExperiments, error) {
+	result := normalisedExperiments{
+		experiments: map[string]Experiment{},
+	}
+	if config == nil {
+		return result, nil
+	}
+	for _, experiment := range config.Experiments {
+		if experiment.ID == "" {
+			return normalisedExperiments{}, errors.New("experiments must have an ID")
+		}
+		if len(experiment.Variants) == 0 {
+			return normalisedExperiments{}, errors.New("experiment " + experiment.ID + " must have at least one variant")
+		}
+		if _, ok := result.experiments[experiment.ID]; ok {
+			return normalisedExperiments{}, errors.New("experiment " + experiment.ID + " is defined more than once")
+		}
+		totalWeight := uint64(0)
+		for _, variant := range experiment.Variants {
+			totalWeight += variant.Weight
+		}
+		if totalWeight == 0 {
+			variants := make([]ExperimentVariant, len(experiment.Variants))
+			for i, variant := range experiment.Variants {
+				variants[i] = ExperimentVariant{Name: variant.Name, Weight: 1}
+			}
+			experiment.Variants = variants
+		}
+		result.experiments[experiment.ID] = experiment
+	}
+	result.onExposure = config.OnExposure
+	return result, nil
+}
+
+// assignVariant deterministically maps a unit to a variant, so the same user
+// or device always sees the same variant of an experiment
+func assignVariant(experiment Experiment, unitID string) string {
+	totalWeight := uint64(0)
+	for _, variant := range experiment.Variants {
+		totalWeight += variant.Weight
+	}
+	hash := sha256.Sum256([]byte(experiment.ID + ":" + unitID))
+	bucket := binary.BigEndian.Uint64(hash[:8]) % totalWeight
+	for _, variant := range experiment.Variants {
+		if bucket < variant.Weight {
+			return variant.Name
+		}
+		bucket -= variant.Weight
+	}
+	// should never come here
+	return experiment.Variants[len(experiment.Variants)-1].Name
+}
+
+// GetExperimentVariant returns the variant of the experiment assigned to
+// unitID (usually a user ID or a device ID) and reports the exposure, both to
+// OnExposure and as an EXPERIMENT_EXPOSURE event.
+func GetExperimentVariant(experimentID string, unitID string, userContext ...UserContext) (string, error) {
+	instance, err := GetInstanceOrThrowError()
+	if err != nil {
+		return "", err
+	}
+	if len(userContext) == 0 || userContext[0] == nil {
+		userContext = []UserContext{&map[string]interface{}{}}
+	}
+	experiment, ok := instance.Experiments.experiments[experimentID]
+	if !ok {
+		return "", errors.New("unknown experiment: " + experimentID)
+	}
+	variant := assignVariant(experiment, unitID)
+
+	// the user context is used to only report an exposure once per request
+	defaultObj, ok := (*userContext[0])["_default"].(map[string]interface{})
+	if !ok {
+		defaultObj = map[string]interface{}{}
+		(*userContext[0])["_default"] = defaultObj
+	}
+	exposures, ok := defaultObj["experimentExposures"].(map[string]bool)
+	if !ok {
+		exposures = map[string]bool{}
+		defaultObj["experimentExposures"] = exposures
+	}
+	exposureKey := experimentID + ":" + unitID
+	if !exposures[exposureKey] {
+		exposures[exposureKey] = true
+		LogDebugMessage("GetExperimentVariant: " + unitID + " exposed to variant " + variant + " of experiment " + experimentID)
+		if instance.Experiments.onExposure != nil {
+			instance.Experiments.onExposure(ExperimentExposure{
+				ExperimentID: experimentID,
+				UnitID:       unitID,
+				Variant:      variant,
+			}, userContext[0])
+		}
+		EmitEvent(Event{
+			Type:   EventExperimentExposure,
+			UserID: unitID,
+			Data: map[string]interface{}{
+				"experimentId": experimentID,
+				"variant":      variant,
+			},
+		}, userContext[0])
+	}
+	return variant, nil
+}
@@ -0,0 +1,132 @@
+/* Copyright (c) 2023, VRAI Labs and/or its affiliates. All rights reserved.
+ *
+ * This software is licensed under the Apache License, Version 2.0 (the
+ * "License") as published by the Apache Software Foundation.
+ *
+ * You may not use this file except in compliance with the License. You may
+ * obtain a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+ * License for the specific language governing permissions and limitations
+ * under the License.
+ */
+
+package supertokens
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExperimentAssignmentIsDeterministicAndWeighted(t *testing.T) {
+	experiment := Experiment{
+		ID: "verificationEmailSubject",
+		Variants: []ExperimentVariant{
+			{Name: "control", Weight: 3},
+			{Name: "short", Weight: 1},
+		},
+	}
+
+	counts := map[string]int{}
+	for i := 0; i < 4000; i++ {
+		unitID := fmt.Sprint("user", i)
+		variant := assignVariant(experiment, unitID)
+		assert.Equal(t, variant, assignVariant(experiment, unitID))
+		counts[variant]++
+	}
+	assert.InDelta(t, 3000, counts["control"], 200)
+	assert.InDelta(t, 1000, counts["short"], 200)
+}
+
+func TestExperimentExposureIsReportedOncePerRequest(t *testing.T) {
+	exposures := []ExperimentExposure{}
+	experiments, err := normaliseExperimentsInput(&ExperimentsInput{
+		Experiments: []Experiment{{
+			ID:       "signUpCopy",
+			Variants: []ExperimentVariant{{Name: "a"}, {Name: "b"}},
+		}},
+		OnExposure: func(exposure ExperimentExposure, userContext UserContext) {
+			exposures = append(exposures, exposure)
+		},
+	})
+	assert.NoError(t, err)
+	superTokensInstance = &superTokens{Experiments: experiments}
+	defer ResetForTest()
+
+	userContext := &map[string]interface{}{}
+	variant, err := GetExperimentVariant("signUpCopy", "user1", userContext)
+	assert.NoError(t, err)
+	sameVariant, err := GetExperimentVariant("signUpCopy", "user1", userContext)
+	assert.NoError(t, err)
+	assert.Equal(t, variant, sameVariant)
+
+	assert.Equal(t, []ExperimentExposure{{
+		ExperimentID: "signUpCopy",
+		UnitID:       "user1",
+		Variant:      variant,
+	}}, exposures)
+
+	_, err = GetExperimentVariant("signUpCopy", "user1")
+	assert.NoError(t, err)
+	assert.Len(t, exposures, 2)
+
+	_, err = GetExperimentVariant("signUpCopy", "user1", nil)
+	assert.NoError(t, err)
+	assert.Len(t, exposures, 3)
+
+	_, err = GetExperimentVariant("unknown", "user1")
+	assert.Error(t, err)
+}
+
+func TestExperimentExposureIsEmittedAsEvent(t *testing.T) {
+	experiments, err := normaliseExperimentsInput(&ExperimentsInput{
+		Experiments: []Experiment{{
+			ID:       "signUpCopy",
+			Variants: []ExperimentVariant{{Name: "a"}},
+		}},
+	})
+	assert.NoError(t, err)
+	events := []Event{}
+	superTokensInstance = &superTokens{
+		Experiments: experiments,
+		Events: normalisedEvents{
+			onEvent: func(event Event, userContext UserContext) {
+				events = append(events, event)
+			},
+		},
+	}
+	defer ResetForTest()
+
+	userContext := &map[string]interface{}{}
+	_, err = GetExperimentVariant("signUpCopy", "user1", userContext)
+	assert.NoError(t, err)
+	_, err = GetExperimentVariant("signUpCopy", "user1", userContext)
+	assert.NoError(t, err)
+
+	assert.Len(t, events, 1)
+	assert.Equal(t, EventExperimentExposure, events[0].Type)
+	assert.Equal(t, "user1", events[0].UserID)
+	assert.Equal(t, map[string]interface{}{
+		"experimentId": "signUpCopy",
+		"variant":      "a",
+	}, events[0].Data)
+}
+
+func TestInvalidExperimentsConfig(t *testing.T) {
+	_, err := normaliseExperimentsInput(&ExperimentsInput{
+		Experiments: []Experiment{{ID: "noVariants"}},
+	})
+	assert.Error(t, err)
+
+	_, err = normaliseExperimentsInput(&ExperimentsInput{
+		Experiments: []Experiment{
+			{ID: "duplicate", Variants: []ExperimentVariant{{Name: "a"}}},
+			{ID: "duplicate", Variants: []ExperimentVariant{{Name: "a"}}},
+		},
+	})
+	assert.Error(t, err)
+}
@@ -0,0 +1,121 @@
+/* Copyright (c) 2023, VRAI Labs and/or its affiliates. All rights reserved.
+ *
+ * This software is licensed under the Apache License, Version 2.0 (the
+ * "License") as published by the Apache Software Foundation.
+ *
+ * You may not use this file except in compliance with the License. You may
+ * obtain a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+ * License for the specific language governing permissions and limitations
+ * under the License.
+ */
+
+package supertokens
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// fipsApprovedJWTAlgorithms are the JWT algorithms whose primitives (RSA,
+// RSA-PSS and ECDSA over P-256/384/521 with SHA-2) are FIPS 186 approved.
+// Symmetric (HS*) algorithms are never used to verify tokens in this SDK.
+var fipsApprovedJWTAlgorithms = []string{"RS256", "RS384", "RS512", "PS256", "PS384", "PS512", "ES256", "ES384", "ES512"}
+
+// FIPSConfig restricts the SDK to FIPS approved primitives. FIPS mode is also
+// enabled for binaries built with the fips build tag. Randomness always comes
+// from crypto/rand. For a FIPS validated crypto module, also build with
+// GOEXPERIMENT=boringcrypto.
+type FIPSConfig struct {
+	Enabled bool
+	// JWTAlgorithms further restricts the algorithms accepted when verifying
+	// JWTs. Init fails if any of them is not FIPS approved.
+	JWTAlgorithms []string
+}
+
+type normalisedFIPSMode struct {
+	enabled       bool
+	jwtAlgorithms []string
+}
+
+// fipsMode is set before recipes are initialised so that they can validate
+// the algorithms they use
+var fipsMode = normalisedFIPSMode{
+	enabled: fipsModeFromBuildTag,
+}
+
+func normaliseFIPSInput(config *FIPSConfig) (normalisedFIPSMode, error) {
+	result := normalisedFIPSMode{
+		enabled:       fipsModeFromBuildTag,
+		jwtAlgorithms: fipsApprovedJWTAlgorithms,
+	}
+	if config == nil {
+		return result, nil
+	}
+	result.enabled = result.enabled || config.Enabled
+	if len(config.JWTAlgorithms) > 0 {
+		if !result.enabled {
+			return normalisedFIPSMode{}, fmt.Errorf("FIPS.JWTAlgorithms is set, but FIPS mode is not enabled")
+		}
+		for _, alg := range config.JWTAlgorithms {
+			if !isFIPSApprovedJWTAlgorithm(alg) {
+				return normalisedFIPSMode{}, fmt.Errorf("the JWT algorithm %s is not allowed in FIPS mode. Allowed algorithms are: %s", alg, strings.Join(fipsApprovedJWTAlgorithms, ", "))
+			}
+		}
+		result.jwtAlgorithms = config.JWTAlgorithms
+	}
+	return result, nil
+}
+
+func isFIPSApprovedJWTAlgorithm(alg string) bool {
+	for _, approved := range fipsApprovedJWTAlgorithms {
+		if alg == approved {
+			return true
+		}
+	}
+	return false
+}
+
+func IsFIPSModeEnabled() bool {
+	return fipsMode.enabled
+}
+
+// GetAllowedJWTAlgorithms returns the algorithms that JWTs can be verified
+// with, or nil if FIPS mode is not enabled (in which case the algorithm of
+// the key is used).
+func GetAllowedJWTAlgorithms() []string {
+	if !fipsMode.enabled {
+		return nil
+	}
+	return fipsMode.jwtAlgorithms
+}
+
+// GetJWTParserOptions restricts the algorithms that JWTs (access tokens, id
+// tokens, ...) can be signed with when FIPS mode is enabled
+func GetJWTParserOptions() []jwt.ParserOption {
+	allowedAlgorithms := GetAllowedJWTAlgorithms()
+	if allowedAlgorithms == nil {
+		return nil
+	}
+	return []jwt.ParserOption{jwt.WithValidMethods(allowedAlgorithms)}
+}
+
+// ValidateJWTAlgorithmForFIPSMode returns an error if FIPS mode is enabled
+// and alg is not allowed. Recipes call this during init for the algorithms
+// they sign with.
+func ValidateJWTAlgorithmForFIPSMode(alg string) error {
+	if !fipsMode.enabled {
+		return nil
+	}
+	for _, allowed := range fipsMode.jwtAlgorithms {
+		if alg == allowed {
+			return nil
+		}
+	}
+	return fmt.Errorf("the JWT algorithm %s is not allowed in FIPS mode", alg)
+}
@@ -0,0 +1,63 @@
+/* Copyright (c) 2023, VRAI Labs and/or its affiliates. All rights reserved.
+ *
+ * This software is licensed under the Apache License, Version 2.0 (the
+ * "License") as published by the Apache Software Foundation.
+ *
+ * You may not use this file except in compliance with the License. You may
+ * obtain a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+ * License for the specific language governing permissions and limitations
+ * under the License.
+ */
+
+package supertokens
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFIPSModeIsOffByDefault(t *testing.T) {
+	defer ResetForTest()
+	ResetForTest()
+
+	assert.Equal(t, fipsModeFromBuildTag, IsFIPSModeEnabled())
+	if !fipsModeFromBuildTag {
+		assert.Nil(t, GetAllowedJWTAlgorithms())
+		assert.Nil(t, GetJWTParserOptions())
+		assert.NoError(t, ValidateJWTAlgorithmForFIPSMode("EdDSA"))
+	}
+}
+
+func TestFIPSModeRestrictsJWTAlgorithms(t *testing.T) {
+	defer ResetForTest()
+
+	var err error
+	fipsMode, err = normaliseFIPSInput(&FIPSConfig{Enabled: true})
+	assert.NoError(t, err)
+	assert.True(t, IsFIPSModeEnabled())
+	assert.Contains(t, GetAllowedJWTAlgorithms(), "RS256")
+	assert.Len(t, GetJWTParserOptions(), 1)
+	assert.NoError(t, ValidateJWTAlgorithmForFIPSMode("ES256"))
+	assert.Error(t, ValidateJWTAlgorithmForFIPSMode("EdDSA"))
+	assert.Error(t, ValidateJWTAlgorithmForFIPSMode("HS256"))
+
+	fipsMode, err = normaliseFIPSInput(&FIPSConfig{Enabled: true, JWTAlgorithms: []string{"PS256"}})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"PS256"}, GetAllowedJWTAlgorithms())
+	assert.Error(t, ValidateJWTAlgorithmForFIPSMode("RS256"))
+}
+
+func TestThatNonApprovedFIPSAlgorithmFailsInit(t *testing.T) {
+	_, err := normaliseFIPSInput(&FIPSConfig{Enabled: true, JWTAlgorithms: []string{"RS256", "EdDSA"}})
+	assert.EqualError(t, err, "the JWT algorithm EdDSA is not allowed in FIPS mode. Allowed algorithms are: RS256, RS384, RS512, PS256, PS384, PS512, ES256, ES384, ES512")
+
+	if !fipsModeFromBuildTag {
+		_, err = normaliseFIPSInput(&FIPSConfig{JWTAlgorithms: []string{"RS256"}})
+		assert.Error(t, err)
+	}
+}
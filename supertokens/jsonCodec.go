@@ -0,0 +1,59 @@
+/* Copyright (c) 2023, VRAI Labs and/or its affiliates. All rights reserved.
+ *
+ * This software is licensed under the Apache License, Version 2.0 (the
+ * "License") as published by the Apache Software Foundation.
+ *
+ * You may not use this file except in compliance with the License. You may
+ * obtain a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+ * License for the specific language governing permissions and limitations
+ * under the License.
+ */
+
+package supertokens
+
+import "encoding/json"
+
+// JSONCodec encodes and decodes the JSON exchanged with the core and the
+// payloads of access tokens. Faster libraries that are compatible with
+// encoding/json can be used, e.g. sonic.ConfigStd or
+// jsoniter.ConfigCompatibleWithStandardLibrary.
+type JSONCodec interface {
+	Marshal(v interface{}) ([]byte, error)
+	Unmarshal(data []byte, v interface{}) error
+}
+
+type stdJSONCodec struct{}
+
+func (stdJSONCodec) Marshal(v interface{}) ([]byte, error) {
+	return json.Marshal(v)
+}
+
+func (stdJSONCodec) Unmarshal(data []byte, v interface{}) error {
+	return json.Unmarshal(data, v)
+}
+
+// jsonCodec is set before recipes are initialised and stays the same for the
+// lifetime of the process
+var jsonCodec JSONCodec = stdJSONCodec{}
+
+func normaliseJSONCodecInput(codec JSONCodec) JSONCodec {
+	if codec == nil {
+		return stdJSONCodec{}
+	}
+	return codec
+}
+
+// JSONMarshal encodes v with the codec configured in TypeInput.JSONCodec
+func JSONMarshal(v interface{}) ([]byte, error) {
+	return jsonCodec.Marshal(v)
+}
+
+// JSONUnmarshal decodes data into v with the codec configured in
+// TypeInput.JSONCodec
+func JSONUnmarshal(data []byte, v interface{}) error {
+	return jsonCodec.Unmarshal(data, v)
+}
@@ -0,0 +1,81 @@
+/* Copyright (c) 2023, VRAI Labs and/or its affiliates. All rights reserved.
+ *
+ * This software is licensed under the Apache License, Version 2.0 (the
+ * "License") as published by the Apache Software Foundation.
+ *
+ * You may not use this file except in compliance with the License. You may
+ * obtain a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+ * License for the specific language governing permissions and limitations
+ * under the License.
+ */
+
+package supertokens
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type countingJSONCodec struct {
+	marshalCalls   int
+	unmarshalCalls int
+}
+
+func (c *countingJSONCodec) Marshal(v interface{}) ([]byte, error) {
+	c.marshalCalls++
+	return json.Marshal(v)
+}
+
+func (c *countingJSONCodec) Unmarshal(data []byte, v interface{}) error {
+	c.unmarshalCalls++
+	return json.Unmarshal(data, v)
+}
+
+func TestThatQuerierUsesTheConfiguredJSONCodec(t *testing.T) {
+	coreServer := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/apiversion" {
+			json.NewEncoder(rw).Encode(map[string]interface{}{"versions": cdiSupported})
+			return
+		}
+		json.NewEncoder(rw).Encode(map[string]interface{}{"status": "OK"})
+	}))
+	defer coreServer.Close()
+
+	defer ResetForTest()
+	ResetForTest()
+	codec := &countingJSONCodec{}
+	err := Init(TypeInput{
+		Supertokens: &ConnectionInfo{
+			ConnectionURI: coreServer.URL,
+		},
+		AppInfo: AppInfo{
+			AppName:       "SuperTokens",
+			APIDomain:     "api.supertokens.io",
+			WebsiteDomain: "supertokens.io",
+		},
+		RecipeList: []Recipe{MakeCustomRecipe(CustomRecipeConfig{RecipeID: "custom"})},
+		JSONCodec:  codec,
+	})
+	assert.NoError(t, err)
+
+	querier, err := GetNewQuerierInstanceOrThrowError("")
+	assert.NoError(t, err)
+	response, err := querier.SendPostRequest("/recipe/test", map[string]interface{}{"key": "value"}, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, "OK", response["status"])
+
+	// the request body, plus the response of /apiversion and /recipe/test
+	assert.Equal(t, 1, codec.marshalCalls)
+	assert.Equal(t, 2, codec.unmarshalCalls)
+
+	ResetForTest()
+	assert.Equal(t, stdJSONCodec{}, jsonCodec)
+}
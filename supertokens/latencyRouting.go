@@ -0,0 +1,200 @@
+/* Copyright (c) 2023, VRAI Labs and/or its affiliates. All rights reserved.
+ *
+ * This software is licensed under the Apache License, Version 2.0 (the
+ * "License") as published by the Apache Software Foundation.
+ *
+ * You may not use this file except in compliance with the License. You may
+ * obtain a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+ * License for the specific language governing permissions and limitations
+ * under the License.
+ */
+
+package supertokens
+
+import (
+	"context"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+)
+
+// LatencyRoutingConfig makes the querier send requests to the healthy core
+// with the lowest latency instead of going round robin over the hosts in
+// ConnectionURI. Hosts are probed periodically by a background worker once
+// supertokens.Start is called. Without it, requests to the core start a probe
+// in a goroutine at most once every ProbeInterval. Requests go round robin
+// until the first probe completes.
+type LatencyRoutingConfig struct {
+	// ProbeInterval defaults to 30 seconds
+	ProbeInterval time.Duration
+	// ProbeTimeout defaults to 2 seconds. Hosts that do not respond in time
+	// are treated as unhealthy until the next probe.
+	ProbeTimeout time.Duration
+}
+
+const defaultLatencyProbeInterval = 30 * time.Second
+const defaultLatencyProbeTimeout = 2 * time.Second
+
+type latencyRouter struct {
+	hosts         []QuerierHost
+	probeInterval time.Duration
+	probeTimeout  time.Duration
+	lock          sync.Mutex
+	// latencies is nil until the first round of probes is done
+	latencies []time.Duration
+	healthy   []bool
+	ranking   []int
+	// rankingStale is set when a host is marked unhealthy between probes
+	rankingStale bool
+	lastProbeAt  time.Time
+	probing      bool
+}
+
+var querierLatencyRouter *latencyRouter
+
+func makeLatencyRouter(hosts []QuerierHost, config *LatencyRoutingConfig) *latencyRouter {
+	if config == nil {
+		return nil
+	}
+	router := &latencyRouter{
+		hosts:         hosts,
+		probeInterval: config.ProbeInterval,
+		probeTimeout:  config.ProbeTimeout,
+	}
+	if router.probeInterval <= 0 {
+		router.probeInterval = defaultLatencyProbeInterval
+	}
+	if router.probeTimeout <= 0 {
+		router.probeTimeout = defaultLatencyProbeTimeout
+	}
+	return router
+}
+
+func (r *latencyRouter) getBackgroundWorker() BackgroundWorker {
+	return BackgroundWorker{
+		Name:     latencyProbeWorkerName,
+		Interval: r.probeInterval,
+		Run: func(ctx context.Context) error {
+			r.probeAll(ctx)
+			return nil
+		},
+	}
+}
+
+const latencyProbeWorkerName = "core-latency-probe"
+
+func (r *latencyRouter) probeAll(ctx context.Context) {
+	r.lock.Lock()
+	r.lastProbeAt = time.Now()
+	r.lock.Unlock()
+
+	latencies := make([]time.Duration, len(r.hosts))
+	healthy := make([]bool, len(r.hosts))
+	var wg sync.WaitGroup
+	for idx := range r.hosts {
+		wg.Add(1)
+		go func(idx int) {
+			defer wg.Done()
+			latencies[idx], healthy[idx] = r.probe(ctx, r.hosts[idx])
+		}(idx)
+	}
+	wg.Wait()
+	r.setProbeResults(latencies, healthy)
+}
+
+func (r *latencyRouter) probe(ctx context.Context, host QuerierHost) (time.Duration, bool) {
+	ctx, cancel := context.WithTimeout(ctx, r.probeTimeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, host.Domain.GetAsStringDangerous()+host.BasePath.GetAsStringDangerous()+"/hello", nil)
+	if err != nil {
+		return 0, false
+	}
+	start := time.Now()
+	resp, err := querierHTTPClient.Do(req)
+	if err != nil {
+		return 0, false
+	}
+	resp.Body.Close()
+	return time.Since(start), resp.StatusCode == http.StatusOK
+}
+
+func (r *latencyRouter) setProbeResults(latencies []time.Duration, healthy []bool) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	r.latencies = latencies
+	r.healthy = healthy
+	r.updateRanking()
+	r.rankingStale = false
+}
+
+// updateRanking orders healthy hosts by latency, followed by the unhealthy
+// ones in the order in which they were configured
+func (r *latencyRouter) updateRanking() {
+	ranking := make([]int, len(r.hosts))
+	for idx := range ranking {
+		ranking[idx] = idx
+	}
+	sort.SliceStable(ranking, func(i, j int) bool {
+		a, b := ranking[i], ranking[j]
+		if r.healthy[a] != r.healthy[b] {
+			return r.healthy[a]
+		}
+		return r.healthy[a] && r.latencies[a] < r.latencies[b]
+	})
+	r.ranking = ranking
+}
+
+// getHostIndex returns the host to use for the given attempt of a request,
+// or -1 if no probe has completed yet. The ranking is only updated at the
+// start of a request so that retries go through every host once.
+func (r *latencyRouter) getHostIndex(attempt int) int {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	if attempt == 0 && !areBackgroundWorkersRunning() {
+		r.startProbeIfDue()
+	}
+	if r.ranking == nil {
+		return -1
+	}
+	if attempt == 0 && r.rankingStale {
+		r.updateRanking()
+		r.rankingStale = false
+	}
+	return r.ranking[attempt%len(r.ranking)]
+}
+
+// startProbeIfDue probes the hosts in a goroutine if the background worker is
+// not running and the last probe is older than the probe interval. It must be
+// called with the lock held.
+func (r *latencyRouter) startProbeIfDue() {
+	if r.probing || time.Since(r.lastProbeAt) < r.probeInterval {
+		return
+	}
+	r.probing = true
+	r.lastProbeAt = time.Now()
+	go func() {
+		defer func() {
+			r.lock.Lock()
+			r.probing = false
+			r.lock.Unlock()
+		}()
+		r.probeAll(context.Background())
+	}()
+}
+
+// markUnhealthy is called when a request to the host fails to connect, so
+// that other hosts are preferred until the host is probed again
+func (r *latencyRouter) markUnhealthy(hostIndex int) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	if r.ranking == nil || !r.healthy[hostIndex] {
+		return
+	}
+	r.healthy[hostIndex] = false
+	r.rankingStale = true
+}
@@ -0,0 +1,142 @@
+/* Copyright (c) 2023, VRAI Labs and/or its affiliates. All rights reserved.
+ *
+ * This software is licensed under the Apache License, Version 2.0 (the
+ * "License") as published by the Apache Software Foundation.
+ *
+ * You may not use this file except in compliance with the License. You may
+ * obtain a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+ * License for the specific language governing permissions and limitations
+ * under the License.
+ */
+
+package supertokens
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func makeLatencyTestCore(t *testing.T, helloDelay time.Duration, requests *int32) (*httptest.Server, QuerierHost) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/hello", func(rw http.ResponseWriter, r *http.Request) {
+		time.Sleep(helloDelay)
+		rw.Write([]byte("Hello"))
+	})
+	mux.HandleFunc("/apiversion", func(rw http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(rw).Encode(map[string]interface{}{"versions": cdiSupported})
+	})
+	mux.HandleFunc("/recipe/test", func(rw http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(requests, 1)
+		json.NewEncoder(rw).Encode(map[string]interface{}{"status": "OK"})
+	})
+	server := httptest.NewServer(mux)
+
+	domain, err := NewNormalisedURLDomain(server.URL)
+	assert.NoError(t, err)
+	basePath, err := NewNormalisedURLPath(server.URL)
+	assert.NoError(t, err)
+	return server, QuerierHost{Domain: domain, BasePath: basePath}
+}
+
+func TestThatLatencyRoutingPrefersFastestHealthyCore(t *testing.T) {
+	var slowRequests, fastRequests int32
+	slowServer, slowHost := makeLatencyTestCore(t, 100*time.Millisecond, &slowRequests)
+	defer slowServer.Close()
+	fastServer, fastHost := makeLatencyTestCore(t, 0, &fastRequests)
+
+	ResetQuerierForTest()
+	defer ResetQuerierForTest()
+	defer resetBackgroundWorkersForTest()
+	initQuerier([]QuerierHost{slowHost, fastHost}, "", nil, nil, 0, nil, &LatencyRoutingConfig{
+		ProbeInterval: time.Hour,
+	})
+	assert.Equal(t, -1, querierLatencyRouter.getHostIndex(0))
+	assert.NoError(t, Start(context.Background()))
+	assert.Eventually(t, func() bool {
+		return querierLatencyRouter.getHostIndex(0) != -1
+	}, time.Second, 10*time.Millisecond)
+
+	querier, err := GetNewQuerierInstanceOrThrowError("")
+	assert.NoError(t, err)
+	for i := 0; i < 4; i++ {
+		_, err = querier.SendGetRequest("/recipe/test", nil, nil)
+		assert.NoError(t, err)
+	}
+	assert.Equal(t, int32(4), atomic.LoadInt32(&fastRequests))
+	assert.Equal(t, int32(0), atomic.LoadInt32(&slowRequests))
+
+	// once the fastest core goes down, requests fail over to the other one
+	fastServer.Close()
+	_, err = querier.SendGetRequest("/recipe/test", nil, nil)
+	assert.NoError(t, err)
+	_, err = querier.SendGetRequest("/recipe/test", nil, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, int32(2), atomic.LoadInt32(&slowRequests))
+	assert.Equal(t, 0, querierLatencyRouter.getHostIndex(0))
+}
+
+func TestLatencyRankingPutsUnhealthyHostsLast(t *testing.T) {
+	router := makeLatencyRouter(make([]QuerierHost, 3), &LatencyRoutingConfig{})
+	assert.Equal(t, defaultLatencyProbeInterval, router.probeInterval)
+	// prevents getHostIndex from probing the hosts
+	router.lastProbeAt = time.Now()
+	assert.Equal(t, -1, router.getHostIndex(0))
+
+	router.setProbeResults([]time.Duration{30 * time.Millisecond, 0, 10 * time.Millisecond}, []bool{true, false, true})
+	assert.Equal(t, []int{2, 0, 1}, router.ranking)
+	assert.Equal(t, 0, router.getHostIndex(1))
+	assert.Equal(t, 2, router.getHostIndex(3))
+}
+
+func TestThatLatencyRoutingProbesWithoutStart(t *testing.T) {
+	var slowRequests, fastRequests int32
+	slowServer, slowHost := makeLatencyTestCore(t, 100*time.Millisecond, &slowRequests)
+	defer slowServer.Close()
+	fastServer, fastHost := makeLatencyTestCore(t, 0, &fastRequests)
+	defer fastServer.Close()
+
+	ResetQuerierForTest()
+	defer ResetQuerierForTest()
+	defer resetBackgroundWorkersForTest()
+	initQuerier([]QuerierHost{slowHost, fastHost}, "", nil, nil, 0, nil, &LatencyRoutingConfig{
+		ProbeInterval: time.Hour,
+	})
+
+	// the first request starts a probe without waiting for it
+	assert.Equal(t, -1, querierLatencyRouter.getHostIndex(0))
+	assert.Eventually(t, func() bool {
+		return querierLatencyRouter.getHostIndex(0) == 1
+	}, time.Second, 10*time.Millisecond)
+}
+
+func TestThatResettingTheQuerierReplacesTheLatencyProbe(t *testing.T) {
+	ResetQuerierForTest()
+	defer ResetQuerierForTest()
+	defer resetBackgroundWorkersForTest()
+	hosts := make([]QuerierHost, 1)
+	initQuerier(hosts, "", nil, nil, 0, nil, &LatencyRoutingConfig{})
+	ResetQuerierForTest()
+	initQuerier(hosts, "", nil, nil, 0, nil, &LatencyRoutingConfig{})
+
+	probes := 0
+	for _, health := range GetBackgroundWorkersHealth() {
+		if health.Name == latencyProbeWorkerName {
+			probes++
+		}
+	}
+	assert.Equal(t, 1, probes)
+
+	ResetQuerierForTest()
+	assert.Empty(t, GetBackgroundWorkersHealth())
+}
@@ -0,0 +1,209 @@
+/* Copyright (c) 2023, VRAI Labs and/or its affiliates. All rights reserved.
+ *
+ * This software is licensed under the Apache License, Version 2.0 (the
+ * "License") as published by the Apache Software Foundation.
+ *
+ * You may not use this file except in compliance with the License. You may
+ * obtain a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+ * License for the specific language governing permissions and limitations
+ * under the License.
+ */
+
+package supertokens
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// MessageKey identifies a message that is shown to end users, either in an
+// API response or in an email / SMS sent by the SDK
+type MessageKey string
+
+const (
+	MessageFieldNotOptional            MessageKey = "FIELD_NOT_OPTIONAL"
+	MessageEmailInvalid                MessageKey = "EMAIL_INVALID"
+	MessagePhoneNumberInvalid          MessageKey = "PHONE_NUMBER_INVALID"
+	MessagePasswordTooShort            MessageKey = "PASSWORD_TOO_SHORT"
+	MessagePasswordTooLong             MessageKey = "PASSWORD_TOO_LONG"
+	MessagePasswordWithoutAlphabet     MessageKey = "PASSWORD_WITHOUT_ALPHABET"
+	MessagePasswordWithoutNumber       MessageKey = "PASSWORD_WITHOUT_NUMBER"
+	MessagePasswordMinLength           MessageKey = "PASSWORD_MIN_LENGTH"
+	MessagePasswordMaxLength           MessageKey = "PASSWORD_MAX_LENGTH"
+	MessagePasswordWithoutLowercase    MessageKey = "PASSWORD_WITHOUT_LOWERCASE"
+	MessagePasswordWithoutUppercase    MessageKey = "PASSWORD_WITHOUT_UPPERCASE"
+	MessagePasswordWithoutSymbol       MessageKey = "PASSWORD_WITHOUT_SYMBOL"
+	MessagePasswordDenied              MessageKey = "PASSWORD_DENIED"
+	MessagePasswordBreached            MessageKey = "PASSWORD_BREACHED"
+	MessageEmailAlreadyExists          MessageKey = "EMAIL_ALREADY_EXISTS"
+	MessageOTPGenerationFailed         MessageKey = "OTP_GENERATION_FAILED"
+	MessagePasswordResetEmailSubject   MessageKey = "PASSWORD_RESET_EMAIL_SUBJECT"
+	MessageEmailVerifyEmailSubject     MessageKey = "EMAIL_VERIFY_EMAIL_SUBJECT"
+	MessagePasswordlessEmailSubject    MessageKey = "PASSWORDLESS_EMAIL_SUBJECT"
+	MessagePasswordlessSMSMagicLink    MessageKey = "PASSWORDLESS_SMS_MAGIC_LINK"
+	MessagePasswordlessSMSOTP          MessageKey = "PASSWORDLESS_SMS_OTP"
+	MessagePasswordlessSMSMagicLinkOTP MessageKey = "PASSWORDLESS_SMS_MAGIC_LINK_OTP"
+)
+
+// DefaultMessages are the English messages used when no translation is found.
+// The SMS and password length messages contain placeholders (e.g. ${otp})
+// that are replaced after translation, so translations should keep them.
+var DefaultMessages = map[MessageKey]string{
+	MessageFieldNotOptional:          "Field is not optional",
+	MessageEmailInvalid:              "Email is invalid",
+	MessagePhoneNumberInvalid:        "Phone number is invalid",
+	MessagePasswordTooShort:          "Password must contain at least 8 characters, including a number",
+	MessagePasswordTooLong:           "Password's length must be lesser than 100 characters",
+	MessagePasswordWithoutAlphabet:   "Password must contain at least one alphabet",
+	MessagePasswordWithoutNumber:     "Password must contain at least one number",
+	MessagePasswordMinLength:         "Password must contain at least ${minLength} characters",
+	MessagePasswordMaxLength:         "Password's length must be lesser than ${maxLength} characters",
+	MessagePasswordWithoutLowercase:  "Password must contain at least one lowercase letter",
+	MessagePasswordWithoutUppercase:  "Password must contain at least one uppercase letter",
+	MessagePasswordWithoutSymbol:     "Password must contain at least one symbol",
+	MessagePasswordDenied:            "This password is too common. Please choose another one",
+	MessagePasswordBreached:          "This password has appeared in a data breach. Please choose another one",
+	MessageEmailAlreadyExists:        "This email already exists. Please sign in instead.",
+	MessageOTPGenerationFailed:       "Failed to generate a one time code. Please try again",
+	MessagePasswordResetEmailSubject: "Password reset instructions",
+	MessageEmailVerifyEmailSubject:   "Email verification instructions",
+	MessagePasswordlessEmailSubject:  "Login to your account",
+	MessagePasswordlessSMSMagicLink: `Click ${magicLink} to login to ${appname}
+
+This is valid for ${time}.`,
+	MessagePasswordlessSMSOTP: `OTP to login is ${otp} for ${appname}
+
+This is valid for ${time}.`,
+	MessagePasswordlessSMSMagicLinkOTP: `OTP to login is ${otp} for ${appname}
+
+Or click ${magicLink} to login.
+
+This is valid for ${time}.`,
+}
+
+type LocalizationInput struct {
+	// Translations maps locales (e.g. "fr" or "pt-BR") to translated
+	// messages. Messages missing for the locale of a request are looked up
+	// for its base language ("pt"), then DefaultLocale, then in English.
+	Translations map[string]map[MessageKey]string
+	// DefaultLocale is used for requests without a locale. Defaults to "en".
+	DefaultLocale string
+	// GetLocale overrides how the locale of a request is found. By default,
+	// the first language of its Accept-Language header is used.
+	GetLocale func(req *http.Request, userContext UserContext) string
+}
+
+type normalisedLocalization struct {
+	translations  map[string]map[MessageKey]string
+	defaultLocale string
+	getLocale     func(req *http.Request, userContext UserContext) string
+}
+
+func normaliseLocalizationInput(config *LocalizationInput) normalisedLocalization {
+	result := normalisedLocalization{
+		translations:  map[string]map[MessageKey]string{},
+		defaultLocale: "en",
+		getLocale: func(req *http.Request, userContext UserContext) string {
+			return getLocaleFromAcceptLanguage(req.Header.Get("Accept-Language"))
+		},
+	}
+	if config == nil {
+		return result
+	}
+	for locale, messages := range config.Translations {
+		result.translations[strings.ToLower(locale)] = messages
+	}
+	if config.DefaultLocale != "" {
+		result.defaultLocale = strings.ToLower(config.DefaultLocale)
+	}
+	if config.GetLocale != nil {
+		result.getLocale = config.GetLocale
+	}
+	return result
+}
+
+// getLocaleFromAcceptLanguage returns the language with the highest quality
+// in an Accept-Language header, or "" if there is none
+func getLocaleFromAcceptLanguage(header string) string {
+	bestLocale := ""
+	bestQuality := 0.0
+	for _, part := range strings.Split(header, ",") {
+		fields := strings.Split(strings.TrimSpace(part), ";")
+		locale := strings.TrimSpace(fields[0])
+		if locale == "" || locale == "*" {
+			continue
+		}
+		quality := 1.0
+		for _, param := range fields[1:] {
+			param = strings.TrimSpace(param)
+			if strings.HasPrefix(param, "q=") {
+				parsed, err := strconv.ParseFloat(strings.TrimPrefix(param, "q="), 64)
+				if err == nil {
+					quality = parsed
+				}
+			}
+		}
+		if quality > bestQuality {
+			bestLocale, bestQuality = locale, quality
+		}
+	}
+	return bestLocale
+}
+
+// GetLocale returns the locale of the request in the user context, or the
+// default locale if there is no request or it has no locale
+func GetLocale(userContext UserContext) string {
+	if superTokensInstance == nil {
+		return "en"
+	}
+	localization := superTokensInstance.Localization
+	req := getRequestFromUserContext(userContext)
+	if req != nil {
+		locale := localization.getLocale(req, userContext)
+		if locale != "" {
+			return strings.ToLower(locale)
+		}
+	}
+	return localization.defaultLocale
+}
+
+// Translate returns the message for a key in the locale of the request in
+// the user context. Keys without a translation or a default message are
+// returned as is.
+func Translate(key MessageKey, userContext UserContext) string {
+	if superTokensInstance != nil {
+		localization := superTokensInstance.Localization
+		locale := GetLocale(userContext)
+		candidates := []string{locale}
+		if idx := strings.IndexAny(locale, "-_"); idx > 0 {
+			candidates = append(candidates, locale[:idx])
+		}
+		candidates = append(candidates, localization.defaultLocale)
+		for _, candidate := range candidates {
+			if message, ok := localization.translations[candidate][key]; ok {
+				return message
+			}
+		}
+	}
+	if message, ok := DefaultMessages[key]; ok {
+		return message
+	}
+	return string(key)
+}
+
+// TranslateMessage translates a message returned by a validator. Default
+// messages of the SDK are translated using their key, and other messages
+// can be translated by adding them to Translations with the message as key.
+func TranslateMessage(message string, userContext UserContext) string {
+	for key, defaultMessage := range DefaultMessages {
+		if defaultMessage == message {
+			return Translate(key, userContext)
+		}
+	}
+	return Translate(MessageKey(message), userContext)
+}
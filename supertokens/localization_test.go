@@ -0,0 +1,122 @@
+/* Copyright (c) 2023, VRAI Labs and/or its affiliates. All rights reserved.
+ *
+ * This software is licensed under the Apache License, Version 2.0 (the
+ * "License") as published by the Apache Software Foundation.
+ *
+ * You may not use this file except in compliance with the License. You may
+ * obtain a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+ * License for the specific language governing permissions and limitations
+ * under the License.
+ */
+
+package supertokens
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func initWithLocalization(t *testing.T, localization *LocalizationInput) {
+	ResetForTest()
+	postInitCallbacks = []func() error{}
+	err := Init(TypeInput{
+		AppInfo: AppInfo{
+			AppName:       "SuperTokens",
+			APIDomain:     "api.supertokens.io",
+			WebsiteDomain: "supertokens.io",
+		},
+		RecipeList:   []Recipe{makeTestRecipe("session", func() {})},
+		Localization: localization,
+	})
+	assert.NoError(t, err)
+}
+
+func userContextWithAcceptLanguage(acceptLanguage string) UserContext {
+	req := httptest.NewRequest(http.MethodPost, "/auth/signup", nil)
+	if acceptLanguage != "" {
+		req.Header.Set("Accept-Language", acceptLanguage)
+	}
+	return MakeDefaultUserContextFromAPI(req)
+}
+
+func TestGetLocaleFromAcceptLanguage(t *testing.T) {
+	assert.Equal(t, "fr-CA", getLocaleFromAcceptLanguage("fr-CA,fr;q=0.9,en;q=0.8"))
+	assert.Equal(t, "de", getLocaleFromAcceptLanguage("en;q=0.5, de"))
+	assert.Equal(t, "en", getLocaleFromAcceptLanguage("*, en;q=0.1"))
+	assert.Equal(t, "", getLocaleFromAcceptLanguage(""))
+}
+
+func TestTranslateFallsBackToBaseLanguageAndEnglish(t *testing.T) {
+	defer ResetForTest()
+	initWithLocalization(t, &LocalizationInput{
+		Translations: map[string]map[MessageKey]string{
+			"fr": {
+				MessageEmailInvalid:     "L'adresse e-mail n'est pas valide",
+				MessageFieldNotOptional: "Ce champ est obligatoire",
+			},
+			"fr-CA": {
+				MessageEmailInvalid: "Le courriel n'est pas valide",
+			},
+		},
+	})
+
+	canadian := userContextWithAcceptLanguage("fr-CA,fr;q=0.9")
+	assert.Equal(t, "Le courriel n'est pas valide", Translate(MessageEmailInvalid, canadian))
+	assert.Equal(t, "Ce champ est obligatoire", Translate(MessageFieldNotOptional, canadian))
+	assert.Equal(t, DefaultMessages[MessagePhoneNumberInvalid], Translate(MessagePhoneNumberInvalid, canadian))
+
+	english := userContextWithAcceptLanguage("en-GB")
+	assert.Equal(t, "Email is invalid", Translate(MessageEmailInvalid, english))
+	assert.Equal(t, "Email is invalid", Translate(MessageEmailInvalid, &map[string]interface{}{}))
+	assert.Equal(t, "UNKNOWN_KEY", Translate("UNKNOWN_KEY", english))
+}
+
+func TestDefaultLocaleAndGetLocale(t *testing.T) {
+	defer ResetForTest()
+	initWithLocalization(t, &LocalizationInput{
+		Translations: map[string]map[MessageKey]string{
+			"de": {MessageEmailInvalid: "Die E-Mail-Adresse ist ungültig"},
+			"es": {MessageEmailInvalid: "El correo electrónico no es válido"},
+		},
+		DefaultLocale: "de",
+	})
+	assert.Equal(t, "Die E-Mail-Adresse ist ungültig", Translate(MessageEmailInvalid, userContextWithAcceptLanguage("")))
+	assert.Equal(t, "Die E-Mail-Adresse ist ungültig", Translate(MessageEmailInvalid, userContextWithAcceptLanguage("it")))
+
+	initWithLocalization(t, &LocalizationInput{
+		Translations: map[string]map[MessageKey]string{
+			"es": {MessageEmailInvalid: "El correo electrónico no es válido"},
+		},
+		GetLocale: func(req *http.Request, userContext UserContext) string {
+			return req.URL.Query().Get("lang")
+		},
+	})
+	req := httptest.NewRequest(http.MethodPost, "/auth/signup?lang=es", nil)
+	req.Header.Set("Accept-Language", "de")
+	userContext := MakeDefaultUserContextFromAPI(req)
+	assert.Equal(t, "es", GetLocale(userContext))
+	assert.Equal(t, "El correo electrónico no es válido", Translate(MessageEmailInvalid, userContext))
+}
+
+func TestTranslateMessage(t *testing.T) {
+	defer ResetForTest()
+	initWithLocalization(t, &LocalizationInput{
+		Translations: map[string]map[MessageKey]string{
+			"fr": {
+				MessagePasswordWithoutNumber: "Le mot de passe doit contenir au moins un chiffre",
+				"Username is already taken":  "Ce nom d'utilisateur est déjà pris",
+			},
+		},
+	})
+	userContext := userContextWithAcceptLanguage("fr")
+	assert.Equal(t, "Le mot de passe doit contenir au moins un chiffre", TranslateMessage("Password must contain at least one number", userContext))
+	assert.Equal(t, "Ce nom d'utilisateur est déjà pris", TranslateMessage("Username is already taken", userContext))
+	assert.Equal(t, "Some other error", TranslateMessage("Some other error", userContext))
+}
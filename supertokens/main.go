@@ -86,3 +86,13 @@ func DeleteUser(userId string) error {
 func GetRequestFromUserContext(userContext UserContext) *http.Request {
 	return getRequestFromUserContext(userContext)
 }
+
+// SetIsSignUpInUserContext is called by the sign up APIs before creating a
+// session, so that session claims can tell sign ups from sign ins.
+func SetIsSignUpInUserContext(userContext UserContext) {
+	setIsSignUpInUserContext(userContext)
+}
+
+func IsSignUpFromUserContext(userContext UserContext) bool {
+	return isSignUpFromUserContext(userContext)
+}
@@ -17,11 +17,15 @@ package supertokens
 
 import (
 	"net/http"
+	"time"
 )
 
 type NormalisedAppinfo struct {
-	AppName                  string
-	GetOrigin                func(request *http.Request, userContext UserContext) (NormalisedURLDomain, error)
+	AppName   string
+	GetOrigin func(request *http.Request, userContext UserContext) (NormalisedURLDomain, error)
+	// HasStaticOrigin is true if the origin does not depend on the request,
+	// i.e. if GetOrigin was not set in the AppInfo
+	HasStaticOrigin          bool
 	APIDomain                NormalisedURLDomain
 	TopLevelAPIDomain        string
 	GetTopLevelWebsiteDomain func(request *http.Request, userContext UserContext) (string, error)
@@ -50,12 +54,64 @@ type TypeInput struct {
 	Telemetry             *bool
 	Debug                 bool
 	OnSuperTokensAPIError func(err error, req *http.Request, res http.ResponseWriter)
+	RateLimiter           *RateLimiterInput
+	// TrustedProxies lists the IPs or CIDRs of the load balancers / proxies in
+	// front of the API. X-Forwarded-For and X-Real-IP are only used to find the
+	// client IP when the request comes from one of these.
+	TrustedProxies []string
+	// GetClientIP overrides how the client IP is derived from a request
+	GetClientIP func(req *http.Request, userContext UserContext) string
+	ShadowMode  *ShadowModeInput
+	Experiments *ExperimentsInput
+	// RequestTimeout limits how long each request to the core can take. Core
+	// requests made while handling an API call also stop when that request's
+	// context is done. Defaults to no timeout.
+	RequestTimeout time.Duration
+	// EgressProxy routes requests to the core and telemetry through a proxy
+	EgressProxy *EgressProxyConfig
+	// ErrorResponseSerializer changes the format of error responses, e.g. to
+	// supertokens.ProblemJSONErrorResponseSerializer. Defaults to JSON.
+	ErrorResponseSerializer ErrorResponseSerializer
+	FIPS                    *FIPSConfig
+	// Events sends sign ups, sign ins, sign outs, session refreshes, password
+	// resets and user deletions to a callback and / or a webhook
+	Events *EventsInput
+	// AuditLogger receives an entry for each request handled by the SDK and
+	// for sign ups, sign ins, sign outs, session refreshes, password resets
+	// and user deletions, whether they succeed or fail
+	AuditLogger AuditLogger
+	// OnUnexpectedError is called with the request and a stack trace for
+	// errors that no recipe handled (before OnSuperTokensAPIError) and for
+	// panics while handling SuperTokens APIs, which are answered with a 500
+	OnUnexpectedError func(report UnexpectedError, userContext UserContext)
+	// Localization translates the messages that recipe APIs return to end
+	// users and the emails / SMS sent by the SDK, see supertokens.Translate
+	Localization *LocalizationInput
+	// JSONCodec replaces encoding/json for requests to the core and for
+	// parsing access tokens. Defaults to encoding/json.
+	JSONCodec JSONCodec
 }
 
 type ConnectionInfo struct {
 	ConnectionURI      string
 	APIKey             string
 	NetworkInterceptor func(*http.Request, UserContext) *http.Request
+	Transport          *TransportConfig
+	// LatencyRouting is useful when ConnectionURI lists cores in multiple
+	// regions. By default, requests go round robin over the hosts.
+	LatencyRouting *LatencyRoutingConfig
+}
+
+// TransportConfig tunes the HTTP transport used to query the core. Fields
+// left nil keep the defaults of http.DefaultTransport.
+type TransportConfig struct {
+	MaxIdleConns        *int
+	MaxIdleConnsPerHost *int
+	MaxConnsPerHost     *int
+	IdleConnTimeout     *time.Duration
+	TLSHandshakeTimeout *time.Duration
+	DisableKeepAlives   bool
+	ForceAttemptHTTP2   *bool
 }
 
 type APIHandled struct {
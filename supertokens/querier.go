@@ -17,7 +17,7 @@ package supertokens
 
 import (
 	"bytes"
-	"encoding/json"
+	"context"
 	"errors"
 	"fmt"
 	"io/ioutil"
@@ -45,6 +45,7 @@ var (
 	querierLock           sync.Mutex
 	querierHostLock       sync.Mutex
 	querierInterceptor    func(*http.Request, UserContext) *http.Request
+	querierHTTPClient     = &http.Client{}
 )
 
 func SetQuerierApiVersionForTests(version string) {
@@ -57,7 +58,7 @@ func (q *Querier) GetQuerierAPIVersion() (string, error) {
 	if querierAPIVersion != "" {
 		return querierAPIVersion, nil
 	}
-	response, _, err := q.sendRequestHelper(NormalisedURLPath{value: "/apiversion"}, func(url string) (*http.Response, error) {
+	response, _, err := q.sendRequestHelperRaw(NormalisedURLPath{value: "/apiversion"}, func(url string) (*http.Response, error) {
 		req, err := http.NewRequest("GET", url, nil)
 		if err != nil {
 			return nil, err
@@ -65,22 +66,17 @@ func (q *Querier) GetQuerierAPIVersion() (string, error) {
 		if QuerierAPIKey != nil {
 			req.Header.Set("api-key", *QuerierAPIKey)
 		}
-		client := &http.Client{}
-		return client.Do(req)
+		return querierHTTPClient.Do(req)
 	}, len(QuerierHosts), nil)
 
 	if err != nil {
 		return "", err
 	}
 
-	respJSON, err := json.Marshal(response)
-	if err != nil {
-		return "", err
-	}
 	var cdiSupportedByServer struct {
 		Versions []string `json:"versions"`
 	}
-	err = json.Unmarshal(respJSON, &cdiSupportedByServer)
+	err = JSONUnmarshal(response, &cdiSupportedByServer)
 	if err != nil {
 		return "", err
 	}
@@ -101,8 +97,12 @@ func GetNewQuerierInstanceOrThrowError(rIDToCore string) (*Querier, error) {
 	return &Querier{RIDToCore: rIDToCore}, nil
 }
 
-func initQuerier(hosts []QuerierHost, APIKey string, interceptor func(*http.Request, UserContext) *http.Request) {
+func initQuerier(hosts []QuerierHost, APIKey string, interceptor func(*http.Request, UserContext) *http.Request, transportConfig *TransportConfig, requestTimeout time.Duration, egressProxy *egressProxy, latencyRouting *LatencyRoutingConfig) {
 	if !querierInitCalled {
+		querierHTTPClient = &http.Client{
+			Transport: makeQuerierTransport(transportConfig, egressProxy),
+			Timeout:   requestTimeout,
+		}
 		querierInitCalled = true
 		QuerierHosts = hosts
 		if APIKey != "" {
@@ -111,6 +111,13 @@ func initQuerier(hosts []QuerierHost, APIKey string, interceptor func(*http.Requ
 		querierAPIVersion = ""
 		querierLastTriedIndex = 0
 		querierInterceptor = interceptor
+		querierLatencyRouter = makeLatencyRouter(hosts, latencyRouting)
+		if querierLatencyRouter != nil {
+			err := RegisterBackgroundWorker(querierLatencyRouter.getBackgroundWorker())
+			if err != nil {
+				LogDebugMessage("initQuerier: " + err.Error())
+			}
+		}
 	}
 }
 
@@ -119,86 +126,36 @@ func (q *Querier) SendPostRequest(path string, data map[string]interface{}, user
 	if err != nil {
 		return nil, err
 	}
-	resp, _, err := q.sendRequestHelper(nP, func(url string) (*http.Response, error) {
-		if data == nil {
-			data = map[string]interface{}{}
-		}
-		jsonData, err := json.Marshal(data)
-		if err != nil {
-			return nil, err
-		}
-		req, err := http.NewRequest("POST", url, bytes.NewBuffer(jsonData))
-		if err != nil {
-			return nil, err
-		}
-
-		apiVersion, querierAPIVersionError := q.GetQuerierAPIVersion()
-		if querierAPIVersionError != nil {
-			return nil, querierAPIVersionError
-		}
-
-		req.Header.Set("content-type", "application/json; charset=utf-8")
-		req.Header.Set("cdi-version", apiVersion)
-		if QuerierAPIKey != nil {
-			req.Header.Set("api-key", *QuerierAPIKey)
-		}
-		if nP.IsARecipePath() && q.RIDToCore != "" {
-			req.Header.Set("rid", q.RIDToCore)
-		}
-
-		if querierInterceptor != nil {
-			req = querierInterceptor(req, userContext)
-		}
-
-		client := &http.Client{}
-		return client.Do(req)
-	}, len(QuerierHosts), nil)
+	if data == nil {
+		data = map[string]interface{}{}
+	}
+	resp, _, err := q.sendRequestHelper(nP, q.makeRequestWithBodyFunction(http.MethodPost, nP, data, nil, userContext), len(QuerierHosts), nil)
 	return resp, err
 }
 
+// SendPostRequestInto is like SendPostRequest, but decodes the response body
+// directly into out instead of going through a map
+func (q *Querier) SendPostRequestInto(path string, data map[string]interface{}, out interface{}, userContext UserContext) error {
+	nP, err := NewNormalisedURLPath(path)
+	if err != nil {
+		return err
+	}
+	if data == nil {
+		data = map[string]interface{}{}
+	}
+	body, _, err := q.sendRequestHelperRaw(nP, q.makeRequestWithBodyFunction(http.MethodPost, nP, data, nil, userContext), len(QuerierHosts), nil)
+	if err != nil {
+		return err
+	}
+	return JSONUnmarshal(body, out)
+}
+
 func (q *Querier) SendDeleteRequest(path string, data map[string]interface{}, params map[string]string, userContext UserContext) (map[string]interface{}, error) {
 	nP, err := NewNormalisedURLPath(path)
 	if err != nil {
 		return nil, err
 	}
-	resp, _, err := q.sendRequestHelper(nP, func(url string) (*http.Response, error) {
-		jsonData, err := json.Marshal(data)
-		if err != nil {
-			return nil, err
-		}
-		req, err := http.NewRequest("DELETE", url, bytes.NewBuffer(jsonData))
-		if err != nil {
-			return nil, err
-		}
-
-		query := req.URL.Query()
-
-		for k, v := range params {
-			query.Add(k, v)
-		}
-		req.URL.RawQuery = query.Encode()
-
-		apiVersion, querierAPIVersionError := q.GetQuerierAPIVersion()
-		if querierAPIVersionError != nil {
-			return nil, querierAPIVersionError
-		}
-
-		req.Header.Set("content-type", "application/json; charset=utf-8")
-		req.Header.Set("cdi-version", apiVersion)
-		if QuerierAPIKey != nil {
-			req.Header.Set("api-key", *QuerierAPIKey)
-		}
-		if nP.IsARecipePath() && q.RIDToCore != "" {
-			req.Header.Set("rid", q.RIDToCore)
-		}
-
-		if querierInterceptor != nil {
-			req = querierInterceptor(req, userContext)
-		}
-
-		client := &http.Client{}
-		return client.Do(req)
-	}, len(QuerierHosts), nil)
+	resp, _, err := q.sendRequestHelper(nP, q.makeRequestWithBodyFunction(http.MethodDelete, nP, data, params, userContext), len(QuerierHosts), nil)
 	return resp, err
 }
 
@@ -207,49 +164,44 @@ func (q *Querier) SendGetRequest(path string, params map[string]string, userCont
 	if err != nil {
 		return nil, err
 	}
-	resp, _, err := q.sendRequestHelper(nP, func(url string) (*http.Response, error) {
-		req, err := http.NewRequest("GET", url, nil)
-		if err != nil {
-			return nil, err
-		}
-
-		query := req.URL.Query()
-
-		for k, v := range params {
-			query.Add(k, v)
-		}
-		req.URL.RawQuery = query.Encode()
-
-		apiVersion, querierAPIVersionError := q.GetQuerierAPIVersion()
-		if querierAPIVersionError != nil {
-			return nil, querierAPIVersionError
-		}
-		req.Header.Set("cdi-version", apiVersion)
-		if QuerierAPIKey != nil {
-			req.Header.Set("api-key", *QuerierAPIKey)
-		}
-		if nP.IsARecipePath() && q.RIDToCore != "" {
-			req.Header.Set("rid", q.RIDToCore)
-		}
-
-		if querierInterceptor != nil {
-			req = querierInterceptor(req, userContext)
-		}
-
-		client := &http.Client{}
-		return client.Do(req)
-	}, len(QuerierHosts), nil)
+	resp, _, err := q.sendRequestHelper(nP, q.makeGetRequestFunction(nP, params, userContext), len(QuerierHosts), nil)
 	return resp, err
 }
 
+// SendGetRequestInto is like SendGetRequest, but decodes the response body
+// directly into out instead of going through a map
+func (q *Querier) SendGetRequestInto(path string, params map[string]string, out interface{}, userContext UserContext) error {
+	nP, err := NewNormalisedURLPath(path)
+	if err != nil {
+		return err
+	}
+	body, _, err := q.sendRequestHelperRaw(nP, q.makeGetRequestFunction(nP, params, userContext), len(QuerierHosts), nil)
+	if err != nil {
+		return err
+	}
+	return JSONUnmarshal(body, out)
+}
+
 func (q *Querier) SendGetRequestWithResponseHeaders(path string, params map[string]string, userContext UserContext) (map[string]interface{}, http.Header, error) {
 	nP, err := NewNormalisedURLPath(path)
 	if err != nil {
 		return nil, nil, err
 	}
+	return q.sendRequestHelper(nP, q.makeGetRequestFunction(nP, params, userContext), len(QuerierHosts), nil)
+}
 
-	return q.sendRequestHelper(nP, func(url string) (*http.Response, error) {
-		req, err := http.NewRequest("GET", url, nil)
+func (q *Querier) SendPutRequest(path string, data map[string]interface{}, userContext UserContext) (map[string]interface{}, error) {
+	nP, err := NewNormalisedURLPath(path)
+	if err != nil {
+		return nil, err
+	}
+	resp, _, err := q.sendRequestHelper(nP, q.makeRequestWithBodyFunction(http.MethodPut, nP, data, nil, userContext), len(QuerierHosts), nil)
+	return resp, err
+}
+
+func (q *Querier) makeGetRequestFunction(nP NormalisedURLPath, params map[string]string, userContext UserContext) httpRequestFunction {
+	return func(url string) (*http.Response, error) {
+		req, err := http.NewRequestWithContext(getContextFromUserContext(userContext), "GET", url, nil)
 		if err != nil {
 			return nil, err
 		}
@@ -277,26 +229,30 @@ func (q *Querier) SendGetRequestWithResponseHeaders(path string, params map[stri
 			req = querierInterceptor(req, userContext)
 		}
 
-		client := &http.Client{}
-		return client.Do(req)
-	}, len(QuerierHosts), nil)
+		return querierHTTPClient.Do(req)
+	}
 }
 
-func (q *Querier) SendPutRequest(path string, data map[string]interface{}, userContext UserContext) (map[string]interface{}, error) {
-	nP, err := NewNormalisedURLPath(path)
-	if err != nil {
-		return nil, err
-	}
-	resp, _, err := q.sendRequestHelper(nP, func(url string) (*http.Response, error) {
-		jsonData, err := json.Marshal(data)
+func (q *Querier) makeRequestWithBodyFunction(method string, nP NormalisedURLPath, data map[string]interface{}, params map[string]string, userContext UserContext) httpRequestFunction {
+	return func(url string) (*http.Response, error) {
+		jsonData, err := JSONMarshal(data)
 		if err != nil {
 			return nil, err
 		}
-		req, err := http.NewRequest("PUT", url, bytes.NewBuffer(jsonData))
+		req, err := http.NewRequestWithContext(getContextFromUserContext(userContext), method, url, bytes.NewBuffer(jsonData))
 		if err != nil {
 			return nil, err
 		}
 
+		if len(params) > 0 {
+			query := req.URL.Query()
+
+			for k, v := range params {
+				query.Add(k, v)
+			}
+			req.URL.RawQuery = query.Encode()
+		}
+
 		apiVersion, querierAPIVersionError := q.GetQuerierAPIVersion()
 		if querierAPIVersionError != nil {
 			return nil, querierAPIVersionError
@@ -315,10 +271,77 @@ func (q *Querier) SendPutRequest(path string, data map[string]interface{}, userC
 			req = querierInterceptor(req, userContext)
 		}
 
-		client := &http.Client{}
-		return client.Do(req)
-	}, len(QuerierHosts), nil)
-	return resp, err
+		return querierHTTPClient.Do(req)
+	}
+}
+
+// makeQuerierTransport returns nil (so that http.DefaultTransport is used) if
+// the transport is not being tuned or proxied
+func makeQuerierTransport(config *TransportConfig, egressProxy *egressProxy) http.RoundTripper {
+	if config == nil && egressProxy == nil {
+		return nil
+	}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	if egressProxy != nil {
+		egressProxy.apply(transport)
+	}
+	if config == nil {
+		return transport
+	}
+	if config.MaxIdleConns != nil {
+		transport.MaxIdleConns = *config.MaxIdleConns
+	}
+	if config.MaxIdleConnsPerHost != nil {
+		transport.MaxIdleConnsPerHost = *config.MaxIdleConnsPerHost
+	}
+	if config.MaxConnsPerHost != nil {
+		transport.MaxConnsPerHost = *config.MaxConnsPerHost
+	}
+	if config.IdleConnTimeout != nil {
+		transport.IdleConnTimeout = *config.IdleConnTimeout
+	}
+	if config.TLSHandshakeTimeout != nil {
+		transport.TLSHandshakeTimeout = *config.TLSHandshakeTimeout
+	}
+	if config.ForceAttemptHTTP2 != nil {
+		transport.ForceAttemptHTTP2 = *config.ForceAttemptHTTP2
+	}
+	transport.DisableKeepAlives = config.DisableKeepAlives
+	return transport
+}
+
+// SetContextInUserContext makes core requests that are passed this user
+// context honour the deadline and cancellation of ctx. This is useful to set a
+// timeout for a single call, e.g. session.GetSessionInformation.
+func SetContextInUserContext(userContext UserContext, ctx context.Context) UserContext {
+	if userContext == nil {
+		userContext = &map[string]interface{}{}
+	}
+	defaultObj, ok := (*userContext)["_default"].(map[string]interface{})
+	if !ok {
+		defaultObj = map[string]interface{}{}
+		(*userContext)["_default"] = defaultObj
+	}
+	defaultObj["context"] = ctx
+	return userContext
+}
+
+// getContextFromUserContext returns the context set via SetContextInUserContext,
+// or else the context of the request being handled, so that core requests are
+// cancelled along with it and honour its deadline
+func getContextFromUserContext(userContext UserContext) context.Context {
+	if userContext != nil {
+		if defaultObj, ok := (*userContext)["_default"].(map[string]interface{}); ok {
+			if ctx, ok := defaultObj["context"].(context.Context); ok {
+				return ctx
+			}
+		}
+	}
+	req := getRequestFromUserContext(userContext)
+	if req == nil {
+		return context.Background()
+	}
+	return req.Context()
 }
 
 type httpRequestFunction func(url string) (*http.Response, error)
@@ -342,13 +365,36 @@ func GetAllCoreUrlsForPath(path string) []string {
 }
 
 func (q *Querier) sendRequestHelper(path NormalisedURLPath, httpRequest httpRequestFunction, numberOfTries int, retryInfoMap *map[string]int) (map[string]interface{}, http.Header, error) {
+	body, headers, err := q.sendRequestHelperRaw(path, httpRequest, numberOfTries, retryInfoMap)
+	if err != nil {
+		return nil, nil, err
+	}
+	finalResult := make(map[string]interface{})
+	jsonError := JSONUnmarshal(body, &finalResult)
+	if jsonError != nil {
+		return map[string]interface{}{
+			"result": string(body),
+		}, headers, nil
+	}
+	return finalResult, headers, nil
+}
+
+func (q *Querier) sendRequestHelperRaw(path NormalisedURLPath, httpRequest httpRequestFunction, numberOfTries int, retryInfoMap *map[string]int) ([]byte, http.Header, error) {
 	if numberOfTries == 0 {
 		return nil, nil, errors.New("no SuperTokens core available to query")
 	}
 
 	querierHostLock.Lock()
-	currentDomain := QuerierHosts[querierLastTriedIndex].Domain.GetAsStringDangerous()
-	currentBasePath := QuerierHosts[querierLastTriedIndex].BasePath.GetAsStringDangerous()
+	hostIndex := -1
+	if querierLatencyRouter != nil {
+		hostIndex = querierLatencyRouter.getHostIndex(len(QuerierHosts) - numberOfTries)
+	}
+	if hostIndex == -1 {
+		hostIndex = querierLastTriedIndex
+		querierLastTriedIndex = (querierLastTriedIndex + 1) % len(QuerierHosts)
+	}
+	currentDomain := QuerierHosts[hostIndex].Domain.GetAsStringDangerous()
+	currentBasePath := QuerierHosts[hostIndex].BasePath.GetAsStringDangerous()
 	url := currentDomain + currentBasePath + path.GetAsStringDangerous()
 
 	maxRetries := 5
@@ -366,14 +412,16 @@ func (q *Querier) sendRequestHelper(path NormalisedURLPath, httpRequest httpRequ
 		_retryInfoMap[url] = maxRetries
 	}
 
-	querierLastTriedIndex = (querierLastTriedIndex + 1) % len(QuerierHosts)
 	querierHostLock.Unlock()
 
 	resp, err := httpRequest(url)
 
 	if err != nil {
 		if strings.Contains(err.Error(), "connection refused") {
-			return q.sendRequestHelper(path, httpRequest, numberOfTries-1, &_retryInfoMap)
+			if querierLatencyRouter != nil {
+				querierLatencyRouter.markUnhealthy(hostIndex)
+			}
+			return q.sendRequestHelperRaw(path, httpRequest, numberOfTries-1, &_retryInfoMap)
 		}
 		if resp != nil {
 			resp.Body.Close()
@@ -399,26 +447,23 @@ func (q *Querier) sendRequestHelper(path NormalisedURLPath, httpRequest httpRequ
 
 				time.Sleep(time.Millisecond * time.Duration(delay))
 
-				return q.sendRequestHelper(path, httpRequest, numberOfTries, &_retryInfoMap)
+				return q.sendRequestHelperRaw(path, httpRequest, numberOfTries, &_retryInfoMap)
 			}
 		}
 
 		return nil, nil, fmt.Errorf("SuperTokens core threw an error for a request to path: '%s' with status code: %v and message: %s", path.GetAsStringDangerous(), resp.StatusCode, body)
 	}
 
-	headers := resp.Header.Clone()
-	finalResult := make(map[string]interface{})
-	jsonError := json.Unmarshal(body, &finalResult)
-	if jsonError != nil {
-		return map[string]interface{}{
-			"result": string(body),
-		}, headers, nil
-	}
-	return finalResult, headers, nil
+	return body, resp.Header.Clone(), nil
 }
 
 func ResetQuerierForTest() {
 	querierInitCalled = false
+	resetCoreFeaturesForTest()
+	if querierLatencyRouter != nil {
+		unregisterBackgroundWorker(latencyProbeWorkerName)
+	}
+	querierLatencyRouter = nil
 }
 
 func (q *Querier) SetApiVersionForTests(apiVersion string) {
@@ -0,0 +1,181 @@
+/* Copyright (c) 2023, VRAI Labs and/or its affiliates. All rights reserved.
+ *
+ * This software is licensed under the Apache License, Version 2.0 (the
+ * "License") as published by the Apache Software Foundation.
+ *
+ * You may not use this file except in compliance with the License. You may
+ * obtain a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+ * License for the specific language governing permissions and limitations
+ * under the License.
+ */
+
+package supertokens
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestThatQuerierDecodesResponseIntoStruct(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/apiversion", func(rw http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(rw).Encode(map[string]interface{}{"versions": cdiSupported})
+	})
+	mux.HandleFunc("/recipe/test", func(rw http.ResponseWriter, r *http.Request) {
+		var body map[string]interface{}
+		json.NewDecoder(r.Body).Decode(&body)
+		json.NewEncoder(rw).Encode(map[string]interface{}{
+			"status":      "OK",
+			"method":      r.Method,
+			"param":       r.URL.Query().Get("param"),
+			"body":        body["key"],
+			"timeCreated": 1702400000123,
+		})
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	domain, err := NewNormalisedURLDomain(server.URL)
+	assert.NoError(t, err)
+	basePath, err := NewNormalisedURLPath(server.URL)
+	assert.NoError(t, err)
+
+	ResetQuerierForTest()
+	defer ResetQuerierForTest()
+	initQuerier([]QuerierHost{{Domain: domain, BasePath: basePath}}, "", nil, nil, 0, nil, nil)
+
+	querier, err := GetNewQuerierInstanceOrThrowError("")
+	assert.NoError(t, err)
+
+	type testResponse struct {
+		Status      string `json:"status"`
+		Method      string `json:"method"`
+		Param       string `json:"param"`
+		Body        string `json:"body"`
+		TimeCreated uint64 `json:"timeCreated"`
+	}
+
+	var getResponse testResponse
+	err = querier.SendGetRequestInto("/recipe/test", map[string]string{"param": "value"}, &getResponse, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, testResponse{
+		Status:      "OK",
+		Method:      http.MethodGet,
+		Param:       "value",
+		TimeCreated: 1702400000123,
+	}, getResponse)
+
+	var postResponse testResponse
+	err = querier.SendPostRequestInto("/recipe/test", map[string]interface{}{"key": "value"}, &postResponse, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, testResponse{
+		Status:      "OK",
+		Method:      http.MethodPost,
+		Body:        "value",
+		TimeCreated: 1702400000123,
+	}, postResponse)
+
+	// the map based functions keep working on top of the same request helpers
+	response, err := querier.SendDeleteRequest("/recipe/test", map[string]interface{}{"key": "value"}, map[string]string{"param": "value"}, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, http.MethodDelete, response["method"])
+	assert.Equal(t, "value", response["param"])
+	assert.Equal(t, "value", response["body"])
+}
+
+func TestQuerierTransportConfig(t *testing.T) {
+	assert.Nil(t, makeQuerierTransport(nil, nil))
+
+	maxIdleConnsPerHost := 50
+	tlsHandshakeTimeout := 2 * time.Second
+	forceAttemptHTTP2 := false
+	transport := makeQuerierTransport(&TransportConfig{
+		MaxIdleConnsPerHost: &maxIdleConnsPerHost,
+		TLSHandshakeTimeout: &tlsHandshakeTimeout,
+		ForceAttemptHTTP2:   &forceAttemptHTTP2,
+	}, nil).(*http.Transport)
+
+	assert.Equal(t, 50, transport.MaxIdleConnsPerHost)
+	assert.Equal(t, 2*time.Second, transport.TLSHandshakeTimeout)
+	assert.False(t, transport.ForceAttemptHTTP2)
+	assert.False(t, transport.DisableKeepAlives)
+	// untouched settings keep the defaults
+	assert.Equal(t, http.DefaultTransport.(*http.Transport).IdleConnTimeout, transport.IdleConnTimeout)
+}
+
+func TestThatQuerierHonoursContextDeadlines(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/apiversion", func(rw http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(rw).Encode(map[string]interface{}{"versions": cdiSupported})
+	})
+	mux.HandleFunc("/recipe/slow", func(rw http.ResponseWriter, r *http.Request) {
+		time.Sleep(200 * time.Millisecond)
+		json.NewEncoder(rw).Encode(map[string]interface{}{"status": "OK"})
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	domain, err := NewNormalisedURLDomain(server.URL)
+	assert.NoError(t, err)
+	basePath, err := NewNormalisedURLPath(server.URL)
+	assert.NoError(t, err)
+
+	ResetQuerierForTest()
+	defer ResetQuerierForTest()
+	initQuerier([]QuerierHost{{Domain: domain, BasePath: basePath}}, "", nil, nil, 0, nil, nil)
+
+	querier, err := GetNewQuerierInstanceOrThrowError("")
+	assert.NoError(t, err)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	_, err = querier.SendGetRequest("/recipe/slow", nil, SetContextInUserContext(nil, ctx))
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+
+	// the context of the request being handled is used by default
+	req := httptest.NewRequest(http.MethodGet, "/", nil).WithContext(ctx)
+	_, err = querier.SendGetRequest("/recipe/slow", nil, MakeDefaultUserContextFromAPI(req))
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+
+	_, err = querier.SendGetRequest("/recipe/slow", nil, nil)
+	assert.NoError(t, err)
+}
+
+func TestThatQuerierHonoursRequestTimeout(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/apiversion", func(rw http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(rw).Encode(map[string]interface{}{"versions": cdiSupported})
+	})
+	mux.HandleFunc("/recipe/slow", func(rw http.ResponseWriter, r *http.Request) {
+		time.Sleep(200 * time.Millisecond)
+		json.NewEncoder(rw).Encode(map[string]interface{}{"status": "OK"})
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	domain, err := NewNormalisedURLDomain(server.URL)
+	assert.NoError(t, err)
+	basePath, err := NewNormalisedURLPath(server.URL)
+	assert.NoError(t, err)
+
+	ResetQuerierForTest()
+	defer ResetQuerierForTest()
+	// /apiversion responds immediately, so only the slow request times out
+	initQuerier([]QuerierHost{{Domain: domain, BasePath: basePath}}, "", nil, nil, 100*time.Millisecond, nil, nil)
+
+	querier, err := GetNewQuerierInstanceOrThrowError("")
+	assert.NoError(t, err)
+
+	_, err = querier.SendGetRequest("/recipe/slow", nil, nil)
+	assert.Error(t, err)
+}
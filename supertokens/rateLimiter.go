@@ -0,0 +1,207 @@
+/* Copyright (c) 2023, VRAI Labs and/or its affiliates. All rights reserved.
+ *
+ * This software is licensed under the Apache License, Version 2.0 (the
+ * "License") as published by the Apache Software Foundation.
+ *
+ * You may not use this file except in compliance with the License. You may
+ * obtain a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+ * License for the specific language governing permissions and limitations
+ * under the License.
+ */
+
+package supertokens
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// RateLimiterStore keeps the hit counters used by the rate limiter. Use a
+// shared store (redis, memcached, ...) when running more than one API server.
+type RateLimiterStore struct {
+	// Increment records one hit for key and returns the number of hits in the
+	// current window along with the time left before the window resets.
+	Increment *func(key string, window time.Duration, userContext UserContext) (RateLimiterStoreResult, error)
+}
+
+type RateLimiterStoreResult struct {
+	Hits       uint64
+	ResetAfter time.Duration
+}
+
+type RateLimitRequestInfo struct {
+	RecipeID string
+	APIID    string
+	TenantID string
+	Request  *http.Request
+}
+
+type RateLimiterInput struct {
+	// Store defaults to an in memory store
+	Store *RateLimiterStore
+	// MaxRequests is the number of requests allowed per key in each window
+	MaxRequests uint64
+	Window      time.Duration
+	// GetKeys returns the keys a request is counted against. A request is
+	// rejected if any key is over the limit. Returning no keys skips rate
+	// limiting for the request. Defaults to the client IP and API ID.
+	GetKeys func(info RateLimitRequestInfo, userContext UserContext) ([]string, error)
+}
+
+type normalisedRateLimiter struct {
+	Store       RateLimiterStore
+	MaxRequests uint64
+	Window      time.Duration
+	GetKeys     func(info RateLimitRequestInfo, userContext UserContext) ([]string, error)
+}
+
+const (
+	defaultRateLimitMaxRequests = 30
+	defaultRateLimitWindow      = time.Minute
+)
+
+func normaliseRateLimiterInput(config *RateLimiterInput) (*normalisedRateLimiter, error) {
+	if config == nil {
+		return nil, nil
+	}
+	result := &normalisedRateLimiter{
+		Store:       MakeInMemoryRateLimiterStore(),
+		MaxRequests: defaultRateLimitMaxRequests,
+		Window:      defaultRateLimitWindow,
+		GetKeys: func(info RateLimitRequestInfo, userContext UserContext) ([]string, error) {
+			return []string{"ip:" + GetClientIP(info.Request, userContext) + ":api:" + info.APIID}, nil
+		},
+	}
+	if config.Store != nil {
+		if config.Store.Increment == nil {
+			return nil, errors.New("rate limiter store must implement Increment")
+		}
+		result.Store = *config.Store
+	}
+	if config.MaxRequests != 0 {
+		result.MaxRequests = config.MaxRequests
+	}
+	if config.Window != 0 {
+		result.Window = config.Window
+	}
+	if config.GetKeys != nil {
+		result.GetKeys = config.GetKeys
+	}
+	return result, nil
+}
+
+// isRateLimited returns true if the request is over the limit, in which case
+// a 429 response has already been sent.
+func (s *superTokens) isRateLimited(info RateLimitRequestInfo, res http.ResponseWriter, userContext UserContext) (bool, error) {
+	if s.RateLimiter == nil {
+		return false, nil
+	}
+	keys, err := s.RateLimiter.GetKeys(info, userContext)
+	if err != nil {
+		return false, err
+	}
+	for _, key := range keys {
+		result, err := (*s.RateLimiter.Store.Increment)(key, s.RateLimiter.Window, userContext)
+		if err != nil {
+			return false, err
+		}
+		if result.Hits > s.RateLimiter.MaxRequests {
+			if s.ShadowMode.policies[ShadowModeRateLimitPolicy] {
+				ReportShadowModeViolation(ShadowModeRateLimitPolicy, "Too many requests for key: "+key, userContext)
+				continue
+			}
+			LogDebugMessage("middleware: Rate limiting request for key: " + key)
+			retryAfter := int(result.ResetAfter.Seconds())
+			if retryAfter < 1 {
+				retryAfter = 1
+			}
+			res.Header().Set("Retry-After", strconv.Itoa(retryAfter))
+			return true, SendNon200ResponseWithMessage(res, "Too many requests", http.StatusTooManyRequests)
+		}
+	}
+	return false, nil
+}
+
+// GetEmailFromRequestBody reads the email from the JSON body of sign in / sign up
+// style requests. It supports both the top level "email" field and the
+// "formFields" array. It can be used in RateLimiterInput.GetKeys to limit
+// requests per email.
+func GetEmailFromRequestBody(req *http.Request) string {
+	body, err := ReadFromRequest(req)
+	if err != nil {
+		return ""
+	}
+	var parsedBody struct {
+		Email      string `json:"email"`
+		FormFields []struct {
+			ID    string      `json:"id"`
+			Value interface{} `json:"value"`
+		} `json:"formFields"`
+	}
+	if json.Unmarshal(body, &parsedBody) != nil {
+		return ""
+	}
+	if parsedBody.Email != "" {
+		return parsedBody.Email
+	}
+	for _, field := range parsedBody.FormFields {
+		if email, ok := field.Value.(string); ok && field.ID == "email" {
+			return email
+		}
+	}
+	return ""
+}
+
+type inMemoryRateLimitEntry struct {
+	hits    uint64
+	resetAt time.Time
+}
+
+// MakeInMemoryRateLimiterStore returns a fixed window store that keeps its
+// counters in process memory.
+func MakeInMemoryRateLimiterStore() RateLimiterStore {
+	var mutex sync.Mutex
+	entries := map[string]*inMemoryRateLimitEntry{}
+	nextCleanup := time.Now()
+
+	increment := func(key string, window time.Duration, userContext UserContext) (RateLimiterStoreResult, error) {
+		mutex.Lock()
+		defer mutex.Unlock()
+
+		now := time.Now()
+		if !now.Before(nextCleanup) {
+			for k, entry := range entries {
+				if !now.Before(entry.resetAt) {
+					delete(entries, k)
+				}
+			}
+			nextCleanup = now.Add(window)
+		}
+
+		entry, ok := entries[key]
+		if !ok || !now.Before(entry.resetAt) {
+			entry = &inMemoryRateLimitEntry{
+				resetAt: now.Add(window),
+			}
+			entries[key] = entry
+		}
+		entry.hits++
+
+		return RateLimiterStoreResult{
+			Hits:       entry.hits,
+			ResetAfter: entry.resetAt.Sub(now),
+		}, nil
+	}
+
+	return RateLimiterStore{
+		Increment: &increment,
+	}
+}
@@ -0,0 +1,117 @@
+/* Copyright (c) 2023, VRAI Labs and/or its affiliates. All rights reserved.
+ *
+ * This software is licensed under the Apache License, Version 2.0 (the
+ * "License") as published by the Apache Software Foundation.
+ *
+ * You may not use this file except in compliance with the License. You may
+ * obtain a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+ * License for the specific language governing permissions and limitations
+ * under the License.
+ */
+
+package supertokens
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestInMemoryRateLimiterStoreResetsAfterWindow(t *testing.T) {
+	store := MakeInMemoryRateLimiterStore()
+
+	result, err := (*store.Increment)("key", 50*time.Millisecond, &map[string]interface{}{})
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(1), result.Hits)
+
+	result, err = (*store.Increment)("key", 50*time.Millisecond, &map[string]interface{}{})
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(2), result.Hits)
+
+	result, err = (*store.Increment)("otherKey", 50*time.Millisecond, &map[string]interface{}{})
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(1), result.Hits)
+
+	time.Sleep(60 * time.Millisecond)
+
+	result, err = (*store.Increment)("key", 50*time.Millisecond, &map[string]interface{}{})
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(1), result.Hits)
+}
+
+func TestRateLimiterStoreMustImplementIncrement(t *testing.T) {
+	_, err := normaliseRateLimiterInput(&RateLimiterInput{Store: &RateLimiterStore{}})
+	assert.Error(t, err)
+}
+
+func TestGetEmailFromRequestBody(t *testing.T) {
+	req := httptest.NewRequest(http.MethodPost, "/auth/signin", strings.NewReader(`{"formFields": [{"id": "password", "value": "pass"}, {"id": "email", "value": "test@example.com"}]}`))
+	assert.Equal(t, "test@example.com", GetEmailFromRequestBody(req))
+
+	req = httptest.NewRequest(http.MethodPost, "/auth/signinup/code", strings.NewReader(`{"email": "test@example.com"}`))
+	assert.Equal(t, "test@example.com", GetEmailFromRequestBody(req))
+
+	req = httptest.NewRequest(http.MethodPost, "/auth/signinup/code", strings.NewReader(`{"phoneNumber": "+1234567890"}`))
+	assert.Equal(t, "", GetEmailFromRequestBody(req))
+}
+
+func TestThatMiddlewareRateLimitsRequests(t *testing.T) {
+	appInfo, err := NormaliseInputAppInfoOrThrowError(AppInfo{
+		AppName:       "SuperTokens",
+		APIDomain:     "api.supertokens.io",
+		WebsiteDomain: "supertokens.io",
+	})
+	assert.NoError(t, err)
+
+	handled := 0
+	recipeModule := MakeRecipeModule("test", appInfo, func(id string, tenantId string, req *http.Request, res http.ResponseWriter, theirHandler http.HandlerFunc, path NormalisedURLPath, method string, userContext UserContext) error {
+		handled++
+		return Send200Response(res, map[string]interface{}{"status": "OK"})
+	}, func() []string {
+		return []string{}
+	}, func() ([]APIHandled, error) {
+		path, err := NewNormalisedURLPath("/test")
+		return []APIHandled{{Method: http.MethodPost, PathWithoutAPIBasePath: path, ID: "/test"}}, err
+	}, nil, func(err error, req *http.Request, res http.ResponseWriter, userContext UserContext) (bool, error) {
+		return false, nil
+	}, defaultOnSuperTokensAPIError)
+
+	rateLimiter, err := normaliseRateLimiterInput(&RateLimiterInput{
+		MaxRequests: 2,
+	})
+	assert.NoError(t, err)
+	s := &superTokens{
+		AppInfo:               appInfo,
+		RecipeModules:         []RecipeModule{recipeModule},
+		OnSuperTokensAPIError: defaultOnSuperTokensAPIError,
+		RateLimiter:           rateLimiter,
+	}
+	handler := s.middleware(nil)
+
+	for i := 0; i < 2; i++ {
+		res := httptest.NewRecorder()
+		handler.ServeHTTP(res, httptest.NewRequest(http.MethodPost, "/auth/test", nil))
+		assert.Equal(t, http.StatusOK, res.Code)
+	}
+
+	res := httptest.NewRecorder()
+	handler.ServeHTTP(res, httptest.NewRequest(http.MethodPost, "/auth/test", nil))
+	assert.Equal(t, http.StatusTooManyRequests, res.Code)
+	assert.NotEmpty(t, res.Header().Get("Retry-After"))
+	assert.Equal(t, 2, handled)
+
+	// requests from another IP are counted separately
+	req := httptest.NewRequest(http.MethodPost, "/auth/test", nil)
+	req.RemoteAddr = "10.0.0.1:1234"
+	res = httptest.NewRecorder()
+	handler.ServeHTTP(res, req)
+	assert.Equal(t, http.StatusOK, res.Code)
+}
@@ -0,0 +1,75 @@
+/* Copyright (c) 2023, VRAI Labs and/or its affiliates. All rights reserved.
+ *
+ * This software is licensed under the Apache License, Version 2.0 (the
+ * "License") as published by the Apache Software Foundation.
+ *
+ * You may not use this file except in compliance with the License. You may
+ * obtain a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+ * License for the specific language governing permissions and limitations
+ * under the License.
+ */
+
+package supertokens
+
+import (
+	"fmt"
+	"strings"
+)
+
+// getPostInitCallbackOrder returns the indexes of recipeModules ordered so
+// that every recipe comes after the recipes it depends on. Recipes without
+// dependencies between them keep the order in which they were listed.
+func getPostInitCallbackOrder(recipeModules []RecipeModule) ([]int, error) {
+	indexByID := map[string]int{}
+	for idx, recipeModule := range recipeModules {
+		indexByID[recipeModule.GetRecipeID()] = idx
+	}
+
+	for _, recipeModule := range recipeModules {
+		for _, dependency := range recipeModule.GetDependencies() {
+			if _, ok := indexByID[dependency]; !ok {
+				return nil, fmt.Errorf("the %s recipe depends on the %s recipe. Please add %s.Init to the RecipeList", recipeModule.GetRecipeID(), dependency, dependency)
+			}
+		}
+	}
+
+	order := []int{}
+	added := make([]bool, len(recipeModules))
+	for len(order) < len(recipeModules) {
+		progressed := false
+		for idx, recipeModule := range recipeModules {
+			if added[idx] {
+				continue
+			}
+			ready := true
+			for _, dependency := range recipeModule.GetDependencies() {
+				if !added[indexByID[dependency]] {
+					ready = false
+					break
+				}
+			}
+			if ready {
+				order = append(order, idx)
+				added[idx] = true
+				progressed = true
+				break
+			}
+		}
+
+		if !progressed {
+			remaining := []string{}
+			for idx, recipeModule := range recipeModules {
+				if !added[idx] {
+					remaining = append(remaining, recipeModule.GetRecipeID())
+				}
+			}
+			return nil, fmt.Errorf("circular dependency between the recipes: %s", strings.Join(remaining, ", "))
+		}
+	}
+
+	return order, nil
+}
@@ -0,0 +1,97 @@
+/* Copyright (c) 2023, VRAI Labs and/or its affiliates. All rights reserved.
+ *
+ * This software is licensed under the Apache License, Version 2.0 (the
+ * "License") as published by the Apache Software Foundation.
+ *
+ * You may not use this file except in compliance with the License. You may
+ * obtain a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+ * License for the specific language governing permissions and limitations
+ * under the License.
+ */
+
+package supertokens
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func makeTestRecipe(recipeId string, onPostInit func(), dependencies ...string) Recipe {
+	return func(appInfo NormalisedAppinfo, onSuperTokensAPIError func(err error, req *http.Request, res http.ResponseWriter)) (*RecipeModule, error) {
+		recipeModule := MakeRecipeModule(recipeId, appInfo, nil, func() []string {
+			return []string{}
+		}, func() ([]APIHandled, error) {
+			return []APIHandled{}, nil
+		}, nil, func(err error, req *http.Request, res http.ResponseWriter, userContext UserContext) (bool, error) {
+			return false, nil
+		}, onSuperTokensAPIError)
+		recipeModule.DependsOn(dependencies...)
+		AddPostInitCallback(func() error {
+			onPostInit()
+			return nil
+		})
+		return &recipeModule, nil
+	}
+}
+
+func initWithTestRecipes(recipeList ...Recipe) error {
+	ResetForTest()
+	postInitCallbacks = []func() error{}
+	return Init(TypeInput{
+		AppInfo: AppInfo{
+			AppName:       "SuperTokens",
+			APIDomain:     "api.supertokens.io",
+			WebsiteDomain: "supertokens.io",
+		},
+		RecipeList: recipeList,
+	})
+}
+
+func TestPostInitCallbacksRunAfterTheirDependencies(t *testing.T) {
+	defer ResetForTest()
+	initialised := []string{}
+	makeRecipe := func(recipeId string, dependencies ...string) Recipe {
+		return makeTestRecipe(recipeId, func() {
+			initialised = append(initialised, recipeId)
+		}, dependencies...)
+	}
+
+	err := initWithTestRecipes(
+		makeRecipe("emailverification", "session"),
+		makeRecipe("profile", "session", "usermetadata"),
+		makeRecipe("session"),
+		makeRecipe("usermetadata"),
+	)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"session", "emailverification", "usermetadata", "profile"}, initialised)
+
+	instance, err := GetInstanceOrThrowError()
+	assert.NoError(t, err)
+	recipeIds := []string{}
+	for _, recipeModule := range instance.RecipeModules {
+		recipeIds = append(recipeIds, recipeModule.GetRecipeID())
+	}
+	assert.Equal(t, []string{"emailverification", "profile", "session", "usermetadata"}, recipeIds)
+}
+
+func TestMissingRecipeDependencyFailsInit(t *testing.T) {
+	defer ResetForTest()
+	err := initWithTestRecipes(makeTestRecipe("emailverification", func() {}, "session"))
+	assert.EqualError(t, err, "the emailverification recipe depends on the session recipe. Please add session.Init to the RecipeList")
+}
+
+func TestCircularRecipeDependencyFailsInit(t *testing.T) {
+	defer ResetForTest()
+	err := initWithTestRecipes(
+		makeTestRecipe("a", func() {}, "b"),
+		makeTestRecipe("b", func() {}, "a"),
+		makeTestRecipe("c", func() {}),
+	)
+	assert.EqualError(t, err, "circular dependency between the recipes: a, b")
+}
@@ -21,17 +21,29 @@ import (
 	"regexp"
 )
 
+// RecipeModule is what a recipe exposes to the middleware and error handler.
+// See MakeCustomRecipe for a simpler way to write a recipe outside of this SDK.
 type RecipeModule struct {
-	recipeID                      string
-	appInfo                       NormalisedAppinfo
-	HandleAPIRequest              func(ID string, tenantId string, req *http.Request, res http.ResponseWriter, theirHandler http.HandlerFunc, path NormalisedURLPath, method string, userContext UserContext) error
-	GetAllCORSHeaders             func() []string
+	recipeID string
+	appInfo  NormalisedAppinfo
+	// HandleAPIRequest serves the API with the given ID (one of those returned
+	// by GetAPIsHandled). theirHandler is the handler wrapped by the middleware.
+	HandleAPIRequest func(ID string, tenantId string, req *http.Request, res http.ResponseWriter, theirHandler http.HandlerFunc, path NormalisedURLPath, method string, userContext UserContext) error
+	// GetAllCORSHeaders returns the headers that the recipe's APIs read
+	GetAllCORSHeaders func() []string
+	// GetAPIsHandled returns the table of APIs that the recipe serves
 	GetAPIsHandled                func() ([]APIHandled, error)
 	ReturnAPIIdIfCanHandleRequest func(path NormalisedURLPath, method string, userContext UserContext) (*string, string, error)
-	HandleError                   func(err error, req *http.Request, res http.ResponseWriter, userContext UserContext) (bool, error)
-	OnSuperTokensAPIError         func(err error, req *http.Request, res http.ResponseWriter)
+	// HandleError returns true if it wrote a response for err
+	HandleError           func(err error, req *http.Request, res http.ResponseWriter, userContext UserContext) (bool, error)
+	OnSuperTokensAPIError func(err error, req *http.Request, res http.ResponseWriter)
+	dependencies          []string
 }
 
+// MakeRecipeModule creates a RecipeModule. handleError and
+// onSuperTokensAPIError must not be nil. If returnAPIIdIfCanHandleRequest is
+// nil, requests are matched against getAPIsHandled, with an optional tenant
+// ID after the API base path.
 func MakeRecipeModule(
 	recipeId string,
 	appInfo NormalisedAppinfo,
@@ -107,3 +119,15 @@ func (r RecipeModule) GetRecipeID() string {
 func (r RecipeModule) GetAppInfo() NormalisedAppinfo {
 	return r.appInfo
 }
+
+// DependsOn declares the IDs of recipes that must also be in the RecipeList.
+// supertokens.Init fails if any of them is missing, and runs their post init
+// callbacks before the ones of this recipe. Recipes are still constructed, and
+// matched against requests, in the order of the RecipeList.
+func (r *RecipeModule) DependsOn(recipeIDs ...string) {
+	r.dependencies = append(r.dependencies, recipeIDs...)
+}
+
+func (r RecipeModule) GetDependencies() []string {
+	return r.dependencies
+}
@@ -0,0 +1,85 @@
+/* Copyright (c) 2023, VRAI Labs and/or its affiliates. All rights reserved.
+ *
+ * This software is licensed under the Apache License, Version 2.0 (the
+ * "License") as published by the Apache Software Foundation.
+ *
+ * You may not use this file except in compliance with the License. You may
+ * obtain a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+ * License for the specific language governing permissions and limitations
+ * under the License.
+ */
+
+package supertokens
+
+import "fmt"
+
+const (
+	// ShadowModeRateLimitPolicy is the policy ID of the rate limiter
+	ShadowModeRateLimitPolicy = "rateLimit"
+	// ShadowModeClaimPolicyPrefix followed by a claim validator ID is the
+	// policy ID of that claim validator
+	ShadowModeClaimPolicyPrefix = "claim:"
+	// ShadowModeFormFieldPolicyPrefix followed by a form field ID is the
+	// policy ID of that form field's validation (e.g. "formField:password")
+	ShadowModeFormFieldPolicyPrefix = "formField:"
+)
+
+// ShadowModeInput lists policies that should be evaluated but not enforced,
+// so that the impact of a new policy can be measured before turning it on.
+type ShadowModeInput struct {
+	Policies []string
+	// OnViolation is called whenever a policy in shadow mode would have
+	// rejected a request. Use it to log or meter the impact of the policy.
+	OnViolation func(violation ShadowModeViolation, userContext UserContext)
+}
+
+type ShadowModeViolation struct {
+	PolicyID string
+	Reason   interface{}
+}
+
+type normalisedShadowMode struct {
+	policies    map[string]bool
+	onViolation func(violation ShadowModeViolation, userContext UserContext)
+}
+
+func normaliseShadowModeInput(config *ShadowModeInput) normalisedShadowMode {
+	result := normalisedShadowMode{
+		policies: map[string]bool{},
+	}
+	if config == nil {
+		return result
+	}
+	for _, policy := range config.Policies {
+		result.policies[policy] = true
+	}
+	result.onViolation = config.OnViolation
+	return result
+}
+
+// IsPolicyInShadowMode returns true if the policy should be evaluated but not enforced
+func IsPolicyInShadowMode(policyID string) bool {
+	instance, err := GetInstanceOrThrowError()
+	if err != nil {
+		return false
+	}
+	return instance.ShadowMode.policies[policyID]
+}
+
+// ReportShadowModeViolation should be called by policies in shadow mode
+// instead of rejecting the request
+func ReportShadowModeViolation(policyID string, reason interface{}, userContext UserContext) {
+	LogDebugMessage(fmt.Sprint("Shadow mode: policy ", policyID, " would have rejected the request. Reason: ", reason))
+	instance, err := GetInstanceOrThrowError()
+	if err != nil || instance.ShadowMode.onViolation == nil {
+		return
+	}
+	instance.ShadowMode.onViolation(ShadowModeViolation{
+		PolicyID: policyID,
+		Reason:   reason,
+	}, userContext)
+}
@@ -0,0 +1,76 @@
+/* Copyright (c) 2023, VRAI Labs and/or its affiliates. All rights reserved.
+ *
+ * This software is licensed under the Apache License, Version 2.0 (the
+ * "License") as published by the Apache Software Foundation.
+ *
+ * You may not use this file except in compliance with the License. You may
+ * obtain a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+ * License for the specific language governing permissions and limitations
+ * under the License.
+ */
+
+package supertokens
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestThatRateLimitInShadowModeIsReportedButNotEnforced(t *testing.T) {
+	appInfo, err := NormaliseInputAppInfoOrThrowError(AppInfo{
+		AppName:       "SuperTokens",
+		APIDomain:     "api.supertokens.io",
+		WebsiteDomain: "supertokens.io",
+	})
+	assert.NoError(t, err)
+
+	recipeModule := MakeRecipeModule("test", appInfo, func(id string, tenantId string, req *http.Request, res http.ResponseWriter, theirHandler http.HandlerFunc, path NormalisedURLPath, method string, userContext UserContext) error {
+		return Send200Response(res, map[string]interface{}{"status": "OK"})
+	}, func() []string {
+		return []string{}
+	}, func() ([]APIHandled, error) {
+		path, err := NewNormalisedURLPath("/test")
+		return []APIHandled{{Method: http.MethodPost, PathWithoutAPIBasePath: path, ID: "/test"}}, err
+	}, nil, func(err error, req *http.Request, res http.ResponseWriter, userContext UserContext) (bool, error) {
+		return false, nil
+	}, defaultOnSuperTokensAPIError)
+
+	violations := []ShadowModeViolation{}
+	rateLimiter, err := normaliseRateLimiterInput(&RateLimiterInput{
+		MaxRequests: 1,
+	})
+	assert.NoError(t, err)
+	superTokensInstance = &superTokens{
+		AppInfo:               appInfo,
+		RecipeModules:         []RecipeModule{recipeModule},
+		OnSuperTokensAPIError: defaultOnSuperTokensAPIError,
+		RateLimiter:           rateLimiter,
+		ShadowMode: normaliseShadowModeInput(&ShadowModeInput{
+			Policies: []string{ShadowModeRateLimitPolicy},
+			OnViolation: func(violation ShadowModeViolation, userContext UserContext) {
+				violations = append(violations, violation)
+			},
+		}),
+	}
+	defer ResetForTest()
+	handler := superTokensInstance.middleware(nil)
+
+	for i := 0; i < 3; i++ {
+		res := httptest.NewRecorder()
+		handler.ServeHTTP(res, httptest.NewRequest(http.MethodPost, "/auth/test", nil))
+		assert.Equal(t, http.StatusOK, res.Code)
+	}
+
+	assert.Len(t, violations, 2)
+	assert.Equal(t, ShadowModeRateLimitPolicy, violations[0].PolicyID)
+
+	assert.True(t, IsPolicyInShadowMode(ShadowModeRateLimitPolicy))
+	assert.False(t, IsPolicyInShadowMode(ShadowModeClaimPolicyPrefix+"st-ev"))
+}
@@ -35,6 +35,16 @@ type superTokens struct {
 	RecipeModules         []RecipeModule
 	OnSuperTokensAPIError func(err error, req *http.Request, res http.ResponseWriter)
 	Telemetry             *bool
+	RateLimiter           *normalisedRateLimiter
+	IPExtractor           normalisedIPExtractor
+	ShadowMode            normalisedShadowMode
+	Experiments           normalisedExperiments
+	// ErrorResponseSerializer is nil unless set in the config
+	ErrorResponseSerializer ErrorResponseSerializer
+	Events                  normalisedEvents
+	AuditLogger             AuditLogger
+	OnUnexpectedError       func(report UnexpectedError, userContext UserContext)
+	Localization            normalisedLocalization
 }
 
 // this will be set to true if this is used in a test app environment
@@ -80,6 +90,24 @@ func supertokensInit(config TypeInput) error {
 		return err
 	}
 
+	superTokens.IPExtractor, err = normaliseIPExtractorInput(config.TrustedProxies, config.GetClientIP)
+	if err != nil {
+		return err
+	}
+
+	fipsMode, err = normaliseFIPSInput(config.FIPS)
+	if err != nil {
+		return err
+	}
+
+	jsonCodec = normaliseJSONCodecInput(config.JSONCodec)
+
+	egressProxy, err := normaliseEgressProxyInput(config.EgressProxy)
+	if err != nil {
+		return err
+	}
+	egressHTTPClient = makeEgressHTTPClient(egressProxy)
+
 	if config.Supertokens != nil {
 		if len(config.Supertokens.ConnectionURI) != 0 {
 			hostList := strings.Split(config.Supertokens.ConnectionURI, ";")
@@ -98,7 +126,7 @@ func supertokensInit(config TypeInput) error {
 					BasePath: basePath,
 				})
 			}
-			initQuerier(hosts, config.Supertokens.APIKey, config.Supertokens.NetworkInterceptor)
+			initQuerier(hosts, config.Supertokens.APIKey, config.Supertokens.NetworkInterceptor, config.Supertokens.Transport, config.RequestTimeout, egressProxy, config.Supertokens.LatencyRouting)
 			superTokens.SuperTokens = *config.Supertokens
 		} else {
 			return errors.New("please provide 'ConnectionURI' value. If you do not want to provide a connection URI, then set config.Supertokens to nil")
@@ -113,12 +141,21 @@ func supertokensInit(config TypeInput) error {
 
 	multitenancyFound := false
 
+	// post init callbacks added by each recipe are tracked so that the ones of
+	// a recipe run after the ones of the recipes it depends on
+	recipeModules := []RecipeModule{}
+	postInitCallbacksByRecipe := [][]func() error{}
+	postInitCallbacksBeforeRecipes := postInitCallbacks
+	postInitCallbacks = []func() error{}
+
 	for _, elem := range config.RecipeList {
 		recipeModule, err := elem(superTokens.AppInfo, superTokens.OnSuperTokensAPIError)
 		if err != nil {
 			return err
 		}
-		superTokens.RecipeModules = append(superTokens.RecipeModules, *recipeModule)
+		recipeModules = append(recipeModules, *recipeModule)
+		postInitCallbacksByRecipe = append(postInitCallbacksByRecipe, postInitCallbacks)
+		postInitCallbacks = []func() error{}
 
 		if recipeModule.GetRecipeID() == "multitenancy" {
 			multitenancyFound = true
@@ -130,16 +167,52 @@ func supertokensInit(config TypeInput) error {
 		if err != nil {
 			return err
 		}
-		superTokens.RecipeModules = append(superTokens.RecipeModules, *recipeModule)
+		recipeModules = append(recipeModules, *recipeModule)
+		postInitCallbacksByRecipe = append(postInitCallbacksByRecipe, postInitCallbacks)
+		postInitCallbacks = []func() error{}
+	}
+
+	order, err := getPostInitCallbackOrder(recipeModules)
+	if err != nil {
+		return err
+	}
+	// the routing order must not depend on the declared dependencies
+	superTokens.RecipeModules = recipeModules
+	postInitCallbacks = postInitCallbacksBeforeRecipes
+	for _, idx := range order {
+		postInitCallbacks = append(postInitCallbacks, postInitCallbacksByRecipe[idx]...)
 	}
 
 	superTokens.Telemetry = config.Telemetry
+	superTokens.RateLimiter, err = normaliseRateLimiterInput(config.RateLimiter)
+	if err != nil {
+		return err
+	}
+	superTokens.ShadowMode = normaliseShadowModeInput(config.ShadowMode)
+	superTokens.ErrorResponseSerializer = config.ErrorResponseSerializer
+	superTokens.Experiments, err = normaliseExperimentsInput(config.Experiments)
+	if err != nil {
+		return err
+	}
+	superTokens.Events, err = normaliseEventsInput(config.Events)
+	if err != nil {
+		return err
+	}
+	superTokens.AuditLogger = config.AuditLogger
+	superTokens.OnUnexpectedError = config.OnUnexpectedError
+	superTokens.Localization = normaliseLocalizationInput(config.Localization)
 	superTokensInstance = superTokens
 
 	return nil
 }
 
 func defaultOnSuperTokensAPIError(err error, req *http.Request, res http.ResponseWriter) {
+	if superTokensInstance != nil && superTokensInstance.ErrorResponseSerializer != nil {
+		sendErr := SendNon200ResponseWithMessage(res, err.Error(), 500)
+		if sendErr == nil {
+			return
+		}
+	}
 	http.Error(res, err.Error(), 500)
 }
 
@@ -158,12 +231,12 @@ func (s *superTokens) middleware(theirHandler http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		dw := MakeDoneWriter(w)
 		userContext := MakeDefaultUserContextFromAPI(r)
+		// set once a recipe handles the request
+		var audit *apiRequestAudit
+		defer s.recoverAPIPanic(&audit, r, dw, userContext)
 		reqURL, err := NewNormalisedURLPath(r.URL.Path)
 		if err != nil {
-			err = s.errorHandler(err, r, dw, userContext)
-			if err != nil && !dw.IsDone() {
-				s.OnSuperTokensAPIError(err, r, dw)
-			}
+			s.handleUnhandledError(s.errorHandler(err, r, dw, userContext), audit, r, dw, userContext)
 			return
 		}
 		path := s.AppInfo.APIGatewayPath.AppendPath(reqURL)
@@ -200,10 +273,7 @@ func (s *superTokens) middleware(theirHandler http.Handler) http.Handler {
 			id, tenantId, err := matchedRecipe.ReturnAPIIdIfCanHandleRequest(path, method, userContext)
 
 			if err != nil {
-				err = s.errorHandler(err, r, dw, userContext)
-				if err != nil && !dw.IsDone() {
-					s.OnSuperTokensAPIError(err, r, dw)
-				}
+				s.handleUnhandledError(s.errorHandler(err, r, dw, userContext), audit, r, dw, userContext)
 				return
 			}
 
@@ -214,22 +284,37 @@ func (s *superTokens) middleware(theirHandler http.Handler) http.Handler {
 			}
 
 			LogDebugMessage("middleware: Request being handled by recipe. ID is: " + *id)
+			audit = &apiRequestAudit{recipeID: matchedRecipe.GetRecipeID(), apiID: *id, tenantId: tenantId}
+			defer writeAPIRequestAuditLog(audit, dw, userContext)
 
 			tenantId, err = GetTenantIdFuncFromUsingMultitenancyRecipe(tenantId, userContext)
 			if err != nil {
-				err = s.errorHandler(err, r, dw, userContext)
-				if err != nil && !dw.IsDone() {
-					s.OnSuperTokensAPIError(err, r, dw)
-				}
+				audit.err = err
+				s.handleUnhandledError(s.errorHandler(err, r, dw, userContext), audit, r, dw, userContext)
+				return
+			}
+
+			limited, err := s.isRateLimited(RateLimitRequestInfo{
+				RecipeID: matchedRecipe.GetRecipeID(),
+				APIID:    *id,
+				TenantID: tenantId,
+				Request:  r,
+			}, dw, userContext)
+			audit.tenantId = tenantId
+			if err != nil {
+				audit.err = err
+				s.handleUnhandledError(s.errorHandler(err, r, dw, userContext), audit, r, dw, userContext)
+				return
+			}
+			if limited {
+				audit.rateLimited = true
 				return
 			}
 
 			apiErr := matchedRecipe.HandleAPIRequest(*id, tenantId, r, dw, theirHandler.ServeHTTP, path, method, userContext)
+			audit.err = apiErr
 			if apiErr != nil {
-				apiErr = s.errorHandler(apiErr, r, dw, userContext)
-				if apiErr != nil && !dw.IsDone() {
-					s.OnSuperTokensAPIError(apiErr, r, dw)
-				}
+				s.handleUnhandledError(s.errorHandler(apiErr, r, dw, userContext), audit, r, dw, userContext)
 				return
 			}
 			LogDebugMessage("middleware: Ended")
@@ -238,21 +323,33 @@ func (s *superTokens) middleware(theirHandler http.Handler) http.Handler {
 				id, tenantId, err := recipeModule.ReturnAPIIdIfCanHandleRequest(path, method, userContext)
 				LogDebugMessage("middleware: Checking recipe ID for match: " + recipeModule.GetRecipeID())
 				if err != nil {
-					err = s.errorHandler(err, r, dw, userContext)
-					if err != nil && !dw.IsDone() {
-						s.OnSuperTokensAPIError(err, r, dw)
-					}
+					s.handleUnhandledError(s.errorHandler(err, r, dw, userContext), audit, r, dw, userContext)
 					return
 				}
 
 				if id != nil {
 					LogDebugMessage("middleware: Request being handled by recipe. ID is: " + *id)
-					err := recipeModule.HandleAPIRequest(*id, tenantId, r, dw, theirHandler.ServeHTTP, path, method, userContext)
+					audit = &apiRequestAudit{recipeID: recipeModule.GetRecipeID(), apiID: *id, tenantId: tenantId}
+					defer writeAPIRequestAuditLog(audit, dw, userContext)
+					limited, err := s.isRateLimited(RateLimitRequestInfo{
+						RecipeID: recipeModule.GetRecipeID(),
+						APIID:    *id,
+						TenantID: tenantId,
+						Request:  r,
+					}, dw, userContext)
+					if err != nil {
+						audit.err = err
+						s.handleUnhandledError(s.errorHandler(err, r, dw, userContext), audit, r, dw, userContext)
+						return
+					}
+					if limited {
+						audit.rateLimited = true
+						return
+					}
+					err = recipeModule.HandleAPIRequest(*id, tenantId, r, dw, theirHandler.ServeHTTP, path, method, userContext)
+					audit.err = err
 					if err != nil {
-						err = s.errorHandler(err, r, dw, userContext)
-						if err != nil && !dw.IsDone() {
-							s.OnSuperTokensAPIError(err, r, dw)
-						}
+						s.handleUnhandledError(s.errorHandler(err, r, dw, userContext), audit, r, dw, userContext)
 					} else {
 						LogDebugMessage("middleware: Ended")
 					}
@@ -283,6 +380,11 @@ func (s *superTokens) getAllCORSHeaders() []string {
 
 func (s *superTokens) errorHandler(originalError error, req *http.Request, res http.ResponseWriter, userContext UserContext) error {
 	LogDebugMessage("errorHandler: Started")
+	var notSupportedErr CoreFeatureNotSupportedError
+	if errors.As(originalError, &notSupportedErr) {
+		LogDebugMessage("errorHandler: Sending 501 status code response")
+		return sendCoreFeatureNotSupportedResponse(notSupportedErr, res)
+	}
 	if errors.As(originalError, &BadInputError{}) {
 		LogDebugMessage("errorHandler: Sending 400 status code response")
 		err := SendNon200ResponseWithMessage(res, originalError.Error(), 400)
@@ -343,21 +445,8 @@ func GetUsersWithSearchParams(tenantId string, timeJoinedOrder string, paginatio
 		requestBody["includeRecipeIds"] = strings.Join((*includeRecipeIds)[:], ",")
 	}
 
-	resp, err := querier.SendGetRequest(tenantId+"/users", requestBody, nil)
-
-	if err != nil {
-		return UserPaginationResult{}, err
-	}
-
-	temporaryVariable, err := json.Marshal(resp)
-	if err != nil {
-		return UserPaginationResult{}, err
-	}
-
 	var result = UserPaginationResult{}
-
-	err = json.Unmarshal(temporaryVariable, &result)
-
+	err = querier.SendGetRequestInto(tenantId+"/users", requestBody, &result, nil)
 	if err != nil {
 		return UserPaginationResult{}, err
 	}
@@ -411,6 +500,16 @@ func deleteUser(userId string) error {
 			return err
 		}
 
+		userContext := &map[string]interface{}{}
+		EmitEvent(Event{
+			Type:   EventUserDeleted,
+			UserID: userId,
+		}, userContext)
+		WriteAuditLog(AuditEntry{
+			Action: AuditActionUserDeleted,
+			Result: AuditResultSuccess,
+			UserID: userId,
+		}, userContext)
 		return nil
 	} else {
 		return errors.New("please upgrade the SuperTokens core to >= 3.7.0")
@@ -418,7 +517,13 @@ func deleteUser(userId string) error {
 }
 
 func ResetForTest() {
+	resetBackgroundWorkersForTest()
 	ResetQuerierForTest()
+	if superTokensInstance != nil && superTokensInstance.Events.webhook != nil {
+		superTokensInstance.Events.webhook.buffer.stop()
+	}
+	fipsMode = normalisedFIPSMode{enabled: fipsModeFromBuildTag}
+	jsonCodec = stdJSONCodec{}
 	superTokensInstance = nil
 }
 
@@ -443,5 +548,30 @@ func getRequestFromUserContext(userContext UserContext) *http.Request {
 		return nil
 	}
 
-	return defaultObj.(map[string]interface{})["request"].(*http.Request)
+	req, _ := defaultObj.(map[string]interface{})["request"].(*http.Request)
+	return req
+}
+
+func setIsSignUpInUserContext(userContext UserContext) {
+	if userContext == nil {
+		return
+	}
+	defaultObj, ok := (*userContext)["_default"].(map[string]interface{})
+	if !ok {
+		defaultObj = map[string]interface{}{}
+		(*userContext)["_default"] = defaultObj
+	}
+	defaultObj["isSignUp"] = true
+}
+
+func isSignUpFromUserContext(userContext UserContext) bool {
+	if userContext == nil {
+		return false
+	}
+	defaultObj, ok := (*userContext)["_default"].(map[string]interface{})
+	if !ok {
+		return false
+	}
+	isSignUp, _ := defaultObj["isSignUp"].(bool)
+	return isSignUp
 }
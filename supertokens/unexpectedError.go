@@ -0,0 +1,103 @@
+/* Copyright (c) 2023, VRAI Labs and/or its affiliates. All rights reserved.
+ *
+ * This software is licensed under the Apache License, Version 2.0 (the
+ * "License") as published by the Apache Software Foundation.
+ *
+ * You may not use this file except in compliance with the License. You may
+ * obtain a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+ * License for the specific language governing permissions and limitations
+ * under the License.
+ */
+
+package supertokens
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"runtime/debug"
+)
+
+// UnexpectedError is passed to OnUnexpectedError for errors that no recipe
+// handled and for panics in the handling of SuperTokens APIs, e.g. to send
+// them to an error tracker like Sentry or Rollbar.
+type UnexpectedError struct {
+	Err error
+	// PanicValue is the value passed to panic, or nil if Err was returned
+	PanicValue interface{}
+	Stack      []byte
+	Request    *http.Request
+	// RecipeID, APIID and TenantId are empty if the error happened before
+	// the API handling the request was known
+	RecipeID string
+	APIID    string
+	TenantId string
+}
+
+// reportUnexpectedError calls OnUnexpectedError, if set. Panics in the
+// callback are logged and ignored.
+func (s *superTokens) reportUnexpectedError(report UnexpectedError, userContext UserContext) {
+	if s.OnUnexpectedError == nil {
+		return
+	}
+	if report.Stack == nil {
+		report.Stack = debug.Stack()
+	}
+	defer func() {
+		if r := recover(); r != nil {
+			LogDebugMessage(fmt.Sprintf("OnUnexpectedError panicked: %v", r))
+		}
+	}()
+	s.OnUnexpectedError(report, userContext)
+}
+
+// handleUnhandledError reports an error that errorHandler returned and sends
+// it to OnSuperTokensAPIError if no response was sent yet
+func (s *superTokens) handleUnhandledError(err error, audit *apiRequestAudit, req *http.Request, dw DoneWriter, userContext UserContext) {
+	if err == nil {
+		return
+	}
+	report := UnexpectedError{Err: err, Request: req}
+	if audit != nil {
+		report.RecipeID, report.APIID, report.TenantId = audit.recipeID, audit.apiID, audit.tenantId
+	}
+	s.reportUnexpectedError(report, userContext)
+	if !dw.IsDone() {
+		s.OnSuperTokensAPIError(err, req, dw)
+	}
+}
+
+// recoverAPIPanic is deferred by the middleware so that a panic while
+// handling a SuperTokens API is reported and answered with a 500 instead of
+// crashing the request's goroutine. Panics in requests that no recipe
+// handles, and http.ErrAbortHandler, are passed on.
+func (s *superTokens) recoverAPIPanic(audit **apiRequestAudit, req *http.Request, dw DoneWriter, userContext UserContext) {
+	r := recover()
+	if r == nil {
+		return
+	}
+	if *audit == nil || r == http.ErrAbortHandler {
+		panic(r)
+	}
+	err, ok := r.(error)
+	if !ok {
+		err = fmt.Errorf("%v", r)
+	}
+	err = fmt.Errorf("panic while handling %s: %w", (*audit).apiID, err)
+	s.reportUnexpectedError(UnexpectedError{
+		Err:        err,
+		PanicValue: r,
+		Stack:      debug.Stack(),
+		Request:    req,
+		RecipeID:   (*audit).recipeID,
+		APIID:      (*audit).apiID,
+		TenantId:   (*audit).tenantId,
+	}, userContext)
+	if !dw.IsDone() {
+		s.OnSuperTokensAPIError(errors.New("internal server error"), req, dw)
+	}
+}
@@ -0,0 +1,105 @@
+/* Copyright (c) 2023, VRAI Labs and/or its affiliates. All rights reserved.
+ *
+ * This software is licensed under the Apache License, Version 2.0 (the
+ * "License") as published by the Apache Software Foundation.
+ *
+ * You may not use this file except in compliance with the License. You may
+ * obtain a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+ * License for the specific language governing permissions and limitations
+ * under the License.
+ */
+
+package supertokens
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func initWithUnexpectedErrorHook(reports *[]UnexpectedError) error {
+	ResetForTest()
+	postInitCallbacks = []func() error{}
+	handler := func(result func() error) func(tenantId string, req *http.Request, res http.ResponseWriter, userContext UserContext) error {
+		return func(tenantId string, req *http.Request, res http.ResponseWriter, userContext UserContext) error {
+			return result()
+		}
+	}
+	return Init(TypeInput{
+		AppInfo: AppInfo{
+			AppName:       "SuperTokens",
+			APIDomain:     "api.supertokens.io",
+			WebsiteDomain: "supertokens.io",
+		},
+		RecipeList: []Recipe{MakeCustomRecipe(CustomRecipeConfig{
+			RecipeID: "custom",
+			APIs: []CustomRecipeAPI{
+				{Method: http.MethodGet, Path: "/panic", Handler: handler(func() error { panic("boom") })},
+				{Method: http.MethodGet, Path: "/error", Handler: handler(func() error { return errors.New("unhandled") })},
+			},
+		})},
+		OnUnexpectedError: func(report UnexpectedError, userContext UserContext) {
+			*reports = append(*reports, report)
+		},
+	})
+}
+
+func TestPanicsInRecipeAPIsAreRecoveredAndReported(t *testing.T) {
+	defer ResetForTest()
+	reports := []UnexpectedError{}
+	err := initWithUnexpectedErrorHook(&reports)
+	assert.NoError(t, err)
+
+	res := httptest.NewRecorder()
+	Middleware(nil).ServeHTTP(res, httptest.NewRequest(http.MethodGet, "/auth/panic", nil))
+
+	assert.Equal(t, http.StatusInternalServerError, res.Code)
+	assert.NotContains(t, res.Body.String(), "boom")
+	assert.Len(t, reports, 1)
+	assert.Equal(t, "boom", reports[0].PanicValue)
+	assert.Equal(t, "custom", reports[0].RecipeID)
+	assert.Equal(t, "GET /panic", reports[0].APIID)
+	assert.Equal(t, "/auth/panic", reports[0].Request.URL.Path)
+	assert.Contains(t, reports[0].Err.Error(), "boom")
+	assert.True(t, strings.Contains(string(reports[0].Stack), "unexpectedError_test.go"))
+}
+
+func TestUnhandledErrorsAreReported(t *testing.T) {
+	defer ResetForTest()
+	reports := []UnexpectedError{}
+	err := initWithUnexpectedErrorHook(&reports)
+	assert.NoError(t, err)
+
+	res := httptest.NewRecorder()
+	Middleware(nil).ServeHTTP(res, httptest.NewRequest(http.MethodGet, "/auth/error", nil))
+
+	assert.Equal(t, http.StatusInternalServerError, res.Code)
+	assert.Len(t, reports, 1)
+	assert.EqualError(t, reports[0].Err, "unhandled")
+	assert.Nil(t, reports[0].PanicValue)
+	assert.Equal(t, "GET /error", reports[0].APIID)
+	assert.NotEmpty(t, reports[0].Stack)
+}
+
+func TestPanicsOutsideSuperTokensAPIsAreNotRecovered(t *testing.T) {
+	defer ResetForTest()
+	reports := []UnexpectedError{}
+	err := initWithUnexpectedErrorHook(&reports)
+	assert.NoError(t, err)
+
+	handler := Middleware(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		panic("app")
+	}))
+	assert.PanicsWithValue(t, "app", func() {
+		handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/auth/other", nil))
+	})
+	assert.Empty(t, reports)
+}
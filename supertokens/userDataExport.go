@@ -0,0 +1,189 @@
+/* Copyright (c) 2023, VRAI Labs and/or its affiliates. All rights reserved.
+ *
+ * This software is licensed under the Apache License, Version 2.0 (the
+ * "License") as published by the Apache Software Foundation.
+ *
+ * You may not use this file except in compliance with the License. You may
+ * obtain a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+ * License for the specific language governing permissions and limitations
+ * under the License.
+ */
+
+package supertokens
+
+import (
+	"sort"
+	"time"
+)
+
+// UserDataExport is everything the core stores about a user, as returned by
+// ExportUserData. It is meant to be marshalled to JSON and sent to the user,
+// e.g. to answer a GDPR data subject access request.
+type UserDataExport struct {
+	UserID        string                 `json:"userId"`
+	ExportedAt    int64                  `json:"exportedAt"`
+	UserIdMapping *UserIdMappingExport   `json:"userIdMapping,omitempty"`
+	LoginMethods  []LoginMethodExport    `json:"loginMethods"`
+	Sessions      []SessionExport        `json:"sessions"`
+	Metadata      map[string]interface{} `json:"metadata"`
+	// Roles maps the tenants of the user to their roles in that tenant
+	Roles map[string][]string `json:"roles"`
+}
+
+type UserIdMappingExport struct {
+	SupertokensUserId  string  `json:"supertokensUserId"`
+	ExternalUserId     string  `json:"externalUserId"`
+	ExternalUserIdInfo *string `json:"externalUserIdInfo,omitempty"`
+}
+
+type LoginMethodExport struct {
+	RecipeID string `json:"recipeId"`
+	// User is the user as returned by the core for this recipe (email,
+	// phone number, third party info, tenants and time joined)
+	User map[string]interface{} `json:"user"`
+}
+
+type SessionExport struct {
+	SessionHandle         string                 `json:"sessionHandle"`
+	TenantId              string                 `json:"tenantId"`
+	TimeCreated           uint64                 `json:"timeCreated"`
+	Expiry                uint64                 `json:"expiry"`
+	SessionDataInDatabase map[string]interface{} `json:"sessionDataInDatabase"`
+	AccessTokenPayload    map[string]interface{} `json:"accessTokenPayload"`
+}
+
+// loginMethodRecipeIDs are the recipes whose users are stored by the core
+var loginMethodRecipeIDs = []string{"emailpassword", "thirdparty", "passwordless"}
+
+// ExportUserData collects the user's login methods, sessions across all
+// tenants, metadata, roles and user ID mapping from the core. Data of
+// recipes that are not used by the app is empty.
+func ExportUserData(userID string, userContext ...UserContext) (UserDataExport, error) {
+	if len(userContext) == 0 {
+		userContext = append(userContext, &map[string]interface{}{})
+	}
+	result := UserDataExport{
+		UserID:       userID,
+		ExportedAt:   time.Now().UnixMilli(),
+		LoginMethods: []LoginMethodExport{},
+		Sessions:     []SessionExport{},
+		Metadata:     map[string]interface{}{},
+		Roles:        map[string][]string{},
+	}
+
+	supported, err := CoreSupports(CoreFeatureUserIdMapping)
+	if err != nil {
+		return UserDataExport{}, err
+	}
+	if supported {
+		mapping, err := GetUserIdMapping(userID, nil)
+		if err != nil {
+			return UserDataExport{}, err
+		}
+		if mapping.OK != nil {
+			result.UserIdMapping = &UserIdMappingExport{
+				SupertokensUserId:  mapping.OK.SupertokensUserId,
+				ExternalUserId:     mapping.OK.ExternalUserId,
+				ExternalUserIdInfo: mapping.OK.ExternalUserIdInfo,
+			}
+		}
+	}
+
+	tenantIds := map[string]bool{}
+	for _, recipeID := range loginMethodRecipeIDs {
+		querier, err := GetNewQuerierInstanceOrThrowError(recipeID)
+		if err != nil {
+			return UserDataExport{}, err
+		}
+		response, err := querier.SendGetRequest("/recipe/user", map[string]string{"userId": userID}, userContext[0])
+		if err != nil {
+			return UserDataExport{}, err
+		}
+		user, ok := response["user"].(map[string]interface{})
+		if response["status"] != "OK" || !ok {
+			continue
+		}
+		result.LoginMethods = append(result.LoginMethods, LoginMethodExport{RecipeID: recipeID, User: user})
+		if userTenantIds, ok := user["tenantIds"].([]interface{}); ok {
+			for _, tenantId := range userTenantIds {
+				if tenantId, ok := tenantId.(string); ok {
+					tenantIds[tenantId] = true
+				}
+			}
+		}
+	}
+	if len(tenantIds) == 0 {
+		tenantIds[DefaultTenantId] = true
+	}
+
+	querier, err := GetNewQuerierInstanceOrThrowError("")
+	if err != nil {
+		return UserDataExport{}, err
+	}
+	sessionHandles, err := querier.SendGetRequest("/recipe/session/user", map[string]string{
+		"userId":                userID,
+		"fetchAcrossAllTenants": "true",
+	}, userContext[0])
+	if err != nil {
+		return UserDataExport{}, err
+	}
+	handles, _ := sessionHandles["sessionHandles"].([]interface{})
+	for _, handle := range handles {
+		handle, ok := handle.(string)
+		if !ok {
+			continue
+		}
+		response, err := querier.SendGetRequest("/recipe/session", map[string]string{"sessionHandle": handle}, userContext[0])
+		if err != nil {
+			return UserDataExport{}, err
+		}
+		if response["status"] != "OK" {
+			// the session expired or was revoked in the meantime
+			continue
+		}
+		session := SessionExport{SessionHandle: handle}
+		session.TenantId, _ = response["tenantId"].(string)
+		if timeCreated, ok := response["timeCreated"].(float64); ok {
+			session.TimeCreated = uint64(timeCreated)
+		}
+		if expiry, ok := response["expiry"].(float64); ok {
+			session.Expiry = uint64(expiry)
+		}
+		session.SessionDataInDatabase, _ = response["userDataInDatabase"].(map[string]interface{})
+		session.AccessTokenPayload, _ = response["userDataInJWT"].(map[string]interface{})
+		result.Sessions = append(result.Sessions, session)
+	}
+
+	metadata, err := querier.SendGetRequest("/recipe/user/metadata", map[string]string{"userId": userID}, userContext[0])
+	if err != nil {
+		return UserDataExport{}, err
+	}
+	if userMetadata, ok := metadata["metadata"].(map[string]interface{}); ok {
+		result.Metadata = userMetadata
+	}
+
+	sortedTenantIds := []string{}
+	for tenantId := range tenantIds {
+		sortedTenantIds = append(sortedTenantIds, tenantId)
+	}
+	sort.Strings(sortedTenantIds)
+	for _, tenantId := range sortedTenantIds {
+		response, err := querier.SendGetRequest(tenantId+"/recipe/user/roles", map[string]string{"userId": userID}, userContext[0])
+		if err != nil {
+			return UserDataExport{}, err
+		}
+		roles := []string{}
+		rawRoles, _ := response["roles"].([]interface{})
+		for _, role := range rawRoles {
+			if role, ok := role.(string); ok {
+				roles = append(roles, role)
+			}
+		}
+		result.Roles[tenantId] = roles
+	}
+	return result, nil
+}
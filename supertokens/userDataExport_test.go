@@ -0,0 +1,112 @@
+/* Copyright (c) 2023, VRAI Labs and/or its affiliates. All rights reserved.
+ *
+ * This software is licensed under the Apache License, Version 2.0 (the
+ * "License") as published by the Apache Software Foundation.
+ *
+ * You may not use this file except in compliance with the License. You may
+ * obtain a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+ * License for the specific language governing permissions and limitations
+ * under the License.
+ */
+
+package supertokens
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExportUserDataAggregatesCoreData(t *testing.T) {
+	respond := func(rw http.ResponseWriter, body map[string]interface{}) {
+		json.NewEncoder(rw).Encode(body)
+	}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/apiversion", func(rw http.ResponseWriter, r *http.Request) {
+		respond(rw, map[string]interface{}{"versions": cdiSupported})
+	})
+	mux.HandleFunc("/recipe/userid/map", func(rw http.ResponseWriter, r *http.Request) {
+		respond(rw, map[string]interface{}{"status": "UNKNOWN_MAPPING_ERROR"})
+	})
+	mux.HandleFunc("/recipe/user", func(rw http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "user1", r.URL.Query().Get("userId"))
+		if r.Header.Get("rid") != "emailpassword" {
+			respond(rw, map[string]interface{}{"status": "UNKNOWN_USER_ID_ERROR"})
+			return
+		}
+		respond(rw, map[string]interface{}{"status": "OK", "user": map[string]interface{}{
+			"id":         "user1",
+			"email":      "user1@example.com",
+			"timeJoined": 1700000000000,
+			"tenantIds":  []string{"public", "customer1"},
+		}})
+	})
+	mux.HandleFunc("/recipe/session/user", func(rw http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "true", r.URL.Query().Get("fetchAcrossAllTenants"))
+		respond(rw, map[string]interface{}{"status": "OK", "sessionHandles": []string{"handle1", "expired"}})
+	})
+	mux.HandleFunc("/recipe/session", func(rw http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("sessionHandle") == "expired" {
+			respond(rw, map[string]interface{}{"status": "UNAUTHORISED"})
+			return
+		}
+		respond(rw, map[string]interface{}{
+			"status":             "OK",
+			"sessionHandle":      "handle1",
+			"userId":             "user1",
+			"tenantId":           "public",
+			"timeCreated":        1700000000000,
+			"expiry":             1800000000000,
+			"userDataInDatabase": map[string]interface{}{"device": "laptop"},
+			"userDataInJWT":      map[string]interface{}{"role": "admin"},
+		})
+	})
+	mux.HandleFunc("/recipe/user/metadata", func(rw http.ResponseWriter, r *http.Request) {
+		respond(rw, map[string]interface{}{"status": "OK", "metadata": map[string]interface{}{"firstName": "Jane"}})
+	})
+	mux.HandleFunc("/public/recipe/user/roles", func(rw http.ResponseWriter, r *http.Request) {
+		respond(rw, map[string]interface{}{"status": "OK", "roles": []string{"admin"}})
+	})
+	mux.HandleFunc("/customer1/recipe/user/roles", func(rw http.ResponseWriter, r *http.Request) {
+		respond(rw, map[string]interface{}{"status": "OK", "roles": []string{}})
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	domain, err := NewNormalisedURLDomain(server.URL)
+	assert.NoError(t, err)
+	basePath, err := NewNormalisedURLPath(server.URL)
+	assert.NoError(t, err)
+	ResetQuerierForTest()
+	defer ResetQuerierForTest()
+	initQuerier([]QuerierHost{{Domain: domain, BasePath: basePath}}, "", nil, nil, 0, nil, nil)
+
+	export, err := ExportUserData("user1")
+	assert.NoError(t, err)
+	assert.Equal(t, "user1", export.UserID)
+	assert.NotZero(t, export.ExportedAt)
+	assert.Nil(t, export.UserIdMapping)
+	assert.Len(t, export.LoginMethods, 1)
+	assert.Equal(t, "emailpassword", export.LoginMethods[0].RecipeID)
+	assert.Equal(t, "user1@example.com", export.LoginMethods[0].User["email"])
+	assert.Equal(t, []SessionExport{{
+		SessionHandle:         "handle1",
+		TenantId:              "public",
+		TimeCreated:           1700000000000,
+		Expiry:                1800000000000,
+		SessionDataInDatabase: map[string]interface{}{"device": "laptop"},
+		AccessTokenPayload:    map[string]interface{}{"role": "admin"},
+	}}, export.Sessions)
+	assert.Equal(t, map[string]interface{}{"firstName": "Jane"}, export.Metadata)
+	assert.Equal(t, map[string][]string{"public": {"admin"}, "customer1": {}}, export.Roles)
+
+	_, err = json.Marshal(export)
+	assert.NoError(t, err)
+}
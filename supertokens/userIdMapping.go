@@ -1,9 +1,5 @@
 package supertokens
 
-import (
-	"errors"
-)
-
 type UserIdType string
 
 const (
@@ -26,13 +22,10 @@ func CreateUserIdMapping(supertokensUserId string, externalUserId string, extern
 	if err != nil {
 		return CreateUserIdMappingResult{}, err
 	}
-	cdiVersion, err := querier.GetQuerierAPIVersion()
+	err = RequireCoreFeature(CoreFeatureUserIdMapping)
 	if err != nil {
 		return CreateUserIdMappingResult{}, err
 	}
-	if MaxVersion(cdiVersion, "2.15") != cdiVersion {
-		return CreateUserIdMappingResult{}, errors.New("Please upgrade the SuperTokens core to >= 3.15.0")
-	}
 
 	data := map[string]interface{}{
 		"superTokensUserId": supertokensUserId,
@@ -84,13 +77,10 @@ func GetUserIdMapping(userId string, userIdType *UserIdType) (GetUserIdMappingRe
 	if err != nil {
 		return GetUserIdMappingResult{}, err
 	}
-	cdiVersion, err := querier.GetQuerierAPIVersion()
+	err = RequireCoreFeature(CoreFeatureUserIdMapping)
 	if err != nil {
 		return GetUserIdMappingResult{}, err
 	}
-	if MaxVersion(cdiVersion, "2.15") != cdiVersion {
-		return GetUserIdMappingResult{}, errors.New("Please upgrade the SuperTokens core to >= 3.15.0")
-	}
 
 	data := map[string]string{
 		"userId": userId,
@@ -136,13 +126,10 @@ func DeleteUserIdMapping(userId string, userIdType *UserIdType, force *bool) (De
 	if err != nil {
 		return DeleteUserIdMappingResult{}, err
 	}
-	cdiVersion, err := querier.GetQuerierAPIVersion()
+	err = RequireCoreFeature(CoreFeatureUserIdMapping)
 	if err != nil {
 		return DeleteUserIdMappingResult{}, err
 	}
-	if MaxVersion(cdiVersion, "2.15") != cdiVersion {
-		return DeleteUserIdMappingResult{}, errors.New("Please upgrade the SuperTokens core to >= 3.15.0")
-	}
 
 	data := map[string]interface{}{
 		"userId": userId,
@@ -174,13 +161,10 @@ func UpdateOrDeleteUserIdMappingInfo(userId string, userIdType *UserIdType, exte
 	if err != nil {
 		return UpdateOrDeleteUserIdMappingInfoResult{}, err
 	}
-	cdiVersion, err := querier.GetQuerierAPIVersion()
+	err = RequireCoreFeature(CoreFeatureUserIdMapping)
 	if err != nil {
 		return UpdateOrDeleteUserIdMappingInfoResult{}, err
 	}
-	if MaxVersion(cdiVersion, "2.15") != cdiVersion {
-		return UpdateOrDeleteUserIdMappingInfoResult{}, errors.New("Please upgrade the SuperTokens core to >= 3.15.0")
-	}
 
 	data := map[string]interface{}{
 		"userId":             userId,
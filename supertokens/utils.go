@@ -119,6 +119,7 @@ func NormaliseInputAppInfoOrThrowError(appInfo AppInfo) (NormalisedAppinfo, erro
 		AppName:                  appInfo.AppName,
 		APIGatewayPath:           apiGatewayPath,
 		GetOrigin:                websiteDomainFunction,
+		HasStaticOrigin:          appInfo.GetOrigin == nil,
 		APIDomain:                apiDomain,
 		APIBasePath:              apiBasePath,
 		TopLevelAPIDomain:        topLevelAPIDomain,
@@ -217,15 +218,13 @@ func SendNon200Response(res http.ResponseWriter, statusCode int, body map[string
 
 		LogDebugMessage("Sending response to client with status code: " + strconv.Itoa(statusCode))
 
-		res.Header().Set("Content-Type", "application/json; charset=utf-8")
-		res.WriteHeader(statusCode)
-
-		bytes, err := json.Marshal(body)
+		contentType, bytes, err := getErrorResponseSerializer()(statusCode, body)
 		if err != nil {
 			return err
-		} else {
-			res.Write(bytes)
 		}
+		res.Header().Set("Content-Type", contentType)
+		res.WriteHeader(statusCode)
+		res.Write(bytes)
 	}
 	return nil
 }
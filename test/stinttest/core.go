@@ -0,0 +1,156 @@
+/* Copyright (c) 2023, VRAI Labs and/or its affiliates. All rights reserved.
+ *
+ * This software is licensed under the Apache License, Version 2.0 (the
+ * "License") as published by the Apache Software Foundation.
+ *
+ * You may not use this file except in compliance with the License. You may
+ * obtain a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+ * License for the specific language governing permissions and limitations
+ * under the License.
+ */
+
+// Package stinttest runs a SuperTokens core in docker and offers helpers to
+// write integration tests of apps that use this SDK against it.
+package stinttest
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+)
+
+const defaultCoreImage = "registry.supertokens.io/supertokens/supertokens-postgresql:latest"
+const corePort = "3567/tcp"
+const defaultStartupTimeout = 60 * time.Second
+
+// ConnectionURIEnvVar can point to an already running core, e.g. in CI, in
+// which case StartCore uses it instead of starting a container
+const ConnectionURIEnvVar = "STINTTEST_CONNECTION_URI"
+
+type CoreOptions struct {
+	// Image defaults to the postgresql core image, which uses an in memory
+	// database when no database is configured
+	Image string
+	// Env is passed to the container, e.g. to configure a database or to set
+	// core config values
+	Env            map[string]string
+	StartupTimeout time.Duration
+}
+
+// Core is a SuperTokens core running in a docker container
+type Core struct {
+	ConnectionURI string
+	containerID   string
+}
+
+// StartCore starts a core with the docker CLI and waits until it responds
+func StartCore(ctx context.Context, options CoreOptions) (*Core, error) {
+	if connectionURI := os.Getenv(ConnectionURIEnvVar); connectionURI != "" {
+		return &Core{ConnectionURI: connectionURI}, nil
+	}
+
+	image := options.Image
+	if image == "" {
+		image = defaultCoreImage
+	}
+	startupTimeout := options.StartupTimeout
+	if startupTimeout == 0 {
+		startupTimeout = defaultStartupTimeout
+	}
+
+	args := []string{"run", "-d", "--rm", "-p", "127.0.0.1::" + corePort}
+	for key, value := range options.Env {
+		args = append(args, "-e", key+"="+value)
+	}
+	args = append(args, image)
+	containerID, err := runDocker(ctx, args...)
+	if err != nil {
+		return nil, err
+	}
+	core := &Core{containerID: containerID}
+
+	portOutput, err := runDocker(ctx, "port", containerID, corePort)
+	if err != nil {
+		core.Stop()
+		return nil, err
+	}
+	hostPort, err := parseDockerPortOutput(portOutput)
+	if err != nil {
+		core.Stop()
+		return nil, err
+	}
+	core.ConnectionURI = "http://" + hostPort
+
+	err = waitForCore(ctx, core.ConnectionURI, startupTimeout)
+	if err != nil {
+		core.Stop()
+		return nil, err
+	}
+	return core, nil
+}
+
+// Stop removes the core's container
+func (c *Core) Stop() error {
+	if c.containerID == "" {
+		return nil
+	}
+	_, err := runDocker(context.Background(), "rm", "-f", c.containerID)
+	return err
+}
+
+func runDocker(ctx context.Context, args ...string) (string, error) {
+	var stdout, stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, "docker", args...)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	err := cmd.Run()
+	if err != nil {
+		return "", fmt.Errorf("docker %s failed: %w: %s", args[0], err, strings.TrimSpace(stderr.String()))
+	}
+	return strings.TrimSpace(stdout.String()), nil
+}
+
+// parseDockerPortOutput returns the first IPv4 mapping printed by docker port,
+// e.g. "127.0.0.1:49153"
+func parseDockerPortOutput(output string) (string, error) {
+	for _, line := range strings.Split(output, "\n") {
+		line = strings.TrimSpace(line)
+		if line != "" && !strings.HasPrefix(line, "[") {
+			return strings.Replace(line, "0.0.0.0:", "127.0.0.1:", 1), nil
+		}
+	}
+	return "", errors.New("could not find the port of the core container in: " + output)
+}
+
+func waitForCore(ctx context.Context, connectionURI string, timeout time.Duration) error {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	for {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, connectionURI+"/hello", nil)
+		if err != nil {
+			return err
+		}
+		resp, err := http.DefaultClient.Do(req)
+		if err == nil {
+			resp.Body.Close()
+			if resp.StatusCode == http.StatusOK {
+				return nil
+			}
+		}
+		select {
+		case <-ctx.Done():
+			return errors.New("the SuperTokens core did not start in time")
+		case <-time.After(500 * time.Millisecond):
+		}
+	}
+}
@@ -0,0 +1,44 @@
+/* Copyright (c) 2023, VRAI Labs and/or its affiliates. All rights reserved.
+ *
+ * This software is licensed under the Apache License, Version 2.0 (the
+ * "License") as published by the Apache Software Foundation.
+ *
+ * You may not use this file except in compliance with the License. You may
+ * obtain a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+ * License for the specific language governing permissions and limitations
+ * under the License.
+ */
+
+package stinttest
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseDockerPortOutput(t *testing.T) {
+	hostPort, err := parseDockerPortOutput("0.0.0.0:49153\n[::]:49153")
+	assert.NoError(t, err)
+	assert.Equal(t, "127.0.0.1:49153", hostPort)
+
+	hostPort, err = parseDockerPortOutput("[::]:49154\n127.0.0.1:49154\n")
+	assert.NoError(t, err)
+	assert.Equal(t, "127.0.0.1:49154", hostPort)
+
+	_, err = parseDockerPortOutput("")
+	assert.Error(t, err)
+}
+
+func TestStartCoreUsesConnectionURIFromEnv(t *testing.T) {
+	t.Setenv(ConnectionURIEnvVar, "http://localhost:3567")
+	core, err := StartCore(context.Background(), CoreOptions{})
+	assert.NoError(t, err)
+	assert.Equal(t, "http://localhost:3567", core.ConnectionURI)
+	assert.NoError(t, core.Stop())
+}
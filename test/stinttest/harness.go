@@ -0,0 +1,131 @@
+/* Copyright (c) 2023, VRAI Labs and/or its affiliates. All rights reserved.
+ *
+ * This software is licensed under the Apache License, Version 2.0 (the
+ * "License") as published by the Apache Software Foundation.
+ *
+ * You may not use this file except in compliance with the License. You may
+ * obtain a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+ * License for the specific language governing permissions and limitations
+ * under the License.
+ */
+
+package stinttest
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/supertokens/supertokens-golang/recipe/dashboard"
+	"github.com/supertokens/supertokens-golang/recipe/emailpassword"
+	"github.com/supertokens/supertokens-golang/recipe/emailverification"
+	"github.com/supertokens/supertokens-golang/recipe/fraudprevention"
+	"github.com/supertokens/supertokens-golang/recipe/jwt"
+	"github.com/supertokens/supertokens-golang/recipe/multitenancy"
+	"github.com/supertokens/supertokens-golang/recipe/openid"
+	"github.com/supertokens/supertokens-golang/recipe/passwordless"
+	"github.com/supertokens/supertokens-golang/recipe/profile"
+	"github.com/supertokens/supertokens-golang/recipe/session"
+	"github.com/supertokens/supertokens-golang/recipe/thirdparty"
+	"github.com/supertokens/supertokens-golang/recipe/thirdpartyemailpassword"
+	"github.com/supertokens/supertokens-golang/recipe/thirdpartypasswordless"
+	"github.com/supertokens/supertokens-golang/recipe/usermetadata"
+	"github.com/supertokens/supertokens-golang/recipe/userroles"
+	"github.com/supertokens/supertokens-golang/supertokens"
+)
+
+type Options struct {
+	RecipeList []supertokens.Recipe
+	// AppInfo defaults to an app served by the harness' test server
+	AppInfo *supertokens.AppInfo
+	// Handler serves the app's own routes behind supertokens.Middleware
+	Handler http.Handler
+	// MakeHandler is used instead of Handler for apps whose routes can only
+	// be made once the SDK is initialised. It is called after supertokens.Init.
+	MakeHandler func() http.Handler
+}
+
+// Harness is an initialised SDK together with a test server running the
+// SuperTokens middleware
+type Harness struct {
+	Core   *Core
+	Server *httptest.Server
+	t      testing.TB
+}
+
+// Setup initialises the SDK against core and starts a test server. Both are
+// reset when the test ends.
+func Setup(t testing.TB, core *Core, options Options) *Harness {
+	t.Helper()
+	ResetSDK()
+
+	// the app's handler can only be made once the SDK is initialised, which
+	// needs the URL of the server
+	var handler http.Handler
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		handler.ServeHTTP(rw, r)
+	}))
+	t.Cleanup(func() {
+		server.Close()
+		ResetSDK()
+	})
+
+	appInfo := supertokens.AppInfo{
+		AppName:       "stinttest",
+		APIDomain:     server.URL,
+		WebsiteDomain: server.URL,
+	}
+	if options.AppInfo != nil {
+		appInfo = *options.AppInfo
+	}
+
+	err := supertokens.Init(supertokens.TypeInput{
+		Supertokens: &supertokens.ConnectionInfo{
+			ConnectionURI: core.ConnectionURI,
+		},
+		AppInfo:    appInfo,
+		RecipeList: options.RecipeList,
+	})
+	if err != nil {
+		t.Fatalf("supertokens.Init failed: %s", err.Error())
+	}
+
+	appHandler := http.NotFoundHandler()
+	if options.MakeHandler != nil {
+		appHandler = options.MakeHandler()
+	} else if options.Handler != nil {
+		appHandler = options.Handler
+	}
+	handler = supertokens.Middleware(appHandler)
+
+	return &Harness{
+		Core:   core,
+		Server: server,
+		t:      t,
+	}
+}
+
+// ResetSDK clears the state of the SDK and of all recipes so that it can be
+// initialised again
+func ResetSDK() {
+	supertokens.ResetForTest()
+	dashboard.ResetForTest()
+	emailpassword.ResetForTest()
+	emailverification.ResetForTest()
+	fraudprevention.ResetForTest()
+	jwt.ResetForTest()
+	multitenancy.ResetForTest()
+	openid.ResetForTest()
+	passwordless.ResetForTest()
+	profile.ResetForTest()
+	session.ResetForTest()
+	thirdparty.ResetForTest()
+	thirdpartyemailpassword.ResetForTest()
+	thirdpartypasswordless.ResetForTest()
+	usermetadata.ResetForTest()
+	userroles.ResetForTest()
+}
@@ -0,0 +1,174 @@
+/* Copyright (c) 2023, VRAI Labs and/or its affiliates. All rights reserved.
+ *
+ * This software is licensed under the Apache License, Version 2.0 (the
+ * "License") as published by the Apache Software Foundation.
+ *
+ * You may not use this file except in compliance with the License. You may
+ * obtain a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+ * License for the specific language governing permissions and limitations
+ * under the License.
+ */
+
+package stinttest
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+
+	"github.com/supertokens/supertokens-golang/recipe/emailverification"
+	"github.com/supertokens/supertokens-golang/recipe/session"
+)
+
+const defaultTenantId = "public"
+
+// TestSession holds the tokens of a session created through the harness.
+// Sessions use header based auth so that tokens can be read from responses.
+type TestSession struct {
+	UserID        string
+	Email         string
+	SessionHandle string
+	AccessToken   string
+	RefreshToken  string
+	FrontToken    string
+}
+
+// SignUp signs up a user with the emailpassword APIs
+func (h *Harness) SignUp(email string, password string) *TestSession {
+	h.t.Helper()
+	return h.emailPasswordRequest("/auth/signup", email, password)
+}
+
+// SignIn signs in a user with the emailpassword APIs
+func (h *Harness) SignIn(email string, password string) *TestSession {
+	h.t.Helper()
+	return h.emailPasswordRequest("/auth/signin", email, password)
+}
+
+// VerifyEmail marks the email of the session's user as verified
+func (h *Harness) VerifyEmail(s *TestSession) {
+	h.t.Helper()
+	tokenResponse, err := emailverification.CreateEmailVerificationToken(defaultTenantId, s.UserID, &s.Email)
+	if err != nil {
+		h.t.Fatalf("creating an email verification token failed: %s", err.Error())
+	}
+	if tokenResponse.EmailAlreadyVerifiedError != nil {
+		return
+	}
+	verifyResponse, err := emailverification.VerifyEmailUsingToken(defaultTenantId, tokenResponse.OK.Token)
+	if err != nil {
+		h.t.Fatalf("verifying the email failed: %s", err.Error())
+	}
+	if verifyResponse.OK == nil {
+		h.t.Fatalf("verifying the email failed: invalid token")
+	}
+}
+
+// Refresh refreshes the session with its refresh token and updates its tokens
+func (h *Harness) Refresh(s *TestSession) {
+	h.t.Helper()
+	res, body := h.do(http.MethodPost, "/auth/session/refresh", s.RefreshToken, nil)
+	if res.StatusCode != http.StatusOK {
+		h.t.Fatalf("refreshing the session failed with status %d: %s", res.StatusCode, string(body))
+	}
+	h.updateTokens(s, res)
+}
+
+// Revoke revokes the session in the core
+func (h *Harness) Revoke(s *TestSession) {
+	h.t.Helper()
+	_, err := session.RevokeSession(s.SessionHandle)
+	if err != nil {
+		h.t.Fatalf("revoking the session failed: %s", err.Error())
+	}
+}
+
+// Request sends a request to the test server, authenticated with the
+// session's access token when s is not nil
+func (h *Harness) Request(method string, path string, body interface{}, s *TestSession) (*http.Response, []byte) {
+	h.t.Helper()
+	token := ""
+	if s != nil {
+		token = s.AccessToken
+	}
+	return h.do(method, path, token, body)
+}
+
+func (h *Harness) emailPasswordRequest(path string, email string, password string) *TestSession {
+	res, body := h.do(http.MethodPost, path, "", map[string]interface{}{
+		"formFields": []map[string]interface{}{
+			{"id": "email", "value": email},
+			{"id": "password", "value": password},
+		},
+	})
+	var response struct {
+		Status string `json:"status"`
+		User   struct {
+			ID string `json:"id"`
+		} `json:"user"`
+	}
+	err := json.Unmarshal(body, &response)
+	if err != nil || response.Status != "OK" {
+		h.t.Fatalf("%s failed with status %d: %s", path, res.StatusCode, string(body))
+	}
+
+	s := &TestSession{
+		UserID: response.User.ID,
+		Email:  email,
+	}
+	h.updateTokens(s, res)
+	sessionContainer, err := session.GetSessionWithoutRequestResponse(s.AccessToken, nil, nil)
+	if err != nil {
+		h.t.Fatalf("reading the created session failed: %s", err.Error())
+	}
+	s.SessionHandle = sessionContainer.GetHandle()
+	return s
+}
+
+func (h *Harness) updateTokens(s *TestSession, res *http.Response) {
+	if accessToken := res.Header.Get("st-access-token"); accessToken != "" {
+		s.AccessToken = accessToken
+	}
+	if refreshToken := res.Header.Get("st-refresh-token"); refreshToken != "" {
+		s.RefreshToken = refreshToken
+	}
+	if frontToken := res.Header.Get("front-token"); frontToken != "" {
+		s.FrontToken = frontToken
+	}
+}
+
+func (h *Harness) do(method string, path string, token string, body interface{}) (*http.Response, []byte) {
+	h.t.Helper()
+	var reqBody io.Reader
+	if body != nil {
+		jsonBody, err := json.Marshal(body)
+		if err != nil {
+			h.t.Fatalf("encoding the request body failed: %s", err.Error())
+		}
+		reqBody = bytes.NewReader(jsonBody)
+	}
+	req, err := http.NewRequest(method, h.Server.URL+path, reqBody)
+	if err != nil {
+		h.t.Fatalf("creating the request failed: %s", err.Error())
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("st-auth-mode", "header")
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	res, err := http.DefaultClient.Do(req)
+	if err != nil {
+		h.t.Fatalf("%s %s failed: %s", method, path, err.Error())
+	}
+	defer res.Body.Close()
+	resBody, err := io.ReadAll(res.Body)
+	if err != nil {
+		h.t.Fatalf("reading the response of %s %s failed: %s", method, path, err.Error())
+	}
+	return res, resBody
+}